@@ -0,0 +1,70 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"math"
+	"os"
+
+	"github.com/krakovia/blockchain/pkg/blockchain"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/opt"
+)
+
+func main() {
+	var dbPath string
+	var format string
+	var fromHeight uint64
+	var toHeight int64
+	var outputFile string
+
+	flag.StringVar(&dbPath, "db", "", "Path to the node's LevelDB directory (db_path in the node config, required)")
+	flag.StringVar(&format, "format", "json", "Output format: json (one block per line) or csv (one transaction per line)")
+	flag.Uint64Var(&fromHeight, "from", 0, "First block height to export")
+	flag.Int64Var(&toHeight, "to", -1, "Last block height to export (-1 = chain tip)")
+	flag.StringVar(&outputFile, "output", "", "Output file path (default: stdout)")
+	flag.Parse()
+
+	if dbPath == "" {
+		log.Fatal("-db is required")
+	}
+
+	to := uint64(math.MaxUint64)
+	if toHeight >= 0 {
+		to = uint64(toHeight)
+	}
+
+	// Somente leitura: o banco de um nó em execução está travado, pare o nó ou exporte uma cópia
+	db, err := leveldb.OpenFile(dbPath, &opt.Options{ReadOnly: true, ErrorIfMissing: true})
+	if err != nil {
+		log.Fatalf("Failed to open database %s: %v", dbPath, err)
+	}
+	defer func() {
+		if err := db.Close(); err != nil {
+			log.Printf("Warning: failed to close database: %v", err)
+		}
+	}()
+
+	out := os.Stdout
+	if outputFile != "" {
+		out, err = os.Create(outputFile)
+		if err != nil {
+			log.Fatalf("Failed to create output file: %v", err)
+		}
+	}
+
+	result, err := blockchain.ExportBlocks(db, out, blockchain.ExportFormat(format), fromHeight, to)
+	if outputFile != "" {
+		if closeErr := out.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}
+	if err != nil {
+		log.Fatalf("Export failed: %v", err)
+	}
+
+	// O resumo vai para stderr para não misturar com a exportação no stdout
+	fmt.Fprintf(os.Stderr, "Exported %d blocks and %d transactions (heights %d-%d) as %s\n",
+		result.Blocks, result.Transactions, result.FromHeight, result.ToHeight, format)
+}
@@ -6,44 +6,130 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/krakovia/blockchain/internal/config"
 	"github.com/krakovia/blockchain/pkg/blockchain"
 )
 
+// allocFlags acumula flags -alloc addr:amount repetidas
+type allocFlags []config.GenesisAllocation
+
+func (a *allocFlags) String() string {
+	parts := make([]string, len(*a))
+	for i, alloc := range *a {
+		parts[i] = fmt.Sprintf("%s:%d", alloc.Address, alloc.Amount)
+	}
+	return strings.Join(parts, ",")
+}
+
+func (a *allocFlags) Set(value string) error {
+	addr, amountStr, ok := strings.Cut(value, ":")
+	if !ok || addr == "" {
+		return fmt.Errorf("expected addr:amount, got %q", value)
+	}
+	amount, err := strconv.ParseUint(amountStr, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid amount in %q: %w", value, err)
+	}
+	*a = append(*a, config.GenesisAllocation{Address: addr, Amount: amount})
+	return nil
+}
+
+// loadAllocFile lê um arquivo JSON com uma lista de {"address", "amount"}
+func loadAllocFile(path string) ([]config.GenesisAllocation, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read allocations file: %w", err)
+	}
+
+	var allocations []config.GenesisAllocation
+	if err := json.Unmarshal(data, &allocations); err != nil {
+		return nil, fmt.Errorf("failed to parse allocations file: %w", err)
+	}
+	return allocations, nil
+}
+
 func main() {
 	var (
+		allocs            allocFlags
+		allocFile         string
 		recipientAddr     string
 		amount            uint64
 		blockTime         int64
 		maxBlockSize      int
 		blockReward       uint64
+		halvingInterval   uint64
+		maxSupply         uint64
 		minValidatorStake uint64
+		unbondingPeriod   uint64
+		coinbaseMaturity  uint64
+		slashBasisPoints  uint64
 		outputFile        string
 		timestamp         int64
 	)
 
 	flag.StringVar(&recipientAddr, "recipient", "", "Recipient address for initial allocation (required)")
 	flag.Uint64Var(&amount, "amount", 1000000000, "Initial token amount")
+	flag.Var(&allocs, "alloc", "Initial allocation as addr:amount (repeatable, replaces -recipient/-amount)")
+	flag.StringVar(&allocFile, "alloc-file", "", "JSON file with a list of {\"address\", \"amount\"} allocations")
 	flag.Int64Var(&blockTime, "block-time", 5000, "Time between blocks in milliseconds (min: 1000ms)")
-	flag.IntVar(&maxBlockSize, "max-block-size", 1000, "Maximum transactions per block")
+	flag.IntVar(&maxBlockSize, "max-block-size", 1000, "Maximum transactions per block, excluding the coinbase")
 	flag.Uint64Var(&blockReward, "block-reward", 50, "Reward per block mined")
+	flag.Uint64Var(&halvingInterval, "halving-interval", 0, "Blocks between block reward halvings (0 = no halving)")
+	flag.Uint64Var(&maxSupply, "max-supply", 0, "Maximum token supply including the initial amount (0 = unlimited)")
 	flag.Uint64Var(&minValidatorStake, "min-stake", 1000, "Minimum stake to be a validator")
+	flag.Uint64Var(&unbondingPeriod, "unbonding-period", 10, "Blocks before unstaked tokens become spendable")
+	flag.Uint64Var(&coinbaseMaturity, "coinbase-maturity", 0, "Blocks before a block reward becomes spendable (0 = immediately)")
+	flag.Uint64Var(&slashBasisPoints, "slash-basis-points", 1000, "Basis points (1/10000) of stake slashed for double signing (0-10000)")
 	flag.StringVar(&outputFile, "output", "", "Output file path (default: stdout)")
 	flag.Int64Var(&timestamp, "timestamp", 0, "Genesis block timestamp (default: current time)")
 	flag.Parse()
 
-	if recipientAddr == "" {
-		log.Fatal("Recipient address is required. Use -recipient flag")
+	if allocFile != "" {
+		fileAllocs, err := loadAllocFile(allocFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		allocs = append(allocs, fileAllocs...)
+	}
+
+	// Sem -alloc/-alloc-file, usa -recipient/-amount como alocação única
+	var allocations []blockchain.GenesisAllocation
+	if len(allocs) > 0 {
+		if recipientAddr != "" {
+			log.Fatal("Use either -recipient/-amount or -alloc/-alloc-file, not both")
+		}
+		for _, alloc := range allocs {
+			allocations = append(allocations, blockchain.GenesisAllocation{Address: alloc.Address, Amount: alloc.Amount})
+		}
+	} else {
+		if recipientAddr == "" {
+			log.Fatal("Recipient address is required. Use -recipient flag (or -alloc / -alloc-file)")
+		}
+		if amount == 0 {
+			log.Fatal("Amount must be greater than 0")
+		}
+		allocations = []blockchain.GenesisAllocation{{Address: recipientAddr, Amount: amount}}
+	}
+
+	var totalAmount uint64
+	for _, alloc := range allocations {
+		totalAmount += alloc.Amount
 	}
 
 	if blockTime < 1000 {
 		log.Fatal("Block time must be at least 1000ms (1 second)")
 	}
 
-	if amount == 0 {
-		log.Fatal("Amount must be greater than 0")
+	if maxSupply > 0 && totalAmount > maxSupply {
+		log.Fatal("Initial allocations cannot exceed max supply")
+	}
+
+	if slashBasisPoints > blockchain.BasisPointsDenominator {
+		log.Fatalf("Slash basis points must be at most %d", blockchain.BasisPointsDenominator)
 	}
 
 	// Usa timestamp atual se não fornecido
@@ -51,22 +137,31 @@ func main() {
 		timestamp = time.Now().Unix()
 	}
 
-	// Cria a transação coinbase do genesis
-	genesisTx := blockchain.NewCoinbaseTransaction(recipientAddr, amount, 0)
-
-	// Cria o bloco genesis
-	genesisBlock := blockchain.GenesisBlock(genesisTx)
+	// Cria o bloco genesis (uma coinbase por alocação) com o mesmo timestamp usado pelos nós
+	genesisBlock, err := blockchain.GenesisBlockWithAllocations(allocations, timestamp)
+	if err != nil {
+		log.Fatalf("Failed to create genesis block: %v", err)
+	}
 
 	// Cria a configuração do genesis
 	genesisConfig := config.GenesisBlock{
 		Timestamp:         timestamp,
-		RecipientAddr:     recipientAddr,
-		Amount:            amount,
 		Hash:              genesisBlock.Hash,
 		BlockTime:         blockTime,
 		MaxBlockSize:      maxBlockSize,
 		BlockReward:       blockReward,
+		HalvingInterval:   halvingInterval,
+		MaxSupply:         maxSupply,
 		MinValidatorStake: minValidatorStake,
+		UnbondingPeriod:   &unbondingPeriod,
+		CoinbaseMaturity:  coinbaseMaturity,
+		SlashBasisPoints:  &slashBasisPoints,
+	}
+	if len(allocs) > 0 {
+		genesisConfig.Allocations = allocs
+	} else {
+		genesisConfig.RecipientAddr = recipientAddr
+		genesisConfig.Amount = amount
 	}
 
 	// Serializa para JSON
@@ -89,12 +184,25 @@ func main() {
 
 	// Exibe resumo
 	fmt.Printf("\n=== Genesis Block Configuration ===\n")
-	fmt.Printf("Recipient Address: %s\n", recipientAddr)
-	fmt.Printf("Initial Amount: %d tokens\n", amount)
+	for _, alloc := range allocations {
+		fmt.Printf("Allocation: %s -> %d tokens\n", alloc.Address, alloc.Amount)
+	}
+	fmt.Printf("Initial Amount: %d tokens\n", totalAmount)
 	fmt.Printf("Block Time: %dms (%.1fs)\n", blockTime, float64(blockTime)/1000)
-	fmt.Printf("Max Block Size: %d transactions\n", maxBlockSize)
+	fmt.Printf("Max Block Size: %d transactions (+ coinbase)\n", maxBlockSize)
 	fmt.Printf("Block Reward: %d tokens\n", blockReward)
+	if halvingInterval > 0 {
+		fmt.Printf("Halving Interval: every %d blocks\n", halvingInterval)
+	}
+	if maxSupply > 0 {
+		fmt.Printf("Max Supply: %d tokens\n", maxSupply)
+	}
 	fmt.Printf("Min Validator Stake: %d tokens\n", minValidatorStake)
+	fmt.Printf("Unbonding Period: %d blocks\n", unbondingPeriod)
+	if coinbaseMaturity > 0 {
+		fmt.Printf("Coinbase Maturity: %d blocks\n", coinbaseMaturity)
+	}
+	fmt.Printf("Slash: %d basis points of stake (%.2f%%)\n", slashBasisPoints, float64(slashBasisPoints)/100)
 	fmt.Printf("Timestamp: %d (%s)\n", timestamp, time.Unix(timestamp, 0).Format(time.RFC3339))
 	fmt.Printf("Genesis Hash: %s\n", genesisBlock.Hash)
 }
@@ -1,17 +1,20 @@
 package main
 
 import (
+	"bufio"
 	"flag"
 	"fmt"
 	"log"
 	"os"
 	"os/signal"
 	"path/filepath"
+	"strings"
 	"syscall"
 	"time"
 
 	"github.com/krakovia/blockchain/internal/config"
 	"github.com/krakovia/blockchain/pkg/blockchain"
+	"github.com/krakovia/blockchain/pkg/network"
 	"github.com/krakovia/blockchain/pkg/node"
 	"github.com/krakovia/blockchain/pkg/wallet"
 )
@@ -19,6 +22,7 @@ import (
 func main() {
 	configPath := flag.String("config", "", "Path to JSON config file (required)")
 	autoMine := flag.Bool("mine", false, "Start mining automatically")
+	auditFairness := flag.Bool("audit-fairness", false, "Audit block producer rotation against stakes and exit")
 	flag.Parse()
 
 	if *configPath == "" {
@@ -37,8 +41,8 @@ func main() {
 		log.Fatal("Failed to create data directory:", err)
 	}
 
-	// Carregar ou criar wallet a partir da configuração
-	w, err := wallet.NewWalletFromPrivateKey(cfg.Wallet.PrivateKey)
+	// Carregar wallet a partir da configuração (chave em claro ou keystore criptografado)
+	w, err := loadWallet(cfg.Wallet)
 	if err != nil {
 		log.Fatalf("Failed to load wallet: %v", err)
 	}
@@ -53,20 +57,21 @@ func main() {
 	// Criar bloco gênesis
 	var genesisBlock *blockchain.Block
 	if cfg.Genesis != nil {
-		// Criar transação coinbase para o genesis com timestamp fixo
-		genesisTx := blockchain.NewCoinbaseTransactionWithTimestamp(
-			cfg.Genesis.RecipientAddr,
-			cfg.Genesis.Amount,
-			0, // block height 0
-			cfg.Genesis.Timestamp,
-		)
+		// Criar genesis block (uma coinbase por alocação) com timestamp fixo do config
+		var allocations []blockchain.GenesisAllocation
+		for _, alloc := range cfg.Genesis.GetAllocations() {
+			allocations = append(allocations, blockchain.GenesisAllocation{Address: alloc.Address, Amount: alloc.Amount})
+		}
 
-		// Criar genesis block com timestamp fixo do config
-		genesisBlock = blockchain.GenesisBlockWithTimestamp(genesisTx, cfg.Genesis.Timestamp)
+		genesisBlock, err = blockchain.GenesisBlockWithAllocations(allocations, cfg.Genesis.Timestamp)
+		if err != nil {
+			log.Fatalf("Failed to create genesis block: %v", err)
+		}
 
 		fmt.Printf("Genesis block created: %s\n", genesisBlock.Hash[:16])
-		fmt.Printf("Genesis recipient: %s\n", cfg.Genesis.RecipientAddr)
-		fmt.Printf("Genesis amount: %d\n", cfg.Genesis.Amount)
+		for _, alloc := range allocations {
+			fmt.Printf("Genesis allocation: %s -> %d\n", alloc.Address, alloc.Amount)
+		}
 		fmt.Printf("Genesis timestamp: %d\n", cfg.Genesis.Timestamp)
 	} else {
 		// Criar genesis padrão se não fornecido
@@ -87,15 +92,39 @@ func main() {
 		if cfg.Genesis.BlockTime > 0 {
 			chainConfig.BlockTime = time.Duration(cfg.Genesis.BlockTime) * time.Millisecond
 		}
-		if cfg.Genesis.MaxBlockSize > 0 {
-			chainConfig.MaxBlockSize = cfg.Genesis.MaxBlockSize
-		}
+		chainConfig.Consensus = cfg.Genesis.ConsensusParams()
 		if cfg.Genesis.BlockReward > 0 {
 			chainConfig.BlockReward = cfg.Genesis.BlockReward
 		}
+		chainConfig.HalvingInterval = cfg.Genesis.HalvingInterval
+		chainConfig.MaxSupply = cfg.Genesis.MaxSupply
 		if cfg.Genesis.MinValidatorStake > 0 {
 			chainConfig.MinValidatorStake = cfg.Genesis.MinValidatorStake
 		}
+		if cfg.Genesis.UnbondingPeriod != nil {
+			chainConfig.UnbondingPeriod = *cfg.Genesis.UnbondingPeriod
+		}
+		chainConfig.CoinbaseMaturity = cfg.Genesis.CoinbaseMaturity
+		if cfg.Genesis.SlashBasisPoints != nil {
+			chainConfig.SlashBasisPoints = *cfg.Genesis.SlashBasisPoints
+		}
+		if cfg.Genesis.MaxReorgDepth > 0 {
+			chainConfig.MaxReorgDepth = cfg.Genesis.MaxReorgDepth
+		}
+		if cfg.Genesis.MaxClockDrift > 0 {
+			chainConfig.MaxClockDrift = time.Duration(cfg.Genesis.MaxClockDrift) * time.Second
+		}
+		if cfg.Genesis.MinFee > 0 {
+			chainConfig.MinFee = cfg.Genesis.MinFee
+		}
+		for _, v := range cfg.Genesis.GetVesting() {
+			chainConfig.Vesting = append(chainConfig.Vesting, blockchain.VestingSchedule{
+				Address:       v.Address,
+				Amount:        v.Amount,
+				CliffHeight:   v.CliffHeight,
+				VestingBlocks: v.VestingBlocks,
+			})
+		}
 	}
 
 	// Configurar nó
@@ -107,19 +136,91 @@ func main() {
 		MaxPeers:          cfg.MaxPeers,
 		MinPeers:          cfg.MinPeers,
 		DiscoveryInterval: cfg.DiscoveryInterval,
+		MaxParallelDials:  cfg.MaxParallelDials,
 		Wallet:            w,
 		GenesisBlock:      genesisBlock,
 		ChainConfig:       chainConfig,
 		CheckpointConfig:  cfg.Checkpoint,
+		PruneConfig:       cfg.Prune,
 		APIConfig:         cfg.API,
 	}
 
+	// O nó recusa iniciar se o gênesis recriado não bater com o hash da config
+	if cfg.Genesis != nil {
+		nodeConfig.GenesisHash = cfg.Genesis.Hash
+	}
+
+	// Tempo máximo para montar respostas de sync
+	if cfg.SyncTimeoutMs > 0 {
+		nodeConfig.SyncAssemblyTimeout = time.Duration(cfg.SyncTimeoutMs) * time.Millisecond
+	}
+	nodeConfig.SyncBatchSize = cfg.SyncBatchSize
+	nodeConfig.MaxFutureBlocks = cfg.MaxFutureBlocks
+	nodeConfig.HeadersFirstSync = cfg.HeadersFirstSync
+	nodeConfig.ParallelSyncPeers = cfg.ParallelSyncPeers
+	nodeConfig.DownloadTimeout = time.Duration(cfg.DownloadTimeoutMs) * time.Millisecond
+	nodeConfig.CompressMessages = cfg.CompressMessages
+	nodeConfig.PeerMaxAge = time.Duration(cfg.PeerMaxAgeHours) * time.Hour
+	nodeConfig.MempoolMinBumpPercent = cfg.MempoolMinBumpPercent
+	nodeConfig.MempoolMaxSize = cfg.MempoolMaxSize
+
+	// Servidores STUN/TURN (TURN para nós atrás de NATs restritivos)
+	for _, server := range cfg.ICEServers {
+		nodeConfig.ICEServers = append(nodeConfig.ICEServers, network.ICEServer{
+			URLs:       server.URLs,
+			Username:   server.Username,
+			Credential: server.Credential,
+		})
+	}
+
+	// Configuração de persistência (retry ao salvar blocos e compactação do LevelDB)
+	if cfg.Storage != nil {
+		nodeConfig.BlockSaveRetries = cfg.Storage.SaveRetries
+		nodeConfig.BlockSaveBackoff = time.Duration(cfg.Storage.SaveBackoffMs) * time.Millisecond
+		nodeConfig.CompactOnStartup = cfg.Storage.CompactOnStartup
+		nodeConfig.CompactInterval = time.Duration(cfg.Storage.CompactIntervalHours) * time.Hour
+	}
+
+	// Filtro de remetentes (deployments permissionados)
+	if cfg.TxFilter != nil {
+		nodeConfig.SenderAllowlist = cfg.TxFilter.Allowlist
+		nodeConfig.SenderDenylist = cfg.TxFilter.Denylist
+		nodeConfig.FilterMempool = cfg.TxFilter.FilterMempool
+	}
+
+	// Rate limit de mensagens recebidas de cada peer
+	if cfg.RateLimit != nil {
+		nodeConfig.DisableRateLimit = cfg.RateLimit.Disabled
+		nodeConfig.RateLimitMaxDrops = cfg.RateLimit.MaxDrops
+		nodeConfig.MessageRateLimits = make(map[string]network.TokenBucketLimit, len(cfg.RateLimit.Limits))
+		for msgType, limit := range cfg.RateLimit.Limits {
+			nodeConfig.MessageRateLimits[msgType] = network.TokenBucketLimit{Rate: limit.Rate, Burst: limit.Burst}
+		}
+	}
+
+	// Limites de banda (conexões com franquia de tráfego)
+	if cfg.Bandwidth != nil {
+		nodeConfig.Bandwidth = network.BandwidthLimit{
+			PeerUpload:    cfg.Bandwidth.PeerUploadBytesPerSec,
+			PeerDownload:  cfg.Bandwidth.PeerDownloadBytesPerSec,
+			TotalUpload:   cfg.Bandwidth.TotalUploadBytesPerSec,
+			TotalDownload: cfg.Bandwidth.TotalDownloadBytesPerSec,
+		}
+	}
+
+	// Log de blocos e transações rejeitados
+	if cfg.RejectLog != nil {
+		nodeConfig.RejectLogPath = cfg.RejectLog.Path
+		nodeConfig.RejectLogMaxSize = int64(cfg.RejectLog.MaxSizeMB) * 1024 * 1024
+		nodeConfig.RejectLogMaxFiles = cfg.RejectLog.MaxFiles
+	}
+
 	// Adicionar stake inicial se fornecido
 	if cfg.Genesis != nil && cfg.Genesis.InitialStake > 0 {
 		nodeConfig.InitialStake = cfg.Genesis.InitialStake
-		nodeConfig.InitialStakeAddr = cfg.Genesis.RecipientAddr
+		nodeConfig.InitialStakeAddr = cfg.Genesis.GetAllocations()[0].Address
 		fmt.Printf("Genesis initial stake configured: %d tokens for %s\n",
-			cfg.Genesis.InitialStake, cfg.Genesis.RecipientAddr[:8])
+			cfg.Genesis.InitialStake, nodeConfig.InitialStakeAddr[:8])
 	}
 
 	// Criar nó
@@ -128,18 +229,31 @@ func main() {
 		log.Fatal("Failed to create node:", err)
 	}
 
+	// Modo de auditoria: analisa a chain local sem conectar à rede
+	if *auditFairness {
+		printFairnessReport(n)
+		if err := n.Stop(); err != nil {
+			log.Fatal("Failed to stop node:", err)
+		}
+		return
+	}
+
 	// Iniciar nó
 	if err := n.Start(); err != nil {
 		log.Fatal("Failed to start node:", err)
 	}
 
 	fmt.Printf("\n=================================\n")
-	fmt.Printf("Node %s started successfully!\n", cfg.ID)
+	fmt.Printf("Node %s started successfully!\n", n.ID)
 	fmt.Printf("Address: %s\n", cfg.Address)
 	fmt.Printf("Database: %s\n", cfg.DBPath)
 	fmt.Printf("Signaling: %s\n", cfg.SignalingServer)
 	if cfg.API != nil && cfg.API.Enabled {
-		fmt.Printf("HTTP API: http://localhost%s\n", cfg.API.Address)
+		scheme := "http"
+		if cfg.API.TLSCertFile != "" {
+			scheme = "https"
+		}
+		fmt.Printf("HTTP API: %s://localhost%s\n", scheme, cfg.API.Address)
 	}
 	fmt.Printf("=================================\n")
 
@@ -173,3 +287,66 @@ func main() {
 
 	fmt.Println("Node stopped successfully")
 }
+
+// printFairnessReport compara os blocos produzidos por cada validador com o esperado pelo stake.
+// Os blocos são buscados por altura (da memória ou do LevelDB, então a auditoria cobre também
+// os podados); os stakes usados são os do topo da chain.
+func printFairnessReport(n *node.Node) {
+	chain := n.GetChain()
+	height := chain.GetHeight()
+	blocks := make([]*blockchain.Block, 0, height)
+	for h := uint64(1); h <= height; h++ {
+		if block, ok := n.GetBlockByHeight(h); ok {
+			blocks = append(blocks, block)
+		}
+	}
+	report := blockchain.AuditFairness(blocks, chain.GetValidators())
+
+	fmt.Printf("\n--- Block Producer Fairness ---\n")
+	fmt.Printf("Blocks: %d (heights %d-%d)\n", report.TotalBlocks, report.FromHeight, report.ToHeight)
+	if missing := height - uint64(report.TotalBlocks); missing > 0 {
+		fmt.Printf("Blocks not available locally (skipped): %d\n", missing)
+	}
+	fmt.Printf("%-64s %12s %8s %9s %9s %7s\n", "Validator", "Stake", "Blocks", "Share", "Expected", "Z")
+	for _, v := range report.Validators {
+		fmt.Printf("%-64s %12d %8d %8.2f%% %8.2f%% %7.2f\n",
+			v.Address, v.Stake, v.Blocks, v.Share*100, v.ExpectedShare*100, v.ZScore)
+	}
+	if report.UnexpectedBlocks > 0 {
+		fmt.Printf("Blocks from producers without current stake: %d\n", report.UnexpectedBlocks)
+	}
+	fmt.Printf("Chi-square: %.2f (%d degrees of freedom)\n", report.ChiSquare, report.DegreesOfFreedom)
+	fmt.Printf("===============================\n")
+}
+
+// keystorePasswordEnv é a variável de ambiente com a senha do keystore (se vazia, a senha é solicitada:
+// sem eco quando a entrada é um terminal, ou lida da primeira linha da entrada redirecionada)
+const keystorePasswordEnv = "KRAKOVIA_KEYSTORE_PASSWORD"
+
+// loadWallet carrega a carteira do nó a partir da chave privada ou do keystore configurado
+func loadWallet(cfg config.WalletConfig) (*wallet.Wallet, error) {
+	if cfg.Keystore == "" {
+		return wallet.NewWalletFromPrivateKey(cfg.PrivateKey)
+	}
+
+	password := os.Getenv(keystorePasswordEnv)
+	if password == "" {
+		fmt.Fprintf(os.Stderr, "Password for keystore %s: ", cfg.Keystore)
+		if stdin := int(os.Stdin.Fd()); isTerminal(stdin) {
+			line, err := readPassword(stdin)
+			fmt.Fprintln(os.Stderr)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read keystore password: %w", err)
+			}
+			password = line
+		} else {
+			line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+			if err != nil && line == "" {
+				return nil, fmt.Errorf("failed to read keystore password: %w", err)
+			}
+			password = strings.TrimRight(line, "\r\n")
+		}
+	}
+
+	return wallet.LoadKeystore(cfg.Keystore, password)
+}
@@ -0,0 +1,13 @@
+//go:build !linux
+
+package main
+
+import "fmt"
+
+// isTerminal só detecta terminais no Linux; nos demais sistemas a senha é lida como entrada comum
+func isTerminal(fd int) bool { return false }
+
+// readPassword não é suportado fora do Linux (isTerminal sempre retorna false)
+func readPassword(fd int) (string, error) {
+	return "", fmt.Errorf("reading a password without echo is not supported on this platform")
+}
@@ -9,9 +9,17 @@ import (
 
 func main() {
 	addr := flag.String("addr", ":9000", "Signaling server address")
+	pingInterval := flag.Duration("ping-interval", signaling.DefaultPingInterval, "Heartbeat ping interval")
+	maxMissedPongs := flag.Int("max-missed-pongs", signaling.DefaultMaxMissedPongs, "Missed pongs before a client is evicted")
+	maxClients := flag.Int("max-clients", 0, "Maximum simultaneous WebSocket connections (0 = unlimited)")
+	sendQueue := flag.Int("send-queue", signaling.DefaultSendQueueSize, "Pending messages per client before a slow client is dropped")
 	flag.Parse()
 
 	server := signaling.NewServer()
+	server.PingInterval = *pingInterval
+	server.MaxMissedPongs = *maxMissedPongs
+	server.MaxClients = *maxClients
+	server.SendQueueSize = *sendQueue
 
 	log.Printf("Starting signaling server on %s", *addr)
 	if err := server.Start(*addr); err != nil {
@@ -1,37 +1,76 @@
 package main
 
 import (
+	"bufio"
 	"encoding/json"
 	"flag"
 	"fmt"
 	"log"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/krakovia/blockchain/pkg/wallet"
 )
 
 type WalletOutput struct {
-	PrivateKey string `json:"private_key"`
+	PrivateKey string `json:"private_key,omitempty"`
 	PublicKey  string `json:"public_key"`
 	Address    string `json:"address"`
+	Mnemonic   string `json:"mnemonic,omitempty"`
+	Keystore   string `json:"keystore,omitempty"`
 }
 
 func main() {
 	var outputFile string
 	var count int
+	var useMnemonic bool
+	var words int
+	var encrypt bool
+	var password string
+	var migrate string
 
 	flag.StringVar(&outputFile, "output", "", "Output file path (default: stdout)")
 	flag.IntVar(&count, "count", 1, "Number of wallets to generate")
+	flag.BoolVar(&useMnemonic, "mnemonic", false, "Derive wallets from a BIP39 mnemonic phrase and print it")
+	flag.IntVar(&words, "words", 12, "Number of mnemonic words (12 or 24, requires -mnemonic)")
+	flag.BoolVar(&encrypt, "encrypt", false, "Write each wallet to an encrypted keystore file (requires -output)")
+	flag.StringVar(&password, "password", "", "Keystore password (prompted if empty, requires -encrypt or -migrate)")
+	flag.StringVar(&migrate, "migrate", "", "Plaintext wallet file to migrate into an encrypted keystore (requires -output)")
 	flag.Parse()
 
+	if migrate != "" {
+		migrateWallet(migrate, outputFile, password)
+		return
+	}
+
 	if count < 1 {
 		log.Fatal("Count must be at least 1")
 	}
 
+	if encrypt {
+		if outputFile == "" {
+			log.Fatal("-encrypt requires -output (keystore file path)")
+		}
+		if password == "" {
+			var err error
+			password, err = readPassword("Keystore password: ")
+			if err != nil {
+				log.Fatalf("Failed to read password: %v", err)
+			}
+		}
+	}
+
 	wallets := make([]WalletOutput, 0, count)
 
 	for i := 0; i < count; i++ {
-		w, err := wallet.NewWallet()
+		var w *wallet.Wallet
+		var err error
+		if useMnemonic {
+			w, err = wallet.NewWalletWithMnemonic(words)
+		} else {
+			w, err = wallet.NewWallet()
+		}
 		if err != nil {
 			log.Fatalf("Failed to create wallet %d: %v", i+1, err)
 		}
@@ -41,6 +80,19 @@ func main() {
 			PublicKey:  w.GetPublicKeyHex(),
 			Address:    w.GetAddress(),
 		}
+		if useMnemonic {
+			walletOutput.Mnemonic, _ = w.Mnemonic()
+		}
+
+		// Com -encrypt a chave privada vai apenas para o keystore criptografado
+		if encrypt {
+			path := keystorePath(outputFile, i, count)
+			if err := w.SaveKeystore(path, password); err != nil {
+				log.Fatalf("Failed to save keystore %d: %v", i+1, err)
+			}
+			walletOutput.PrivateKey = ""
+			walletOutput.Keystore = path
+		}
 
 		wallets = append(wallets, walletOutput)
 	}
@@ -58,7 +110,7 @@ func main() {
 		log.Fatalf("Failed to marshal output: %v", err)
 	}
 
-	if outputFile != "" {
+	if outputFile != "" && !encrypt {
 		err = os.WriteFile(outputFile, output, 0644)
 		if err != nil {
 			log.Fatalf("Failed to write output file: %v", err)
@@ -68,3 +120,59 @@ func main() {
 		fmt.Println(string(output))
 	}
 }
+
+// migrateWallet converte uma carteira em texto puro gerada sem -encrypt em um keystore
+// criptografado em outputFile
+func migrateWallet(plaintextPath, outputFile, password string) {
+	if outputFile == "" {
+		log.Fatal("-migrate requires -output (keystore file path)")
+	}
+	if password == "" {
+		var err error
+		password, err = readPassword("Keystore password: ")
+		if err != nil {
+			log.Fatalf("Failed to read password: %v", err)
+		}
+	}
+
+	w, err := wallet.MigrateToEncrypted(plaintextPath, outputFile, password)
+	if err != nil {
+		log.Fatalf("Failed to migrate wallet: %v", err)
+	}
+
+	output, err := json.MarshalIndent(WalletOutput{
+		PublicKey: w.GetPublicKeyHex(),
+		Address:   w.GetAddress(),
+		Keystore:  outputFile,
+	}, "", "  ")
+	if err != nil {
+		log.Fatalf("Failed to marshal output: %v", err)
+	}
+
+	fmt.Println(string(output))
+	fmt.Fprintf(os.Stderr, "Wallet migrated to %s. Check the keystore and then delete %s\n", outputFile, plaintextPath)
+}
+
+// keystorePath retorna o caminho do keystore da carteira i
+// (com várias carteiras, adiciona o índice antes da extensão: wallet-1.json, wallet-2.json...)
+func keystorePath(outputFile string, i, count int) string {
+	if count == 1 {
+		return outputFile
+	}
+	ext := filepath.Ext(outputFile)
+	return fmt.Sprintf("%s-%d%s", strings.TrimSuffix(outputFile, ext), i+1, ext)
+}
+
+// readPassword lê a senha da entrada padrão
+func readPassword(prompt string) (string, error) {
+	fmt.Fprint(os.Stderr, prompt)
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && line == "" {
+		return "", err
+	}
+	password := strings.TrimRight(line, "\r\n")
+	if password == "" {
+		return "", fmt.Errorf("password cannot be empty")
+	}
+	return password, nil
+}
@@ -0,0 +1,146 @@
+package game
+
+import "math"
+
+// Biome tipo de região do mundo, que define os blocos da superfície e o relevo
+type Biome uint8
+
+const (
+	BiomePlains Biome = iota
+	BiomeDesert
+	BiomeMountains
+	BiomeSnow
+
+	biomeCount
+)
+
+// String retorna o nome do bioma (para a UI)
+func (b Biome) String() string {
+	switch b {
+	case BiomePlains:
+		return "Planície"
+	case BiomeDesert:
+		return "Deserto"
+	case BiomeMountains:
+		return "Montanhas"
+	case BiomeSnow:
+		return "Neve"
+	default:
+		return "Desconhecido"
+	}
+}
+
+// BiomeProfile blocos e relevo de um bioma
+type BiomeProfile struct {
+	Surface         BlockType // Bloco do topo da coluna
+	Filler          BlockType // Camadas logo abaixo do topo (DirtDepth blocos)
+	BaseHeight      float64   // Altura média da superfície
+	HeightAmplitude float64   // Variação da superfície acima e abaixo da média
+
+	// Clima típico do bioma no mapa (temperatura e relevo entre -1 e 1); cada coluna pertence
+	// ao bioma de clima mais próximo
+	Temperature float64
+	Relief      float64
+}
+
+// BiomeProfiles perfil de cada bioma
+var BiomeProfiles = [biomeCount]BiomeProfile{
+	BiomePlains:    {Surface: BlockGrass, Filler: BlockDirt, BaseHeight: 8, HeightAmplitude: 6, Temperature: 0, Relief: -0.1},
+	BiomeDesert:    {Surface: BlockSand, Filler: BlockSand, BaseHeight: 6, HeightAmplitude: 4, Temperature: 0.3, Relief: -0.15},
+	BiomeMountains: {Surface: BlockStone, Filler: BlockGravel, BaseHeight: 18, HeightAmplitude: 28, Temperature: 0, Relief: 0.25},
+	BiomeSnow:      {Surface: BlockSnow, Filler: BlockDirt, BaseHeight: 10, HeightAmplitude: 10, Temperature: -0.3, Relief: -0.05},
+}
+
+// BiomeMap distribui os biomas pelo mundo com ruído de baixa frequência (temperatura e relevo).
+// Perto da fronteira entre dois biomas a altura e a amplitude do terreno são misturadas, para
+// o relevo passar de um para o outro sem degraus
+type BiomeMap struct {
+	Seed       int64
+	Scale      float64 // Tamanho típico (em blocos) das regiões de clima
+	BlendWidth float64 // Largura da mistura nas fronteiras, em unidades de clima (0 = sem mistura)
+
+	temperature *perlinNoise
+	relief      *perlinNoise
+}
+
+// NewBiomeMap cria o mapa de biomas da seed com parâmetros padrão
+func NewBiomeMap(seed int64) *BiomeMap {
+	return &BiomeMap{
+		Seed:        seed,
+		Scale:       256,
+		BlendWidth:  0.06,
+		temperature: newPerlinNoise(seed ^ 0x7e3a1f),
+		relief:      newPerlinNoise(seed ^ 0x2b9c4d),
+	}
+}
+
+// climate temperatura e relevo da coluna (x, z)
+func (bm *BiomeMap) climate(x, z int32) (temperature, relief float64) {
+	fx, fz := float64(x)/bm.Scale, float64(z)/bm.Scale
+	return bm.temperature.fbm2(fx, fz, 2), bm.relief.fbm2(fx+0.5, fz+0.5, 2)
+}
+
+// climateDistances distância do clima da coluna até o clima típico de cada bioma
+func (bm *BiomeMap) climateDistances(x, z int32) (distances [biomeCount]float64, nearest Biome) {
+	temperature, relief := bm.climate(x, z)
+	for b := Biome(0); b < biomeCount; b++ {
+		dt := temperature - BiomeProfiles[b].Temperature
+		dr := relief - BiomeProfiles[b].Relief
+		distances[b] = math.Sqrt(dt*dt + dr*dr)
+		if distances[b] < distances[nearest] {
+			nearest = b
+		}
+	}
+	return distances, nearest
+}
+
+// BiomeAt retorna o bioma da coluna (x, z)
+func (bm *BiomeMap) BiomeAt(x, z int32) Biome {
+	_, nearest := bm.climateDistances(x, z)
+	return nearest
+}
+
+// Weights retorna o peso de cada bioma na coluna (x, z), somando 1. Longe das fronteiras só o
+// bioma da coluna tem peso; perto delas, cada bioma cujo clima está a menos de BlendWidth do
+// mais próximo entra na mistura, com peso maior quanto mais próximo
+func (bm *BiomeMap) Weights(x, z int32) [biomeCount]float64 {
+	distances, nearest := bm.climateDistances(x, z)
+
+	var weights [biomeCount]float64
+	if bm.BlendWidth <= 0 {
+		weights[nearest] = 1
+		return weights
+	}
+
+	total := 0.0
+	for b := range weights {
+		w := 1 - (distances[b]-distances[nearest])/bm.BlendWidth
+		if w > 0 {
+			weights[b] = w * w // Suaviza a entrada do bioma vizinho
+			total += weights[b]
+		}
+	}
+	for b := range weights {
+		weights[b] /= total
+	}
+	return weights
+}
+
+// Terrain retorna o bioma da coluna e a altura média e a amplitude do relevo já misturadas
+// com os biomas vizinhos
+func (bm *BiomeMap) Terrain(x, z int32) (biome Biome, baseHeight, amplitude float64) {
+	weights := bm.Weights(x, z)
+	for b, w := range weights {
+		baseHeight += w * BiomeProfiles[b].BaseHeight
+		amplitude += w * BiomeProfiles[b].HeightAmplitude
+		if w > weights[biome] {
+			biome = Biome(b)
+		}
+	}
+	return biome, baseHeight, amplitude
+}
+
+// BiomeSource é implementado por geradores de terreno com biomas (ver World.GetBiome)
+type BiomeSource interface {
+	BiomeAt(x, z int32) Biome
+}
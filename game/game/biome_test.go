@@ -0,0 +1,139 @@
+package game
+
+import (
+	"testing"
+)
+
+func TestBiomeMapStableForSeed(t *testing.T) {
+	first, second := NewBiomeMap(42), NewBiomeMap(42)
+	other := NewBiomeMap(43)
+
+	world := NewWorld()
+	world.TerrainGenerator = NewTerrainGenerator(42)
+
+	var seen [biomeCount]bool
+	differs := false
+	for x := int32(-2048); x < 2048; x += 32 {
+		for z := int32(-2048); z < 2048; z += 32 {
+			biome := first.BiomeAt(x, z)
+			if second.BiomeAt(x, z) != biome {
+				t.Fatalf("Column (%d, %d): same seed gave %v and %v", x, z, biome, second.BiomeAt(x, z))
+			}
+			if world.GetBiome(x, z) != biome {
+				t.Fatalf("Column (%d, %d): World.GetBiome gave %v, expected %v", x, z, world.GetBiome(x, z), biome)
+			}
+			if other.BiomeAt(x, z) != biome {
+				differs = true
+			}
+			seen[biome] = true
+		}
+	}
+
+	for b, ok := range seen {
+		if !ok {
+			t.Errorf("Biome %v never assigned in a 4096x4096 area", Biome(b))
+		}
+	}
+	if !differs {
+		t.Error("Different seeds should produce different biome maps")
+	}
+
+	// Gerador sem biomas: tudo planície
+	world.TerrainGenerator = NewFlatTerrainGenerator(10)
+	if got := world.GetBiome(100, 100); got != BiomePlains {
+		t.Errorf("Generator without biomes should report plains, got %v", got)
+	}
+}
+
+func TestBiomeSurfaceBlocks(t *testing.T) {
+	tg := NewTerrainGenerator(7).(*NoiseTerrainGenerator)
+	tg.CaveThreshold = 1
+
+	for x := int32(-1024); x < 1024; x += 37 {
+		for z := int32(-1024); z < 1024; z += 37 {
+			profile := BiomeProfiles[tg.BiomeAt(x, z)]
+			surface := tg.SurfaceHeight(x, z)
+			if got := tg.GetBlockTypeAt(x, surface, z); got != profile.Surface {
+				t.Fatalf("Column (%d, %d) in %v: expected surface %d, got %d", x, z, tg.BiomeAt(x, z), profile.Surface, got)
+			}
+			if got := tg.GetBlockTypeAt(x, surface-1, z); got != profile.Filler {
+				t.Fatalf("Column (%d, %d) in %v: expected filler %d, got %d", x, z, tg.BiomeAt(x, z), profile.Filler, got)
+			}
+		}
+	}
+}
+
+// biomeBorder coluna x de uma fronteira entre biomas na linha z (BiomeAt muda de x para x+1)
+type biomeBorder struct {
+	x, z int32
+}
+
+func findBiomeBorders(bm *BiomeMap, limit int) []biomeBorder {
+	var borders []biomeBorder
+	for z := int32(-2048); z < 2048 && len(borders) < limit; z += 97 {
+		for x := int32(-2048); x < 2048 && len(borders) < limit; x++ {
+			if bm.BiomeAt(x, z) != bm.BiomeAt(x+1, z) {
+				borders = append(borders, biomeBorder{x: x, z: z})
+				x += 64
+			}
+		}
+	}
+	return borders
+}
+
+func TestBiomeBorderBlending(t *testing.T) {
+	bm := NewBiomeMap(12345)
+	borders := findBiomeBorders(bm, 50)
+	if len(borders) < 10 {
+		t.Fatalf("Expected biome borders to test, found %d", len(borders))
+	}
+
+	// Na fronteira os dois biomas entram na mistura e a altura média fica entre as deles
+	for _, border := range borders {
+		a, b := bm.BiomeAt(border.x, border.z), bm.BiomeAt(border.x+1, border.z)
+		weights := bm.Weights(border.x, border.z)
+		if weights[a] <= 0 || weights[b] <= 0 {
+			t.Fatalf("Border (%d, %d) between %v and %v: both should be blended, weights %v", border.x, border.z, a, b, weights)
+		}
+
+		low, high := 1e9, -1e9
+		for biome, w := range weights {
+			if w > 0 {
+				low = min(low, BiomeProfiles[biome].BaseHeight)
+				high = max(high, BiomeProfiles[biome].BaseHeight)
+			}
+		}
+		_, baseHeight, _ := bm.Terrain(border.x, border.z)
+		if baseHeight <= low || baseHeight >= high {
+			t.Errorf("Border (%d, %d) between %v and %v: base height %.2f not strictly between %.0f and %.0f",
+				border.x, border.z, a, b, baseHeight, low, high)
+		}
+	}
+
+	// Sem mistura a superfície salta na fronteira; com mistura os degraus diminuem
+	blended := NewNoiseTerrainGenerator(12345)
+	blended.Biomes = bm
+	hard := NewNoiseTerrainGenerator(12345)
+	hard.Biomes = NewBiomeMap(12345)
+	hard.Biomes.BlendWidth = 0
+
+	maxStep := func(tg *NoiseTerrainGenerator) int32 {
+		worst := int32(0)
+		for _, border := range borders {
+			for x := border.x - 32; x < border.x+32; x++ {
+				step := tg.SurfaceHeight(x+1, border.z) - tg.SurfaceHeight(x, border.z)
+				worst = max(worst, step, -step)
+			}
+		}
+		return worst
+	}
+
+	blendedStep, hardStep := maxStep(blended), maxStep(hard)
+	t.Logf("Largest step near borders: %d blocks blended, %d without blending", blendedStep, hardStep)
+	if blendedStep >= hardStep {
+		t.Errorf("Blending should smooth biome borders: largest step %d, without blending %d", blendedStep, hardStep)
+	}
+	if blendedStep > 4 {
+		t.Errorf("Biome borders should not have cliffs, largest step %d blocks", blendedStep)
+	}
+}
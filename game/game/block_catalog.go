@@ -0,0 +1,151 @@
+package game
+
+import "slices"
+
+// Dimensões padrão da grade do catálogo de blocos
+const (
+	DefaultCatalogColumns = 8
+	DefaultCatalogRows    = 4
+)
+
+// CatalogCategories abas do catálogo, na ordem em que aparecem (Tab avança)
+var CatalogCategories = []BlockCategory{CategoryAll, CategoryNatural, CategoryDecorative, CategoryCustom}
+
+// BlockCatalog tela com todos os blocos (tecla E) em uma grade de Columns x Rows, filtrada pela
+// aba da categoria; as setas escolhem o bloco e Enter o coloca na hotbar. Catálogos com mais
+// linhas que Rows rolam para manter o selecionado visível. Só guarda o estado da tela; o
+// desenho fica no main.
+type BlockCatalog struct {
+	Open     bool
+	Columns  int
+	Rows     int           // Linhas visíveis
+	Category BlockCategory // Aba atual (CategoryAll mostra todos)
+	Selected int           // Índice em Blocks
+	Scroll   int           // Primeira linha visível
+
+	all    []BlockType // Catálogo completo, sem filtro
+	Blocks []BlockType // Blocos da aba atual
+}
+
+// NewBlockCatalog cria o catálogo com a grade informada (mínimo de uma coluna e uma linha)
+func NewBlockCatalog(columns, rows int) *BlockCatalog {
+	return &BlockCatalog{Columns: max(columns, 1), Rows: max(rows, 1)}
+}
+
+// CatalogBlocks retorna os blocos que podem ser colocados (todos menos o ar) e os criados pelo
+// jogador, em ordem de tipo
+func CatalogBlocks(custom *CustomBlockManager) []BlockType {
+	blocks := make([]BlockType, 0, BlockMoss)
+	for blockType := BlockGrass; blockType <= BlockMoss; blockType++ {
+		blocks = append(blocks, blockType)
+	}
+	if custom != nil {
+		customTypes := make([]BlockType, 0, custom.Count())
+		for blockType := range custom.Blocks {
+			customTypes = append(customTypes, blockType)
+		}
+		slices.Sort(customTypes)
+		blocks = append(blocks, customTypes...)
+	}
+	return blocks
+}
+
+// FilterBlocksByCategory retorna os blocos da categoria, na mesma ordem (CategoryAll retorna todos)
+func FilterBlocksByCategory(blocks []BlockType, category BlockCategory) []BlockType {
+	filtered := make([]BlockType, 0, len(blocks))
+	for _, blockType := range blocks {
+		if category == CategoryAll || GetBlockCategory(blockType) == category {
+			filtered = append(filtered, blockType)
+		}
+	}
+	return filtered
+}
+
+// Toggle abre ou fecha a tela
+func (c *BlockCatalog) Toggle() {
+	c.Open = !c.Open
+}
+
+// SetBlocks troca o catálogo completo (p.ex. depois de criar um bloco) e reaplica o filtro
+func (c *BlockCatalog) SetBlocks(blocks []BlockType) {
+	c.all = blocks
+	c.refilter()
+}
+
+// SetCategory mostra só os blocos da categoria, voltando a seleção para o primeiro
+func (c *BlockCatalog) SetCategory(category BlockCategory) {
+	c.Category = category
+	c.Selected = 0
+	c.refilter()
+}
+
+// NextCategory avança para a próxima aba de CatalogCategories, dando a volta no fim
+func (c *BlockCatalog) NextCategory() {
+	next := 0
+	for i, category := range CatalogCategories {
+		if category == c.Category {
+			next = (i + 1) % len(CatalogCategories)
+		}
+	}
+	c.SetCategory(CatalogCategories[next])
+}
+
+// refilter recalcula Blocks para a aba atual e mantém a seleção dentro da lista
+func (c *BlockCatalog) refilter() {
+	c.Blocks = FilterBlocksByCategory(c.all, c.Category)
+	c.Selected = min(c.Selected, max(len(c.Blocks)-1, 0))
+	c.scrollToSelected()
+}
+
+// RowCount número de linhas da grade com os blocos da aba atual
+func (c *BlockCatalog) RowCount() int {
+	return (len(c.Blocks) + c.Columns - 1) / c.Columns
+}
+
+// Cell retorna a coluna e a linha da grade (contada a partir da primeira visível) do índice
+func (c *BlockCatalog) Cell(index int) (column, row int) {
+	return index % c.Columns, index/c.Columns - c.Scroll
+}
+
+// VisibleRange retorna o intervalo [start, end) dos índices de Blocks nas linhas visíveis
+func (c *BlockCatalog) VisibleRange() (start, end int) {
+	start = c.Scroll * c.Columns
+	end = min(start+c.Rows*c.Columns, len(c.Blocks))
+	return start, end
+}
+
+// Move anda a seleção dx colunas e dy linhas, parando nas bordas da grade, e rola a grade para
+// manter o bloco selecionado visível
+func (c *BlockCatalog) Move(dx, dy int) {
+	if len(c.Blocks) == 0 {
+		return
+	}
+
+	column := c.Selected%c.Columns + dx
+	row := c.Selected/c.Columns + dy
+	if column < 0 || column >= c.Columns || row < 0 || row >= c.RowCount() {
+		return
+	}
+	c.Selected = min(row*c.Columns+column, len(c.Blocks)-1)
+	c.scrollToSelected()
+}
+
+// scrollToSelected ajusta Scroll para que a linha do bloco selecionado fique visível
+func (c *BlockCatalog) scrollToSelected() {
+	row := c.Selected / c.Columns
+	if row < c.Scroll {
+		c.Scroll = row
+	}
+	if row >= c.Scroll+c.Rows {
+		c.Scroll = row - c.Rows + 1
+	}
+	c.Scroll = max(min(c.Scroll, c.RowCount()-c.Rows), 0)
+}
+
+// SelectedBlock retorna o bloco selecionado (BlockAir se a aba está vazia)
+func (c *BlockCatalog) SelectedBlock() BlockType {
+	if c.Selected >= len(c.Blocks) {
+		return BlockAir
+	}
+	return c.Blocks[c.Selected]
+}
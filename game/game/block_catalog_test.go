@@ -0,0 +1,112 @@
+package game
+
+import "testing"
+
+func TestBlockCatalogFiltersByCategory(t *testing.T) {
+	all := CatalogBlocks(nil)
+	if len(all) != int(BlockMoss) || all[0] != BlockGrass {
+		t.Fatalf("Expected every block but air in the catalog, got %v", all)
+	}
+
+	contains := func(list []BlockType, blockType BlockType) bool {
+		for _, b := range list {
+			if b == blockType {
+				return true
+			}
+		}
+		return false
+	}
+
+	decorative := FilterBlocksByCategory(all, CategoryDecorative)
+	for _, blockType := range []BlockType{BlockPlanks, BlockBricks, BlockCobblestone, BlockGlass} {
+		if !contains(decorative, blockType) {
+			t.Errorf("Decorative tab should have block %d, got %v", blockType, decorative)
+		}
+	}
+
+	natural := FilterBlocksByCategory(all, CategoryNatural)
+	if !contains(natural, BlockGrass) || !contains(natural, BlockWater) || contains(natural, BlockGlass) {
+		t.Errorf("Natural tab has the wrong blocks: %v", natural)
+	}
+	if len(natural)+len(decorative) != len(all) {
+		t.Errorf("Every block should be in exactly one category")
+	}
+	if custom := FilterBlocksByCategory(all, CategoryCustom); len(custom) != 0 {
+		t.Errorf("Built-in blocks should not be in the custom tab, got %v", custom)
+	}
+
+	// Uma categoria definida em BlockDefinitions muda a aba do bloco
+	BlockDefinitions[BlockClay] = CustomBlockDefinition{Alpha: 255, Category: CategoryDecorative}
+	defer delete(BlockDefinitions, BlockClay)
+	if !contains(FilterBlocksByCategory(all, CategoryDecorative), BlockClay) {
+		t.Error("Block with a decorative definition should move to the decorative tab")
+	}
+
+	// Trocar de aba filtra a grade e volta a seleção para o início
+	catalog := NewBlockCatalog(DefaultCatalogColumns, DefaultCatalogRows)
+	catalog.SetBlocks(all)
+	catalog.Move(0, 2)
+	catalog.SetCategory(CategoryDecorative)
+	if len(catalog.Blocks) != len(decorative)+1 || catalog.SelectedBlock() != catalog.Blocks[0] {
+		t.Errorf("Expected the decorative blocks with the first selected, got %v (selected %d)", catalog.Blocks, catalog.SelectedBlock())
+	}
+	catalog.NextCategory()
+	if catalog.Category != CategoryCustom || len(catalog.Blocks) != 0 || catalog.SelectedBlock() != BlockAir {
+		t.Errorf("Empty custom tab should select nothing, got %q with %v", catalog.Category, catalog.Blocks)
+	}
+	catalog.NextCategory()
+	if catalog.Category != CategoryAll || len(catalog.Blocks) != len(all) {
+		t.Errorf("Tab after custom should show every block, got %q with %d blocks", catalog.Category, len(catalog.Blocks))
+	}
+}
+
+func TestBlockCatalogLayoutFollowsColumns(t *testing.T) {
+	blocks := make([]BlockType, 30)
+	for i := range blocks {
+		blocks[i] = BlockType(i + 1)
+	}
+
+	for _, tc := range []struct {
+		columns, rows int
+		rowCount      int
+		cell          [2]int // Coluna e linha do índice 13
+	}{
+		{columns: 8, rows: 4, rowCount: 4, cell: [2]int{5, 1}},
+		{columns: 5, rows: 4, rowCount: 6, cell: [2]int{3, 2}},
+		{columns: 12, rows: 2, rowCount: 3, cell: [2]int{1, 1}},
+	} {
+		catalog := NewBlockCatalog(tc.columns, tc.rows)
+		catalog.SetBlocks(blocks)
+
+		if got := catalog.RowCount(); got != tc.rowCount {
+			t.Errorf("%d columns: expected %d rows, got %d", tc.columns, tc.rowCount, got)
+		}
+		if column, row := catalog.Cell(13); column != tc.cell[0] || row != tc.cell[1] {
+			t.Errorf("%d columns: expected index 13 at %v, got (%d, %d)", tc.columns, tc.cell, column, row)
+		}
+
+		// Descer uma linha pula Columns blocos
+		catalog.Move(0, 1)
+		if catalog.Selected != tc.columns {
+			t.Errorf("%d columns: moving down should select index %d, got %d", tc.columns, tc.columns, catalog.Selected)
+		}
+
+		// Ir até a última linha rola a grade e deixa só Rows linhas visíveis
+		for i := 0; i < tc.rowCount; i++ {
+			catalog.Move(0, 1)
+		}
+		start, end := catalog.VisibleRange()
+		if wantScroll := max(tc.rowCount-tc.rows, 0); catalog.Scroll != wantScroll {
+			t.Errorf("%d columns: expected scroll %d, got %d", tc.columns, wantScroll, catalog.Scroll)
+		}
+		if start != catalog.Scroll*tc.columns || end-start > tc.rows*tc.columns || end > len(blocks) {
+			t.Errorf("%d columns: bad visible range [%d, %d)", tc.columns, start, end)
+		}
+		if catalog.Selected < start || catalog.Selected >= end {
+			t.Errorf("%d columns: selected %d outside the visible range [%d, %d)", tc.columns, catalog.Selected, start, end)
+		}
+		if _, row := catalog.Cell(catalog.Selected); row < 0 || row >= tc.rows {
+			t.Errorf("%d columns: selected block drawn at hidden row %d", tc.columns, row)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package game
+
+import "sort"
+
+// CustomBlockDefinition propriedades de renderização de um tipo de bloco
+type CustomBlockDefinition struct {
+	Transparent bool  // Deixa ver os blocos atrás dele (vidro, água): desenhado na passada transparente
+	Alpha       uint8 // Opacidade das faces (255 = opaco); só vale para blocos transparentes
+
+	// Textura de cada face na orientação padrão (índices do mesher: +X, -X, +Y, -Y, +Z, -Z);
+	// BlockAir usa a do próprio bloco. As faces laterais acompanham a rotação do bloco.
+	FaceTextures [6]BlockType
+
+	// Aba do catálogo de blocos (ver BlockCatalog); vazio usa o padrão de GetBlockCategory
+	Category BlockCategory
+}
+
+// BlockCategory categoria de um tipo de bloco, usada para filtrar o catálogo
+type BlockCategory string
+
+// Categorias do catálogo de blocos
+const (
+	CategoryAll        BlockCategory = ""           // Sem filtro
+	CategoryNatural    BlockCategory = "natural"    // Terreno, minérios e líquidos
+	CategoryDecorative BlockCategory = "decorative" // Materiais de construção
+	CategoryCustom     BlockCategory = "custom"     // Criados pelo jogador
+)
+
+// DefaultTransparentAlpha opacidade de um bloco que vira transparente sem opacidade definida
+const DefaultTransparentAlpha = 160
+
+// BlockDefinitions propriedades dos tipos de bloco que não são cubos opacos comuns ou que
+// ficam fora da categoria padrão (os que não estão aqui são opacos com a mesma textura em
+// todas as faces). Alterar em jogo com ChunkManager.SetBlockTransparency.
+var BlockDefinitions = map[BlockType]CustomBlockDefinition{
+	BlockGlass: {Transparent: true, Alpha: 110, Category: CategoryDecorative},
+	BlockWater: {Transparent: true, Alpha: 160},
+	BlockIce:   {Transparent: true, Alpha: 200},
+
+	// Tora com o corte (tábuas) na frente: gira com R antes de colocar
+	BlockWood: {Alpha: 255, FaceTextures: [6]BlockType{4: BlockPlanks}},
+
+	BlockPlanks:      {Alpha: 255, Category: CategoryDecorative},
+	BlockBricks:      {Alpha: 255, Category: CategoryDecorative},
+	BlockCobblestone: {Alpha: 255, Category: CategoryDecorative},
+}
+
+// GetBlockDefinition retorna as propriedades do tipo de bloco
+func GetBlockDefinition(blockType BlockType) CustomBlockDefinition {
+	if def, exists := BlockDefinitions[blockType]; exists {
+		return def
+	}
+	return CustomBlockDefinition{Alpha: 255}
+}
+
+// GetBlockCategory retorna a categoria do tipo de bloco: a da definição ou, sem ela,
+// CategoryCustom para os blocos criados pelo jogador e CategoryNatural para os embutidos
+func GetBlockCategory(blockType BlockType) BlockCategory {
+	if category := BlockDefinitions[blockType].Category; category != CategoryAll {
+		return category
+	}
+	if blockType >= FirstCustomBlockType {
+		return CategoryCustom
+	}
+	return CategoryNatural
+}
+
+// IsTransparentBlock indica se o tipo de bloco é desenhado na passada transparente
+func IsTransparentBlock(blockType BlockType) bool {
+	return BlockDefinitions[blockType].Transparent
+}
+
+// faceVisible indica se a face de um bloco encostada no vizinho deve entrar na mesh: sempre
+// contra ar e contra um bloco transparente de outro tipo (p.ex. a pedra atrás do vidro), mas
+// não entre dois blocos transparentes iguais nem contra um bloco opaco
+func faceVisible(blockType, neighbor BlockType) bool {
+	if neighbor == BlockAir {
+		return true
+	}
+	return neighbor != blockType && IsTransparentBlock(neighbor)
+}
+
+// blockDefinitionsKey serializa as definições em ordem de tipo (entra na chave do cache de
+// meshes, já que mudam as faces geradas)
+func blockDefinitionsKey() []byte {
+	types := make([]int, 0, len(BlockDefinitions))
+	for blockType := range BlockDefinitions {
+		types = append(types, int(blockType))
+	}
+	sort.Ints(types)
+
+	key := make([]byte, 0, len(types)*9)
+	for _, blockType := range types {
+		def := BlockDefinitions[BlockType(blockType)]
+		transparent := byte(0)
+		if def.Transparent {
+			transparent = 1
+		}
+		key = append(key, byte(blockType), transparent, def.Alpha)
+		for _, texture := range def.FaceTextures {
+			key = append(key, byte(texture))
+		}
+	}
+	return key
+}
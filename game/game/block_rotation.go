@@ -0,0 +1,88 @@
+package game
+
+import "fmt"
+
+// BlockRotation giro de um bloco em torno do eixo Y, em quartos de volta no sentido horário
+// visto de cima. Guardado por bloco (Chunk.Rotations); só muda a textura das faces laterais
+// de blocos com FaceTextures (ver BlockFaceTexture).
+type BlockRotation uint8
+
+const (
+	Rotation0 BlockRotation = iota
+	Rotation90
+	Rotation180
+	Rotation270
+
+	blockRotationCount
+)
+
+// yawFaces faces laterais (índices do mesher) na ordem em que um quarto de volta horário leva
+// uma na seguinte: +Z → -X → -Z → +X
+var yawFaces = [blockRotationCount]int{4, 1, 5, 0}
+
+// Next retorna a próxima rotação (tecla R)
+func (r BlockRotation) Next() BlockRotation {
+	return (r + 1) % blockRotationCount
+}
+
+// String retorna a rotação em graus (para a UI)
+func (r BlockRotation) String() string {
+	return fmt.Sprintf("%d°", int(r%blockRotationCount)*90)
+}
+
+// yawFaceIndex posição da face em yawFaces (-1 para topo e fundo, que não giram)
+func yawFaceIndex(face int) int {
+	for i, f := range yawFaces {
+		if f == face {
+			return i
+		}
+	}
+	return -1
+}
+
+// WorldFace retorna a face do mundo ocupada pela face local (orientação padrão) do bloco girado
+func (r BlockRotation) WorldFace(localFace int) int {
+	i := yawFaceIndex(localFace)
+	if i < 0 {
+		return localFace
+	}
+	return yawFaces[(i+int(r%blockRotationCount))%len(yawFaces)]
+}
+
+// LocalFace retorna qual face local do bloco girado aparece na face do mundo (inverso de WorldFace)
+func (r BlockRotation) LocalFace(worldFace int) int {
+	i := yawFaceIndex(worldFace)
+	if i < 0 {
+		return worldFace
+	}
+	n := len(yawFaces)
+	return yawFaces[(i-int(r%blockRotationCount)+n)%n]
+}
+
+// BlockFaceTexture retorna o tipo de bloco cuja textura vai na face do mundo (índice do mesher)
+// de um bloco com a rotação informada: a textura da face local em FaceTextures, ou a do próprio
+// bloco
+func BlockFaceTexture(blockType BlockType, rotation BlockRotation, face int) BlockType {
+	if texture := BlockDefinitions[blockType].FaceTextures[rotation.LocalFace(face)]; texture != BlockAir {
+		return texture
+	}
+	return blockType
+}
+
+// blockTextures retorna as texturas usadas pelas faces do tipo de bloco (a própria primeiro)
+func blockTextures(blockType BlockType) []BlockType {
+	textures := []BlockType{blockType}
+	for _, texture := range BlockDefinitions[blockType].FaceTextures {
+		if texture == BlockAir {
+			continue
+		}
+		seen := false
+		for _, t := range textures {
+			seen = seen || t == texture
+		}
+		if !seen {
+			textures = append(textures, texture)
+		}
+	}
+	return textures
+}
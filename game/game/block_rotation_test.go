@@ -0,0 +1,179 @@
+package game
+
+import (
+	"path/filepath"
+	"testing"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// Helper: textura de cada face de um bloco sozinho no chunk, lida do slot de cada quad da mesh
+// gulosa (um quad por face)
+func singleBlockFaceTextures(t *testing.T, chunk *Chunk) map[int]BlockType {
+	t.Helper()
+
+	slotToTexture := make(map[int]BlockType)
+	for texture, slot := range chunk.ChunkAtlas.UsedBlocks {
+		slotToTexture[int(slot)] = texture
+	}
+
+	mesh := chunk.ChunkMesh
+	if quads := len(mesh.Vertices) / 12; quads != 6 {
+		t.Fatalf("Expected 6 quads for a single block, got %d", quads)
+	}
+
+	textures := make(map[int]BlockType)
+	for quad := 0; quad < 6; quad++ {
+		n := mesh.Normals[quad*12 : quad*12+3]
+		for face, normal := range faceNormals {
+			if normal[0] == n[0] && normal[1] == n[1] && normal[2] == n[2] {
+				textures[face] = slotToTexture[int(mesh.Texcoords2[quad*8])]
+			}
+		}
+	}
+	return textures
+}
+
+func TestBlockRotationFaceTextures(t *testing.T) {
+	DisableGPUUploadForTesting = true
+
+	// BlockWood tem as tábuas na face +Z (4) na orientação padrão; cada quarto de volta horário
+	// (visto de cima) leva a frente para a próxima face lateral
+	expectedFront := map[BlockRotation]int{
+		Rotation0:   4, // +Z
+		Rotation90:  1, // -X
+		Rotation180: 5, // -Z
+		Rotation270: 0, // +X
+	}
+
+	atlas := NewDynamicAtlasManager(4, 32)
+	atlas.AllocateSlot(BlockWood)
+	atlas.AllocateSlot(BlockPlanks)
+
+	for rotation, front := range expectedFront {
+		chunk := NewChunk(0, 0, 0)
+		chunk.GreedyMeshing = true
+		chunk.SetBlockWithRotation(5, 5, 5, BlockWood, rotation)
+		if chunk.GetRotation(5, 5, 5) != rotation {
+			t.Fatalf("Rotation %s was not stored", rotation)
+		}
+		chunk.UpdateMeshesWithNeighbors(chunkOnlyBlocks(chunk), nil)
+
+		textures := singleBlockFaceTextures(t, chunk)
+		for face := 0; face < 6; face++ {
+			want := BlockWood
+			if face == front {
+				want = BlockPlanks
+			}
+			if textures[face] != want {
+				t.Errorf("Rotation %s, face %d: mesh uses texture %d, want %d", rotation, face, textures[face], want)
+			}
+			if got := BlockFaceTexture(BlockWood, rotation, face); got != want {
+				t.Errorf("Rotation %s, face %d: BlockFaceTexture = %d, want %d", rotation, face, got, want)
+			}
+
+			// O atlas global resolve as UVs da face pela rotação
+			uMin, vMin, _, _ := atlas.GetFaceUVs(BlockWood, rotation, face)
+			wantU, wantV, _, _ := atlas.GetBlockUVs(want)
+			if uMin != wantU || vMin != wantV {
+				t.Errorf("Rotation %s, face %d: atlas UVs (%.2f, %.2f), want (%.2f, %.2f)", rotation, face, uMin, vMin, wantU, wantV)
+			}
+		}
+
+		// Sem greedy meshing a textura das faces é a mesma
+		plain := NewChunk(0, 0, 0)
+		plain.SetBlockWithRotation(5, 5, 5, BlockWood, rotation)
+		plain.UpdateMeshesWithNeighbors(chunkOnlyBlocks(plain), nil)
+		uMin, vMin, _, _ := plain.ChunkAtlas.GetBlockUVs(BlockPlanks)
+		for quad := 0; quad < 6; quad++ {
+			n := plain.ChunkMesh.Normals[quad*12 : quad*12+3]
+			isFront := faceNormals[front][0] == n[0] && faceNormals[front][1] == n[1] && faceNormals[front][2] == n[2]
+			usesPlanks := plain.ChunkMesh.Texcoords[quad*8+2] == uMin && plain.ChunkMesh.Texcoords[quad*8+3] == vMin
+			if isFront != usesPlanks {
+				t.Errorf("Rotation %s: quad with normal %v uses planks = %v, want %v", rotation, n, usesPlanks, isFront)
+			}
+		}
+	}
+}
+
+func TestBlockRotationFaceMapping(t *testing.T) {
+	for r := Rotation0; r < blockRotationCount; r++ {
+		for face := 0; face < 6; face++ {
+			if got := r.LocalFace(r.WorldFace(face)); got != face {
+				t.Errorf("Rotation %s: LocalFace(WorldFace(%d)) = %d", r, face, got)
+			}
+		}
+		// Topo e fundo não giram
+		if r.WorldFace(2) != 2 || r.WorldFace(3) != 3 {
+			t.Errorf("Rotation %s should keep top and bottom faces", r)
+		}
+	}
+
+	if Rotation270.Next() != Rotation0 {
+		t.Error("Rotation should cycle back to 0 after 270°")
+	}
+}
+
+func TestPlayerRotatesPlacedBlock(t *testing.T) {
+	world := createChunkedFlatWorld()
+	player := NewPlayer(rl.NewVector3(16.5, 11, 16.5))
+	input := &SimulatedInput{}
+
+	for i := 0; i < 3; i++ {
+		input.Rotate = true
+		player.Update(1.0/60.0, world, input)
+	}
+	if player.PlaceRotation != Rotation270 {
+		t.Fatalf("Expected rotation 270° after pressing R three times, got %s", player.PlaceRotation)
+	}
+
+	// Colocar um bloco direcional mirando o chão à frente
+	player.PlaceBlockType = BlockWood
+	player.FirstPerson = true
+	player.Pitch = -0.6
+	input.RightClick = true
+	player.Update(1.0/60.0, world, input)
+	if !player.LookingAtBlock {
+		t.Fatal("Player should be looking at the ground")
+	}
+	x, y, z := int32(player.PlaceBlock.X), int32(player.PlaceBlock.Y), int32(player.PlaceBlock.Z)
+
+	if world.GetBlock(x, y, z) != BlockWood {
+		t.Fatalf("Expected wood placed at (%d, %d, %d), got %d", x, y, z, world.GetBlock(x, y, z))
+	}
+	if world.GetBlockRotation(x, y, z) != Rotation270 {
+		t.Errorf("Placed block should keep the player's rotation, got %s", world.GetBlockRotation(x, y, z))
+	}
+
+	// Remover e colocar outro bloco pelo SetBlock volta à orientação padrão
+	world.SetBlock(x, y, z, BlockStone)
+	if world.GetBlockRotation(x, y, z) != Rotation0 {
+		t.Errorf("SetBlock should reset the rotation, got %s", world.GetBlockRotation(x, y, z))
+	}
+}
+
+func TestWorldSaveKeepsBlockRotation(t *testing.T) {
+	world := newGeneratedTestWorld()
+	world.SetBlockWithRotation(3, 20, 3, BlockWood, Rotation90)
+
+	// Girar um bloco gerado sem mudar o tipo também é uma edição
+	blockType := world.GetBlock(-4, 1, -4)
+	world.SetBlockWithRotation(-4, 1, -4, blockType, Rotation180)
+
+	path := filepath.Join(t.TempDir(), "world.sav")
+	if err := world.Save(path); err != nil {
+		t.Fatalf("Failed to save world: %v", err)
+	}
+
+	loaded := NewWorld()
+	if err := loaded.Load(path); err != nil {
+		t.Fatalf("Failed to load world: %v", err)
+	}
+
+	if loaded.GetBlock(3, 20, 3) != BlockWood || loaded.GetBlockRotation(3, 20, 3) != Rotation90 {
+		t.Errorf("Expected wood at 90°, got %d at %s", loaded.GetBlock(3, 20, 3), loaded.GetBlockRotation(3, 20, 3))
+	}
+	if loaded.GetBlockRotation(-4, 1, -4) != Rotation180 {
+		t.Errorf("Rotation-only edit should be saved, got %s", loaded.GetBlockRotation(-4, 1, -4))
+	}
+}
@@ -0,0 +1,152 @@
+package game
+
+import (
+	"hash/fnv"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// Limites da área onde os blocos comemorativos são colocados
+const (
+	BlockMarkerRange     = 64 // X e Z ficam em [-BlockMarkerRange, BlockMarkerRange)
+	BlockMarkerMinY      = 10 // Logo acima da superfície do terreno (y=8)
+	BlockMarkerMaxY      = 30 // Inclusivo, ainda dentro do primeiro chunk vertical
+	DefaultMaxMarkers    = 256
+	blockMarkerEventsMax = 16 // Eventos processados por frame
+)
+
+// BlockEvent representa um bloco minerado recebido de um nó da blockchain
+type BlockEvent struct {
+	Height    uint64
+	Hash      string
+	Validator string // Endereço do validador que produziu o bloco
+}
+
+// BlockEventSource fornece eventos de blocos minerados (ex: NodeBlockFeed)
+type BlockEventSource interface {
+	Events() <-chan BlockEvent
+}
+
+// BlockMarker é um bloco comemorativo colocado no mundo
+type BlockMarker struct {
+	Event  BlockEvent
+	X      int32
+	Y      int32
+	Z      int32
+	Placed bool // true quando o bloco já foi escrito em um chunk carregado
+}
+
+// BlockViewer visualiza a atividade da blockchain no mundo voxel.
+// Cada bloco minerado vira um bloco de ouro em coordenadas derivadas do hash.
+type BlockViewer struct {
+	Source     BlockEventSource
+	Markers    []*BlockMarker
+	MaxMarkers int
+	LastEvent  *BlockEvent
+	MarkerType BlockType
+}
+
+// NewBlockViewer cria um visualizador. source pode ser nil (nenhum nó conectado).
+func NewBlockViewer(source BlockEventSource) *BlockViewer {
+	return &BlockViewer{
+		Source:     source,
+		Markers:    make([]*BlockMarker, 0),
+		MaxMarkers: DefaultMaxMarkers,
+		MarkerType: BlockGoldOre,
+	}
+}
+
+// HashToWorldCoords converte o hash de um bloco em coordenadas do mundo.
+// O mapeamento é determinístico e sempre respeita os limites BlockMarker*.
+func HashToWorldCoords(hash string) (x, y, z int32) {
+	h := fnv.New64a()
+	h.Write([]byte(hash))
+	v := h.Sum64()
+
+	span := uint64(BlockMarkerRange * 2)
+	heightSpan := uint64(BlockMarkerMaxY - BlockMarkerMinY + 1)
+
+	x = int32(v%span) - BlockMarkerRange
+	z = int32((v>>20)%span) - BlockMarkerRange
+	y = int32((v>>40)%heightSpan) + BlockMarkerMinY
+	return
+}
+
+// HandleEvent registra um bloco minerado como marcador (sem tocar no mundo ainda)
+func (bv *BlockViewer) HandleEvent(event BlockEvent) *BlockMarker {
+	if bv == nil {
+		return nil
+	}
+
+	x, y, z := HashToWorldCoords(event.Hash)
+	marker := &BlockMarker{Event: event, X: x, Y: y, Z: z}
+
+	bv.Markers = append(bv.Markers, marker)
+	if bv.MaxMarkers > 0 && len(bv.Markers) > bv.MaxMarkers {
+		// Descartar o marcador mais antigo (o bloco colocado permanece no mundo)
+		bv.Markers = bv.Markers[len(bv.Markers)-bv.MaxMarkers:]
+	}
+
+	bv.LastEvent = &marker.Event
+	return marker
+}
+
+// Update consome eventos pendentes e coloca os blocos comemorativos nos chunks carregados
+func (bv *BlockViewer) Update(world *World) {
+	if bv == nil {
+		return
+	}
+
+	if bv.Source != nil {
+		events := bv.Source.Events()
+	drain:
+		for i := 0; i < blockMarkerEventsMax; i++ {
+			select {
+			case event, ok := <-events:
+				if !ok {
+					bv.Source = nil
+					break drain
+				}
+				bv.HandleEvent(event)
+			default:
+				break drain
+			}
+		}
+	}
+
+	if world == nil || world.ChunkManager == nil {
+		return
+	}
+
+	for _, marker := range bv.Markers {
+		// Só escrever em chunks já gerados: ChunkManager.SetBlock criaria um chunk
+		// com o gerador antigo e o terreno real nunca seria carregado
+		key := GetChunkCoord(marker.X, marker.Y, marker.Z).Key()
+		if _, loaded := world.ChunkManager.Chunks[key]; !loaded {
+			marker.Placed = false
+			continue
+		}
+
+		if !marker.Placed {
+			// Direto no ChunkManager: marcadores são locais e não viram edições (World.OnBlockEdit)
+			world.ChunkManager.SetBlock(marker.X, marker.Y, marker.Z, bv.MarkerType)
+			marker.Placed = true
+		}
+	}
+}
+
+// Render desenha um contorno nos marcadores colocados (deve ser chamado dentro de BeginMode3D)
+func (bv *BlockViewer) Render() {
+	if bv == nil {
+		return
+	}
+
+	for _, marker := range bv.Markers {
+		if !marker.Placed {
+			continue
+		}
+
+		center := rl.NewVector3(float32(marker.X)+0.5, float32(marker.Y)+0.5, float32(marker.Z)+0.5)
+		rl.DrawCubeWiresV(center, rl.NewVector3(1.1, 1.1, 1.1), rl.Gold)
+	}
+}
@@ -0,0 +1,106 @@
+package game
+
+import (
+	"fmt"
+	"testing"
+)
+
+// fakeBlockSource implementa BlockEventSource para testes
+type fakeBlockSource struct {
+	events chan BlockEvent
+}
+
+func (f *fakeBlockSource) Events() <-chan BlockEvent {
+	return f.events
+}
+
+func TestHashToWorldCoords_DeterministicAndInBounds(t *testing.T) {
+	for i := 0; i < 1000; i++ {
+		hash := fmt.Sprintf("%064x", i*7919)
+
+		x1, y1, z1 := HashToWorldCoords(hash)
+		x2, y2, z2 := HashToWorldCoords(hash)
+		if x1 != x2 || y1 != y2 || z1 != z2 {
+			t.Fatalf("Mapping not deterministic for %s: (%d,%d,%d) vs (%d,%d,%d)", hash, x1, y1, z1, x2, y2, z2)
+		}
+
+		if x1 < -BlockMarkerRange || x1 >= BlockMarkerRange || z1 < -BlockMarkerRange || z1 >= BlockMarkerRange {
+			t.Fatalf("X/Z out of bounds for %s: (%d, %d)", hash, x1, z1)
+		}
+		if y1 < BlockMarkerMinY || y1 > BlockMarkerMaxY {
+			t.Fatalf("Y out of bounds for %s: %d", hash, y1)
+		}
+	}
+
+	// Hashes diferentes devem se espalhar pelo mundo
+	xa, ya, za := HashToWorldCoords("aaaa")
+	xb, yb, zb := HashToWorldCoords("bbbb")
+	if xa == xb && ya == yb && za == zb {
+		t.Error("Different hashes mapped to the same coordinates")
+	}
+}
+
+func TestBlockViewer_NilSafe(t *testing.T) {
+	world := createFlatWorld()
+
+	var nilViewer *BlockViewer
+	nilViewer.Update(world)
+	if nilViewer.HandleEvent(BlockEvent{Height: 1, Hash: "abc"}) != nil {
+		t.Error("Nil viewer should not create markers")
+	}
+
+	// Sem nó conectado
+	viewer := NewBlockViewer(nil)
+	viewer.Update(world)
+	viewer.Update(nil)
+	if len(viewer.Markers) != 0 {
+		t.Errorf("Expected no markers without a node, got %d", len(viewer.Markers))
+	}
+}
+
+func TestBlockViewer_PlacesMarkerInLoadedChunk(t *testing.T) {
+	world := createFlatWorld()
+	source := &fakeBlockSource{events: make(chan BlockEvent, 4)}
+	viewer := NewBlockViewer(source)
+
+	// Procurar um hash que caia em um chunk carregado (-1..1, 0, -1..1)
+	var hash string
+	for i := 0; ; i++ {
+		hash = fmt.Sprintf("block-%d", i)
+		x, y, z := HashToWorldCoords(hash)
+		coord := GetChunkCoord(x, y, z)
+		if coord.X >= -1 && coord.X <= 1 && coord.Z >= -1 && coord.Z <= 1 && coord.Y == 0 {
+			break
+		}
+	}
+
+	source.events <- BlockEvent{Height: 7, Hash: hash}
+	viewer.Update(world)
+
+	if len(viewer.Markers) != 1 {
+		t.Fatalf("Expected 1 marker, got %d", len(viewer.Markers))
+	}
+	marker := viewer.Markers[0]
+	if !marker.Placed {
+		t.Fatal("Marker in a loaded chunk should be placed")
+	}
+	if world.GetBlock(marker.X, marker.Y, marker.Z) != BlockGoldOre {
+		t.Error("Commemorative block was not placed in the world")
+	}
+	if viewer.LastEvent == nil || viewer.LastEvent.Height != 7 {
+		t.Error("LastEvent should track the most recent block")
+	}
+
+	chunksBefore := world.GetLoadedChunksCount()
+
+	// Marcador fora dos chunks carregados fica pendente sem criar chunks
+	viewer.HandleEvent(BlockEvent{Height: 8, Hash: hash})
+	viewer.Markers[1].X += 10 * ChunkSize
+	viewer.Update(world)
+	if viewer.Markers[1].Placed {
+		t.Error("Marker in an unloaded chunk should stay pending")
+	}
+	if world.GetLoadedChunksCount() != chunksBefore {
+		t.Error("Viewer must not create chunks")
+	}
+}
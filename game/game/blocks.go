@@ -30,6 +30,34 @@ const (
 	BlockMoss
 )
 
+// BlockNames nome de exibição dos blocos embutidos (os criados pelo jogador usam o próprio nome,
+// ver World.BlockName)
+var BlockNames = map[BlockType]string{
+	BlockGrass:       "Grama",
+	BlockDirt:        "Terra",
+	BlockStone:       "Pedra",
+	BlockWood:        "Madeira",
+	BlockLeaves:      "Folhas",
+	BlockSand:        "Areia",
+	BlockGravel:      "Cascalho",
+	BlockCobblestone: "Pedregulho",
+	BlockPlanks:      "Tábuas",
+	BlockBricks:      "Tijolos",
+	BlockGlass:       "Vidro",
+	BlockIronOre:     "Minério de ferro",
+	BlockGoldOre:     "Minério de ouro",
+	BlockDiamondOre:  "Minério de diamante",
+	BlockCoal:        "Carvão",
+	BlockSnow:        "Neve",
+	BlockIce:         "Gelo",
+	BlockObsidian:    "Obsidiana",
+	BlockBedrock:     "Rocha matriz",
+	BlockWater:       "Água",
+	BlockLava:        "Lava",
+	BlockClay:        "Argila",
+	BlockMoss:        "Musgo",
+}
+
 // GetBlockUVs retorna as coordenadas UV normalizadas (0-1) para um tipo de bloco
 // Atlas é 8x8, cada textura 32x32 pixels (256x256 total)
 func GetBlockUVs(blockType BlockType) (uMin, vMin, uMax, vMax float32) {
@@ -1,6 +1,7 @@
 package game
 
 import (
+	"fmt"
 	"math"
 
 	rl "github.com/gen2brain/raylib-go/raylib"
@@ -24,6 +25,21 @@ type Chunk struct {
 	ChunkAtlas       *ChunkAtlas // Atlas de texturas específico deste chunk
 	NeedUpdateMeshes bool
 	IsGenerated      bool
+
+	// Giro de cada bloco em torno do eixo Y (ver BlockRotation; blocos gerados ficam em Rotation0)
+	Rotations [ChunkSize][ChunkHeight][ChunkSize]BlockRotation
+
+	// Sombreia os vértices das faces pela oclusão ambiente dos blocos vizinhos ao gerar a mesh
+	AmbientOcclusion bool
+
+	// Editado pelo jogador desde a geração procedural (persistido por World.Save)
+	Modified bool
+
+	// Junta faces coplanares do mesmo bloco em quads maiores ao gerar a mesh (ver buildGreedyMesh)
+	GreedyMeshing bool
+
+	// Faces dos blocos transparentes (vidro, água), desenhadas depois das opacas com alpha
+	TransparentMesh *ChunkMesh
 }
 
 // NewChunk cria um novo chunk nas coordenadas especificadas
@@ -34,6 +50,7 @@ func NewChunk(x, y, z int32) *Chunk {
 		ChunkAtlas:       NewChunkAtlas(16, 32), // Atlas 8x8 = 64 slots
 		NeedUpdateMeshes: true,
 		IsGenerated:      false,
+		TransparentMesh:  NewChunkMesh(),
 	}
 }
 
@@ -45,12 +62,26 @@ func (c *Chunk) GetBlock(x, y, z int32) BlockType {
 	return c.Blocks[x][y][z]
 }
 
-// SetBlock define o tipo de bloco nas coordenadas locais do chunk (0-31)
+// GetRotation retorna a rotação do bloco nas coordenadas locais do chunk (0-31)
+func (c *Chunk) GetRotation(x, y, z int32) BlockRotation {
+	if x < 0 || x >= ChunkSize || y < 0 || y >= ChunkHeight || z < 0 || z >= ChunkSize {
+		return Rotation0
+	}
+	return c.Rotations[x][y][z]
+}
+
+// SetBlock define o tipo de bloco nas coordenadas locais do chunk (0-31), na orientação padrão
 func (c *Chunk) SetBlock(x, y, z int32, block BlockType) {
+	c.SetBlockWithRotation(x, y, z, block, Rotation0)
+}
+
+// SetBlockWithRotation define o tipo e a rotação do bloco nas coordenadas locais do chunk (0-31)
+func (c *Chunk) SetBlockWithRotation(x, y, z int32, block BlockType, rotation BlockRotation) {
 	if x < 0 || x >= ChunkSize || y < 0 || y >= ChunkHeight || z < 0 || z >= ChunkSize {
 		return
 	}
 	c.Blocks[x][y][z] = block
+	c.Rotations[x][y][z] = rotation % blockRotationCount
 	c.NeedUpdateMeshes = true
 }
 
@@ -105,8 +136,16 @@ func (c *Chunk) GenerateTerrain() {
 	// Meshes serão atualizadas no primeiro render
 }
 
-// GenerateTerrainWithGenerator gera terreno usando TerrainGenerator
-func (c *Chunk) GenerateTerrainWithGenerator(tg *TerrainGenerator) {
+// GenerateTerrainWithGenerator gera terreno usando TerrainGenerator (de uma vez, se ele
+// implementar ChunkFiller)
+func (c *Chunk) GenerateTerrainWithGenerator(tg TerrainGenerator) {
+	if filler, ok := tg.(ChunkFiller); ok {
+		filler.FillChunk(c)
+		c.IsGenerated = true
+		c.NeedUpdateMeshes = true
+		return
+	}
+
 	worldX := c.Coord.X * ChunkSize
 	worldY := c.Coord.Y * ChunkHeight
 	worldZ := c.Coord.Z * ChunkSize
@@ -179,13 +218,20 @@ func (c *Chunk) UpdateMeshes(atlas *DynamicAtlasManager) {
 
 // UpdateMeshesWithNeighbors atualiza meshes considerando chunks vizinhos
 func (c *Chunk) UpdateMeshesWithNeighbors(getBlockFunc func(x, y, z int32) BlockType, globalAtlas *DynamicAtlasManager) {
-	// Limpar mesh anterior
+	// Limpar meshes anteriores
 	c.ChunkMesh.Clear()
+	c.TransparentMesh.Clear()
 
 	// Resetar atlas do chunk
 	c.ChunkAtlas.UsedBlocks = make(map[BlockType]int32)
 	c.ChunkAtlas.NeedsRebuild = true
 
+	if c.GreedyMeshing {
+		c.buildGreedyMesh(getBlockFunc)
+		c.finishMeshUpdate(globalAtlas)
+		return
+	}
+
 	// Posição mundial do chunk
 	worldX := c.Coord.X * ChunkSize
 	worldY := c.Coord.Y * ChunkHeight
@@ -222,24 +268,168 @@ func (c *Chunk) UpdateMeshesWithNeighbors(getBlockFunc func(x, y, z int32) Block
 				for faceIndex, dir := range directions {
 					neighborBlock := getBlockFunc(wx+dir.dx, wy+dir.dy, wz+dir.dz)
 
-					// Se o vizinho é ar, a face está exposta
-					if neighborBlock == BlockAir {
-						// Adicionar quad para esta face usando o atlas do chunk
-						c.ChunkMesh.AddQuadWithChunkAtlas(float32(wx), float32(wy), float32(wz), faceIndex, blockType, c.ChunkAtlas)
+					// Se o vizinho é ar (ou um bloco transparente de outro tipo), a face está exposta
+					if faceVisible(blockType, neighborBlock) {
+						// Adicionar quad para esta face usando o atlas do chunk, com a textura da
+						// face conforme a rotação do bloco
+						texture := BlockFaceTexture(blockType, c.Rotations[x][y][z], faceIndex)
+						c.ChunkAtlas.AddBlockType(texture)
+						mesh := c.meshFor(blockType)
+						mesh.AddQuadWithChunkAtlas(float32(wx), float32(wy), float32(wz), faceIndex, texture, c.ChunkAtlas)
+						if c.AmbientOcclusion {
+							mesh.ShadeLastQuad(quadAmbientOcclusion(getBlockFunc, wx, wy, wz, dir.dx, dir.dy, dir.dz, mesh.Vertices[len(mesh.Vertices)-12:]))
+						}
+						c.setQuadAlpha(mesh, blockType)
 					}
 				}
 			}
 		}
 	}
 
+	c.finishMeshUpdate(globalAtlas)
+}
+
+// Unload libera os recursos de GPU do chunk (meshes e textura do atlas); chamar ao descarregá-lo
+func (c *Chunk) Unload() {
+	c.ChunkMesh.Clear()
+	c.TransparentMesh.Clear()
+	c.ChunkAtlas.Unload()
+}
+
+// meshFor retorna a mesh que recebe as faces do tipo de bloco (transparente ou opaca)
+func (c *Chunk) meshFor(blockType BlockType) *ChunkMesh {
+	if IsTransparentBlock(blockType) {
+		return c.TransparentMesh
+	}
+	return c.ChunkMesh
+}
+
+// setQuadAlpha aplica a opacidade do bloco ao último quad da mesh transparente
+func (c *Chunk) setQuadAlpha(mesh *ChunkMesh, blockType BlockType) {
+	if mesh == c.TransparentMesh {
+		mesh.SetLastQuadAlpha(GetBlockDefinition(blockType).Alpha)
+	}
+}
+
+// aoBrightness brilho de um vértice conforme quantos dos 3 blocos ao redor dele o ocluem
+var aoBrightness = [4]uint8{255, 204, 166, 128}
+
+// quadAmbientOcclusion calcula o brilho dos 4 vértices (vertices: x, y, z de cada um) da face
+// do bloco (wx, wy, wz) com normal (dx, dy, dz). Cada vértice olha os dois blocos laterais e o
+// diagonal na camada em frente à face; com as duas laterais sólidas o canto conta como ocluído.
+// Blocos transparentes (vidro, água) deixam passar a luz e não ocluem.
+func quadAmbientOcclusion(getBlockFunc func(x, y, z int32) BlockType, wx, wy, wz, dx, dy, dz int32, vertices []float32) [4]uint8 {
+	solid := func(x, y, z int32) int {
+		if block := getBlockFunc(x, y, z); block == BlockAir || IsTransparentBlock(block) {
+			return 0
+		}
+		return 1
+	}
+
+	// Bloco em frente à face
+	fx, fy, fz := wx+dx, wy+dy, wz+dz
+
+	var brightness [4]uint8
+	for i := 0; i < 4; i++ {
+		// Direção do vértice a partir do centro do bloco em cada eixo tangente à face
+		// (o eixo da normal fica zerado)
+		var ox, oy, oz int32
+		if dx == 0 {
+			ox = cornerOffset(vertices[i*3], wx)
+		}
+		if dy == 0 {
+			oy = cornerOffset(vertices[i*3+1], wy)
+		}
+		if dz == 0 {
+			oz = cornerOffset(vertices[i*3+2], wz)
+		}
+
+		// Laterais: o deslocamento em cada eixo tangente separadamente
+		var side1, side2 int
+		switch {
+		case dx != 0:
+			side1, side2 = solid(fx, fy+oy, fz), solid(fx, fy, fz+oz)
+		case dy != 0:
+			side1, side2 = solid(fx+ox, fy, fz), solid(fx, fy, fz+oz)
+		default:
+			side1, side2 = solid(fx+ox, fy, fz), solid(fx, fy+oy, fz)
+		}
+		corner := solid(fx+ox, fy+oy, fz+oz)
+
+		occlusion := side1 + side2 + corner
+		if side1 == 1 && side2 == 1 {
+			occlusion = 3
+		}
+		brightness[i] = aoBrightness[occlusion]
+	}
+
+	return brightness
+}
+
+// cornerOffset retorna -1 se a coordenada do vértice está na face inferior do bloco e +1 se
+// está na superior
+func cornerOffset(vertex float32, block int32) int32 {
+	if int32(vertex) == block {
+		return -1
+	}
+	return 1
+}
+
+// UpdateMeshesWithCache atualiza a mesh usando o cache em disco: se os blocos do chunk e da
+// borda dos vizinhos não mudaram desde a última construção, carrega a mesh gravada em vez de
+// reconstruí-la. Retorna true se a mesh veio do cache. Com cache nil, apenas reconstrói.
+func (c *Chunk) UpdateMeshesWithCache(getBlockFunc func(x, y, z int32) BlockType, globalAtlas *DynamicAtlasManager, cache *ChunkMeshCache) bool {
+	if cache == nil {
+		c.UpdateMeshesWithNeighbors(getBlockFunc, globalAtlas)
+		return false
+	}
+
+	key := cache.Key(c, getBlockFunc)
+	if cached, ok := cache.Load(c.Coord, key); ok {
+		c.ChunkMesh.Clear()
+		c.ChunkMesh.Vertices = append(c.ChunkMesh.Vertices, cached.Vertices...)
+		c.ChunkMesh.Texcoords = append(c.ChunkMesh.Texcoords, cached.Texcoords...)
+		c.ChunkMesh.Texcoords2 = append(c.ChunkMesh.Texcoords2, cached.Texcoords2...)
+		c.ChunkMesh.Normals = append(c.ChunkMesh.Normals, cached.Normals...)
+		c.ChunkMesh.Indices = append(c.ChunkMesh.Indices, cached.Indices...)
+		c.ChunkMesh.Colors = append(c.ChunkMesh.Colors, cached.Colors...)
+
+		c.TransparentMesh.Clear()
+		cached.Transparent.appendTo(c.TransparentMesh)
+
+		c.ChunkAtlas.UsedBlocks = cached.UsedBlocks
+		if c.ChunkAtlas.UsedBlocks == nil {
+			c.ChunkAtlas.UsedBlocks = make(map[BlockType]int32)
+		}
+		c.ChunkAtlas.NeedsRebuild = true
+
+		c.finishMeshUpdate(globalAtlas)
+		return true
+	}
+
+	c.UpdateMeshesWithNeighbors(getBlockFunc, globalAtlas)
+	if err := cache.Store(c, key); err != nil {
+		fmt.Printf("AVISO: Erro ao salvar mesh do chunk %v no cache: %v\n", c.Coord, err)
+	}
+	return false
+}
+
+// finishMeshUpdate reconstrói o atlas do chunk e envia a mesh para a GPU
+func (c *Chunk) finishMeshUpdate(globalAtlas *DynamicAtlasManager) {
+	c.ChunkAtlas.Tiled = c.GreedyMeshing
+
 	// Rebuildar atlas do chunk se necessário
+	if globalAtlas != nil {
+		c.ChunkAtlas.SetTileSize(globalAtlas.TileSize)
+	}
 	if c.ChunkAtlas.NeedsRebuild && globalAtlas != nil {
 		c.ChunkAtlas.RebuildAtlas(globalAtlas.TextureCache)
 		c.ChunkAtlas.UploadToGPU()
 	}
 
-	// Upload mesh para GPU
+	// Upload meshes para GPU
 	c.ChunkMesh.UploadToGPU()
+	c.TransparentMesh.UploadToGPU()
 
 	c.NeedUpdateMeshes = false
 }
@@ -0,0 +1,213 @@
+package game
+
+import (
+	"reflect"
+	"testing"
+)
+
+// Helper: conta os vértices sombreados (cor abaixo de branco) da mesh
+func countShadedVertices(mesh *ChunkMesh) int {
+	shaded := 0
+	for i := 0; i < len(mesh.Colors); i += 4 {
+		if mesh.Colors[i] < 255 {
+			shaded++
+		}
+	}
+	return shaded
+}
+
+func TestAmbientOcclusionToggleRemeshesChunks(t *testing.T) {
+	DisableGPUUploadForTesting = true
+
+	cm := NewChunkManager(1)
+	chunk := newCachedTestChunk()
+	cm.Chunks[chunk.Coord.Key()] = chunk
+
+	// Sem oclusão ambiente: todos os vértices brancos
+	cm.UpdatePendingMeshes(10, nil)
+	if len(chunk.ChunkMesh.Colors) != len(chunk.ChunkMesh.Vertices)/3*4 {
+		t.Fatalf("Expected one RGBA color per vertex, got %d colors for %d vertices",
+			len(chunk.ChunkMesh.Colors), len(chunk.ChunkMesh.Vertices)/3)
+	}
+	if shaded := countShadedVertices(chunk.ChunkMesh); shaded != 0 {
+		t.Fatalf("Expected no shaded vertices with AO disabled, got %d", shaded)
+	}
+	plainVertices := append([]float32(nil), chunk.ChunkMesh.Vertices...)
+	plainColors := append([]uint8(nil), chunk.ChunkMesh.Colors...)
+
+	// Ligar marca os chunks carregados para reconstrução
+	cm.SetAmbientOcclusion(true)
+	if !chunk.NeedUpdateMeshes {
+		t.Fatal("Enabling AO should mark loaded chunks dirty")
+	}
+	cm.UpdatePendingMeshes(10, nil)
+
+	// O degrau (grama sobre a terra) escurece os cantos das faces de terra ao redor dele
+	if shaded := countShadedVertices(chunk.ChunkMesh); shaded == 0 {
+		t.Error("Expected shaded vertices with AO enabled")
+	}
+	if !reflect.DeepEqual(chunk.ChunkMesh.Vertices, plainVertices) {
+		t.Error("AO should only change vertex colors, not geometry")
+	}
+
+	// Repetir a mesma configuração não reconstrói nada
+	cm.SetAmbientOcclusion(true)
+	if chunk.NeedUpdateMeshes {
+		t.Error("Setting the same AO value should not mark chunks dirty")
+	}
+
+	// Desligar volta às cores originais
+	cm.SetAmbientOcclusion(false)
+	if !chunk.NeedUpdateMeshes {
+		t.Fatal("Disabling AO should mark loaded chunks dirty")
+	}
+	cm.UpdatePendingMeshes(10, nil)
+	if !reflect.DeepEqual(chunk.ChunkMesh.Colors, plainColors) {
+		t.Error("Disabling AO should restore unshaded vertex colors")
+	}
+
+	// Chunks carregados depois herdam a configuração do gerenciador
+	cm.SetAmbientOcclusion(true)
+	cm.SetBlock(100, 0, 100, BlockStone)
+	if created := cm.Chunks[GetChunkCoord(100, 0, 100).Key()]; created == nil || !created.AmbientOcclusion {
+		t.Error("New chunks should use the manager's AO setting")
+	}
+}
+
+func TestAmbientOcclusionShadesInnerCorner(t *testing.T) {
+	DisableGPUUploadForTesting = true
+
+	// Chão com uma parede ao lado: a face de cima do chão encostada na parede fica sombreada
+	chunk := NewChunk(0, 0, 0)
+	chunk.AmbientOcclusion = true
+	chunk.SetBlock(1, 0, 1, BlockStone) // Chão
+	chunk.SetBlock(2, 1, 1, BlockStone) // Parede em +X, um nível acima
+	chunk.UpdateMeshesWithNeighbors(chunkOnlyBlocks(chunk), nil)
+
+	mesh := chunk.ChunkMesh
+	for quad := 0; quad < len(mesh.Vertices)/12; quad++ {
+		v := mesh.Vertices[quad*12 : quad*12+12]
+		n := mesh.Normals[quad*12 : quad*12+3]
+		// Face +Y do bloco de chão (y = 1, x entre 1 e 2)
+		if n[1] != 1 || v[1] != 1 || v[0] < 1 || v[0] > 2 {
+			continue
+		}
+		for i := 0; i < 4; i++ {
+			color := mesh.Colors[quad*16+i*4]
+			if nearWall := v[i*3] == 2; nearWall && color == 255 {
+				t.Errorf("Vertex at x=2 next to the wall should be shaded, got %d", color)
+			} else if !nearWall && color != 255 {
+				t.Errorf("Vertex at x=1 away from the wall should not be shaded, got %d", color)
+			}
+		}
+		return
+	}
+	t.Fatal("Top face of the floor block not found")
+}
+
+func TestChunkMeshCacheKeepsAmbientOcclusion(t *testing.T) {
+	DisableGPUUploadForTesting = true
+	cache := NewChunkMeshCache(t.TempDir())
+
+	original := newCachedTestChunk()
+	original.AmbientOcclusion = true
+	original.UpdateMeshesWithCache(chunkOnlyBlocks(original), nil, cache)
+
+	// Mesma chave com AO: as cores vêm do cache
+	reloaded := newCachedTestChunk()
+	reloaded.AmbientOcclusion = true
+	if !reloaded.UpdateMeshesWithCache(chunkOnlyBlocks(reloaded), nil, cache) {
+		t.Fatal("Unchanged chunk with AO should load its cached mesh")
+	}
+	if !reflect.DeepEqual(reloaded.ChunkMesh.Colors, original.ChunkMesh.Colors) {
+		t.Error("Cached mesh should keep the AO vertex colors")
+	}
+
+	// Sem AO a chave muda e a mesh é reconstruída
+	plain := newCachedTestChunk()
+	if plain.UpdateMeshesWithCache(chunkOnlyBlocks(plain), nil, cache) {
+		t.Error("Toggling AO should invalidate the cached mesh")
+	}
+	if countShadedVertices(plain.ChunkMesh) != 0 {
+		t.Error("Mesh rebuilt without AO should not be shaded")
+	}
+}
+
+func TestQuadAmbientOcclusionPatterns(t *testing.T) {
+	// Face de cima do bloco (0, 0, 0): a camada em frente é y = 1
+	vertices := []float32{0, 1, 0, 1, 1, 0, 1, 1, 1, 0, 1, 1}
+
+	tests := []struct {
+		name     string
+		blocks   map[[3]int32]BlockType
+		expected [4]uint8
+	}{
+		{"open", nil, [4]uint8{255, 255, 255, 255}},
+		{"one side", map[[3]int32]BlockType{{-1, 1, 0}: BlockStone}, [4]uint8{204, 255, 255, 204}},
+		{"corner only", map[[3]int32]BlockType{{-1, 1, -1}: BlockStone}, [4]uint8{204, 255, 255, 255}},
+		{"side and corner", map[[3]int32]BlockType{{-1, 1, 0}: BlockStone, {-1, 1, -1}: BlockStone}, [4]uint8{166, 255, 255, 204}},
+		// Canto côncavo: as duas laterais ocluem totalmente o vértice, mesmo sem o diagonal
+		{"concave corner", map[[3]int32]BlockType{{-1, 1, 0}: BlockStone, {0, 1, -1}: BlockStone}, [4]uint8{128, 204, 255, 204}},
+		{"transparent neighbors", map[[3]int32]BlockType{{-1, 1, 0}: BlockGlass, {0, 1, -1}: BlockWater, {-1, 1, -1}: BlockIce}, [4]uint8{255, 255, 255, 255}},
+		// Blocos atrás da face (mesma camada do bloco) não contam
+		{"behind the face", map[[3]int32]BlockType{{-1, 0, 0}: BlockStone, {0, 0, -1}: BlockStone}, [4]uint8{255, 255, 255, 255}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getBlock := func(x, y, z int32) BlockType {
+				return tt.blocks[[3]int32{x, y, z}]
+			}
+			if got := quadAmbientOcclusion(getBlock, 0, 0, 0, 0, 1, 0, vertices); got != tt.expected {
+				t.Errorf("Expected brightness %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestAmbientOcclusionRemeshesDiagonalChunks(t *testing.T) {
+	DisableGPUUploadForTesting = true
+
+	newManager := func(ao bool) *ChunkManager {
+		cm := NewChunkManager(1)
+		cm.AmbientOcclusion = ao
+		for _, coord := range []ChunkCoord{{0, 0, 0}, {-1, 0, 0}, {0, 0, -1}, {-1, 0, -1}, {1, 0, 1}} {
+			chunk := NewChunk(coord.X, coord.Y, coord.Z)
+			chunk.NeedUpdateMeshes = false
+			cm.Chunks[coord.Key()] = chunk
+		}
+		return cm
+	}
+	marked := func(cm *ChunkManager, coord ChunkCoord) bool {
+		return cm.Chunks[coord.Key()].NeedUpdateMeshes
+	}
+
+	// Bloco no canto (x = 0, z = 0) do chunk de origem: a AO do chunk na diagonal lê esse bloco
+	cm := newManager(true)
+	cm.SetBlock(0, 5, 0, BlockStone)
+	for _, coord := range []ChunkCoord{{-1, 0, 0}, {0, 0, -1}, {-1, 0, -1}} {
+		if !marked(cm, coord) {
+			t.Errorf("Chunk %v next to the edited corner should be remeshed", coord)
+		}
+	}
+	if marked(cm, ChunkCoord{1, 0, 1}) {
+		t.Error("Chunk on the far side should not be remeshed")
+	}
+
+	// Sem AO só os vizinhos por face precisam de mesh nova
+	cm = newManager(false)
+	cm.SetBlock(0, 5, 0, BlockStone)
+	if !marked(cm, ChunkCoord{-1, 0, 0}) || !marked(cm, ChunkCoord{0, 0, -1}) {
+		t.Error("Face neighbors should be remeshed without AO")
+	}
+	if marked(cm, ChunkCoord{-1, 0, -1}) {
+		t.Error("Diagonal chunk should not be remeshed without AO")
+	}
+
+	// Chunk novo: com AO os vizinhos pela aresta também são reconstruídos
+	cm = newManager(true)
+	cm.MarkNeighborsForUpdate(ChunkCoord{0, 0, 0})
+	if !marked(cm, ChunkCoord{-1, 0, -1}) || !marked(cm, ChunkCoord{1, 0, 1}) {
+		t.Error("Edge neighbors of a new chunk should be remeshed with AO")
+	}
+}
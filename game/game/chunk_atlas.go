@@ -22,6 +22,9 @@ type ChunkAtlas struct {
 	Material       rl.Material  // Material específico do chunk
 	NeedsRebuild   bool         // Precisa reconstruir?
 	IsUploaded     bool         // Já foi feito upload para GPU?
+
+	// Desenha com o shader de quads repetidos (mesh gulosa; ver ChunkMesh.AddTiledQuad)
+	Tiled bool
 }
 
 // NewChunkAtlas cria um novo atlas para um chunk
@@ -49,6 +52,15 @@ func (ca *ChunkAtlas) AddBlockType(blockType BlockType) {
 	ca.NeedsRebuild = true
 }
 
+// SetTileSize acompanha o tamanho dos tiles do atlas global (ver DynamicAtlasManager.SetTileSize)
+func (ca *ChunkAtlas) SetTileSize(tileSize int32) {
+	if tileSize == ca.TileSize {
+		return
+	}
+	ca.TileSize = tileSize
+	ca.NeedsRebuild = true
+}
+
 // RebuildAtlas reconstrói o atlas com as texturas necessárias
 func (ca *ChunkAtlas) RebuildAtlas(textureCache map[BlockType]image.Image) {
 	if !ca.NeedsRebuild {
@@ -135,6 +147,11 @@ func (ca *ChunkAtlas) UploadToGPU() {
 	if !ca.IsUploaded {
 		ca.Material = rl.LoadMaterialDefault()
 	}
+	if ca.Tiled {
+		ca.Material.Shader = loadTiledQuadShader()
+	} else {
+		ca.Material.Shader = loadChunkShader()
+	}
 
 	diffuseMap := ca.Material.GetMap(rl.MapDiffuse)
 	diffuseMap.Texture = ca.AtlasTexture
@@ -142,6 +159,81 @@ func (ca *ChunkAtlas) UploadToGPU() {
 	ca.IsUploaded = true
 }
 
+// PrepareDraw passa ao shader de quads repetidos o grid atual do atlas (chamar antes de
+// desenhar a mesh do chunk)
+func (ca *ChunkAtlas) PrepareDraw() {
+	if !ca.Tiled {
+		return
+	}
+	rl.SetShaderValue(ca.Material.Shader, tiledQuadGridLoc, []float32{float32(ca.GridSize)}, rl.ShaderUniformFloat)
+}
+
+// Shader de quads repetidos: a UV vem em unidades de bloco e o slot do atlas no segundo
+// conjunto de UVs; fract() repete o tile uma vez por bloco do quad
+const tiledQuadVertexShader = `#version 330
+in vec3 vertexPosition;
+in vec2 vertexTexCoord;
+in vec2 vertexTexCoord2;
+in vec3 vertexNormal;
+in vec4 vertexColor;
+uniform mat4 mvp;
+uniform mat4 matModel;
+out vec2 fragTexCoord;
+out float fragSlot;
+out vec4 fragColor;
+out vec3 fragPosition;
+out vec3 fragNormal;
+void main() {
+    fragTexCoord = vertexTexCoord;
+    fragSlot = vertexTexCoord2.x;
+    fragColor = vertexColor;
+    fragPosition = vec3(matModel*vec4(vertexPosition, 1.0));
+    fragNormal = vertexNormal;
+    gl_Position = mvp*vec4(vertexPosition, 1.0);
+}
+`
+
+const tiledQuadFragmentShader = `#version 330
+in vec2 fragTexCoord;
+in float fragSlot;
+in vec4 fragColor;
+in vec3 fragPosition;
+in vec3 fragNormal;
+uniform sampler2D texture0;
+uniform vec4 colDiffuse;
+uniform float gridSize;
+` + fogShaderUniforms + `
+` + lightShaderUniforms + `
+out vec4 finalColor;
+void main() {
+    float slot = floor(fragSlot + 0.5);
+    vec2 tile = vec2(mod(slot, gridSize), floor(slot/gridSize));
+    vec2 uv = (tile + fract(fragTexCoord))/gridSize;
+    finalColor = applyFog(applyLight(texture(texture0, uv)*colDiffuse*fragColor, fragNormal), fragPosition);
+}
+`
+
+var (
+	tiledQuadShader  rl.Shader
+	tiledQuadGridLoc int32
+	tiledQuadFog     fogShaderLocs
+	tiledQuadLight   lightShaderLocs
+	tiledQuadLoaded  bool
+)
+
+// loadTiledQuadShader carrega o shader de quads repetidos na primeira chamada (compartilhado
+// por todos os chunks)
+func loadTiledQuadShader() rl.Shader {
+	if !tiledQuadLoaded {
+		tiledQuadShader = rl.LoadShaderFromMemory(tiledQuadVertexShader, tiledQuadFragmentShader)
+		tiledQuadGridLoc = rl.GetShaderLocation(tiledQuadShader, "gridSize")
+		tiledQuadFog = getFogShaderLocs(tiledQuadShader)
+		tiledQuadLight = getLightShaderLocs(tiledQuadShader)
+		tiledQuadLoaded = true
+	}
+	return tiledQuadShader
+}
+
 // GetBlockUVs retorna as coordenadas UV para um tipo de bloco
 func (ca *ChunkAtlas) GetBlockUVs(blockType BlockType) (uMin, vMin, uMax, vMax float32) {
 	index, exists := ca.UsedBlocks[blockType]
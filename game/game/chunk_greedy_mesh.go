@@ -0,0 +1,126 @@
+package game
+
+// unshadedFace brilho dos 4 vértices de uma face sem oclusão ambiente
+var unshadedFace = [4]uint8{255, 255, 255, 255}
+
+// greedyCell face exposta na máscara do greedy meshing: só se juntam faces do mesmo tipo de
+// bloco com a mesma textura (que depende da rotação do bloco)
+type greedyCell struct {
+	block   BlockType
+	texture BlockType
+}
+
+// buildGreedyMesh gera a mesh do chunk com greedy meshing: para cada direção de face e cada
+// camada do chunk, monta a máscara das faces expostas e junta as faces vizinhas do mesmo tipo
+// de bloco e textura em retângulos, emitidos como um único quad com a textura repetida por bloco (ver
+// ChunkMesh.AddTiledQuad). Com oclusão ambiente, faces sombreadas não são juntadas (o brilho
+// interpolado em um quad maior seria diferente) e saem como quads de um bloco. Faces de blocos
+// transparentes vão para a mesh transparente do chunk.
+func (c *Chunk) buildGreedyMesh(getBlockFunc func(x, y, z int32) BlockType) {
+	origin := [3]int32{c.Coord.X * ChunkSize, c.Coord.Y * ChunkHeight, c.Coord.Z * ChunkSize}
+	dims := [3]int32{ChunkSize, ChunkHeight, ChunkSize}
+
+	for face := 0; face < 6; face++ {
+		normal := faceNormals[face]
+		dir := [3]int32{int32(normal[0]), int32(normal[1]), int32(normal[2])}
+
+		// Eixo da normal (n) e os dois eixos da camada (a, b)
+		n := 0
+		for axis := 0; axis < 3; axis++ {
+			if dir[axis] != 0 {
+				n = axis
+			}
+		}
+		a, b := (n+1)%3, (n+2)%3
+		if a > b {
+			a, b = b, a
+		}
+		da, db := dims[a], dims[b]
+		mask := make([]greedyCell, da*db)
+
+		for layer := int32(0); layer < dims[n]; layer++ {
+			// Máscara das faces expostas da camada (BlockAir = sem face a juntar)
+			for i := int32(0); i < da; i++ {
+				for j := int32(0); j < db; j++ {
+					var pos [3]int32
+					pos[n], pos[a], pos[b] = layer, i, j
+
+					mask[i*db+j] = greedyCell{}
+					blockType := c.Blocks[pos[0]][pos[1]][pos[2]]
+					if blockType == BlockAir {
+						continue
+					}
+
+					wx, wy, wz := origin[0]+pos[0], origin[1]+pos[1], origin[2]+pos[2]
+					if !faceVisible(blockType, getBlockFunc(wx+dir[0], wy+dir[1], wz+dir[2])) {
+						continue
+					}
+					texture := BlockFaceTexture(blockType, c.Rotations[pos[0]][pos[1]][pos[2]], face)
+					c.ChunkAtlas.AddBlockType(texture)
+
+					if c.AmbientOcclusion {
+						var vertices [12]float32
+						for v, corner := range faceCorners[face] {
+							vertices[v*3] = float32(wx) + corner[0]
+							vertices[v*3+1] = float32(wy) + corner[1]
+							vertices[v*3+2] = float32(wz) + corner[2]
+						}
+						brightness := quadAmbientOcclusion(getBlockFunc, wx, wy, wz, dir[0], dir[1], dir[2], vertices[:])
+						if brightness != unshadedFace {
+							mesh := c.meshFor(blockType)
+							mesh.AddTiledQuad(float32(wx), float32(wy), float32(wz), face, [3]float32{1, 1, 1}, texture, c.ChunkAtlas)
+							mesh.ShadeLastQuad(brightness)
+							c.setQuadAlpha(mesh, blockType)
+							continue
+						}
+					}
+
+					mask[i*db+j] = greedyCell{block: blockType, texture: texture}
+				}
+			}
+
+			// Junta a máscara em retângulos: estende ao longo de b e depois de a
+			for i := int32(0); i < da; i++ {
+				for j := int32(0); j < db; {
+					cell := mask[i*db+j]
+					if cell.block == BlockAir {
+						j++
+						continue
+					}
+
+					width := int32(1)
+					for j+width < db && mask[i*db+j+width] == cell {
+						width++
+					}
+
+					height := int32(1)
+				grow:
+					for i+height < da {
+						for k := int32(0); k < width; k++ {
+							if mask[(i+height)*db+j+k] != cell {
+								break grow
+							}
+						}
+						height++
+					}
+
+					for di := int32(0); di < height; di++ {
+						for dj := int32(0); dj < width; dj++ {
+							mask[(i+di)*db+j+dj] = greedyCell{}
+						}
+					}
+
+					var pos [3]int32
+					pos[n], pos[a], pos[b] = layer, i, j
+					size := [3]float32{1, 1, 1}
+					size[a], size[b] = float32(height), float32(width)
+					mesh := c.meshFor(cell.block)
+					mesh.AddTiledQuad(float32(origin[0]+pos[0]), float32(origin[1]+pos[1]), float32(origin[2]+pos[2]), face, size, cell.texture, c.ChunkAtlas)
+					c.setQuadAlpha(mesh, cell.block)
+
+					j += width
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,189 @@
+package game
+
+import "testing"
+
+// Helper: número de triângulos da mesh
+func meshTriangles(mesh *ChunkMesh) int {
+	return len(mesh.Indices) / 3
+}
+
+// Helper: área (em faces de bloco) coberta pela mesh, por direção de face e slot do atlas.
+// Em meshes gulosas a área do quad é a extensão da textura (UVs em unidades de bloco).
+func greedyFaceArea(mesh *ChunkMesh) map[[2]int]float32 {
+	area := make(map[[2]int]float32)
+	for quad := 0; quad < len(mesh.Vertices)/12; quad++ {
+		n := mesh.Normals[quad*12 : quad*12+3]
+		face := 0
+		for f, normal := range faceNormals {
+			if normal[0] == n[0] && normal[1] == n[1] && normal[2] == n[2] {
+				face = f
+			}
+		}
+		slot := int(mesh.Texcoords2[quad*8])
+		uv := mesh.Texcoords[quad*8+6 : quad*8+8] // Vértice 3: (uSize, vSize)
+		area[[2]int{face, slot}] += uv[0] * uv[1]
+	}
+	return area
+}
+
+// Helper: faces expostas do chunk contadas bloco a bloco, por direção de face e slot do atlas
+func exposedFaces(c *Chunk) map[[2]int]float32 {
+	faces := make(map[[2]int]float32)
+	getBlock := chunkOnlyBlocks(c)
+	for x := int32(0); x < ChunkSize; x++ {
+		for y := int32(0); y < ChunkHeight; y++ {
+			for z := int32(0); z < ChunkSize; z++ {
+				blockType := c.Blocks[x][y][z]
+				if blockType == BlockAir {
+					continue
+				}
+				for face, n := range faceNormals {
+					if getBlock(x+int32(n[0]), y+int32(n[1]), z+int32(n[2])) == BlockAir {
+						faces[[2]int{face, int(c.ChunkAtlas.UsedBlocks[blockType])}]++
+					}
+				}
+			}
+		}
+	}
+	return faces
+}
+
+func TestGreedyMeshingReducesTriangles(t *testing.T) {
+	DisableGPUUploadForTesting = true
+
+	// Cubo sólido de 16³ blocos
+	build := func(greedy bool) *Chunk {
+		chunk := NewChunk(0, 0, 0)
+		chunk.GreedyMeshing = greedy
+		for x := int32(0); x < 16; x++ {
+			for y := int32(0); y < 16; y++ {
+				for z := int32(0); z < 16; z++ {
+					chunk.SetBlock(x, y, z, BlockStone)
+				}
+			}
+		}
+		chunk.UpdateMeshesWithNeighbors(chunkOnlyBlocks(chunk), nil)
+		return chunk
+	}
+
+	plain := meshTriangles(build(false).ChunkMesh)
+	greedy := meshTriangles(build(true).ChunkMesh)
+
+	// Sem greedy: 6 lados x 16x16 faces x 2 triângulos; com greedy: um quad por lado
+	if plain != 6*16*16*2 {
+		t.Errorf("Expected %d triangles without greedy meshing, got %d", 6*16*16*2, plain)
+	}
+	if greedy != 6*2 {
+		t.Errorf("Expected 12 triangles with greedy meshing, got %d", greedy)
+	}
+	t.Logf("Solid 16³ chunk: %d triangles -> %d with greedy meshing", plain, greedy)
+
+	// Terreno plano gerado: a redução também deve ser grande
+	terrain := func(greedy bool) int {
+		chunk := NewChunk(0, 0, 0)
+		chunk.GreedyMeshing = greedy
+		chunk.GenerateTerrain()
+		chunk.UpdateMeshesWithNeighbors(chunkOnlyBlocks(chunk), nil)
+		return meshTriangles(chunk.ChunkMesh)
+	}
+	plainTerrain, greedyTerrain := terrain(false), terrain(true)
+	if greedyTerrain*4 > plainTerrain {
+		t.Errorf("Expected at least 4x fewer triangles on terrain, got %d -> %d", plainTerrain, greedyTerrain)
+	}
+	t.Logf("Terrain chunk: %d triangles -> %d with greedy meshing", plainTerrain, greedyTerrain)
+}
+
+func TestGreedyMeshingKeepsFacesAndTextures(t *testing.T) {
+	DisableGPUUploadForTesting = true
+
+	// Blocos de tipos diferentes lado a lado não podem virar o mesmo quad
+	chunk := newCachedTestChunk()
+	chunk.SetBlock(2, 1, 2, BlockGrass)
+	chunk.GreedyMeshing = true
+	chunk.UpdateMeshesWithNeighbors(chunkOnlyBlocks(chunk), nil)
+
+	mesh := chunk.ChunkMesh
+	if len(mesh.Texcoords2) != len(mesh.Vertices)/3*2 {
+		t.Fatalf("Expected one atlas slot per vertex, got %d for %d vertices", len(mesh.Texcoords2), len(mesh.Vertices)/3)
+	}
+
+	expected := exposedFaces(chunk)
+	got := greedyFaceArea(mesh)
+	if len(got) != len(expected) {
+		t.Errorf("Expected faces for %d (face, block) pairs, got %d", len(expected), len(got))
+	}
+	for key, faces := range expected {
+		if got[key] != faces {
+			t.Errorf("Face %d of slot %d: expected %.0f block faces, got %.0f", key[0], key[1], faces, got[key])
+		}
+	}
+}
+
+func TestGreedyMeshingWithAmbientOcclusion(t *testing.T) {
+	DisableGPUUploadForTesting = true
+
+	build := func(greedy bool) *ChunkMesh {
+		chunk := newCachedTestChunk()
+		chunk.AmbientOcclusion = true
+		chunk.GreedyMeshing = greedy
+		chunk.UpdateMeshesWithNeighbors(chunkOnlyBlocks(chunk), nil)
+		return chunk.ChunkMesh
+	}
+	plain, greedy := build(false), build(true)
+
+	// Faces sombreadas saem sozinhas com o mesmo brilho; só as sem sombra são juntadas
+	if got, want := countShadedVertices(greedy), countShadedVertices(plain); got != want {
+		t.Errorf("Expected %d shaded vertices with greedy meshing, got %d", want, got)
+	}
+	if meshTriangles(greedy) >= meshTriangles(plain) {
+		t.Errorf("Greedy meshing should still merge unshaded faces: %d -> %d triangles", meshTriangles(plain), meshTriangles(greedy))
+	}
+}
+
+func TestSetGreedyMeshingRemeshesChunks(t *testing.T) {
+	DisableGPUUploadForTesting = true
+
+	cm := NewChunkManager(1)
+	chunk := newCachedTestChunk()
+	cm.Chunks[chunk.Coord.Key()] = chunk
+	cm.UpdatePendingMeshes(10, nil)
+
+	cm.SetGreedyMeshing(true)
+	if !chunk.GreedyMeshing || !chunk.NeedUpdateMeshes {
+		t.Fatal("Enabling greedy meshing should update and mark loaded chunks dirty")
+	}
+	cm.UpdatePendingMeshes(10, nil)
+	if !chunk.ChunkAtlas.Tiled {
+		t.Error("Greedy chunk should be drawn with the tiled quad shader")
+	}
+
+	cm.SetGreedyMeshing(true)
+	if chunk.NeedUpdateMeshes {
+		t.Error("Setting the same value should not mark chunks dirty")
+	}
+
+	// A chave do cache muda com a configuração
+	cache := NewChunkMeshCache(t.TempDir())
+	greedyKey := cache.Key(chunk, chunkOnlyBlocks(chunk))
+	chunk.GreedyMeshing = false
+	if cache.Key(chunk, chunkOnlyBlocks(chunk)) == greedyKey {
+		t.Error("Greedy meshing should change the mesh cache key")
+	}
+}
+
+func TestAddTiledQuadMatchesBlockFace(t *testing.T) {
+	atlas := NewChunkAtlas(16, 32)
+	atlas.AddBlockType(BlockStone)
+
+	// Quad de um bloco: mesma geometria da face comum
+	for face := 0; face < 6; face++ {
+		single, tiled := NewChunkMesh(), NewChunkMesh()
+		single.AddQuadWithChunkAtlas(3, 4, 5, face, BlockStone, atlas)
+		tiled.AddTiledQuad(3, 4, 5, face, [3]float32{1, 1, 1}, BlockStone, atlas)
+		for i := range single.Vertices {
+			if single.Vertices[i] != tiled.Vertices[i] || single.Normals[i] != tiled.Normals[i] {
+				t.Fatalf("Face %d: tiled quad geometry differs from the block face", face)
+			}
+		}
+	}
+}
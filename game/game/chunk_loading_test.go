@@ -2,6 +2,7 @@ package game
 
 import (
 	"math"
+	"reflect"
 	"testing"
 
 	rl "github.com/gen2brain/raylib-go/raylib"
@@ -370,3 +371,74 @@ func TestChunkLoading_NoUnloadWithinDistance(t *testing.T) {
 	t.Logf("Chunks que permaneceram carregados: %d de %d", chunksStillLoaded, len(initialChunks))
 	t.Logf("Total de chunks agora: %d", world.GetLoadedChunksCount())
 }
+
+// TestRenderDistanceStreamsChunks verifica que World.RenderDistance define os chunks
+// carregados ao redor do jogador: depois de um salto, ficam exatamente os chunks do raio ao redor
+// da nova posição, e os descarregados liberam as meshes
+func TestRenderDistanceStreamsChunks(t *testing.T) {
+	DisableGPUUploadForTesting = true
+	defer func() { DisableGPUUploadForTesting = false }()
+
+	world := NewWorld()
+	world.RenderDistance = 1
+
+	// Raio esférico em chunks ao redor da posição (o mesmo critério de LoadChunksAroundPlayer)
+	expected := func(pos rl.Vector3) map[ChunkCoord]bool {
+		center := GetChunkCoordFromFloat(pos.X, pos.Y, pos.Z)
+		coords := make(map[ChunkCoord]bool)
+		for x := center.X - 1; x <= center.X+1; x++ {
+			for y := center.Y - 1; y <= center.Y+1; y++ {
+				for z := center.Z - 1; z <= center.Z+1; z++ {
+					dx, dy, dz := x-center.X, y-center.Y, z-center.Z
+					if dx*dx+dy*dy+dz*dz <= 1 {
+						coords[ChunkCoord{X: x, Y: y, Z: z}] = true
+					}
+				}
+			}
+		}
+		return coords
+	}
+	loaded := func() map[ChunkCoord]bool {
+		coords := make(map[ChunkCoord]bool)
+		for _, chunk := range world.ChunkManager.Chunks {
+			coords[chunk.Coord] = true
+		}
+		return coords
+	}
+	settle := func(pos rl.Vector3) {
+		for i := 0; i < 10; i++ {
+			world.Update(pos, 1)
+		}
+	}
+
+	start := rl.NewVector3(16, 15, 16)
+	settle(start)
+	if got, want := loaded(), expected(start); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Expected chunks %v around the start, got %v", want, got)
+	}
+	world.ChunkManager.UpdatePendingMeshes(100, nil)
+	startChunk := world.ChunkManager.Chunks[GetChunkCoordFromFloat(start.X, start.Y, start.Z).Key()]
+	if len(startChunk.ChunkMesh.Vertices) == 0 {
+		t.Fatal("Start chunk should have a mesh before unloading")
+	}
+
+	// Um chunk para o lado: os antigos ainda dentro da folga de descarregamento ficam (sem
+	// carregar/descarregar repetidamente na fronteira)
+	near := rl.NewVector3(16+ChunkSize, 15, 16)
+	settle(near)
+	for coord := range expected(start) {
+		if !loaded()[coord] {
+			t.Errorf("Chunk %v within the unload margin should stay loaded", coord)
+		}
+	}
+
+	// Salto longe: sobra exatamente o raio ao redor da nova posição
+	far := rl.NewVector3(16+6*ChunkSize, 15, 16)
+	settle(far)
+	if got, want := loaded(), expected(far); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected chunks %v around the new position, got %v", want, got)
+	}
+	if len(startChunk.ChunkMesh.Vertices) != 0 || startChunk.ChunkMesh.Uploaded {
+		t.Error("Unloaded chunk should release its mesh")
+	}
+}
@@ -1,7 +1,9 @@
 package game
 
 import (
+	"fmt"
 	"math"
+	"sort"
 
 	rl "github.com/gen2brain/raylib-go/raylib"
 )
@@ -15,6 +17,30 @@ type ChunkManager struct {
 	UpdateCooldown      float32 // Tempo desde a última atualização de chunks
 	UpdateCooldownLimit float32 // Tempo mínimo entre atualizações (em segundos)
 	NewChunksLoaded     bool    // Flag para indicar que novos chunks foram carregados
+
+	// Cache de meshes em disco (nil = sempre reconstruir)
+	MeshCache *ChunkMeshCache
+
+	// Oclusão ambiente nas meshes dos chunks (alterar com SetAmbientOcclusion)
+	AmbientOcclusion bool
+
+	// Greedy meshing nas meshes dos chunks (alterar com SetGreedyMeshing)
+	GreedyMeshing bool
+
+	// Neblina de distância aplicada aos chunks (vale no próximo Render, sem reconstruir meshes)
+	Fog Fog
+
+	// Luz do momento do dia (ver TimeOfDay.Light); o Sky substitui a cor da neblina. Vazia, os
+	// chunks são desenhados sem sombreamento direcional.
+	Light DayLight
+
+	// Chunks desenhados e descartados pelo frustum da câmera no último Render (debug)
+	ChunksDrawn  int
+	ChunksCulled int
+
+	// Blocos dos chunks editados que foram descarregados; usados no lugar da geração
+	// procedural quando o chunk volta a ser carregado
+	editedChunks map[int64]*savedChunk
 }
 
 // NewChunkManager cria um novo gerenciador de chunks
@@ -25,11 +51,18 @@ func NewChunkManager(renderDistance int32) *ChunkManager {
 		UnloadDistance:      renderDistance + 2, // Descarrega um pouco além da distância de renderização
 		UpdateCooldown:      0,
 		UpdateCooldownLimit: 0.05, // Atualizar chunks no máximo a cada 0.05 segundos (20 vezes por segundo)
+		editedChunks:        make(map[int64]*savedChunk),
 	}
 }
 
+// SetRenderDistance altera o raio de chunks carregados, mantendo a folga de descarregamento
+func (cm *ChunkManager) SetRenderDistance(renderDistance int32) {
+	cm.RenderDistance = renderDistance
+	cm.UnloadDistance = renderDistance + 2
+}
+
 // Update atualiza os chunks baseado na posição do jogador
-func (cm *ChunkManager) Update(playerPos rl.Vector3, dt float32, terrainGen *TerrainGenerator) {
+func (cm *ChunkManager) Update(playerPos rl.Vector3, dt float32, terrainGen TerrainGenerator) {
 	// Incrementar cooldown
 	cm.UpdateCooldown += dt
 
@@ -51,7 +84,7 @@ func (cm *ChunkManager) Update(playerPos rl.Vector3, dt float32, terrainGen *Ter
 }
 
 // LoadChunksAroundPlayer carrega chunks ao redor do jogador
-func (cm *ChunkManager) LoadChunksAroundPlayer(playerPos rl.Vector3, terrainGen *TerrainGenerator) {
+func (cm *ChunkManager) LoadChunksAroundPlayer(playerPos rl.Vector3, terrainGen TerrainGenerator) {
 	playerChunk := GetChunkCoordFromFloat(playerPos.X, playerPos.Y, playerPos.Z)
 
 	// Limitar o número de chunks carregados por frame para evitar lag
@@ -77,6 +110,8 @@ func (cm *ChunkManager) LoadChunksAroundPlayer(playerPos rl.Vector3, terrainGen
 						// Se o chunk não existe, criar e gerar
 						if _, exists := cm.Chunks[key]; !exists {
 							chunk := NewChunk(x, y, z)
+							chunk.AmbientOcclusion = cm.AmbientOcclusion
+							chunk.GreedyMeshing = cm.GreedyMeshing
 
 							// Usar o novo gerador de terreno se fornecido
 							if terrainGen != nil {
@@ -85,6 +120,12 @@ func (cm *ChunkManager) LoadChunksAroundPlayer(playerPos rl.Vector3, terrainGen
 								chunk.GenerateTerrain()
 							}
 
+							// Edições do jogador substituem o terreno gerado
+							if edited, ok := cm.editedChunks[key]; ok {
+								edited.restore(chunk)
+								delete(cm.editedChunks, key)
+							}
+
 							cm.Chunks[key] = chunk
 
 							// Marcar que novos chunks foram carregados
@@ -126,8 +167,13 @@ func (cm *ChunkManager) UnloadDistantChunks(playerPos rl.Vector3) {
 		}
 	}
 
-	// Remover chunks marcados
+	// Remover chunks marcados (guardando os blocos dos editados) e liberar a GPU na hora
 	for _, key := range toRemove {
+		chunk := cm.Chunks[key]
+		if chunk.Modified {
+			cm.editedChunks[key] = newSavedChunk(chunk)
+		}
+		chunk.Unload()
 		delete(cm.Chunks, key)
 	}
 }
@@ -153,6 +199,20 @@ func (cm *ChunkManager) GetBlock(x, y, z int32) BlockType {
 	return chunk.GetBlock(localX, localY, localZ)
 }
 
+// GetBlockRotation retorna a rotação do bloco nas coordenadas mundiais
+func (cm *ChunkManager) GetBlockRotation(x, y, z int32) BlockRotation {
+	chunk, exists := cm.Chunks[GetChunkCoord(x, y, z).Key()]
+	if !exists {
+		return Rotation0
+	}
+
+	localX := ((x % ChunkSize) + ChunkSize) % ChunkSize
+	localY := ((y % ChunkHeight) + ChunkHeight) % ChunkHeight
+	localZ := ((z % ChunkSize) + ChunkSize) % ChunkSize
+
+	return chunk.GetRotation(localX, localY, localZ)
+}
+
 // IsBlockHidden verifica se um bloco nas coordenadas mundiais está completamente cercado
 func (cm *ChunkManager) IsBlockHidden(x, y, z int32) bool {
 	// Verificar todas as 6 direções
@@ -179,8 +239,13 @@ func (cm *ChunkManager) IsBlockHidden(x, y, z int32) bool {
 	return true
 }
 
-// SetBlock define o tipo de bloco nas coordenadas mundiais
+// SetBlock define o tipo de bloco nas coordenadas mundiais, na orientação padrão
 func (cm *ChunkManager) SetBlock(x, y, z int32, block BlockType) {
+	cm.SetBlockWithRotation(x, y, z, block, Rotation0)
+}
+
+// SetBlockWithRotation define o tipo e a rotação do bloco nas coordenadas mundiais
+func (cm *ChunkManager) SetBlockWithRotation(x, y, z int32, block BlockType, rotation BlockRotation) {
 	// Obter coordenadas do chunk
 	chunkCoord := GetChunkCoord(x, y, z)
 	key := chunkCoord.Key()
@@ -190,7 +255,13 @@ func (cm *ChunkManager) SetBlock(x, y, z int32, block BlockType) {
 	if !exists {
 		// Se não existe, criar o chunk
 		chunk = NewChunk(chunkCoord.X, chunkCoord.Y, chunkCoord.Z)
+		chunk.AmbientOcclusion = cm.AmbientOcclusion
+		chunk.GreedyMeshing = cm.GreedyMeshing
 		chunk.GenerateTerrain()
+		if edited, ok := cm.editedChunks[key]; ok {
+			edited.restore(chunk)
+			delete(cm.editedChunks, key)
+		}
 		cm.Chunks[key] = chunk
 	}
 
@@ -200,33 +271,77 @@ func (cm *ChunkManager) SetBlock(x, y, z int32, block BlockType) {
 	localY := ((y % ChunkHeight) + ChunkHeight) % ChunkHeight
 	localZ := ((z % ChunkSize) + ChunkSize) % ChunkSize
 
-	chunk.SetBlock(localX, localY, localZ, block)
+	chunk.SetBlockWithRotation(localX, localY, localZ, block, rotation)
+	chunk.Modified = true
 
 	// Se o bloco modificado está na borda do chunk, marcar chunks vizinhos para atualização
 	// Isso garante que faces que antes estavam ocultas agora apareçam
-	if localX == 0 {
-		// Borda X- -> marcar chunk à esquerda
-		cm.MarkChunkForUpdate(ChunkCoord{X: chunkCoord.X - 1, Y: chunkCoord.Y, Z: chunkCoord.Z})
+	cm.markChunksAroundBlock(x, y, z, chunkCoord)
+}
+
+// markChunksAroundBlock marca para atualização os outros chunks que leem o bloco (x, y, z) ao
+// construir a mesh: os que encostam nele por uma face e, com a oclusão ambiente ligada, também
+// os das arestas e cantos (a AO das faces vizinhas olha os blocos na diagonal)
+func (cm *ChunkManager) markChunksAroundBlock(x, y, z int32, own ChunkCoord) {
+	for dx := int32(-1); dx <= 1; dx++ {
+		for dy := int32(-1); dy <= 1; dy++ {
+			for dz := int32(-1); dz <= 1; dz++ {
+				if dx*dx+dy*dy+dz*dz != 1 && !cm.AmbientOcclusion {
+					continue
+				}
+				if coord := GetChunkCoord(x+dx, y+dy, z+dz); coord != own {
+					cm.MarkChunkForUpdate(coord)
+				}
+			}
+		}
+	}
+}
+
+// SetAmbientOcclusion liga ou desliga a oclusão ambiente e marca os chunks carregados para
+// reconstruir a mesh com a nova configuração
+func (cm *ChunkManager) SetAmbientOcclusion(enabled bool) {
+	if cm.AmbientOcclusion == enabled {
+		return
+	}
+	cm.AmbientOcclusion = enabled
+
+	for _, chunk := range cm.Chunks {
+		chunk.AmbientOcclusion = enabled
+		chunk.NeedUpdateMeshes = true
 	}
-	if localX == ChunkSize-1 {
-		// Borda X+ -> marcar chunk à direita
-		cm.MarkChunkForUpdate(ChunkCoord{X: chunkCoord.X + 1, Y: chunkCoord.Y, Z: chunkCoord.Z})
+}
+
+// SetGreedyMeshing liga ou desliga o greedy meshing e marca os chunks carregados para
+// reconstruir a mesh com a nova configuração
+func (cm *ChunkManager) SetGreedyMeshing(enabled bool) {
+	if cm.GreedyMeshing == enabled {
+		return
 	}
-	if localY == 0 {
-		// Borda Y- -> marcar chunk abaixo
-		cm.MarkChunkForUpdate(ChunkCoord{X: chunkCoord.X, Y: chunkCoord.Y - 1, Z: chunkCoord.Z})
+	cm.GreedyMeshing = enabled
+
+	for _, chunk := range cm.Chunks {
+		chunk.GreedyMeshing = enabled
+		chunk.NeedUpdateMeshes = true
 	}
-	if localY == ChunkHeight-1 {
-		// Borda Y+ -> marcar chunk acima
-		cm.MarkChunkForUpdate(ChunkCoord{X: chunkCoord.X, Y: chunkCoord.Y + 1, Z: chunkCoord.Z})
+}
+
+// SetBlockTransparency liga ou desliga a transparência de um tipo de bloco e marca os chunks
+// carregados para reconstruir a mesh com as novas faces. Blocos sem opacidade definida ficam
+// com DefaultTransparentAlpha.
+func (cm *ChunkManager) SetBlockTransparency(blockType BlockType, transparent bool) {
+	def := GetBlockDefinition(blockType)
+	if def.Transparent == transparent {
+		return
 	}
-	if localZ == 0 {
-		// Borda Z- -> marcar chunk atrás
-		cm.MarkChunkForUpdate(ChunkCoord{X: chunkCoord.X, Y: chunkCoord.Y, Z: chunkCoord.Z - 1})
+
+	def.Transparent = transparent
+	if transparent && def.Alpha == 255 {
+		def.Alpha = DefaultTransparentAlpha
 	}
-	if localZ == ChunkSize-1 {
-		// Borda Z+ -> marcar chunk à frente
-		cm.MarkChunkForUpdate(ChunkCoord{X: chunkCoord.X, Y: chunkCoord.Y, Z: chunkCoord.Z + 1})
+	BlockDefinitions[blockType] = def
+
+	for _, chunk := range cm.Chunks {
+		chunk.NeedUpdateMeshes = true
 	}
 }
 
@@ -242,10 +357,17 @@ func (cm *ChunkManager) MarkChunkForUpdate(coord ChunkCoord) {
 func (cm *ChunkManager) UpdatePendingMeshes(maxMeshUpdatesPerFrame int, atlas *DynamicAtlasManager) int {
 	meshesUpdated := 0
 
+	// Meshes em cache geradas com outro atlas são descartadas
+	if cm.MeshCache != nil && atlas != nil {
+		if err := cm.MeshCache.SetAtlasVersion(atlas.GetVersion()); err != nil {
+			fmt.Printf("AVISO: Erro ao validar cache de meshes: %v\n", err)
+		}
+	}
+
 	// Atualizar meshes com limite para evitar FPS drops
 	for _, chunk := range cm.Chunks {
 		if chunk.NeedUpdateMeshes {
-			chunk.UpdateMeshesWithNeighbors(cm.GetBlock, atlas)
+			chunk.UpdateMeshesWithCache(cm.GetBlock, atlas, cm.MeshCache)
 			meshesUpdated++
 
 			// Limitar atualizações por frame
@@ -258,8 +380,10 @@ func (cm *ChunkManager) UpdatePendingMeshes(maxMeshUpdatesPerFrame int, atlas *D
 	return meshesUpdated
 }
 
-// Render renderiza todos os chunks carregados usando atlas por chunk
-func (cm *ChunkManager) Render(grassMesh, dirtMesh, stoneMesh rl.Mesh, material rl.Material, playerPos rl.Vector3, visibleBlocks *VisibleBlocksTracker, atlas *DynamicAtlasManager) {
+// Render renderiza todos os chunks carregados usando atlas por chunk. Com frustum, chunks
+// inteiramente fora da visão da câmera não são desenhados (nil = desenha todos). Os blocos
+// transparentes são desenhados numa segunda passada, depois de toda a geometria opaca.
+func (cm *ChunkManager) Render(grassMesh, dirtMesh, stoneMesh rl.Mesh, material rl.Material, playerPos rl.Vector3, visibleBlocks *VisibleBlocksTracker, atlas *DynamicAtlasManager, frustum *Frustum) {
 	// Atualizar meshes pendentes (máximo 3 por frame)
 	const maxMeshUpdatesPerFrame = 3
 	cm.UpdatePendingMeshes(maxMeshUpdatesPerFrame, atlas)
@@ -267,6 +391,17 @@ func (cm *ChunkManager) Render(grassMesh, dirtMesh, stoneMesh rl.Mesh, material
 	// Renderizar apenas chunks próximos ao jogador
 	playerChunk := GetChunkCoordFromFloat(playerPos.X, playerPos.Y, playerPos.Z)
 
+	light := cm.Light
+	if light == (DayLight{}) {
+		light = fullDayLight(cm.Fog.Color)
+	}
+	fog := cm.Fog
+	fog.Color = light.Sky
+	applyFogUniforms(fog, playerPos)
+	applyLightUniforms(light)
+
+	cm.ChunksDrawn, cm.ChunksCulled = 0, 0
+	transparent := make([]*Chunk, 0)
 	for _, chunk := range cm.Chunks {
 		dx := float32(chunk.Coord.X - playerChunk.X)
 		dy := float32(chunk.Coord.Y - playerChunk.Y)
@@ -274,12 +409,57 @@ func (cm *ChunkManager) Render(grassMesh, dirtMesh, stoneMesh rl.Mesh, material
 		distSq := dx*dx + dy*dy + dz*dz
 
 		if distSq <= float32(cm.RenderDistance*cm.RenderDistance) {
-			if chunk.ChunkMesh.Uploaded && chunk.ChunkAtlas.IsUploaded {
+			if (chunk.ChunkMesh.Uploaded || chunk.TransparentMesh.Uploaded) && chunk.ChunkAtlas.IsUploaded {
+				if frustum != nil && !frustum.IntersectsAABB(ChunkBounds(chunk.Coord)) {
+					cm.ChunksCulled++
+					continue
+				}
+				cm.ChunksDrawn++
+
+				if chunk.TransparentMesh.Uploaded {
+					transparent = append(transparent, chunk)
+				}
+				if !chunk.ChunkMesh.Uploaded {
+					continue
+				}
+
 				// Usar o material específico do chunk (com seu próprio atlas)
+				chunk.ChunkAtlas.PrepareDraw()
 				rl.DrawMesh(chunk.ChunkMesh.Mesh, chunk.ChunkAtlas.Material, rl.MatrixIdentity())
 			}
 		}
 	}
+
+	if len(transparent) == 0 {
+		return
+	}
+
+	// Segunda passada: do chunk mais distante para o mais próximo, com blending e sem escrever
+	// no depth buffer (a geometria opaca atrás continua visível através dos blocos)
+	sortChunksBackToFront(transparent, playerPos)
+	rl.BeginBlendMode(rl.BlendAlpha)
+	rl.DisableDepthMask()
+	for _, chunk := range transparent {
+		chunk.ChunkAtlas.PrepareDraw()
+		rl.DrawMesh(chunk.TransparentMesh.Mesh, chunk.ChunkAtlas.Material, rl.MatrixIdentity())
+	}
+	rl.EnableDepthMask()
+	rl.EndBlendMode()
+}
+
+// sortChunksBackToFront ordena os chunks pela distância do centro até a posição, do mais
+// distante para o mais próximo
+func sortChunksBackToFront(chunks []*Chunk, pos rl.Vector3) {
+	distSq := func(chunk *Chunk) float32 {
+		min, max := ChunkBounds(chunk.Coord)
+		dx := (min.X+max.X)/2 - pos.X
+		dy := (min.Y+max.Y)/2 - pos.Y
+		dz := (min.Z+max.Z)/2 - pos.Z
+		return dx*dx + dy*dy + dz*dz
+	}
+	sort.Slice(chunks, func(i, j int) bool {
+		return distSq(chunks[i]) > distSq(chunks[j])
+	})
 }
 
 // GetTotalBlocks retorna o número total de faces RENDERIZADAS (para debug)
@@ -290,6 +470,9 @@ func (cm *ChunkManager) GetTotalBlocks() int {
 			// Cada quad (face) tem 2 triângulos
 			total += int(chunk.ChunkMesh.Mesh.TriangleCount / 2)
 		}
+		if chunk.TransparentMesh != nil && chunk.TransparentMesh.Uploaded {
+			total += int(chunk.TransparentMesh.Mesh.TriangleCount / 2)
+		}
 	}
 	return total
 }
@@ -301,8 +484,22 @@ func (cm *ChunkManager) GetLoadedChunksCount() int {
 
 // MarkNeighborsForUpdate marca os chunks vizinhos para atualização de meshes
 // Deve ser chamado quando um novo chunk é criado para que os vizinhos
-// recalculem suas faces considerando o novo chunk
+// recalculem suas faces considerando o novo chunk. Com a oclusão ambiente ligada, os vizinhos
+// pelas arestas e cantos também são marcados: a AO deles lê os blocos do novo chunk na diagonal.
 func (cm *ChunkManager) MarkNeighborsForUpdate(coord ChunkCoord) {
+	if cm.AmbientOcclusion {
+		for dx := int32(-1); dx <= 1; dx++ {
+			for dy := int32(-1); dy <= 1; dy++ {
+				for dz := int32(-1); dz <= 1; dz++ {
+					if dx != 0 || dy != 0 || dz != 0 {
+						cm.MarkChunkForUpdate(ChunkCoord{X: coord.X + dx, Y: coord.Y + dy, Z: coord.Z + dz})
+					}
+				}
+			}
+		}
+		return
+	}
+
 	// Verificar os 6 vizinhos diretos (faces adjacentes)
 	neighbors := []ChunkCoord{
 		{X: coord.X + 1, Y: coord.Y, Z: coord.Z}, // X+
@@ -10,12 +10,14 @@ var DisableGPUUploadForTesting = false
 
 // ChunkMesh representa uma mesh customizada para um chunk
 type ChunkMesh struct {
-	Vertices  []float32
-	Texcoords []float32
-	Normals   []float32
-	Indices   []uint16
-	Mesh      rl.Mesh
-	Uploaded  bool
+	Vertices   []float32
+	Texcoords  []float32
+	Texcoords2 []float32 // Slot do atlas por vértice (só em quads repetidos; ver AddTiledQuad)
+	Normals    []float32
+	Indices    []uint16
+	Colors     []uint8 // RGBA por vértice (sombreamento de oclusão ambiente; branco = sem sombra)
+	Mesh       rl.Mesh
+	Uploaded   bool
 }
 
 // NewChunkMesh cria uma nova mesh vazia para um chunk
@@ -25,6 +27,7 @@ func NewChunkMesh() *ChunkMesh {
 		Texcoords: make([]float32, 0, 10000),
 		Normals:   make([]float32, 0, 10000),
 		Indices:   make([]uint16, 0, 10000),
+		Colors:    make([]uint8, 0, 10000),
 		Uploaded:  false,
 	}
 }
@@ -135,6 +138,9 @@ func (cm *ChunkMesh) AddQuad(x, y, z float32, face int, blockType BlockType, atl
 		uMax, vMax, // 3
 	)
 
+	// Cores (sem sombreamento)
+	cm.Colors = append(cm.Colors, quadWhite[:]...)
+
 	// Índices (2 triângulos por quad)
 	cm.Indices = append(cm.Indices,
 		vertexOffset+0, vertexOffset+1, vertexOffset+2,
@@ -244,6 +250,9 @@ func (cm *ChunkMesh) AddQuadWithChunkAtlas(x, y, z float32, face int, blockType
 		uMax, vMax,
 	)
 
+	// Cores (sem sombreamento; ver ShadeLastQuad)
+	cm.Colors = append(cm.Colors, quadWhite[:]...)
+
 	// Índices
 	cm.Indices = append(cm.Indices,
 		vertexOffset+0, vertexOffset+1, vertexOffset+2,
@@ -251,6 +260,98 @@ func (cm *ChunkMesh) AddQuadWithChunkAtlas(x, y, z float32, face int, blockType
 	)
 }
 
+// quadWhite cores RGBA dos 4 vértices de um quad sem sombreamento
+var quadWhite = [16]uint8{
+	255, 255, 255, 255,
+	255, 255, 255, 255,
+	255, 255, 255, 255,
+	255, 255, 255, 255,
+}
+
+// ShadeLastQuad define o brilho (0-255) de cada vértice do último quad adicionado
+func (cm *ChunkMesh) ShadeLastQuad(brightness [4]uint8) {
+	colors := cm.Colors[len(cm.Colors)-16:]
+	for i, b := range brightness {
+		colors[i*4] = b
+		colors[i*4+1] = b
+		colors[i*4+2] = b
+	}
+}
+
+// SetLastQuadAlpha define a opacidade (0-255) dos 4 vértices do último quad adicionado
+func (cm *ChunkMesh) SetLastQuadAlpha(alpha uint8) {
+	colors := cm.Colors[len(cm.Colors)-16:]
+	for i := 0; i < 4; i++ {
+		colors[i*4+3] = alpha
+	}
+}
+
+// faceCorners cantos (deslocamentos 0/1 em x, y, z) dos 4 vértices de cada face, na mesma
+// ordem de AddQuadWithChunkAtlas
+var faceCorners = [6][4][3]float32{
+	{{1, 0, 0}, {1, 1, 0}, {1, 1, 1}, {1, 0, 1}}, // 0: +X
+	{{0, 0, 1}, {0, 1, 1}, {0, 1, 0}, {0, 0, 0}}, // 1: -X
+	{{0, 1, 0}, {0, 1, 1}, {1, 1, 1}, {1, 1, 0}}, // 2: +Y
+	{{0, 0, 1}, {0, 0, 0}, {1, 0, 0}, {1, 0, 1}}, // 3: -Y
+	{{1, 0, 1}, {1, 1, 1}, {0, 1, 1}, {0, 0, 1}}, // 4: +Z
+	{{0, 0, 0}, {0, 1, 0}, {1, 1, 0}, {1, 0, 0}}, // 5: -Z
+}
+
+// faceNormals normal de cada face
+var faceNormals = [6][3]float32{
+	{1, 0, 0}, {-1, 0, 0}, {0, 1, 0}, {0, -1, 0}, {0, 0, 1}, {0, 0, -1},
+}
+
+// faceTextureAxes eixos do mundo (0 = x, 1 = y, 2 = z) ao longo dos quais correm o u e o v da
+// textura em cada face
+var faceTextureAxes = [6][2]int{
+	{2, 1}, {2, 1}, {0, 2}, {0, 2}, {0, 1}, {0, 1},
+}
+
+// AddTiledQuad adiciona a face de um retângulo de blocos iguais: a partir do bloco (x, y, z),
+// size blocos em cada eixo (o da normal é ignorado). A textura se repete uma vez por bloco: as
+// UVs vão em unidades de bloco e Texcoords2 leva o slot do bloco no atlas do chunk, que o
+// shader de quads repetidos (ver ChunkAtlas.Tiled) converte em UV do atlas.
+func (cm *ChunkMesh) AddTiledQuad(x, y, z float32, face int, size [3]float32, blockType BlockType, chunkAtlas *ChunkAtlas) {
+	vertexOffset := uint16(len(cm.Vertices) / 3)
+	normal := faceNormals[face]
+	slot := float32(chunkAtlas.UsedBlocks[blockType])
+
+	// Extensão da textura (em blocos) ao longo de u e v
+	uAxis, vAxis := faceTextureAxes[face][0], faceTextureAxes[face][1]
+	uSize, vSize := size[uAxis], size[vAxis]
+
+	for i, corner := range faceCorners[face] {
+		for axis := 0; axis < 3; axis++ {
+			if normal[axis] == 0 {
+				corner[axis] *= size[axis]
+			}
+		}
+		cm.Vertices = append(cm.Vertices, x+corner[0], y+corner[1], z+corner[2])
+		cm.Normals = append(cm.Normals, normal[0], normal[1], normal[2])
+		cm.Texcoords2 = append(cm.Texcoords2, slot, 0)
+
+		// Mesma orientação de AddQuadWithChunkAtlas: (uMin, vMax), (uMin, vMin), (uMax, vMin), (uMax, vMax)
+		switch i {
+		case 0:
+			cm.Texcoords = append(cm.Texcoords, 0, vSize)
+		case 1:
+			cm.Texcoords = append(cm.Texcoords, 0, 0)
+		case 2:
+			cm.Texcoords = append(cm.Texcoords, uSize, 0)
+		case 3:
+			cm.Texcoords = append(cm.Texcoords, uSize, vSize)
+		}
+	}
+
+	cm.Colors = append(cm.Colors, quadWhite[:]...)
+
+	cm.Indices = append(cm.Indices,
+		vertexOffset+0, vertexOffset+1, vertexOffset+2,
+		vertexOffset+0, vertexOffset+2, vertexOffset+3,
+	)
+}
+
 // UploadToGPU faz upload da mesh para a GPU
 func (cm *ChunkMesh) UploadToGPU() {
 	if len(cm.Vertices) == 0 {
@@ -271,6 +372,10 @@ func (cm *ChunkMesh) UploadToGPU() {
 	cm.Mesh.Vertices = &cm.Vertices[0]
 	cm.Mesh.Texcoords = &cm.Texcoords[0]
 	cm.Mesh.Normals = &cm.Normals[0]
+	cm.Mesh.Colors = &cm.Colors[0]
+	if len(cm.Texcoords2) > 0 {
+		cm.Mesh.Texcoords2 = &cm.Texcoords2[0]
+	}
 	cm.Mesh.Indices = (*uint16)(nil)
 	if len(cm.Indices) > 0 {
 		cm.Mesh.Indices = &cm.Indices[0]
@@ -285,8 +390,10 @@ func (cm *ChunkMesh) UploadToGPU() {
 func (cm *ChunkMesh) Clear() {
 	cm.Vertices = cm.Vertices[:0]
 	cm.Texcoords = cm.Texcoords[:0]
+	cm.Texcoords2 = cm.Texcoords2[:0]
 	cm.Normals = cm.Normals[:0]
 	cm.Indices = cm.Indices[:0]
+	cm.Colors = cm.Colors[:0]
 
 	if cm.Uploaded {
 		rl.UnloadMesh(&cm.Mesh)
@@ -0,0 +1,280 @@
+package game
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// MeshCacheDir é o diretório padrão onde as meshes dos chunks são guardadas entre sessões
+const MeshCacheDir = "mesh_cache"
+
+// Arquivo que registra a versão do atlas usada pelas meshes em cache
+const meshCacheVersionFile = "atlas_version"
+
+// ChunkMeshCache guarda em disco as meshes (vértices, UVs, normais e índices) já construídas,
+// evitando reconstruir chunks que não mudaram desde a última vez que foram carregados.
+// Cada chunk tem um arquivo; a chave gravada nele é o hash dos blocos do chunk (e da borda dos
+// vizinhos, que define as faces expostas) junto com a versão do atlas.
+type ChunkMeshCache struct {
+	Dir          string
+	AtlasVersion string
+
+	// Estatísticas
+	Hits   int
+	Misses int
+}
+
+// cachedChunkMesh é o conteúdo serializado de uma mesh em cache
+type cachedChunkMesh struct {
+	Key        [sha256.Size]byte
+	Vertices   []float32
+	Texcoords  []float32
+	Texcoords2 []float32
+	Normals    []float32
+	Indices    []uint16
+	Colors     []uint8
+	UsedBlocks map[BlockType]int32
+
+	// Faces dos blocos transparentes (ver Chunk.TransparentMesh)
+	Transparent cachedMeshBuffers
+}
+
+// cachedMeshBuffers buffers de uma mesh secundária do chunk
+type cachedMeshBuffers struct {
+	Vertices   []float32
+	Texcoords  []float32
+	Texcoords2 []float32
+	Normals    []float32
+	Indices    []uint16
+	Colors     []uint8
+}
+
+// newCachedMeshBuffers copia as referências dos buffers da mesh para serialização
+func newCachedMeshBuffers(mesh *ChunkMesh) cachedMeshBuffers {
+	return cachedMeshBuffers{
+		Vertices:   mesh.Vertices,
+		Texcoords:  mesh.Texcoords,
+		Texcoords2: mesh.Texcoords2,
+		Normals:    mesh.Normals,
+		Indices:    mesh.Indices,
+		Colors:     mesh.Colors,
+	}
+}
+
+// appendTo acrescenta os buffers em cache à mesh
+func (b cachedMeshBuffers) appendTo(mesh *ChunkMesh) {
+	mesh.Vertices = append(mesh.Vertices, b.Vertices...)
+	mesh.Texcoords = append(mesh.Texcoords, b.Texcoords...)
+	mesh.Texcoords2 = append(mesh.Texcoords2, b.Texcoords2...)
+	mesh.Normals = append(mesh.Normals, b.Normals...)
+	mesh.Indices = append(mesh.Indices, b.Indices...)
+	mesh.Colors = append(mesh.Colors, b.Colors...)
+}
+
+// NewChunkMeshCache cria um cache de meshes no diretório informado
+func NewChunkMeshCache(dir string) *ChunkMeshCache {
+	return &ChunkMeshCache{Dir: dir}
+}
+
+// SetAtlasVersion define a versão do atlas. Se for diferente da versão com que as meshes em
+// disco foram geradas, o cache é descartado (as UVs gravadas não valem para o novo atlas).
+func (mc *ChunkMeshCache) SetAtlasVersion(version string) error {
+	if version == mc.AtlasVersion && mc.AtlasVersion != "" {
+		return nil
+	}
+	mc.AtlasVersion = version
+
+	versionPath := filepath.Join(mc.Dir, meshCacheVersionFile)
+	stored, err := os.ReadFile(versionPath)
+	if err == nil && string(stored) == version {
+		return nil
+	}
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to read mesh cache version: %w", err)
+	}
+
+	if err := mc.Clear(); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(mc.Dir, 0755); err != nil {
+		return fmt.Errorf("failed to create mesh cache dir: %w", err)
+	}
+	if err := os.WriteFile(versionPath, []byte(version), 0644); err != nil {
+		return fmt.Errorf("failed to write mesh cache version: %w", err)
+	}
+
+	return nil
+}
+
+// Clear remove todas as meshes em cache
+func (mc *ChunkMeshCache) Clear() error {
+	entries, err := os.ReadDir(mc.Dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to list mesh cache: %w", err)
+	}
+
+	for _, entry := range entries {
+		if !strings.HasSuffix(entry.Name(), ".mesh") {
+			continue
+		}
+		if err := os.Remove(filepath.Join(mc.Dir, entry.Name())); err != nil {
+			return fmt.Errorf("failed to remove cached mesh: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Key calcula a chave do chunk: blocos do chunk e suas rotações, borda dos chunks vizinhos, tamanho do
+// grid do atlas do chunk (define as UVs), versão do atlas, se a oclusão ambiente está ativa
+// (com ela, as arestas e cantos dos vizinhos também influenciam a mesh), se a mesh é gulosa e
+// quais blocos são transparentes
+func (mc *ChunkMeshCache) Key(c *Chunk, getBlockFunc func(x, y, z int32) BlockType) [sha256.Size]byte {
+	h := sha256.New()
+	buf := make([]byte, 4)
+
+	writeBlock := func(block BlockType) {
+		binary.LittleEndian.PutUint32(buf, uint32(block))
+		h.Write(buf)
+	}
+
+	for x := int32(0); x < ChunkSize; x++ {
+		for y := int32(0); y < ChunkHeight; y++ {
+			for z := int32(0); z < ChunkSize; z++ {
+				writeBlock(c.Blocks[x][y][z])
+				h.Write([]byte{byte(c.Rotations[x][y][z])})
+			}
+		}
+	}
+
+	// Borda dos vizinhos: um bloco além de cada face do chunk
+	worldX := c.Coord.X * ChunkSize
+	worldY := c.Coord.Y * ChunkHeight
+	worldZ := c.Coord.Z * ChunkSize
+	for a := int32(0); a < ChunkSize; a++ {
+		for b := int32(0); b < ChunkHeight; b++ {
+			writeBlock(getBlockFunc(worldX-1, worldY+b, worldZ+a))
+			writeBlock(getBlockFunc(worldX+ChunkSize, worldY+b, worldZ+a))
+			writeBlock(getBlockFunc(worldX+a, worldY+b, worldZ-1))
+			writeBlock(getBlockFunc(worldX+a, worldY+b, worldZ+ChunkSize))
+		}
+		for b := int32(0); b < ChunkSize; b++ {
+			writeBlock(getBlockFunc(worldX+a, worldY-1, worldZ+b))
+			writeBlock(getBlockFunc(worldX+a, worldY+ChunkHeight, worldZ+b))
+		}
+	}
+
+	if c.AmbientOcclusion {
+		// Arestas e cantos: blocos fora do chunk em dois ou três eixos ao mesmo tempo
+		for x := int32(-1); x <= ChunkSize; x++ {
+			for y := int32(-1); y <= ChunkHeight; y++ {
+				for z := int32(-1); z <= ChunkSize; z++ {
+					outside := 0
+					if x < 0 || x == ChunkSize {
+						outside++
+					}
+					if y < 0 || y == ChunkHeight {
+						outside++
+					}
+					if z < 0 || z == ChunkSize {
+						outside++
+					}
+					if outside >= 2 {
+						writeBlock(getBlockFunc(worldX+x, worldY+y, worldZ+z))
+					}
+				}
+			}
+		}
+		// 2: versão da AO em que blocos transparentes não ocluem (invalida as meshes gravadas antes)
+		h.Write([]byte{2})
+	} else {
+		h.Write([]byte{0})
+	}
+
+	// Meshes gulosas têm outra geometria e UVs (só entra na chave com ela ligada, mantendo as
+	// chaves já gravadas sem ela)
+	if c.GreedyMeshing {
+		h.Write([]byte("greedy"))
+	}
+
+	h.Write(blockDefinitionsKey())
+
+	binary.LittleEndian.PutUint32(buf, uint32(c.ChunkAtlas.GridSize))
+	h.Write(buf)
+	h.Write([]byte(mc.AtlasVersion))
+
+	var key [sha256.Size]byte
+	copy(key[:], h.Sum(nil))
+	return key
+}
+
+// path retorna o arquivo de cache de um chunk
+func (mc *ChunkMeshCache) path(coord ChunkCoord) string {
+	return filepath.Join(mc.Dir, fmt.Sprintf("chunk_%d_%d_%d.mesh", coord.X, coord.Y, coord.Z))
+}
+
+// Load carrega a mesh em cache do chunk se ela foi gerada com a mesma chave.
+// Retorna false se não houver cache válido (arquivo ausente, corrompido ou chave diferente).
+func (mc *ChunkMeshCache) Load(coord ChunkCoord, key [sha256.Size]byte) (*cachedChunkMesh, bool) {
+	data, err := os.ReadFile(mc.path(coord))
+	if err != nil {
+		mc.Misses++
+		return nil, false
+	}
+
+	var cached cachedChunkMesh
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&cached); err != nil || cached.Key != key {
+		mc.Misses++
+		return nil, false
+	}
+
+	mc.Hits++
+	return &cached, true
+}
+
+// Store grava a mesh atual do chunk no cache com a chave informada
+func (mc *ChunkMeshCache) Store(c *Chunk, key [sha256.Size]byte) error {
+	cached := cachedChunkMesh{
+		Key:        key,
+		Vertices:   c.ChunkMesh.Vertices,
+		Texcoords:  c.ChunkMesh.Texcoords,
+		Texcoords2: c.ChunkMesh.Texcoords2,
+		Normals:    c.ChunkMesh.Normals,
+		Indices:    c.ChunkMesh.Indices,
+		Colors:     c.ChunkMesh.Colors,
+		UsedBlocks: c.ChunkAtlas.UsedBlocks,
+
+		Transparent: newCachedMeshBuffers(c.TransparentMesh),
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(&cached); err != nil {
+		return fmt.Errorf("failed to encode chunk mesh: %w", err)
+	}
+
+	if err := os.MkdirAll(mc.Dir, 0755); err != nil {
+		return fmt.Errorf("failed to create mesh cache dir: %w", err)
+	}
+
+	// Escreve em arquivo temporário e renomeia para não deixar cache pela metade
+	path := mc.path(c.Coord)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write chunk mesh: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write chunk mesh: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,122 @@
+package game
+
+import (
+	"reflect"
+	"testing"
+)
+
+// Helper: chunk com um pequeno degrau de blocos, como se tivesse acabado de ser carregado
+func newCachedTestChunk() *Chunk {
+	chunk := NewChunk(0, 0, 0)
+	for x := int32(0); x < 4; x++ {
+		for z := int32(0); z < 4; z++ {
+			chunk.SetBlock(x, 0, z, BlockStone)
+			chunk.SetBlock(x, 1, z, BlockDirt)
+		}
+	}
+	chunk.SetBlock(0, 2, 0, BlockGrass)
+	return chunk
+}
+
+// Helper: leitura de blocos sem vizinhos carregados (fora do chunk é ar)
+func chunkOnlyBlocks(c *Chunk) func(x, y, z int32) BlockType {
+	return func(x, y, z int32) BlockType {
+		return c.GetBlock(x-c.Coord.X*ChunkSize, y-c.Coord.Y*ChunkHeight, z-c.Coord.Z*ChunkSize)
+	}
+}
+
+func TestChunkMeshCache(t *testing.T) {
+	DisableGPUUploadForTesting = true
+	cache := NewChunkMeshCache(t.TempDir())
+	if err := cache.SetAtlasVersion("atlas-v1"); err != nil {
+		t.Fatalf("Failed to set atlas version: %v", err)
+	}
+
+	// Primeira carga: constrói e grava no cache
+	original := newCachedTestChunk()
+	if original.UpdateMeshesWithCache(chunkOnlyBlocks(original), nil, cache) {
+		t.Fatal("First load should build the mesh")
+	}
+	if len(original.ChunkMesh.Vertices) == 0 {
+		t.Fatal("Expected mesh to be built")
+	}
+
+	// Chunk inalterado recarregado: usa a mesh em cache
+	reloaded := newCachedTestChunk()
+	if !reloaded.UpdateMeshesWithCache(chunkOnlyBlocks(reloaded), nil, cache) {
+		t.Fatal("Unchanged chunk should load its cached mesh")
+	}
+	if !reflect.DeepEqual(reloaded.ChunkMesh.Vertices, original.ChunkMesh.Vertices) ||
+		!reflect.DeepEqual(reloaded.ChunkMesh.Texcoords, original.ChunkMesh.Texcoords) ||
+		!reflect.DeepEqual(reloaded.ChunkMesh.Indices, original.ChunkMesh.Indices) {
+		t.Error("Cached mesh should match the built mesh")
+	}
+	if !reflect.DeepEqual(reloaded.ChunkAtlas.UsedBlocks, original.ChunkAtlas.UsedBlocks) {
+		t.Error("Cached mesh should restore the chunk atlas blocks")
+	}
+	if reloaded.NeedUpdateMeshes {
+		t.Error("Chunk loaded from cache should not need a mesh update")
+	}
+
+	// Chunk modificado: reconstrói
+	modified := newCachedTestChunk()
+	modified.SetBlock(3, 2, 3, BlockGrass)
+	if modified.UpdateMeshesWithCache(chunkOnlyBlocks(modified), nil, cache) {
+		t.Fatal("Modified chunk should rebuild its mesh")
+	}
+	if len(modified.ChunkMesh.Vertices) <= len(original.ChunkMesh.Vertices) {
+		t.Error("Rebuilt mesh should include the new block")
+	}
+
+	if cache.Hits != 1 || cache.Misses != 2 {
+		t.Errorf("Expected 1 hit and 2 misses, got %d hits and %d misses", cache.Hits, cache.Misses)
+	}
+}
+
+func TestChunkMeshCacheInvalidatedOnAtlasChange(t *testing.T) {
+	DisableGPUUploadForTesting = true
+	dir := t.TempDir()
+
+	cache := NewChunkMeshCache(dir)
+	cache.SetAtlasVersion("atlas-v1")
+	chunk := newCachedTestChunk()
+	chunk.UpdateMeshesWithCache(chunkOnlyBlocks(chunk), nil, cache)
+
+	// Nova sessão com o mesmo atlas: cache válido
+	cache = NewChunkMeshCache(dir)
+	cache.SetAtlasVersion("atlas-v1")
+	chunk = newCachedTestChunk()
+	if !chunk.UpdateMeshesWithCache(chunkOnlyBlocks(chunk), nil, cache) {
+		t.Fatal("Cache should survive a new session with the same atlas")
+	}
+
+	// Atlas mudou: meshes gravadas são descartadas
+	if err := cache.SetAtlasVersion("atlas-v2"); err != nil {
+		t.Fatalf("Failed to set atlas version: %v", err)
+	}
+	chunk = newCachedTestChunk()
+	if chunk.UpdateMeshesWithCache(chunkOnlyBlocks(chunk), nil, cache) {
+		t.Error("Atlas change should invalidate cached meshes")
+	}
+}
+
+func TestChunkMeshCacheNeighborChange(t *testing.T) {
+	DisableGPUUploadForTesting = true
+	cache := NewChunkMeshCache(t.TempDir())
+	cache.SetAtlasVersion("atlas-v1")
+
+	chunk := newCachedTestChunk()
+	chunk.UpdateMeshesWithCache(chunkOnlyBlocks(chunk), nil, cache)
+
+	// Um bloco do chunk vizinho encostado na face -X esconde faces deste chunk
+	withNeighbor := func(x, y, z int32) BlockType {
+		if x == -1 && y == 0 && z == 0 {
+			return BlockStone
+		}
+		return chunkOnlyBlocks(chunk)(x, y, z)
+	}
+	reloaded := newCachedTestChunk()
+	if reloaded.UpdateMeshesWithCache(withNeighbor, nil, cache) {
+		t.Error("Neighbor change at the chunk border should rebuild the mesh")
+	}
+}
@@ -0,0 +1,141 @@
+package game
+
+import "testing"
+
+// Helper: número de quads (faces) da mesh
+func meshQuads(mesh *ChunkMesh) int {
+	return len(mesh.Indices) / 6
+}
+
+// Helper: chunk com os blocos informados em linha ao longo de X a partir de (5, 5, 5)
+func newTransparencyTestChunk(greedy bool, blocks ...BlockType) *Chunk {
+	chunk := NewChunk(0, 0, 0)
+	chunk.GreedyMeshing = greedy
+	for i, blockType := range blocks {
+		chunk.SetBlock(5+int32(i), 5, 5, blockType)
+	}
+	chunk.UpdateMeshesWithNeighbors(chunkOnlyBlocks(chunk), nil)
+	return chunk
+}
+
+func TestTransparentBlockFaces(t *testing.T) {
+	DisableGPUUploadForTesting = true
+
+	tests := []struct {
+		name        string
+		blocks      []BlockType
+		opaque      int
+		transparent int
+	}{
+		// Vidro cercado de ar: todas as faces, na mesh transparente
+		{"glass next to air", []BlockType{BlockGlass}, 0, 6},
+		// Dois vidros: a face entre eles não é desenhada
+		{"glass next to glass", []BlockType{BlockGlass, BlockGlass}, 0, 10},
+		// Vidro e água: transparentes diferentes, as duas faces internas aparecem
+		{"glass next to water", []BlockType{BlockGlass, BlockWater}, 0, 12},
+		// Pedra atrás do vidro: a face da pedra aparece, a do vidro contra a pedra não
+		{"stone next to glass", []BlockType{BlockStone, BlockGlass}, 6, 5},
+		// Dois blocos opacos continuam com a face interna oculta
+		{"stone next to stone", []BlockType{BlockStone, BlockStone}, 10, 0},
+	}
+
+	for _, tt := range tests {
+		chunk := newTransparencyTestChunk(false, tt.blocks...)
+		if got := meshQuads(chunk.ChunkMesh); got != tt.opaque {
+			t.Errorf("%s: expected %d opaque faces, got %d", tt.name, tt.opaque, got)
+		}
+		if got := meshQuads(chunk.TransparentMesh); got != tt.transparent {
+			t.Errorf("%s: expected %d transparent faces, got %d", tt.name, tt.transparent, got)
+		}
+	}
+}
+
+func TestTransparentBlockAlpha(t *testing.T) {
+	DisableGPUUploadForTesting = true
+
+	chunk := newTransparencyTestChunk(false, BlockStone, BlockGlass)
+	alpha := GetBlockDefinition(BlockGlass).Alpha
+	for i := 3; i < len(chunk.TransparentMesh.Colors); i += 4 {
+		if chunk.TransparentMesh.Colors[i] != alpha {
+			t.Fatalf("Expected glass vertices with alpha %d, got %d", alpha, chunk.TransparentMesh.Colors[i])
+		}
+	}
+	for i := 3; i < len(chunk.ChunkMesh.Colors); i += 4 {
+		if chunk.ChunkMesh.Colors[i] != 255 {
+			t.Fatalf("Expected opaque vertices, got alpha %d", chunk.ChunkMesh.Colors[i])
+		}
+	}
+}
+
+func TestTransparentBlockGreedyMeshing(t *testing.T) {
+	DisableGPUUploadForTesting = true
+
+	// Mesma regra de faces com greedy meshing
+	chunk := newTransparencyTestChunk(true, BlockStone, BlockGlass)
+	if got := meshQuads(chunk.ChunkMesh); got != 6 {
+		t.Errorf("Expected 6 opaque faces, got %d", got)
+	}
+	if got := meshQuads(chunk.TransparentMesh); got != 5 {
+		t.Errorf("Expected 5 transparent faces, got %d", got)
+	}
+
+	// Vidros em linha viram um quad por lado, sem faces internas
+	chunk = newTransparencyTestChunk(true, BlockGlass, BlockGlass, BlockGlass)
+	if got := meshQuads(chunk.TransparentMesh); got != 6 {
+		t.Errorf("Expected 6 merged glass faces, got %d", got)
+	}
+}
+
+func TestSetBlockTransparencyRemeshesChunks(t *testing.T) {
+	DisableGPUUploadForTesting = true
+	original, defined := BlockDefinitions[BlockStone]
+	t.Cleanup(func() {
+		if defined {
+			BlockDefinitions[BlockStone] = original
+		} else {
+			delete(BlockDefinitions, BlockStone)
+		}
+	})
+
+	cm := NewChunkManager(1)
+	chunk := newTransparencyTestChunk(false, BlockStone, BlockStone)
+	cm.Chunks[chunk.Coord.Key()] = chunk
+	cache := NewChunkMeshCache(t.TempDir())
+	opaqueKey := cache.Key(chunk, chunkOnlyBlocks(chunk))
+
+	cm.SetBlockTransparency(BlockStone, true)
+	if !chunk.NeedUpdateMeshes {
+		t.Fatal("Changing block transparency should mark loaded chunks dirty")
+	}
+	if def := GetBlockDefinition(BlockStone); def.Alpha != DefaultTransparentAlpha {
+		t.Errorf("Expected default alpha %d, got %d", DefaultTransparentAlpha, def.Alpha)
+	}
+	if cache.Key(chunk, chunkOnlyBlocks(chunk)) == opaqueKey {
+		t.Error("Block transparency should change the mesh cache key")
+	}
+
+	cm.UpdatePendingMeshes(10, nil)
+	if meshQuads(chunk.ChunkMesh) != 0 || meshQuads(chunk.TransparentMesh) != 10 {
+		t.Errorf("Expected stone in the transparent mesh, got %d opaque and %d transparent faces",
+			meshQuads(chunk.ChunkMesh), meshQuads(chunk.TransparentMesh))
+	}
+}
+
+func TestTransparentMeshCache(t *testing.T) {
+	DisableGPUUploadForTesting = true
+	cache := NewChunkMeshCache(t.TempDir())
+
+	original := newTransparencyTestChunk(false, BlockStone, BlockGlass)
+	original.UpdateMeshesWithCache(chunkOnlyBlocks(original), nil, cache)
+
+	loaded := NewChunk(0, 0, 0)
+	loaded.Blocks = original.Blocks
+	if !loaded.UpdateMeshesWithCache(chunkOnlyBlocks(loaded), nil, cache) {
+		t.Fatal("Second load should come from the cache")
+	}
+	if meshQuads(loaded.TransparentMesh) != meshQuads(original.TransparentMesh) ||
+		len(loaded.TransparentMesh.Colors) != len(original.TransparentMesh.Colors) {
+		t.Errorf("Cached transparent mesh differs: %d faces, expected %d",
+			meshQuads(loaded.TransparentMesh), meshQuads(original.TransparentMesh))
+	}
+}
@@ -0,0 +1,244 @@
+package game
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+)
+
+// CustomBlocksDir é o diretório padrão dos blocos criados pelo jogador
+const CustomBlocksDir = "custom_blocks"
+
+// Arquivo com as definições dos blocos, dentro do diretório dos blocos
+const customBlocksFile = "blocks.json"
+
+// FirstCustomBlockType primeiro BlockType dos blocos criados pelo jogador (os anteriores são
+// reservados aos blocos embutidos)
+const FirstCustomBlockType BlockType = 128
+
+// Limites padrão dos blocos criados pelo jogador (ver CustomBlockManager)
+const (
+	DefaultMaxCustomBlocks       = 64
+	DefaultMaxCustomTextureBytes = 1 << 20 // 1 MB por PNG
+)
+
+// CustomBlock bloco criado pelo jogador, com uma textura própria em todas as faces
+type CustomBlock struct {
+	Type        BlockType     `json:"type"`
+	Name        string        `json:"name"`
+	Texture     string        `json:"texture"` // PNG dentro do diretório dos blocos
+	Transparent bool          `json:"transparent,omitempty"`
+	Alpha       uint8         `json:"alpha,omitempty"`    // Opacidade quando transparente (0 = DefaultTransparentAlpha)
+	Category    BlockCategory `json:"category,omitempty"` // Aba do catálogo (vazio = CategoryCustom)
+}
+
+// CustomBlockManager guarda os blocos criados pelo jogador em Dir (definições em blocks.json
+// e uma textura PNG por bloco) e os registra nas definições de bloco e no atlas
+type CustomBlockManager struct {
+	Dir    string
+	Blocks map[BlockType]*CustomBlock
+
+	// Limites aplicados ao criar blocos (os já gravados são sempre carregados)
+	MaxBlocks       int   // Máximo de blocos criados pelo jogador
+	MaxTextureBytes int64 // Tamanho máximo do PNG de cada bloco
+}
+
+// NewCustomBlockManager cria o gerenciador para o diretório informado (sem ler o disco)
+func NewCustomBlockManager(dir string) *CustomBlockManager {
+	return &CustomBlockManager{
+		Dir:             dir,
+		Blocks:          make(map[BlockType]*CustomBlock),
+		MaxBlocks:       DefaultMaxCustomBlocks,
+		MaxTextureBytes: DefaultMaxCustomTextureBytes,
+	}
+}
+
+// Count retorna quantos blocos o jogador já criou
+func (m *CustomBlockManager) Count() int {
+	return len(m.Blocks)
+}
+
+// Get retorna o bloco criado pelo jogador com o tipo informado (nil se não existe)
+func (m *CustomBlockManager) Get(blockType BlockType) *CustomBlock {
+	return m.Blocks[blockType]
+}
+
+// nextType próximo BlockType livre para um bloco novo
+func (m *CustomBlockManager) nextType() (BlockType, error) {
+	for t := int(FirstCustomBlockType); t <= 255; t++ {
+		if _, used := m.Blocks[BlockType(t)]; !used {
+			return BlockType(t), nil
+		}
+	}
+	return 0, fmt.Errorf("no free block types left for custom blocks")
+}
+
+// Create cria um bloco com a textura do arquivo PNG informado: copia a textura para Dir,
+// registra o bloco (definição e atlas, se informado) e grava as definições
+func (m *CustomBlockManager) Create(name, texturePath string, transparent bool, atlas *DynamicAtlasManager) (*CustomBlock, error) {
+	if name == "" {
+		return nil, fmt.Errorf("custom block name cannot be empty")
+	}
+	if m.Count() >= m.MaxBlocks {
+		return nil, fmt.Errorf("custom block limit reached (%d/%d)", m.Count(), m.MaxBlocks)
+	}
+
+	info, err := os.Stat(texturePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read texture: %w", err)
+	}
+	if info.Size() > m.MaxTextureBytes {
+		return nil, fmt.Errorf("texture %s is too large: %d bytes (max %d)", texturePath, info.Size(), m.MaxTextureBytes)
+	}
+
+	data, err := os.ReadFile(texturePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read texture: %w", err)
+	}
+
+	blockType, err := m.nextType()
+	if err != nil {
+		return nil, err
+	}
+
+	block := &CustomBlock{
+		Type:        blockType,
+		Name:        name,
+		Texture:     fmt.Sprintf("block_%d.png", blockType),
+		Transparent: transparent,
+	}
+
+	// Valida a textura no atlas antes de gravar qualquer coisa
+	if atlas != nil {
+		if err := atlas.UploadTextureFromFile(blockType, texturePath); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := os.MkdirAll(m.Dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create custom blocks dir: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(m.Dir, block.Texture), data, 0644); err != nil {
+		return nil, fmt.Errorf("failed to write texture: %w", err)
+	}
+
+	m.Blocks[blockType] = block
+	m.register(block)
+
+	if err := m.Save(); err != nil {
+		return nil, err
+	}
+	return block, nil
+}
+
+// register adiciona a definição do bloco a BlockDefinitions
+func (m *CustomBlockManager) register(block *CustomBlock) {
+	def := CustomBlockDefinition{Alpha: 255, Category: block.Category}
+	if block.Transparent {
+		def.Transparent = true
+		def.Alpha = block.Alpha
+		if def.Alpha == 0 {
+			def.Alpha = DefaultTransparentAlpha
+		}
+	}
+	BlockDefinitions[block.Type] = def
+}
+
+// SetCategory muda a aba do catálogo de um bloco criado pelo jogador (mantendo a transparência
+// atual) e grava as definições
+func (m *CustomBlockManager) SetCategory(blockType BlockType, category BlockCategory) error {
+	block := m.Get(blockType)
+	if block == nil {
+		return fmt.Errorf("custom block %d not found", blockType)
+	}
+	if !slices.Contains(CatalogCategories, category) {
+		return fmt.Errorf("unknown block category %q", category)
+	}
+	block.Category = category
+
+	def := GetBlockDefinition(blockType)
+	def.Category = category
+	BlockDefinitions[blockType] = def
+
+	return m.Save()
+}
+
+// Save grava as definições dos blocos em Dir/blocks.json
+func (m *CustomBlockManager) Save() error {
+	blocks := make([]*CustomBlock, 0, len(m.Blocks))
+	for _, block := range m.Blocks {
+		blocks = append(blocks, block)
+	}
+	sort.Slice(blocks, func(i, j int) bool { return blocks[i].Type < blocks[j].Type })
+
+	data, err := json.MarshalIndent(blocks, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal custom blocks: %w", err)
+	}
+
+	if err := os.MkdirAll(m.Dir, 0755); err != nil {
+		return fmt.Errorf("failed to create custom blocks dir: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(m.Dir, customBlocksFile), data, 0644); err != nil {
+		return fmt.Errorf("failed to write custom blocks: %w", err)
+	}
+
+	return nil
+}
+
+// Load lê as definições de Dir/blocks.json e registra os blocos (sem arquivo, não há blocos).
+// As texturas entram no atlas com RegisterTextures, depois que ele existe.
+func (m *CustomBlockManager) Load() error {
+	data, err := os.ReadFile(filepath.Join(m.Dir, customBlocksFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read custom blocks: %w", err)
+	}
+
+	var blocks []*CustomBlock
+	if err := json.Unmarshal(data, &blocks); err != nil {
+		return fmt.Errorf("failed to parse custom blocks: %w", err)
+	}
+
+	for _, block := range blocks {
+		if block.Type < FirstCustomBlockType {
+			return fmt.Errorf("custom block %q uses reserved block type %d", block.Name, block.Type)
+		}
+		m.Blocks[block.Type] = block
+		m.register(block)
+	}
+
+	return nil
+}
+
+// RegisterTextures carrega a textura de cada bloco no atlas. Texturas gravadas com outro
+// tamanho de tile são redimensionadas; blocos com textura faltando ficam com a padrão.
+func (m *CustomBlockManager) RegisterTextures(atlas *DynamicAtlasManager) error {
+	var errs []error
+	for _, block := range m.Blocks {
+		path := filepath.Join(m.Dir, block.Texture)
+		file, err := os.Open(path)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("custom block %q: %w", block.Name, err))
+			continue
+		}
+		img, _, err := image.Decode(file)
+		file.Close()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("custom block %q: failed to decode %s: %w", block.Name, path, err))
+			continue
+		}
+
+		if err := atlas.UploadTexture(block.Type, scaleTexture(img, atlas.TileSize)); err != nil {
+			errs = append(errs, fmt.Errorf("custom block %q: %w", block.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
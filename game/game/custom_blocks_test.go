@@ -0,0 +1,242 @@
+package game
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCustomBlockAndHotbarSurviveReload(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), CustomBlocksDir)
+	hotbarPath := filepath.Join(t.TempDir(), HotbarFile)
+	texture := createTestTexture(32)
+
+	// Sessão 1: criar o bloco e colocá-lo na hotbar
+	atlas := NewDynamicAtlasManager(4, 32)
+	blocks := NewCustomBlockManager(dir)
+	block, err := blocks.Create("Mármore", writeTestPNG(t, texture), false, atlas)
+	if err != nil {
+		t.Fatalf("Failed to create custom block: %v", err)
+	}
+	if block.Type < FirstCustomBlockType {
+		t.Fatalf("Custom block should not reuse built-in types, got %d", block.Type)
+	}
+
+	hotbar := NewBlockHotbar()
+	if err := hotbar.Assign(3, block.Type); err != nil {
+		t.Fatalf("Failed to assign hotbar slot: %v", err)
+	}
+	hotbar.Select(3)
+	if err := hotbar.Save(hotbarPath); err != nil {
+		t.Fatalf("Failed to save hotbar: %v", err)
+	}
+
+	// Sessão 2: tudo recarregado do disco
+	reloaded := NewCustomBlockManager(dir)
+	if err := reloaded.Load(); err != nil {
+		t.Fatalf("Failed to load custom blocks: %v", err)
+	}
+	got := reloaded.Get(block.Type)
+	if got == nil || got.Name != "Mármore" {
+		t.Fatalf("Custom block should survive reload, got %+v", got)
+	}
+
+	reloadedAtlas := NewDynamicAtlasManager(4, 32)
+	if err := reloaded.RegisterTextures(reloadedAtlas); err != nil {
+		t.Fatalf("Failed to register custom textures: %v", err)
+	}
+	img, exists := reloadedAtlas.TextureCache[block.Type]
+	if !exists {
+		t.Fatal("Custom block texture should be registered in the atlas")
+	}
+	for _, p := range [][2]int{{0, 0}, {17, 9}, {31, 31}} {
+		r1, g1, b1, a1 := img.At(p[0], p[1]).RGBA()
+		r2, g2, b2, a2 := texture.At(p[0], p[1]).RGBA()
+		if r1 != r2 || g1 != g2 || b1 != b2 || a1 != a2 {
+			t.Errorf("Texture pixel %v differs after reload", p)
+		}
+	}
+
+	loadedHotbar, err := LoadBlockHotbar(hotbarPath)
+	if err != nil {
+		t.Fatalf("Failed to load hotbar: %v", err)
+	}
+	if loadedHotbar.Slots != hotbar.Slots || loadedHotbar.SelectedBlock() != block.Type {
+		t.Errorf("Hotbar should survive reload: expected %v (slot %d), got %v (slot %d)",
+			hotbar.Slots, hotbar.Selected, loadedHotbar.Slots, loadedHotbar.Selected)
+	}
+
+	// Um segundo bloco recebe outro tipo
+	second, err := reloaded.Create("Cobre", writeTestPNG(t, texture), false, reloadedAtlas)
+	if err != nil {
+		t.Fatalf("Failed to create second custom block: %v", err)
+	}
+	if second.Type == block.Type {
+		t.Errorf("Custom blocks should get distinct types, both got %d", block.Type)
+	}
+}
+
+func TestCustomBlockRejectsWrongTextureSize(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), CustomBlocksDir)
+	blocks := NewCustomBlockManager(dir)
+
+	_, err := blocks.Create("Grande", writeTestPNG(t, createTestTexture(64)), false, NewDynamicAtlasManager(4, 32))
+	if err == nil {
+		t.Fatal("Texture with the wrong size should be rejected")
+	}
+	if len(blocks.Blocks) != 0 {
+		t.Error("Rejected block must not be registered")
+	}
+
+	reloaded := NewCustomBlockManager(dir)
+	if err := reloaded.Load(); err != nil || len(reloaded.Blocks) != 0 {
+		t.Errorf("Rejected block must not be saved, got %d blocks (err %v)", len(reloaded.Blocks), err)
+	}
+}
+
+func TestCustomBlockTransparentDefinition(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), CustomBlocksDir)
+	blocks := NewCustomBlockManager(dir)
+	block, err := blocks.Create("Vitral", writeTestPNG(t, createTestTexture(32)), true, nil)
+	if err != nil {
+		t.Fatalf("Failed to create custom block: %v", err)
+	}
+	defer delete(BlockDefinitions, block.Type)
+
+	delete(BlockDefinitions, block.Type)
+	if err := NewCustomBlockManager(dir).Load(); err != nil {
+		t.Fatalf("Failed to load custom blocks: %v", err)
+	}
+	if !IsTransparentBlock(block.Type) {
+		t.Error("Transparent custom block should be registered as transparent on load")
+	}
+}
+
+func TestLoadBlockHotbarDefaults(t *testing.T) {
+	hotbar, err := LoadBlockHotbar(filepath.Join(t.TempDir(), HotbarFile))
+	if err != nil {
+		t.Fatalf("Missing hotbar file should not fail: %v", err)
+	}
+	if hotbar.Slots != NewBlockHotbar().Slots || hotbar.SelectedBlock() != BlockStone {
+		t.Errorf("Expected default hotbar, got %+v", hotbar)
+	}
+
+	if err := hotbar.Assign(HotbarSize, BlockDirt); err == nil {
+		t.Error("Assigning outside the hotbar should fail")
+	}
+	if err := hotbar.Assign(0, BlockAir); err == nil {
+		t.Error("Assigning air should fail")
+	}
+}
+
+func TestWorldBlockName(t *testing.T) {
+	world := createChunkedFlatWorld()
+	world.CustomBlocks = NewCustomBlockManager(filepath.Join(t.TempDir(), CustomBlocksDir))
+	block, err := world.CustomBlocks.Create("Mármore", writeTestPNG(t, createTestTexture(32)), false, NewDynamicAtlasManager(4, 32))
+	if err != nil {
+		t.Fatalf("Failed to create custom block: %v", err)
+	}
+	world.SetBlock(3, 11, 3, block.Type)
+
+	tests := []struct {
+		x, y, z  int32
+		expected string
+	}{
+		{3, 10, 3, "Grama"},   // Topo do terreno plano
+		{3, 5, 3, "Pedra"},    // Embaixo da terra
+		{3, 11, 3, "Mármore"}, // Bloco criado pelo jogador
+		{3, 20, 3, ""},        // Ar não tem nome
+	}
+	for _, tt := range tests {
+		if name := world.BlockName(world.GetBlock(tt.x, tt.y, tt.z)); name != tt.expected {
+			t.Errorf("Block at (%d, %d, %d): expected name %q, got %q", tt.x, tt.y, tt.z, tt.expected, name)
+		}
+	}
+
+	// Tipo sem nome nem bloco customizado
+	if name := world.BlockName(FirstCustomBlockType + 50); name != fmt.Sprintf("Bloco %d", FirstCustomBlockType+50) {
+		t.Errorf("Unknown block type should fall back to its number, got %q", name)
+	}
+
+	// Todo bloco embutido tem nome
+	for blockType := range BlockTextureFiles {
+		if blockType != BlockAir && BlockNames[blockType] == "" {
+			t.Errorf("Built-in block %d has no display name", blockType)
+		}
+	}
+}
+
+func TestCustomBlockLimits(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), CustomBlocksDir)
+	blocks := NewCustomBlockManager(dir)
+	blocks.MaxBlocks = 2
+	texture := writeTestPNG(t, createTestTexture(32))
+
+	for i := 0; i < 2; i++ {
+		if _, err := blocks.Create(fmt.Sprintf("Bloco %d", i), texture, false, nil); err != nil {
+			t.Fatalf("Block %d within the limit should be created: %v", i, err)
+		}
+	}
+
+	// Acima do limite o bloco não é criado nem gravado
+	_, err := blocks.Create("Excedente", texture, false, nil)
+	if err == nil || !strings.Contains(err.Error(), "limit reached (2/2)") {
+		t.Fatalf("Expected custom block limit error, got %v", err)
+	}
+	if blocks.Count() != 2 {
+		t.Errorf("Rejected block must not be registered, got %d blocks", blocks.Count())
+	}
+	reloaded := NewCustomBlockManager(dir)
+	if err := reloaded.Load(); err != nil || reloaded.Count() != 2 {
+		t.Errorf("Rejected block must not be saved, got %d blocks (err %v)", reloaded.Count(), err)
+	}
+
+	// Texturas maiores que MaxTextureBytes são recusadas antes de serem lidas
+	small := NewCustomBlockManager(filepath.Join(t.TempDir(), CustomBlocksDir))
+	small.MaxTextureBytes = 16
+	_, err = small.Create("Pesado", texture, false, nil)
+	if err == nil || !strings.Contains(err.Error(), "too large") {
+		t.Fatalf("Expected texture size error, got %v", err)
+	}
+	if small.Count() != 0 {
+		t.Errorf("Block with an oversized texture must not be registered, got %d blocks", small.Count())
+	}
+}
+
+func TestCustomBlockCategory(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), CustomBlocksDir)
+	blocks := NewCustomBlockManager(dir)
+	block, err := blocks.Create("Azulejo", writeTestPNG(t, createTestTexture(32)), false, nil)
+	if err != nil {
+		t.Fatalf("Failed to create custom block: %v", err)
+	}
+	defer delete(BlockDefinitions, block.Type)
+
+	all := CatalogBlocks(blocks)
+	if len(all) != int(BlockMoss)+1 || all[len(all)-1] != block.Type {
+		t.Fatalf("Custom block should come after the built-in blocks, got %v", all)
+	}
+	if custom := FilterBlocksByCategory(all, CategoryCustom); len(custom) != 1 || custom[0] != block.Type {
+		t.Errorf("Custom tab should have the new block, got %v", custom)
+	}
+
+	if err := blocks.SetCategory(block.Type, CategoryDecorative); err != nil {
+		t.Fatalf("Failed to set category: %v", err)
+	}
+	if custom := FilterBlocksByCategory(all, CategoryCustom); len(custom) != 0 {
+		t.Errorf("Block moved to another tab should leave the custom tab, got %v", custom)
+	}
+	if err := blocks.SetCategory(block.Type, "metal"); err == nil {
+		t.Error("Unknown category should be rejected")
+	}
+
+	// A categoria sobrevive ao recarregar os blocos do disco
+	delete(BlockDefinitions, block.Type)
+	if err := NewCustomBlockManager(dir).Load(); err != nil {
+		t.Fatalf("Failed to load custom blocks: %v", err)
+	}
+	if got := GetBlockCategory(block.Type); got != CategoryDecorative {
+		t.Errorf("Expected category %q after reload, got %q", CategoryDecorative, got)
+	}
+}
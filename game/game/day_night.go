@@ -0,0 +1,181 @@
+package game
+
+import (
+	"math"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// Ciclo dia/noite
+const (
+	// DayLengthSeconds duração de um dia completo (24 horas do jogo) com Speed 1
+	DayLengthSeconds = 1200.0
+
+	// DefaultStartHour hora do dia em que um mundo novo começa
+	DefaultStartHour = 8.0
+)
+
+// Cores do céu nos momentos do dia que não usam a cor de dia configurada (Settings.FogColor)
+var (
+	NightSkyColor = rl.NewColor(12, 16, 40, 255)
+	DawnSkyColor  = rl.NewColor(250, 150, 100, 255)
+	DuskSkyColor  = rl.NewColor(240, 110, 70, 255)
+)
+
+// TimeOfDay relógio do mundo: Hour vai de 0 a 24 (0 meia-noite, 6 amanhecer, 12 meio-dia,
+// 18 anoitecer) e avança em World.Update
+type TimeOfDay struct {
+	Hour   float32
+	Speed  float32 // Multiplicador da passagem do tempo (1 = um dia a cada DayLengthSeconds)
+	Frozen bool    // Parado (para screenshots); Advance não faz nada
+}
+
+// NewTimeOfDay cria o relógio na hora inicial padrão, com velocidade normal
+func NewTimeOfDay() TimeOfDay {
+	return TimeOfDay{Hour: DefaultStartHour, Speed: 1}
+}
+
+// Advance avança o relógio dt segundos (multiplicados por Speed), voltando a 0 depois das 24h
+func (t *TimeOfDay) Advance(dt float32) {
+	if t.Frozen {
+		return
+	}
+	hour := float64(t.Hour) + float64(dt*t.Speed)*24/DayLengthSeconds
+	hour = math.Mod(hour, 24)
+	if hour < 0 {
+		hour += 24
+	}
+	t.Hour = float32(hour)
+}
+
+// skyKeyframe cor do céu em uma hora; nil usa a cor de dia
+type skyKeyframe struct {
+	hour  float32
+	color *rl.Color
+}
+
+// Noite até as 5h, amanhecer às 6h, dia das 8h às 16h, pôr do sol às 18h e noite de novo às
+// 19h30; entre dois pontos a cor é interpolada linearmente
+var skyKeyframes = []skyKeyframe{
+	{0, &NightSkyColor},
+	{5, &NightSkyColor},
+	{6, &DawnSkyColor},
+	{8, nil},
+	{16, nil},
+	{18, &DuskSkyColor},
+	{19.5, &NightSkyColor},
+	{24, &NightSkyColor},
+}
+
+// SkyColorAt retorna a cor do céu na hora informada, com day como a cor de dia
+func SkyColorAt(hour float32, day rl.Color) rl.Color {
+	hour = float32(math.Mod(float64(hour), 24))
+	if hour < 0 {
+		hour += 24
+	}
+
+	color := func(k skyKeyframe) rl.Color {
+		if k.color == nil {
+			return day
+		}
+		return *k.color
+	}
+
+	for i := 1; i < len(skyKeyframes); i++ {
+		prev, next := skyKeyframes[i-1], skyKeyframes[i]
+		if hour <= next.hour {
+			f := (hour - prev.hour) / (next.hour - prev.hour)
+			return lerpColor(color(prev), color(next), f)
+		}
+	}
+	return color(skyKeyframes[len(skyKeyframes)-1])
+}
+
+// lerpColor interpola duas cores (f de 0 a 1)
+func lerpColor(a, b rl.Color, f float32) rl.Color {
+	lerp := func(x, y uint8) uint8 {
+		return uint8(math.Round(float64(float32(x) + (float32(y)-float32(x))*f)))
+	}
+	return rl.NewColor(lerp(a.R, b.R), lerp(a.G, b.G), lerp(a.B, b.B), lerp(a.A, b.A))
+}
+
+// SunDirection retorna a direção (normalizada) de onde vem a luz do sol na hora informada:
+// nasce no leste (+X) às 6h, fica no alto ao meio-dia e se põe no oeste às 18h. Um pouco
+// inclinado em Z para as faces norte e sul não ficarem iguais.
+func SunDirection(hour float32) rl.Vector3 {
+	angle := float64(hour-6) / 12 * math.Pi
+	return rl.Vector3Normalize(rl.NewVector3(float32(math.Cos(angle)), float32(math.Sin(angle)), 0.3))
+}
+
+// DayLight iluminação dos chunks em um momento do dia
+type DayLight struct {
+	Sky     rl.Color   // Cor do céu e da neblina
+	Sun     rl.Vector3 // Direção de onde vem a luz (o sol de dia, a lua à noite)
+	Ambient float32    // Luz que todas as faces recebem
+	Diffuse float32    // Luz extra das faces voltadas para Sun
+}
+
+// Light retorna a iluminação na hora atual, com day como a cor do céu de dia. A luz
+// direcional vem do sol enquanto ele está acima do horizonte e, mais fraca, da lua (o lado
+// oposto) à noite.
+func (t TimeOfDay) Light(day rl.Color) DayLight {
+	sun := SunDirection(t.Hour)
+
+	// 0 de noite, 1 com o sol a partir de ~20° acima do horizonte
+	daylight := float32(math.Max(0, math.Min(1, float64(sun.Y+0.1)/0.45)))
+	if sun.Y < 0 {
+		sun = rl.Vector3Negate(sun)
+	}
+
+	return DayLight{
+		Sky:     SkyColorAt(t.Hour, day),
+		Sun:     sun,
+		Ambient: 0.3 + 0.3*daylight,
+		Diffuse: 0.15 + 0.35*daylight,
+	}
+}
+
+// fullDayLight luz sem sombreamento direcional (chunks com as cores da textura, como antes do
+// ciclo dia/noite); usada quando nenhuma iluminação foi definida
+func fullDayLight(sky rl.Color) DayLight {
+	return DayLight{Sky: sky, Sun: rl.NewVector3(0, 1, 0), Ambient: 1}
+}
+
+// Uniforms e função de luz direcional compartilhadas pelos shaders de chunk
+const lightShaderUniforms = `uniform vec3 sunDirection;
+uniform float lightAmbient;
+uniform float lightDiffuse;
+vec4 applyLight(vec4 color, vec3 normal) {
+    float light = lightAmbient + lightDiffuse*max(dot(normalize(normal), sunDirection), 0.0);
+    return vec4(color.rgb*min(light, 1.0), color.a);
+}`
+
+// lightShaderLocs posições dos uniforms de luz em um shader
+type lightShaderLocs struct {
+	sun, ambient, diffuse int32
+}
+
+func getLightShaderLocs(shader rl.Shader) lightShaderLocs {
+	return lightShaderLocs{
+		sun:     rl.GetShaderLocation(shader, "sunDirection"),
+		ambient: rl.GetShaderLocation(shader, "lightAmbient"),
+		diffuse: rl.GetShaderLocation(shader, "lightDiffuse"),
+	}
+}
+
+func (l lightShaderLocs) set(shader rl.Shader, light DayLight) {
+	rl.SetShaderValue(shader, l.sun, []float32{light.Sun.X, light.Sun.Y, light.Sun.Z}, rl.ShaderUniformVec3)
+	rl.SetShaderValue(shader, l.ambient, []float32{light.Ambient}, rl.ShaderUniformFloat)
+	rl.SetShaderValue(shader, l.diffuse, []float32{light.Diffuse}, rl.ShaderUniformFloat)
+}
+
+// applyLightUniforms atualiza a luz nos shaders de chunk já carregados (chamar uma vez por
+// frame, antes de desenhar os chunks)
+func applyLightUniforms(light DayLight) {
+	if chunkShaderLoaded {
+		chunkShaderLight.set(chunkShader, light)
+	}
+	if tiledQuadLoaded {
+		tiledQuadLight.set(tiledQuadShader, light)
+	}
+}
@@ -0,0 +1,89 @@
+package game
+
+import (
+	"math"
+	"testing"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+func TestSkyColorAt(t *testing.T) {
+	day := rl.NewColor(102, 191, 255, 255)
+
+	tests := []struct {
+		name     string
+		hour     float32
+		expected rl.Color
+	}{
+		{"midnight", 0, NightSkyColor},
+		{"before dawn", 5, NightSkyColor},
+		{"dawn", 6, DawnSkyColor},
+		{"halfway to day", 7, rl.NewColor(176, 171, 178, 255)},
+		{"morning", 8, day},
+		{"noon", 12, day},
+		{"dusk", 18, DuskSkyColor},
+		{"night", 21, NightSkyColor},
+		{"wraps after 24h", 36, day},
+		{"negative hour", -24, NightSkyColor},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SkyColorAt(tt.hour, day); got != tt.expected {
+				t.Errorf("SkyColorAt(%.1f) = %v, expected %v", tt.hour, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestTimeOfDayAdvance(t *testing.T) {
+	clock := NewTimeOfDay()
+	if clock.Hour != DefaultStartHour {
+		t.Fatalf("Expected start hour %.1f, got %.1f", DefaultStartHour, clock.Hour)
+	}
+
+	// Um quarto do dia real = 6 horas do jogo
+	clock.Advance(DayLengthSeconds / 4)
+	if math.Abs(float64(clock.Hour-14)) > 1e-3 {
+		t.Errorf("Expected 14h after a quarter day, got %.3f", clock.Hour)
+	}
+
+	// Com o dobro da velocidade, meio dia real dá a volta inteira
+	clock.Speed = 2
+	clock.Advance(DayLengthSeconds / 2)
+	if math.Abs(float64(clock.Hour-14)) > 1e-3 {
+		t.Errorf("Expected to wrap back to 14h, got %.3f", clock.Hour)
+	}
+
+	clock.Frozen = true
+	clock.Advance(DayLengthSeconds / 3)
+	if math.Abs(float64(clock.Hour-14)) > 1e-3 {
+		t.Errorf("Frozen clock should not advance, got %.3f", clock.Hour)
+	}
+}
+
+func TestTimeOfDayLight(t *testing.T) {
+	day := rl.NewColor(102, 191, 255, 255)
+
+	noon := TimeOfDay{Hour: 12}.Light(day)
+	if noon.Sky != day || noon.Sun.Y < 0.9 {
+		t.Errorf("Noon should have the day sky and the sun overhead, got %+v", noon)
+	}
+
+	midnight := TimeOfDay{Hour: 0}.Light(day)
+	if midnight.Sky != NightSkyColor {
+		t.Errorf("Midnight should have the night sky, got %v", midnight.Sky)
+	}
+	// A lua ilumina de cima, mais fraca que o sol
+	if midnight.Sun.Y <= 0 {
+		t.Errorf("Night light should come from above, got direction %v", midnight.Sun)
+	}
+	if midnight.Ambient >= noon.Ambient || midnight.Diffuse >= noon.Diffuse {
+		t.Errorf("Night should be darker than noon: %+v vs %+v", midnight, noon)
+	}
+
+	// Sol nasce no leste e se põe no oeste
+	if SunDirection(6).X < 0.9 || SunDirection(18).X > -0.9 {
+		t.Errorf("Sun should rise in +X and set in -X, got %v and %v", SunDirection(6), SunDirection(18))
+	}
+}
@@ -1,6 +1,7 @@
 package game
 
 import (
+	"crypto/sha256"
 	"fmt"
 	"image"
 	"image/color"
@@ -12,17 +13,33 @@ import (
 	rl "github.com/gen2brain/raylib-go/raylib"
 )
 
+// Tamanhos de textura aceitos (lado do tile em pixels)
+const DefaultTextureSize int32 = 32
+
+// SupportedTextureSizes lados de textura aceitos pelo atlas (potências de dois)
+var SupportedTextureSizes = []int32{16, 32, 64, 128}
+
+// IsSupportedTextureSize verifica se o lado informado é um tamanho de textura aceito
+func IsSupportedTextureSize(size int32) bool {
+	for _, supported := range SupportedTextureSizes {
+		if size == supported {
+			return true
+		}
+	}
+	return false
+}
+
 // DynamicAtlasManager gerencia um atlas de texturas dinâmico
 type DynamicAtlasManager struct {
 	mu sync.RWMutex
 
 	// Configuração
 	AtlasGridSize  int32 // Ex: 4 para atlas 4x4
-	TileSize       int32 // Ex: 32 pixels
+	TileSize       int32 // Ex: 32 pixels (ver SupportedTextureSizes)
 	AtlasPixelSize int32 // AtlasGridSize * TileSize
 
 	// Cache de texturas carregadas
-	TextureCache map[BlockType]image.Image // BlockType → imagem 32x32
+	TextureCache map[BlockType]image.Image // BlockType → imagem TileSize x TileSize
 
 	// Mapeamento de slots
 	BlockToSlot map[BlockType]int32   // BlockType → posição no atlas (0-15 para 4x4)
@@ -34,6 +51,7 @@ type DynamicAtlasManager struct {
 	AtlasImage   *image.RGBA      // Imagem do atlas montado
 	AtlasTexture rl.Texture2D     // Textura no GPU
 	AtlasDirty   bool             // Precisa rebuild?
+	Version      string           // Hash do conteúdo do atlas (muda quando o atlas muda)
 
 	// Estatísticas
 	LoadedTextures int
@@ -87,12 +105,79 @@ func (dam *DynamicAtlasManager) LoadTexture(blockType BlockType, filePath string
 		return fmt.Errorf("erro ao decodificar %s: %w", filePath, err)
 	}
 
-	dam.TextureCache[blockType] = img
+	// Texturas embutidas de outro tamanho são redimensionadas para o tile configurado
+	dam.TextureCache[blockType] = scaleTexture(img, dam.TileSize)
 	dam.LoadedTextures++
 
 	return nil
 }
 
+// UploadTexture substitui a textura de um BlockType por uma imagem enviada pelo usuário, que
+// deve ter exatamente TileSize x TileSize pixels
+func (dam *DynamicAtlasManager) UploadTexture(blockType BlockType, img image.Image) error {
+	dam.mu.Lock()
+	defer dam.mu.Unlock()
+
+	bounds := img.Bounds()
+	if int32(bounds.Dx()) != dam.TileSize || int32(bounds.Dy()) != dam.TileSize {
+		return fmt.Errorf("textura deve ter %dx%d pixels, recebida %dx%d",
+			dam.TileSize, dam.TileSize, bounds.Dx(), bounds.Dy())
+	}
+
+	if _, exists := dam.TextureCache[blockType]; !exists {
+		dam.LoadedTextures++
+	}
+	dam.TextureCache[blockType] = img
+	if _, allocated := dam.BlockToSlot[blockType]; allocated {
+		dam.AtlasDirty = true
+	}
+
+	return nil
+}
+
+// UploadTextureFromFile lê um PNG (caminho completo, fora de assets/) e o envia com UploadTexture
+func (dam *DynamicAtlasManager) UploadTextureFromFile(blockType BlockType, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("erro ao abrir %s: %w", path, err)
+	}
+	defer file.Close()
+
+	img, _, err := image.Decode(file)
+	if err != nil {
+		return fmt.Errorf("erro ao decodificar %s: %w", path, err)
+	}
+
+	if err := dam.UploadTexture(blockType, img); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+// SetTileSize muda o tamanho dos tiles do atlas, redimensionando as texturas já carregadas.
+// O atlas é remontado no próximo RebuildAtlas.
+func (dam *DynamicAtlasManager) SetTileSize(size int32) error {
+	if !IsSupportedTextureSize(size) {
+		return fmt.Errorf("tamanho de textura não suportado: %d (aceitos: %v)", size, SupportedTextureSizes)
+	}
+
+	dam.mu.Lock()
+	defer dam.mu.Unlock()
+
+	if size == dam.TileSize {
+		return nil
+	}
+
+	dam.TileSize = size
+	dam.AtlasPixelSize = dam.AtlasGridSize * size
+	for blockType, img := range dam.TextureCache {
+		dam.TextureCache[blockType] = scaleTexture(img, size)
+	}
+	dam.AtlasDirty = true
+
+	return nil
+}
+
 // AllocateSlot aloca um slot no atlas para um BlockType
 func (dam *DynamicAtlasManager) AllocateSlot(blockType BlockType) int32 {
 	dam.mu.Lock()
@@ -150,6 +235,11 @@ func (dam *DynamicAtlasManager) RebuildAtlas() {
 		return
 	}
 
+	// Ajustar a imagem ao tamanho atual dos tiles (ver SetTileSize)
+	if dam.AtlasImage.Bounds().Dx() != int(dam.AtlasPixelSize) {
+		dam.AtlasImage = image.NewRGBA(image.Rect(0, 0, int(dam.AtlasPixelSize), int(dam.AtlasPixelSize)))
+	}
+
 	// Limpar atlas (preto com alpha)
 	for y := 0; y < int(dam.AtlasPixelSize); y++ {
 		for x := 0; x < int(dam.AtlasPixelSize); x++ {
@@ -171,7 +261,8 @@ func (dam *DynamicAtlasManager) RebuildAtlas() {
 		destX := int(col * dam.TileSize)
 		destY := int(row * dam.TileSize)
 
-		// Copiar pixels
+		// Copiar pixels (texturas de outro tamanho são redimensionadas)
+		img = scaleTexture(img, dam.TileSize)
 		for y := 0; y < int(dam.TileSize); y++ {
 			for x := 0; x < int(dam.TileSize); x++ {
 				// Garantir que não exceda os limites da imagem fonte
@@ -186,6 +277,14 @@ func (dam *DynamicAtlasManager) RebuildAtlas() {
 
 	dam.AtlasDirty = false
 	dam.RebuildCount++
+	dam.Version = fmt.Sprintf("%x", sha256.Sum256(dam.AtlasImage.Pix))
+}
+
+// GetVersion retorna a versão atual do atlas (vazia antes do primeiro rebuild)
+func (dam *DynamicAtlasManager) GetVersion() string {
+	dam.mu.RLock()
+	defer dam.mu.RUnlock()
+	return dam.Version
 }
 
 // UploadToGPU faz upload do atlas para GPU
@@ -235,6 +334,12 @@ func (dam *DynamicAtlasManager) GetBlockUVs(blockType BlockType) (uMin, vMin, uM
 	return
 }
 
+// GetFaceUVs retorna as UVs da face (índice do mesher) de um bloco com a rotação informada:
+// blocos com FaceTextures usam a textura da face local que a rotação leva para essa face
+func (dam *DynamicAtlasManager) GetFaceUVs(blockType BlockType, rotation BlockRotation, face int) (uMin, vMin, uMax, vMax float32) {
+	return dam.GetBlockUVs(BlockFaceTexture(blockType, rotation, face))
+}
+
 // SaveAtlasDebug salva atlas atual em arquivo para debug
 func (dam *DynamicAtlasManager) SaveAtlasDebug(filename string) error {
 	dam.mu.RLock()
@@ -262,3 +367,25 @@ func (dam *DynamicAtlasManager) PrintStats() {
 	fmt.Printf("Atlas Dirty: %v\n", dam.AtlasDirty)
 	fmt.Printf("==========================\n")
 }
+
+// scaleTexture redimensiona uma textura para size x size pixels (vizinho mais próximo, para
+// manter o visual pixelado). Texturas já no tamanho certo são retornadas sem cópia.
+func scaleTexture(img image.Image, size int32) image.Image {
+	bounds := img.Bounds()
+	if int32(bounds.Dx()) == size && int32(bounds.Dy()) == size {
+		return img
+	}
+	if bounds.Dx() == 0 || bounds.Dy() == 0 {
+		return img
+	}
+
+	scaled := image.NewRGBA(image.Rect(0, 0, int(size), int(size)))
+	for y := 0; y < int(size); y++ {
+		srcY := bounds.Min.Y + y*bounds.Dy()/int(size)
+		for x := 0; x < int(size); x++ {
+			srcX := bounds.Min.X + x*bounds.Dx()/int(size)
+			scaled.Set(x, y, img.At(srcX, srcY))
+		}
+	}
+	return scaled
+}
@@ -0,0 +1,128 @@
+package game
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Helper: textura size x size com um pixel diferente em cada canto
+func createTestTexture(size int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			img.Set(x, y, color.RGBA{uint8(x), uint8(y), 100, 255})
+		}
+	}
+	return img
+}
+
+// Helper: grava a imagem como PNG em um diretório temporário
+func writeTestPNG(t *testing.T, img image.Image) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "texture.png")
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("Failed to create PNG: %v", err)
+	}
+	defer file.Close()
+
+	if err := png.Encode(file, img); err != nil {
+		t.Fatalf("Failed to encode PNG: %v", err)
+	}
+	return path
+}
+
+func TestDynamicAtlas_Upload64x64Texture(t *testing.T) {
+	atlas := NewDynamicAtlasManager(4, 64)
+	if atlas.AtlasPixelSize != 256 {
+		t.Fatalf("Expected 256px atlas for 4x4 grid of 64px tiles, got %d", atlas.AtlasPixelSize)
+	}
+
+	texture := createTestTexture(64)
+	if err := atlas.UploadTextureFromFile(BlockStone, writeTestPNG(t, texture)); err != nil {
+		t.Fatalf("64x64 upload should be accepted: %v", err)
+	}
+
+	stored, exists := atlas.TextureCache[BlockStone]
+	if !exists {
+		t.Fatal("Uploaded texture should be stored in the cache")
+	}
+	if stored.Bounds().Dx() != 64 || stored.Bounds().Dy() != 64 {
+		t.Errorf("Stored texture should be 64x64, got %v", stored.Bounds())
+	}
+
+	slot := atlas.AllocateSlot(BlockStone)
+	atlas.RebuildAtlas()
+
+	// O tile inteiro (inclusive além dos 32 primeiros pixels) deve estar no atlas
+	destX := int(slot%atlas.AtlasGridSize) * 64
+	destY := int(slot/atlas.AtlasGridSize) * 64
+	for _, p := range [][2]int{{0, 0}, {31, 31}, {32, 40}, {63, 63}} {
+		got := atlas.AtlasImage.RGBAAt(destX+p[0], destY+p[1])
+		want := texture.RGBAAt(p[0], p[1])
+		if got != want {
+			t.Errorf("Atlas pixel (%d, %d) of the tile = %v, want %v", p[0], p[1], got, want)
+		}
+	}
+
+	uMin, vMin, uMax, vMax := atlas.GetBlockUVs(BlockStone)
+	if uMax-uMin != 0.25 || vMax-vMin != 0.25 {
+		t.Errorf("Expected tile UVs of 1/4 for a 4x4 grid, got (%.2f, %.2f)-(%.2f, %.2f)", uMin, vMin, uMax, vMax)
+	}
+}
+
+func TestDynamicAtlas_RejectsWrongTextureSize(t *testing.T) {
+	atlas := NewDynamicAtlasManager(4, 64)
+
+	for _, size := range []int{32, 128, 63} {
+		if err := atlas.UploadTexture(BlockStone, createTestTexture(size)); err == nil {
+			t.Errorf("%dx%d upload should be rejected by a 64px atlas", size, size)
+		}
+	}
+	if _, exists := atlas.TextureCache[BlockStone]; exists {
+		t.Error("Rejected uploads must not be stored")
+	}
+}
+
+func TestDynamicAtlas_SetTileSizeRescalesTextures(t *testing.T) {
+	atlas := NewDynamicAtlasManager(4, 32)
+	if err := atlas.UploadTexture(BlockDirt, createTestTexture(32)); err != nil {
+		t.Fatalf("32x32 upload should be accepted: %v", err)
+	}
+	atlas.AllocateSlot(BlockDirt)
+	atlas.RebuildAtlas()
+
+	if err := atlas.SetTileSize(48); err == nil {
+		t.Error("Unsupported tile size should be rejected")
+	}
+	if err := atlas.SetTileSize(16); err != nil {
+		t.Fatalf("Failed to change tile size: %v", err)
+	}
+
+	if bounds := atlas.TextureCache[BlockDirt].Bounds(); bounds.Dx() != 16 || bounds.Dy() != 16 {
+		t.Errorf("Cached texture should be rescaled to 16x16, got %v", bounds)
+	}
+
+	atlas.RebuildAtlas()
+	if atlas.AtlasImage.Bounds().Dx() != 64 {
+		t.Errorf("Expected 64px atlas for a 4x4 grid of 16px tiles, got %d", atlas.AtlasImage.Bounds().Dx())
+	}
+}
+
+func TestSettingsClampTextureSize(t *testing.T) {
+	for _, tt := range []struct {
+		in, want int32
+	}{{16, 16}, {64, 64}, {128, 128}, {0, DefaultTextureSize}, {48, DefaultTextureSize}, {256, DefaultTextureSize}} {
+		settings := DefaultSettings()
+		settings.TextureSize = tt.in
+		settings.Clamp()
+		if settings.TextureSize != tt.want {
+			t.Errorf("TextureSize %d clamped to %d, want %d", tt.in, settings.TextureSize, tt.want)
+		}
+	}
+}
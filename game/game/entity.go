@@ -0,0 +1,114 @@
+package game
+
+import (
+	"math"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// Entity é um objeto do mundo que não é bloco (NPC, item etc.), representado por uma caixa
+type Entity struct {
+	ID         int
+	Name       string
+	Position   rl.Vector3 // Centro da base da entidade
+	Size       rl.Vector3 // Largura (X), altura (Y) e profundidade (Z)
+	Color      rl.Color
+	OnInteract func(player *Player) // Chamado quando o jogador interage com a entidade (opcional)
+}
+
+// BoundingBox retorna a caixa da entidade em coordenadas do mundo
+func (e *Entity) BoundingBox() rl.BoundingBox {
+	half := rl.NewVector3(e.Size.X/2, 0, e.Size.Z/2)
+	return rl.NewBoundingBox(
+		rl.NewVector3(e.Position.X-half.X, e.Position.Y, e.Position.Z-half.Z),
+		rl.NewVector3(e.Position.X+half.X, e.Position.Y+e.Size.Y, e.Position.Z+half.Z),
+	)
+}
+
+// AddEntity coloca uma entidade no mundo e retorna a entidade criada
+func (w *World) AddEntity(name string, position, size rl.Vector3) *Entity {
+	w.nextEntityID++
+	entity := &Entity{
+		ID:       w.nextEntityID,
+		Name:     name,
+		Position: position,
+		Size:     size,
+		Color:    rl.Orange,
+	}
+	w.entities = append(w.entities, entity)
+	return entity
+}
+
+// RemoveEntity remove a entidade do mundo
+func (w *World) RemoveEntity(id int) {
+	for i, entity := range w.entities {
+		if entity.ID == id {
+			w.entities = append(w.entities[:i], w.entities[i+1:]...)
+			return
+		}
+	}
+}
+
+// Entities retorna as entidades do mundo
+func (w *World) Entities() []*Entity {
+	return w.entities
+}
+
+// RenderEntities desenha as entidades como caixas
+func (w *World) RenderEntities() {
+	for _, entity := range w.entities {
+		center := rl.NewVector3(entity.Position.X, entity.Position.Y+entity.Size.Y/2, entity.Position.Z)
+		rl.DrawCubeV(center, entity.Size, entity.Color)
+		rl.DrawCubeWiresV(center, entity.Size, rl.Black)
+	}
+}
+
+// RaycastEntities retorna a entidade mais próxima atingida pelo raio (dir normalizada)
+func (w *World) RaycastEntities(origin, dir rl.Vector3, maxDistance float32) RaycastHit {
+	var nearest RaycastHit
+	for _, entity := range w.entities {
+		distance, ok := rayBoxDistance(origin, dir, entity.BoundingBox())
+		if !ok || distance > maxDistance {
+			continue
+		}
+		if !nearest.Hit || distance < nearest.Distance {
+			nearest = RaycastHit{Hit: true, Distance: distance, Entity: entity}
+		}
+	}
+	return nearest
+}
+
+// rayBoxDistance calcula onde o raio entra na caixa (método dos slabs). Se a origem está
+// dentro da caixa a distância é 0.
+func rayBoxDistance(origin, dir rl.Vector3, box rl.BoundingBox) (float32, bool) {
+	tMin := float32(0)
+	tMax := float32(math.MaxFloat32)
+
+	axes := [3][4]float32{
+		{origin.X, dir.X, box.Min.X, box.Max.X},
+		{origin.Y, dir.Y, box.Min.Y, box.Max.Y},
+		{origin.Z, dir.Z, box.Min.Z, box.Max.Z},
+	}
+	for _, axis := range axes {
+		o, d, lo, hi := axis[0], axis[1], axis[2], axis[3]
+		if d == 0 {
+			// Raio paralelo ao slab: precisa estar entre os planos
+			if o < lo || o > hi {
+				return 0, false
+			}
+			continue
+		}
+
+		t1 := (lo - o) / d
+		t2 := (hi - o) / d
+		if t1 > t2 {
+			t1, t2 = t2, t1
+		}
+		tMin = max(tMin, t1)
+		tMax = min(tMax, t2)
+		if tMin > tMax {
+			return 0, false
+		}
+	}
+	return tMin, true
+}
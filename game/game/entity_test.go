@@ -0,0 +1,63 @@
+package game
+
+import (
+	"testing"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// TestRaycastSelectsNearestEntityOverFartherBlock verifica que uma entidade na frente de um
+// bloco é o alvo do raio, e que o bloco volta a ser o alvo quando a entidade está atrás dele
+func TestRaycastSelectsNearestEntityOverFartherBlock(t *testing.T) {
+	world := createChunkedFlatWorld()
+
+	// Bloco a ~6.5 de distância na direção +X, acima do chão (Y=10)
+	world.SetBlock(12, 12, 5, BlockStone)
+	npc := world.AddEntity("npc", rl.NewVector3(9, 11, 5.5), rl.NewVector3(0.6, 1.8, 0.6))
+
+	origin := rl.NewVector3(5.5, 12.5, 5.5)
+	dir := rl.NewVector3(1, 0, 0)
+
+	entityHit := world.RaycastEntities(origin, dir, 10)
+	if !entityHit.Hit || entityHit.Entity != npc {
+		t.Fatalf("Ray should hit the entity, got %+v", entityHit)
+	}
+	if !approximatelyEqual(entityHit.Distance, 3.2, 0.01) {
+		t.Errorf("Expected entity hit at distance 3.2, got %.2f", entityHit.Distance)
+	}
+
+	hit := world.Raycast(origin, dir, 10)
+	if hit.Entity != npc {
+		t.Fatalf("Nearest hit should be the entity, got block %v (entity %v)", hit.Block, hit.Entity)
+	}
+
+	// Jogador mirando na mesma direção seleciona a entidade e pode interagir com ela
+	player := NewPlayer(rl.NewVector3(5.5, 11, 5.5))
+	player.Camera.Position = origin
+	player.Camera.Target = rl.Vector3Add(origin, dir)
+	player.Raycast(world)
+
+	if !player.LookingAtEntity || player.TargetEntity != npc || player.LookingAtBlock {
+		t.Fatalf("Player should target the entity (entity=%v block=%v)", player.LookingAtEntity, player.LookingAtBlock)
+	}
+
+	interacted := false
+	npc.OnInteract = func(p *Player) { interacted = p == player }
+	player.InteractWith(player.TargetEntity)
+	if !interacted {
+		t.Error("Interacting should call the entity's OnInteract")
+	}
+
+	// Entidade atrás do bloco: o bloco é o alvo
+	npc.Position = rl.NewVector3(14, 11, 5.5)
+	hit = world.Raycast(origin, dir, 10)
+	if hit.Entity != nil || !hit.Hit || hit.Block != rl.NewVector3(12, 12, 5) {
+		t.Errorf("Block in front of the entity should be the hit, got %+v", hit)
+	}
+
+	// Entidade removida não é mais atingida
+	world.RemoveEntity(npc.ID)
+	if hit := world.RaycastEntities(origin, dir, 10); hit.Hit {
+		t.Errorf("Removed entity should not be hit")
+	}
+}
@@ -0,0 +1,154 @@
+package game
+
+import (
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// Fog neblina de distância: os chunks se misturam à cor do céu entre Near e Far (em blocos a
+// partir do jogador), escondendo o surgimento dos chunks na borda da distância de renderização
+type Fog struct {
+	Enabled bool
+	Color   rl.Color
+	Near    float32 // Distância onde a neblina começa
+	Far     float32 // Distância onde a neblina cobre tudo
+}
+
+// NewFog cria a neblina das configurações para a distância de renderização (em chunks):
+// FogStart e FogEnd são frações do raio de visão
+func NewFog(settings Settings, renderDistance int32) Fog {
+	radius := float32(renderDistance * ChunkSize)
+	return Fog{
+		Enabled: settings.Fog,
+		Color:   rl.NewColor(settings.FogColor[0], settings.FogColor[1], settings.FogColor[2], 255),
+		Near:    settings.FogStart * radius,
+		Far:     settings.FogEnd * radius,
+	}
+}
+
+// FogFactor retorna quanto da cor da neblina cobre um ponto à distância informada: 0 até near,
+// 1 a partir de far e linear entre os dois
+func FogFactor(distance, near, far float32) float32 {
+	if distance <= near {
+		return 0
+	}
+	if distance >= far {
+		return 1
+	}
+	return (distance - near) / (far - near)
+}
+
+// Factor retorna o fator da neblina à distância informada (0 com a neblina desligada)
+func (f Fog) Factor(distance float32) float32 {
+	if !f.Enabled {
+		return 0
+	}
+	return FogFactor(distance, f.Near, f.Far)
+}
+
+// Shader padrão dos chunks: o mesmo do raylib (textura * colDiffuse * cor do vértice) com a
+// luz do dia (ver DayLight) e a neblina aplicada pela distância do fragmento até fogOrigin
+const chunkVertexShader = `#version 330
+in vec3 vertexPosition;
+in vec2 vertexTexCoord;
+in vec3 vertexNormal;
+in vec4 vertexColor;
+uniform mat4 mvp;
+uniform mat4 matModel;
+out vec2 fragTexCoord;
+out vec4 fragColor;
+out vec3 fragPosition;
+out vec3 fragNormal;
+void main() {
+    fragTexCoord = vertexTexCoord;
+    fragColor = vertexColor;
+    fragPosition = vec3(matModel*vec4(vertexPosition, 1.0));
+    fragNormal = vertexNormal;
+    gl_Position = mvp*vec4(vertexPosition, 1.0);
+}
+`
+
+const chunkFragmentShader = `#version 330
+in vec2 fragTexCoord;
+in vec4 fragColor;
+in vec3 fragPosition;
+in vec3 fragNormal;
+uniform sampler2D texture0;
+uniform vec4 colDiffuse;
+` + fogShaderUniforms + `
+` + lightShaderUniforms + `
+out vec4 finalColor;
+void main() {
+    finalColor = applyFog(applyLight(texture(texture0, fragTexCoord)*colDiffuse*fragColor, fragNormal), fragPosition);
+}
+`
+
+// Uniforms e função de neblina compartilhadas pelos shaders de chunk (mesma conta de FogFactor;
+// com a neblina desligada near e far ficam fora de alcance)
+const fogShaderUniforms = `uniform vec3 fogOrigin;
+uniform vec4 fogColor;
+uniform float fogNear;
+uniform float fogFar;
+vec4 applyFog(vec4 color, vec3 position) {
+    float d = distance(position, fogOrigin);
+    float f = clamp((d - fogNear)/max(fogFar - fogNear, 0.0001), 0.0, 1.0);
+    return vec4(mix(color.rgb, fogColor.rgb, f), color.a);
+}`
+
+// Sem neblina: distância muito maior que qualquer chunk carregado
+const fogDisabledDistance = 1e9
+
+var (
+	chunkShader       rl.Shader
+	chunkShaderFog    fogShaderLocs
+	chunkShaderLight  lightShaderLocs
+	chunkShaderLoaded bool
+)
+
+// loadChunkShader carrega o shader padrão dos chunks na primeira chamada (compartilhado por
+// todos os chunks)
+func loadChunkShader() rl.Shader {
+	if !chunkShaderLoaded {
+		chunkShader = rl.LoadShaderFromMemory(chunkVertexShader, chunkFragmentShader)
+		chunkShaderFog = getFogShaderLocs(chunkShader)
+		chunkShaderLight = getLightShaderLocs(chunkShader)
+		chunkShaderLoaded = true
+	}
+	return chunkShader
+}
+
+// fogShaderLocs posições dos uniforms de neblina em um shader
+type fogShaderLocs struct {
+	origin, color, near, far int32
+}
+
+func getFogShaderLocs(shader rl.Shader) fogShaderLocs {
+	return fogShaderLocs{
+		origin: rl.GetShaderLocation(shader, "fogOrigin"),
+		color:  rl.GetShaderLocation(shader, "fogColor"),
+		near:   rl.GetShaderLocation(shader, "fogNear"),
+		far:    rl.GetShaderLocation(shader, "fogFar"),
+	}
+}
+
+func (l fogShaderLocs) set(shader rl.Shader, fog Fog, origin rl.Vector3) {
+	near, far := float32(fogDisabledDistance), float32(fogDisabledDistance)
+	if fog.Enabled {
+		near, far = fog.Near, fog.Far
+	}
+	color := rl.ColorNormalize(fog.Color)
+	rl.SetShaderValue(shader, l.origin, []float32{origin.X, origin.Y, origin.Z}, rl.ShaderUniformVec3)
+	rl.SetShaderValue(shader, l.color, []float32{color.X, color.Y, color.Z, color.W}, rl.ShaderUniformVec4)
+	rl.SetShaderValue(shader, l.near, []float32{near}, rl.ShaderUniformFloat)
+	rl.SetShaderValue(shader, l.far, []float32{far}, rl.ShaderUniformFloat)
+}
+
+// applyFogUniforms atualiza a neblina nos shaders de chunk já carregados (chamar uma vez por
+// frame, antes de desenhar os chunks)
+func applyFogUniforms(fog Fog, origin rl.Vector3) {
+	if chunkShaderLoaded {
+		chunkShaderFog.set(chunkShader, fog, origin)
+	}
+	if tiledQuadLoaded {
+		tiledQuadFog.set(tiledQuadShader, fog, origin)
+	}
+}
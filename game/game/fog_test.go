@@ -0,0 +1,91 @@
+package game
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+func TestFogFactor(t *testing.T) {
+	tests := []struct {
+		name      string
+		distance  float32
+		near, far float32
+		expected  float32
+	}{
+		{"before near", 10, 40, 80, 0},
+		{"at near", 40, 40, 80, 0},
+		{"quarter", 50, 40, 80, 0.25},
+		{"halfway", 60, 40, 80, 0.5},
+		{"at far", 80, 40, 80, 1},
+		{"beyond far", 200, 40, 80, 1},
+		{"near equals far", 61, 60, 60, 1},
+		{"zero near", 20, 0, 80, 0.25},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FogFactor(tt.distance, tt.near, tt.far)
+			if math.Abs(float64(got-tt.expected)) > 1e-6 {
+				t.Errorf("FogFactor(%.1f, %.1f, %.1f) = %.4f, expected %.4f", tt.distance, tt.near, tt.far, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNewFogFollowsRenderDistance(t *testing.T) {
+	settings := DefaultSettings()
+	settings.FogStart = 0.5
+	settings.FogEnd = 1
+
+	fog := NewFog(settings, 4)
+	if fog.Near != 2*ChunkSize || fog.Far != 4*ChunkSize {
+		t.Errorf("Expected fog from %d to %d blocks, got %.1f to %.1f", 2*ChunkSize, 4*ChunkSize, fog.Near, fog.Far)
+	}
+	if fog.Color.R != DefaultFogColor[0] || fog.Color.G != DefaultFogColor[1] || fog.Color.B != DefaultFogColor[2] || fog.Color.A != 255 {
+		t.Errorf("Unexpected fog color %v", fog.Color)
+	}
+
+	// Reduzir a distância de renderização aproxima a neblina na mesma proporção
+	smaller := NewFog(settings, 2)
+	if smaller.Near != fog.Near/2 || smaller.Far != fog.Far/2 {
+		t.Errorf("Fog should scale with render distance, got %.1f to %.1f", smaller.Near, smaller.Far)
+	}
+
+	// O último chunk visível fica coberto pela neblina
+	if f := fog.Factor(4 * ChunkSize); f != 1 {
+		t.Errorf("Expected full fog at the view radius, got %.2f", f)
+	}
+
+	// Desligada, não cobre nada
+	settings.Fog = false
+	if f := NewFog(settings, 4).Factor(1000); f != 0 {
+		t.Errorf("Disabled fog should have factor 0, got %.2f", f)
+	}
+}
+
+func TestFogSettingsClampAndDefaults(t *testing.T) {
+	settings := DefaultSettings()
+	settings.FogStart = -1
+	settings.FogEnd = 2
+	settings.Clamp()
+	if settings.FogStart != 0 || settings.FogEnd != 1 {
+		t.Errorf("Expected fog range clamped to 0..1, got %.2f..%.2f", settings.FogStart, settings.FogEnd)
+	}
+
+	// Fim antes do início vira um corte no início
+	settings.FogStart, settings.FogEnd = 0.8, 0.3
+	settings.Clamp()
+	if settings.FogEnd != 0.8 {
+		t.Errorf("Expected fog end raised to start 0.8, got %.2f", settings.FogEnd)
+	}
+
+	// Configurações antigas sem os campos da neblina ficam com o padrão
+	loaded := DefaultSettings()
+	if err := json.Unmarshal([]byte(`{"fov": 70, "mouse_sensitivity": 0.003}`), &loaded); err != nil {
+		t.Fatalf("Failed to parse settings: %v", err)
+	}
+	if !loaded.Fog || loaded.FogColor != DefaultFogColor || loaded.FogStart != DefaultFogStart || loaded.FogEnd != DefaultFogEnd {
+		t.Errorf("Expected default fog settings, got %+v", loaded)
+	}
+}
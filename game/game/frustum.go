@@ -0,0 +1,118 @@
+package game
+
+import (
+	"math"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// Planos de corte da projeção (mesmos padrões do raylib em BeginMode3D)
+const (
+	frustumNear = 0.01
+	frustumFar  = 1000.0
+)
+
+// Frustum são os 6 planos do volume de visão da câmera (esquerda, direita, baixo, cima, perto,
+// longe). Cada plano é (a, b, c, d) com a normal apontando para dentro: um ponto está do lado
+// de dentro quando a*x + b*y + c*z + d >= 0.
+type Frustum struct {
+	Planes [6]rl.Vector4
+}
+
+// NewCameraFrustum extrai o frustum da câmera com a proporção de tela informada, usando as
+// mesmas matrizes de view e projeção que o raylib monta em BeginMode3D
+func NewCameraFrustum(camera rl.Camera3D, aspect float32) Frustum {
+	view := rl.MatrixLookAt(camera.Position, camera.Target, camera.Up)
+
+	var projection rl.Matrix
+	if camera.Projection == rl.CameraOrthographic {
+		top := camera.Fovy / 2
+		right := top * aspect
+		projection = rl.MatrixOrtho(-right, right, -top, top, frustumNear, frustumFar)
+	} else {
+		projection = perspectiveMatrix(camera.Fovy, aspect, frustumNear, frustumFar)
+	}
+
+	return NewFrustumFromMatrix(rl.MatrixMultiply(view, projection))
+}
+
+// perspectiveMatrix projeção em perspectiva simétrica (fovy em graus), igual à do rlFrustum
+// usado pelo BeginMode3D. Não usa rl.MatrixPerspective: o MatrixFrustum do raylib-go calcula
+// right + left/rl em vez de (right + left)/rl e inclina a projeção.
+func perspectiveMatrix(fovy, aspect, near, far float32) rl.Matrix {
+	top := near * float32(math.Tan(float64(fovy)*math.Pi/360))
+	right := top * aspect
+
+	var m rl.Matrix
+	m.M0 = near / right
+	m.M5 = near / top
+	m.M10 = -(far + near) / (far - near)
+	m.M11 = -1
+	m.M14 = -(far * near * 2) / (far - near)
+	return m
+}
+
+// NewFrustumFromMatrix extrai os planos de uma matriz view-projeção (método de Gribb/Hartmann)
+func NewFrustumFromMatrix(m rl.Matrix) Frustum {
+	// Linhas da matriz (o raylib guarda por coluna: M0-M3 é a primeira coluna)
+	row0 := rl.NewVector4(m.M0, m.M4, m.M8, m.M12)
+	row1 := rl.NewVector4(m.M1, m.M5, m.M9, m.M13)
+	row2 := rl.NewVector4(m.M2, m.M6, m.M10, m.M14)
+	row3 := rl.NewVector4(m.M3, m.M7, m.M11, m.M15)
+
+	add := func(a, b rl.Vector4) rl.Vector4 {
+		return rl.NewVector4(a.X+b.X, a.Y+b.Y, a.Z+b.Z, a.W+b.W)
+	}
+	sub := func(a, b rl.Vector4) rl.Vector4 {
+		return rl.NewVector4(a.X-b.X, a.Y-b.Y, a.Z-b.Z, a.W-b.W)
+	}
+
+	f := Frustum{Planes: [6]rl.Vector4{
+		add(row3, row0), // Esquerda
+		sub(row3, row0), // Direita
+		add(row3, row1), // Baixo
+		sub(row3, row1), // Cima
+		add(row3, row2), // Perto
+		sub(row3, row2), // Longe
+	}}
+
+	// Normaliza para a distância ao plano ficar em unidades do mundo
+	for i, p := range f.Planes {
+		length := float32(math.Sqrt(float64(p.X*p.X + p.Y*p.Y + p.Z*p.Z)))
+		if length > 0 {
+			f.Planes[i] = rl.NewVector4(p.X/length, p.Y/length, p.Z/length, p.W/length)
+		}
+	}
+
+	return f
+}
+
+// IntersectsAABB indica se a caixa (min, max) está ao menos em parte dentro do frustum. O teste
+// é conservador: a caixa só é descartada quando fica inteira atrás de algum plano, então caixas
+// na borda da visão (ou em um canto fora dela) continuam sendo desenhadas.
+func (f Frustum) IntersectsAABB(min, max rl.Vector3) bool {
+	for _, p := range f.Planes {
+		// Vértice da caixa mais à frente na direção da normal do plano
+		x, y, z := min.X, min.Y, min.Z
+		if p.X >= 0 {
+			x = max.X
+		}
+		if p.Y >= 0 {
+			y = max.Y
+		}
+		if p.Z >= 0 {
+			z = max.Z
+		}
+		if p.X*x+p.Y*y+p.Z*z+p.W < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// ChunkBounds retorna a caixa (AABB) em coordenadas do mundo ocupada por um chunk
+func ChunkBounds(coord ChunkCoord) (min, max rl.Vector3) {
+	min = rl.NewVector3(float32(coord.X*ChunkSize), float32(coord.Y*ChunkHeight), float32(coord.Z*ChunkSize))
+	max = rl.NewVector3(min.X+ChunkSize, min.Y+ChunkHeight, min.Z+ChunkSize)
+	return min, max
+}
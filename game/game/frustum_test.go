@@ -0,0 +1,77 @@
+package game
+
+import (
+	"testing"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// Helper: câmera na origem olhando para +Z (60° de FOV vertical, proporção 16:9)
+func newTestFrustum(projection rl.CameraProjection) Frustum {
+	camera := rl.Camera3D{
+		Position:   rl.NewVector3(0, 0, 0),
+		Target:     rl.NewVector3(0, 0, 1),
+		Up:         rl.NewVector3(0, 1, 0),
+		Fovy:       60,
+		Projection: projection,
+	}
+	return NewCameraFrustum(camera, 16.0/9.0)
+}
+
+func TestFrustumIntersectsAABB(t *testing.T) {
+	frustum := newTestFrustum(rl.CameraPerspective)
+
+	// Em z=10 a visão vai de x=±10.26 e y=±5.77
+	tests := []struct {
+		name     string
+		min, max rl.Vector3
+		visible  bool
+	}{
+		{"inside", rl.NewVector3(-1, -1, 5), rl.NewVector3(1, 1, 7), true},
+		{"behind camera", rl.NewVector3(-1, -1, -7), rl.NewVector3(1, 1, -5), false},
+		{"outside side", rl.NewVector3(20, -1, 9), rl.NewVector3(22, 1, 11), false},
+		{"outside other side", rl.NewVector3(-22, -1, 9), rl.NewVector3(-20, 1, 11), false},
+		{"above", rl.NewVector3(-1, 20, 9), rl.NewVector3(1, 22, 11), false},
+		{"beyond far plane", rl.NewVector3(-1, -1, 2000), rl.NewVector3(1, 1, 2010), false},
+		{"straddling side plane", rl.NewVector3(8, -1, 9), rl.NewVector3(14, 1, 11), true},
+		{"straddling top plane", rl.NewVector3(-1, 5, 9), rl.NewVector3(1, 7, 11), true},
+		{"containing camera", rl.NewVector3(-1, -1, -1), rl.NewVector3(1, 1, 1), true},
+		{"just past the edge", rl.NewVector3(10.4, -1, 9.9), rl.NewVector3(11, 1, 10), false},
+	}
+
+	for _, tt := range tests {
+		if got := frustum.IntersectsAABB(tt.min, tt.max); got != tt.visible {
+			t.Errorf("%s: expected visible=%v, got %v", tt.name, tt.visible, got)
+		}
+	}
+}
+
+func TestFrustumChunkBounds(t *testing.T) {
+	frustum := newTestFrustum(rl.CameraPerspective)
+
+	// Chunk da câmera, à frente, atrás e vizinho lateral cuja borda entra na visão
+	for coord, visible := range map[ChunkCoord]bool{
+		{X: 0, Y: 0, Z: 0}:   true,
+		{X: -1, Y: -1, Z: 0}: true,
+		{X: 0, Y: 0, Z: 3}:   true,
+		{X: 0, Y: 0, Z: -2}:  false,
+		{X: 2, Y: 0, Z: 1}:   true,
+		{X: 4, Y: 0, Z: 1}:   false,
+	} {
+		if got := frustum.IntersectsAABB(ChunkBounds(coord)); got != visible {
+			t.Errorf("Chunk %v: expected visible=%v, got %v", coord, visible, got)
+		}
+	}
+}
+
+func TestFrustumOrthographic(t *testing.T) {
+	// Projeção ortográfica: a largura da visão não cresce com a distância
+	frustum := newTestFrustum(rl.CameraOrthographic)
+
+	if !frustum.IntersectsAABB(rl.NewVector3(-1, -1, 100), rl.NewVector3(1, 1, 101)) {
+		t.Error("Box in front of the orthographic camera should be visible")
+	}
+	if frustum.IntersectsAABB(rl.NewVector3(60, -1, 100), rl.NewVector3(61, 1, 101)) {
+		t.Error("Box beyond the orthographic width should be culled")
+	}
+}
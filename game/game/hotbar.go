@@ -0,0 +1,90 @@
+package game
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// HotbarSize número de slots da hotbar (teclas 1 a 9)
+const HotbarSize = 9
+
+// HotbarFile é o arquivo padrão onde a hotbar é persistida
+const HotbarFile = "hotbar.json"
+
+// BlockHotbar blocos à mão do jogador: o slot selecionado define o bloco colocado com o botão
+// direito (ver Player.PlaceBlockType)
+type BlockHotbar struct {
+	Slots    [HotbarSize]BlockType `json:"slots"`
+	Selected int                   `json:"selected"`
+}
+
+// NewBlockHotbar cria a hotbar com os blocos padrão
+func NewBlockHotbar() *BlockHotbar {
+	return &BlockHotbar{
+		Slots: [HotbarSize]BlockType{
+			BlockStone, BlockDirt, BlockGrass, BlockPlanks, BlockWood,
+			BlockBricks, BlockCobblestone, BlockGlass, BlockSand,
+		},
+	}
+}
+
+// Select seleciona um slot (0 a HotbarSize-1); fora do intervalo é ignorado
+func (h *BlockHotbar) Select(slot int) {
+	if slot >= 0 && slot < HotbarSize {
+		h.Selected = slot
+	}
+}
+
+// SelectedBlock retorna o bloco do slot selecionado
+func (h *BlockHotbar) SelectedBlock() BlockType {
+	return h.Slots[h.Selected]
+}
+
+// Assign coloca um tipo de bloco em um slot
+func (h *BlockHotbar) Assign(slot int, block BlockType) error {
+	if slot < 0 || slot >= HotbarSize {
+		return fmt.Errorf("invalid hotbar slot %d", slot)
+	}
+	if block == BlockAir {
+		return fmt.Errorf("cannot assign air to hotbar slot %d", slot)
+	}
+	h.Slots[slot] = block
+	return nil
+}
+
+// LoadBlockHotbar carrega a hotbar de um arquivo JSON.
+// Se o arquivo não existir, retorna a hotbar padrão.
+func LoadBlockHotbar(path string) (*BlockHotbar, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return NewBlockHotbar(), nil
+	}
+	if err != nil {
+		return NewBlockHotbar(), fmt.Errorf("failed to read hotbar: %w", err)
+	}
+
+	hotbar := NewBlockHotbar()
+	if err := json.Unmarshal(data, hotbar); err != nil {
+		return NewBlockHotbar(), fmt.Errorf("failed to parse hotbar: %w", err)
+	}
+	if hotbar.Selected < 0 || hotbar.Selected >= HotbarSize {
+		hotbar.Selected = 0
+	}
+	return hotbar, nil
+}
+
+// Save salva a hotbar em um arquivo JSON
+func (h *BlockHotbar) Save(path string) error {
+	data, err := json.MarshalIndent(h, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal hotbar: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write hotbar: %w", err)
+	}
+
+	return nil
+}
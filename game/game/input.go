@@ -17,6 +17,7 @@ type Input interface {
 	IsFlyDownPressed() bool
 	IsCameraTogglePressed() bool
 	IsCollisionTogglePressed() bool
+	IsRotatePressed() bool
 	GetMouseDelta() rl.Vector2
 }
 
@@ -75,6 +76,10 @@ func (r *RaylibInput) IsCollisionTogglePressed() bool {
 	return rl.IsKeyPressed(rl.KeyK)
 }
 
+func (r *RaylibInput) IsRotatePressed() bool {
+	return rl.IsKeyPressed(rl.KeyR)
+}
+
 // SimulatedInput implementa Input para testes
 type SimulatedInput struct {
 	Forward         bool
@@ -89,6 +94,7 @@ type SimulatedInput struct {
 	FlyDown         bool
 	CameraToggle    bool
 	CollisionToggle bool
+	Rotate          bool
 	MouseDelta      rl.Vector2
 }
 
@@ -157,3 +163,9 @@ func (s *SimulatedInput) IsCollisionTogglePressed() bool {
 	s.CollisionToggle = false
 	return result
 }
+
+func (s *SimulatedInput) IsRotatePressed() bool {
+	result := s.Rotate
+	s.Rotate = false
+	return result
+}
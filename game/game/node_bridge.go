@@ -0,0 +1,170 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultNodePollInterval intervalo padrão de consulta ao nó
+const DefaultNodePollInterval = time.Second
+
+// MaxNodeBackfill máximo de blocos pulados entre duas consultas buscados em /api/blocks; de
+// intervalos maiores só os mais recentes viram eventos
+const MaxNodeBackfill = 32
+
+// NodeBlockFeed conecta o jogo a um nó da blockchain via API HTTP (/api/lastblock, e
+// /api/blocks para os blocos pulados entre duas consultas)
+// e emite um BlockEvent a cada novo bloco observado
+type NodeBlockFeed struct {
+	BaseURL      string
+	Username     string
+	Password     string
+	PollInterval time.Duration
+
+	client     *http.Client
+	events     chan BlockEvent
+	stopChan   chan struct{}
+	wg         sync.WaitGroup
+	lastHeight uint64
+	hasLast    bool
+}
+
+// lastBlockResponse resposta de /api/lastblock
+type lastBlockResponse struct {
+	Height    uint64 `json:"height"`
+	Hash      string `json:"hash"`
+	Validator string `json:"validator"`
+}
+
+// blockRangeResponse resposta de /api/blocks (só os campos usados)
+type blockRangeResponse struct {
+	Blocks []struct {
+		Height    uint64 `json:"height"`
+		Hash      string `json:"hash"`
+		Validator string `json:"validator"`
+	} `json:"blocks"`
+}
+
+// NewNodeBlockFeed cria um feed para o nó em baseURL (ex: http://localhost:8080)
+func NewNodeBlockFeed(baseURL, username, password string) *NodeBlockFeed {
+	return &NodeBlockFeed{
+		BaseURL:      strings.TrimRight(baseURL, "/"),
+		Username:     username,
+		Password:     password,
+		PollInterval: DefaultNodePollInterval,
+		client:       &http.Client{Timeout: 2 * time.Second},
+		events:       make(chan BlockEvent, 64),
+		stopChan:     make(chan struct{}),
+	}
+}
+
+// Events retorna o canal de blocos minerados
+func (f *NodeBlockFeed) Events() <-chan BlockEvent {
+	return f.events
+}
+
+// Start inicia a consulta periódica ao nó em background
+func (f *NodeBlockFeed) Start() {
+	f.wg.Add(1)
+	go f.pollLoop()
+}
+
+// Stop encerra a consulta e fecha o canal de eventos
+func (f *NodeBlockFeed) Stop() {
+	close(f.stopChan)
+	f.wg.Wait()
+	close(f.events)
+}
+
+// pollLoop consulta o nó até Stop ser chamado
+func (f *NodeBlockFeed) pollLoop() {
+	defer f.wg.Done()
+
+	ticker := time.NewTicker(f.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := f.poll(); err != nil {
+			// Nó indisponível não deve derrubar o jogo
+			fmt.Printf("Node feed: %v\n", err)
+		}
+
+		select {
+		case <-f.stopChan:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// get consulta a API do nó e decodifica a resposta JSON em out
+func (f *NodeBlockFeed) get(path string, out interface{}) error {
+	req, err := http.NewRequest(http.MethodGet, f.BaseURL+path, nil)
+	if err != nil {
+		return err
+	}
+	if f.Username != "" {
+		req.SetBasicAuth(f.Username, f.Password)
+	}
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("invalid response: %w", err)
+	}
+	return nil
+}
+
+// poll busca o último bloco e emite um evento para cada bloco novo desde a consulta anterior
+func (f *NodeBlockFeed) poll() error {
+	var last lastBlockResponse
+	if err := f.get("/api/lastblock", &last); err != nil {
+		return err
+	}
+
+	if f.hasLast && last.Height == f.lastHeight {
+		return nil
+	}
+
+	// Blocos produzidos entre as duas consultas (no máximo MaxNodeBackfill); se a busca
+	// falhar, segue só com o último
+	if f.hasLast && last.Height > f.lastHeight+1 {
+		from := f.lastHeight + 1
+		if last.Height-from > MaxNodeBackfill {
+			from = last.Height - MaxNodeBackfill
+		}
+		var skipped blockRangeResponse
+		if err := f.get(fmt.Sprintf("/api/blocks?from=%d&to=%d", from, last.Height-1), &skipped); err != nil {
+			fmt.Printf("Node feed: failed to fetch blocks %d-%d: %v\n", from, last.Height-1, err)
+		}
+		for _, block := range skipped.Blocks {
+			f.emit(BlockEvent{Height: block.Height, Hash: block.Hash, Validator: block.Validator})
+		}
+	}
+
+	f.lastHeight = last.Height
+	f.hasLast = true
+	f.emit(BlockEvent{Height: last.Height, Hash: last.Hash, Validator: last.Validator})
+	return nil
+}
+
+// emit envia o evento sem bloquear
+func (f *NodeBlockFeed) emit(event BlockEvent) {
+	select {
+	case f.events <- event:
+	default:
+		// Jogo não está consumindo; descartar para não bloquear
+	}
+}
@@ -0,0 +1,249 @@
+package game
+
+import (
+	"fmt"
+	"hash/fnv"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// Limites padrão da torre do modo observador
+const (
+	DefaultObserverSide      = 8   // Largura (em blocos) de cada camada da torre
+	DefaultObserverMaxLayers = 24  // Camadas da torre acima da origem
+	DefaultObserverMaxBlocks = 512 // Blocos mantidos no mundo; os mais antigos são removidos
+)
+
+// validatorBlockPalette blocos opacos usados para colorir a torre por validador
+var validatorBlockPalette = []BlockType{
+	BlockGoldOre, BlockDiamondOre, BlockIronOre, BlockBricks,
+	BlockPlanks, BlockCoal, BlockClay, BlockMoss,
+	BlockObsidian, BlockSnow, BlockCobblestone, BlockWood,
+}
+
+// validatorHash hash estável do endereço de um validador
+func validatorHash(address string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(address))
+	return h.Sum64()
+}
+
+// ValidatorColor retorna a cor de um validador, derivada do endereço (sempre a mesma para o
+// mesmo endereço, com saturação e brilho fixos para ficar visível contra o céu e o terreno)
+func ValidatorColor(address string) rl.Color {
+	return rl.ColorFromHSV(float32(validatorHash(address)%360), 0.75, 0.9)
+}
+
+// ValidatorBlockType retorna o tipo de bloco que representa um validador na torre
+func ValidatorBlockType(address string) BlockType {
+	return validatorBlockPalette[(validatorHash(address)>>16)%uint64(len(validatorBlockPalette))]
+}
+
+// ObserverBlock bloco da torre que representa um bloco da chain
+type ObserverBlock struct {
+	Event  BlockEvent
+	X      int32
+	Y      int32
+	Z      int32
+	Type   BlockType
+	Placed bool // true quando o bloco já foi escrito em um chunk carregado
+
+	written bool // Já foi escrito no mundo alguma vez (chunks editados guardam o bloco ao descarregar)
+}
+
+// ObserverMode acompanha um nó e empilha, a partir da origem do mundo, um bloco por bloco da
+// chain com a cor do validador que o produziu. A torre ocupa Side x Side blocos por camada e
+// até MaxLayers camadas; passando de MaxBlocks, os blocos mais antigos são removidos e a
+// sequência de posições recomeça da primeira camada.
+type ObserverMode struct {
+	Source BlockEventSource
+
+	// Base da torre (centro da primeira camada)
+	OriginX, OriginY, OriginZ int32
+
+	Side      int32
+	MaxLayers int32
+	MaxBlocks int
+
+	Blocks    []*ObserverBlock // Do mais antigo para o mais novo
+	LastEvent *BlockEvent
+
+	// Blocos produzidos por validador desde o início da observação
+	BlocksByValidator map[string]int
+
+	next     int              // Índice da próxima posição na sequência
+	observed int              // Eventos recebidos desde o início
+	removals []*ObserverBlock // Blocos descartados ainda por apagar do mundo
+}
+
+// NewObserverMode cria o modo observador com a torre em (x, y, z). source pode ser nil.
+func NewObserverMode(source BlockEventSource, x, y, z int32) *ObserverMode {
+	return &ObserverMode{
+		Source:            source,
+		OriginX:           x,
+		OriginY:           y,
+		OriginZ:           z,
+		Side:              DefaultObserverSide,
+		MaxLayers:         DefaultObserverMaxLayers,
+		MaxBlocks:         DefaultObserverMaxBlocks,
+		Blocks:            make([]*ObserverBlock, 0),
+		BlocksByValidator: make(map[string]int),
+	}
+}
+
+// capacity número de posições da torre
+func (om *ObserverMode) capacity() int {
+	return int(om.Side * om.Side * om.MaxLayers)
+}
+
+// PositionFor retorna a posição do index-ésimo bloco da sequência: camada a camada de baixo
+// para cima, cada camada linha a linha, voltando à primeira camada depois da última
+func (om *ObserverMode) PositionFor(index int) (x, y, z int32) {
+	i := int32(index % om.capacity())
+	perLayer := om.Side * om.Side
+	layer, inLayer := i/perLayer, i%perLayer
+
+	x = om.OriginX - om.Side/2 + inLayer%om.Side
+	z = om.OriginZ - om.Side/2 + inLayer/om.Side
+	y = om.OriginY + layer
+	return
+}
+
+// HandleEvent registra um bloco da chain na próxima posição da torre (sem tocar no mundo ainda)
+func (om *ObserverMode) HandleEvent(event BlockEvent) *ObserverBlock {
+	if om == nil {
+		return nil
+	}
+
+	x, y, z := om.PositionFor(om.next)
+	om.next = (om.next + 1) % om.capacity()
+
+	block := &ObserverBlock{Event: event, X: x, Y: y, Z: z, Type: ValidatorBlockType(event.Validator)}
+	om.Blocks = append(om.Blocks, block)
+
+	// Nunca mais blocos que posições: a sequência reutiliza a posição do mais antigo
+	maxBlocks := om.MaxBlocks
+	if maxBlocks <= 0 || maxBlocks > om.capacity() {
+		maxBlocks = om.capacity()
+	}
+	if len(om.Blocks) > maxBlocks {
+		evicted := len(om.Blocks) - maxBlocks
+		for _, old := range om.Blocks[:evicted] {
+			if old.written && !(old.X == x && old.Y == y && old.Z == z) {
+				om.removals = append(om.removals, old)
+			}
+		}
+		om.Blocks = om.Blocks[evicted:]
+	}
+
+	om.observed++
+	if event.Validator != "" {
+		om.BlocksByValidator[event.Validator]++
+	}
+	om.LastEvent = &block.Event
+	return block
+}
+
+// HeaviestValidator retorna o validador que mais produziu blocos desde o início da observação
+// (no empate, o de menor endereço)
+func (om *ObserverMode) HeaviestValidator() (address string, blocks int) {
+	if om == nil {
+		return "", 0
+	}
+	for validator, count := range om.BlocksByValidator {
+		if count > blocks || (count == blocks && validator < address) {
+			address, blocks = validator, count
+		}
+	}
+	return address, blocks
+}
+
+// Observed retorna quantos blocos da chain foram observados desde o início (inclusive os já
+// removidos da torre)
+func (om *ObserverMode) Observed() int {
+	if om == nil {
+		return 0
+	}
+	return om.observed
+}
+
+// Update consome eventos pendentes e coloca (ou apaga) os blocos da torre nos chunks carregados
+func (om *ObserverMode) Update(world *World) {
+	if om == nil {
+		return
+	}
+
+	if om.Source != nil {
+		events := om.Source.Events()
+	drain:
+		for i := 0; i < blockMarkerEventsMax; i++ {
+			select {
+			case event, ok := <-events:
+				if !ok {
+					om.Source = nil
+					break drain
+				}
+				om.HandleEvent(event)
+			default:
+				break drain
+			}
+		}
+	}
+
+	if world == nil || world.ChunkManager == nil {
+		return
+	}
+
+	// Só escrever em chunks já gerados, como o BlockViewer
+	loaded := func(x, y, z int32) bool {
+		_, ok := world.ChunkManager.Chunks[GetChunkCoord(x, y, z).Key()]
+		return ok
+	}
+
+	// A torre é local: escreve direto no ChunkManager, sem passar por World.OnBlockEdit
+	pending := om.removals[:0]
+	for _, old := range om.removals {
+		if !loaded(old.X, old.Y, old.Z) {
+			pending = append(pending, old)
+			continue
+		}
+		world.ChunkManager.SetBlock(old.X, old.Y, old.Z, BlockAir)
+	}
+	om.removals = pending
+
+	for _, block := range om.Blocks {
+		if !loaded(block.X, block.Y, block.Z) {
+			block.Placed = false
+			continue
+		}
+		if !block.Placed {
+			world.ChunkManager.SetBlock(block.X, block.Y, block.Z, block.Type)
+			block.Placed = true
+			block.written = true
+		}
+	}
+}
+
+// Render desenha o contorno de cada bloco da torre na cor do seu validador (deve ser chamado
+// dentro de BeginMode3D)
+func (om *ObserverMode) Render() {
+	if om == nil {
+		return
+	}
+
+	for _, block := range om.Blocks {
+		if !block.Placed {
+			continue
+		}
+		center := rl.NewVector3(float32(block.X)+0.5, float32(block.Y)+0.5, float32(block.Z)+0.5)
+		rl.DrawCubeWiresV(center, rl.NewVector3(1.05, 1.05, 1.05), ValidatorColor(block.Event.Validator))
+	}
+}
+
+// ShortAddress abrevia um endereço para a UI
+func ShortAddress(address string) string {
+	if len(address) <= 12 {
+		return address
+	}
+	return fmt.Sprintf("%s…%s", address[:6], address[len(address)-4:])
+}
@@ -0,0 +1,209 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestValidatorColor_Deterministic(t *testing.T) {
+	inPalette := func(bt BlockType) bool {
+		for _, p := range validatorBlockPalette {
+			if p == bt {
+				return true
+			}
+		}
+		return false
+	}
+
+	colors := make(map[[3]uint8]bool)
+	for i := 0; i < 50; i++ {
+		addr := fmt.Sprintf("validator-%d", i)
+
+		c1, c2 := ValidatorColor(addr), ValidatorColor(addr)
+		if c1 != c2 {
+			t.Fatalf("Color not deterministic for %s: %v vs %v", addr, c1, c2)
+		}
+		if c1.A != 255 {
+			t.Errorf("Validator color should be opaque, got alpha %d", c1.A)
+		}
+		colors[[3]uint8{c1.R, c1.G, c1.B}] = true
+
+		if ValidatorBlockType(addr) != ValidatorBlockType(addr) {
+			t.Fatalf("Block type not deterministic for %s", addr)
+		}
+		if !inPalette(ValidatorBlockType(addr)) {
+			t.Errorf("Block type %d for %s is not in the palette", ValidatorBlockType(addr), addr)
+		}
+	}
+
+	// Validadores diferentes devem ter cores diferentes
+	if len(colors) < 40 {
+		t.Errorf("Expected validator colors to spread, got %d distinct colors for 50 validators", len(colors))
+	}
+}
+
+func TestObserverMode_PositionSequence(t *testing.T) {
+	observer := NewObserverMode(nil, 10, 20, 30)
+	observer.Side = 2
+	observer.MaxLayers = 2
+
+	expected := [][3]int32{
+		{9, 20, 29}, {10, 20, 29}, {9, 20, 30}, {10, 20, 30}, // Primeira camada, linha a linha
+		{9, 21, 29}, {10, 21, 29}, {9, 21, 30}, {10, 21, 30}, // Segunda camada
+		{9, 20, 29}, // Volta para a primeira posição
+	}
+	for i, want := range expected {
+		x, y, z := observer.PositionFor(i)
+		if [3]int32{x, y, z} != want {
+			t.Errorf("PositionFor(%d) = (%d, %d, %d), want %v", i, x, y, z, want)
+		}
+	}
+}
+
+func TestObserverMode_NilSafe(t *testing.T) {
+	world := createFlatWorld()
+
+	var nilObserver *ObserverMode
+	nilObserver.Update(world)
+	nilObserver.Render()
+	if nilObserver.HandleEvent(BlockEvent{Height: 1, Validator: "v"}) != nil {
+		t.Error("Nil observer should not create blocks")
+	}
+	if addr, count := nilObserver.HeaviestValidator(); addr != "" || count != 0 {
+		t.Errorf("Nil observer should have no heaviest validator, got %s (%d)", addr, count)
+	}
+
+	observer := NewObserverMode(nil, 0, 12, 0)
+	observer.Update(world)
+	observer.Update(nil)
+	if len(observer.Blocks) != 0 {
+		t.Errorf("Expected no blocks without a node, got %d", len(observer.Blocks))
+	}
+}
+
+func TestObserverMode_PlacesBlocksFromEvents(t *testing.T) {
+	world := createChunkedFlatWorld()
+	source := &fakeBlockSource{events: make(chan BlockEvent, 8)}
+	observer := NewObserverMode(source, 0, 11, 0)
+	observer.Side = 2
+	observer.MaxLayers = 2
+
+	validators := []string{"alice", "bob", "alice"}
+	for i, v := range validators {
+		source.events <- BlockEvent{Height: uint64(i + 1), Hash: fmt.Sprintf("hash-%d", i), Validator: v}
+	}
+	observer.Update(world)
+
+	if len(observer.Blocks) != len(validators) {
+		t.Fatalf("Expected %d blocks, got %d", len(validators), len(observer.Blocks))
+	}
+	for i, block := range observer.Blocks {
+		x, y, z := observer.PositionFor(i)
+		if block.X != x || block.Y != y || block.Z != z {
+			t.Errorf("Block %d at (%d, %d, %d), want (%d, %d, %d)", i, block.X, block.Y, block.Z, x, y, z)
+		}
+		if !block.Placed {
+			t.Errorf("Block %d in a loaded chunk should be placed", i)
+		}
+		if got := world.GetBlock(x, y, z); got != ValidatorBlockType(validators[i]) {
+			t.Errorf("Block %d: world has %d, want validator block %d", i, got, ValidatorBlockType(validators[i]))
+		}
+	}
+
+	if observer.LastEvent == nil || observer.LastEvent.Height != 3 {
+		t.Error("LastEvent should track the most recent block")
+	}
+	if addr, count := observer.HeaviestValidator(); addr != "alice" || count != 2 {
+		t.Errorf("Expected alice with 2 blocks as heaviest, got %s (%d)", addr, count)
+	}
+	if observer.Observed() != 3 {
+		t.Errorf("Expected 3 observed blocks, got %d", observer.Observed())
+	}
+}
+
+func TestObserverMode_EvictsOldestBlocks(t *testing.T) {
+	world := createChunkedFlatWorld()
+	source := &fakeBlockSource{events: make(chan BlockEvent, 8)}
+	observer := NewObserverMode(source, 0, 11, 0)
+	observer.Side = 2
+	observer.MaxLayers = 2
+	observer.MaxBlocks = 3
+
+	for i := 0; i < 3; i++ {
+		source.events <- BlockEvent{Height: uint64(i + 1), Validator: "alice"}
+	}
+	observer.Update(world)
+	firstX, firstY, firstZ := observer.PositionFor(0)
+
+	// O quarto bloco remove o primeiro do mundo
+	source.events <- BlockEvent{Height: 4, Validator: "bob"}
+	observer.Update(world)
+
+	if len(observer.Blocks) != 3 {
+		t.Fatalf("Expected 3 blocks after eviction, got %d", len(observer.Blocks))
+	}
+	if observer.Blocks[0].Event.Height != 2 {
+		t.Errorf("Oldest block should have been evicted, first is now height %d", observer.Blocks[0].Event.Height)
+	}
+	if got := world.GetBlock(firstX, firstY, firstZ); got != BlockAir {
+		t.Errorf("Evicted block should be removed from the world, got %d", got)
+	}
+	x, y, z := observer.PositionFor(3)
+	if got := world.GetBlock(x, y, z); got != ValidatorBlockType("bob") {
+		t.Errorf("Newest block should be placed, got %d", got)
+	}
+
+	// Blocos removidos continuam contando para o validador
+	if observer.BlocksByValidator["alice"] != 3 || observer.Observed() != 4 {
+		t.Errorf("Expected 3 blocks by alice and 4 observed, got %d and %d", observer.BlocksByValidator["alice"], observer.Observed())
+	}
+}
+
+func TestNodeBlockFeed_BackfillsSkippedBlocks(t *testing.T) {
+	height := uint64(1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/lastblock":
+			json.NewEncoder(w).Encode(map[string]interface{}{"height": height, "hash": fmt.Sprintf("h%d", height), "validator": "v"})
+		case "/api/blocks":
+			if r.URL.Query().Get("from") != "2" || r.URL.Query().Get("to") != "3" {
+				t.Errorf("Unexpected range query: %s", r.URL.RawQuery)
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"blocks": []map[string]interface{}{
+				{"height": 2, "hash": "h2", "validator": "a"},
+				{"height": 3, "hash": "h3", "validator": "b"},
+			}})
+		}
+	}))
+	defer server.Close()
+
+	feed := NewNodeBlockFeed(server.URL, "", "")
+	if err := feed.poll(); err != nil {
+		t.Fatalf("poll failed: %v", err)
+	}
+	height = 4
+	if err := feed.poll(); err != nil {
+		t.Fatalf("poll failed: %v", err)
+	}
+
+	var heights []uint64
+	timeout := time.After(time.Second)
+	for len(heights) < 4 {
+		select {
+		case event := <-feed.Events():
+			heights = append(heights, event.Height)
+		case <-timeout:
+			t.Fatalf("Expected 4 events, got %v", heights)
+		}
+	}
+	for i, h := range heights {
+		if h != uint64(i+1) {
+			t.Errorf("Expected events in height order, got %v", heights)
+			break
+		}
+	}
+}
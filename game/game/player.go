@@ -106,6 +106,11 @@ type Player struct {
 	LookingAtBlock      bool
 	TargetBlock         rl.Vector3
 	PlaceBlock          rl.Vector3
+	PlaceBlockType      BlockType     // Tipo de bloco colocado com o botão direito
+	PlaceRotation       BlockRotation // Rotação do próximo bloco colocado (tecla R)
+	LookingAtEntity     bool
+	TargetEntity        *Entity
+	InteractAnimation   int // Animação do modelo tocada ao interagir com uma entidade (-1 = nenhuma)
 	Height              float32
 	Radius              float32
 	CameraDistance      float32
@@ -115,7 +120,10 @@ type Player struct {
 	FlyMode             bool
 	ShowCollisionBody   bool
 	Model               *PlayerModel
-	ModelOpacity        float32 // Opacidade do modelo (0.0 = transparente, 1.0 = opaco)
+	ModelOpacity        float32  // Opacidade do modelo (0.0 = transparente, 1.0 = opaco)
+	Settings            Settings // FOV, sensibilidade e inversão do mouse
+	ReachDistance       float32  // Alcance para mirar/quebrar/colocar blocos andando
+	FlyReachDistance    float32  // Alcance no fly mode
 }
 
 func NewPlayer(position rl.Vector3) *Player {
@@ -130,6 +138,11 @@ func NewPlayer(position rl.Vector3) *Player {
 		ThirdPersonDistance: 5.0,
 		FirstPersonDistance: 0.35,
 		ModelOpacity:        1.0, // Começa opaco
+		InteractAnimation:   -1,
+		PlaceBlockType:      BlockStone,
+		Settings:            DefaultSettings(),
+		ReachDistance:       DefaultReachDistance,
+		FlyReachDistance:    DefaultReachDistance,
 	}
 
 	// Carregar modelo 3D do player
@@ -140,13 +153,30 @@ func NewPlayer(position rl.Vector3) *Player {
 		Position:   rl.NewVector3(position.X, position.Y+2, position.Z+5),
 		Target:     rl.NewVector3(position.X, position.Y+1, position.Z),
 		Up:         rl.NewVector3(0, 1, 0),
-		Fovy:       60.0,
+		Fovy:       player.Settings.FOV,
 		Projection: rl.CameraPerspective,
 	}
 
 	return player
 }
 
+// ApplySettings aplica novas configurações (limitadas aos intervalos válidos) ao jogador e à câmera
+func (p *Player) ApplySettings(settings Settings) {
+	settings.Clamp()
+	p.Settings = settings
+	p.Camera.Fovy = settings.FOV
+	p.ReachDistance = settings.ReachDistance
+	p.FlyReachDistance = settings.FlyReachDistance
+}
+
+// Reach retorna o alcance atual do jogador (FlyReachDistance no fly mode)
+func (p *Player) Reach() float32 {
+	if p.FlyMode {
+		return p.FlyReachDistance
+	}
+	return p.ReachDistance
+}
+
 func (p *Player) Update(dt float32, world *World, input Input) {
 	// Atualizar animação do modelo 3D
 	if p.Model != nil && p.Model.IsLoaded {
@@ -172,9 +202,17 @@ func (p *Player) Update(dt float32, world *World, input Input) {
 		p.ShowCollisionBody = !p.ShowCollisionBody
 	}
 
+	// Girar o próximo bloco a colocar com tecla R
+	if input.IsRotatePressed() {
+		p.PlaceRotation = p.PlaceRotation.Next()
+	}
+
 	// Controle do mouse
 	mouseDelta := input.GetMouseDelta()
-	sensitivity := float32(0.003)
+	sensitivity := p.Settings.MouseSensitivity
+	if p.Settings.InvertY {
+		mouseDelta.Y = -mouseDelta.Y
+	}
 
 	p.Yaw -= mouseDelta.X * sensitivity
 	p.Pitch -= mouseDelta.Y * sensitivity // Mantém sensação natural em primeira e terceira pessoa
@@ -260,8 +298,13 @@ func (p *Player) Update(dt float32, world *World, input Input) {
 	// Atualizar câmera considerando colisões e transições suaves
 	p.updateCamera(dt, world)
 
-	// Raycasting para colocar/remover blocos
-	p.RaycastBlocks(world)
+	// Raycasting para colocar/remover blocos e mirar entidades
+	p.Raycast(world)
+
+	// Interação com entidades
+	if p.LookingAtEntity && input.IsLeftClickPressed() {
+		p.InteractWith(p.TargetEntity)
+	}
 
 	// InteraÃ§Ã£o com blocos
 	if input.IsLeftClickPressed() && p.LookingAtBlock {
@@ -269,7 +312,7 @@ func (p *Player) Update(dt float32, world *World, input Input) {
 		world.SetBlock(int32(p.TargetBlock.X), int32(p.TargetBlock.Y), int32(p.TargetBlock.Z), BlockAir)
 	}
 
-	if input.IsRightClickPressed() && p.LookingAtBlock {
+	if input.IsRightClickPressed() && p.LookingAtBlock && p.canPlaceAt(world, p.PlaceBlock) {
 		// Colocar bloco - mas verificar se não colide com o jogador
 		placePos := rl.NewVector3(
 			float32(int32(p.PlaceBlock.X))+0.5,
@@ -279,7 +322,7 @@ func (p *Player) Update(dt float32, world *World, input Input) {
 
 		// Verificar se o bloco que vai ser colocado não colide com o jogador
 		if !p.wouldBlockCollideWithPlayer(placePos) {
-			world.SetBlock(int32(p.PlaceBlock.X), int32(p.PlaceBlock.Y), int32(p.PlaceBlock.Z), BlockStone)
+			world.SetBlockWithRotation(int32(p.PlaceBlock.X), int32(p.PlaceBlock.Y), int32(p.PlaceBlock.Z), p.PlaceBlockType, p.PlaceRotation)
 		}
 	}
 }
@@ -603,111 +646,42 @@ func (p *Player) CheckCollision(newPos rl.Vector3, world *World) bool {
 	return false
 }
 
-func (p *Player) RaycastBlocks(world *World) {
-	// Raycast diretamente da cÃ¢mera na direÃ§Ã£o que ela estÃ¡ apontando
-	// Isso garante que o raycast sempre acerte onde o crosshair aponta
-	rayOrigin := p.Camera.Position
-	rayDir := rl.Vector3Normalize(rl.Vector3Subtract(p.Camera.Target, p.Camera.Position))
-
-	maxDistance := float32(10.0)
-	p.LookingAtBlock = false
-
-	// PosiÃ§Ã£o inicial do voxel
-	voxelX := int32(math.Floor(float64(rayOrigin.X)))
-	voxelY := int32(math.Floor(float64(rayOrigin.Y)))
-	voxelZ := int32(math.Floor(float64(rayOrigin.Z)))
-
-	// DireÃ§Ã£o do passo (1 ou -1)
-	stepX := int32(1)
-	if rayDir.X < 0 {
-		stepX = -1
+// InteractWith interage com a entidade: toca a animação de interação e chama OnInteract
+func (p *Player) InteractWith(entity *Entity) {
+	if p.Model != nil {
+		p.Model.SetAnimation(p.InteractAnimation)
 	}
-	stepY := int32(1)
-	if rayDir.Y < 0 {
-		stepY = -1
+	if entity.OnInteract != nil {
+		entity.OnInteract(p)
 	}
-	stepZ := int32(1)
-	if rayDir.Z < 0 {
-		stepZ = -1
-	}
-
-	// Calcular tMax e tDelta
-	var tMaxX, tMaxY, tMaxZ float32
-	var tDeltaX, tDeltaY, tDeltaZ float32
+}
 
-	if rayDir.X != 0 {
-		if rayDir.X > 0 {
-			tMaxX = (float32(voxelX+1) - rayOrigin.X) / rayDir.X
-		} else {
-			tMaxX = (float32(voxelX) - rayOrigin.X) / rayDir.X
-		}
-		tDeltaX = float32(math.Abs(float64(1.0 / rayDir.X)))
-	} else {
-		tMaxX = float32(math.MaxFloat32)
-		tDeltaX = float32(math.MaxFloat32)
-	}
+// Raycast atualiza o alvo do jogador: o bloco ou a entidade mais próxima na direção da câmera
+func (p *Player) Raycast(world *World) {
+	// Raycast diretamente da câmera na direção que ela está apontando
+	// Isso garante que o raycast sempre acerte onde o crosshair aponta
+	rayOrigin := p.Camera.Position
+	rayDir := rl.Vector3Normalize(rl.Vector3Subtract(p.Camera.Target, p.Camera.Position))
 
-	if rayDir.Y != 0 {
-		if rayDir.Y > 0 {
-			tMaxY = (float32(voxelY+1) - rayOrigin.Y) / rayDir.Y
-		} else {
-			tMaxY = (float32(voxelY) - rayOrigin.Y) / rayDir.Y
-		}
-		tDeltaY = float32(math.Abs(float64(1.0 / rayDir.Y)))
-	} else {
-		tMaxY = float32(math.MaxFloat32)
-		tDeltaY = float32(math.MaxFloat32)
-	}
+	hit := world.Raycast(rayOrigin, rayDir, p.Reach())
 
-	if rayDir.Z != 0 {
-		if rayDir.Z > 0 {
-			tMaxZ = (float32(voxelZ+1) - rayOrigin.Z) / rayDir.Z
-		} else {
-			tMaxZ = (float32(voxelZ) - rayOrigin.Z) / rayDir.Z
-		}
-		tDeltaZ = float32(math.Abs(float64(1.0 / rayDir.Z)))
-	} else {
-		tMaxZ = float32(math.MaxFloat32)
-		tDeltaZ = float32(math.MaxFloat32)
+	p.LookingAtBlock = hit.Hit && hit.Entity == nil
+	p.LookingAtEntity = hit.Entity != nil
+	p.TargetEntity = hit.Entity
+	if p.LookingAtBlock {
+		p.TargetBlock = hit.Block
+		p.PlaceBlock = hit.Place
 	}
+}
 
-	// Armazenar voxel anterior para colocaÃ§Ã£o de blocos
-	prevVoxelX, prevVoxelY, prevVoxelZ := voxelX, voxelY, voxelZ
-
-	// DDA traversal
-	for t := float32(0); t < maxDistance; {
-		// Verificar se o voxel atual contÃ©m um bloco
-		if world.GetBlock(voxelX, voxelY, voxelZ) != BlockAir {
-			p.LookingAtBlock = true
-			p.TargetBlock = rl.NewVector3(float32(voxelX), float32(voxelY), float32(voxelZ))
-			p.PlaceBlock = rl.NewVector3(float32(prevVoxelX), float32(prevVoxelY), float32(prevVoxelZ))
-			return
-		}
-
-		// Armazenar voxel atual antes de avanÃ§ar
-		prevVoxelX, prevVoxelY, prevVoxelZ = voxelX, voxelY, voxelZ
-
-		// AvanÃ§ar para o prÃ³ximo voxel
-		if tMaxX < tMaxY {
-			if tMaxX < tMaxZ {
-				voxelX += stepX
-				t = tMaxX
-				tMaxX += tDeltaX
-			} else {
-				voxelZ += stepZ
-				t = tMaxZ
-				tMaxZ += tDeltaZ
-			}
-		} else {
-			if tMaxY < tMaxZ {
-				voxelY += stepY
-				t = tMaxY
-				tMaxY += tDeltaY
-			} else {
-				voxelZ += stepZ
-				t = tMaxZ
-				tMaxZ += tDeltaZ
-			}
-		}
+// canPlaceAt indica se dá para colocar um bloco em pos: o voxel precisa estar vazio e encostado
+// por uma face no bloco mirado, para nunca colocar no ar além do último voxel atingido
+func (p *Player) canPlaceAt(world *World, pos rl.Vector3) bool {
+	dx := math.Abs(float64(pos.X - p.TargetBlock.X))
+	dy := math.Abs(float64(pos.Y - p.TargetBlock.Y))
+	dz := math.Abs(float64(pos.Z - p.TargetBlock.Z))
+	if dx+dy+dz != 1 {
+		return false
 	}
+	return world.GetBlock(int32(pos.X), int32(pos.Y), int32(pos.Z)) == BlockAir
 }
@@ -207,3 +207,82 @@ func BenchmarkBlockCollisionCheck(b *testing.B) {
 		player.wouldBlockCollideWithPlayer(blockPos)
 	}
 }
+
+// TestPlayerReachDistance verifica que o raycast do jogador respeita o alcance configurado,
+// com alcance próprio no fly mode
+func TestPlayerReachDistance(t *testing.T) {
+	world := createChunkedFlatWorld()
+
+	// A face do bloco fica a 6.5 da origem na direção +X
+	world.SetBlock(12, 12, 5, BlockStone)
+	origin := rl.NewVector3(5.5, 12.5, 5.5)
+
+	player := NewPlayer(rl.NewVector3(5.5, 11, 5.5))
+	if player.Reach() != DefaultReachDistance {
+		t.Fatalf("Expected default reach %.1f, got %.1f", DefaultReachDistance, player.Reach())
+	}
+	player.Camera.Position = origin
+	player.Camera.Target = rl.Vector3Add(origin, rl.NewVector3(1, 0, 0))
+
+	player.ReachDistance = 6.4
+	player.Raycast(world)
+	if player.LookingAtBlock {
+		t.Errorf("Block just beyond reach should not be hit (target %v)", player.TargetBlock)
+	}
+
+	player.ReachDistance = 6.6
+	player.Raycast(world)
+	if !player.LookingAtBlock || player.TargetBlock != rl.NewVector3(12, 12, 5) {
+		t.Errorf("Block just within reach should be hit, got looking=%v target=%v", player.LookingAtBlock, player.TargetBlock)
+	}
+
+	// No fly mode vale FlyReachDistance, aplicada pelas configurações
+	settings := DefaultSettings()
+	settings.ReachDistance = 4
+	settings.FlyReachDistance = 20
+	player.ApplySettings(settings)
+	player.Raycast(world)
+	if player.LookingAtBlock {
+		t.Error("Walking reach from settings should not hit the block")
+	}
+	player.FlyMode = true
+	player.Raycast(world)
+	if !player.LookingAtBlock {
+		t.Error("Fly reach from settings should hit the block")
+	}
+}
+
+// TestPlayerCannotPlaceBeyondHitVoxel verifica que só dá para colocar blocos vazios encostados
+// no bloco mirado
+func TestPlayerCannotPlaceBeyondHitVoxel(t *testing.T) {
+	world := createChunkedFlatWorld()
+	world.SetBlock(12, 12, 5, BlockStone)
+
+	player := NewPlayer(rl.NewVector3(5.5, 11, 5.5))
+	player.TargetBlock = rl.NewVector3(12, 12, 5)
+
+	tests := []struct {
+		pos      rl.Vector3
+		expected bool
+	}{
+		{rl.NewVector3(11, 12, 5), true},  // Face de frente
+		{rl.NewVector3(12, 13, 5), true},  // Face de cima
+		{rl.NewVector3(10, 12, 5), false}, // No ar, além da face
+		{rl.NewVector3(11, 13, 5), false}, // Diagonal
+		{rl.NewVector3(12, 12, 5), false}, // O próprio bloco mirado
+		{rl.NewVector3(12, 10, 5), false}, // Ocupado (chão)
+	}
+	for _, tt := range tests {
+		if got := player.canPlaceAt(world, tt.pos); got != tt.expected {
+			t.Errorf("canPlaceAt(%v) = %v, expected %v", tt.pos, got, tt.expected)
+		}
+	}
+
+	// Câmera dentro de um bloco: o voxel de colocação é o próprio bloco atingido
+	player.Camera.Position = rl.NewVector3(12.5, 12.5, 5.5)
+	player.Camera.Target = rl.NewVector3(13.5, 12.5, 5.5)
+	player.Raycast(world)
+	if !player.LookingAtBlock || player.canPlaceAt(world, player.PlaceBlock) {
+		t.Errorf("Placing from inside a block should be refused (target %v, place %v)", player.TargetBlock, player.PlaceBlock)
+	}
+}
@@ -0,0 +1,144 @@
+package game
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// Valores padrão e limites das configurações de câmera
+const (
+	DefaultFOV              = 60.0
+	MinFOV                  = 30.0
+	MaxFOV                  = 110.0
+	DefaultMouseSensitivity = 0.003
+	MinMouseSensitivity     = 0.0005
+	MaxMouseSensitivity     = 0.02
+
+	// Distância de renderização em chunks
+	DefaultRenderDistance = 5
+	MinRenderDistance     = 2
+	MaxRenderDistance     = 16
+
+	// Alcance (em blocos) para mirar, quebrar e colocar blocos
+	DefaultReachDistance = 10.0
+	MinReachDistance     = 1.0
+	MaxReachDistance     = 64.0
+
+	// Neblina da metade do raio de visão até pouco antes da borda, na cor do céu (rl.SkyBlue)
+	DefaultFogStart = 0.5
+	DefaultFogEnd   = 0.9
+
+	// SettingsFile é o arquivo padrão onde as configurações são persistidas
+	SettingsFile = "settings.json"
+)
+
+// DefaultFogColor cor padrão da neblina e do céu
+var DefaultFogColor = [3]uint8{102, 191, 255}
+
+// Settings agrupa as configurações de conforto ajustáveis em tempo de execução
+type Settings struct {
+	FOV              float32 `json:"fov"`                // Campo de visão vertical em graus
+	MouseSensitivity float32 `json:"mouse_sensitivity"`  // Radianos por pixel de movimento do mouse
+	InvertY          bool    `json:"invert_y"`           // Mouse para cima olha para baixo
+	RenderDistance   int32   `json:"render_distance"`    // Raio de chunks carregados ao redor do jogador (ver World.Update)
+	ReachDistance    float32 `json:"reach_distance"`     // Alcance do jogador andando
+	FlyReachDistance float32 `json:"fly_reach_distance"` // Alcance do jogador no fly mode
+	AmbientOcclusion bool    `json:"ambient_occlusion"`  // Sombreamento dos cantos entre blocos (desligar alivia GPUs fracas)
+	GreedyMeshing    bool    `json:"greedy_meshing"`     // Junta faces iguais em quads maiores (menos vértices, mais FPS)
+
+	// Neblina de distância (ver Fog): FogStart e FogEnd são frações da distância de renderização
+	Fog      bool     `json:"fog"`
+	FogColor [3]uint8 `json:"fog_color"` // RGB; também é a cor do céu de dia (ver SkyColorAt)
+	FogStart float32  `json:"fog_start"`
+	FogEnd   float32  `json:"fog_end"`
+
+	// Lado das texturas em pixels (16, 32, 64 ou 128); vale a partir da próxima inicialização
+	TextureSize int32 `json:"texture_size"`
+}
+
+// DefaultSettings retorna as configurações padrão
+func DefaultSettings() Settings {
+	return Settings{
+		FOV:              DefaultFOV,
+		MouseSensitivity: DefaultMouseSensitivity,
+		RenderDistance:   DefaultRenderDistance,
+		ReachDistance:    DefaultReachDistance,
+		FlyReachDistance: DefaultReachDistance,
+		AmbientOcclusion: true,
+		GreedyMeshing:    true,
+		Fog:              true,
+		FogColor:         DefaultFogColor,
+		FogStart:         DefaultFogStart,
+		FogEnd:           DefaultFogEnd,
+		TextureSize:      DefaultTextureSize,
+	}
+}
+
+// Clamp limita os valores aos intervalos válidos
+func (s *Settings) Clamp() {
+	s.FOV = clampFloat32(s.FOV, MinFOV, MaxFOV)
+	s.MouseSensitivity = clampFloat32(s.MouseSensitivity, MinMouseSensitivity, MaxMouseSensitivity)
+	if s.RenderDistance < MinRenderDistance {
+		s.RenderDistance = MinRenderDistance
+	}
+	if s.RenderDistance > MaxRenderDistance {
+		s.RenderDistance = MaxRenderDistance
+	}
+	s.ReachDistance = clampFloat32(s.ReachDistance, MinReachDistance, MaxReachDistance)
+	s.FlyReachDistance = clampFloat32(s.FlyReachDistance, MinReachDistance, MaxReachDistance)
+	s.FogStart = clampFloat32(s.FogStart, 0, 1)
+	s.FogEnd = clampFloat32(s.FogEnd, s.FogStart, 1)
+	if !IsSupportedTextureSize(s.TextureSize) {
+		s.TextureSize = DefaultTextureSize
+	}
+}
+
+// LoadSettings carrega as configurações de um arquivo JSON.
+// Se o arquivo não existir, retorna as configurações padrão.
+func LoadSettings(path string) (Settings, error) {
+	settings := DefaultSettings()
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return settings, nil
+	}
+	if err != nil {
+		return settings, fmt.Errorf("failed to read settings: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return DefaultSettings(), fmt.Errorf("failed to parse settings: %w", err)
+	}
+
+	settings.Clamp()
+	return settings, nil
+}
+
+// Save salva as configurações em um arquivo JSON
+func (s Settings) Save(path string) error {
+	s.Clamp()
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal settings: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write settings: %w", err)
+	}
+
+	return nil
+}
+
+// clampFloat32 limita um valor ao intervalo [min, max]
+func clampFloat32(value, min, max float32) float32 {
+	if value < min {
+		return min
+	}
+	if value > max {
+		return max
+	}
+	return value
+}
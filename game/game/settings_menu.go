@@ -0,0 +1,74 @@
+package game
+
+import "fmt"
+
+// Itens da tela de configurações, na ordem em que aparecem
+const (
+	SettingsItemFOV = iota
+	SettingsItemSensitivity
+	SettingsItemInvertY
+	SettingsItemRenderDistance
+	SettingsItemReach
+	SettingsItemFlyReach
+	SettingsItemAmbientOcclusion
+	SettingsItemGreedyMeshing
+	SettingsItemFog
+	settingsItemCount
+)
+
+// SettingsMenu tela de configurações (tecla O): as setas para cima/baixo escolhem o item e
+// esquerda/direita ajustam o valor. Só guarda o estado da tela; o desenho fica no main.
+type SettingsMenu struct {
+	Open     bool
+	Selected int
+}
+
+// Toggle abre ou fecha a tela
+func (m *SettingsMenu) Toggle() {
+	m.Open = !m.Open
+}
+
+// Move seleciona o item anterior (delta < 0) ou o próximo (delta > 0), dando a volta nas pontas
+func (m *SettingsMenu) Move(delta int) {
+	m.Selected = ((m.Selected+delta)%settingsItemCount + settingsItemCount) % settingsItemCount
+}
+
+// Adjust altera o item selecionado de settings na direção dir (-1 diminui, +1 aumenta; nas opções
+// liga/desliga qualquer direção alterna). Os limites são aplicados depois, por Settings.Clamp.
+func (m *SettingsMenu) Adjust(settings *Settings, dir int) {
+	switch m.Selected {
+	case SettingsItemFOV:
+		settings.FOV += 5 * float32(dir)
+	case SettingsItemSensitivity:
+		settings.MouseSensitivity += 0.0005 * float32(dir)
+	case SettingsItemInvertY:
+		settings.InvertY = !settings.InvertY
+	case SettingsItemRenderDistance:
+		settings.RenderDistance += int32(dir)
+	case SettingsItemReach:
+		settings.ReachDistance += float32(dir)
+	case SettingsItemFlyReach:
+		settings.FlyReachDistance += float32(dir)
+	case SettingsItemAmbientOcclusion:
+		settings.AmbientOcclusion = !settings.AmbientOcclusion
+	case SettingsItemGreedyMeshing:
+		settings.GreedyMeshing = !settings.GreedyMeshing
+	case SettingsItemFog:
+		settings.Fog = !settings.Fog
+	}
+}
+
+// Lines retorna o texto de cada item com o valor atual de settings
+func (m *SettingsMenu) Lines(settings Settings) []string {
+	return []string{
+		fmt.Sprintf("FOV: %.0f", settings.FOV),
+		fmt.Sprintf("Sensibilidade do mouse: %.4f", settings.MouseSensitivity),
+		fmt.Sprintf("Inverter eixo Y: %v", settings.InvertY),
+		fmt.Sprintf("Distância de renderização: %d chunks", settings.RenderDistance),
+		fmt.Sprintf("Alcance: %.0f blocos", settings.ReachDistance),
+		fmt.Sprintf("Alcance no fly mode: %.0f blocos", settings.FlyReachDistance),
+		fmt.Sprintf("Oclusão ambiente: %v", settings.AmbientOcclusion),
+		fmt.Sprintf("Greedy meshing: %v", settings.GreedyMeshing),
+		fmt.Sprintf("Neblina: %v", settings.Fog),
+	}
+}
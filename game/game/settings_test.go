@@ -0,0 +1,211 @@
+package game
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+func TestMouseSensitivityScalesDelta(t *testing.T) {
+	world := createChunkedFlatWorld()
+
+	rotation := func(sensitivity float32) (float32, float32) {
+		player := NewPlayer(rl.NewVector3(16, 11, 16))
+		player.FlyMode = true
+		player.ApplySettings(Settings{FOV: DefaultFOV, MouseSensitivity: sensitivity})
+
+		startYaw, startPitch := player.Yaw, player.Pitch
+		input := &SimulatedInput{MouseDelta: rl.NewVector2(100, 50)}
+		player.Update(1.0/60.0, world, input)
+
+		return player.Yaw - startYaw, player.Pitch - startPitch
+	}
+
+	yaw, pitch := rotation(0.002)
+	if math.Abs(float64(yaw+0.2)) > 1e-5 || math.Abs(float64(pitch+0.1)) > 1e-5 {
+		t.Errorf("Expected yaw -0.2 and pitch -0.1 with sensitivity 0.002, got %.5f and %.5f", yaw, pitch)
+	}
+
+	// Dobrar a sensibilidade dobra a rotação para o mesmo delta do mouse
+	yaw2, pitch2 := rotation(0.004)
+	if math.Abs(float64(yaw2-2*yaw)) > 1e-5 || math.Abs(float64(pitch2-2*pitch)) > 1e-5 {
+		t.Errorf("Rotation should scale with sensitivity: %.5f/%.5f vs %.5f/%.5f", yaw2, pitch2, yaw, pitch)
+	}
+}
+
+func TestSettingsClamp(t *testing.T) {
+	tests := []struct {
+		name              string
+		in                Settings
+		expectFOV         float32
+		expectSensitivity float32
+	}{
+		{"defaults unchanged", DefaultSettings(), DefaultFOV, DefaultMouseSensitivity},
+		{"fov too low", Settings{FOV: 5, MouseSensitivity: 0.003}, MinFOV, 0.003},
+		{"fov too high", Settings{FOV: 179, MouseSensitivity: 0.003}, MaxFOV, 0.003},
+		{"zero sensitivity", Settings{FOV: 90, MouseSensitivity: 0}, 90, MinMouseSensitivity},
+		{"huge sensitivity", Settings{FOV: 90, MouseSensitivity: 1}, 90, MaxMouseSensitivity},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settings := tt.in
+			settings.Clamp()
+			if settings.FOV != tt.expectFOV || settings.MouseSensitivity != tt.expectSensitivity {
+				t.Errorf("Expected FOV %.1f / sensitivity %.4f, got %.1f / %.4f",
+					tt.expectFOV, tt.expectSensitivity, settings.FOV, settings.MouseSensitivity)
+			}
+		})
+	}
+}
+
+func TestApplySettingsClampsCameraFOV(t *testing.T) {
+	player := NewPlayer(rl.NewVector3(16, 16, 16))
+	if player.Camera.Fovy != DefaultFOV {
+		t.Errorf("Expected default FOV %.1f, got %.1f", DefaultFOV, player.Camera.Fovy)
+	}
+
+	player.ApplySettings(Settings{FOV: 500, MouseSensitivity: DefaultMouseSensitivity})
+	if player.Camera.Fovy != MaxFOV || player.Settings.FOV != MaxFOV {
+		t.Errorf("Camera FOV should be clamped to %.1f, got %.1f", MaxFOV, player.Camera.Fovy)
+	}
+
+	player.ApplySettings(Settings{FOV: -10, MouseSensitivity: DefaultMouseSensitivity})
+	if player.Camera.Fovy != MinFOV {
+		t.Errorf("Camera FOV should be clamped to %.1f, got %.1f", MinFOV, player.Camera.Fovy)
+	}
+}
+
+func TestSettingsPersistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), SettingsFile)
+
+	// Arquivo inexistente retorna os padrões
+	loaded, err := LoadSettings(path)
+	if err != nil {
+		t.Fatalf("Missing settings file should not fail: %v", err)
+	}
+	if loaded != DefaultSettings() {
+		t.Errorf("Expected default settings, got %+v", loaded)
+	}
+
+	saved := Settings{FOV: 90, MouseSensitivity: 0.005, InvertY: true, RenderDistance: 8, ReachDistance: 6, FlyReachDistance: 24, TextureSize: 64}
+	if err := saved.Save(path); err != nil {
+		t.Fatalf("Failed to save settings: %v", err)
+	}
+	loaded, err = LoadSettings(path)
+	if err != nil {
+		t.Fatalf("Failed to load settings: %v", err)
+	}
+	if loaded != saved {
+		t.Errorf("Expected %+v after round-trip, got %+v", saved, loaded)
+	}
+
+	// Valores editados manualmente fora dos limites são ajustados ao carregar
+	if err := os.WriteFile(path, []byte(`{"fov": 1000, "mouse_sensitivity": -1}`), 0644); err != nil {
+		t.Fatalf("Failed to write settings: %v", err)
+	}
+	loaded, _ = LoadSettings(path)
+	if loaded.FOV != MaxFOV || loaded.MouseSensitivity != MinMouseSensitivity {
+		t.Errorf("Loaded settings should be clamped, got %+v", loaded)
+	}
+
+	if err := os.WriteFile(path, []byte("not json"), 0644); err != nil {
+		t.Fatalf("Failed to write settings: %v", err)
+	}
+	if loaded, err = LoadSettings(path); err == nil || loaded != DefaultSettings() {
+		t.Error("Invalid settings file should return an error and the defaults")
+	}
+}
+
+func TestLoadedSettingsChangeSensitivity(t *testing.T) {
+	world := createChunkedFlatWorld()
+	path := filepath.Join(t.TempDir(), SettingsFile)
+	if err := os.WriteFile(path, []byte(`{"mouse_sensitivity": 0.006, "invert_y": true}`), 0644); err != nil {
+		t.Fatalf("Failed to write settings: %v", err)
+	}
+
+	settings, err := LoadSettings(path)
+	if err != nil {
+		t.Fatalf("Failed to load settings: %v", err)
+	}
+	// Campos ausentes no arquivo ficam com os padrões
+	if settings.FOV != DefaultFOV || settings.RenderDistance != DefaultRenderDistance {
+		t.Errorf("Missing fields should keep defaults, got %+v", settings)
+	}
+
+	player := NewPlayer(rl.NewVector3(16, 11, 16))
+	player.FlyMode = true
+	player.ApplySettings(settings)
+	if player.Settings.MouseSensitivity != 0.006 {
+		t.Fatalf("Expected effective sensitivity 0.006, got %.4f", player.Settings.MouseSensitivity)
+	}
+
+	startYaw, startPitch := player.Yaw, player.Pitch
+	player.Update(1.0/60.0, world, &SimulatedInput{MouseDelta: rl.NewVector2(100, 50)})
+
+	// Yaw com a sensibilidade carregada; pitch no sentido oposto ao padrão por causa do invert_y
+	yaw, pitch := player.Yaw-startYaw, player.Pitch-startPitch
+	if math.Abs(float64(yaw+0.6)) > 1e-5 || math.Abs(float64(pitch-0.3)) > 1e-5 {
+		t.Errorf("Expected yaw -0.6 and pitch +0.3 from loaded settings, got %.5f and %.5f", yaw, pitch)
+	}
+}
+
+func TestSettingsMenuAdjust(t *testing.T) {
+	menu := &SettingsMenu{}
+	settings := DefaultSettings()
+
+	menu.Move(-1)
+	if menu.Selected != SettingsItemFog {
+		t.Errorf("Moving up from the first item should wrap to the last, got %d", menu.Selected)
+	}
+	menu.Move(1)
+	if menu.Selected != SettingsItemFOV {
+		t.Errorf("Moving down from the last item should wrap to the first, got %d", menu.Selected)
+	}
+
+	menu.Adjust(&settings, 1)
+	if settings.FOV != DefaultFOV+5 {
+		t.Errorf("Expected FOV %.0f, got %.0f", DefaultFOV+5, settings.FOV)
+	}
+
+	menu.Selected = SettingsItemInvertY
+	menu.Adjust(&settings, -1)
+	if !settings.InvertY {
+		t.Error("Adjusting invert Y should toggle it")
+	}
+
+	// O menu não limita os valores; Clamp (via ApplySettings) sim
+	menu.Selected = SettingsItemRenderDistance
+	for i := 0; i < 20; i++ {
+		menu.Adjust(&settings, 1)
+	}
+	settings.Clamp()
+	if settings.RenderDistance != MaxRenderDistance {
+		t.Errorf("Render distance should be clamped to %d, got %d", MaxRenderDistance, settings.RenderDistance)
+	}
+
+	if lines := menu.Lines(settings); len(lines) != settingsItemCount {
+		t.Errorf("Expected %d menu lines, got %d", settingsItemCount, len(lines))
+	}
+}
+
+func TestWorldUpdateAppliesRenderDistance(t *testing.T) {
+	world := NewWorld()
+
+	// Valor ajustado direto no ChunkManager não é sobrescrito enquanto World.RenderDistance não mudar
+	world.ChunkManager.RenderDistance = 2
+	world.Update(rl.NewVector3(16, 16, 16), 0)
+	if world.ChunkManager.RenderDistance != 2 {
+		t.Errorf("Unchanged world render distance should not override the chunk manager, got %d", world.ChunkManager.RenderDistance)
+	}
+
+	world.RenderDistance = 8
+	world.Update(rl.NewVector3(16, 16, 16), 0)
+	if world.ChunkManager.RenderDistance != 8 || world.ChunkManager.UnloadDistance != 10 {
+		t.Errorf("Expected render/unload distance 8/10, got %d/%d",
+			world.ChunkManager.RenderDistance, world.ChunkManager.UnloadDistance)
+	}
+}
@@ -1,87 +1,82 @@
 package game
 
-// TerrainGenerator gera terreno de forma determinística baseado em seed
-type TerrainGenerator struct {
-	Seed int64
+// DefaultWorldSeed seed do mundo quando nenhuma é informada
+const DefaultWorldSeed = 12345
+
+// TerrainGenerator gera os blocos do terreno de forma determinística: a mesma seed sempre
+// produz o mesmo mundo
+type TerrainGenerator interface {
+	// WorldSeed retorna a seed do mundo (gravada no save)
+	WorldSeed() int64
+	// GetBlockTypeAt retorna o bloco gerado na posição do mundo
+	GetBlockTypeAt(x, y, z int32) BlockType
 }
 
-// NewTerrainGenerator cria um novo gerador de terreno
-func NewTerrainGenerator(seed int64) *TerrainGenerator {
-	return &TerrainGenerator{Seed: seed}
+// ChunkFiller é implementado por geradores que preenchem um chunk inteiro de uma vez, mais
+// rápido que bloco a bloco (ver Chunk.GenerateTerrainWithGenerator)
+type ChunkFiller interface {
+	FillChunk(c *Chunk)
 }
 
-// hash3D gera um hash determinístico baseado em posição 3D
-func (tg *TerrainGenerator) hash3D(x, y, z int32) uint64 {
-	h := uint64(tg.Seed)
-	h ^= uint64(x) * 0x45d9f3b
-	h ^= uint64(y) * 0x45d9f3b * 3
-	h ^= uint64(z) * 0x45d9f3b * 7
-	h = (h ^ (h >> 16)) * 0x45d9f3b
-	h = (h ^ (h >> 16)) * 0x45d9f3b
-	h = h ^ (h >> 16)
-	return h
+// NewTerrainGenerator cria o gerador de terreno padrão do jogo (colinas e cavernas por ruído,
+// com biomas)
+func NewTerrainGenerator(seed int64) TerrainGenerator {
+	tg := NewNoiseTerrainGenerator(seed)
+	tg.Biomes = NewBiomeMap(seed)
+	return tg
 }
 
-// GetBlockTypeAt retorna o tipo de bloco para uma posição específica
-func (tg *TerrainGenerator) GetBlockTypeAt(x, y, z int32) BlockType {
-	// Camada de ar acima de y=8
-	if y > 8 {
+// Profundidade padrão da camada de terra abaixo da grama
+const defaultDirtDepth = 3
+
+// layeredBlock retorna o material de uma coluna com superfície em surface: grama no topo,
+// dirtDepth camadas de terra e pedra abaixo
+func layeredBlock(y, surface, dirtDepth int32) BlockType {
+	return paletteBlock(y, surface, dirtDepth, BlockGrass, BlockDirt)
+}
+
+// paletteBlock como layeredBlock, com os blocos do topo e das camadas abaixo dele informados
+func paletteBlock(y, surface, fillerDepth int32, top, filler BlockType) BlockType {
+	switch {
+	case y > surface:
 		return BlockAir
+	case y == surface:
+		return top
+	case y >= surface-fillerDepth:
+		return filler
+	default:
+		return BlockStone
 	}
+}
 
-	// Camada de superfície (y=8)
-	if y == 8 {
-		h := tg.hash3D(x, y, z)
-		surfaceTypes := []BlockType{
-			BlockGrass, BlockSand, BlockGravel, BlockStone,
-			BlockSnow, BlockMoss, BlockClay,
-		}
-		return surfaceTypes[h%uint64(len(surfaceTypes))]
-	}
+// FlatTerrainGenerator gera um terreno plano com superfície de grama em Height (útil em testes)
+type FlatTerrainGenerator struct {
+	Seed   int64
+	Height int32
+}
 
-	// Camadas intermediárias superiores (y=6-7)
-	if y >= 6 && y < 8 {
-		h := tg.hash3D(x, y, z)
-		upperTypes := []BlockType{
-			BlockDirt, BlockCobblestone, BlockGravel,
-			BlockCoal, BlockClay, BlockStone,
-		}
-		return upperTypes[h%uint64(len(upperTypes))]
-	}
+// NewFlatTerrainGenerator cria um gerador de terreno plano na altura informada
+func NewFlatTerrainGenerator(height int32) *FlatTerrainGenerator {
+	return &FlatTerrainGenerator{Height: height}
+}
 
-	// Camadas intermediárias (y=4-5)
-	if y >= 4 && y < 6 {
-		h := tg.hash3D(x, y, z)
-		midTypes := []BlockType{
-			BlockDirt, BlockCobblestone, BlockGravel,
-			BlockCoal, BlockIronOre, BlockStone,
-			BlockClay, BlockObsidian,
-		}
-		return midTypes[h%uint64(len(midTypes))]
-	}
+// WorldSeed retorna a seed do gerador (não influencia o terreno plano)
+func (fg *FlatTerrainGenerator) WorldSeed() int64 {
+	return fg.Seed
+}
 
-	// Camadas profundas (y=2-3)
-	if y >= 2 && y < 4 {
-		h := tg.hash3D(x, y, z)
-		deepTypes := []BlockType{
-			BlockStone, BlockCobblestone, BlockIronOre,
-			BlockGoldOre, BlockDiamondOre, BlockObsidian,
-			BlockCoal, BlockBedrock,
-		}
-		return deepTypes[h%uint64(len(deepTypes))]
-	}
+// GetBlockTypeAt retorna grama na superfície, terra logo abaixo e pedra no fundo
+func (fg *FlatTerrainGenerator) GetBlockTypeAt(x, y, z int32) BlockType {
+	return layeredBlock(y, fg.Height, defaultDirtDepth)
+}
 
-	// Camada mais profunda (y=0-1) - mais minérios raros
-	if y >= 0 && y < 2 {
-		h := tg.hash3D(x, y, z)
-		deepestTypes := []BlockType{
-			BlockStone, BlockBedrock, BlockObsidian,
-			BlockDiamondOre, BlockGoldOre, BlockLava,
-			BlockIronOre,
+// SpawnHeight retorna a altura logo acima do bloco sólido mais alto da coluna (x, z),
+// procurando de maxY para baixo; se a coluna estiver vazia, retorna maxY
+func SpawnHeight(tg TerrainGenerator, x, z, maxY int32) int32 {
+	for y := maxY; y > maxY-4*ChunkHeight; y-- {
+		if tg.GetBlockTypeAt(x, y, z) != BlockAir {
+			return y + 1
 		}
-		return deepestTypes[h%uint64(len(deepestTypes))]
 	}
-
-	// Abaixo de y=0, apenas bedrock
-	return BlockBedrock
+	return maxY
 }
@@ -0,0 +1,145 @@
+package game
+
+import (
+	"testing"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// Helper: gera o chunk com o gerador informado
+func generateChunk(tg TerrainGenerator, coord ChunkCoord) *Chunk {
+	chunk := &Chunk{Coord: coord}
+	chunk.GenerateTerrainWithGenerator(tg)
+	return chunk
+}
+
+func TestNoiseTerrainSameSeedSameChunk(t *testing.T) {
+	coords := []ChunkCoord{{X: 0, Y: 0, Z: 0}, {X: -3, Y: -1, Z: 5}, {X: 7, Y: 0, Z: -2}}
+
+	for _, coord := range coords {
+		first := generateChunk(NewNoiseTerrainGenerator(42), coord)
+		second := generateChunk(NewNoiseTerrainGenerator(42), coord)
+		if first.Blocks != second.Blocks {
+			t.Errorf("Chunk %v: same seed should generate identical blocks", coord)
+		}
+	}
+
+	// Seeds diferentes geram mundos diferentes
+	a := generateChunk(NewNoiseTerrainGenerator(42), coords[0])
+	b := generateChunk(NewNoiseTerrainGenerator(43), coords[0])
+	if a.Blocks == b.Blocks {
+		t.Error("Different seeds should generate different terrain")
+	}
+}
+
+func TestNoiseTerrainFillChunkMatchesGetBlockTypeAt(t *testing.T) {
+	tg := NewNoiseTerrainGenerator(7)
+	coord := ChunkCoord{X: 1, Y: -1, Z: -1}
+	chunk := generateChunk(tg, coord)
+
+	for x := int32(0); x < ChunkSize; x++ {
+		for y := int32(0); y < ChunkHeight; y++ {
+			for z := int32(0); z < ChunkSize; z++ {
+				want := tg.GetBlockTypeAt(coord.X*ChunkSize+x, coord.Y*ChunkHeight+y, coord.Z*ChunkSize+z)
+				if chunk.Blocks[x][y][z] != want {
+					t.Fatalf("Block (%d, %d, %d): FillChunk gave %d, GetBlockTypeAt gave %d", x, y, z, chunk.Blocks[x][y][z], want)
+				}
+			}
+		}
+	}
+}
+
+func TestNoiseTerrainLayers(t *testing.T) {
+	tg := NewNoiseTerrainGenerator(99)
+	tg.CaveThreshold = 1 // Sem cavernas: colunas completas
+
+	minHeight, maxHeight := int32(1<<30), int32(-1<<30)
+	for x := int32(-64); x < 64; x += 3 {
+		for z := int32(-64); z < 64; z += 3 {
+			surface := tg.SurfaceHeight(x, z)
+			minHeight = min(minHeight, surface)
+			maxHeight = max(maxHeight, surface)
+
+			if got := tg.GetBlockTypeAt(x, surface+1, z); got != BlockAir {
+				t.Fatalf("Column (%d, %d): expected air above the surface, got %d", x, z, got)
+			}
+			if got := tg.GetBlockTypeAt(x, surface, z); got != BlockGrass {
+				t.Fatalf("Column (%d, %d): expected grass on the surface, got %d", x, z, got)
+			}
+			for depth := int32(1); depth <= tg.DirtDepth; depth++ {
+				if got := tg.GetBlockTypeAt(x, surface-depth, z); got != BlockDirt {
+					t.Fatalf("Column (%d, %d): expected dirt %d blocks deep, got %d", x, z, depth, got)
+				}
+			}
+			if got := tg.GetBlockTypeAt(x, surface-tg.DirtDepth-1, z); got != BlockStone {
+				t.Fatalf("Column (%d, %d): expected stone below the dirt, got %d", x, z, got)
+			}
+		}
+	}
+
+	// Relevo com colinas, dentro da amplitude configurada
+	if maxHeight-minHeight < 4 {
+		t.Errorf("Expected hills, surface only varies from %d to %d", minHeight, maxHeight)
+	}
+	amplitude := int32(tg.HeightAmplitude)
+	if minHeight < tg.BaseHeight-amplitude || maxHeight > tg.BaseHeight+amplitude {
+		t.Errorf("Surface from %d to %d outside %d±%d", minHeight, maxHeight, tg.BaseHeight, amplitude)
+	}
+}
+
+func TestNoiseTerrainCaves(t *testing.T) {
+	tg := NewNoiseTerrainGenerator(5)
+
+	// Cavernas só abaixo do teto mínimo, nunca abrindo a superfície
+	caves := 0
+	for x := int32(0); x < 64; x++ {
+		for z := int32(0); z < 64; z++ {
+			surface := tg.SurfaceHeight(x, z)
+			for y := surface - 40; y <= surface; y++ {
+				if tg.GetBlockTypeAt(x, y, z) != BlockAir {
+					continue
+				}
+				if y > surface-tg.CaveRoof {
+					t.Fatalf("Cave at (%d, %d, %d) too close to the surface at %d", x, y, z, surface)
+				}
+				caves++
+			}
+		}
+	}
+
+	total := 64 * 64 * 41
+	if caves == 0 || caves > total/4 {
+		t.Errorf("Expected some underground caves, got %d of %d blocks", caves, total)
+	}
+	t.Logf("Cave blocks: %d of %d (%.1f%%)", caves, total, float64(caves)*100/float64(total))
+}
+
+func TestFlatTerrainGeneratorInjected(t *testing.T) {
+	world := NewWorld()
+	world.TerrainGenerator = NewFlatTerrainGenerator(10)
+	world.ChunkManager.RenderDistance = 1
+	for i := 0; i < 5; i++ {
+		world.ChunkManager.LoadChunksAroundPlayer(rl.NewVector3(16, 10, 16), world.TerrainGenerator)
+	}
+
+	for x := int32(0); x < ChunkSize; x += 5 {
+		for z := int32(0); z < ChunkSize; z += 5 {
+			if got := world.GetBlock(x, 10, z); got != BlockGrass {
+				t.Fatalf("Expected flat grass at (%d, 10, %d), got %d", x, z, got)
+			}
+			if got := world.GetBlock(x, 11, z); got != BlockAir {
+				t.Fatalf("Expected air above the flat surface at (%d, 11, %d), got %d", x, z, got)
+			}
+			if got := world.GetBlock(x, 5, z); got != BlockStone {
+				t.Fatalf("Expected stone at depth at (%d, 5, %d), got %d", x, z, got)
+			}
+		}
+	}
+}
+
+func BenchmarkNoiseTerrainFillChunk(b *testing.B) {
+	tg := NewNoiseTerrainGenerator(1)
+	for i := 0; i < b.N; i++ {
+		generateChunk(tg, ChunkCoord{X: int32(i), Y: 0, Z: 0})
+	}
+}
@@ -0,0 +1,208 @@
+package game
+
+import (
+	"math"
+	"math/rand"
+)
+
+// perlinNoise ruído de Perlin ("improved noise", 2002) com a tabela de permutação embaralhada
+// pela seed. Valores aproximadamente em [-1, 1].
+type perlinNoise struct {
+	perm [512]uint8
+}
+
+// newPerlinNoise cria o ruído da seed informada
+func newPerlinNoise(seed int64) *perlinNoise {
+	p := &perlinNoise{}
+	table := rand.New(rand.NewSource(seed)).Perm(256)
+	for i := range p.perm {
+		p.perm[i] = uint8(table[i&255])
+	}
+	return p
+}
+
+// noise3 ruído 3D no ponto (x, y, z)
+func (p *perlinNoise) noise3(x, y, z float64) float64 {
+	fx, fy, fz := math.Floor(x), math.Floor(y), math.Floor(z)
+	xi, yi, zi := int(fx)&255, int(fy)&255, int(fz)&255
+	x, y, z = x-fx, y-fy, z-fz
+	u, v, w := perlinFade(x), perlinFade(y), perlinFade(z)
+
+	perm := &p.perm
+	a := int(perm[xi]) + yi
+	aa, ab := int(perm[a])+zi, int(perm[a+1])+zi
+	b := int(perm[xi+1]) + yi
+	ba, bb := int(perm[b])+zi, int(perm[b+1])+zi
+
+	return perlinLerp(w,
+		perlinLerp(v,
+			perlinLerp(u, perlinGrad(perm[aa], x, y, z), perlinGrad(perm[ba], x-1, y, z)),
+			perlinLerp(u, perlinGrad(perm[ab], x, y-1, z), perlinGrad(perm[bb], x-1, y-1, z))),
+		perlinLerp(v,
+			perlinLerp(u, perlinGrad(perm[aa+1], x, y, z-1), perlinGrad(perm[ba+1], x-1, y, z-1)),
+			perlinLerp(u, perlinGrad(perm[ab+1], x, y-1, z-1), perlinGrad(perm[bb+1], x-1, y-1, z-1))))
+}
+
+// fbm2 soma octaves do ruído 2D (em z = 0), cada uma com o dobro da frequência e metade da
+// amplitude da anterior; o resultado é normalizado para a mesma faixa do ruído
+func (p *perlinNoise) fbm2(x, z float64, octaves int) float64 {
+	total, amplitude, frequency, norm := 0.0, 1.0, 1.0, 0.0
+	for i := 0; i < octaves; i++ {
+		total += p.noise3(x*frequency, z*frequency, 0) * amplitude
+		norm += amplitude
+		amplitude *= 0.5
+		frequency *= 2
+	}
+	return total / norm
+}
+
+// perlinFade curva de suavização 6t^5 - 15t^4 + 10t^3
+func perlinFade(t float64) float64 {
+	return t * t * t * (t*(t*6-15) + 10)
+}
+
+func perlinLerp(t, a, b float64) float64 {
+	return a + t*(b-a)
+}
+
+// perlinGrad produto escalar com um dos 12 gradientes (arestas do cubo) escolhido pelo hash
+func perlinGrad(hash uint8, x, y, z float64) float64 {
+	h := hash & 15
+	u := y
+	if h < 8 {
+		u = x
+	}
+	v := z
+	if h < 4 {
+		v = y
+	} else if h == 12 || h == 14 {
+		v = x
+	}
+	if h&1 != 0 {
+		u = -u
+	}
+	if h&2 != 0 {
+		v = -v
+	}
+	return u + v
+}
+
+// NoiseTerrainGenerator gera colinas com ruído de Perlin 2D (altura da superfície de cada
+// coluna) e cavernas com ruído 3D, em camadas de grama, terra e pedra
+type NoiseTerrainGenerator struct {
+	Seed int64
+
+	BaseHeight      int32   // Altura média da superfície
+	HeightAmplitude float64 // Variação máxima da superfície (em blocos) acima e abaixo da média
+	HillScale       float64 // Largura típica (em blocos) das colinas
+	Octaves         int     // Octaves do ruído da superfície (mais = relevo mais detalhado)
+	DirtDepth       int32   // Camadas de terra abaixo da grama
+
+	CaveScale     float64 // Tamanho típico (em blocos) das cavernas
+	CaveThreshold float64 // Ruído 3D acima disso vira caverna (>= 1 = sem cavernas)
+	CaveRoof      int32   // Camadas sólidas mínimas entre a superfície e uma caverna
+
+	// Biomas (nil = grama e terra em todo o mundo, com BaseHeight e HeightAmplitude). Com
+	// biomas, a altura, a amplitude e os blocos da superfície vêm do bioma de cada coluna
+	Biomes *BiomeMap
+
+	height *perlinNoise
+	caves  *perlinNoise
+}
+
+// NewNoiseTerrainGenerator cria um gerador de colinas e cavernas com parâmetros padrão
+func NewNoiseTerrainGenerator(seed int64) *NoiseTerrainGenerator {
+	return &NoiseTerrainGenerator{
+		Seed:            seed,
+		BaseHeight:      8,
+		HeightAmplitude: 16,
+		HillScale:       64,
+		Octaves:         4,
+		DirtDepth:       defaultDirtDepth,
+		CaveScale:       20,
+		CaveThreshold:   0.4,
+		CaveRoof:        4,
+		height:          newPerlinNoise(seed),
+		caves:           newPerlinNoise(seed ^ 0x5deece66d),
+	}
+}
+
+// WorldSeed retorna a seed do gerador
+func (ng *NoiseTerrainGenerator) WorldSeed() int64 {
+	return ng.Seed
+}
+
+// terrainColumn superfície e blocos de uma coluna do terreno
+type terrainColumn struct {
+	surface     int32
+	top, filler BlockType
+}
+
+// column calcula a superfície e os blocos da coluna (x, z)
+func (ng *NoiseTerrainGenerator) column(x, z int32) terrainColumn {
+	n := ng.height.fbm2(float64(x)/ng.HillScale, float64(z)/ng.HillScale, ng.Octaves)
+	if ng.Biomes == nil {
+		return terrainColumn{
+			surface: ng.BaseHeight + int32(math.Round(n*ng.HeightAmplitude)),
+			top:     BlockGrass,
+			filler:  BlockDirt,
+		}
+	}
+
+	biome, baseHeight, amplitude := ng.Biomes.Terrain(x, z)
+	profile := BiomeProfiles[biome]
+	return terrainColumn{
+		surface: int32(math.Round(baseHeight + n*amplitude)),
+		top:     profile.Surface,
+		filler:  profile.Filler,
+	}
+}
+
+// SurfaceHeight retorna a altura da superfície (bloco do topo) da coluna (x, z)
+func (ng *NoiseTerrainGenerator) SurfaceHeight(x, z int32) int32 {
+	return ng.column(x, z).surface
+}
+
+// BiomeAt retorna o bioma da coluna (x, z) (planície se o gerador não tiver biomas)
+func (ng *NoiseTerrainGenerator) BiomeAt(x, z int32) Biome {
+	if ng.Biomes == nil {
+		return BiomePlains
+	}
+	return ng.Biomes.BiomeAt(x, z)
+}
+
+// GetBlockTypeAt retorna o bloco gerado na posição do mundo
+func (ng *NoiseTerrainGenerator) GetBlockTypeAt(x, y, z int32) BlockType {
+	return ng.blockAt(x, y, z, ng.column(x, z))
+}
+
+// FillChunk preenche o chunk calculando a superfície de cada coluna uma só vez
+func (ng *NoiseTerrainGenerator) FillChunk(c *Chunk) {
+	worldX := c.Coord.X * ChunkSize
+	worldY := c.Coord.Y * ChunkHeight
+	worldZ := c.Coord.Z * ChunkSize
+
+	for x := int32(0); x < ChunkSize; x++ {
+		for z := int32(0); z < ChunkSize; z++ {
+			column := ng.column(worldX+x, worldZ+z)
+			for y := int32(0); y < ChunkHeight; y++ {
+				c.Blocks[x][y][z] = ng.blockAt(worldX+x, worldY+y, worldZ+z, column)
+			}
+		}
+	}
+}
+
+// blockAt material do bloco na coluna informada, escavando as cavernas
+func (ng *NoiseTerrainGenerator) blockAt(x, y, z int32, column terrainColumn) BlockType {
+	blockType := paletteBlock(y, column.surface, ng.DirtDepth, column.top, column.filler)
+	if blockType == BlockAir || y > column.surface-ng.CaveRoof || ng.CaveThreshold >= 1 {
+		return blockType
+	}
+
+	// Cavernas mais achatadas que altas (escala vertical menor)
+	n := ng.caves.noise3(float64(x)/ng.CaveScale, float64(y)/(ng.CaveScale*0.6), float64(z)/ng.CaveScale)
+	if n > ng.CaveThreshold {
+		return BlockAir
+	}
+	return blockType
+}
@@ -0,0 +1,181 @@
+package game
+
+import (
+	"container/list"
+	"fmt"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// TextureLoader carrega e descarrega as texturas individuais dos blocos na GPU
+type TextureLoader interface {
+	Load(blockType BlockType) (rl.Texture2D, error)
+	Unload(texture rl.Texture2D)
+}
+
+// FileTextureLoader carrega as texturas dos arquivos de BlockTextureFiles
+type FileTextureLoader struct{}
+
+// Load carrega a textura do bloco (deve ser chamado após rl.InitWindow)
+func (FileTextureLoader) Load(blockType BlockType) (rl.Texture2D, error) {
+	file, exists := BlockTextureFiles[blockType]
+	if !exists {
+		file = DefaultTextureFile
+	}
+
+	texture := rl.LoadTexture(file)
+	if texture.ID == 0 {
+		return texture, fmt.Errorf("failed to load texture %s", file)
+	}
+	return texture, nil
+}
+
+// Unload descarrega a textura da GPU
+func (FileTextureLoader) Unload(texture rl.Texture2D) {
+	rl.UnloadTexture(texture)
+}
+
+// TextureCacheConfig limites do cache de texturas (0 = sem limite)
+type TextureCacheConfig struct {
+	MaxTextures int   // Máximo de texturas carregadas
+	MaxBytes    int64 // Orçamento de VRAM (estimado em 4 bytes por pixel)
+}
+
+// textureCacheEntry textura carregada de um tipo de bloco
+type textureCacheEntry struct {
+	blockType BlockType
+	texture   rl.Texture2D
+	bytes     int64
+}
+
+// TextureCache mantém na GPU só as texturas de blocos em uso (p.ex. os slots visíveis de uma
+// lista de blocos), carregando-as sob demanda e descarregando as usadas há mais tempo (LRU)
+// quando passa do limite de quantidade ou de VRAM.
+type TextureCache struct {
+	loader TextureLoader
+	config TextureCacheConfig
+
+	entries map[BlockType]*list.Element
+	lru     *list.List // Frente = usada mais recentemente
+	bytes   int64
+
+	// Estatísticas
+	Loads     int
+	Evictions int
+}
+
+// NewTextureCache cria um cache de texturas com o loader e os limites informados
+func NewTextureCache(loader TextureLoader, config TextureCacheConfig) *TextureCache {
+	return &TextureCache{
+		loader:  loader,
+		config:  config,
+		entries: make(map[BlockType]*list.Element),
+		lru:     list.New(),
+	}
+}
+
+// Get retorna a textura do bloco, carregando-a se necessário, e a marca como usada
+func (tc *TextureCache) Get(blockType BlockType) (rl.Texture2D, error) {
+	texture, err := tc.touch(blockType)
+	if err != nil {
+		return texture, err
+	}
+	tc.evict(map[BlockType]bool{blockType: true})
+	return texture, nil
+}
+
+// ShowSlots carrega as texturas dos blocos visíveis (p.ex. ao rolar uma lista de slots) e
+// descarrega as fora da tela usadas há mais tempo até voltar ao orçamento. As visíveis nunca
+// são descarregadas, mesmo que sozinhas passem do limite.
+func (tc *TextureCache) ShowSlots(visible []BlockType) error {
+	pinned := make(map[BlockType]bool, len(visible))
+
+	// Na ordem dos slots: ao rolar para baixo, os primeiros (que saem antes da tela) ficam
+	// como os usados há mais tempo
+	var firstErr error
+	for _, blockType := range visible {
+		pinned[blockType] = true
+		if _, err := tc.touch(blockType); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	tc.evict(pinned)
+	return firstErr
+}
+
+// Contains indica se a textura do bloco está carregada
+func (tc *TextureCache) Contains(blockType BlockType) bool {
+	_, exists := tc.entries[blockType]
+	return exists
+}
+
+// Len retorna quantas texturas estão carregadas
+func (tc *TextureCache) Len() int {
+	return len(tc.entries)
+}
+
+// Bytes retorna a VRAM estimada das texturas carregadas
+func (tc *TextureCache) Bytes() int64 {
+	return tc.bytes
+}
+
+// Clear descarrega todas as texturas
+func (tc *TextureCache) Clear() {
+	for tc.lru.Len() > 0 {
+		tc.remove(tc.lru.Back())
+	}
+}
+
+// touch carrega a textura se preciso e a move para a frente da LRU
+func (tc *TextureCache) touch(blockType BlockType) (rl.Texture2D, error) {
+	if elem, exists := tc.entries[blockType]; exists {
+		tc.lru.MoveToFront(elem)
+		return elem.Value.(*textureCacheEntry).texture, nil
+	}
+
+	texture, err := tc.loader.Load(blockType)
+	if err != nil {
+		return texture, fmt.Errorf("failed to load texture for block %d: %w", blockType, err)
+	}
+	tc.Loads++
+
+	entry := &textureCacheEntry{
+		blockType: blockType,
+		texture:   texture,
+		bytes:     int64(texture.Width) * int64(texture.Height) * 4,
+	}
+	tc.entries[blockType] = tc.lru.PushFront(entry)
+	tc.bytes += entry.bytes
+	return texture, nil
+}
+
+// evict descarrega as texturas usadas há mais tempo (exceto as de pinned) enquanto o cache
+// estiver acima do orçamento
+func (tc *TextureCache) evict(pinned map[BlockType]bool) {
+	elem := tc.lru.Back()
+	for elem != nil && tc.overBudget() {
+		prev := elem.Prev()
+		if !pinned[elem.Value.(*textureCacheEntry).blockType] {
+			tc.remove(elem)
+			tc.Evictions++
+		}
+		elem = prev
+	}
+}
+
+// overBudget indica se o cache passou de algum dos limites
+func (tc *TextureCache) overBudget() bool {
+	if tc.config.MaxTextures > 0 && len(tc.entries) > tc.config.MaxTextures {
+		return true
+	}
+	return tc.config.MaxBytes > 0 && tc.bytes > tc.config.MaxBytes
+}
+
+// remove descarrega a textura da entrada e a tira do cache
+func (tc *TextureCache) remove(elem *list.Element) {
+	entry := tc.lru.Remove(elem).(*textureCacheEntry)
+	delete(tc.entries, entry.blockType)
+	tc.bytes -= entry.bytes
+	tc.loader.Unload(entry.texture)
+}
@@ -0,0 +1,137 @@
+package game
+
+import (
+	"errors"
+	"testing"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// mockTextureLoader registra as texturas carregadas sem usar a GPU
+type mockTextureLoader struct {
+	size    int32
+	nextID  uint32
+	loaded  map[uint32]BlockType
+	failing map[BlockType]bool
+}
+
+func newMockTextureLoader(size int32) *mockTextureLoader {
+	return &mockTextureLoader{size: size, loaded: make(map[uint32]BlockType)}
+}
+
+func (m *mockTextureLoader) Load(blockType BlockType) (rl.Texture2D, error) {
+	if m.failing[blockType] {
+		return rl.Texture2D{}, errors.New("missing file")
+	}
+	m.nextID++
+	m.loaded[m.nextID] = blockType
+	return rl.Texture2D{ID: m.nextID, Width: m.size, Height: m.size}, nil
+}
+
+func (m *mockTextureLoader) Unload(texture rl.Texture2D) {
+	delete(m.loaded, texture.ID)
+}
+
+// Helper: tipos de bloco carregados no mock
+func (m *mockTextureLoader) loadedTypes() map[BlockType]bool {
+	types := make(map[BlockType]bool)
+	for _, blockType := range m.loaded {
+		types[blockType] = true
+	}
+	return types
+}
+
+func TestTextureCacheScrollLoadsAndEvicts(t *testing.T) {
+	loader := newMockTextureLoader(32)
+	cache := NewTextureCache(loader, TextureCacheConfig{MaxTextures: 6})
+
+	// Lista com 20 slots, 4 visíveis por vez
+	slots := make([]BlockType, 20)
+	for i := range slots {
+		slots[i] = BlockType(i + 1)
+	}
+	show := func(first int) {
+		t.Helper()
+		if err := cache.ShowSlots(slots[first : first+4]); err != nil {
+			t.Fatalf("Failed to show slots: %v", err)
+		}
+		for _, blockType := range slots[first : first+4] {
+			if !cache.Contains(blockType) {
+				t.Errorf("Visible slot %d should be loaded", blockType)
+			}
+		}
+	}
+
+	// Abrir a lista carrega só os slots visíveis
+	show(0)
+	if cache.Loads != 4 || cache.Len() != 4 {
+		t.Fatalf("Expected 4 textures loaded lazily, got %d loads and %d cached", cache.Loads, cache.Len())
+	}
+
+	// Rolar até o limite mantém os anteriores; passar dele descarrega os mais antigos
+	show(2)
+	if cache.Len() != 6 || cache.Evictions != 0 {
+		t.Fatalf("Expected 6 cached textures without evictions, got %d (%d evictions)", cache.Len(), cache.Evictions)
+	}
+	show(6)
+	if cache.Len() != 6 || cache.Evictions != 4 {
+		t.Fatalf("Expected 4 evictions keeping 6 textures, got %d (%d cached)", cache.Evictions, cache.Len())
+	}
+	for _, evicted := range slots[0:4] {
+		if cache.Contains(evicted) {
+			t.Errorf("Off-screen slot %d should have been evicted", evicted)
+		}
+	}
+	if len(loader.loaded) != cache.Len() {
+		t.Errorf("Evicted textures should be unloaded from the GPU: %d loaded, %d cached", len(loader.loaded), cache.Len())
+	}
+
+	// Voltar para um slot ainda em cache não recarrega
+	loads := cache.Loads
+	if _, err := cache.Get(slots[4]); err != nil {
+		t.Fatalf("Failed to get cached texture: %v", err)
+	}
+	if cache.Loads != loads {
+		t.Error("Cached texture should not be reloaded")
+	}
+
+	cache.Clear()
+	if cache.Len() != 0 || len(loader.loaded) != 0 || cache.Bytes() != 0 {
+		t.Errorf("Clear should unload every texture, %d still loaded", len(loader.loaded))
+	}
+}
+
+func TestTextureCacheVRAMBudget(t *testing.T) {
+	loader := newMockTextureLoader(64)
+	const textureBytes = 64 * 64 * 4
+	cache := NewTextureCache(loader, TextureCacheConfig{MaxBytes: 3 * textureBytes})
+
+	for blockType := BlockType(1); blockType <= 5; blockType++ {
+		if _, err := cache.Get(blockType); err != nil {
+			t.Fatalf("Failed to load texture: %v", err)
+		}
+	}
+	if cache.Len() != 3 || cache.Bytes() != 3*textureBytes {
+		t.Fatalf("Expected 3 textures within the VRAM budget, got %d (%d bytes)", cache.Len(), cache.Bytes())
+	}
+	if types := loader.loadedTypes(); !types[3] || !types[4] || !types[5] {
+		t.Errorf("Expected the most recently used textures to stay loaded, got %v", types)
+	}
+
+	// Slots visíveis além do orçamento ficam carregados enquanto estão na tela
+	if err := cache.ShowSlots([]BlockType{6, 7, 8, 9}); err != nil {
+		t.Fatalf("Failed to show slots: %v", err)
+	}
+	if cache.Len() != 4 {
+		t.Errorf("Visible textures should never be evicted, got %d cached", cache.Len())
+	}
+
+	// Erro de carga é reportado sem impedir os outros slots
+	loader.failing = map[BlockType]bool{10: true}
+	if err := cache.ShowSlots([]BlockType{10, 11}); err == nil {
+		t.Error("Expected error for a texture that fails to load")
+	}
+	if !cache.Contains(11) || cache.Contains(10) {
+		t.Error("Other visible slots should still be loaded")
+	}
+}
@@ -1,6 +1,9 @@
 package game
 
 import (
+	"fmt"
+	"math"
+
 	rl "github.com/gen2brain/raylib-go/raylib"
 )
 
@@ -12,20 +15,48 @@ type World struct {
 	StoneMesh        rl.Mesh
 	Material         rl.Material
 	TextureAtlas     rl.Texture2D
-	RenderDistance   int32
-	TerrainGenerator *TerrainGenerator
+	RenderDistance   int32 // Raio em chunks; alterações passam ao ChunkManager no próximo Update
+	TerrainGenerator TerrainGenerator
+
+	// Último RenderDistance repassado ao ChunkManager
+	appliedRenderDistance int32
+
+	// Relógio do ciclo dia/noite (cor do céu e luz dos chunks)
+	TimeOfDay TimeOfDay
 
 	// Sistema de atlas dinâmico
 	DynamicAtlas  *DynamicAtlasManager
 	VisibleBlocks *VisibleBlocksTracker
+	TextureSize   int32 // Lado das texturas em pixels (ver SupportedTextureSizes; 0 usa o padrão)
+
+	// Blocos criados pelo jogador (carregados de CustomBlocksDir em NewWorld)
+	CustomBlocks *CustomBlockManager
+
+	// Entidades (NPCs, itens) que não fazem parte da grade de blocos
+	entities     []*Entity
+	nextEntityID int
+
+	// Posição do jogador gravada por Save e restaurada por Load
+	PlayerPosition rl.Vector3
+
+	// OnBlockEdit, se definido, é chamado a cada SetBlock/SetBlockWithRotation (usado pelo
+	// netbridge para publicar as edições; edições direto no ChunkManager não passam por aqui)
+	OnBlockEdit func(x, y, z int32, block BlockType, rotation BlockRotation)
 }
 
 func NewWorld() *World {
-	renderDistance := int32(5)
+	renderDistance := int32(DefaultRenderDistance)
 	w := &World{
 		ChunkManager:     NewChunkManager(renderDistance),
 		RenderDistance:   renderDistance,
-		TerrainGenerator: NewTerrainGenerator(12345), // Seed fixo para testes
+		TerrainGenerator: NewTerrainGenerator(DefaultWorldSeed),
+		CustomBlocks:     NewCustomBlockManager(CustomBlocksDir),
+		TimeOfDay:        NewTimeOfDay(),
+
+		appliedRenderDistance: renderDistance,
+	}
+	if err := w.CustomBlocks.Load(); err != nil {
+		fmt.Printf("AVISO: Erro ao carregar blocos customizados: %v\n", err)
 	}
 	return w
 }
@@ -33,7 +64,11 @@ func NewWorld() *World {
 // InitWorldGraphics inicializa recursos gráficos do mundo (deve ser chamado após rl.InitWindow)
 func (w *World) InitWorldGraphics() {
 	// Inicializar atlas dinâmico 4x4
-	w.DynamicAtlas = NewDynamicAtlasManager(4, 32)
+	textureSize := w.TextureSize
+	if !IsSupportedTextureSize(textureSize) {
+		textureSize = DefaultTextureSize
+	}
+	w.DynamicAtlas = NewDynamicAtlasManager(4, textureSize)
 	w.VisibleBlocks = NewVisibleBlocksTracker()
 
 	// Carregar texturas de todos os tipos conhecidos
@@ -45,6 +80,13 @@ func (w *World) InitWorldGraphics() {
 		}
 	}
 
+	// Texturas dos blocos criados pelo jogador
+	if w.CustomBlocks != nil {
+		if err := w.CustomBlocks.RegisterTextures(w.DynamicAtlas); err != nil {
+			fmt.Printf("AVISO: Erro ao carregar texturas de blocos customizados: %v\n", err)
+		}
+	}
+
 	// Build inicial do atlas
 	w.DynamicAtlas.RebuildAtlas()
 	w.DynamicAtlas.UploadToGPU()
@@ -67,22 +109,188 @@ func (w *World) InitWorldGraphics() {
 }
 
 func (w *World) SetBlock(x, y, z int32, block BlockType) {
-	w.ChunkManager.SetBlock(x, y, z, block)
+	w.SetBlockWithRotation(x, y, z, block, Rotation0)
+}
+
+// SetBlockWithRotation coloca um bloco girado (ver BlockRotation)
+func (w *World) SetBlockWithRotation(x, y, z int32, block BlockType, rotation BlockRotation) {
+	w.ChunkManager.SetBlockWithRotation(x, y, z, block, rotation)
+	if w.OnBlockEdit != nil {
+		w.OnBlockEdit(x, y, z, block, rotation)
+	}
+}
+
+// GetBlockRotation retorna a rotação do bloco nas coordenadas mundiais
+func (w *World) GetBlockRotation(x, y, z int32) BlockRotation {
+	return w.ChunkManager.GetBlockRotation(x, y, z)
 }
 
 func (w *World) GetBlock(x, y, z int32) BlockType {
 	return w.ChunkManager.GetBlock(x, y, z)
 }
 
+// BlockName retorna o nome de exibição do tipo de bloco: o do bloco criado pelo jogador, o do
+// bloco embutido ou "Bloco N" para tipos sem nome. Ar não tem nome (retorna "").
+func (w *World) BlockName(blockType BlockType) string {
+	if blockType == BlockAir {
+		return ""
+	}
+	if w.CustomBlocks != nil {
+		if custom := w.CustomBlocks.Get(blockType); custom != nil {
+			return custom.Name
+		}
+	}
+	if name, exists := BlockNames[blockType]; exists {
+		return name
+	}
+	return fmt.Sprintf("Bloco %d", blockType)
+}
+
 func (w *World) IsBlockHidden(x, y, z int32) bool {
 	return w.ChunkManager.IsBlockHidden(x, y, z)
 }
 
+// RaycastHit resultado de um raycast no mundo: o bloco ou a entidade mais próxima atingida
+type RaycastHit struct {
+	Hit      bool
+	Distance float32    // Distância da origem até o ponto de entrada no alvo
+	Entity   *Entity    // Entidade atingida (nil se o alvo é um bloco)
+	Block    rl.Vector3 // Bloco atingido
+	Place    rl.Vector3 // Voxel vazio antes do bloco (onde um bloco seria colocado)
+}
+
+// Raycast retorna o alvo mais próximo (bloco ou entidade) na direção dir, até maxDistance
+func (w *World) Raycast(origin, dir rl.Vector3, maxDistance float32) RaycastHit {
+	block := w.RaycastBlocks(origin, dir, maxDistance)
+	entity := w.RaycastEntities(origin, dir, maxDistance)
+
+	if entity.Hit && (!block.Hit || entity.Distance < block.Distance) {
+		return entity
+	}
+	return block
+}
+
+// RaycastBlocks percorre os voxels do raio (DDA) e retorna o primeiro bloco sólido até maxDistance
+func (w *World) RaycastBlocks(rayOrigin, rayDir rl.Vector3, maxDistance float32) RaycastHit {
+	// PosiÃ§Ã£o inicial do voxel
+	voxelX := int32(math.Floor(float64(rayOrigin.X)))
+	voxelY := int32(math.Floor(float64(rayOrigin.Y)))
+	voxelZ := int32(math.Floor(float64(rayOrigin.Z)))
+
+	// DireÃ§Ã£o do passo (1 ou -1)
+	stepX := int32(1)
+	if rayDir.X < 0 {
+		stepX = -1
+	}
+	stepY := int32(1)
+	if rayDir.Y < 0 {
+		stepY = -1
+	}
+	stepZ := int32(1)
+	if rayDir.Z < 0 {
+		stepZ = -1
+	}
+
+	// Calcular tMax e tDelta
+	var tMaxX, tMaxY, tMaxZ float32
+	var tDeltaX, tDeltaY, tDeltaZ float32
+
+	if rayDir.X != 0 {
+		if rayDir.X > 0 {
+			tMaxX = (float32(voxelX+1) - rayOrigin.X) / rayDir.X
+		} else {
+			tMaxX = (float32(voxelX) - rayOrigin.X) / rayDir.X
+		}
+		tDeltaX = float32(math.Abs(float64(1.0 / rayDir.X)))
+	} else {
+		tMaxX = float32(math.MaxFloat32)
+		tDeltaX = float32(math.MaxFloat32)
+	}
+
+	if rayDir.Y != 0 {
+		if rayDir.Y > 0 {
+			tMaxY = (float32(voxelY+1) - rayOrigin.Y) / rayDir.Y
+		} else {
+			tMaxY = (float32(voxelY) - rayOrigin.Y) / rayDir.Y
+		}
+		tDeltaY = float32(math.Abs(float64(1.0 / rayDir.Y)))
+	} else {
+		tMaxY = float32(math.MaxFloat32)
+		tDeltaY = float32(math.MaxFloat32)
+	}
+
+	if rayDir.Z != 0 {
+		if rayDir.Z > 0 {
+			tMaxZ = (float32(voxelZ+1) - rayOrigin.Z) / rayDir.Z
+		} else {
+			tMaxZ = (float32(voxelZ) - rayOrigin.Z) / rayDir.Z
+		}
+		tDeltaZ = float32(math.Abs(float64(1.0 / rayDir.Z)))
+	} else {
+		tMaxZ = float32(math.MaxFloat32)
+		tDeltaZ = float32(math.MaxFloat32)
+	}
+
+	// Armazenar voxel anterior para colocaÃ§Ã£o de blocos
+	prevVoxelX, prevVoxelY, prevVoxelZ := voxelX, voxelY, voxelZ
+
+	// DDA traversal
+	for t := float32(0); t < maxDistance; {
+		// Verificar se o voxel atual contÃ©m um bloco
+		if w.GetBlock(voxelX, voxelY, voxelZ) != BlockAir {
+			return RaycastHit{
+				Hit:      true,
+				Distance: t,
+				Block:    rl.NewVector3(float32(voxelX), float32(voxelY), float32(voxelZ)),
+				Place:    rl.NewVector3(float32(prevVoxelX), float32(prevVoxelY), float32(prevVoxelZ)),
+			}
+		}
+
+		// Armazenar voxel atual antes de avanÃ§ar
+		prevVoxelX, prevVoxelY, prevVoxelZ = voxelX, voxelY, voxelZ
+
+		// AvanÃ§ar para o prÃ³ximo voxel
+		if tMaxX < tMaxY {
+			if tMaxX < tMaxZ {
+				voxelX += stepX
+				t = tMaxX
+				tMaxX += tDeltaX
+			} else {
+				voxelZ += stepZ
+				t = tMaxZ
+				tMaxZ += tDeltaZ
+			}
+		} else {
+			if tMaxY < tMaxZ {
+				voxelY += stepY
+				t = tMaxY
+				tMaxY += tDeltaY
+			} else {
+				voxelZ += stepZ
+				t = tMaxZ
+				tMaxZ += tDeltaZ
+			}
+		}
+	}
+
+	return RaycastHit{}
+}
+
 // Update atualiza o mundo (carrega/descarrega chunks baseado na posição do jogador)
 func (w *World) Update(playerPos rl.Vector3, dt float32) {
+	// Nova distância de renderização (configurações): descarregar já o que ficou fora do raio
+	if w.RenderDistance != w.appliedRenderDistance {
+		w.ChunkManager.SetRenderDistance(w.RenderDistance)
+		w.ChunkManager.UnloadDistantChunks(playerPos)
+		w.appliedRenderDistance = w.RenderDistance
+	}
+
 	// Atualizar chunks (carrega/descarrega)
 	w.ChunkManager.Update(playerPos, dt, w.TerrainGenerator)
 
+	// Passar o tempo do ciclo dia/noite
+	w.TimeOfDay.Advance(dt)
+
 	// Gerenciar atlas dinamicamente (apenas quando necessário)
 	w.UpdateDynamicAtlas()
 }
@@ -122,15 +330,17 @@ func (w *World) UpdateDynamicAtlas() {
 			}
 		}
 
-		// Verificar se os tipos encontrados estão no atlas
+		// Verificar se os tipos encontrados (e as texturas das suas faces) estão no atlas
 		for blockType := range uniqueTypes {
-			w.DynamicAtlas.mu.RLock()
-			_, exists := w.DynamicAtlas.BlockToSlot[blockType]
-			w.DynamicAtlas.mu.RUnlock()
+			for _, texture := range blockTextures(blockType) {
+				w.DynamicAtlas.mu.RLock()
+				_, exists := w.DynamicAtlas.BlockToSlot[texture]
+				w.DynamicAtlas.mu.RUnlock()
 
-			if !exists {
-				w.DynamicAtlas.AllocateSlot(blockType)
-				atlasChanged = true
+				if !exists {
+					w.DynamicAtlas.AllocateSlot(texture)
+					atlasChanged = true
+				}
 			}
 		}
 	}
@@ -151,8 +361,26 @@ func (w *World) UpdateDynamicAtlas() {
 	}
 }
 
-func (w *World) Render(playerPos rl.Vector3) {
-	w.ChunkManager.Render(w.GrassMesh, w.DirtMesh, w.StoneMesh, w.Material, playerPos, w.VisibleBlocks, w.DynamicAtlas)
+// Render desenha os chunks visíveis pela câmera, com a luz da hora do dia, e as entidades
+func (w *World) Render(playerPos rl.Vector3, camera rl.Camera3D) {
+	w.ChunkManager.Light = w.TimeOfDay.Light(w.ChunkManager.Fog.Color)
+	frustum := NewCameraFrustum(camera, float32(ScreenWidth)/float32(ScreenHeight))
+	w.ChunkManager.Render(w.GrassMesh, w.DirtMesh, w.StoneMesh, w.Material, playerPos, w.VisibleBlocks, w.DynamicAtlas, &frustum)
+	w.RenderEntities()
+}
+
+// SkyColor retorna a cor do céu na hora atual (a cor de dia é a da neblina configurada)
+func (w *World) SkyColor() rl.Color {
+	return SkyColorAt(w.TimeOfDay.Hour, w.ChunkManager.Fog.Color)
+}
+
+// GetBiome retorna o bioma da coluna (x, z) (planície se o gerador de terreno não tiver
+// biomas)
+func (w *World) GetBiome(x, z int32) Biome {
+	if source, ok := w.TerrainGenerator.(BiomeSource); ok {
+		return source.BiomeAt(x, z)
+	}
+	return BiomePlains
 }
 
 // GetTotalBlocks retorna o número total de blocos (para debug/UI)
@@ -160,6 +388,12 @@ func (w *World) GetTotalBlocks() int {
 	return w.ChunkManager.GetTotalBlocks()
 }
 
+// GetCullingStats retorna quantos chunks foram desenhados e quantos ficaram fora da câmera no
+// último Render (para debug/UI)
+func (w *World) GetCullingStats() (drawn, culled int) {
+	return w.ChunkManager.ChunksDrawn, w.ChunkManager.ChunksCulled
+}
+
 // GetLoadedChunksCount retorna o número de chunks carregados (para debug/UI)
 func (w *World) GetLoadedChunksCount() int {
 	return w.ChunkManager.GetLoadedChunksCount()
@@ -0,0 +1,157 @@
+package game
+
+import (
+	"compress/gzip"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"os"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// WorldSaveFile é o arquivo padrão do save do mundo
+const WorldSaveFile = "world.sav"
+
+// worldSaveVersion versão do formato do save (incrementar ao mudar savedWorld)
+const worldSaveVersion = 1
+
+// savedWorld é o conteúdo serializado de um save: semente do terreno, posição do jogador e
+// os blocos dos chunks que diferem da geração procedural
+type savedWorld struct {
+	Version        int
+	Seed           int64
+	PlayerPosition rl.Vector3
+	Chunks         []savedChunk
+}
+
+// savedChunk blocos de um chunk editado (também guarda as edições de chunks descarregados).
+// Saves anteriores às rotações carregam com todos os blocos na orientação padrão.
+type savedChunk struct {
+	Coord     ChunkCoord
+	Blocks    [ChunkSize][ChunkHeight][ChunkSize]BlockType
+	Rotations [ChunkSize][ChunkHeight][ChunkSize]BlockRotation
+}
+
+// newSavedChunk copia os blocos e as rotações do chunk
+func newSavedChunk(c *Chunk) *savedChunk {
+	return &savedChunk{Coord: c.Coord, Blocks: c.Blocks, Rotations: c.Rotations}
+}
+
+// restore aplica as edições guardadas ao chunk
+func (sc *savedChunk) restore(c *Chunk) {
+	c.Blocks = sc.Blocks
+	c.Rotations = sc.Rotations
+	c.Modified = true
+}
+
+// Save grava o mundo em um arquivo binário (gob comprimido com gzip): a posição do jogador
+// (PlayerPosition) e os chunks editados, carregados ou não, que diferem do terreno gerado.
+// Chunks sem edições são recriados pelo gerador ao carregar.
+func (w *World) Save(path string) error {
+	saved := savedWorld{
+		Version:        worldSaveVersion,
+		PlayerPosition: w.PlayerPosition,
+	}
+	if w.TerrainGenerator != nil {
+		saved.Seed = w.TerrainGenerator.WorldSeed()
+	}
+
+	cm := w.ChunkManager
+	for _, chunk := range cm.Chunks {
+		if chunk.Modified {
+			if edited := newSavedChunk(chunk); w.differsFromGenerated(edited) {
+				saved.Chunks = append(saved.Chunks, *edited)
+			}
+		}
+	}
+	for _, edited := range cm.editedChunks {
+		if w.differsFromGenerated(edited) {
+			saved.Chunks = append(saved.Chunks, *edited)
+		}
+	}
+
+	// Escreve em arquivo temporário e renomeia para não deixar um save pela metade
+	tmp := path + ".tmp"
+	file, err := os.Create(tmp)
+	if err != nil {
+		return fmt.Errorf("failed to create world save: %w", err)
+	}
+
+	zw := gzip.NewWriter(file)
+	encodeErr := gob.NewEncoder(zw).Encode(&saved)
+	closeErr := errors.Join(zw.Close(), file.Close())
+	if encodeErr != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to encode world save: %w", encodeErr)
+	}
+	if closeErr != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write world save: %w", closeErr)
+	}
+
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write world save: %w", err)
+	}
+
+	return nil
+}
+
+// Load substitui o mundo pelo save do arquivo: restaura a semente do terreno e a posição do
+// jogador (PlayerPosition) e carrega os chunks editados. Os demais chunks são descartados e
+// gerados de novo ao redor do jogador.
+func (w *World) Load(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open world save: %w", err)
+	}
+	defer file.Close()
+
+	zr, err := gzip.NewReader(file)
+	if err != nil {
+		return fmt.Errorf("failed to read world save: %w", err)
+	}
+	defer zr.Close()
+
+	var saved savedWorld
+	if err := gob.NewDecoder(zr).Decode(&saved); err != nil {
+		return fmt.Errorf("failed to decode world save: %w", err)
+	}
+	if saved.Version != worldSaveVersion {
+		return fmt.Errorf("unsupported world save version %d", saved.Version)
+	}
+
+	// Um gerador injetado com a mesma seed é mantido; com outra seed, volta ao gerador padrão
+	if w.TerrainGenerator == nil || w.TerrainGenerator.WorldSeed() != saved.Seed {
+		w.TerrainGenerator = NewTerrainGenerator(saved.Seed)
+	}
+	w.PlayerPosition = saved.PlayerPosition
+
+	cm := w.ChunkManager
+	for _, chunk := range cm.Chunks {
+		chunk.Unload()
+	}
+	cm.Chunks = make(map[int64]*Chunk)
+	cm.editedChunks = make(map[int64]*savedChunk)
+	cm.NewChunksLoaded = true
+	for _, sc := range saved.Chunks {
+		chunk := NewChunk(sc.Coord.X, sc.Coord.Y, sc.Coord.Z)
+		chunk.AmbientOcclusion = cm.AmbientOcclusion
+		chunk.GreedyMeshing = cm.GreedyMeshing
+		sc.restore(chunk)
+		chunk.IsGenerated = true
+		cm.Chunks[sc.Coord.Key()] = chunk
+	}
+
+	return nil
+}
+
+// differsFromGenerated indica se os blocos ou as rotações diferem do que o gerador produz para
+// o chunk (sem gerador, o terreno gerado é vazio; blocos gerados nunca são girados)
+func (w *World) differsFromGenerated(edited *savedChunk) bool {
+	generated := &Chunk{Coord: edited.Coord}
+	if w.TerrainGenerator != nil {
+		generated.GenerateTerrainWithGenerator(w.TerrainGenerator)
+	}
+	return generated.Blocks != edited.Blocks || generated.Rotations != edited.Rotations
+}
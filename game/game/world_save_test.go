@@ -0,0 +1,111 @@
+package game
+
+import (
+	"path/filepath"
+	"testing"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// Helper: mundo com os chunks ao redor da origem gerados pelo terreno procedural
+func newGeneratedTestWorld() *World {
+	world := NewWorld()
+	for i := 0; i < 3; i++ {
+		world.ChunkManager.LoadChunksAroundPlayer(rl.NewVector3(0, 0, 0), world.TerrainGenerator)
+	}
+	return world
+}
+
+func TestWorldSaveAndLoad(t *testing.T) {
+	world := newGeneratedTestWorld()
+	if _, loaded := world.ChunkManager.Chunks[ChunkCoord{}.Key()]; !loaded {
+		t.Fatal("Origin chunk should be loaded")
+	}
+
+	// Edições no chunk da origem
+	world.SetBlock(1, 20, 1, BlockStone)
+	world.SetBlock(2, 5, 2, BlockAir)
+	world.SetBlock(31, 31, 31, BlockGrass)
+
+	// Edição desfeita em outro chunk: volta a ser igual ao terreno gerado
+	original := world.GetBlock(-5, 3, -5)
+	world.SetBlock(-5, 3, -5, BlockGrass)
+	world.SetBlock(-5, 3, -5, original)
+
+	world.PlayerPosition = rl.NewVector3(3.5, 21, -7.25)
+
+	path := filepath.Join(t.TempDir(), "world.sav")
+	if err := world.Save(path); err != nil {
+		t.Fatalf("Failed to save world: %v", err)
+	}
+
+	loaded := NewWorld()
+	if err := loaded.Load(path); err != nil {
+		t.Fatalf("Failed to load world: %v", err)
+	}
+
+	if loaded.PlayerPosition != world.PlayerPosition {
+		t.Errorf("Expected player position %v, got %v", world.PlayerPosition, loaded.PlayerPosition)
+	}
+
+	// Só o chunk que difere da geração é gravado
+	if len(loaded.ChunkManager.Chunks) != 1 {
+		t.Fatalf("Expected only the edited chunk to be saved, got %d chunks", len(loaded.ChunkManager.Chunks))
+	}
+
+	for _, pos := range [][3]int32{{1, 20, 1}, {2, 5, 2}, {31, 31, 31}} {
+		if got, want := loaded.GetBlock(pos[0], pos[1], pos[2]), world.GetBlock(pos[0], pos[1], pos[2]); got != want {
+			t.Errorf("Block at %v: expected %d, got %d", pos, want, got)
+		}
+	}
+	if loaded.ChunkManager.Chunks[ChunkCoord{}.Key()].Blocks != world.ChunkManager.Chunks[ChunkCoord{}.Key()].Blocks {
+		t.Error("Loaded chunk should match the saved chunk block by block")
+	}
+
+	// Chunks não gravados voltam do gerador com o terreno original
+	loaded.ChunkManager.LoadChunksAroundPlayer(rl.NewVector3(0, 0, 0), loaded.TerrainGenerator)
+	loaded.ChunkManager.LoadChunksAroundPlayer(rl.NewVector3(0, 0, 0), loaded.TerrainGenerator)
+	if got := loaded.GetBlock(-5, 3, -5); got != original {
+		t.Errorf("Unsaved chunk should be regenerated: expected %d, got %d", original, got)
+	}
+	if got := loaded.GetBlock(1, 20, 1); got != BlockStone {
+		t.Errorf("Reloading around the player should keep the saved edit, got %d", got)
+	}
+}
+
+func TestWorldSaveKeepsUnloadedEdits(t *testing.T) {
+	world := newGeneratedTestWorld()
+	world.SetBlock(4, 25, 4, BlockStone)
+
+	// Jogador se afasta: o chunk editado é descarregado
+	world.ChunkManager.UnloadDistantChunks(rl.NewVector3(10000, 0, 10000))
+	if len(world.ChunkManager.Chunks) != 0 {
+		t.Fatalf("Expected all chunks to be unloaded, got %d", len(world.ChunkManager.Chunks))
+	}
+
+	path := filepath.Join(t.TempDir(), "world.sav")
+	if err := world.Save(path); err != nil {
+		t.Fatalf("Failed to save world: %v", err)
+	}
+
+	loaded := NewWorld()
+	if err := loaded.Load(path); err != nil {
+		t.Fatalf("Failed to load world: %v", err)
+	}
+	if got := loaded.GetBlock(4, 25, 4); got != BlockStone {
+		t.Errorf("Edit in unloaded chunk should be saved, got %d", got)
+	}
+
+	// Voltando, o chunk é recarregado com a edição em vez do terreno gerado
+	world.ChunkManager.LoadChunksAroundPlayer(rl.NewVector3(0, 0, 0), world.TerrainGenerator)
+	if got := world.GetBlock(4, 25, 4); got != BlockStone {
+		t.Errorf("Reloaded chunk should keep the edit, got %d", got)
+	}
+}
+
+func TestWorldLoadMissingFile(t *testing.T) {
+	world := NewWorld()
+	if err := world.Load(filepath.Join(t.TempDir(), "missing.sav")); err == nil {
+		t.Error("Expected error loading a missing save")
+	}
+}
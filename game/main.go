@@ -1,14 +1,34 @@
 package main
 
 import (
+	"errors"
+	"flag"
 	"fmt"
+	"math"
+	"os"
 
 	rl "github.com/gen2brain/raylib-go/raylib"
 
 	"krakovia/game"
+	"krakovia/netbridge"
 )
 
 func main() {
+	nodeURL := flag.String("node", "", "URL da API de um nó da blockchain para visualizar blocos minerados (ex: http://localhost:8080)")
+	nodeUser := flag.String("node-user", "", "Usuário da API do nó")
+	nodePass := flag.String("node-pass", "", "Senha da API do nó")
+	observe := flag.Bool("observer", false, "Modo observador: empilha na origem do mundo um bloco por bloco da chain do nó (-node), na cor do validador que o produziu")
+	worldAddress := flag.String("world-address", "", "Compartilha o mundo pela chain do nó (-node): as edições viram transações para este endereço")
+	seed := flag.Int64("seed", game.DefaultWorldSeed, "Seed do gerador de terreno (ignorada ao carregar um mundo salvo com outra seed)")
+	newBlockName := flag.String("new-block", "", "Cria um bloco customizado com este nome (exige -new-block-texture) e o coloca no slot selecionado da hotbar")
+	newBlockTexture := flag.String("new-block-texture", "", "Textura PNG do bloco criado com -new-block (no tamanho texture_size)")
+	newBlockCategory := flag.String("new-block-category", "", "Aba do catálogo do bloco criado com -new-block (natural, decorative ou custom; padrão custom)")
+	maxCustomBlocks := flag.Int("max-custom-blocks", game.DefaultMaxCustomBlocks, "Máximo de blocos customizados que podem ser criados")
+	maxTextureKB := flag.Int64("max-custom-texture-kb", game.DefaultMaxCustomTextureBytes/1024, "Tamanho máximo em KB da textura PNG de um bloco customizado")
+	catalogColumns := flag.Int("catalog-columns", game.DefaultCatalogColumns, "Colunas da grade do catálogo de blocos (tecla E)")
+	catalogRows := flag.Int("catalog-rows", game.DefaultCatalogRows, "Linhas visíveis da grade do catálogo de blocos (as demais rolam)")
+	flag.Parse()
+
 	rl.SetTraceLogLevel(rl.LogWarning)
 
 	rl.InitWindow(game.ScreenWidth, game.ScreenHeight, "Krakovia")
@@ -20,15 +40,118 @@ func main() {
 	// Inicializar jogador
 	player := game.NewPlayer(rl.NewVector3(16, 16, 16))
 
+	// Carregar configurações salvas (câmera, mouse, distância de renderização e gráficos)
+	settings, err := game.LoadSettings(game.SettingsFile)
+	if err != nil {
+		fmt.Printf("Erro ao carregar configurações, usando padrão: %v\n", err)
+	}
+	player.ApplySettings(settings)
+
 	// Inicializar mundo
 	world := game.NewWorld()
+	world.TerrainGenerator = game.NewTerrainGenerator(*seed)
+	world.RenderDistance = player.Settings.RenderDistance
+
+	// Nascer sobre o terreno (as colinas podem passar da altura inicial)
+	player.Position.Y = float32(game.SpawnHeight(world.TerrainGenerator, 16, 16, 64)) + 1
 
-	// Inicializar gráficos do mundo (depois de InitWindow)
+	// Inicializar gráficos do mundo (depois de InitWindow), com o tamanho de textura configurado
+	world.TextureSize = player.Settings.TextureSize
 	world.InitWorldGraphics()
 
+	// Restaurar o mundo salvo (edições do jogador e posição)
+	if err := world.Load(game.WorldSaveFile); err == nil {
+		player.Position = world.PlayerPosition
+		fmt.Printf("Mundo carregado de %s\n", game.WorldSaveFile)
+	} else if !errors.Is(err, os.ErrNotExist) {
+		fmt.Printf("Erro ao carregar mundo, gerando um novo: %v\n", err)
+	}
+
+	// Salvar o mundo ao fechar a janela
+	defer saveWorld(world, player)
+
+	// Hotbar da última sessão (teclas 1 a 9), salva ao fechar
+	hotbar, err := game.LoadBlockHotbar(game.HotbarFile)
+	if err != nil {
+		fmt.Printf("Erro ao carregar hotbar, usando padrão: %v\n", err)
+	}
+	defer func() {
+		if err := hotbar.Save(game.HotbarFile); err != nil {
+			fmt.Printf("Erro ao salvar hotbar: %v\n", err)
+		}
+	}()
+
+	// Criar bloco customizado pela linha de comando (fica salvo em game.CustomBlocksDir)
+	world.CustomBlocks.MaxBlocks = *maxCustomBlocks
+	world.CustomBlocks.MaxTextureBytes = *maxTextureKB * 1024
+	if *newBlockName != "" {
+		block, err := world.CustomBlocks.Create(*newBlockName, *newBlockTexture, false, world.DynamicAtlas)
+		if err != nil {
+			fmt.Printf("Erro ao criar bloco %q: %v\n", *newBlockName, err)
+		} else {
+			if *newBlockCategory != "" {
+				if err := world.CustomBlocks.SetCategory(block.Type, game.BlockCategory(*newBlockCategory)); err != nil {
+					fmt.Printf("Erro ao definir a categoria do bloco %q: %v\n", block.Name, err)
+				}
+			}
+			hotbar.Assign(hotbar.Selected, block.Type)
+			fmt.Printf("Bloco %q criado no slot %d da hotbar (%d/%d blocos customizados)\n",
+				block.Name, hotbar.Selected+1, world.CustomBlocks.Count(), world.CustomBlocks.MaxBlocks)
+		}
+	}
+
+	// Oclusão ambiente conforme as configurações salvas
+	world.ChunkManager.SetAmbientOcclusion(player.Settings.AmbientOcclusion)
+	world.ChunkManager.SetGreedyMeshing(player.Settings.GreedyMeshing)
+	world.ChunkManager.Fog = game.NewFog(player.Settings, world.RenderDistance)
+
+	// Reaproveitar meshes de chunks que não mudaram desde a última sessão
+	world.ChunkManager.MeshCache = game.NewChunkMeshCache(game.MeshCacheDir)
+
+	// Visualizador de blocos minerados (sem nó conectado não faz nada)
+	var blockSource game.BlockEventSource
+	if *nodeURL != "" {
+		feed := game.NewNodeBlockFeed(*nodeURL, *nodeUser, *nodePass)
+		feed.Start()
+		defer feed.Stop()
+		blockSource = feed
+	} else if *observe {
+		fmt.Println("Modo observador sem -node: nenhum bloco será recebido")
+	}
+
+	// No modo observador os eventos vão para a torre na origem, e o jogador voa ao lado dela
+	var observer *game.ObserverMode
+	if *observe {
+		base := game.SpawnHeight(world.TerrainGenerator, 0, 0, 64)
+		observer = game.NewObserverMode(blockSource, 0, base, 0)
+		blockSource = nil
+
+		player.FlyMode = true
+		player.Position = rl.NewVector3(float32(observer.Side)*2, float32(base+observer.Side), float32(observer.Side)*2)
+	}
+	blockViewer := game.NewBlockViewer(blockSource)
+
+	// Mundo compartilhado: edições publicadas como transações e as dos outros jogadores
+	// aplicadas conforme os blocos são minerados
+	var bridge *netbridge.Bridge
+	if *nodeURL != "" && *worldAddress != "" {
+		bridge = netbridge.New(world, netbridge.NewNodeLedger(*nodeURL, *nodeUser, *nodePass, *worldAddress))
+		bridge.Start()
+		defer bridge.Stop()
+	} else if *worldAddress != "" {
+		fmt.Println("Mundo compartilhado sem -node: as edições ficam só neste mundo")
+	}
+
 	// Input real do Raylib
 	input := &game.RaylibInput{}
 
+	// Tela de configurações (tecla O)
+	settingsMenu := &game.SettingsMenu{}
+
+	// Catálogo de blocos (tecla E) com os embutidos e os criados pelo jogador
+	catalog := game.NewBlockCatalog(*catalogColumns, *catalogRows)
+	catalog.SetBlocks(game.CatalogBlocks(world.CustomBlocks))
+
 	// Loop principal do jogo
 	for !rl.WindowShouldClose() {
 		dt := rl.GetFrameTime()
@@ -65,20 +188,151 @@ func main() {
 			}
 		}
 
+		if rl.IsKeyPressed(rl.KeyF4) {
+			// F4: quick-save do mundo
+			saveWorld(world, player)
+		}
+
+		// F5/F6: diminuir/aumentar FOV | F7/F8: diminuir/aumentar sensibilidade do mouse | F9: oclusão ambiente | F10: greedy meshing | F11: neblina
+		settings := player.Settings
+		if rl.IsKeyPressed(rl.KeyF5) {
+			settings.FOV -= 5
+		}
+		if rl.IsKeyPressed(rl.KeyF6) {
+			settings.FOV += 5
+		}
+		if rl.IsKeyPressed(rl.KeyF7) {
+			settings.MouseSensitivity -= 0.0005
+		}
+		if rl.IsKeyPressed(rl.KeyF8) {
+			settings.MouseSensitivity += 0.0005
+		}
+		if rl.IsKeyPressed(rl.KeyF9) {
+			settings.AmbientOcclusion = !settings.AmbientOcclusion
+		}
+		if rl.IsKeyPressed(rl.KeyF10) {
+			settings.GreedyMeshing = !settings.GreedyMeshing
+		}
+		if rl.IsKeyPressed(rl.KeyF11) {
+			settings.Fog = !settings.Fog
+		}
+
+		// O: abre/fecha a tela de configurações (com ela aberta o mouse fica livre e o jogador parado)
+		if rl.IsKeyPressed(rl.KeyO) && !catalog.Open {
+			settingsMenu.Toggle()
+			if settingsMenu.Open {
+				rl.EnableCursor()
+			} else {
+				rl.DisableCursor()
+			}
+		}
+		if settingsMenu.Open {
+			if rl.IsKeyPressed(rl.KeyUp) {
+				settingsMenu.Move(-1)
+			}
+			if rl.IsKeyPressed(rl.KeyDown) {
+				settingsMenu.Move(1)
+			}
+			if rl.IsKeyPressed(rl.KeyLeft) {
+				settingsMenu.Adjust(&settings, -1)
+			}
+			if rl.IsKeyPressed(rl.KeyRight) {
+				settingsMenu.Adjust(&settings, 1)
+			}
+		}
+
+		// E: abre/fecha o catálogo de blocos | Tab: próxima aba | setas: escolher | Enter: coloca o
+		// bloco escolhido no slot selecionado da hotbar
+		if rl.IsKeyPressed(rl.KeyE) && !settingsMenu.Open {
+			catalog.Toggle()
+		}
+		if catalog.Open {
+			if rl.IsKeyPressed(rl.KeyTab) {
+				catalog.NextCategory()
+			}
+			if rl.IsKeyPressed(rl.KeyUp) {
+				catalog.Move(0, -1)
+			}
+			if rl.IsKeyPressed(rl.KeyDown) {
+				catalog.Move(0, 1)
+			}
+			if rl.IsKeyPressed(rl.KeyLeft) {
+				catalog.Move(-1, 0)
+			}
+			if rl.IsKeyPressed(rl.KeyRight) {
+				catalog.Move(1, 0)
+			}
+			if rl.IsKeyPressed(rl.KeyEnter) {
+				if err := hotbar.Assign(hotbar.Selected, catalog.SelectedBlock()); err == nil {
+					catalog.Toggle()
+				}
+			}
+		}
+
+		// N: congela/descongela o ciclo dia/noite (para screenshots) | -/=: metade/dobro da velocidade do tempo
+		if rl.IsKeyPressed(rl.KeyN) {
+			world.TimeOfDay.Frozen = !world.TimeOfDay.Frozen
+		}
+		if rl.IsKeyPressed(rl.KeyMinus) {
+			world.TimeOfDay.Speed /= 2
+		}
+		if rl.IsKeyPressed(rl.KeyEqual) {
+			world.TimeOfDay.Speed *= 2
+		}
+
+		// 1 a 9: selecionar o slot da hotbar
+		for slot := 0; slot < game.HotbarSize; slot++ {
+			if rl.IsKeyPressed(int32(rl.KeyOne) + int32(slot)) {
+				hotbar.Select(slot)
+			}
+		}
+		player.PlaceBlockType = hotbar.SelectedBlock()
+
+		// T: liga/desliga a transparência do tipo de bloco mirado
+		if rl.IsKeyPressed(rl.KeyT) && player.LookingAtBlock {
+			blockType := world.GetBlock(int32(player.TargetBlock.X), int32(player.TargetBlock.Y), int32(player.TargetBlock.Z))
+			world.ChunkManager.SetBlockTransparency(blockType, !game.IsTransparentBlock(blockType))
+		}
+
+		if settings != player.Settings {
+			player.ApplySettings(settings)
+			world.ChunkManager.SetAmbientOcclusion(player.Settings.AmbientOcclusion)
+			world.ChunkManager.SetGreedyMeshing(player.Settings.GreedyMeshing)
+			world.RenderDistance = player.Settings.RenderDistance
+			world.ChunkManager.Fog = game.NewFog(player.Settings, world.RenderDistance)
+			if err := player.Settings.Save(game.SettingsFile); err != nil {
+				fmt.Printf("Erro ao salvar configurações: %v\n", err)
+			}
+		}
+
 		// Atualizar mundo (carrega/descarrega chunks baseado na posição do jogador)
 		world.Update(player.Position, dt)
 
+		// Colocar blocos comemorativos dos blocos minerados
+		blockViewer.Update(world)
+		observer.Update(world)
+
+		// Aplicar edições dos outros jogadores
+		bridge.Update()
+
 		// Atualizar jogador
-		player.Update(dt, world, input)
+		if !settingsMenu.Open && !catalog.Open {
+			player.Update(dt, world, input)
+		}
 
 		// Renderizar
 		rl.BeginDrawing()
-		rl.ClearBackground(rl.SkyBlue)
+		// Céu na cor da hora do dia (a mesma da neblina): os chunks distantes somem nele
+		rl.ClearBackground(world.SkyColor())
 
 		rl.BeginMode3D(player.Camera)
 
 		// Renderizar mundo
-		world.Render(player.Position)
+		world.Render(player.Position, player.Camera)
+
+		// Destacar blocos comemorativos
+		blockViewer.Render()
+		observer.Render()
 
 		// Renderizar jogador como cápsula
 		player.RenderPlayer()
@@ -94,20 +348,38 @@ func main() {
 			rl.DrawCubeWiresV(centerPos, rl.NewVector3(1.01, 1.01, 1.01), rl.Red)
 		}
 
+		// Destacar a entidade mirada
+		if player.LookingAtEntity {
+			rl.DrawBoundingBox(player.TargetEntity.BoundingBox(), rl.Red)
+		}
+
 		rl.EndMode3D()
 
 		// UI
-		renderUI(player, world)
+		renderUI(player, world, blockViewer, observer, hotbar)
+		renderSettingsMenu(settingsMenu, player.Settings)
+		renderCatalog(world, catalog)
 
 		rl.EndDrawing()
 	}
 }
 
+// saveWorld grava o mundo e a posição do jogador em game.WorldSaveFile
+func saveWorld(world *game.World, player *game.Player) {
+	world.PlayerPosition = player.Position
+	if err := world.Save(game.WorldSaveFile); err != nil {
+		fmt.Printf("Erro ao salvar mundo: %v\n", err)
+		return
+	}
+	fmt.Printf("Mundo salvo em %s\n", game.WorldSaveFile)
+}
+
 // renderUI desenha a interface do usuário
-func renderUI(player *game.Player, world *game.World) {
+func renderUI(player *game.Player, world *game.World, blockViewer *game.BlockViewer, observer *game.ObserverMode, hotbar *game.BlockHotbar) {
 	rl.DrawText("WASD - Mover | Espaço - Pular | Mouse - Olhar | P - Fly Mode | K - Collision Body", 10, 10, 20, rl.Black)
-	rl.DrawText("Click Esquerdo - Remover | Click Direito - Colocar | V - Alternar Câmera", 10, 35, 20, rl.Black)
-	rl.DrawText("F1 - Atlas Stats | F2 - Save Atlas | F3 - Visible Blocks", 10, 60, 20, rl.DarkGray)
+	rl.DrawText(fmt.Sprintf("Click Esquerdo - Remover | Click Direito - Colocar | R - Girar bloco (%s) | V - Alternar Câmera | T - Transparência do bloco | O - Configurações", player.PlaceRotation), 10, 35, 20, rl.Black)
+	rl.DrawText(fmt.Sprintf("F1 - Atlas Stats | F2 - Save Atlas | F3 - Visible Blocks | F4 - Salvar | F5/F6 - FOV (%.0f) | F7/F8 - Sensibilidade (%.4f) | F9 - AO (%v) | F10 - Greedy (%v) | F11 - Neblina (%v)",
+		player.Settings.FOV, player.Settings.MouseSensitivity, player.Settings.AmbientOcclusion, player.Settings.GreedyMeshing, player.Settings.Fog), 10, 60, 20, rl.DarkGray)
 
 	yOffset := int32(85)
 
@@ -125,12 +397,162 @@ func renderUI(player *game.Player, world *game.World) {
 	rl.DrawText(fmt.Sprintf("Chunk: (%d, %d, %d)", playerChunk.X, playerChunk.Y, playerChunk.Z), 10, yOffset, 20, rl.Black)
 	yOffset += 25
 
+	// Bioma da coluna do jogador
+	biome := world.GetBiome(int32(math.Floor(float64(player.Position.X))), int32(math.Floor(float64(player.Position.Z))))
+	rl.DrawText(fmt.Sprintf("Bioma: %s", biome), 10, yOffset, 20, rl.Black)
+	yOffset += 25
+
+	// Hora do ciclo dia/noite
+	clock := world.TimeOfDay
+	hours, minutes := int(clock.Hour), int((clock.Hour-float32(int(clock.Hour)))*60)
+	status := fmt.Sprintf("x%g", clock.Speed)
+	if clock.Frozen {
+		status = "parado"
+	}
+	rl.DrawText(fmt.Sprintf("Hora: %02d:%02d (%s) | N - Parar | -/= - Velocidade", hours, minutes, status), 10, yOffset, 20, rl.Black)
+	yOffset += 25
+
 	totalBlocks := world.GetTotalBlocks()
 	chunksLoaded := world.GetLoadedChunksCount()
-	rl.DrawText(fmt.Sprintf("Blocos: %d | Chunks: %d", totalBlocks, chunksLoaded), 10, yOffset, 20, rl.Black)
+	rl.DrawText(fmt.Sprintf("Blocos: %d | Chunks: %d | Blocos customizados: %d/%d",
+		totalBlocks, chunksLoaded, world.CustomBlocks.Count(), world.CustomBlocks.MaxBlocks), 10, yOffset, 20, rl.Black)
+	yOffset += 25
+
+	chunksDrawn, chunksCulled := world.GetCullingStats()
+	rl.DrawText(fmt.Sprintf("Chunks desenhados: %d | Fora da câmera: %d", chunksDrawn, chunksCulled), 10, yOffset, 20, rl.Black)
+	yOffset += 25
+
+	// Último bloco minerado recebido do nó
+	if blockViewer != nil && blockViewer.LastEvent != nil {
+		x, y, z := game.HashToWorldCoords(blockViewer.LastEvent.Hash)
+		rl.DrawText(fmt.Sprintf("Bloco #%d em (%d, %d, %d)", blockViewer.LastEvent.Height, x, y, z), 10, yOffset, 20, rl.DarkBrown)
+	}
+
+	// Modo observador: último bloco e validador que mais produziu, cada um com sua cor
+	if observer != nil && observer.LastEvent != nil {
+		last := observer.LastEvent
+		rl.DrawRectangle(10, yOffset, 20, 20, game.ValidatorColor(last.Validator))
+		rl.DrawText(fmt.Sprintf("Bloco #%d por %s", last.Height, game.ShortAddress(last.Validator)), 40, yOffset, 20, rl.DarkBrown)
+		yOffset += 25
+
+		heaviest, blocks := observer.HeaviestValidator()
+		rl.DrawRectangle(10, yOffset, 20, 20, game.ValidatorColor(heaviest))
+		rl.DrawText(fmt.Sprintf("Mais blocos: %s (%d de %d observados)", game.ShortAddress(heaviest), blocks, observer.Observed()), 40, yOffset, 20, rl.DarkBrown)
+	}
+	renderHotbar(world, hotbar)
+
 	rl.DrawText(fmt.Sprintf("FPS: %d", rl.GetFPS()), 10, game.ScreenHeight-30, 20, rl.Green)
 
 	// Crosshair
 	rl.DrawLine(game.ScreenWidth/2-10, game.ScreenHeight/2, game.ScreenWidth/2+10, game.ScreenHeight/2, rl.White)
 	rl.DrawLine(game.ScreenWidth/2, game.ScreenHeight/2-10, game.ScreenWidth/2, game.ScreenHeight/2+10, rl.White)
+
+	// Nome do bloco mirado logo abaixo do crosshair
+	if player.LookingAtBlock {
+		target := world.GetBlock(int32(player.TargetBlock.X), int32(player.TargetBlock.Y), int32(player.TargetBlock.Z))
+		if name := world.BlockName(target); name != "" {
+			width := rl.MeasureText(name, 20)
+			rl.DrawText(name, game.ScreenWidth/2-width/2, game.ScreenHeight/2+16, 20, rl.White)
+		}
+	}
+}
+
+// renderSettingsMenu desenha a tela de configurações, se aberta, com o item selecionado destacado
+func renderSettingsMenu(menu *game.SettingsMenu, settings game.Settings) {
+	if !menu.Open {
+		return
+	}
+
+	const width, height = 520, 360
+	x := int32(game.ScreenWidth-width) / 2
+	y := int32(game.ScreenHeight-height) / 2
+	rl.DrawRectangle(x, y, width, height, rl.Fade(rl.Black, 0.75))
+	rl.DrawText("Configurações", x+20, y+15, 24, rl.White)
+
+	for i, line := range menu.Lines(settings) {
+		color := rl.LightGray
+		if i == menu.Selected {
+			color = rl.Yellow
+			line = "> " + line
+		}
+		rl.DrawText(line, x+20, y+55+int32(i)*28, 20, color)
+	}
+
+	rl.DrawText("Setas: escolher/ajustar | O: fechar", x+20, y+height-30, 18, rl.Gray)
+}
+
+// renderCatalog desenha a grade do catálogo de blocos com as abas das categorias em cima; a
+// posição de cada bloco vem de BlockCatalog.Cell, então a grade acompanha Columns e Rows
+func renderCatalog(world *game.World, catalog *game.BlockCatalog) {
+	if !catalog.Open {
+		return
+	}
+
+	const slotSize, gap = 48, 6
+	width := int32(catalog.Columns*(slotSize+gap) + gap)
+	height := int32(catalog.Rows*(slotSize+gap)+gap) + 110
+	x := (game.ScreenWidth - width) / 2
+	y := (game.ScreenHeight - height) / 2
+
+	rl.DrawRectangle(x, y, width, height, rl.Fade(rl.Black, 0.8))
+	rl.DrawText("Blocos", x+gap, y+10, 24, rl.White)
+
+	// Abas
+	tabX := x + gap
+	for _, category := range game.CatalogCategories {
+		label := string(category)
+		switch category {
+		case game.CategoryAll:
+			label = "todos"
+		case game.CategoryCustom:
+			label = fmt.Sprintf("custom (%d/%d)", world.CustomBlocks.Count(), world.CustomBlocks.MaxBlocks)
+		}
+		color := rl.LightGray
+		if category == catalog.Category {
+			color = rl.Yellow
+		}
+		rl.DrawText(label, tabX, y+42, 18, color)
+		tabX += rl.MeasureText(label, 18) + 16
+	}
+
+	gridY := y + 70
+	start, end := catalog.VisibleRange()
+	for index := start; index < end; index++ {
+		column, row := catalog.Cell(index)
+		sx := x + gap + int32(column*(slotSize+gap))
+		sy := gridY + int32(row*(slotSize+gap))
+		rl.DrawRectangle(sx, sy, slotSize, slotSize, rl.Fade(rl.DarkGray, 0.6))
+		if index == catalog.Selected {
+			rl.DrawRectangleLines(sx-2, sy-2, slotSize+4, slotSize+4, rl.Yellow)
+		}
+		rl.DrawText(fmt.Sprintf("%d", catalog.Blocks[index]), sx+8, sy+14, 20, rl.White)
+	}
+
+	footer := "Tab: aba | Setas: escolher | Enter: colocar na hotbar | E: fechar"
+	if name := world.BlockName(catalog.SelectedBlock()); name != "" {
+		footer = fmt.Sprintf("%s (%d/%d) | %s", name, catalog.Selected+1, len(catalog.Blocks), footer)
+	}
+	rl.DrawText(footer, x+gap, y+height-30, 18, rl.Gray)
+}
+
+// renderHotbar desenha os slots da hotbar na base da tela, com o selecionado destacado
+func renderHotbar(world *game.World, hotbar *game.BlockHotbar) {
+	const slotSize, gap = 48, 6
+	x := int32(game.ScreenWidth-game.HotbarSize*(slotSize+gap)) / 2
+	y := int32(game.ScreenHeight - slotSize - 40)
+
+	for slot, blockType := range hotbar.Slots {
+		sx := x + int32(slot*(slotSize+gap))
+		rl.DrawRectangle(sx, y, slotSize, slotSize, rl.Fade(rl.Black, 0.4))
+		if slot == hotbar.Selected {
+			rl.DrawRectangleLines(sx-2, y-2, slotSize+4, slotSize+4, rl.Yellow)
+		}
+		rl.DrawText(fmt.Sprintf("%d", slot+1), sx+4, y+4, 10, rl.White)
+		rl.DrawText(fmt.Sprintf("%d", blockType), sx+14, y+20, 20, rl.White)
+	}
+
+	// Nome do bloco selecionado
+	if name := world.BlockName(hotbar.SelectedBlock()); name != "" {
+		rl.DrawText(name, x, y-24, 20, rl.White)
+	}
 }
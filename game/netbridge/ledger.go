@@ -0,0 +1,132 @@
+package netbridge
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Tx transação de um bloco (só os campos usados pela ponte)
+type Tx struct {
+	ID   string `json:"id"`
+	From string `json:"from"`
+	Data string `json:"data"`
+}
+
+// Block bloco da chain com as transações, na ordem em que foram incluídas
+type Block struct {
+	Height       uint64 `json:"height"`
+	Hash         string `json:"hash"`
+	Transactions []Tx   `json:"transactions"`
+}
+
+// Ledger acesso da ponte a um nó: publicar uma transação com dados e ler os blocos minerados
+type Ledger interface {
+	// Submit publica uma transação com o campo data informado e retorna o ID dela
+	Submit(data string) (string, error)
+	// BlocksFrom retorna os blocos a partir da altura informada, em ordem crescente (pode
+	// retornar só os primeiros; a ponte pede o restante na próxima consulta)
+	BlocksFrom(height uint64) ([]Block, error)
+}
+
+// NodeLedger Ledger de um nó da blockchain via API HTTP (/api/transaction/send e /api/blocks).
+// As edições saem da carteira do nó como transferências de Amount para WorldAddress, já que a
+// chain não aceita transações sem valor nem para o próprio remetente.
+type NodeLedger struct {
+	BaseURL      string
+	Username     string
+	Password     string
+	WorldAddress string // Destino das transações de edição (combinado entre os jogadores)
+	Amount       uint64
+	Fee          uint64
+
+	client *http.Client
+}
+
+// NewNodeLedger cria o Ledger para o nó em baseURL (ex: http://localhost:8080)
+func NewNodeLedger(baseURL, username, password, worldAddress string) *NodeLedger {
+	return &NodeLedger{
+		BaseURL:      strings.TrimRight(baseURL, "/"),
+		Username:     username,
+		Password:     password,
+		WorldAddress: worldAddress,
+		Amount:       1,
+		client:       &http.Client{Timeout: 2 * time.Second},
+	}
+}
+
+// Submit cria a transação de edição no nó
+func (l *NodeLedger) Submit(data string) (string, error) {
+	body, err := json.Marshal(map[string]interface{}{
+		"to":     l.WorldAddress,
+		"amount": l.Amount,
+		"fee":    l.Fee,
+		"data":   data,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	var created struct {
+		TxID string `json:"tx_id"`
+	}
+	if err := l.do(http.MethodPost, "/api/transaction/send", bytes.NewReader(body), &created); err != nil {
+		return "", fmt.Errorf("failed to submit edit: %w", err)
+	}
+	return created.TxID, nil
+}
+
+// BlocksFrom busca em /api/blocks os blocos a partir da altura informada
+func (l *NodeLedger) BlocksFrom(height uint64) ([]Block, error) {
+	var blocks struct {
+		Blocks []Block `json:"blocks"`
+	}
+	if err := l.do(http.MethodGet, fmt.Sprintf("/api/blocks?from=%d", height), nil, &blocks); err != nil {
+		return nil, fmt.Errorf("failed to fetch blocks from %d: %w", height, err)
+	}
+	return blocks.Blocks, nil
+}
+
+// do chama a API do nó e decodifica a resposta JSON em out
+func (l *NodeLedger) do(method, path string, body *bytes.Reader, out interface{}) error {
+	var req *http.Request
+	var err error
+	if body != nil {
+		req, err = http.NewRequest(method, l.BaseURL+path, body)
+	} else {
+		req, err = http.NewRequest(method, l.BaseURL+path, nil)
+	}
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if l.Username != "" {
+		req.SetBasicAuth(l.Username, l.Password)
+	}
+
+	resp, err := l.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var apiErr struct {
+			Error string `json:"error"`
+		}
+		if json.NewDecoder(resp.Body).Decode(&apiErr) == nil && apiErr.Error != "" {
+			return fmt.Errorf("status %d: %s", resp.StatusCode, apiErr.Error)
+		}
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("invalid response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,260 @@
+// Package netbridge compartilha o mundo voxel entre jogadores através de um nó da blockchain:
+// cada edição local vira uma transação (chunk, posição e bloco no campo data) e as edições
+// encontradas nos blocos minerados são aplicadas ao World, na ordem da chain (a última vence).
+package netbridge
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"krakovia/game"
+)
+
+// EditPrefix prefixo do campo data das transações de edição do mundo
+const EditPrefix = "voxel:"
+
+// DefaultPollInterval intervalo padrão entre sincronizações com o nó
+const DefaultPollInterval = time.Second
+
+// Edit edição de um bloco do mundo
+type Edit struct {
+	X, Y, Z  int32
+	Block    game.BlockType
+	Rotation game.BlockRotation
+}
+
+// Encode codifica a edição para o campo data:
+// "voxel:<chunk x>,<chunk y>,<chunk z>:<local x>,<local y>,<local z>:<bloco>:<rotação>"
+func (e Edit) Encode() string {
+	chunk := game.GetChunkCoord(e.X, e.Y, e.Z)
+	return fmt.Sprintf("%s%d,%d,%d:%d,%d,%d:%d:%d", EditPrefix,
+		chunk.X, chunk.Y, chunk.Z,
+		e.X-chunk.X*game.ChunkSize, e.Y-chunk.Y*game.ChunkHeight, e.Z-chunk.Z*game.ChunkSize,
+		e.Block, e.Rotation)
+}
+
+// DecodeEdit lê uma edição do campo data de uma transação (ok = false se não é uma edição)
+func DecodeEdit(data string) (Edit, bool) {
+	rest, found := strings.CutPrefix(data, EditPrefix)
+	if !found {
+		return Edit{}, false
+	}
+
+	parts := strings.Split(rest, ":")
+	if len(parts) != 4 {
+		return Edit{}, false
+	}
+	chunk, ok := parseTriple(parts[0])
+	if !ok {
+		return Edit{}, false
+	}
+	local, ok := parseTriple(parts[1])
+	if !ok || local[0] < 0 || local[0] >= game.ChunkSize || local[1] < 0 || local[1] >= game.ChunkHeight ||
+		local[2] < 0 || local[2] >= game.ChunkSize {
+		return Edit{}, false
+	}
+	block, err := strconv.ParseUint(parts[2], 10, 8)
+	if err != nil {
+		return Edit{}, false
+	}
+	rotation, err := strconv.ParseUint(parts[3], 10, 8)
+	if err != nil || rotation > uint64(game.Rotation270) {
+		return Edit{}, false
+	}
+
+	return Edit{
+		X:        chunk[0]*game.ChunkSize + local[0],
+		Y:        chunk[1]*game.ChunkHeight + local[1],
+		Z:        chunk[2]*game.ChunkSize + local[2],
+		Block:    game.BlockType(block),
+		Rotation: game.BlockRotation(rotation),
+	}, true
+}
+
+// parseTriple lê "a,b,c"
+func parseTriple(s string) ([3]int32, bool) {
+	var out [3]int32
+	fields := strings.Split(s, ",")
+	if len(fields) != 3 {
+		return out, false
+	}
+	for i, field := range fields {
+		v, err := strconv.ParseInt(field, 10, 32)
+		if err != nil {
+			return out, false
+		}
+		out[i] = int32(v)
+	}
+	return out, true
+}
+
+// blockPos posição de um bloco no mundo
+type blockPos struct {
+	X, Y, Z int32
+}
+
+// localEdit edição local ainda não vista em um bloco minerado
+type localEdit struct {
+	edit Edit
+	txID string // vazio até o nó aceitar a transação
+}
+
+// Bridge liga um World a um Ledger. Edições feitas com World.SetBlock são publicadas por Sync;
+// os blocos lidos por Sync são aplicados ao mundo por Update, que deve rodar na thread do jogo.
+//
+// Merge last-write-wins: as edições valem na ordem da chain (altura do bloco e posição da
+// transação nele). Enquanto uma edição local não é minerada, edições remotas na mesma posição
+// são ignoradas, porque a local entra na chain depois delas.
+type Bridge struct {
+	World        *game.World
+	Ledger       Ledger
+	PollInterval time.Duration
+
+	mu         sync.Mutex
+	outbox     []*localEdit
+	pending    map[blockPos]*localEdit
+	received   []Block
+	nextHeight uint64
+
+	stopChan chan struct{}
+	wg       sync.WaitGroup
+}
+
+// New cria a ponte e passa a capturar as edições do mundo (World.OnBlockEdit). A chain é lida
+// desde o gênesis, para que quem entra depois receba todas as edições.
+func New(world *game.World, ledger Ledger) *Bridge {
+	b := &Bridge{
+		World:        world,
+		Ledger:       ledger,
+		PollInterval: DefaultPollInterval,
+		pending:      make(map[blockPos]*localEdit),
+		stopChan:     make(chan struct{}),
+	}
+	world.OnBlockEdit = b.onBlockEdit
+	return b
+}
+
+// onBlockEdit enfileira uma edição local para publicação
+func (b *Bridge) onBlockEdit(x, y, z int32, block game.BlockType, rotation game.BlockRotation) {
+	local := &localEdit{edit: Edit{X: x, Y: y, Z: z, Block: block, Rotation: rotation}}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.outbox = append(b.outbox, local)
+	b.pending[blockPos{x, y, z}] = local
+}
+
+// Sync publica as edições locais pendentes e busca os blocos novos do nó (sem tocar no mundo)
+func (b *Bridge) Sync() error {
+	b.mu.Lock()
+	outbox := b.outbox
+	b.outbox = nil
+	b.mu.Unlock()
+
+	for i, local := range outbox {
+		txID, err := b.Ledger.Submit(local.edit.Encode())
+		if err != nil {
+			// Tenta de novo na próxima sincronização
+			b.mu.Lock()
+			b.outbox = append(append([]*localEdit{}, outbox[i:]...), b.outbox...)
+			b.mu.Unlock()
+			return err
+		}
+		b.mu.Lock()
+		local.txID = txID
+		b.mu.Unlock()
+	}
+
+	b.mu.Lock()
+	from := b.nextHeight
+	b.mu.Unlock()
+
+	blocks, err := b.Ledger.BlocksFrom(from)
+	if err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, block := range blocks {
+		if block.Height < b.nextHeight {
+			continue
+		}
+		b.received = append(b.received, block)
+		b.nextHeight = block.Height + 1
+	}
+	return nil
+}
+
+// Update aplica ao mundo as edições dos blocos recebidos, na ordem da chain.
+// Seguro com ponte nil (jogo sem nó conectado).
+func (b *Bridge) Update() {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, block := range b.received {
+		for _, tx := range block.Transactions {
+			edit, ok := DecodeEdit(tx.Data)
+			if !ok {
+				continue
+			}
+
+			pos := blockPos{edit.X, edit.Y, edit.Z}
+			if local, waiting := b.pending[pos]; waiting {
+				// A edição local vence as anteriores a ela; quando ela mesma é minerada, a
+				// posição volta a aceitar edições remotas
+				if local.txID != "" && local.txID == tx.ID {
+					delete(b.pending, pos)
+				}
+				continue
+			}
+
+			// Direto no ChunkManager: edições remotas não são publicadas de novo
+			b.World.ChunkManager.SetBlockWithRotation(edit.X, edit.Y, edit.Z, edit.Block, edit.Rotation)
+		}
+	}
+	b.received = nil
+}
+
+// Start sincroniza com o nó em background a cada PollInterval
+func (b *Bridge) Start() {
+	b.wg.Add(1)
+	go b.syncLoop()
+}
+
+// Stop encerra a sincronização em background. Seguro com ponte nil.
+func (b *Bridge) Stop() {
+	if b == nil {
+		return
+	}
+	close(b.stopChan)
+	b.wg.Wait()
+}
+
+// syncLoop sincroniza até Stop ser chamado
+func (b *Bridge) syncLoop() {
+	defer b.wg.Done()
+
+	ticker := time.NewTicker(b.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := b.Sync(); err != nil {
+			// Nó indisponível não deve derrubar o jogo
+			fmt.Printf("Net bridge: %v\n", err)
+		}
+
+		select {
+		case <-b.stopChan:
+			return
+		case <-ticker.C:
+		}
+	}
+}
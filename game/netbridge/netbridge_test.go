@@ -0,0 +1,235 @@
+package netbridge
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"krakovia/game"
+)
+
+// memoryNetwork chain em memória compartilhada pelos nós do teste (gossip instantâneo)
+type memoryNetwork struct {
+	mu      sync.Mutex
+	chain   []Block
+	mempool []Tx
+	nextID  int
+}
+
+// mine inclui as transações do mempool em um bloco novo, na ordem de chegada
+func (n *memoryNetwork) mine() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.chain = append(n.chain, Block{
+		Height:       uint64(len(n.chain)),
+		Hash:         fmt.Sprintf("block-%d", len(n.chain)),
+		Transactions: n.mempool,
+	})
+	n.mempool = nil
+}
+
+// memoryNode nó em memória: Ledger que publica na rede com a própria carteira
+type memoryNode struct {
+	network *memoryNetwork
+	address string
+}
+
+func (m *memoryNode) Submit(data string) (string, error) {
+	m.network.mu.Lock()
+	defer m.network.mu.Unlock()
+	m.network.nextID++
+	tx := Tx{ID: fmt.Sprintf("tx-%d", m.network.nextID), From: m.address, Data: data}
+	m.network.mempool = append(m.network.mempool, tx)
+	return tx.ID, nil
+}
+
+func (m *memoryNode) BlocksFrom(height uint64) ([]Block, error) {
+	m.network.mu.Lock()
+	defer m.network.mu.Unlock()
+	if height >= uint64(len(m.network.chain)) {
+		return nil, nil
+	}
+	return append([]Block(nil), m.network.chain[height:]...), nil
+}
+
+// Helper: dois jogadores, cada um com seu mundo e sua ponte para um nó da mesma rede
+func newTestPlayers(t *testing.T) (*memoryNetwork, [2]*Bridge) {
+	t.Helper()
+	game.DisableGPUUploadForTesting = true
+
+	network := &memoryNetwork{}
+	network.mine() // gênesis
+	var bridges [2]*Bridge
+	for i := range bridges {
+		bridges[i] = New(game.NewWorld(), &memoryNode{network: network, address: fmt.Sprintf("player-%d", i)})
+	}
+	return network, bridges
+}
+
+// Helper: publica as edições pendentes, minera e entrega os blocos aos dois jogadores
+func syncAll(t *testing.T, network *memoryNetwork, bridges [2]*Bridge) {
+	t.Helper()
+	for _, b := range bridges {
+		if err := b.Sync(); err != nil {
+			t.Fatalf("Sync failed: %v", err)
+		}
+	}
+	network.mine()
+	for _, b := range bridges {
+		if err := b.Sync(); err != nil {
+			t.Fatalf("Sync failed: %v", err)
+		}
+		b.Update()
+	}
+}
+
+func TestBridgesConvergeOnPlacedBlock(t *testing.T) {
+	network, bridges := newTestPlayers(t)
+	alice, bob := bridges[0].World, bridges[1].World
+
+	alice.SetBlockWithRotation(-5, 40, 37, game.BlockWood, game.Rotation90)
+	if bob.GetBlock(-5, 40, 37) == game.BlockWood {
+		t.Fatal("Edit should not reach the other world before being mined")
+	}
+
+	syncAll(t, network, bridges)
+
+	for i, world := range []*game.World{alice, bob} {
+		if world.GetBlock(-5, 40, 37) != game.BlockWood || world.GetBlockRotation(-5, 40, 37) != game.Rotation90 {
+			t.Errorf("World %d: expected wood at 90°, got %d at %s", i, world.GetBlock(-5, 40, 37), world.GetBlockRotation(-5, 40, 37))
+		}
+	}
+
+	// Quebrar o bloco no outro mundo também converge
+	bob.SetBlock(-5, 40, 37, game.BlockAir)
+	syncAll(t, network, bridges)
+
+	for i, world := range []*game.World{alice, bob} {
+		if world.GetBlock(-5, 40, 37) != game.BlockAir {
+			t.Errorf("World %d: expected the block to be removed, got %d", i, world.GetBlock(-5, 40, 37))
+		}
+	}
+
+	// Edições remotas não são publicadas de novo
+	if txs := len(network.chain[len(network.chain)-1].Transactions); txs != 1 {
+		t.Errorf("Expected only the local edit in the last block, got %d transactions", txs)
+	}
+}
+
+func TestBridgeLastWriteWins(t *testing.T) {
+	network, bridges := newTestPlayers(t)
+	alice, bob := bridges[0].World, bridges[1].World
+
+	// Os dois editam a mesma posição no mesmo bloco: vale a transação que entrou por último
+	alice.SetBlock(3, 40, 3, game.BlockStone)
+	bob.SetBlock(3, 40, 3, game.BlockGlass)
+	syncAll(t, network, bridges)
+
+	for i, world := range []*game.World{alice, bob} {
+		if world.GetBlock(3, 40, 3) != game.BlockGlass {
+			t.Errorf("World %d: expected the last write (glass), got %d", i, world.GetBlock(3, 40, 3))
+		}
+	}
+
+	// Uma edição local ainda não minerada não é desfeita por blocos com edições mais antigas
+	bob.SetBlock(3, 40, 3, game.BlockBricks)
+	if err := bridges[1].Sync(); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	alice.SetBlock(3, 40, 3, game.BlockSand)
+	network.mine() // só a edição do Bob
+	for _, b := range bridges {
+		if err := b.Sync(); err != nil {
+			t.Fatalf("Sync failed: %v", err)
+		}
+		b.Update()
+	}
+	if alice.GetBlock(3, 40, 3) != game.BlockSand {
+		t.Errorf("Pending local edit should win over older remote edits, got %d", alice.GetBlock(3, 40, 3))
+	}
+
+	syncAll(t, network, bridges)
+	for i, world := range []*game.World{alice, bob} {
+		if world.GetBlock(3, 40, 3) != game.BlockSand {
+			t.Errorf("World %d: expected sand after the last edit is mined, got %d", i, world.GetBlock(3, 40, 3))
+		}
+	}
+}
+
+func TestEditEncoding(t *testing.T) {
+	edits := []Edit{
+		{X: 0, Y: 0, Z: 0, Block: game.BlockStone},
+		{X: -1, Y: 70, Z: -33, Block: game.BlockWood, Rotation: game.Rotation270},
+		{X: 100, Y: -5, Z: 31, Block: game.BlockType(200)},
+	}
+	for _, edit := range edits {
+		data := edit.Encode()
+		decoded, ok := DecodeEdit(data)
+		if !ok || decoded != edit {
+			t.Errorf("Edit %+v encoded as %q decoded to %+v (ok %v)", edit, data, decoded, ok)
+		}
+	}
+
+	for _, data := range []string{
+		"",
+		"Coinbase reward for block 3",
+		`{"type":"stake"}`,
+		"voxel:0,0,0:32,0,0:1:0", // posição local fora do chunk
+		"voxel:0,0,0:1,1,1:1:4",  // rotação inválida
+		"voxel:0,0,0:1,1,1:256:0",
+		"voxel:0,0:1,1,1:1:0",
+	} {
+		if _, ok := DecodeEdit(data); ok {
+			t.Errorf("Data %q should not decode as an edit", data)
+		}
+	}
+}
+
+func TestNodeLedgerUsesNodeAPI(t *testing.T) {
+	var sent map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/api/transaction/send" && r.Method == http.MethodPost:
+			json.NewDecoder(r.Body).Decode(&sent)
+			json.NewEncoder(w).Encode(map[string]string{"status": "transaction created", "tx_id": "abc"})
+		case r.URL.Path == "/api/blocks" && r.URL.Query().Get("from") == "7":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"blocks": []map[string]interface{}{{
+					"height": 7,
+					"hash":   "h7",
+					"transactions": []map[string]interface{}{
+						{"id": "abc", "from": "me", "to": "world", "amount": 1, "data": "voxel:0,0,0:1,2,3:1:0"},
+					},
+				}},
+			})
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "insufficient balance"})
+		}
+	}))
+	defer server.Close()
+
+	ledger := NewNodeLedger(server.URL+"/", "", "", "world")
+	txID, err := ledger.Submit("voxel:0,0,0:1,2,3:1:0")
+	if err != nil || txID != "abc" {
+		t.Fatalf("Expected tx abc, got %q (err %v)", txID, err)
+	}
+	if sent["to"] != "world" || sent["amount"] != float64(1) || sent["data"] != "voxel:0,0,0:1,2,3:1:0" {
+		t.Errorf("Unexpected transaction request: %v", sent)
+	}
+
+	blocks, err := ledger.BlocksFrom(7)
+	if err != nil {
+		t.Fatalf("BlocksFrom failed: %v", err)
+	}
+	if len(blocks) != 1 || blocks[0].Height != 7 || len(blocks[0].Transactions) != 1 || blocks[0].Transactions[0].ID != "abc" {
+		t.Fatalf("Unexpected blocks: %+v", blocks)
+	}
+
+	if _, err := ledger.BlocksFrom(8); err == nil {
+		t.Error("API errors should be reported")
+	}
+}
@@ -4,36 +4,123 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
+
+	"github.com/krakovia/blockchain/pkg/blockchain"
 )
 
 // GenesisBlock representa a configuração do bloco gênesis
 type GenesisBlock struct {
-	Timestamp         int64  `json:"timestamp"`           // Timestamp do bloco gênesis
-	RecipientAddr     string `json:"recipient_addr"`      // Endereço que receberá a recompensa inicial
-	Amount            uint64 `json:"amount"`              // Quantidade de tokens iniciais
-	InitialStake      uint64 `json:"initial_stake"`       // Stake inicial do recipient (0 = sem stake inicial)
-	Hash              string `json:"hash"`                // Hash esperado do bloco gênesis
-	BlockTime         int64  `json:"block_time"`          // Tempo entre blocos em milissegundos
-	MaxBlockSize      int    `json:"max_block_size"`      // Máximo de transações por bloco
-	BlockReward       uint64 `json:"block_reward"`        // Recompensa por bloco minerado
-	MinValidatorStake uint64 `json:"min_validator_stake"` // Stake mínimo para ser validador
+	Timestamp         int64   `json:"timestamp"`                    // Timestamp do bloco gênesis
+	RecipientAddr     string  `json:"recipient_addr"`               // Endereço que receberá a recompensa inicial
+	Amount            uint64  `json:"amount"`                       // Quantidade de tokens iniciais
+	InitialStake      uint64  `json:"initial_stake"`                // Stake inicial do recipient ou da primeira alocação (0 = sem stake inicial)
+	Hash              string  `json:"hash"`                         // Hash esperado do bloco gênesis
+	BlockTime         int64   `json:"block_time"`                   // Tempo entre blocos em milissegundos
+	MaxBlockSize      int     `json:"max_block_size"`               // Máximo de transações por bloco, sem contar a coinbase
+	BlockReward       uint64  `json:"block_reward"`                 // Recompensa por bloco minerado
+	HalvingInterval   uint64  `json:"halving_interval"`             // Blocos entre cada halving da recompensa (0 = sem halving)
+	MaxSupply         uint64  `json:"max_supply"`                   // Oferta máxima de tokens, incluindo o gênesis (0 = ilimitada)
+	MinValidatorStake uint64  `json:"min_validator_stake"`          // Stake mínimo para ser validador
+	UnbondingPeriod   *uint64 `json:"unbonding_period,omitempty"`   // Blocos até o valor de um unstake virar saldo (ausente = padrão, 0 = imediato)
+	CoinbaseMaturity  uint64  `json:"coinbase_maturity"`            // Blocos até a recompensa de um bloco poder ser gasta (0 = imediato)
+	SlashBasisPoints  *uint64 `json:"slash_basis_points,omitempty"` // Parte do stake removida por assinatura dupla, em pontos base de 1/10000 (ausente = padrão, 0 = sem punição)
+	MaxReorgDepth     uint64  `json:"max_reorg_depth"`              // Blocos abaixo da ponta que um fork pode substituir (0 = padrão)
+	MaxClockDrift     int64   `json:"max_clock_drift"`              // Tolerância em segundos para timestamps no futuro e limite do ajuste do relógio pelos peers (0 = padrão)
+	MinFee            uint64  `json:"min_fee"`                      // Fee mínima para uma transação entrar no mempool (0 = padrão)
+
+	// Limites de consenso além de max_block_size (0 = padrão; ver blockchain.ConsensusParams)
+	MaxBlockBytes int `json:"max_block_bytes,omitempty"` // Tamanho máximo do bloco em bytes
+	MaxTxBytes    int `json:"max_tx_bytes,omitempty"`    // Tamanho máximo de uma transação em bytes
+	MaxMemoBytes  int `json:"max_memo_bytes,omitempty"`  // Tamanho máximo do campo data de uma transação
+
+	// Saldos iniciais de vários endereços (substitui recipient_addr/amount quando presente)
+	Allocations []GenesisAllocation `json:"allocations,omitempty"`
+
+	// Bloqueios de saldo com cliff e liberação linear (premine lock)
+	Vesting []GenesisVesting `json:"vesting,omitempty"`
+}
+
+// GenesisAllocation representa o saldo inicial de um endereço no gênesis
+type GenesisAllocation struct {
+	Address string `json:"address"`
+	Amount  uint64 `json:"amount"`
+}
+
+// GenesisVesting bloqueia parte da alocação de um endereço até cliff_height e a libera
+// linearmente ao longo de vesting_blocks blocos
+type GenesisVesting struct {
+	Address       string `json:"address"`        // Endereço alocado (vazio = recipient_addr ou primeira alocação)
+	Amount        uint64 `json:"amount"`         // Valor bloqueado (0 = toda a alocação do endereço)
+	CliffHeight   uint64 `json:"cliff_height"`   // Altura até a qual nada é liberado
+	VestingBlocks uint64 `json:"vesting_blocks"` // Blocos de liberação linear após o cliff (0 = tudo no cliff)
+}
+
+// ConsensusParams retorna os limites de consenso do gênesis
+func (g *GenesisBlock) ConsensusParams() blockchain.ConsensusParams {
+	return blockchain.ConsensusParams{
+		MaxBlockBytes: g.MaxBlockBytes,
+		MaxBlockTxs:   g.MaxBlockSize,
+		MaxTxBytes:    g.MaxTxBytes,
+		MaxMemoBytes:  g.MaxMemoBytes,
+	}
+}
+
+// GetVesting retorna os bloqueios do gênesis com endereço e valor padrão preenchidos
+func (g *GenesisBlock) GetVesting() []GenesisVesting {
+	allocations := g.GetAllocations()
+	vesting := make([]GenesisVesting, 0, len(g.Vesting))
+	for _, v := range g.Vesting {
+		if v.Address == "" {
+			v.Address = allocations[0].Address
+		}
+		if v.Amount == 0 {
+			for _, alloc := range allocations {
+				if alloc.Address == v.Address {
+					v.Amount = alloc.Amount
+				}
+			}
+		}
+		vesting = append(vesting, v)
+	}
+	return vesting
+}
+
+// GetAllocations retorna as alocações do gênesis; sem a lista, usa recipient_addr/amount
+func (g *GenesisBlock) GetAllocations() []GenesisAllocation {
+	if len(g.Allocations) > 0 {
+		return g.Allocations
+	}
+	return []GenesisAllocation{{Address: g.RecipientAddr, Amount: g.Amount}}
 }
 
 // WalletConfig representa as chaves da carteira do nó
+// (chave privada em claro ou caminho para um keystore criptografado)
 type WalletConfig struct {
-	PrivateKey string `json:"private_key"` // Chave privada ECDSA em formato hexadecimal
-	PublicKey  string `json:"public_key"`  // Chave pública ECDSA em formato hexadecimal
-	Address    string `json:"address"`     // Endereço derivado da chave pública
+	PrivateKey string `json:"private_key"`        // Chave privada ECDSA em formato hexadecimal
+	PublicKey  string `json:"public_key"`         // Chave pública ECDSA em formato hexadecimal
+	Address    string `json:"address"`            // Endereço derivado da chave pública
+	Keystore   string `json:"keystore,omitempty"` // Arquivo keystore criptografado (alternativa a private_key)
 }
 
 // CheckpointConfig representa a configuração do sistema de checkpoints
 type CheckpointConfig struct {
-	Enabled       bool `json:"enabled"`          // Habilita o sistema de checkpoints
-	Interval      int  `json:"interval"`         // Checkpoint a cada X blocos
-	KeepInMemory  int  `json:"keep_in_memory"`   // Manter últimos X blocos em memória
-	KeepOnDisk    int  `json:"keep_on_disk"`     // Manter últimos X checkpoints no disco
-	CSVDelimiter  string `json:"csv_delimiter"`  // Delimitador do CSV (padrão: ",")
-	Compression   bool `json:"compression"`      // Comprimir CSV no LevelDB
+	Enabled      bool   `json:"enabled"`        // Habilita o sistema de checkpoints
+	Interval     int    `json:"interval"`       // Checkpoint a cada X blocos
+	KeepInMemory int    `json:"keep_in_memory"` // Manter últimos X blocos em memória
+	KeepOnDisk   int    `json:"keep_on_disk"`   // Manter últimos X checkpoints no disco
+	CSVDelimiter string `json:"csv_delimiter"`  // Delimitador do CSV (padrão: ",")
+	Compression  bool   `json:"compression"`    // Comprimir CSV no LevelDB
+
+	AllowUnsigned   bool `json:"allow_unsigned"`   // Confiar em checkpoints de peers sem o endosso do quorum do stake (inseguro)
+	SignatureQuorum int  `json:"signature_quorum"` // Porcentagem do stake que precisa assinar (0 = mais de 2/3)
+}
+
+// PruneConfig representa a profundidade de pruning dos blocos, independente dos checkpoints.
+// Sem ela, os blocos só saem da memória nos checkpoints (checkpoint.keep_in_memory).
+type PruneConfig struct {
+	KeepInMemory int `json:"keep_in_memory"` // Manter os últimos X blocos em memória (0 = todos); os demais são lidos do LevelDB
+	KeepOnDisk   int `json:"keep_on_disk"`   // Manter os últimos X blocos no LevelDB (0 = todos, nó de arquivo)
 }
 
 // APIConfig representa a configuração do servidor HTTP da API
@@ -42,21 +129,108 @@ type APIConfig struct {
 	Address  string `json:"address"`  // Endereço do servidor (ex: :8080)
 	Username string `json:"username"` // Usuário para autenticação
 	Password string `json:"password"` // Senha para autenticação
+
+	MetricsEnabled bool `json:"metrics_enabled"` // Expõe GET /metrics no formato do Prometheus
+
+	BasicAuth   bool   `json:"basic_auth"`    // Aceita HTTP Basic além dos tokens de /api/login (compatibilidade)
+	TokenSecret string `json:"token_secret"`  // Chave HMAC dos tokens (vazia = aleatória a cada início)
+	TokenTTL    int    `json:"token_ttl"`     // Validade dos tokens em segundos (0 = 1 hora)
+	TLSCertFile string `json:"tls_cert_file"` // Certificado TLS em PEM (com tls_key_file, serve HTTPS)
+	TLSKeyFile  string `json:"tls_key_file"`  // Chave privada do certificado TLS em PEM
+}
+
+// StorageConfig representa a configuração de persistência em disco
+type StorageConfig struct {
+	SaveRetries          int  `json:"save_retries"`           // Tentativas extras ao falhar ao salvar um bloco (0 = 3, <0 = nenhuma)
+	SaveBackoffMs        int  `json:"save_backoff_ms"`        // Espera inicial entre tentativas (dobra a cada falha)
+	CompactOnStartup     bool `json:"compact_on_startup"`     // Compactar o LevelDB ao iniciar o nó
+	CompactIntervalHours int  `json:"compact_interval_hours"` // Intervalo mínimo entre compactações (0 = 24h)
+}
+
+// TxFilterConfig representa o filtro de remetentes para deployments permissionados
+type TxFilterConfig struct {
+	Allowlist     []string `json:"allowlist"`      // Remetentes permitidos nos blocos (vazio = todos)
+	Denylist      []string `json:"denylist"`       // Remetentes nunca incluídos nos blocos
+	FilterMempool bool     `json:"filter_mempool"` // Rejeitar também na admissão ao mempool
+}
+
+// RateLimitConfig representa o rate limit de mensagens recebidas de cada peer
+type RateLimitConfig struct {
+	Disabled bool                        `json:"disabled"`  // Não limitar mensagens recebidas
+	Limits   map[string]MessageRateLimit `json:"limits"`    // Limites por tipo de mensagem (sobrescrevem os padrões)
+	MaxDrops int                         `json:"max_drops"` // Desconectar o peer após N mensagens descartadas (0 = apenas descartar)
+}
+
+// MessageRateLimit representa o limite de um tipo de mensagem (token bucket)
+type MessageRateLimit struct {
+	Rate  float64 `json:"rate"`  // Mensagens por segundo (0 = sem limite)
+	Burst int     `json:"burst"` // Rajada máxima
+}
+
+// RejectLogConfig representa o log de blocos e transações rejeitados (análise forense)
+type RejectLogConfig struct {
+	Path      string `json:"path"`        // Arquivo JSONL (vazio = desativado)
+	MaxSizeMB int    `json:"max_size_mb"` // Tamanho máximo antes de rotacionar (0 = 10 MB)
+	MaxFiles  int    `json:"max_files"`   // Arquivos rotacionados mantidos (0 = 3)
+}
+
+// BandwidthConfig representa os limites de banda das mensagens em massa (respostas de sync,
+// headers e checkpoints), em bytes por segundo (0 = sem limite)
+type BandwidthConfig struct {
+	PeerUploadBytesPerSec    int64 `json:"peer_upload_bytes_per_sec"`    // Envio para cada peer
+	PeerDownloadBytesPerSec  int64 `json:"peer_download_bytes_per_sec"`  // Recebimento de cada peer
+	TotalUploadBytesPerSec   int64 `json:"total_upload_bytes_per_sec"`   // Envio somado de todos os peers
+	TotalDownloadBytesPerSec int64 `json:"total_download_bytes_per_sec"` // Recebimento somado de todos os peers
+}
+
+// ICEServerConfig representa um servidor STUN ou TURN usado nas conexões WebRTC
+type ICEServerConfig struct {
+	URLs       []string `json:"urls"`       // Ex: "stun:stun.example.com:3478", "turn:turn.example.com:3478"
+	Username   string   `json:"username"`   // Usuário do TURN (opcional)
+	Credential string   `json:"credential"` // Senha do TURN (opcional)
 }
 
 // NodeConfig representa a configuração de um nó
 type NodeConfig struct {
-	ID                string            `json:"id"`
+	ID                string            `json:"id"` // Opcional: derivado da carteira; se informado deve ser o endereço dela
 	Address           string            `json:"address"`
 	DBPath            string            `json:"db_path"`
 	SignalingServer   string            `json:"signaling_server"`
-	MaxPeers          int               `json:"max_peers"`          // Máximo de peers conectados (0 = ilimitado)
-	MinPeers          int               `json:"min_peers"`          // Mínimo de peers desejado
-	DiscoveryInterval int               `json:"discovery_interval"` // Intervalo de descoberta em segundos
-	Wallet            WalletConfig      `json:"wallet"`             // Configuração da carteira
-	Genesis           *GenesisBlock     `json:"genesis,omitempty"`  // Configuração do bloco gênesis (opcional)
+	MaxPeers          int               `json:"max_peers"`            // Máximo de peers conectados (0 = ilimitado)
+	MinPeers          int               `json:"min_peers"`            // Mínimo de peers desejado
+	DiscoveryInterval int               `json:"discovery_interval"`   // Intervalo de descoberta em segundos
+	MaxParallelDials  int               `json:"max_parallel_dials"`   // Conexões de saída estabelecidas em paralelo (0 = padrão)
+	SyncTimeoutMs     int               `json:"sync_timeout_ms"`      // Tempo máximo para montar uma resposta de sync (0 = padrão)
+	SyncBatchSize     int               `json:"sync_batch_size"`      // Blocos aplicados e gravados por lote na sincronização (0 = padrão, 1 = um a um)
+	MaxFutureBlocks   uint64            `json:"max_future_blocks"`    // Alturas à frente da ponta que um bloco de gossip pode estar para ser guardado como órfão (0 = padrão)
+	HeadersFirstSync  bool              `json:"headers_first_sync"`   // Valida os headers antes de baixar os corpos dos blocos na sincronização
+	ParallelSyncPeers int               `json:"parallel_sync_peers"`  // Peers dos quais blocos são baixados ao mesmo tempo na sincronização (0 = padrão, 1 = um por vez)
+	DownloadTimeoutMs int               `json:"download_timeout_ms"`  // Prazo para um peer entregar os blocos pedidos antes de pedi-los a outro (0 = padrão)
+	CompressMessages  bool              `json:"compress_messages"`    // Comprime blocos, transações e respostas de sync para peers que também comprimem
+	Wallet            WalletConfig      `json:"wallet"`               // Configuração da carteira
+	Genesis           *GenesisBlock     `json:"genesis,omitempty"`    // Configuração do bloco gênesis (opcional)
 	Checkpoint        *CheckpointConfig `json:"checkpoint,omitempty"` // Configuração de checkpoints (opcional)
-	API               *APIConfig        `json:"api,omitempty"`      // Configuração da API HTTP (opcional)
+	Prune             *PruneConfig      `json:"prune,omitempty"`      // Profundidade de pruning dos blocos (opcional)
+	API               *APIConfig        `json:"api,omitempty"`        // Configuração da API HTTP (opcional)
+	Storage           *StorageConfig    `json:"storage,omitempty"`    // Configuração de persistência (opcional)
+	TxFilter          *TxFilterConfig   `json:"tx_filter,omitempty"`  // Filtro de remetentes (opcional)
+	RateLimit         *RateLimitConfig  `json:"rate_limit,omitempty"` // Rate limit de mensagens por peer (opcional)
+	RejectLog         *RejectLogConfig  `json:"reject_log,omitempty"` // Log de blocos e transações rejeitados (opcional)
+
+	// Servidores STUN/TURN das conexões WebRTC (vazio = STUN público padrão)
+	ICEServers []ICEServerConfig `json:"ice_servers,omitempty"`
+
+	// Horas desde a última atividade para um peer salvo ser reconectado ao iniciar (0 = 24)
+	PeerMaxAgeHours int `json:"peer_max_age_hours"`
+
+	// Limites de banda por peer e total (opcional)
+	Bandwidth *BandwidthConfig `json:"bandwidth,omitempty"`
+
+	// Aumento mínimo da fee (%) para substituir uma transação pendente com o mesmo nonce (0 = 10)
+	MempoolMinBumpPercent uint64 `json:"mempool_min_bump_percent"`
+
+	// Máximo de transações no mempool (0 = 10000)
+	MempoolMaxSize int `json:"mempool_max_size"`
 }
 
 // LoadNodeConfig carrega a configuração de um arquivo JSON
@@ -72,9 +246,6 @@ func LoadNodeConfig(filepath string) (*NodeConfig, error) {
 	}
 
 	// Validações básicas
-	if config.ID == "" {
-		return nil, fmt.Errorf("node ID is required")
-	}
 	if config.Address == "" {
 		return nil, fmt.Errorf("node address is required")
 	}
@@ -86,11 +257,17 @@ func LoadNodeConfig(filepath string) (*NodeConfig, error) {
 	}
 
 	// Validações da carteira
-	if config.Wallet.PrivateKey == "" {
-		return nil, fmt.Errorf("wallet private key is required")
-	}
-	if config.Wallet.PublicKey == "" {
-		return nil, fmt.Errorf("wallet public key is required")
+	if config.Wallet.Keystore != "" {
+		if config.Wallet.PrivateKey != "" {
+			return nil, fmt.Errorf("wallet private key and keystore are mutually exclusive")
+		}
+	} else {
+		if config.Wallet.PrivateKey == "" {
+			return nil, fmt.Errorf("wallet private key or keystore is required")
+		}
+		if config.Wallet.PublicKey == "" {
+			return nil, fmt.Errorf("wallet public key is required")
+		}
 	}
 	if config.Wallet.Address == "" {
 		return nil, fmt.Errorf("wallet address is required")
@@ -98,11 +275,48 @@ func LoadNodeConfig(filepath string) (*NodeConfig, error) {
 
 	// Validações do bloco gênesis (se fornecido)
 	if config.Genesis != nil {
-		if config.Genesis.RecipientAddr == "" {
-			return nil, fmt.Errorf("genesis recipient address is required")
+		if len(config.Genesis.Allocations) > 0 {
+			if config.Genesis.RecipientAddr != "" || config.Genesis.Amount != 0 {
+				return nil, fmt.Errorf("genesis recipient_addr/amount cannot be combined with allocations")
+			}
+			seen := make(map[string]bool)
+			for i, alloc := range config.Genesis.Allocations {
+				if alloc.Address == "" {
+					return nil, fmt.Errorf("genesis allocation %d address is required", i)
+				}
+				if alloc.Amount == 0 {
+					return nil, fmt.Errorf("genesis allocation %d amount must be greater than 0", i)
+				}
+				if seen[alloc.Address] {
+					return nil, fmt.Errorf("duplicate genesis allocation for %s", alloc.Address)
+				}
+				seen[alloc.Address] = true
+			}
+		} else {
+			if config.Genesis.RecipientAddr == "" {
+				return nil, fmt.Errorf("genesis recipient address is required")
+			}
+			if config.Genesis.Amount == 0 {
+				return nil, fmt.Errorf("genesis amount must be greater than 0")
+			}
+		}
+		allocated := make(map[string]uint64)
+		for _, alloc := range config.Genesis.GetAllocations() {
+			allocated[alloc.Address] = alloc.Amount
 		}
-		if config.Genesis.Amount == 0 {
-			return nil, fmt.Errorf("genesis amount must be greater than 0")
+		vested := make(map[string]bool)
+		for i, v := range config.Genesis.GetVesting() {
+			amount, ok := allocated[v.Address]
+			if !ok {
+				return nil, fmt.Errorf("genesis vesting %d address %s has no allocation", i, v.Address)
+			}
+			if v.Amount > amount {
+				return nil, fmt.Errorf("genesis vesting %d amount %d exceeds allocation %d", i, v.Amount, amount)
+			}
+			if vested[v.Address] {
+				return nil, fmt.Errorf("duplicate genesis vesting for %s", v.Address)
+			}
+			vested[v.Address] = true
 		}
 		if config.Genesis.Hash == "" {
 			return nil, fmt.Errorf("genesis hash is required")
@@ -113,7 +327,16 @@ func LoadNodeConfig(filepath string) (*NodeConfig, error) {
 			config.Genesis.BlockTime = 5000 // Padrão: 5 segundos (5000ms)
 		}
 		if config.Genesis.MaxBlockSize == 0 {
-			config.Genesis.MaxBlockSize = 1000
+			config.Genesis.MaxBlockSize = blockchain.DefaultMaxBlockTxs
+		}
+		if config.Genesis.MaxBlockBytes == 0 {
+			config.Genesis.MaxBlockBytes = blockchain.DefaultMaxBlockBytes
+		}
+		if config.Genesis.MaxTxBytes == 0 {
+			config.Genesis.MaxTxBytes = blockchain.DefaultMaxTxBytes
+		}
+		if config.Genesis.MaxMemoBytes == 0 {
+			config.Genesis.MaxMemoBytes = blockchain.DefaultMaxMemoBytes
 		}
 		if config.Genesis.BlockReward == 0 {
 			config.Genesis.BlockReward = 50
@@ -126,6 +349,9 @@ func LoadNodeConfig(filepath string) (*NodeConfig, error) {
 		if config.Genesis.BlockTime < 1000 {
 			return nil, fmt.Errorf("block time must be at least 1000ms (1 second)")
 		}
+		if err := config.Genesis.ConsensusParams().Validate(); err != nil {
+			return nil, fmt.Errorf("invalid consensus limits: %w", err)
+		}
 	}
 
 	// Valores padrão
@@ -139,6 +365,51 @@ func LoadNodeConfig(filepath string) (*NodeConfig, error) {
 		config.DiscoveryInterval = 30 // Padrão: 30 segundos
 	}
 
+	if config.MaxParallelDials < 0 {
+		return nil, fmt.Errorf("max_parallel_dials cannot be negative")
+	}
+	if config.PeerMaxAgeHours < 0 {
+		return nil, fmt.Errorf("peer_max_age_hours cannot be negative")
+	}
+	if config.SyncTimeoutMs < 0 {
+		return nil, fmt.Errorf("sync_timeout_ms cannot be negative")
+	}
+	if config.SyncBatchSize < 0 {
+		return nil, fmt.Errorf("sync_batch_size cannot be negative")
+	}
+	if config.ParallelSyncPeers < 0 {
+		return nil, fmt.Errorf("parallel_sync_peers cannot be negative")
+	}
+	if config.DownloadTimeoutMs < 0 {
+		return nil, fmt.Errorf("download_timeout_ms cannot be negative")
+	}
+	if config.RejectLog != nil && (config.RejectLog.MaxSizeMB < 0 || config.RejectLog.MaxFiles < 0) {
+		return nil, fmt.Errorf("reject_log max_size_mb and max_files cannot be negative")
+	}
+	if bw := config.Bandwidth; bw != nil && (bw.PeerUploadBytesPerSec < 0 || bw.PeerDownloadBytesPerSec < 0 ||
+		bw.TotalUploadBytesPerSec < 0 || bw.TotalDownloadBytesPerSec < 0) {
+		return nil, fmt.Errorf("bandwidth limits cannot be negative")
+	}
+
+	// Servidores ICE: TURN exige credenciais
+	for i, server := range config.ICEServers {
+		if len(server.URLs) == 0 {
+			return nil, fmt.Errorf("ice_servers[%d] must have at least one url", i)
+		}
+		for _, url := range server.URLs {
+			scheme, _, _ := strings.Cut(url, ":")
+			switch scheme {
+			case "stun", "stuns":
+			case "turn", "turns":
+				if server.Username == "" || server.Credential == "" {
+					return nil, fmt.Errorf("ice_servers[%d]: TURN server %s requires username and credential", i, url)
+				}
+			default:
+				return nil, fmt.Errorf("ice_servers[%d]: unsupported url %s (expected stun:, stuns:, turn: or turns:)", i, url)
+			}
+		}
+	}
+
 	// Validar limites
 	if config.MinPeers > config.MaxPeers {
 		return nil, fmt.Errorf("min_peers (%d) cannot be greater than max_peers (%d)", config.MinPeers, config.MaxPeers)
@@ -171,6 +442,20 @@ func LoadNodeConfig(filepath string) (*NodeConfig, error) {
 			if config.Checkpoint.KeepOnDisk < 1 {
 				return nil, fmt.Errorf("keep_on_disk must be at least 1")
 			}
+			if config.Checkpoint.SignatureQuorum < 0 || config.Checkpoint.SignatureQuorum > 100 {
+				return nil, fmt.Errorf("checkpoint signature_quorum must be between 0 and 100, got %d", config.Checkpoint.SignatureQuorum)
+			}
+		}
+	}
+
+	// Pruning de blocos (sem valores padrão: 0 mantém todos os blocos)
+	if config.Prune != nil {
+		if config.Prune.KeepInMemory < 0 || config.Prune.KeepOnDisk < 0 {
+			return nil, fmt.Errorf("prune keep_in_memory and keep_on_disk cannot be negative")
+		}
+		// Blocos que saem da memória precisam continuar no disco para servir o sync
+		if config.Prune.KeepOnDisk > 0 && (config.Prune.KeepInMemory == 0 || config.Prune.KeepOnDisk < config.Prune.KeepInMemory) {
+			return nil, fmt.Errorf("prune keep_on_disk (%d) must be at least keep_in_memory (%d)", config.Prune.KeepOnDisk, config.Prune.KeepInMemory)
 		}
 	}
 
@@ -187,6 +472,29 @@ func LoadNodeConfig(filepath string) (*NodeConfig, error) {
 			if config.API.Password == "" {
 				return nil, fmt.Errorf("API password is required when API is enabled")
 			}
+			if (config.API.TLSCertFile == "") != (config.API.TLSKeyFile == "") {
+				return nil, fmt.Errorf("API TLS requires both tls_cert_file and tls_key_file")
+			}
+			if config.API.TokenTTL < 0 {
+				return nil, fmt.Errorf("API token_ttl must not be negative")
+			}
+		}
+	}
+
+	// Configuração de persistência (valores padrão e validações)
+	if config.Storage != nil {
+		if config.Storage.SaveRetries == 0 {
+			config.Storage.SaveRetries = 3 // Padrão: 3 tentativas extras (negativo desativa)
+		}
+		if config.Storage.SaveBackoffMs == 0 {
+			config.Storage.SaveBackoffMs = 100 // Padrão: 100ms
+		}
+
+		if config.Storage.SaveBackoffMs < 0 {
+			return nil, fmt.Errorf("storage save_backoff_ms cannot be negative")
+		}
+		if config.Storage.CompactIntervalHours < 0 {
+			return nil, fmt.Errorf("storage compact_interval_hours cannot be negative")
 		}
 	}
 
@@ -0,0 +1,71 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeTestConfig grava um config mínimo com o trecho de gênesis informado e o carrega
+func writeTestConfig(t *testing.T, genesisExtra string) *NodeConfig {
+	t.Helper()
+
+	data := `{
+  "address": ":9001",
+  "db_path": "./data/test",
+  "signaling_server": "ws://localhost:9000/ws",
+  "wallet": {"private_key": "aa", "public_key": "bb", "address": "cc"},
+  "genesis": {
+    "timestamp": 1609459200,
+    "recipient_addr": "cc",
+    "amount": 1000,
+    "hash": "dd"` + genesisExtra + `
+  }
+}`
+	path := filepath.Join(t.TempDir(), "node.json")
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	cfg, err := LoadNodeConfig(path)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	return cfg
+}
+
+func TestGenesisUnbondingPeriodKeepsExplicitZero(t *testing.T) {
+	// Ausente: fica nil e o nó usa o padrão da chain
+	if cfg := writeTestConfig(t, ""); cfg.Genesis.UnbondingPeriod != nil {
+		t.Errorf("Missing unbonding_period should stay nil, got %d", *cfg.Genesis.UnbondingPeriod)
+	}
+
+	// Zero explícito: unstake liberado imediatamente, não o padrão
+	cfg := writeTestConfig(t, `, "unbonding_period": 0`)
+	if cfg.Genesis.UnbondingPeriod == nil || *cfg.Genesis.UnbondingPeriod != 0 {
+		t.Errorf("Explicit unbonding_period 0 should be kept, got %v", cfg.Genesis.UnbondingPeriod)
+	}
+
+	cfg = writeTestConfig(t, `, "unbonding_period": 25`)
+	if cfg.Genesis.UnbondingPeriod == nil || *cfg.Genesis.UnbondingPeriod != 25 {
+		t.Errorf("Expected unbonding_period 25, got %v", cfg.Genesis.UnbondingPeriod)
+	}
+}
+
+func TestGenesisSlashBasisPointsKeepsExplicitZero(t *testing.T) {
+	// Ausente: fica nil e o nó usa o padrão da chain
+	if cfg := writeTestConfig(t, ""); cfg.Genesis.SlashBasisPoints != nil {
+		t.Errorf("Missing slash_basis_points should stay nil, got %d", *cfg.Genesis.SlashBasisPoints)
+	}
+
+	// Zero explícito: assinatura dupla sem punição, não o padrão
+	cfg := writeTestConfig(t, `, "slash_basis_points": 0`)
+	if cfg.Genesis.SlashBasisPoints == nil || *cfg.Genesis.SlashBasisPoints != 0 {
+		t.Errorf("Explicit slash_basis_points 0 should be kept, got %v", cfg.Genesis.SlashBasisPoints)
+	}
+
+	cfg = writeTestConfig(t, `, "slash_basis_points": 500`)
+	if cfg.Genesis.SlashBasisPoints == nil || *cfg.Genesis.SlashBasisPoints != 500 {
+		t.Errorf("Expected slash_basis_points 500, got %v", cfg.Genesis.SlashBasisPoints)
+	}
+}
@@ -1,6 +1,8 @@
 package api
 
 import (
+	"time"
+
 	"github.com/krakovia/blockchain/pkg/blockchain"
 	"github.com/krakovia/blockchain/pkg/network"
 )
@@ -14,6 +16,14 @@ func (p *PeerAdapter) GetID() string {
 	return p.peer.ID
 }
 
+func (p *PeerAdapter) GetBytesSent() uint64 {
+	return p.peer.BytesSent()
+}
+
+func (p *PeerAdapter) GetBytesReceived() uint64 {
+	return p.peer.BytesReceived()
+}
+
 // BlockAdapter adapta blockchain.Block para BlockInfo
 type BlockAdapter struct {
 	block *blockchain.Block
@@ -47,6 +57,196 @@ func (b *BlockAdapter) GetTransactionCount() int {
 	return len(b.block.Transactions)
 }
 
+func (b *BlockAdapter) GetValidatorAddr() string {
+	if b.block == nil {
+		return ""
+	}
+	return b.block.Header.ValidatorAddr
+}
+
+func (b *BlockAdapter) GetVersion() uint32 {
+	if b.block == nil {
+		return 0
+	}
+	return b.block.Header.Version
+}
+
+func (b *BlockAdapter) GetPreviousHash() string {
+	if b.block == nil {
+		return ""
+	}
+	return b.block.Header.PreviousHash
+}
+
+func (b *BlockAdapter) GetMerkleRoot() string {
+	if b.block == nil {
+		return ""
+	}
+	return b.block.Header.MerkleRoot
+}
+
+func (b *BlockAdapter) GetSignature() string {
+	if b.block == nil {
+		return ""
+	}
+	return b.block.Header.Signature
+}
+
+func (b *BlockAdapter) GetNonce() uint64 {
+	if b.block == nil {
+		return 0
+	}
+	return b.block.Header.Nonce
+}
+
+func (b *BlockAdapter) GetCheckpointHash() string {
+	if b.block == nil {
+		return ""
+	}
+	return b.block.Header.CheckpointHash
+}
+
+func (b *BlockAdapter) GetCheckpointHeight() uint64 {
+	if b.block == nil {
+		return 0
+	}
+	return b.block.Header.CheckpointHeight
+}
+
+func (b *BlockAdapter) GetTransactions() []TxInfo {
+	if b.block == nil {
+		return []TxInfo{}
+	}
+	txs := make([]TxInfo, len(b.block.Transactions))
+	for i, tx := range b.block.Transactions {
+		txs[i] = &TxAdapter{tx: tx}
+	}
+	return txs
+}
+
+// ValidatorAdapter adapta blockchain.Validator para ValidatorInfo
+type ValidatorAdapter struct {
+	validator blockchain.Validator
+}
+
+func (v *ValidatorAdapter) GetAddress() string {
+	return v.validator.Address
+}
+
+func (v *ValidatorAdapter) GetStake() uint64 {
+	return v.validator.Stake
+}
+
+func (v *ValidatorAdapter) GetName() string {
+	return v.validator.Name
+}
+
+// RewardProjectionAdapter adapta blockchain.RewardProjection para RewardProjectionInfo
+type RewardProjectionAdapter struct {
+	projection blockchain.RewardProjection
+}
+
+func (p *RewardProjectionAdapter) GetAddress() string {
+	return p.projection.Address
+}
+
+func (p *RewardProjectionAdapter) GetStake() uint64 {
+	return p.projection.Stake
+}
+
+func (p *RewardProjectionAdapter) GetTotalStake() uint64 {
+	return p.projection.TotalStake
+}
+
+func (p *RewardProjectionAdapter) GetStakeShare() float64 {
+	return p.projection.StakeShare
+}
+
+func (p *RewardProjectionAdapter) GetSelectionProbability() float64 {
+	return p.projection.SelectionProbability
+}
+
+func (p *RewardProjectionAdapter) GetBlockTime() time.Duration {
+	return p.projection.BlockTime
+}
+
+func (p *RewardProjectionAdapter) GetBlockReward() uint64 {
+	return p.projection.BlockReward
+}
+
+func (p *RewardProjectionAdapter) ExpectedBlocks(window time.Duration) float64 {
+	return p.projection.ExpectedBlocks(window)
+}
+
+func (p *RewardProjectionAdapter) ExpectedReward(window time.Duration) float64 {
+	return p.projection.ExpectedReward(window)
+}
+
+// GenesisAdapter adapta o bloco gênesis e a configuração da chain para GenesisInfo
+type GenesisAdapter struct {
+	block  *blockchain.Block
+	config blockchain.ChainConfig
+}
+
+// NewGenesisAdapter cria um GenesisAdapter
+func NewGenesisAdapter(block *blockchain.Block, config blockchain.ChainConfig) *GenesisAdapter {
+	return &GenesisAdapter{block: block, config: config}
+}
+
+func (g *GenesisAdapter) GetHash() string {
+	if g.block == nil {
+		return ""
+	}
+	return g.block.Hash
+}
+
+func (g *GenesisAdapter) GetTimestamp() int64 {
+	if g.block == nil {
+		return 0
+	}
+	return g.block.Header.Timestamp
+}
+
+func (g *GenesisAdapter) GetRecipient() string {
+	if g.block == nil {
+		return ""
+	}
+	if coinbase := g.block.GetCoinbaseTransaction(); coinbase != nil {
+		return coinbase.To
+	}
+	return g.block.Header.ValidatorAddr
+}
+
+func (g *GenesisAdapter) GetAmount() uint64 {
+	if g.block == nil {
+		return 0
+	}
+	if coinbase := g.block.GetCoinbaseTransaction(); coinbase != nil {
+		return coinbase.Amount
+	}
+	return 0
+}
+
+func (g *GenesisAdapter) GetBlockTime() time.Duration {
+	return g.config.BlockTime
+}
+
+func (g *GenesisAdapter) GetMaxBlockSize() int {
+	return g.config.Consensus.MaxBlockTxs
+}
+
+func (g *GenesisAdapter) GetBlockReward() uint64 {
+	return g.config.BlockReward
+}
+
+func (g *GenesisAdapter) GetMinValidatorStake() uint64 {
+	return g.config.MinValidatorStake
+}
+
+func (g *GenesisAdapter) GetMinFee() uint64 {
+	return g.config.MinFee
+}
+
 // TxAdapter adapta blockchain.Transaction para TxInfo
 type TxAdapter struct {
 	tx *blockchain.Transaction
@@ -58,3 +258,52 @@ func (t *TxAdapter) GetID() string {
 	}
 	return t.tx.ID
 }
+
+func (t *TxAdapter) GetFrom() string {
+	if t.tx == nil {
+		return ""
+	}
+	return t.tx.From
+}
+
+func (t *TxAdapter) GetTo() string {
+	if t.tx == nil {
+		return ""
+	}
+	return t.tx.To
+}
+
+func (t *TxAdapter) GetAmount() uint64 {
+	if t.tx == nil {
+		return 0
+	}
+	return t.tx.Amount
+}
+
+func (t *TxAdapter) GetFee() uint64 {
+	if t.tx == nil {
+		return 0
+	}
+	return t.tx.Fee
+}
+
+func (t *TxAdapter) GetNonce() uint64 {
+	if t.tx == nil {
+		return 0
+	}
+	return t.tx.Nonce
+}
+
+func (t *TxAdapter) GetTimestamp() int64 {
+	if t.tx == nil {
+		return 0
+	}
+	return t.tx.Timestamp
+}
+
+func (t *TxAdapter) GetData() string {
+	if t.tx == nil {
+		return ""
+	}
+	return t.tx.Data
+}
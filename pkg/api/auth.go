@@ -0,0 +1,191 @@
+package api
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// DefaultTokenTTL validade padrão dos tokens emitidos por /api/login
+const DefaultTokenTTL = time.Hour
+
+// tokenClaims conteúdo assinado de um token
+type tokenClaims struct {
+	Subject   string `json:"sub"` // Usuário autenticado
+	ExpiresAt int64  `json:"exp"` // Expiração (unix, em segundos)
+}
+
+// LoginRequest corpo de POST /api/login
+type LoginRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// LoginResponse token emitido por POST /api/login
+type LoginResponse struct {
+	Token     string `json:"token"`
+	TokenType string `json:"token_type"`
+	ExpiresAt int64  `json:"expires_at"`
+}
+
+// newTokenSecret retorna a chave de assinatura configurada ou, se vazia, uma chave aleatória
+// (tokens deixam de valer quando o nó reinicia)
+func newTokenSecret(configured string) []byte {
+	if configured != "" {
+		return []byte(configured)
+	}
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		panic(fmt.Sprintf("failed to generate API token secret: %v", err))
+	}
+	return secret
+}
+
+// tokenTTL retorna a validade dos tokens
+func (s *Server) tokenTTL() time.Duration {
+	if s.config.TokenTTL > 0 {
+		return s.config.TokenTTL
+	}
+	return DefaultTokenTTL
+}
+
+// IssueToken emite um token para o usuário, válido até expiresAt. O token é o JSON das claims
+// e a assinatura HMAC-SHA256 dele, ambos em base64 URL e separados por ponto.
+func (s *Server) IssueToken(username string, expiresAt time.Time) (string, error) {
+	claims, err := json.Marshal(tokenClaims{Subject: username, ExpiresAt: expiresAt.Unix()})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal token claims: %w", err)
+	}
+	payload := base64.RawURLEncoding.EncodeToString(claims)
+	return payload + "." + s.signToken(payload), nil
+}
+
+// VerifyToken verifica a assinatura e a validade de um token e retorna o usuário
+func (s *Server) VerifyToken(token string) (string, error) {
+	payload, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return "", fmt.Errorf("malformed token")
+	}
+	if !hmac.Equal([]byte(signature), []byte(s.signToken(payload))) {
+		return "", fmt.Errorf("invalid token signature")
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return "", fmt.Errorf("malformed token payload: %w", err)
+	}
+	var claims tokenClaims
+	if err := json.Unmarshal(data, &claims); err != nil {
+		return "", fmt.Errorf("malformed token claims: %w", err)
+	}
+	if time.Now().Unix() >= claims.ExpiresAt {
+		return "", fmt.Errorf("token expired")
+	}
+	if claims.Subject != s.config.Username {
+		return "", fmt.Errorf("token issued for unknown user %q", claims.Subject)
+	}
+	return claims.Subject, nil
+}
+
+// signToken assina o payload de um token com a chave do servidor
+func (s *Server) signToken(payload string) string {
+	mac := hmac.New(sha256.New, s.tokenSecret)
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// validCredentials compara usuário e senha com os configurados em tempo constante
+func (s *Server) validCredentials(username, password string) bool {
+	userOK := subtle.ConstantTimeCompare([]byte(username), []byte(s.config.Username)) == 1
+	passOK := subtle.ConstantTimeCompare([]byte(password), []byte(s.config.Password)) == 1
+	return userOK && passOK
+}
+
+// authEnabled indica se a API exige autenticação (usuário e senha configurados)
+func (s *Server) authEnabled() bool {
+	return s.config.Username != "" && s.config.Password != ""
+}
+
+// authenticate verifica as credenciais da requisição: token no cabeçalho Authorization: Bearer
+// (ou no parâmetro token nos WebSockets) e, se BasicAuth estiver ativo, HTTP Basic
+func (s *Server) authenticate(r *http.Request) bool {
+	if token, ok := bearerToken(r); ok {
+		_, err := s.VerifyToken(token)
+		return err == nil
+	}
+
+	if !s.config.BasicAuth {
+		return false
+	}
+	username, password, ok := r.BasicAuth()
+	if !ok && isWebSocketPath(r.URL.Path) {
+		username, password, ok = queryCredentials(r)
+	}
+	return ok && s.validCredentials(username, password)
+}
+
+// isWebSocketPath indica se a rota é um WebSocket, onde o navegador não envia cabeçalhos e as
+// credenciais podem ir na URL
+func isWebSocketPath(path string) bool {
+	return path == "/api/ws" || path == "/api/stream/raw"
+}
+
+// bearerToken lê o token do cabeçalho Authorization ou, nos WebSockets, do parâmetro token
+func bearerToken(r *http.Request) (string, bool) {
+	if header := r.Header.Get("Authorization"); header != "" {
+		scheme, token, ok := strings.Cut(header, " ")
+		if ok && strings.EqualFold(scheme, "Bearer") && token != "" {
+			return token, true
+		}
+		return "", false
+	}
+	if isWebSocketPath(r.URL.Path) {
+		if token := r.URL.Query().Get("token"); token != "" {
+			return token, true
+		}
+	}
+	return "", false
+}
+
+// handleLogin troca usuário e senha por um token (POST /api/login)
+func (s *Server) handleLogin(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !s.authEnabled() {
+		http.Error(w, "Authentication is not configured", http.StatusNotFound)
+		return
+	}
+
+	var req LoginRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if !s.validCredentials(req.Username, req.Password) {
+		http.Error(w, "Invalid credentials", http.StatusUnauthorized)
+		return
+	}
+
+	expiresAt := time.Now().Add(s.tokenTTL())
+	token, err := s.IssueToken(req.Username, expiresAt)
+	if err != nil {
+		http.Error(w, "Failed to issue token", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(LoginResponse{
+		Token:     token,
+		TokenType: "Bearer",
+		ExpiresAt: expiresAt.Unix(),
+	})
+}
@@ -0,0 +1,234 @@
+package api
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/krakovia/blockchain/pkg/blockchain"
+)
+
+// Tipos de evento enviados em /api/ws
+const (
+	EventNewBlock           = "new_block"
+	EventNewTransaction     = "new_transaction"
+	EventPeerConnected      = "peer_connected"
+	EventPeerDisconnected   = "peer_disconnected"
+	EventMiningStateChanged = "mining_state_changed"
+	EventReorg              = "reorg"
+)
+
+// Parâmetros das conexões WebSocket de eventos
+const (
+	eventClientBuffer = 64               // Eventos pendentes por cliente antes de ele ser desconectado
+	eventWriteTimeout = 10 * time.Second // Prazo para escrever um evento ou ping
+	eventPingInterval = 30 * time.Second // Intervalo de pings para detectar clientes mortos
+)
+
+// Event evento enviado aos clientes de /api/ws
+type Event struct {
+	Type      string      `json:"type"`
+	Timestamp int64       `json:"timestamp"`
+	Data      interface{} `json:"data"`
+}
+
+// eventHub distribui eventos às conexões WebSocket abertas
+type eventHub struct {
+	mu      sync.Mutex
+	clients map[chan []byte]struct{}
+}
+
+// newEventHub cria um hub sem clientes
+func newEventHub() *eventHub {
+	return &eventHub{clients: make(map[chan []byte]struct{})}
+}
+
+// subscribe registra um cliente e retorna o canal dos seus eventos
+func (h *eventHub) subscribe() chan []byte {
+	return h.subscribeBuffered(eventClientBuffer)
+}
+
+// subscribeBuffered registra um cliente que pode acumular até buffer eventos pendentes
+func (h *eventHub) subscribeBuffered(buffer int) chan []byte {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	ch := make(chan []byte, buffer)
+	h.clients[ch] = struct{}{}
+	return ch
+}
+
+// unsubscribe remove o cliente (no-op se ele já foi removido)
+func (h *eventHub) unsubscribe(ch chan []byte) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, ok := h.clients[ch]; ok {
+		delete(h.clients, ch)
+		close(ch)
+	}
+}
+
+// broadcast envia o evento a todos os clientes. Um cliente que não consome os eventos a tempo
+// é desconectado em vez de travar quem publica.
+func (h *eventHub) broadcast(data []byte) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for ch := range h.clients {
+		select {
+		case ch <- data:
+		default:
+			delete(h.clients, ch)
+			close(ch)
+		}
+	}
+}
+
+// closeAll desconecta todos os clientes
+func (h *eventHub) closeAll() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for ch := range h.clients {
+		delete(h.clients, ch)
+		close(ch)
+	}
+}
+
+// clientCount retorna o número de clientes conectados
+func (h *eventHub) clientCount() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.clients)
+}
+
+// Publish envia um evento aos clientes de /api/ws (não bloqueia)
+func (s *Server) Publish(eventType string, data interface{}) {
+	if s.events.clientCount() == 0 {
+		return
+	}
+
+	payload, err := json.Marshal(Event{Type: eventType, Timestamp: time.Now().Unix(), Data: data})
+	if err != nil {
+		fmt.Printf("Failed to marshal %s event: %v\n", eventType, err)
+		return
+	}
+	s.events.broadcast(payload)
+}
+
+// PublishNewBlock publica um bloco adicionado à chain (mesmos campos de /api/lastblock)
+func (s *Server) PublishNewBlock(block *blockchain.Block) {
+	s.Publish(EventNewBlock, s.blockSummaryJSON(&BlockAdapter{block: block}))
+}
+
+// PublishNewTransaction publica uma transação que entrou no mempool
+func (s *Server) PublishNewTransaction(tx *blockchain.Transaction) {
+	s.Publish(EventNewTransaction, txToJSON(&TxAdapter{tx: tx}))
+}
+
+// PublishPeerConnected publica a conexão de um peer
+func (s *Server) PublishPeerConnected(peerID string, peerCount int) {
+	s.Publish(EventPeerConnected, map[string]interface{}{"id": peerID, "peer_count": peerCount})
+}
+
+// PublishPeerDisconnected publica a desconexão de um peer
+func (s *Server) PublishPeerDisconnected(peerID string, peerCount int) {
+	s.Publish(EventPeerDisconnected, map[string]interface{}{"id": peerID, "peer_count": peerCount})
+}
+
+// PublishMiningState publica o início ou a parada da mineração
+func (s *Server) PublishMiningState(mining bool) {
+	s.Publish(EventMiningStateChanged, map[string]interface{}{"mining": mining})
+}
+
+// PublishReorg publica a troca da chain principal por um fork mais pesado; depth é o número de
+// blocos substituídos
+func (s *Server) PublishReorg(result *blockchain.ReorgResult, newTip *blockchain.Block) {
+	data := map[string]interface{}{
+		"fork_height": result.ForkHeight,
+		"depth":       len(result.Replaced),
+		"height":      newTip.Header.Height,
+		"new_tip":     newTip.Hash,
+		"old_weight":  result.OldWeight,
+		"new_weight":  result.NewWeight,
+	}
+	if len(result.Replaced) > 0 {
+		data["old_tip"] = result.Replaced[len(result.Replaced)-1].Hash
+	}
+	s.Publish(EventReorg, data)
+}
+
+// handleEvents abre a conexão WebSocket de eventos (GET /api/ws) e envia os eventos
+// publicados até o cliente desconectar
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	conn, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// Upgrade já respondeu ao cliente com o erro
+		return
+	}
+	defer conn.Close()
+
+	events := s.events.subscribe()
+	defer s.events.unsubscribe(events)
+
+	// Leitura só para detectar o fechamento pelo cliente (mensagens recebidas são ignoradas)
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	ping := time.NewTicker(eventPingInterval)
+	defer ping.Stop()
+
+	for {
+		select {
+		case data, ok := <-events:
+			if !ok {
+				// Cliente lento ou servidor parando
+				_ = conn.WriteControl(websocket.CloseMessage,
+					websocket.FormatCloseMessage(websocket.CloseGoingAway, ""), time.Now().Add(eventWriteTimeout))
+				return
+			}
+			_ = conn.SetWriteDeadline(time.Now().Add(eventWriteTimeout))
+			if err := conn.WriteMessage(websocket.TextMessage, data); err != nil {
+				return
+			}
+		case <-ping.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(eventWriteTimeout)); err != nil {
+				return
+			}
+		case <-closed:
+			return
+		}
+	}
+}
+
+// queryCredentials lê as credenciais do parâmetro auth (base64 de "usuário:senha"), usado
+// pelo navegador em /api/ws, onde o WebSocket não permite enviar o cabeçalho Authorization
+func queryCredentials(r *http.Request) (string, string, bool) {
+	token := r.URL.Query().Get("auth")
+	if token == "" {
+		return "", "", false
+	}
+	decoded, err := base64.StdEncoding.DecodeString(token)
+	if err != nil {
+		return "", "", false
+	}
+	return strings.Cut(string(decoded), ":")
+}
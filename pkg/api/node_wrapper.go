@@ -16,12 +16,20 @@ type RealNode interface {
 	GetMempoolSize() int
 	GetPeers() []*network.Peer
 	GetLastBlock() *blockchain.Block
+	GetBlockByHeight(height uint64) (*blockchain.Block, bool)
+	GetBlockByHash(hash string) (*blockchain.Block, bool)
+	GetBlockRange(fromHeight, toHeight uint64) []*blockchain.Block
+	GetChain() *blockchain.Chain
 	IsMining() bool
 	StartMining() error
 	StopMining()
+	GetBlockTemplate() (*blockchain.BlockTemplate, error)
+	SubmitBlock(block *blockchain.Block) error
+	ForceSyncFrom(peerID string) error
 	CreateTransaction(to string, amount, fee uint64, data string) (*blockchain.Transaction, error)
 	CreateStakeTransaction(amount, fee uint64) (*blockchain.Transaction, error)
 	CreateUnstakeTransaction(amount, fee uint64) (*blockchain.Transaction, error)
+	CreateRegisterNameTransaction(name string, fee uint64) (*blockchain.Transaction, error)
 }
 
 // NodeWrapper envolve o node real para implementar NodeInterface
@@ -50,6 +58,10 @@ func (w *NodeWrapper) GetBalance() uint64 {
 	return w.node.GetBalance()
 }
 
+func (w *NodeWrapper) GetImmatureBalance() uint64 {
+	return w.node.GetChain().GetImmatureBalance(w.node.GetWalletAddress())
+}
+
 func (w *NodeWrapper) GetStake() uint64 {
 	return w.node.GetStake()
 }
@@ -76,6 +88,85 @@ func (w *NodeWrapper) GetLastBlock() BlockInfo {
 	return &BlockAdapter{block: block}
 }
 
+func (w *NodeWrapper) GetBlockByHeight(height uint64) (BlockInfo, bool) {
+	block, exists := w.node.GetBlockByHeight(height)
+	if !exists {
+		return nil, false
+	}
+	return &BlockAdapter{block: block}, true
+}
+
+func (w *NodeWrapper) GetBlockByHash(hash string) (BlockInfo, bool) {
+	block, exists := w.node.GetBlockByHash(hash)
+	if !exists {
+		return nil, false
+	}
+	return &BlockAdapter{block: block}, true
+}
+
+func (w *NodeWrapper) GetBlockRange(fromHeight, toHeight uint64) []BlockInfo {
+	realBlocks := w.node.GetBlockRange(fromHeight, toHeight)
+	blocks := make([]BlockInfo, len(realBlocks))
+	for i, b := range realBlocks {
+		blocks[i] = &BlockAdapter{block: b}
+	}
+	return blocks
+}
+
+func (w *NodeWrapper) GetRawBlockRange(fromHeight, toHeight uint64) []*blockchain.Block {
+	return w.node.GetBlockRange(fromHeight, toHeight)
+}
+
+func (w *NodeWrapper) GetGenesis() GenesisInfo {
+	chain := w.node.GetChain()
+	return NewGenesisAdapter(chain.GetGenesis(), chain.GetConfig())
+}
+
+func (w *NodeWrapper) GetValidators() []ValidatorInfo {
+	realValidators := w.node.GetChain().GetValidators()
+	validators := make([]ValidatorInfo, len(realValidators))
+	for i, v := range realValidators {
+		validators[i] = &ValidatorAdapter{validator: v}
+	}
+	return validators
+}
+
+func (w *NodeWrapper) GetValidatorName(address string) string {
+	return w.node.GetChain().GetName(address)
+}
+
+func (w *NodeWrapper) GetRewardProjection(address string) RewardProjectionInfo {
+	return &RewardProjectionAdapter{projection: w.node.GetChain().PendingReward(address)}
+}
+
+func (w *NodeWrapper) GetValidatorSchedule(fromHeight, toHeight uint64) []blockchain.ScheduleEntry {
+	return w.node.GetChain().ValidatorSchedule(fromHeight, toHeight)
+}
+
+func (w *NodeWrapper) FindTransaction(txID string) (TxInfo, uint64, bool) {
+	tx, height, found := w.node.GetChain().FindTransaction(txID)
+	if !found {
+		return nil, 0, false
+	}
+	return &TxAdapter{tx: tx}, height, true
+}
+
+func (w *NodeWrapper) GetAddressHistory(address string, limit int) ([]TxInfo, error) {
+	realTxs, err := w.node.GetChain().GetAddressHistory(address, limit)
+	if err != nil {
+		return nil, err
+	}
+	txs := make([]TxInfo, len(realTxs))
+	for i, tx := range realTxs {
+		txs[i] = &TxAdapter{tx: tx}
+	}
+	return txs, nil
+}
+
+func (w *NodeWrapper) GetBalanceAtHeight(address string, height uint64) (uint64, error) {
+	return w.node.GetChain().GetBalanceAtHeight(address, height)
+}
+
 func (w *NodeWrapper) IsMining() bool {
 	return w.node.IsMining()
 }
@@ -88,6 +179,18 @@ func (w *NodeWrapper) StopMining() {
 	w.node.StopMining()
 }
 
+func (w *NodeWrapper) GetBlockTemplate() (*blockchain.BlockTemplate, error) {
+	return w.node.GetBlockTemplate()
+}
+
+func (w *NodeWrapper) SubmitBlock(block *blockchain.Block) error {
+	return w.node.SubmitBlock(block)
+}
+
+func (w *NodeWrapper) ForceSyncFrom(peerID string) error {
+	return w.node.ForceSyncFrom(peerID)
+}
+
 func (w *NodeWrapper) CreateTransaction(to string, amount, fee uint64, data string) (TxInfo, error) {
 	tx, err := w.node.CreateTransaction(to, amount, fee, data)
 	if err != nil {
@@ -111,3 +214,11 @@ func (w *NodeWrapper) CreateUnstakeTransaction(amount, fee uint64) (TxInfo, erro
 	}
 	return &TxAdapter{tx: tx}, nil
 }
+
+func (w *NodeWrapper) CreateRegisterNameTransaction(name string, fee uint64) (TxInfo, error) {
+	tx, err := w.node.CreateRegisterNameTransaction(name, fee)
+	if err != nil {
+		return nil, err
+	}
+	return &TxAdapter{tx: tx}, nil
+}
@@ -4,22 +4,40 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/krakovia/blockchain/pkg/blockchain"
 )
 
 // Config configuração da API HTTP
 type Config struct {
-	Enabled  bool
-	Address  string
-	Username string
-	Password string
+	Enabled        bool
+	Address        string
+	Username       string
+	Password       string
+	MetricsEnabled bool // Expõe GET /metrics no formato do Prometheus
+
+	BasicAuth   bool          // Aceita HTTP Basic além dos tokens de /api/login (compatibilidade)
+	TokenSecret string        // Chave HMAC dos tokens (vazia = aleatória; tokens não sobrevivem a reinício)
+	TokenTTL    time.Duration // Validade dos tokens (0 = DefaultTokenTTL)
+	TLSCertFile string        // Certificado TLS (com TLSKeyFile, serve HTTPS)
+	TLSKeyFile  string        // Chave privada do certificado TLS
 }
 
 // Server servidor HTTP da API
 type Server struct {
-	config *Config
-	node   NodeInterface
-	server *http.Server
+	config      *Config
+	node        NodeInterface
+	server      *http.Server
+	metrics     http.Handler
+	events      *eventHub
+	rawEvents   *eventHub
+	upgrader    websocket.Upgrader
+	tokenSecret []byte
 }
 
 // NodeInterface interface que o node deve implementar
@@ -28,22 +46,41 @@ type NodeInterface interface {
 	GetWalletAddress() string
 	GetChainHeight() uint64
 	GetBalance() uint64
+	GetImmatureBalance() uint64 // Recompensas de bloco ainda não gastáveis
 	GetStake() uint64
 	GetNonce() uint64
 	GetMempoolSize() int
 	GetPeers() []PeerInfo
 	GetLastBlock() BlockInfo
+	GetBlockByHeight(height uint64) (BlockInfo, bool)
+	GetBlockByHash(hash string) (BlockInfo, bool)
+	GetBlockRange(fromHeight, toHeight uint64) []BlockInfo
+	GetRawBlockRange(fromHeight, toHeight uint64) []*blockchain.Block // Blocos completos (stream bruto)
+	GetGenesis() GenesisInfo
+	GetValidators() []ValidatorInfo
+	GetValidatorName(address string) string
+	GetRewardProjection(address string) RewardProjectionInfo
+	GetValidatorSchedule(fromHeight, toHeight uint64) []blockchain.ScheduleEntry // Agenda de validadores (auditoria do consenso)
+	FindTransaction(txID string) (TxInfo, uint64, bool)
+	GetAddressHistory(address string, limit int) ([]TxInfo, error)
+	GetBalanceAtHeight(address string, height uint64) (uint64, error) // Saldo após o bloco height (reexecuta a chain)
 	IsMining() bool
 	StartMining() error
 	StopMining()
+	GetBlockTemplate() (*blockchain.BlockTemplate, error) // Mineração externa
+	SubmitBlock(block *blockchain.Block) error            // Mineração externa
+	ForceSyncFrom(peerID string) error                    // Sync forçado com um peer (admin)
 	CreateTransaction(to string, amount, fee uint64, data string) (TxInfo, error)
 	CreateStakeTransaction(amount, fee uint64) (TxInfo, error)
 	CreateUnstakeTransaction(amount, fee uint64) (TxInfo, error)
+	CreateRegisterNameTransaction(name string, fee uint64) (TxInfo, error)
 }
 
 // PeerInfo informações de um peer
 type PeerInfo interface {
 	GetID() string
+	GetBytesSent() uint64
+	GetBytesReceived() uint64
 }
 
 // BlockInfo informações de um bloco
@@ -52,83 +89,190 @@ type BlockInfo interface {
 	GetHash() string
 	GetTimestamp() int64
 	GetTransactionCount() int
+	GetValidatorAddr() string
+	GetVersion() uint32
+	GetPreviousHash() string
+	GetMerkleRoot() string
+	GetSignature() string
+	GetNonce() uint64
+	GetCheckpointHash() string
+	GetCheckpointHeight() uint64
+	GetTransactions() []TxInfo
+}
+
+// ValidatorInfo informações de um validador
+type ValidatorInfo interface {
+	GetAddress() string
+	GetStake() uint64
+	GetName() string
+}
+
+// RewardProjectionInfo projeção de produção de blocos e recompensa de um validador
+type RewardProjectionInfo interface {
+	GetAddress() string
+	GetStake() uint64
+	GetTotalStake() uint64
+	GetStakeShare() float64
+	GetSelectionProbability() float64
+	GetBlockTime() time.Duration
+	GetBlockReward() uint64
+	ExpectedBlocks(window time.Duration) float64
+	ExpectedReward(window time.Duration) float64
+}
+
+// GenesisInfo informações do bloco gênesis e da configuração da chain
+type GenesisInfo interface {
+	GetHash() string
+	GetTimestamp() int64
+	GetRecipient() string
+	GetAmount() uint64
+	GetBlockTime() time.Duration
+	GetMaxBlockSize() int
+	GetBlockReward() uint64
+	GetMinValidatorStake() uint64
+	GetMinFee() uint64
 }
 
 // TxInfo informações de uma transação
 type TxInfo interface {
 	GetID() string
+	GetFrom() string
+	GetTo() string
+	GetAmount() uint64
+	GetFee() uint64
+	GetNonce() uint64
+	GetTimestamp() int64
+	GetData() string
 }
 
+// MaxBlocksPerRequest limita a quantidade de blocos retornados por /api/blocks (mesmo limite do sync)
+const MaxBlocksPerRequest = 100
+
+// Limites de transações retornadas por /api/address/{addr}/history
+const (
+	DefaultAddressHistoryLimit = 50
+	MaxAddressHistoryLimit     = 500
+)
+
 // NewServer cria um novo servidor API
 func NewServer(node NodeInterface, config *Config) *Server {
 	return &Server{
-		config: config,
-		node:   node,
+		config:      config,
+		node:        node,
+		events:      newEventHub(),
+		rawEvents:   newEventHub(),
+		tokenSecret: newTokenSecret(config.TokenSecret),
 	}
 }
 
+// SetMetricsHandler define o handler servido em /metrics quando MetricsEnabled
+func (s *Server) SetMetricsHandler(handler http.Handler) {
+	s.metrics = handler
+}
+
 // Start inicia o servidor HTTP
 func (s *Server) Start() error {
 	if !s.config.Enabled {
 		return nil
 	}
 
+	s.server = &http.Server{
+		Addr:    s.config.Address,
+		Handler: s.routes(),
+	}
+
+	go func() {
+		var err error
+		if s.TLSEnabled() {
+			fmt.Printf("Starting API server on %s (TLS)\n", s.config.Address)
+			err = s.server.ListenAndServeTLS(s.config.TLSCertFile, s.config.TLSKeyFile)
+		} else {
+			fmt.Printf("Starting API server on %s\n", s.config.Address)
+			err = s.server.ListenAndServe()
+		}
+		if err != nil && err != http.ErrServerClosed {
+			fmt.Printf("API server error: %v\n", err)
+		}
+	}()
+
+	return nil
+}
+
+// TLSEnabled indica se o servidor serve HTTPS
+func (s *Server) TLSEnabled() bool {
+	return s.config.TLSCertFile != "" && s.config.TLSKeyFile != ""
+}
+
+// routes registra os endpoints da API e aplica a autenticação
+func (s *Server) routes() http.Handler {
 	mux := http.NewServeMux()
 
 	// UI
 	mux.HandleFunc("/", s.handleUI)
 
 	// API endpoints
+	mux.HandleFunc("/api/login", s.handleLogin)
 	mux.HandleFunc("/api/status", s.handleStatus)
 	mux.HandleFunc("/api/wallet", s.handleWallet)
 	mux.HandleFunc("/api/peers", s.handlePeers)
 	mux.HandleFunc("/api/lastblock", s.handleLastBlock)
+	mux.HandleFunc("/api/genesis", s.handleGenesis)
+	mux.HandleFunc("/api/block/", s.handleBlock)
+	mux.HandleFunc("/api/blocks", s.handleBlocks)
+	mux.HandleFunc("/api/validators", s.handleValidators)
+	mux.HandleFunc("/api/validators/", s.handleValidatorProjection)
+	mux.HandleFunc("/api/consensus/schedule", s.handleConsensusSchedule)
+	mux.HandleFunc("/api/address/", s.handleAddress)
 	mux.HandleFunc("/api/mining/start", s.handleStartMining)
 	mux.HandleFunc("/api/mining/stop", s.handleStopMining)
+	mux.HandleFunc("/api/mining/template", s.handleBlockTemplate)
+	mux.HandleFunc("/api/mining/submit", s.handleSubmitBlock)
+	mux.HandleFunc("/api/sync/from/", s.handleForceSync)
 	mux.HandleFunc("/api/transaction/send", s.handleSendTransaction)
 	mux.HandleFunc("/api/transaction/stake", s.handleStakeTransaction)
 	mux.HandleFunc("/api/transaction/unstake", s.handleUnstakeTransaction)
+	mux.HandleFunc("/api/transaction/register-name", s.handleRegisterNameTransaction)
+	mux.HandleFunc("/api/transaction/", s.handleTransaction)
+	mux.HandleFunc("/api/ws", s.handleEvents)
+	mux.HandleFunc("/api/stream/raw", s.handleRawStream)
 
-	s.server = &http.Server{
-		Addr:    s.config.Address,
-		Handler: s.authMiddleware(mux),
+	// Métricas do Prometheus
+	if s.config.MetricsEnabled && s.metrics != nil {
+		mux.Handle("/metrics", s.metrics)
 	}
 
-	go func() {
-		fmt.Printf("Starting API server on %s\n", s.config.Address)
-		if err := s.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			fmt.Printf("API server error: %v\n", err)
-		}
-	}()
-
-	return nil
+	return s.authMiddleware(mux)
 }
 
 // Stop para o servidor HTTP
 func (s *Server) Stop() error {
+	s.events.closeAll()
+	s.rawEvents.closeAll()
 	if s.server != nil {
 		return s.server.Close()
 	}
 	return nil
 }
 
-// authMiddleware middleware de autenticação básica
+// authMiddleware middleware de autenticação (token Bearer ou, se habilitado, HTTP Basic)
 func (s *Server) authMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Permitir acesso à UI sem autenticação para facilitar desenvolvimento
-		if r.URL.Path == "/" {
+		// Permitir acesso à UI sem autenticação para facilitar desenvolvimento; o login
+		// verifica as credenciais por conta própria
+		if r.URL.Path == "/" || r.URL.Path == "/api/login" {
 			next.ServeHTTP(w, r)
 			return
 		}
 
-		// Verificar autenticação básica nas rotas /api
-		if s.config.Username != "" && s.config.Password != "" {
-			username, password, ok := r.BasicAuth()
-			if !ok || username != s.config.Username || password != s.config.Password {
-				w.Header().Set("WWW-Authenticate", `Basic realm="Krakovia Node API"`)
-				http.Error(w, "Unauthorized", http.StatusUnauthorized)
-				return
+		// Verificar autenticação nas rotas /api
+		if s.authEnabled() && !s.authenticate(r) {
+			challenge := `Bearer realm="Krakovia Node API"`
+			if s.config.BasicAuth {
+				challenge = `Basic realm="Krakovia Node API"`
 			}
+			w.Header().Set("WWW-Authenticate", challenge)
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
 		}
 
 		next.ServeHTTP(w, r)
@@ -162,10 +306,12 @@ func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
 // handleWallet retorna informações da wallet
 func (s *Server) handleWallet(w http.ResponseWriter, r *http.Request) {
 	wallet := map[string]interface{}{
-		"address": s.node.GetWalletAddress(),
-		"balance": s.node.GetBalance(),
-		"stake":   s.node.GetStake(),
-		"nonce":   s.node.GetNonce(),
+		"address":          s.node.GetWalletAddress(),
+		"name":             s.node.GetValidatorName(s.node.GetWalletAddress()),
+		"balance":          s.node.GetBalance(),
+		"immature_balance": s.node.GetImmatureBalance(),
+		"stake":            s.node.GetStake(),
+		"nonce":            s.node.GetNonce(),
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -175,34 +321,488 @@ func (s *Server) handleWallet(w http.ResponseWriter, r *http.Request) {
 // handlePeers retorna lista de peers
 func (s *Server) handlePeers(w http.ResponseWriter, r *http.Request) {
 	peers := s.node.GetPeers()
-	peerList := make([]map[string]string, 0, len(peers))
+	peerList := make([]map[string]interface{}, 0, len(peers))
 
+	var totalSent, totalReceived uint64
 	for _, peer := range peers {
-		peerList = append(peerList, map[string]string{
-			"id": peer.GetID(),
+		peerList = append(peerList, map[string]interface{}{
+			"id":             peer.GetID(),
+			"bytes_sent":     peer.GetBytesSent(),
+			"bytes_received": peer.GetBytesReceived(),
 		})
+		totalSent += peer.GetBytesSent()
+		totalReceived += peer.GetBytesReceived()
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	_ = json.NewEncoder(w).Encode(map[string]interface{}{
-		"peers": peerList,
-		"count": len(peerList),
+		"peers":          peerList,
+		"count":          len(peerList),
+		"bytes_sent":     totalSent,
+		"bytes_received": totalReceived,
 	})
 }
 
 // handleLastBlock retorna último bloco
 func (s *Server) handleLastBlock(w http.ResponseWriter, r *http.Request) {
-	block := s.node.GetLastBlock()
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(s.blockSummaryJSON(s.node.GetLastBlock()))
+}
+
+// blockSummaryJSON monta o resumo de um bloco (sem as transações)
+func (s *Server) blockSummaryJSON(block BlockInfo) map[string]interface{} {
+	return map[string]interface{}{
+		"height":         block.GetHeight(),
+		"hash":           block.GetHash(),
+		"timestamp":      block.GetTimestamp(),
+		"tx_count":       block.GetTransactionCount(),
+		"validator":      block.GetValidatorAddr(),
+		"validator_name": s.node.GetValidatorName(block.GetValidatorAddr()),
+	}
+}
+
+// handleBlock retorna um bloco completo pela altura (/api/block/{height})
+// ou pelo hash (/api/block/hash/{hash})
+func (s *Server) handleBlock(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	path := strings.TrimPrefix(r.URL.Path, "/api/block/")
+
+	var block BlockInfo
+	var exists bool
+	if hash, ok := strings.CutPrefix(path, "hash/"); ok {
+		if hash == "" || strings.Contains(hash, "/") {
+			writeJSONError(w, http.StatusBadRequest, "invalid block hash")
+			return
+		}
+		block, exists = s.node.GetBlockByHash(hash)
+	} else {
+		height, err := strconv.ParseUint(path, 10, 64)
+		if err != nil {
+			writeJSONError(w, http.StatusBadRequest, "invalid block height")
+			return
+		}
+		block, exists = s.node.GetBlockByHeight(height)
+	}
+
+	if !exists {
+		writeJSONError(w, http.StatusNotFound, "block not found")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(s.blockToJSON(block))
+}
+
+// handleBlocks retorna um intervalo de blocos completos (/api/blocks?from=&to=).
+// Sem "to", retorna até MaxBlocksPerRequest blocos a partir de "from";
+// intervalos maiores são truncados.
+func (s *Server) handleBlocks(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+
+	var from uint64
+	if value := query.Get("from"); value != "" {
+		parsed, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			writeJSONError(w, http.StatusBadRequest, "invalid from height")
+			return
+		}
+		from = parsed
+	}
+
+	to := from + MaxBlocksPerRequest - 1
+	if value := query.Get("to"); value != "" {
+		parsed, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			writeJSONError(w, http.StatusBadRequest, "invalid to height")
+			return
+		}
+		to = parsed
+	}
+
+	if to < from {
+		writeJSONError(w, http.StatusBadRequest, "from must be less than or equal to to")
+		return
+	}
+	if to-from+1 > MaxBlocksPerRequest {
+		to = from + MaxBlocksPerRequest - 1
+	}
+
+	blocks := s.node.GetBlockRange(from, to)
+	blockList := make([]map[string]interface{}, 0, len(blocks))
+	for _, block := range blocks {
+		blockList = append(blockList, s.blockToJSON(block))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"from":   from,
+		"to":     to,
+		"count":  len(blockList),
+		"blocks": blockList,
+	})
+}
+
+// blockToJSON monta a representação completa de um bloco (header, transações e validador)
+func (s *Server) blockToJSON(block BlockInfo) map[string]interface{} {
+	txs := block.GetTransactions()
+	txList := make([]map[string]interface{}, 0, len(txs))
+	for _, tx := range txs {
+		txList = append(txList, txToJSON(tx))
+	}
+
+	header := map[string]interface{}{
+		"version":        block.GetVersion(),
+		"height":         block.GetHeight(),
+		"timestamp":      block.GetTimestamp(),
+		"previous_hash":  block.GetPreviousHash(),
+		"merkle_root":    block.GetMerkleRoot(),
+		"validator_addr": block.GetValidatorAddr(),
+		"signature":      block.GetSignature(),
+		"nonce":          block.GetNonce(),
+	}
+	if block.GetCheckpointHash() != "" {
+		header["checkpoint_hash"] = block.GetCheckpointHash()
+		header["checkpoint_height"] = block.GetCheckpointHeight()
+	}
+
+	return map[string]interface{}{
+		"hash":           block.GetHash(),
+		"height":         block.GetHeight(),
+		"header":         header,
+		"validator":      block.GetValidatorAddr(),
+		"validator_name": s.node.GetValidatorName(block.GetValidatorAddr()),
+		"tx_count":       len(txList),
+		"transactions":   txList,
+	}
+}
+
+// txToJSON monta a representação de uma transação
+func txToJSON(tx TxInfo) map[string]interface{} {
+	return map[string]interface{}{
+		"id":        tx.GetID(),
+		"from":      tx.GetFrom(),
+		"to":        tx.GetTo(),
+		"amount":    tx.GetAmount(),
+		"fee":       tx.GetFee(),
+		"nonce":     tx.GetNonce(),
+		"timestamp": tx.GetTimestamp(),
+		"data":      tx.GetData(),
+	}
+}
+
+// handleTransaction retorna uma transação minerada pelo ID (/api/transaction/{id}),
+// com a altura do bloco que a contém e o número de confirmações
+func (s *Server) handleTransaction(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	txID := strings.TrimPrefix(r.URL.Path, "/api/transaction/")
+	if txID == "" || strings.Contains(txID, "/") {
+		writeJSONError(w, http.StatusBadRequest, "invalid transaction id")
+		return
+	}
+
+	tx, blockHeight, found := s.node.FindTransaction(txID)
+	if !found {
+		writeJSONError(w, http.StatusNotFound, "transaction not found")
+		return
+	}
+
+	var confirmations uint64
+	if height := s.node.GetChainHeight(); height > blockHeight {
+		confirmations = height - blockHeight
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"transaction":   txToJSON(tx),
+		"block_height":  blockHeight,
+		"confirmations": confirmations,
+	})
+}
+
+// handleAddress encaminha as rotas de /api/address/{addr}/...
+func (s *Server) handleAddress(w http.ResponseWriter, r *http.Request) {
+	if parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/api/address/"), "/"); len(parts) == 2 && parts[1] == "balance" {
+		s.handleAddressBalance(w, r)
+		return
+	}
+	s.handleAddressHistory(w, r)
+}
+
+// handleAddressBalance retorna o saldo de um endereço após um bloco
+// (/api/address/{addr}/balance?height=; sem height, na ponta da chain)
+func (s *Server) handleAddressBalance(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/api/address/"), "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] != "balance" {
+		http.NotFound(w, r)
+		return
+	}
+	address := parts[0]
+
+	chainHeight := s.node.GetChainHeight()
+	height := chainHeight
+	if value := r.URL.Query().Get("height"); value != "" {
+		parsed, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			writeJSONError(w, http.StatusBadRequest, "invalid height")
+			return
+		}
+		height = parsed
+	}
+	if height > chainHeight {
+		writeJSONError(w, http.StatusBadRequest, fmt.Sprintf("height %d is above chain height %d", height, chainHeight))
+		return
+	}
 
-	blockData := map[string]interface{}{
-		"height":     block.GetHeight(),
-		"hash":       block.GetHash(),
-		"timestamp":  block.GetTimestamp(),
-		"tx_count":   block.GetTransactionCount(),
+	balance, err := s.node.GetBalanceAtHeight(address, height)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, err.Error())
+		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	_ = json.NewEncoder(w).Encode(blockData)
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"address": address,
+		"height":  height,
+		"balance": balance,
+	})
+}
+
+// handleAddressHistory retorna as transações enviadas e recebidas por um endereço
+// (/api/address/{addr}/history?limit=), da mais nova para a mais antiga
+func (s *Server) handleAddressHistory(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/api/address/"), "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] != "history" {
+		http.NotFound(w, r)
+		return
+	}
+	address := parts[0]
+
+	limit := DefaultAddressHistoryLimit
+	if value := r.URL.Query().Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 {
+			writeJSONError(w, http.StatusBadRequest, "invalid limit")
+			return
+		}
+		limit = parsed
+	}
+	if limit > MaxAddressHistoryLimit {
+		limit = MaxAddressHistoryLimit
+	}
+
+	txs, err := s.node.GetAddressHistory(address, limit)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	txList := make([]map[string]interface{}, 0, len(txs))
+	for _, tx := range txs {
+		entry := txToJSON(tx)
+		switch {
+		case tx.GetFrom() == address && tx.GetTo() == address:
+			entry["direction"] = "self"
+		case tx.GetFrom() == address:
+			entry["direction"] = "out"
+		default:
+			entry["direction"] = "in"
+		}
+		txList = append(txList, entry)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"address":      address,
+		"count":        len(txList),
+		"transactions": txList,
+	})
+}
+
+// writeJSONError escreve uma resposta de erro em JSON
+func writeJSONError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{
+		"error": message,
+	})
+}
+
+// handleGenesis retorna o bloco gênesis e os parâmetros da chain (para confirmar a rede)
+func (s *Server) handleGenesis(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	genesis := s.node.GetGenesis()
+
+	genesisData := map[string]interface{}{
+		"hash":      genesis.GetHash(),
+		"timestamp": genesis.GetTimestamp(),
+		"recipient": genesis.GetRecipient(),
+		"amount":    genesis.GetAmount(),
+		"chain_config": map[string]interface{}{
+			"block_time_ms":       genesis.GetBlockTime().Milliseconds(),
+			"max_block_size":      genesis.GetMaxBlockSize(),
+			"block_reward":        genesis.GetBlockReward(),
+			"min_validator_stake": genesis.GetMinValidatorStake(),
+			"min_fee":             genesis.GetMinFee(),
+		},
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(genesisData)
+}
+
+// handleValidators retorna os validadores ativos com seus nomes de exibição
+func (s *Server) handleValidators(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	validators := s.node.GetValidators()
+
+	// Ordena por stake (maior primeiro) e endereço para saída determinística
+	sort.Slice(validators, func(i, j int) bool {
+		if validators[i].GetStake() != validators[j].GetStake() {
+			return validators[i].GetStake() > validators[j].GetStake()
+		}
+		return validators[i].GetAddress() < validators[j].GetAddress()
+	})
+
+	validatorList := make([]map[string]interface{}, 0, len(validators))
+	for _, v := range validators {
+		validatorList = append(validatorList, map[string]interface{}{
+			"address": v.GetAddress(),
+			"name":    v.GetName(),
+			"stake":   v.GetStake(),
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"validators": validatorList,
+		"count":      len(validatorList),
+	})
+}
+
+// handleValidatorProjection retorna a projeção de recompensa de um validador
+// (GET /api/validators/{addr}/projection?window=24h, janela padrão de 1h)
+func (s *Server) handleValidatorProjection(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/api/validators/"), "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] != "projection" {
+		http.NotFound(w, r)
+		return
+	}
+	address := parts[0]
+
+	window := time.Hour
+	if value := r.URL.Query().Get("window"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed <= 0 {
+			http.Error(w, "Invalid window duration", http.StatusBadRequest)
+			return
+		}
+		window = parsed
+	}
+
+	projection := s.node.GetRewardProjection(address)
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"address":               projection.GetAddress(),
+		"stake":                 projection.GetStake(),
+		"total_stake":           projection.GetTotalStake(),
+		"stake_share":           projection.GetStakeShare(),
+		"selection_probability": projection.GetSelectionProbability(),
+		"block_time_ms":         projection.GetBlockTime().Milliseconds(),
+		"block_reward":          projection.GetBlockReward(),
+		"window_seconds":        window.Seconds(),
+		"expected_blocks":       projection.ExpectedBlocks(window),
+		"expected_reward":       projection.ExpectedReward(window),
+	})
+}
+
+// handleConsensusSchedule retorna a agenda de validadores (GET /api/consensus/schedule?from=&to=).
+// Sem "from", começa na próxima altura; sem "to", retorna só "from". Alturas produzidas trazem
+// quem produziu o bloco e a próxima altura traz o validador previsto; as seguintes ainda não
+// têm previsão (dependem do hash do próximo bloco).
+func (s *Server) handleConsensusSchedule(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	nextHeight := s.node.GetChainHeight() + 1
+
+	from := nextHeight
+	if value := query.Get("from"); value != "" {
+		parsed, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			writeJSONError(w, http.StatusBadRequest, "invalid from height")
+			return
+		}
+		from = parsed
+	}
+
+	to := from
+	if value := query.Get("to"); value != "" {
+		parsed, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			writeJSONError(w, http.StatusBadRequest, "invalid to height")
+			return
+		}
+		to = parsed
+	}
+
+	if to < from {
+		writeJSONError(w, http.StatusBadRequest, "from must be less than or equal to to")
+		return
+	}
+	if to-from+1 > MaxBlocksPerRequest {
+		to = from + MaxBlocksPerRequest - 1
+	}
+
+	schedule := s.node.GetValidatorSchedule(from, to)
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"from":        from,
+		"to":          to,
+		"next_height": nextHeight,
+		"count":       len(schedule),
+		"schedule":    schedule,
+	})
 }
 
 // handleStartMining inicia mineração
@@ -227,6 +827,86 @@ func (s *Server) handleStartMining(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// handleBlockTemplate retorna o template do próximo bloco para mineração externa
+func (s *Server) handleBlockTemplate(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	template, err := s.node.GetBlockTemplate()
+	if err != nil {
+		writeJSONError(w, http.StatusServiceUnavailable, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(template)
+}
+
+// handleSubmitBlock recebe um bloco montado a partir de GET /api/mining/template
+func (s *Server) handleSubmitBlock(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var block blockchain.Block
+	if err := json.NewDecoder(r.Body).Decode(&block); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := s.node.SubmitBlock(&block); err != nil {
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"status": "block accepted",
+		"height": block.Header.Height,
+		"hash":   block.Hash,
+	})
+}
+
+// handleForceSync dispara o sync com um peer conectado (POST /api/sync/from/{peerID})
+func (s *Server) handleForceSync(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	peerID := strings.TrimPrefix(r.URL.Path, "/api/sync/from/")
+	if peerID == "" || strings.Contains(peerID, "/") {
+		writeJSONError(w, http.StatusBadRequest, "invalid peer ID")
+		return
+	}
+
+	known := false
+	for _, peer := range s.node.GetPeers() {
+		if peer.GetID() == peerID {
+			known = true
+			break
+		}
+	}
+	if !known {
+		writeJSONError(w, http.StatusNotFound, fmt.Sprintf("peer %s not found", peerID))
+		return
+	}
+
+	if err := s.node.ForceSyncFrom(peerID); err != nil {
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]string{
+		"status":  "sync requested",
+		"peer_id": peerID,
+	})
+}
+
 // handleStopMining para mineração
 func (s *Server) handleStopMining(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
@@ -345,3 +1025,37 @@ func (s *Server) handleUnstakeTransaction(w http.ResponseWriter, r *http.Request
 		"tx_id":  tx.GetID(),
 	})
 }
+
+// handleRegisterNameTransaction cria uma transação de registro de nome de exibição
+func (s *Server) handleRegisterNameTransaction(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req struct {
+		Name string `json:"name"`
+		Fee  uint64 `json:"fee"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	tx, err := s.node.CreateRegisterNameTransaction(req.Name, req.Fee)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]string{
+		"status": "register name transaction created",
+		"tx_id":  tx.GetID(),
+	})
+}
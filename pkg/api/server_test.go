@@ -0,0 +1,870 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/krakovia/blockchain/pkg/blockchain"
+	"github.com/krakovia/blockchain/pkg/consensus"
+	"github.com/krakovia/blockchain/pkg/metrics"
+	"github.com/krakovia/blockchain/pkg/wallet"
+)
+
+// mockNode implementa apenas o necessário de NodeInterface para os handlers testados
+type mockNode struct {
+	NodeInterface
+	genesis    GenesisInfo
+	validators []blockchain.Validator
+	lastBlock  *blockchain.Block
+	blocks     []*blockchain.Block
+}
+
+func (m *mockNode) GetGenesis() GenesisInfo {
+	return m.genesis
+}
+
+func (m *mockNode) GetValidators() []ValidatorInfo {
+	validators := make([]ValidatorInfo, len(m.validators))
+	for i, v := range m.validators {
+		validators[i] = &ValidatorAdapter{validator: v}
+	}
+	return validators
+}
+
+func (m *mockNode) GetValidatorName(address string) string {
+	for _, v := range m.validators {
+		if v.Address == address {
+			return v.Name
+		}
+	}
+	return ""
+}
+
+func (m *mockNode) GetRewardProjection(address string) RewardProjectionInfo {
+	validators := blockchain.ValidatorList(m.validators)
+	projection := blockchain.RewardProjection{
+		Address:              address,
+		TotalStake:           validators.TotalStake(),
+		SelectionProbability: blockchain.GetSelectionProbability(address, validators),
+		BlockTime:            time.Second,
+		BlockReward:          50,
+	}
+	if v := validators.GetValidator(address); v != nil {
+		projection.Stake = v.Stake
+		projection.StakeShare = float64(v.Stake) / float64(projection.TotalStake)
+	}
+	return &RewardProjectionAdapter{projection: projection}
+}
+
+func (m *mockNode) GetBlockByHeight(height uint64) (BlockInfo, bool) {
+	for _, b := range m.blocks {
+		if b.Header.Height == height {
+			return &BlockAdapter{block: b}, true
+		}
+	}
+	return nil, false
+}
+
+func (m *mockNode) GetBlockByHash(hash string) (BlockInfo, bool) {
+	for _, b := range m.blocks {
+		if b.Hash == hash {
+			return &BlockAdapter{block: b}, true
+		}
+	}
+	return nil, false
+}
+
+func (m *mockNode) GetBlockRange(fromHeight, toHeight uint64) []BlockInfo {
+	blocks := make([]BlockInfo, 0)
+	for _, b := range m.blocks {
+		if b.Header.Height >= fromHeight && b.Header.Height <= toHeight {
+			blocks = append(blocks, &BlockAdapter{block: b})
+		}
+	}
+	return blocks
+}
+
+func (m *mockNode) GetValidatorSchedule(fromHeight, toHeight uint64) []blockchain.ScheduleEntry {
+	schedule := make([]blockchain.ScheduleEntry, 0)
+	last := m.blocks[len(m.blocks)-1]
+	for _, b := range m.blocks[1:] {
+		if b.Header.Height >= fromHeight && b.Header.Height <= toHeight {
+			schedule = append(schedule, blockchain.ScheduleEntry{
+				Height:       b.Header.Height,
+				PreviousHash: b.Header.PreviousHash,
+				Validator:    b.Header.ValidatorAddr,
+				Produced:     true,
+			})
+		}
+	}
+	if next := last.Header.Height + 1; next >= fromHeight && next <= toHeight {
+		schedule = append(schedule, blockchain.ScheduleEntry{
+			Height:       next,
+			PreviousHash: last.Hash,
+			Validator:    consensus.SelectValidator(next, last.Hash, blockchain.ValidatorList(m.validators).Stakes()),
+		})
+	}
+	return schedule
+}
+
+func (m *mockNode) FindTransaction(txID string) (TxInfo, uint64, bool) {
+	for _, b := range m.blocks {
+		if tx := b.Transactions.FindByID(txID); tx != nil {
+			return &TxAdapter{tx: tx}, b.Header.Height, true
+		}
+	}
+	return nil, 0, false
+}
+
+func (m *mockNode) GetAddressHistory(address string, limit int) ([]TxInfo, error) {
+	history := make([]TxInfo, 0)
+	for i := len(m.blocks) - 1; i >= 0 && len(history) < limit; i-- {
+		for _, tx := range m.blocks[i].Transactions {
+			if tx.From == address || tx.To == address {
+				history = append(history, &TxAdapter{tx: tx})
+			}
+		}
+	}
+	return history, nil
+}
+
+func (m *mockNode) GetBalanceAtHeight(address string, height uint64) (uint64, error) {
+	var balance uint64
+	for _, b := range m.blocks {
+		if b.Header.Height > height {
+			break
+		}
+		for _, tx := range b.Transactions {
+			if tx.To == address {
+				balance += tx.Amount
+			}
+		}
+	}
+	return balance, nil
+}
+
+func (m *mockNode) GetChainHeight() uint64 {
+	if len(m.blocks) == 0 {
+		return 0
+	}
+	return m.blocks[len(m.blocks)-1].Header.Height
+}
+
+func (m *mockNode) GetLastBlock() BlockInfo {
+	return &BlockAdapter{block: m.lastBlock}
+}
+
+func TestHandleGenesis(t *testing.T) {
+	w, _ := wallet.NewWallet()
+	genesis := blockchain.GenesisBlock(blockchain.NewCoinbaseTransaction(w.GetAddress(), 1000000, 0))
+	config := blockchain.DefaultChainConfig()
+
+	server := NewServer(&mockNode{genesis: NewGenesisAdapter(genesis, config)}, &Config{Enabled: true})
+
+	rec := httptest.NewRecorder()
+	server.handleGenesis(rec, httptest.NewRequest(http.MethodGet, "/api/genesis", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", rec.Code)
+	}
+
+	var resp struct {
+		Hash        string `json:"hash"`
+		Timestamp   int64  `json:"timestamp"`
+		Recipient   string `json:"recipient"`
+		Amount      uint64 `json:"amount"`
+		ChainConfig struct {
+			MaxBlockSize      int    `json:"max_block_size"`
+			MinValidatorStake uint64 `json:"min_validator_stake"`
+			MinFee            uint64 `json:"min_fee"`
+		} `json:"chain_config"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	if resp.Hash != genesis.Hash {
+		t.Errorf("Expected genesis hash %s, got %s", genesis.Hash, resp.Hash)
+	}
+	if resp.Timestamp != genesis.Header.Timestamp {
+		t.Errorf("Expected timestamp %d, got %d", genesis.Header.Timestamp, resp.Timestamp)
+	}
+	if resp.Recipient != w.GetAddress() || resp.Amount != 1000000 {
+		t.Errorf("Unexpected recipient/amount: %s/%d", resp.Recipient, resp.Amount)
+	}
+	if resp.ChainConfig.MaxBlockSize != config.Consensus.MaxBlockTxs || resp.ChainConfig.MinValidatorStake != config.MinValidatorStake || resp.ChainConfig.MinFee != config.MinFee {
+		t.Errorf("Chain config mismatch: %+v", resp.ChainConfig)
+	}
+}
+
+func TestHandleGenesisMethodNotAllowed(t *testing.T) {
+	server := NewServer(&mockNode{}, &Config{Enabled: true})
+
+	rec := httptest.NewRecorder()
+	server.handleGenesis(rec, httptest.NewRequest(http.MethodPost, "/api/genesis", nil))
+
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected status 405, got %d", rec.Code)
+	}
+}
+
+func TestHandleValidators(t *testing.T) {
+	node := &mockNode{validators: []blockchain.Validator{
+		{Address: "addr-small", Stake: 1000},
+		{Address: "addr-big", Stake: 5000, Name: "Big Pool"},
+	}}
+	server := NewServer(node, &Config{Enabled: true})
+
+	rec := httptest.NewRecorder()
+	server.handleValidators(rec, httptest.NewRequest(http.MethodGet, "/api/validators", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", rec.Code)
+	}
+
+	var resp struct {
+		Count      int `json:"count"`
+		Validators []struct {
+			Address string `json:"address"`
+			Name    string `json:"name"`
+			Stake   uint64 `json:"stake"`
+		} `json:"validators"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	if resp.Count != 2 || len(resp.Validators) != 2 {
+		t.Fatalf("Expected 2 validators, got %d", resp.Count)
+	}
+	// Ordenado por stake (maior primeiro)
+	if resp.Validators[0].Address != "addr-big" || resp.Validators[0].Name != "Big Pool" {
+		t.Errorf("Expected named validator first, got %+v", resp.Validators[0])
+	}
+	if resp.Validators[1].Name != "" {
+		t.Errorf("Unnamed validator should have empty name, got %q", resp.Validators[1].Name)
+	}
+}
+
+func TestHandleLastBlockValidatorName(t *testing.T) {
+	w, _ := wallet.NewWallet()
+	block := blockchain.NewBlock(1, "prev", blockchain.TransactionSlice{blockchain.NewCoinbaseTransaction(w.GetAddress(), 50, 1)}, w.GetAddress())
+
+	node := &mockNode{
+		lastBlock:  block,
+		validators: []blockchain.Validator{{Address: w.GetAddress(), Stake: 1000, Name: "Krakow Pool"}},
+	}
+	server := NewServer(node, &Config{Enabled: true})
+
+	rec := httptest.NewRecorder()
+	server.handleLastBlock(rec, httptest.NewRequest(http.MethodGet, "/api/lastblock", nil))
+
+	var resp struct {
+		Validator     string `json:"validator"`
+		ValidatorName string `json:"validator_name"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	if resp.Validator != w.GetAddress() || resp.ValidatorName != "Krakow Pool" {
+		t.Errorf("Unexpected validator info: %+v", resp)
+	}
+}
+
+func TestHandleValidatorProjection(t *testing.T) {
+	node := &mockNode{validators: []blockchain.Validator{
+		{Address: "addr-a", Stake: 5000},
+		{Address: "addr-b", Stake: 5000},
+	}}
+	server := NewServer(node, &Config{Enabled: true})
+
+	rec := httptest.NewRecorder()
+	server.handleValidatorProjection(rec, httptest.NewRequest(http.MethodGet, "/api/validators/addr-a/projection?window=10m", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", rec.Code)
+	}
+
+	var resp struct {
+		Address              string  `json:"address"`
+		StakeShare           float64 `json:"stake_share"`
+		SelectionProbability float64 `json:"selection_probability"`
+		WindowSeconds        float64 `json:"window_seconds"`
+		ExpectedBlocks       float64 `json:"expected_blocks"`
+		ExpectedReward       float64 `json:"expected_reward"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	// Metade do stake com blocos de 1s: 300 dos 600 blocos em 10 minutos
+	if resp.Address != "addr-a" || resp.StakeShare != 0.5 || resp.WindowSeconds != 600 {
+		t.Errorf("Unexpected projection: %+v", resp)
+	}
+	if resp.ExpectedBlocks != 300 || resp.ExpectedReward != 15000 {
+		t.Errorf("Expected 300 blocks and 15000 reward, got %.2f and %.2f", resp.ExpectedBlocks, resp.ExpectedReward)
+	}
+}
+
+func TestHandleValidatorProjectionErrors(t *testing.T) {
+	server := NewServer(&mockNode{}, &Config{Enabled: true})
+
+	tests := []struct {
+		method string
+		path   string
+		code   int
+	}{
+		{http.MethodGet, "/api/validators/addr-a", http.StatusNotFound},
+		{http.MethodGet, "/api/validators/addr-a/other", http.StatusNotFound},
+		{http.MethodGet, "/api/validators//projection", http.StatusNotFound},
+		{http.MethodGet, "/api/validators/addr-a/projection?window=abc", http.StatusBadRequest},
+		{http.MethodGet, "/api/validators/addr-a/projection?window=-1h", http.StatusBadRequest},
+		{http.MethodPost, "/api/validators/addr-a/projection", http.StatusMethodNotAllowed},
+	}
+
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		server.handleValidatorProjection(rec, httptest.NewRequest(tt.method, tt.path, nil))
+		if rec.Code != tt.code {
+			t.Errorf("%s %s: expected status %d, got %d", tt.method, tt.path, tt.code, rec.Code)
+		}
+	}
+}
+
+// newExplorerNode cria um mockNode com uma chain de count blocos (gênesis incluso)
+func newExplorerNode(t *testing.T, count int) *mockNode {
+	t.Helper()
+	w, _ := wallet.NewWallet()
+
+	genesis := blockchain.GenesisBlock(blockchain.NewCoinbaseTransaction(w.GetAddress(), 1000000, 0))
+	blocks := []*blockchain.Block{genesis}
+	for h := 1; h < count; h++ {
+		prev := blocks[len(blocks)-1]
+		txs := blockchain.TransactionSlice{blockchain.NewCoinbaseTransaction(w.GetAddress(), 50, uint64(h))}
+		block := blockchain.NewBlock(uint64(h), prev.Hash, txs, w.GetAddress())
+		hash, err := block.CalculateHash()
+		if err != nil {
+			t.Fatalf("Failed to hash block: %v", err)
+		}
+		block.Hash = hash
+		blocks = append(blocks, block)
+	}
+
+	return &mockNode{
+		blocks:     blocks,
+		validators: []blockchain.Validator{{Address: w.GetAddress(), Stake: 1000, Name: "Explorer Pool"}},
+	}
+}
+
+func TestHandleBlockByHeightAndHash(t *testing.T) {
+	node := newExplorerNode(t, 3)
+	server := NewServer(node, &Config{Enabled: true})
+	expected := node.blocks[2]
+
+	for _, path := range []string{"/api/block/2", "/api/block/hash/" + expected.Hash} {
+		rec := httptest.NewRecorder()
+		server.handleBlock(rec, httptest.NewRequest(http.MethodGet, path, nil))
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: expected status 200, got %d", path, rec.Code)
+		}
+
+		var resp struct {
+			Hash          string `json:"hash"`
+			Height        uint64 `json:"height"`
+			Validator     string `json:"validator"`
+			ValidatorName string `json:"validator_name"`
+			Header        struct {
+				PreviousHash string `json:"previous_hash"`
+				MerkleRoot   string `json:"merkle_root"`
+			} `json:"header"`
+			Transactions []struct {
+				ID     string `json:"id"`
+				To     string `json:"to"`
+				Amount uint64 `json:"amount"`
+			} `json:"transactions"`
+		}
+		if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+
+		if resp.Hash != expected.Hash || resp.Height != 2 || resp.Header.PreviousHash != node.blocks[1].Hash {
+			t.Errorf("%s: unexpected block %+v", path, resp)
+		}
+		if resp.Header.MerkleRoot == "" || resp.Header.MerkleRoot != expected.Header.MerkleRoot {
+			t.Errorf("%s: header incomplete: %+v", path, resp.Header)
+		}
+		if resp.ValidatorName != "Explorer Pool" || resp.Validator != expected.Header.ValidatorAddr {
+			t.Errorf("%s: unexpected validator %s (%s)", path, resp.Validator, resp.ValidatorName)
+		}
+		if len(resp.Transactions) != 1 || resp.Transactions[0].ID != expected.Transactions[0].ID || resp.Transactions[0].Amount != 50 {
+			t.Errorf("%s: unexpected transactions %+v", path, resp.Transactions)
+		}
+	}
+}
+
+func TestHandleBlockErrors(t *testing.T) {
+	server := NewServer(newExplorerNode(t, 2), &Config{Enabled: true})
+
+	tests := []struct {
+		path string
+		code int
+	}{
+		{"/api/block/5", http.StatusNotFound},
+		{"/api/block/hash/deadbeef", http.StatusNotFound},
+		{"/api/block/abc", http.StatusBadRequest},
+		{"/api/block/hash/", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		server.handleBlock(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+		if rec.Code != tt.code {
+			t.Errorf("%s: expected status %d, got %d", tt.path, tt.code, rec.Code)
+		}
+
+		var resp map[string]string
+		if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil || resp["error"] == "" {
+			t.Errorf("%s: expected JSON error body, got %v", tt.path, err)
+		}
+	}
+}
+
+func TestHandleBlocksRange(t *testing.T) {
+	node := newExplorerNode(t, 150)
+	server := NewServer(node, &Config{Enabled: true})
+
+	type rangeResp struct {
+		From   uint64 `json:"from"`
+		To     uint64 `json:"to"`
+		Count  int    `json:"count"`
+		Blocks []struct {
+			Height uint64 `json:"height"`
+		} `json:"blocks"`
+	}
+
+	get := func(path string) (int, rangeResp) {
+		rec := httptest.NewRecorder()
+		server.handleBlocks(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		var resp rangeResp
+		_ = json.NewDecoder(rec.Body).Decode(&resp)
+		return rec.Code, resp
+	}
+
+	code, resp := get("/api/blocks?from=10&to=14")
+	if code != http.StatusOK || resp.Count != 5 || resp.Blocks[0].Height != 10 || resp.Blocks[4].Height != 14 {
+		t.Errorf("Unexpected range response: %d %+v", code, resp)
+	}
+
+	// Intervalos acima do limite são truncados em MaxBlocksPerRequest
+	_, resp = get("/api/blocks?from=0&to=149")
+	if resp.Count != MaxBlocksPerRequest || resp.To != MaxBlocksPerRequest-1 {
+		t.Errorf("Expected range capped to %d blocks, got %d (to=%d)", MaxBlocksPerRequest, resp.Count, resp.To)
+	}
+
+	// Sem "to", retorna até o limite a partir de "from"
+	_, resp = get("/api/blocks?from=120")
+	if resp.Count != 30 || resp.From != 120 {
+		t.Errorf("Expected 30 blocks from 120, got %d", resp.Count)
+	}
+
+	for _, path := range []string{"/api/blocks?from=10&to=5", "/api/blocks?from=x", "/api/blocks?to=-1"} {
+		if code, _ := get(path); code != http.StatusBadRequest {
+			t.Errorf("%s: expected status 400, got %d", path, code)
+		}
+	}
+}
+
+func TestHandleConsensusSchedule(t *testing.T) {
+	node := newExplorerNode(t, 150)
+	server := NewServer(node, &Config{Enabled: true})
+	validator := node.validators[0].Address
+
+	type scheduleResp struct {
+		From       uint64                     `json:"from"`
+		To         uint64                     `json:"to"`
+		NextHeight uint64                     `json:"next_height"`
+		Count      int                        `json:"count"`
+		Schedule   []blockchain.ScheduleEntry `json:"schedule"`
+	}
+
+	get := func(path string) (int, scheduleResp) {
+		rec := httptest.NewRecorder()
+		server.handleConsensusSchedule(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		var resp scheduleResp
+		_ = json.NewDecoder(rec.Body).Decode(&resp)
+		return rec.Code, resp
+	}
+
+	// Sem parâmetros, retorna só o validador previsto para a próxima altura
+	code, resp := get("/api/consensus/schedule")
+	if code != http.StatusOK || resp.NextHeight != 150 || resp.Count != 1 {
+		t.Fatalf("Unexpected default schedule: %d %+v", code, resp)
+	}
+	if next := resp.Schedule[0]; next.Height != 150 || next.Produced || next.Validator != validator || next.PreviousHash != node.blocks[149].Hash {
+		t.Errorf("Unexpected next validator entry: %+v", next)
+	}
+
+	// Alturas produzidas trazem o validador do bloco
+	_, resp = get("/api/consensus/schedule?from=10&to=12")
+	if resp.Count != 3 || resp.Schedule[0].Height != 10 || !resp.Schedule[0].Produced || resp.Schedule[0].Validator != validator {
+		t.Errorf("Unexpected produced schedule: %+v", resp)
+	}
+
+	// Intervalos acima do limite são truncados em MaxBlocksPerRequest
+	_, resp = get("/api/consensus/schedule?from=1&to=500")
+	if resp.To != MaxBlocksPerRequest || resp.Count != MaxBlocksPerRequest {
+		t.Errorf("Expected schedule capped to %d heights, got %d (to=%d)", MaxBlocksPerRequest, resp.Count, resp.To)
+	}
+
+	for _, path := range []string{"/api/consensus/schedule?from=10&to=5", "/api/consensus/schedule?from=x", "/api/consensus/schedule?to=-1"} {
+		if code, _ := get(path); code != http.StatusBadRequest {
+			t.Errorf("%s: expected status 400, got %d", path, code)
+		}
+	}
+}
+
+func TestHandleTransactionLookup(t *testing.T) {
+	node := newExplorerNode(t, 6)
+	server := NewServer(node, &Config{Enabled: true})
+	expected := node.blocks[2].Transactions[0]
+
+	rec := httptest.NewRecorder()
+	server.handleTransaction(rec, httptest.NewRequest(http.MethodGet, "/api/transaction/"+expected.ID, nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", rec.Code)
+	}
+
+	var resp struct {
+		Transaction struct {
+			ID     string `json:"id"`
+			To     string `json:"to"`
+			Amount uint64 `json:"amount"`
+		} `json:"transaction"`
+		BlockHeight   uint64 `json:"block_height"`
+		Confirmations uint64 `json:"confirmations"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	if resp.Transaction.ID != expected.ID || resp.Transaction.To != expected.To || resp.Transaction.Amount != 50 {
+		t.Errorf("Unexpected transaction %+v", resp.Transaction)
+	}
+	if resp.BlockHeight != 2 {
+		t.Errorf("Expected block height 2, got %d", resp.BlockHeight)
+	}
+	if resp.Confirmations != 3 {
+		t.Errorf("Expected 3 confirmations, got %d", resp.Confirmations)
+	}
+
+	for path, code := range map[string]int{
+		"/api/transaction/unknown": http.StatusNotFound,
+		"/api/transaction/":        http.StatusBadRequest,
+	} {
+		rec := httptest.NewRecorder()
+		server.handleTransaction(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != code {
+			t.Errorf("%s: expected status %d, got %d", path, code, rec.Code)
+		}
+	}
+}
+
+func TestHandleAddressHistory(t *testing.T) {
+	node := newExplorerNode(t, 5)
+	server := NewServer(node, &Config{Enabled: true})
+	address := node.validators[0].Address
+
+	get := func(path string) (int, map[string]interface{}) {
+		rec := httptest.NewRecorder()
+		server.handleAddressHistory(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		var resp map[string]interface{}
+		_ = json.NewDecoder(rec.Body).Decode(&resp)
+		return rec.Code, resp
+	}
+
+	code, resp := get("/api/address/" + address + "/history?limit=2")
+	if code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", code)
+	}
+	txs, _ := resp["transactions"].([]interface{})
+	if resp["count"] != float64(2) || len(txs) != 2 {
+		t.Fatalf("Expected 2 transactions, got %v", resp["count"])
+	}
+
+	// Mais nova primeiro: coinbase do bloco 4, depois do bloco 3
+	newest := txs[0].(map[string]interface{})
+	if newest["id"] != node.blocks[4].Transactions[0].ID || newest["direction"] != "in" {
+		t.Errorf("Unexpected newest entry %+v", newest)
+	}
+	if txs[1].(map[string]interface{})["id"] != node.blocks[3].Transactions[0].ID {
+		t.Errorf("Unexpected second entry %+v", txs[1])
+	}
+
+	for path, expected := range map[string]int{
+		"/api/address/" + address + "/history?limit=0": http.StatusBadRequest,
+		"/api/address/" + address + "/history?limit=x": http.StatusBadRequest,
+		"/api/address/" + address:                      http.StatusNotFound,
+		"/api/address//history":                        http.StatusNotFound,
+	} {
+		if code, _ := get(path); code != expected {
+			t.Errorf("%s: expected status %d, got %d", path, expected, code)
+		}
+	}
+}
+
+func TestHandleAddressBalance(t *testing.T) {
+	node := newExplorerNode(t, 5)
+	server := NewServer(node, &Config{Enabled: true})
+	address := node.validators[0].Address
+
+	get := func(path string) (int, map[string]interface{}) {
+		rec := httptest.NewRecorder()
+		server.handleAddress(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		var resp map[string]interface{}
+		_ = json.NewDecoder(rec.Body).Decode(&resp)
+		return rec.Code, resp
+	}
+
+	// Gênesis (1000000) + 2 coinbases de 50
+	code, resp := get("/api/address/" + address + "/balance?height=2")
+	if code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", code)
+	}
+	if resp["address"] != address || resp["height"] != float64(2) || resp["balance"] != float64(1000100) {
+		t.Errorf("Unexpected balance response %+v", resp)
+	}
+
+	// Sem height, o saldo é o da ponta
+	if _, resp := get("/api/address/" + address + "/balance"); resp["height"] != float64(4) || resp["balance"] != float64(1000200) {
+		t.Errorf("Expected tip balance, got %+v", resp)
+	}
+
+	// O roteador continua atendendo o histórico
+	if code, _ := get("/api/address/" + address + "/history"); code != http.StatusOK {
+		t.Errorf("History should still be served, got status %d", code)
+	}
+
+	for path, expected := range map[string]int{
+		"/api/address/" + address + "/balance?height=5": http.StatusBadRequest,
+		"/api/address/" + address + "/balance?height=x": http.StatusBadRequest,
+		"/api/address//balance":                         http.StatusNotFound,
+	} {
+		if code, _ := get(path); code != expected {
+			t.Errorf("%s: expected status %d, got %d", path, expected, code)
+		}
+	}
+}
+
+func TestMetricsEndpoint(t *testing.T) {
+	registry := metrics.NewRegistry()
+	registry.NewGaugeFunc("krakovia_chain_height", "Altura", func() float64 { return 7 })
+	registry.NewCounter("krakovia_blocks_mined_total", "Blocos minerados").Inc()
+
+	get := func(server *Server) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+		req.SetBasicAuth("admin", "secret")
+		server.routes().ServeHTTP(rec, req)
+		return rec
+	}
+
+	server := NewServer(&mockNode{}, &Config{Enabled: true, Username: "admin", Password: "secret", MetricsEnabled: true, BasicAuth: true})
+	server.SetMetricsHandler(registry.Handler())
+
+	rec := get(server)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", rec.Code)
+	}
+	body := rec.Body.String()
+	for _, expected := range []string{"krakovia_chain_height 7", "krakovia_blocks_mined_total 1"} {
+		if !strings.Contains(body, expected) {
+			t.Errorf("Expected %q in metrics output, got:\n%s", expected, body)
+		}
+	}
+
+	// Sem MetricsEnabled o endpoint não é registrado
+	disabled := NewServer(&mockNode{}, &Config{Enabled: true, Username: "admin", Password: "secret", BasicAuth: true})
+	disabled.SetMetricsHandler(registry.Handler())
+	if strings.Contains(get(disabled).Body.String(), "krakovia_chain_height") {
+		t.Error("Metrics should not be served when disabled")
+	}
+}
+
+func TestLoginIssuesBearerToken(t *testing.T) {
+	server := NewServer(&mockNode{}, &Config{Enabled: true, Username: "admin", Password: "secret", MetricsEnabled: true})
+	server.SetMetricsHandler(metrics.NewRegistry().Handler())
+	handler := server.routes()
+
+	login := func(body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/login", strings.NewReader(body)))
+		return rec
+	}
+	get := func(configure func(req *http.Request)) int {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+		configure(req)
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	if rec := login(`{"username":"admin","password":"wrong"}`); rec.Code != http.StatusUnauthorized {
+		t.Errorf("Wrong password should be rejected with 401, got %d", rec.Code)
+	}
+
+	rec := login(`{"username":"admin","password":"secret"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200 from login, got %d", rec.Code)
+	}
+	var resp LoginResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("Failed to decode login response: %v", err)
+	}
+	if resp.Token == "" || resp.TokenType != "Bearer" {
+		t.Fatalf("Expected a bearer token, got %+v", resp)
+	}
+	if ttl := resp.ExpiresAt - time.Now().Unix(); ttl < int64(DefaultTokenTTL.Seconds())-5 || ttl > int64(DefaultTokenTTL.Seconds()) {
+		t.Errorf("Expected token to expire in %v, got %ds", DefaultTokenTTL, ttl)
+	}
+
+	if code := get(func(req *http.Request) { req.Header.Set("Authorization", "Bearer "+resp.Token) }); code != http.StatusOK {
+		t.Errorf("Valid token should be accepted, got %d", code)
+	}
+	if code := get(func(req *http.Request) {}); code != http.StatusUnauthorized {
+		t.Errorf("Request without credentials should be rejected, got %d", code)
+	}
+
+	// Basic só é aceito com BasicAuth
+	if code := get(func(req *http.Request) { req.SetBasicAuth("admin", "secret") }); code != http.StatusUnauthorized {
+		t.Errorf("Basic auth should be rejected when disabled, got %d", code)
+	}
+	server.config.BasicAuth = true
+	if code := get(func(req *http.Request) { req.SetBasicAuth("admin", "secret") }); code != http.StatusOK {
+		t.Errorf("Basic auth should be accepted when enabled, got %d", code)
+	}
+	if code := get(func(req *http.Request) { req.Header.Set("Authorization", "Bearer "+resp.Token) }); code != http.StatusOK {
+		t.Errorf("Token should still be accepted with Basic auth enabled, got %d", code)
+	}
+}
+
+func TestTokenExpiryAndTampering(t *testing.T) {
+	server := NewServer(&mockNode{}, &Config{Enabled: true, Username: "admin", Password: "secret"})
+
+	token, err := server.IssueToken("admin", time.Now().Add(time.Minute))
+	if err != nil {
+		t.Fatalf("Failed to issue token: %v", err)
+	}
+	if user, err := server.VerifyToken(token); err != nil || user != "admin" {
+		t.Fatalf("Fresh token should verify, got %q (%v)", user, err)
+	}
+
+	expired, _ := server.IssueToken("admin", time.Now().Add(-time.Second))
+	if _, err := server.VerifyToken(expired); err == nil {
+		t.Error("Expired token should be rejected")
+	}
+
+	// Claims trocadas (expiração estendida) sem a assinatura correspondente
+	payload, signature, _ := strings.Cut(token, ".")
+	extended, _ := server.IssueToken("admin", time.Now().Add(24*time.Hour))
+	extendedPayload, _, _ := strings.Cut(extended, ".")
+	for name, tampered := range map[string]string{
+		"payload swapped":     extendedPayload + "." + signature,
+		"signature swapped":   payload + "." + strings.Split(expired, ".")[1],
+		"signature truncated": payload + "." + signature[:len(signature)-2],
+		"missing signature":   payload,
+		"garbage":             "not-a-token",
+	} {
+		if _, err := server.VerifyToken(tampered); err == nil {
+			t.Errorf("%s: tampered token should be rejected", name)
+		}
+	}
+
+	// Token assinado por outro servidor (outra chave) não vale
+	other := NewServer(&mockNode{}, &Config{Enabled: true, Username: "admin", Password: "secret"})
+	if _, err := other.VerifyToken(token); err == nil {
+		t.Error("Token signed with another secret should be rejected")
+	}
+
+	// Mesma token_secret: tokens sobrevivem a um reinício
+	first := NewServer(&mockNode{}, &Config{Enabled: true, Username: "admin", Password: "secret", TokenSecret: "shared"})
+	second := NewServer(&mockNode{}, &Config{Enabled: true, Username: "admin", Password: "secret", TokenSecret: "shared"})
+	shared, _ := first.IssueToken("admin", time.Now().Add(time.Minute))
+	if _, err := second.VerifyToken(shared); err != nil {
+		t.Errorf("Token should verify with the same secret: %v", err)
+	}
+
+	// O token do WebSocket pode ir no parâmetro token
+	rec := httptest.NewRecorder()
+	server.routes().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/ws?token=forged."+signature, nil))
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("Forged token in query should be rejected, got %d", rec.Code)
+	}
+}
+
+// syncMockNode registra os pedidos de sync forçado
+type syncMockNode struct {
+	mockNode
+	peers  []PeerInfo
+	synced []string
+}
+
+func (m *syncMockNode) GetPeers() []PeerInfo {
+	return m.peers
+}
+
+func (m *syncMockNode) ForceSyncFrom(peerID string) error {
+	m.synced = append(m.synced, peerID)
+	return nil
+}
+
+type mockPeer struct {
+	id string
+}
+
+func (p mockPeer) GetID() string            { return p.id }
+func (p mockPeer) GetBytesSent() uint64     { return 0 }
+func (p mockPeer) GetBytesReceived() uint64 { return 0 }
+
+func TestHandleForceSync(t *testing.T) {
+	node := &syncMockNode{peers: []PeerInfo{mockPeer{id: "peer-a"}}}
+	server := NewServer(node, &Config{Enabled: true, Username: "admin", Password: "secret", BasicAuth: true})
+	handler := server.routes()
+
+	for _, tt := range []struct {
+		method string
+		path   string
+		status int
+	}{
+		{http.MethodPost, "/api/sync/from/peer-b", http.StatusNotFound},
+		{http.MethodPost, "/api/sync/from/", http.StatusBadRequest},
+		{http.MethodGet, "/api/sync/from/peer-a", http.StatusMethodNotAllowed},
+		{http.MethodPost, "/api/sync/from/peer-a", http.StatusOK},
+	} {
+		req := httptest.NewRequest(tt.method, tt.path, nil)
+		req.SetBasicAuth("admin", "secret")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != tt.status {
+			t.Errorf("%s %s: expected %d, got %d", tt.method, tt.path, tt.status, rec.Code)
+		}
+	}
+
+	if len(node.synced) != 1 || node.synced[0] != "peer-a" {
+		t.Errorf("Expected a single sync from peer-a, got %v", node.synced)
+	}
+
+	// Sem credenciais a rota é recusada
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/sync/from/peer-a", nil))
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("Unauthenticated force sync should be rejected, got %d", rec.Code)
+	}
+}
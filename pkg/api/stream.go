@@ -0,0 +1,176 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/krakovia/blockchain/pkg/blockchain"
+)
+
+// Tipos de mensagem enviados em /api/stream/raw
+const (
+	RawEventBlock       = "block"
+	RawEventTransaction = "transaction"
+)
+
+// rawClientBuffer mensagens pendentes por cliente do stream bruto (maior que o de /api/ws para
+// comportar os blocos ao vivo que chegam enquanto o backfill é enviado)
+const rawClientBuffer = 1024
+
+// RawEvent mensagem de /api/stream/raw: o bloco ou a transação serializados por completo
+type RawEvent struct {
+	Type     string          `json:"type"`
+	Height   uint64          `json:"height,omitempty"`   // Altura do bloco (só em eventos block)
+	Backfill bool            `json:"backfill,omitempty"` // Bloco histórico enviado por causa de fromHeight
+	Data     json.RawMessage `json:"data"`               // Block.Serialize / Transaction.Serialize
+}
+
+// publishRaw envia um evento aos clientes de /api/stream/raw (não bloqueia)
+func (s *Server) publishRaw(eventType string, height uint64, serialize func() ([]byte, error)) {
+	if s.rawEvents.clientCount() == 0 {
+		return
+	}
+
+	data, err := serialize()
+	if err != nil {
+		fmt.Printf("Failed to serialize raw %s: %v\n", eventType, err)
+		return
+	}
+	payload, err := json.Marshal(RawEvent{Type: eventType, Height: height, Data: data})
+	if err != nil {
+		fmt.Printf("Failed to marshal raw %s event: %v\n", eventType, err)
+		return
+	}
+	s.rawEvents.broadcast(payload)
+}
+
+// handleRawStream abre o stream bruto de blocos e transações (GET /api/stream/raw). Com
+// fromHeight, os blocos da chain a partir dessa altura são enviados antes dos eventos ao vivo.
+func (s *Server) handleRawStream(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	backfill := false
+	var fromHeight uint64
+	if value := r.URL.Query().Get("fromHeight"); value != "" {
+		parsed, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			writeJSONError(w, http.StatusBadRequest, "invalid fromHeight")
+			return
+		}
+		backfill = true
+		fromHeight = parsed
+	}
+
+	conn, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// Upgrade já respondeu ao cliente com o erro
+		return
+	}
+	defer conn.Close()
+
+	// Inscreve antes do backfill para não perder blocos adicionados durante ele
+	events := s.rawEvents.subscribeBuffered(rawClientBuffer)
+	defer s.rawEvents.unsubscribe(events)
+
+	var lastSent uint64
+	sentAny := false
+	if backfill {
+		lastSent, sentAny, err = s.sendBackfill(conn, fromHeight)
+		if err != nil {
+			return
+		}
+	}
+
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	ping := time.NewTicker(eventPingInterval)
+	defer ping.Stop()
+
+	for {
+		select {
+		case data, ok := <-events:
+			if !ok {
+				_ = conn.WriteControl(websocket.CloseMessage,
+					websocket.FormatCloseMessage(websocket.CloseGoingAway, ""), time.Now().Add(eventWriteTimeout))
+				return
+			}
+			// Blocos que o backfill já enviou não são repetidos
+			if sentAny {
+				var event RawEvent
+				if json.Unmarshal(data, &event) == nil && event.Type == RawEventBlock && event.Height <= lastSent {
+					continue
+				}
+			}
+			_ = conn.SetWriteDeadline(time.Now().Add(eventWriteTimeout))
+			if err := conn.WriteMessage(websocket.TextMessage, data); err != nil {
+				return
+			}
+		case <-ping.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(eventWriteTimeout)); err != nil {
+				return
+			}
+		case <-closed:
+			return
+		}
+	}
+}
+
+// sendBackfill envia os blocos da chain a partir de fromHeight, em lotes de MaxBlocksPerRequest,
+// até alcançar a ponta. Retorna a altura do último bloco enviado.
+func (s *Server) sendBackfill(conn *websocket.Conn, fromHeight uint64) (uint64, bool, error) {
+	var lastSent uint64
+	sentAny := false
+
+	for from := fromHeight; from <= s.node.GetChainHeight(); {
+		to := min(from+MaxBlocksPerRequest-1, s.node.GetChainHeight())
+		blocks := s.node.GetRawBlockRange(from, to)
+		if len(blocks) == 0 {
+			break
+		}
+
+		for _, block := range blocks {
+			data, err := block.Serialize()
+			if err != nil {
+				return lastSent, sentAny, fmt.Errorf("failed to serialize block %d: %w", block.Header.Height, err)
+			}
+			payload, err := json.Marshal(RawEvent{Type: RawEventBlock, Height: block.Header.Height, Backfill: true, Data: data})
+			if err != nil {
+				return lastSent, sentAny, fmt.Errorf("failed to marshal block %d: %w", block.Header.Height, err)
+			}
+			_ = conn.SetWriteDeadline(time.Now().Add(eventWriteTimeout))
+			if err := conn.WriteMessage(websocket.TextMessage, payload); err != nil {
+				return lastSent, sentAny, err
+			}
+			lastSent = block.Header.Height
+			sentAny = true
+		}
+		from = lastSent + 1
+	}
+
+	return lastSent, sentAny, nil
+}
+
+// PublishRawBlock publica um bloco completo aos clientes de /api/stream/raw
+func (s *Server) PublishRawBlock(block *blockchain.Block) {
+	s.publishRaw(RawEventBlock, block.Header.Height, block.Serialize)
+}
+
+// PublishRawTransaction publica uma transação completa aos clientes de /api/stream/raw
+func (s *Server) PublishRawTransaction(tx *blockchain.Transaction) {
+	s.publishRaw(RawEventTransaction, 0, tx.Serialize)
+}
@@ -381,9 +381,9 @@ const htmlUI = `<!DOCTYPE html>
                 options.headers = {};
             }
 
-            // Adicionar autenticação básica se disponível
+            // Adicionar token de /api/login se disponível
             if (authToken) {
-                options.headers['Authorization'] = 'Basic ' + authToken;
+                options.headers['Authorization'] = 'Bearer ' + authToken;
             }
 
             const response = await fetch(API_BASE + endpoint, options);
@@ -397,13 +397,23 @@ const htmlUI = `<!DOCTYPE html>
             return response;
         }
 
-        // Solicitar autenticação
-        function requestAuth() {
+        // Solicitar autenticação e trocar as credenciais por um token
+        async function requestAuth() {
+            authToken = null;
             const username = prompt('Usuário:');
             const password = prompt('Senha:');
 
             if (username && password) {
-                authToken = btoa(username + ':' + password);
+                const response = await fetch(API_BASE + '/api/login', {
+                    method: 'POST',
+                    headers: { 'Content-Type': 'application/json' },
+                    body: JSON.stringify({ username, password })
+                });
+                if (!response.ok) {
+                    alert('Usuário ou senha inválidos');
+                    return;
+                }
+                authToken = (await response.json()).token;
                 loadAll();
             }
         }
@@ -674,12 +684,69 @@ const htmlUI = `<!DOCTYPE html>
             loadPeers();
         }
 
-        // Auto-refresh a cada 5 segundos
-        setInterval(loadStatus, 5000);
-        setInterval(loadLastBlock, 5000);
+        // Atualizações em tempo real pelo WebSocket /api/ws; sem conexão, volta ao polling
+        let pollTimer = null;
+
+        function startPolling() {
+            if (!pollTimer) {
+                pollTimer = setInterval(() => { loadStatus(); loadLastBlock(); }, 5000);
+            }
+        }
+
+        function stopPolling() {
+            clearInterval(pollTimer);
+            pollTimer = null;
+        }
+
+        function connectEvents() {
+            const protocol = location.protocol === 'https:' ? 'wss://' : 'ws://';
+            let url = protocol + location.host + API_BASE + '/api/ws';
+            if (authToken) {
+                url += '?token=' + encodeURIComponent(authToken);
+            }
+
+            const socket = new WebSocket(url);
+            socket.onopen = () => {
+                stopPolling();
+                loadAll();
+            };
+            socket.onmessage = (message) => handleEvent(JSON.parse(message.data));
+            socket.onclose = () => {
+                startPolling();
+                setTimeout(connectEvents, 5000);
+            };
+        }
+
+        // Aplica um evento recebido ao DOM
+        function handleEvent(event) {
+            const data = event.data;
+            switch (event.type) {
+                case 'new_block':
+                    document.getElementById('block-height').textContent = data.height;
+                    document.getElementById('block-hash').textContent = data.hash;
+                    document.getElementById('block-txs').textContent = data.tx_count;
+                    document.getElementById('chain-height').textContent = data.height;
+                    loadStatus();
+                    loadWallet();
+                    break;
+                case 'new_transaction':
+                    loadStatus();
+                    break;
+                case 'peer_connected':
+                case 'peer_disconnected':
+                    document.getElementById('peers-count').textContent = data.peer_count;
+                    loadPeers();
+                    break;
+                case 'mining_state_changed':
+                    document.getElementById('mining-status').textContent = data.mining ? 'Sim' : 'Não';
+                    break;
+            }
+        }
 
         // Carregar ao iniciar
+        startPolling();
         loadAll();
+        connectEvents();
     </script>
 </body>
 </html>
@@ -0,0 +1,163 @@
+package blockchain
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+// Índice de histórico por endereço no LevelDB.
+// Cada transação gera uma entrada por endereço envolvido, com chave
+// addr-{endereço}-{altura}-{posição no bloco} e o ID da transação como valor.
+// Altura e posição têm largura fixa para que a ordem das chaves seja a ordem
+// da chain, permitindo percorrer o histórico do mais novo para o mais antigo.
+
+// addressIndexPrefix prefixo das entradas de um endereço
+func addressIndexPrefix(address string) string {
+	return fmt.Sprintf("addr-%s-", address)
+}
+
+// addressIndexKey chave de uma entrada do índice
+func addressIndexKey(address string, height uint64, position int) string {
+	return fmt.Sprintf("%s%020d-%06d", addressIndexPrefix(address), height, position)
+}
+
+// parseAddressIndexKey extrai altura e posição de uma chave do índice
+func parseAddressIndexKey(key, address string) (uint64, int, error) {
+	var height uint64
+	var position int
+	suffix := strings.TrimPrefix(key, addressIndexPrefix(address))
+	if _, err := fmt.Sscanf(suffix, "%d-%d", &height, &position); err != nil {
+		return 0, 0, fmt.Errorf("invalid address index key %s: %w", key, err)
+	}
+	return height, position, nil
+}
+
+// transactionAddresses retorna os endereços cujo histórico inclui a transação.
+// Coinbase só credita o destinatário; transações para si mesmo (stake, nome) aparecem uma vez.
+func transactionAddresses(tx *Transaction) []string {
+	if tx.IsCoinbase() {
+		return []string{tx.To}
+	}
+	if tx.To == "" || tx.To == tx.From {
+		return []string{tx.From}
+	}
+	return []string{tx.From, tx.To}
+}
+
+// IndexBlockAddresses grava as entradas do índice de endereços para as transações do bloco
+func IndexBlockAddresses(db *leveldb.DB, block *Block) error {
+	if db == nil {
+		return fmt.Errorf("database cannot be nil")
+	}
+	if block == nil {
+		return fmt.Errorf("block cannot be nil")
+	}
+
+	batch := new(leveldb.Batch)
+	putAddressIndex(batch, block)
+	if err := db.Write(batch, nil); err != nil {
+		return fmt.Errorf("failed to save address index: %w", err)
+	}
+
+	return nil
+}
+
+// putAddressIndex adiciona ao batch as entradas do índice de endereços do bloco
+func putAddressIndex(batch *leveldb.Batch, block *Block) {
+	for i, tx := range block.Transactions {
+		for _, addr := range transactionAddresses(tx) {
+			batch.Put([]byte(addressIndexKey(addr, block.Header.Height, i)), []byte(tx.ID))
+		}
+	}
+}
+
+// GetAddressHistory retorna as transações enviadas e recebidas pelo endereço, da mais nova
+// para a mais antiga (limit <= 0 retorna todas). Com banco configurado (SetDB) usa o índice
+// de endereços; sem banco, percorre apenas os blocos em memória.
+func (c *Chain) GetAddressHistory(address string, limit int) ([]*Transaction, error) {
+	c.mu.RLock()
+	db := c.db
+	c.mu.RUnlock()
+
+	if db == nil {
+		return c.addressHistoryFromMemory(address, limit), nil
+	}
+
+	history := make([]*Transaction, 0)
+	blocks := make(map[uint64]*Block)
+
+	iter := db.NewIterator(util.BytesPrefix([]byte(addressIndexPrefix(address))), nil)
+	defer iter.Release()
+
+	for ok := iter.Last(); ok; ok = iter.Prev() {
+		if limit > 0 && len(history) >= limit {
+			break
+		}
+
+		height, position, err := parseAddressIndexKey(string(iter.Key()), address)
+		if err != nil {
+			return nil, err
+		}
+
+		block, cached := blocks[height]
+		if !cached {
+			block = c.blockForHistory(db, height)
+			blocks[height] = block
+		}
+
+		// Entradas de blocos substituídos (reorganização) ou já podados do disco são ignoradas
+		if block == nil || position >= len(block.Transactions) {
+			continue
+		}
+		tx := block.Transactions[position]
+		if tx.ID != string(iter.Value()) {
+			continue
+		}
+
+		history = append(history, tx)
+	}
+	if err := iter.Error(); err != nil {
+		return nil, fmt.Errorf("failed to read address index: %w", err)
+	}
+
+	return history, nil
+}
+
+// blockForHistory busca o bloco em memória e, se necessário, no disco
+func (c *Chain) blockForHistory(db *leveldb.DB, height uint64) *Block {
+	if block, exists := c.GetBlockByHeight(height); exists && !block.IsCheckpointAnchor() {
+		return block
+	}
+	block, err := LoadBlockFromDB(db, height)
+	if err != nil {
+		return nil
+	}
+	return block
+}
+
+// addressHistoryFromMemory percorre os blocos em memória do mais novo para o mais antigo
+func (c *Chain) addressHistoryFromMemory(address string, limit int) []*Transaction {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	history := make([]*Transaction, 0)
+	for i := len(c.blocks) - 1; i >= 0; i-- {
+		txs := c.blocks[i].Transactions
+		for j := len(txs) - 1; j >= 0; j-- {
+			if limit > 0 && len(history) >= limit {
+				return history
+			}
+			for _, addr := range transactionAddresses(txs[j]) {
+				if addr == address {
+					history = append(history, txs[j])
+					break
+				}
+			}
+		}
+	}
+
+	return history
+}
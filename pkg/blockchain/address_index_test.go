@@ -0,0 +1,155 @@
+package blockchain
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/krakovia/blockchain/pkg/wallet"
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+// Helper: minera um bloco com as transações informadas e o adiciona à chain
+func mineBlockWith(t *testing.T, chain *Chain, miner *wallet.Wallet, txs ...*Transaction) {
+	t.Helper()
+
+	mp := NewMempool()
+	for _, tx := range txs {
+		if err := mp.AddTransaction(tx); err != nil {
+			t.Fatalf("Failed to add transaction: %v", err)
+		}
+	}
+
+	block, err := NewMiner(miner, chain, mp).CreateBlock()
+	if err != nil {
+		t.Fatalf("Failed to create block: %v", err)
+	}
+	if err := chain.AddBlock(block); err != nil {
+		t.Fatalf("Failed to add block: %v", err)
+	}
+}
+
+func txIDs(txs []*Transaction) []string {
+	ids := make([]string, len(txs))
+	for i, tx := range txs {
+		ids[i] = tx.ID
+	}
+	return ids
+}
+
+func assertHistory(t *testing.T, name string, got []*Transaction, want ...*Transaction) {
+	t.Helper()
+
+	if len(got) != len(want) {
+		t.Fatalf("%s: expected %d transactions, got %d (%v)", name, len(want), len(got), txIDs(got))
+	}
+	for i := range want {
+		if got[i].ID != want[i].ID {
+			t.Errorf("%s: position %d expected %s, got %s", name, i, want[i].ID, got[i].ID)
+		}
+	}
+}
+
+func TestChainAddressHistory(t *testing.T) {
+	sender, _ := wallet.NewWallet()
+	recipient, _ := wallet.NewWallet()
+	validator, _ := wallet.NewWallet()
+
+	config := DefaultChainConfig()
+	config.BlockTime = 100 * time.Millisecond
+
+	genesis := GenesisBlock(NewCoinbaseTransaction(sender.GetAddress(), 10000, 0))
+	chain, err := NewChain(genesis, config)
+	if err != nil {
+		t.Fatalf("Failed to create chain: %v", err)
+	}
+
+	db, err := leveldb.OpenFile(filepath.Join(t.TempDir(), "history.db"), nil)
+	if err != nil {
+		t.Fatalf("Failed to open DB: %v", err)
+	}
+	defer db.Close()
+	if err := chain.SetDB(db); err != nil {
+		t.Fatalf("Failed to set DB: %v", err)
+	}
+
+	sign := func(w *wallet.Wallet, to string, amount, nonce uint64) *Transaction {
+		tx := NewTransaction(w.GetAddress(), to, amount, 1, nonce, "")
+		if err := tx.Sign(w); err != nil {
+			t.Fatalf("Failed to sign transaction: %v", err)
+		}
+		return tx
+	}
+
+	// Duas transferências: sender -> recipient e recipient -> sender
+	first := sign(sender, recipient.GetAddress(), 500, 0)
+	mineBlockWith(t, chain, validator, first)
+	second := sign(recipient, sender.GetAddress(), 200, 0)
+	mineBlockWith(t, chain, validator, second)
+
+	genesisTx := genesis.Transactions[0]
+	coinbase1 := chain.blocks[1].Transactions[0]
+	coinbase2 := chain.blocks[2].Transactions[0]
+
+	check := func() {
+		t.Helper()
+
+		history, err := chain.GetAddressHistory(sender.GetAddress(), 0)
+		if err != nil {
+			t.Fatalf("Failed to get sender history: %v", err)
+		}
+		assertHistory(t, "sender", history, second, first, genesisTx)
+
+		history, err = chain.GetAddressHistory(recipient.GetAddress(), 0)
+		if err != nil {
+			t.Fatalf("Failed to get recipient history: %v", err)
+		}
+		assertHistory(t, "recipient", history, second, first)
+
+		// Coinbase credita apenas o validador que recebeu a recompensa
+		history, err = chain.GetAddressHistory(validator.GetAddress(), 0)
+		if err != nil {
+			t.Fatalf("Failed to get validator history: %v", err)
+		}
+		assertHistory(t, "validator", history, coinbase2, coinbase1)
+
+		history, err = chain.GetAddressHistory(sender.GetAddress(), 1)
+		if err != nil {
+			t.Fatalf("Failed to get limited history: %v", err)
+		}
+		assertHistory(t, "sender (limit 1)", history, second)
+	}
+
+	check()
+
+	// Após remover os blocos da memória, o histórico vem do disco
+	if err := PruneOldBlocks(db, chain.GetAllBlocksPointer(), 1); err != nil {
+		t.Fatalf("Failed to prune blocks: %v", err)
+	}
+	check()
+}
+
+func TestChainAddressHistoryWithoutDB(t *testing.T) {
+	sender, _ := wallet.NewWallet()
+	recipient, _ := wallet.NewWallet()
+
+	config := DefaultChainConfig()
+	config.BlockTime = 100 * time.Millisecond
+
+	chain, err := NewChain(GenesisBlock(NewCoinbaseTransaction(sender.GetAddress(), 10000, 0)), config)
+	if err != nil {
+		t.Fatalf("Failed to create chain: %v", err)
+	}
+
+	tx := NewTransaction(sender.GetAddress(), recipient.GetAddress(), 500, 1, 0, "")
+	if err := tx.Sign(sender); err != nil {
+		t.Fatalf("Failed to sign transaction: %v", err)
+	}
+	mineBlockWith(t, chain, sender, tx)
+
+	history, err := chain.GetAddressHistory(recipient.GetAddress(), 0)
+	if err != nil {
+		t.Fatalf("Failed to get history: %v", err)
+	}
+	assertHistory(t, "recipient", history, tx)
+}
@@ -1,20 +1,28 @@
 package blockchain
 
 import (
-	"crypto/sha256"
-	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
+
+	"github.com/krakovia/blockchain/pkg/wallet"
 )
 
+// BlockFormatVersion versão do formato dos bytes que entram nos hashes (hashPayload do header e
+// canonicalPayload da transação): o primeiro byte do payload, seguido dos campos em ordem fixa,
+// inteiros em big-endian e strings prefixadas pelo tamanho (ver payloadWriter). O formato é
+// fixado pelos arquivos em testdata/; mudar qualquer um desses bytes muda os hashes, então a
+// mudança precisa ser intencional e regravar o golden no mesmo commit.
+const BlockFormatVersion uint32 = 1
+
 // BlockHeader contém os metadados do bloco
 type BlockHeader struct {
 	Version          uint32 `json:"version"`                    // Versão do protocolo
 	Height           uint64 `json:"height"`                     // Altura do bloco na chain
 	Timestamp        int64  `json:"timestamp"`                  // Timestamp Unix
 	PreviousHash     string `json:"previous_hash"`              // Hash do bloco anterior
-	MerkleRoot       string `json:"merkle_root"`                // Raiz da árvore de Merkle das transações
+	MerkleRoot       string `json:"merkle_root"`                // Raiz da árvore de Merkle dos IDs das transações
 	ValidatorAddr    string `json:"validator_addr"`             // Endereço do validador que criou o bloco
 	Signature        string `json:"signature"`                  // Assinatura do validador
 	PublicKey        string `json:"public_key"`                 // Chave pública do validador
@@ -28,6 +36,8 @@ type Block struct {
 	Header       BlockHeader       `json:"header"`
 	Transactions TransactionSlice  `json:"transactions"`
 	Hash         string            `json:"hash"`
+
+	checkpointAnchor bool // Âncora de checkpoint restaurado (nunca serializada)
 }
 
 // NewBlock cria um novo bloco
@@ -36,7 +46,7 @@ func NewBlock(height uint64, previousHash string, transactions TransactionSlice,
 
 	block := &Block{
 		Header: BlockHeader{
-			Version:       1,
+			Version:       BlockFormatVersion,
 			Height:        height,
 			Timestamp:     time.Now().Unix(),
 			PreviousHash:  previousHash,
@@ -50,47 +60,67 @@ func NewBlock(height uint64, previousHash string, transactions TransactionSlice,
 	return block
 }
 
+// NewCheckpointAnchorBlock cria um bloco âncora para a chain restaurada de um checkpoint.
+// Ele só carrega a altura e o hash do bloco do checkpoint, para que os blocos
+// seguintes possam se conectar; não possui transações e nunca deve ser propagado.
+func NewCheckpointAnchorBlock(height uint64, hash string) *Block {
+	return &Block{
+		Header: BlockHeader{
+			Version: BlockFormatVersion,
+			Height:  height,
+		},
+		Transactions:     TransactionSlice{},
+		Hash:             hash,
+		checkpointAnchor: true,
+	}
+}
+
+// IsCheckpointAnchor verifica se o bloco é uma âncora de checkpoint
+func (b *Block) IsCheckpointAnchor() bool {
+	return b.checkpointAnchor
+}
+
 // CalculateHash calcula o hash do bloco (sem incluir a assinatura)
 func (b *Block) CalculateHash() (string, error) {
-	// Cria uma cópia do header sem assinatura para calcular o hash
-	headerCopy := BlockHeader{
-		Version:       b.Header.Version,
-		Height:        b.Header.Height,
-		Timestamp:     b.Header.Timestamp,
-		PreviousHash:  b.Header.PreviousHash,
-		MerkleRoot:    b.Header.MerkleRoot,
-		ValidatorAddr: b.Header.ValidatorAddr,
-		PublicKey:     b.Header.PublicKey,
-		Nonce:         b.Header.Nonce,
-	}
-
-	data, err := json.Marshal(headerCopy)
+	return b.Header.CalculateHash()
+}
+
+// Sign assina o bloco com a carteira do validador. Define a chave pública no header,
+// recalcula o hash (que cobre a chave pública) e assina o hash do header.
+func (b *Block) Sign(w *wallet.Wallet) error {
+	if w.GetAddress() != b.Header.ValidatorAddr {
+		return fmt.Errorf("wallet address %s does not match block validator %s", w.GetAddress(), b.Header.ValidatorAddr)
+	}
+
+	b.Header.PublicKey = w.GetPublicKeyHex()
+
+	hash, err := b.CalculateHash()
+	if err != nil {
+		return err
+	}
+	b.Hash = hash
+
+	signature, err := w.Sign([]byte(hash))
 	if err != nil {
-		return "", fmt.Errorf("failed to marshal block header: %w", err)
+		return fmt.Errorf("failed to sign block: %w", err)
 	}
+	b.Header.Signature = signature
 
-	hash := sha256.Sum256(data)
-	return hex.EncodeToString(hash[:]), nil
+	return nil
 }
 
-// GetSignData retorna os dados que devem ser assinados pelo validador
-func (b *Block) GetSignData() ([]byte, error) {
-	headerCopy := BlockHeader{
-		Version:       b.Header.Version,
-		Height:        b.Header.Height,
-		Timestamp:     b.Header.Timestamp,
-		PreviousHash:  b.Header.PreviousHash,
-		MerkleRoot:    b.Header.MerkleRoot,
-		ValidatorAddr: b.Header.ValidatorAddr,
-		Nonce:         b.Header.Nonce,
+// VerifySignature verifica se o bloco foi assinado pelo validador indicado em ValidatorAddr
+func (b *Block) VerifySignature() error {
+	if err := b.Header.verifyValidatorKey(); err != nil {
+		return err
 	}
 
-	data, err := json.Marshal(headerCopy)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal block header for signing: %w", err)
+	// O hash assinado precisa corresponder ao header atual
+	if err := b.VerifyHash(); err != nil {
+		return err
 	}
 
-	return data, nil
+	return b.Header.verifySignatureOf(b.Hash)
 }
 
 // VerifyHash verifica se o hash do bloco está correto
@@ -131,7 +161,8 @@ func (b *Block) VerifyTransactions() error {
 		return fmt.Errorf("block contains duplicate transactions")
 	}
 
-	// Valida cada transação
+	// Valida a estrutura e as regras de cada transação; as assinaturas, a parte cara, são
+	// verificadas depois, em paralelo
 	for i, tx := range b.Transactions {
 		// Primeira transação deve ser coinbase
 		if i == 0 {
@@ -142,21 +173,40 @@ func (b *Block) VerifyTransactions() error {
 				return fmt.Errorf("invalid coinbase transaction: %w", err)
 			}
 		} else {
-			// Outras transações não devem ser coinbase
+			// Outras transações não devem ser coinbase (exceto no gênesis, uma por alocação)
 			if tx.IsCoinbase() {
-				return fmt.Errorf("only first transaction can be coinbase")
+				if !b.IsGenesis() {
+					return fmt.Errorf("only first transaction can be coinbase")
+				}
+				if err := tx.VerifyCoinbase(); err != nil {
+					return fmt.Errorf("invalid genesis allocation at index %d: %w", i, err)
+				}
+				continue
 			}
-			if err := tx.Validate(); err != nil {
+			if err := tx.validateRules(); err != nil {
 				return fmt.Errorf("invalid transaction at index %d: %w", i, err)
 			}
 		}
 	}
 
+	if err := b.Transactions.VerifySignatures(); err != nil {
+		var txErr *TransactionError
+		if errors.As(err, &txErr) {
+			return fmt.Errorf("invalid transaction at index %d: %w", txErr.Index, txErr.Err)
+		}
+		return err
+	}
+
 	return nil
 }
 
-// Validate valida o bloco completamente
+// Validate valida o bloco completamente com o relógio local e a tolerância padrão
 func (b *Block) Validate() error {
+	return b.ValidateAt(time.Now(), DefaultMaxClockDrift)
+}
+
+// ValidateAt valida o bloco completamente, aceitando timestamps até maxDrift depois de now
+func (b *Block) ValidateAt(now time.Time, maxDrift time.Duration) error {
 	// Valida campos obrigatórios
 	if b.Header.Height == 0 && b.Header.PreviousHash != "" {
 		return fmt.Errorf("genesis block must have empty previous hash")
@@ -171,9 +221,8 @@ func (b *Block) Validate() error {
 	}
 
 	// Valida timestamp (não pode ser muito no futuro)
-	now := time.Now().Unix()
-	if b.Header.Timestamp > now+300 { // 5 minutos de tolerância
-		return fmt.Errorf("block timestamp is too far in the future")
+	if limit := now.Add(maxDrift).Unix(); b.Header.Timestamp > limit {
+		return fmt.Errorf("block timestamp is too far in the future: %d > %d", b.Header.Timestamp, limit)
 	}
 
 	// Verifica o hash do bloco
@@ -199,10 +248,15 @@ func (b *Block) Serialize() ([]byte, error) {
 	return json.Marshal(b)
 }
 
-// DeserializeBlock desserializa um bloco de JSON
+// DeserializeBlock desserializa um bloco de JSON (comprimido ou não)
 func DeserializeBlock(data []byte) (*Block, error) {
+	data, err := DecompressPayload(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to deserialize block: %w", err)
+	}
+
 	var block Block
-	err := json.Unmarshal(data, &block)
+	err = json.Unmarshal(data, &block)
 	if err != nil {
 		return nil, fmt.Errorf("failed to deserialize block: %w", err)
 	}
@@ -293,12 +347,51 @@ func GenesisBlockWithTimestamp(genesisTransaction *Transaction, timestamp int64)
 		return nil
 	}
 
-	transactions := TransactionSlice{genesisTransaction}
+	return newGenesisBlock(TransactionSlice{genesisTransaction}, timestamp)
+}
+
+// GenesisAllocation saldo inicial de um endereço no bloco gênesis
+type GenesisAllocation struct {
+	Address string `json:"address"`
+	Amount  uint64 `json:"amount"`
+}
+
+// GenesisBlockWithAllocations cria o bloco gênesis com uma transação coinbase por alocação,
+// na ordem informada (a ordem faz parte do hash). O primeiro endereço é o validador inicial.
+// Com uma única alocação, o bloco é idêntico ao criado por GenesisBlockWithTimestamp.
+func GenesisBlockWithAllocations(allocations []GenesisAllocation, timestamp int64) (*Block, error) {
+	if len(allocations) == 0 {
+		return nil, fmt.Errorf("genesis requires at least one allocation")
+	}
+
+	transactions := make(TransactionSlice, 0, len(allocations))
+	seen := make(map[string]bool, len(allocations))
+	for i, alloc := range allocations {
+		if alloc.Address == "" {
+			return nil, fmt.Errorf("genesis allocation %d has empty address", i)
+		}
+		if alloc.Amount == 0 {
+			return nil, fmt.Errorf("genesis allocation %d (%s) has zero amount", i, alloc.Address)
+		}
+		if seen[alloc.Address] {
+			return nil, fmt.Errorf("duplicate genesis allocation for %s", alloc.Address)
+		}
+		seen[alloc.Address] = true
+
+		transactions = append(transactions, NewCoinbaseTransactionWithTimestamp(alloc.Address, alloc.Amount, 0, timestamp))
+	}
+
+	return newGenesisBlock(transactions, timestamp), nil
+}
+
+// newGenesisBlock monta o bloco gênesis com as transações coinbase informadas
+func newGenesisBlock(transactions TransactionSlice, timestamp int64) *Block {
+	genesisTransaction := transactions[0]
 	merkleRoot := transactions.CalculateMerkleRoot()
 
 	block := &Block{
 		Header: BlockHeader{
-			Version:       1,
+			Version:       BlockFormatVersion,
 			Height:        0,
 			Timestamp:     timestamp,
 			PreviousHash:  "",
@@ -326,13 +419,15 @@ func ValidateGenesisBlock(block *Block, expectedGenesisHash string) error {
 		return fmt.Errorf("genesis block hash mismatch: expected %s, got %s", expectedGenesisHash, block.Hash)
 	}
 
-	// Valida que tem exatamente uma transação coinbase
-	if len(block.Transactions) != 1 {
-		return fmt.Errorf("genesis block must have exactly one transaction")
+	// Valida que só tem transações coinbase (uma por alocação)
+	if len(block.Transactions) == 0 {
+		return fmt.Errorf("genesis block must have at least one transaction")
 	}
 
-	if !block.Transactions[0].IsCoinbase() {
-		return fmt.Errorf("genesis block transaction must be coinbase")
+	for _, tx := range block.Transactions {
+		if !tx.IsCoinbase() {
+			return fmt.Errorf("genesis block transactions must be coinbase")
+		}
 	}
 
 	// Verifica o hash e merkle root
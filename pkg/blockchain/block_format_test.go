@@ -0,0 +1,155 @@
+package blockchain
+
+import (
+	"bytes"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var updateGolden = flag.Bool("update", false, "regrava os arquivos golden em testdata/")
+
+// goldenBlock monta um bloco fixo (sem relógio nem chaves aleatórias) cobrindo os campos que
+// entram nos hashes, inclusive os opcionais
+func goldenBlock(t *testing.T) *Block {
+	t.Helper()
+
+	coinbase := NewCoinbaseTransactionWithTimestamp("validator-address", 50, 7, 1700000000)
+	payment := &Transaction{
+		From:         "sender-address",
+		To:           "recipient-address",
+		Amount:       1234,
+		Fee:          5,
+		Timestamp:    1700000001,
+		PublicKey:    "04abcdef",
+		Nonce:        3,
+		Data:         "pagamento",
+		ExpiryHeight: 100,
+	}
+	id, err := payment.CalculateHash()
+	if err != nil {
+		t.Fatalf("Failed to hash transaction: %v", err)
+	}
+	payment.ID = id
+
+	txs := TransactionSlice{coinbase, payment}
+	block := &Block{
+		Header: BlockHeader{
+			Version:       BlockFormatVersion,
+			Height:        7,
+			Timestamp:     1700000002,
+			PreviousHash:  strings.Repeat("ab", 32),
+			MerkleRoot:    txs.CalculateMerkleRoot(),
+			ValidatorAddr: "validator-address",
+			PublicKey:     "04fedcba",
+			Nonce:         9,
+		},
+		Transactions: txs,
+	}
+	hash, err := block.CalculateHash()
+	if err != nil {
+		t.Fatalf("Failed to hash block: %v", err)
+	}
+	block.Hash = hash
+	return block
+}
+
+// TestBlockFormatGolden garante que os bytes cobertos pelos hashes continuam os mesmos do formato
+// BlockFormatVersion. Uma falha aqui significa que hashes de blocos e transações mudaram; se a
+// mudança for intencional, regrave com go test -run BlockFormat -update no mesmo commit.
+func TestBlockFormatGolden(t *testing.T) {
+	block := goldenBlock(t)
+
+	var out bytes.Buffer
+	headerPayload, err := block.Header.hashPayload()
+	if err != nil {
+		t.Fatalf("Failed to serialize header: %v", err)
+	}
+	fmt.Fprintf(&out, "version: %d\n", BlockFormatVersion)
+	fmt.Fprintf(&out, "header: %s\n", hex.EncodeToString(headerPayload))
+	fmt.Fprintf(&out, "block_hash: %s\n", block.Hash)
+	for i, tx := range block.Transactions {
+		payload, err := tx.canonicalPayload(true)
+		if err != nil {
+			t.Fatalf("Failed to serialize transaction %d: %v", i, err)
+		}
+		signData, err := tx.GetSignData()
+		if err != nil {
+			t.Fatalf("Failed to get sign data of transaction %d: %v", i, err)
+		}
+		fmt.Fprintf(&out, "tx[%d]: %s\n", i, hex.EncodeToString(payload))
+		fmt.Fprintf(&out, "tx[%d]_sign_data: %s\n", i, hex.EncodeToString(signData))
+		fmt.Fprintf(&out, "tx[%d]_id: %s\n", i, tx.ID)
+	}
+	fmt.Fprintf(&out, "merkle_root: %s\n", block.Header.MerkleRoot)
+
+	path := filepath.Join("testdata", fmt.Sprintf("block_format_v%d.golden", BlockFormatVersion))
+	if *updateGolden {
+		if err := os.WriteFile(path, out.Bytes(), 0644); err != nil {
+			t.Fatalf("Failed to update golden file: %v", err)
+		}
+	}
+
+	expected, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read golden file: %v", err)
+	}
+	if !bytes.Equal(out.Bytes(), expected) {
+		t.Errorf("Serialized block format changed (hashes would change across versions).\nGot:\n%s\nExpected:\n%s", out.Bytes(), expected)
+	}
+}
+
+// TestBlockFormatLayout confere o layout binário campo a campo: byte de versão, inteiros
+// big-endian e strings prefixadas pelo tamanho
+func TestBlockFormatLayout(t *testing.T) {
+	header := BlockHeader{
+		Version:       BlockFormatVersion,
+		Height:        0x0102,
+		Timestamp:     -1,
+		PreviousHash:  "ab",
+		MerkleRoot:    "",
+		ValidatorAddr: "v",
+		PublicKey:     "pk",
+		Nonce:         3,
+	}
+	payload, err := header.hashPayload()
+	if err != nil {
+		t.Fatalf("Failed to serialize header: %v", err)
+	}
+
+	expected := "01" + // versão do formato
+		"00000001" + // Version
+		"0000000000000102" + // Height
+		"ffffffffffffffff" + // Timestamp
+		"00000002" + hex.EncodeToString([]byte("ab")) + // PreviousHash
+		"00000000" + // MerkleRoot
+		"00000001" + hex.EncodeToString([]byte("v")) + // ValidatorAddr
+		"00000002" + hex.EncodeToString([]byte("pk")) + // PublicKey
+		"0000000000000003" // Nonce
+	if got := hex.EncodeToString(payload); got != expected {
+		t.Errorf("Unexpected header layout:\ngot  %s\nwant %s", got, expected)
+	}
+
+	// Mudar o tamanho de uma string não pode ser confundido com mover bytes entre campos
+	shifted := header
+	shifted.PreviousHash, shifted.MerkleRoot = "a", "b"
+	if other, _ := shifted.hashPayload(); bytes.Equal(payload, other) {
+		t.Error("Different field boundaries produced the same payload")
+	}
+}
+
+func TestBlockHeaderRejectsNewerFormat(t *testing.T) {
+	block := goldenBlock(t)
+
+	block.Header.Version = BlockFormatVersion + 1
+	if _, err := block.CalculateHash(); err == nil {
+		t.Error("Expected header with a newer format version to be rejected")
+	}
+	if err := block.VerifyHash(); err == nil {
+		t.Error("Expected hash verification to fail for a newer format version")
+	}
+}
@@ -0,0 +1,151 @@
+package blockchain
+
+import (
+	"fmt"
+	"time"
+)
+
+// BlockTemplate dados para montar o próximo bloco fora do nó (mineração externa): os campos
+// do header pendente, a coinbase e as transações selecionadas do mempool, e se o validador
+// pode produzir o bloco agora
+type BlockTemplate struct {
+	Version       uint32           `json:"version"`
+	Height        uint64           `json:"height"`
+	PreviousHash  string           `json:"previous_hash"`
+	ValidatorAddr string           `json:"validator_addr"`
+	CurTime       int64            `json:"cur_time"`      // Horário da rede (unix, em segundos)
+	MinTimestamp  int64            `json:"min_timestamp"` // Menor timestamp aceito (tempo mínimo entre blocos)
+	MaxTimestamp  int64            `json:"max_timestamp"` // Maior timestamp aceito (tolerância de relógio)
+	Coinbase      *Transaction     `json:"coinbase"`      // Recompensa do bloco, sempre a primeira transação
+	Transactions  TransactionSlice `json:"transactions"`  // Transações do mempool, na ordem em que devem entrar
+
+	// Elegibilidade do validador
+	CanMine           bool   `json:"can_mine"`            // Stake suficiente para validar
+	IsMyTurn          bool   `json:"is_my_turn"`          // Validador prioritário para a próxima altura
+	Rank              int    `json:"rank"`                // Posição na fila de prioridade (-1 = fora dela)
+	Stake             uint64 `json:"stake"`               // Stake atual do validador
+	MinValidatorStake uint64 `json:"min_validator_stake"` // Stake mínimo exigido
+}
+
+// NewBlock monta o bloco (ainda sem assinatura) com a coinbase seguida das transações do
+// template, carimbado com CurTime ou, se for cedo demais, com MinTimestamp
+func (t *BlockTemplate) NewBlock() *Block {
+	transactions := make(TransactionSlice, 0, len(t.Transactions)+1)
+	transactions = append(transactions, t.Coinbase)
+	transactions = append(transactions, t.Transactions...)
+
+	block := NewBlock(t.Height, t.PreviousHash, transactions, t.ValidatorAddr)
+	block.Header.Version = t.Version
+	block.Header.Timestamp = max(t.CurTime, t.MinTimestamp)
+	return block
+}
+
+// buildTemplate seleciona a coinbase e as transações do próximo bloco, sem a elegibilidade
+func (m *Miner) buildTemplate() (*BlockTemplate, error) {
+	lastBlock := m.chain.GetLastBlock()
+	if lastBlock == nil {
+		return nil, fmt.Errorf("no last block")
+	}
+
+	config := m.chain.GetConfig()
+
+	// Um segundo bloco na mesma altura (ex.: depois de uma reorganização que descartou o nosso)
+	// seria uma assinatura dupla punível
+	height := lastBlock.Header.Height + 1
+	if m.chain.HasSignedAt(height, m.address) {
+		return nil, fmt.Errorf("validator already signed a block at height %d", height)
+	}
+
+	// Cria transação coinbase com a recompensa do cronograma de halving, limitada à oferta máxima
+	coinbase := NewCoinbaseTransaction(
+		m.address,
+		m.chain.CoinbaseReward(height),
+		height,
+	)
+
+	// Pega transações do mempool priorizadas por fee (mantendo a ordem de nonce por remetente)
+	// e mantém apenas as que executam em sequência sobre o estado atual.
+	// Os limites de consenso cortam o fim da lista para o bloco caber em MaxBlockTxs e MaxBlockBytes.
+	candidates := m.mempool.GetTransactionsByFee(0)
+	if !m.senderFilter.IsEmpty() {
+		candidates = TransactionSlice(candidates).Filter(m.senderFilter.AllowsTransaction)
+	}
+	validTxs := m.chain.context.SelectExecutableTransactions(candidates, config.Consensus.MaxBlockTxs)
+	validTxs = config.Consensus.FitTransactions(coinbase, validTxs)
+
+	// Carimba com o horário ajustado pela rede, para peers com relógios diferentes aceitarem o bloco,
+	// respeitando o tempo mínimo entre blocos (80% do BlockTime)
+	now := m.chain.Now()
+	minBlockTime := int64(config.BlockTime.Seconds() * 0.8)
+
+	return &BlockTemplate{
+		Version:       BlockFormatVersion,
+		Height:        height,
+		PreviousHash:  lastBlock.Hash,
+		ValidatorAddr: m.address,
+		CurTime:       now.Unix(),
+		MinTimestamp:  lastBlock.Header.Timestamp + minBlockTime,
+		MaxTimestamp:  now.Add(config.ClockDrift()).Unix(),
+		Coinbase:      coinbase,
+		Transactions:  validTxs,
+	}, nil
+}
+
+// GetBlockTemplate retorna o template do próximo bloco deste minerador, para ser montado
+// por um processo externo e entregue com SubmitBlock
+func (m *Miner) GetBlockTemplate() (*BlockTemplate, error) {
+	template, err := m.buildTemplate()
+	if err != nil {
+		return nil, err
+	}
+
+	template.CanMine = m.CanMine()
+	template.IsMyTurn = m.IsMyTurn()
+	template.Rank = m.GetRank()
+	template.Stake = m.GetStake()
+	template.MinValidatorStake = m.chain.GetConfig().MinValidatorStake
+
+	return template, nil
+}
+
+// SubmitBlock valida e adiciona à chain um bloco montado fora do nó a partir de um template.
+// O bloco precisa ser deste minerador; se vier sem assinatura, é assinado com a carteira dele.
+// Valem as mesmas regras da mineração interna (stake mínimo e vez do validador).
+func (m *Miner) SubmitBlock(block *Block) error {
+	if block == nil {
+		return fmt.Errorf("block cannot be nil")
+	}
+	if block.Header.ValidatorAddr != m.address {
+		return fmt.Errorf("block validator %s is not this miner (%s)", block.Header.ValidatorAddr, m.address)
+	}
+	if !m.CanMine() {
+		return fmt.Errorf("insufficient stake to mine")
+	}
+	if !m.IsMyTurn() {
+		return fmt.Errorf("not this miner's turn")
+	}
+
+	if block.Header.Signature == "" {
+		if err := block.Sign(m.wallet); err != nil {
+			return fmt.Errorf("failed to sign block: %w", err)
+		}
+	}
+
+	if err := m.chain.AddBlock(block); err != nil {
+		return fmt.Errorf("block rejected: %w", err)
+	}
+
+	m.lastMined = time.Now()
+	m.removeMinedTransactions(block)
+
+	// Ao contrário do MineLoop, o bloco só é propagado depois de aceito pela chain, já que foi
+	// montado fora do nó
+	if m.onBlockCreated != nil {
+		m.onBlockCreated(block)
+	}
+	if m.onBlockAdded != nil {
+		m.onBlockAdded(block)
+	}
+
+	return nil
+}
@@ -0,0 +1,61 @@
+package blockchain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/krakovia/blockchain/pkg/wallet"
+)
+
+func TestSubmitBlockFromTemplate(t *testing.T) {
+	validator, _ := wallet.NewWallet()
+	other, _ := wallet.NewWallet()
+	genesis := GenesisBlock(NewCoinbaseTransaction(validator.GetAddress(), 10000, 0))
+
+	config := DefaultChainConfig()
+	config.BlockTime = 100 * time.Millisecond
+	chain, err := NewChainWithStake(genesis, config, validator.GetAddress(), 1000)
+	if err != nil {
+		t.Fatalf("Failed to create chain: %v", err)
+	}
+	miner := NewMiner(validator, chain, NewMempool())
+
+	template, err := miner.GetBlockTemplate()
+	if err != nil {
+		t.Fatalf("Failed to get template: %v", err)
+	}
+	if template.CurTime > template.MaxTimestamp || template.MinTimestamp > template.MaxTimestamp {
+		t.Errorf("Inconsistent timestamp bounds: %+v", template)
+	}
+
+	// Bloco de outro validador não é aceito como deste minerador
+	foreign := template.NewBlock()
+	foreign.Header.ValidatorAddr = other.GetAddress()
+	if err := foreign.Sign(other); err != nil {
+		t.Fatalf("Failed to sign foreign block: %v", err)
+	}
+	if err := miner.SubmitBlock(foreign); err == nil {
+		t.Error("Block from another validator should be rejected")
+	}
+
+	// Bloco assinado pelo processo externo com a chave do validador
+	block := template.NewBlock()
+	if err := block.Sign(validator); err != nil {
+		t.Fatalf("Failed to sign block: %v", err)
+	}
+	added := 0
+	miner.SetOnBlockAdded(func(*Block) { added++ })
+	if err := miner.SubmitBlock(block); err != nil {
+		t.Fatalf("Failed to submit block: %v", err)
+	}
+	if chain.GetHeight() != 1 || added != 1 {
+		t.Errorf("Expected block added at height 1 with callback, got height %d and %d callbacks", chain.GetHeight(), added)
+	}
+
+	// Template antigo não conecta mais à ponta
+	stale := template.NewBlock()
+	stale.Header.Nonce = 1
+	if err := miner.SubmitBlock(stale); err == nil {
+		t.Error("Block built from a stale template should be rejected")
+	}
+}
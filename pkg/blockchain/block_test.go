@@ -308,6 +308,74 @@ func TestValidateGenesisBlock(t *testing.T) {
 	}
 }
 
+func TestGenesisBlockWithAllocations(t *testing.T) {
+	const timestamp = 1700000000
+	allocations := []GenesisAllocation{
+		{Address: "alice", Amount: 1000},
+		{Address: "bob", Amount: 250},
+		{Address: "carol", Amount: 75},
+	}
+
+	genesis, err := GenesisBlockWithAllocations(allocations, timestamp)
+	if err != nil {
+		t.Fatalf("Failed to create genesis: %v", err)
+	}
+	if err := ValidateGenesisBlock(genesis, genesis.Hash); err != nil {
+		t.Errorf("Multi-allocation genesis should be valid: %v", err)
+	}
+
+	// Mesmas alocações geram o mesmo hash
+	again, _ := GenesisBlockWithAllocations(allocations, timestamp)
+	if again.Hash != genesis.Hash {
+		t.Error("Genesis hash should be deterministic")
+	}
+
+	chain, err := NewChain(genesis, DefaultChainConfig())
+	if err != nil {
+		t.Fatalf("Failed to create chain: %v", err)
+	}
+	for _, alloc := range allocations {
+		if balance := chain.GetBalance(alloc.Address); balance != alloc.Amount {
+			t.Errorf("Expected balance %d for %s, got %d", alloc.Amount, alloc.Address, balance)
+		}
+	}
+	if supply := chain.TotalSupply(); supply != 1325 {
+		t.Errorf("Expected total supply 1325, got %d", supply)
+	}
+
+	// Uma única alocação gera o mesmo gênesis de antes
+	single, _ := GenesisBlockWithAllocations(allocations[:1], timestamp)
+	legacy := GenesisBlockWithTimestamp(NewCoinbaseTransactionWithTimestamp("alice", 1000, 0, timestamp), timestamp)
+	if single.Hash != legacy.Hash {
+		t.Error("Single allocation genesis should match the legacy genesis hash")
+	}
+}
+
+func TestGenesisBlockWithAllocationsInvalid(t *testing.T) {
+	tests := []struct {
+		name        string
+		allocations []GenesisAllocation
+	}{
+		{"Empty", nil},
+		{"Empty address", []GenesisAllocation{{Address: "", Amount: 10}}},
+		{"Zero amount", []GenesisAllocation{{Address: "alice", Amount: 0}}},
+		{"Duplicate", []GenesisAllocation{{Address: "alice", Amount: 10}, {Address: "alice", Amount: 20}}},
+	}
+
+	for _, tt := range tests {
+		if _, err := GenesisBlockWithAllocations(tt.allocations, 1700000000); err == nil {
+			t.Errorf("%s: expected error", tt.name)
+		}
+	}
+
+	// Só o gênesis pode ter mais de uma coinbase
+	genesis, _ := GenesisBlockWithAllocations([]GenesisAllocation{{Address: "alice", Amount: 10}, {Address: "bob", Amount: 20}}, 1700000000)
+	block := NewBlock(1, genesis.Hash, genesis.Transactions, "alice")
+	if err := block.VerifyTransactions(); err == nil {
+		t.Error("Non-genesis block with multiple coinbase should be rejected")
+	}
+}
+
 func TestBlockGetCoinbaseTransaction(t *testing.T) {
 	coinbase := NewCoinbaseTransaction("validator_addr", 50, 1)
 	txs := TransactionSlice{coinbase}
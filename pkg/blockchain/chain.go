@@ -4,24 +4,58 @@ import (
 	"fmt"
 	"sync"
 	"time"
+
+	"github.com/syndtr/goleveldb/leveldb"
 )
 
 // ChainConfig configurações da blockchain
 type ChainConfig struct {
 	BlockTime         time.Duration // Tempo entre blocos (200-300ms para testes)
-	MaxBlockSize      int           // Máximo de transações por bloco
-	BlockReward       uint64        // Recompensa por bloco
+	BlockReward       uint64        // Recompensa por bloco (antes do primeiro halving)
+	HalvingInterval   uint64        // Blocos entre cada halving da recompensa (0 = sem halving)
+	MaxSupply         uint64        // Oferta máxima de tokens, incluindo o gênesis (0 = ilimitada)
 	MinValidatorStake uint64        // Stake mínimo para ser validador
+	UnbondingPeriod   uint64        // Blocos até o valor de um unstake virar saldo (0 = imediato)
+	CoinbaseMaturity  uint64        // Blocos até a recompensa de um bloco poder ser gasta (0 = imediato)
+	SlashBasisPoints  uint64        // Parte do stake removida por assinatura dupla, em pontos base (1/10000; 0 = sem punição)
+	MaxReorgDepth     uint64        // Blocos abaixo da ponta que um fork pode substituir; os mais antigos são finais (0 = sem limite)
+	MaxClockDrift     time.Duration // Tolerância para timestamps no futuro e limite do ajuste do relógio pela rede (0 = DefaultMaxClockDrift)
+	MinFee            uint64        // Fee mínima para uma transação entrar no mempool (0 = sem mínimo); política de admissão, não regra de validade do bloco
+
+	// Bloqueios de saldo do gênesis (cliff + liberação linear)
+	Vesting []VestingSchedule
+
+	// Limites de tamanho de blocos e transações
+	Consensus ConsensusParams
 }
 
 // DefaultChainConfig retorna configurações padrão para testes
 func DefaultChainConfig() ChainConfig {
 	return ChainConfig{
 		BlockTime:         200 * time.Millisecond, // 200ms entre blocos (otimizado para testes rápidos)
-		MaxBlockSize:      1000,
 		BlockReward:       50,
 		MinValidatorStake: 100,
+		UnbondingPeriod:   10,
+		SlashBasisPoints:  1000,
+		MaxReorgDepth:     100,
+		MaxClockDrift:     DefaultMaxClockDrift,
+		MinFee:            1,
+		Consensus:         DefaultConsensusParams(),
+	}
+}
+
+// ClockDrift retorna a tolerância para timestamps no futuro (MaxClockDrift ou o padrão)
+func (cfg ChainConfig) ClockDrift() time.Duration {
+	if cfg.MaxClockDrift <= 0 {
+		return DefaultMaxClockDrift
 	}
+	return cfg.MaxClockDrift
+}
+
+// EvidenceMaxAge retorna até quantos blocos depois da altura denunciada uma evidência de
+// assinatura dupla pode entrar em um bloco (ver evidenceMaxAge)
+func (cfg ChainConfig) EvidenceMaxAge() uint64 {
+	return evidenceMaxAge(cfg.UnbondingPeriod)
 }
 
 // Chain representa a blockchain completa
@@ -40,8 +74,33 @@ type Chain struct {
 	// Map de hash -> bloco para lookup rápido
 	blocksByHash map[string]*Block
 
+	// Peso acumulado (soma dos stakes dos produtores) até cada bloco da chain principal
+	weights map[string]uint64
+
 	// Bloco gênesis
 	genesis *Block
+
+	// Stake inicial aplicado sobre o gênesis (necessário para reexecutar o estado desde o gênesis)
+	initialStakeAddr string
+	initialStake     uint64
+
+	// Último checkpoint carregado para reconstruir saldos antigos (ver GetBalanceAtHeight)
+	historyMu   sync.Mutex
+	historyBase *Checkpoint
+
+	// Banco usado para buscar blocos e transações que já saíram da memória (opcional)
+	db *leveldb.DB
+
+	// Total de tokens já emitidos por coinbase (gênesis incluso)
+	minted uint64
+
+	// Relógio ajustado pelo horário dos peers, usado para carimbar e validar blocos
+	clock *NetworkClock
+
+	// Detecção de assinatura dupla: altura -> validador -> header do bloco aceito
+	signedBlocks map[uint64]map[string]*Block
+	reported     map[string]bool // validador-altura já denunciados
+	onDoubleSign func(*SlashingEvidence)
 }
 
 // NewChain cria uma nova blockchain com bloco gênesis
@@ -64,13 +123,78 @@ func NewChainWithStake(genesisBlock *Block, config ChainConfig, stakeAddr string
 		return nil, fmt.Errorf("invalid genesis block: %w", err)
 	}
 
+	// Emissão do gênesis já conta para a oferta máxima
+	var minted uint64
+	for _, tx := range genesisBlock.Transactions {
+		if tx.IsCoinbase() {
+			minted += tx.Amount
+		}
+	}
+	if config.MaxSupply > 0 && minted > config.MaxSupply {
+		return nil, fmt.Errorf("genesis allocation %d exceeds max supply %d", minted, config.MaxSupply)
+	}
+
+	// O vesting só pode bloquear valores alocados no gênesis
+	for _, schedule := range config.Vesting {
+		var allocated uint64
+		for _, tx := range genesisBlock.Transactions {
+			if tx.IsCoinbase() && tx.To == schedule.Address {
+				allocated += tx.Amount
+			}
+		}
+		if schedule.Amount > allocated {
+			return nil, fmt.Errorf("vesting for %s locks %d but genesis allocates %d", schedule.Address, schedule.Amount, allocated)
+		}
+	}
+
+	chain := &Chain{
+		config:           config,
+		clock:            NewNetworkClock(config.ClockDrift()),
+		blocks:           BlockSlice{genesisBlock},
+		blocksByHash:     make(map[string]*Block),
+		weights:          map[string]uint64{genesisBlock.Hash: 0},
+		genesis:          genesisBlock,
+		initialStakeAddr: stakeAddr,
+		initialStake:     stakeAmount,
+		minted:           minted,
+		signedBlocks:     make(map[uint64]map[string]*Block),
+		reported:         make(map[string]bool),
+	}
+
+	chain.blocksByHash[genesisBlock.Hash] = genesisBlock
+
 	// Cria contexto com gênesis
-	ctx, err := NewContextWithGenesis(genesisBlock)
+	ctx, err := chain.newGenesisContext()
+	if err != nil {
+		return nil, err
+	}
+	if stakeAddr != "" && stakeAmount > 0 {
+		fmt.Printf("Initial stake applied: %s -> %d tokens staked\n", stakeAddr[:8], stakeAmount)
+	}
+	chain.context = ctx
+
+	return chain, nil
+}
+
+// configureContext aplica ao contexto os parâmetros da chain que não fazem parte do estado
+func (c *Chain) configureContext(ctx *Context) {
+	ctx.SetMinStake(c.config.MinValidatorStake)
+	ctx.SetUnbondingPeriod(c.config.UnbondingPeriod)
+	ctx.SetCoinbaseMaturity(c.config.CoinbaseMaturity)
+	ctx.SetVesting(c.config.Vesting)
+	ctx.SetSlashBasisPoints(c.config.SlashBasisPoints)
+}
+
+// newGenesisContext cria o contexto com o estado após o gênesis e o stake inicial opcional
+func (c *Chain) newGenesisContext() (*Context, error) {
+	ctx, err := NewContextWithGenesis(c.genesis)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create context: %w", err)
 	}
+	c.configureContext(ctx)
 
 	// Aplica stake inicial se fornecido
+	stakeAddr, stakeAmount := c.initialStakeAddr, c.initialStake
 	if stakeAddr != "" && stakeAmount > 0 {
 		// Verificar se o endereço tem saldo suficiente
 		balance := ctx.GetBalance(stakeAddr)
@@ -81,68 +205,173 @@ func NewChainWithStake(genesisBlock *Block, config ChainConfig, stakeAddr string
 		// Aplicar stake inicial (subtrai do saldo e adiciona ao stake)
 		ctx.SetBalance(stakeAddr, balance-stakeAmount)
 		ctx.SetStake(stakeAddr, stakeAmount)
+	}
 
-		fmt.Printf("Initial stake applied: %s -> %d tokens staked\n", stakeAddr[:8], stakeAmount)
+	return ctx, nil
+}
+
+// AddBlock adiciona um novo bloco à chain
+func (c *Chain) AddBlock(block *Block) error {
+	// Um segundo bloco do mesmo validador na mesma altura é denunciado antes de ser rejeitado
+	if evidence := c.checkDoubleSign(block); evidence != nil {
+		c.notifyDoubleSign(evidence)
+		return fmt.Errorf("double sign detected: validator %s already signed another block at height %d",
+			block.Header.ValidatorAddr, block.Header.Height)
 	}
 
-	chain := &Chain{
-		config:       config,
-		blocks:       BlockSlice{genesisBlock},
-		context:      ctx,
-		blocksByHash: make(map[string]*Block),
-		genesis:      genesisBlock,
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	reward, err := c.validateBlockLocked(block, c.blocks[len(c.blocks)-1], c.minted)
+	if err != nil {
+		return err
 	}
 
-	chain.blocksByHash[genesisBlock.Hash] = genesisBlock
+	// Adiciona ao contexto (executa transações)
+	if err := c.context.AddBlock(block); err != nil {
+		return fmt.Errorf("failed to add block to context: %w", err)
+	}
 
-	return chain, nil
+	// Adiciona à chain
+	c.appendBlockLocked(block, reward)
+
+	// Indexa o histórico por endereço (falha no índice não invalida o bloco já aplicado)
+	if c.db != nil {
+		if err := IndexBlockAddresses(c.db, block); err != nil {
+			fmt.Printf("⚠️  Failed to index addresses of block %d: %v\n", block.Header.Height, err)
+		}
+	}
+
+	return nil
 }
 
-// AddBlock adiciona um novo bloco à chain
-func (c *Chain) AddBlock(block *Block) error {
+// AddBlocks adiciona blocos consecutivos (ex.: recebidos na sincronização) de uma só vez.
+// O estado é copiado uma única vez para o lote e o índice de endereços é gravado em uma
+// única escrita no banco. A operação é atômica: se algum bloco for inválido, nenhum é
+// adicionado e a chain permanece como estava.
+func (c *Chain) AddBlocks(blocks []*Block) error {
+	if len(blocks) == 0 {
+		return nil
+	}
+
+	for i, block := range blocks {
+		if evidence := c.checkDoubleSign(block); evidence != nil {
+			c.notifyDoubleSign(evidence)
+			err := fmt.Errorf("double sign detected: validator %s already signed another block at height %d",
+				block.Header.ValidatorAddr, block.Header.Height)
+			return &BatchError{Index: i, Height: block.Header.Height, Err: err}
+		}
+	}
+
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	// Valida cada bloco sobre o anterior do lote
+	lastBlock := c.blocks[len(c.blocks)-1]
+	minted := c.minted
+	rewards := make([]uint64, len(blocks))
+	seen := make(map[string]bool, len(blocks))
+	for i, block := range blocks {
+		if seen[block.Hash] {
+			return &BatchError{Index: i, Height: block.Header.Height, Err: fmt.Errorf("block already exists in batch")}
+		}
+		seen[block.Hash] = true
+
+		reward, err := c.validateBlockLocked(block, lastBlock, minted)
+		if err != nil {
+			return &BatchError{Index: i, Height: block.Header.Height, Err: err}
+		}
+		rewards[i] = reward
+		minted += reward
+		lastBlock = block
+	}
+
+	// Executa todos os blocos; se algum falhar o contexto não é alterado
+	if err := c.context.AddBlocks(blocks); err != nil {
+		return fmt.Errorf("failed to add blocks to context: %w", err)
+	}
+
+	for i, block := range blocks {
+		c.appendBlockLocked(block, rewards[i])
+	}
+
+	// Indexa o histórico por endereço (falha no índice não invalida os blocos já aplicados)
+	if c.db != nil {
+		batch := new(leveldb.Batch)
+		for _, block := range blocks {
+			putAddressIndex(batch, block)
+		}
+		if err := c.db.Write(batch, nil); err != nil {
+			fmt.Printf("⚠️  Failed to index addresses of blocks %d-%d: %v\n",
+				blocks[0].Header.Height, blocks[len(blocks)-1].Header.Height, err)
+		}
+	}
+
+	return nil
+}
+
+// validateBlockLocked valida o bloco como sucessor de lastBlock, com minted tokens já emitidos,
+// e retorna a recompensa do coinbase (deve ser chamado com lock)
+func (c *Chain) validateBlockLocked(block *Block, lastBlock *Block, minted uint64) (uint64, error) {
+	// Limita o tamanho antes de qualquer validação custosa (hash, merkle, assinaturas)
+	if err := c.config.Consensus.CheckBlock(block); err != nil {
+		return 0, err
+	}
+
 	// Valida o bloco
-	if err := block.Validate(); err != nil {
-		return fmt.Errorf("block validation failed: %w", err)
+	if err := block.ValidateAt(c.clock.Now(), c.config.ClockDrift()); err != nil {
+		return 0, fmt.Errorf("block validation failed: %w", err)
+	}
+
+	// Só o validador declarado no header pode ter produzido o bloco
+	if err := block.VerifySignature(); err != nil {
+		return 0, fmt.Errorf("block signature verification failed: %w", err)
 	}
 
 	// Verifica se já existe
 	if _, exists := c.blocksByHash[block.Hash]; exists {
-		return fmt.Errorf("block already exists in chain")
+		return 0, fmt.Errorf("block already exists in chain")
 	}
 
 	// Verifica conexão com a chain
-	lastBlock := c.blocks[len(c.blocks)-1]
 	if block.Header.PreviousHash != lastBlock.Hash {
-		return fmt.Errorf("block does not connect to chain: expected previous hash %s, got %s",
+		return 0, fmt.Errorf("block does not connect to chain: expected previous hash %s, got %s",
 			lastBlock.Hash, block.Header.PreviousHash)
 	}
 
 	if block.Header.Height != lastBlock.Header.Height+1 {
-		return fmt.Errorf("invalid block height: expected %d, got %d",
+		return 0, fmt.Errorf("invalid block height: expected %d, got %d",
 			lastBlock.Header.Height+1, block.Header.Height)
 	}
 
 	// Valida tempo mínimo entre blocos (80% do BlockTime configurado)
 	minBlockTime := int64(c.config.BlockTime.Seconds() * 0.8)
 	if block.Header.Timestamp < lastBlock.Header.Timestamp+minBlockTime {
-		return fmt.Errorf("block mined too fast: timestamp %d < minimum %d (last: %d + %d)",
+		return 0, fmt.Errorf("block mined too fast: timestamp %d < minimum %d (last: %d + %d)",
 			block.Header.Timestamp, lastBlock.Header.Timestamp+minBlockTime,
 			lastBlock.Header.Timestamp, minBlockTime)
 	}
 
-	// Adiciona ao contexto (executa transações)
-	if err := c.context.AddBlock(block); err != nil {
-		return fmt.Errorf("failed to add block to context: %w", err)
+	// A recompensa não pode exceder a do cronograma de halving nem a oferta restante
+	var reward uint64
+	if coinbase := block.GetCoinbaseTransaction(); coinbase != nil {
+		reward = coinbase.Amount
+		if allowed := c.coinbaseRewardFor(block.Header.Height, minted); reward > allowed {
+			return 0, fmt.Errorf("coinbase reward %d exceeds block reward %d at height %d",
+				reward, allowed, block.Header.Height)
+		}
 	}
 
-	// Adiciona à chain
+	return reward, nil
+}
+
+// appendBlockLocked adiciona um bloco já aplicado ao contexto (deve ser chamado com lock)
+func (c *Chain) appendBlockLocked(block *Block, reward uint64) {
 	c.blocks = append(c.blocks, block)
 	c.blocksByHash[block.Hash] = block
-
-	return nil
+	c.weights[block.Hash] = c.weights[block.Header.PreviousHash] + c.context.blockWeight(block.Hash)
+	c.minted += reward
+	c.recordSignedBlock(block)
 }
 
 // GetBlock retorna um bloco pelo hash
@@ -170,6 +399,62 @@ func (c *Chain) GetBlockByHeight(height uint64) (*Block, bool) {
 	return nil, false
 }
 
+// SetDB define o banco consultado por FindTransaction e GetAddressHistory para blocos
+// fora da memória. Os blocos já em memória (ex.: gênesis) entram no índice de endereços.
+func (c *Chain) SetDB(db *leveldb.DB) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.db = db
+
+	for _, block := range c.blocks {
+		if block.IsCheckpointAnchor() {
+			continue
+		}
+		if err := IndexBlockAddresses(db, block); err != nil {
+			return fmt.Errorf("failed to index block %d: %w", block.Header.Height, err)
+		}
+	}
+
+	return nil
+}
+
+// FindTransaction procura uma transação minerada pelo ID, retornando-a junto com a
+// altura do bloco que a contém. Blocos em memória são consultados primeiro; os demais
+// são localizados pelo índice txid -> altura do banco (se configurado via SetDB).
+func (c *Chain) FindTransaction(txID string) (*Transaction, uint64, bool) {
+	c.mu.RLock()
+	db := c.db
+	for i := len(c.blocks) - 1; i >= 0; i-- {
+		block := c.blocks[i]
+		if tx := block.Transactions.FindByID(txID); tx != nil {
+			c.mu.RUnlock()
+			return tx, block.Header.Height, true
+		}
+	}
+	c.mu.RUnlock()
+
+	if db == nil {
+		return nil, 0, false
+	}
+
+	height, err := LoadTransactionHeightFromDB(db, txID)
+	if err != nil {
+		return nil, 0, false
+	}
+	block, err := LoadBlockFromDB(db, height)
+	if err != nil {
+		return nil, 0, false
+	}
+
+	// O índice pode apontar para um bloco substituído em uma reorganização
+	tx := block.Transactions.FindByID(txID)
+	if tx == nil {
+		return nil, 0, false
+	}
+
+	return tx, height, true
+}
+
 // GetLastBlock retorna o último bloco da chain
 func (c *Chain) GetLastBlock() *Block {
 	c.mu.RLock()
@@ -195,9 +480,20 @@ func (c *Chain) GetHeight() uint64 {
 	return c.blocks[len(c.blocks)-1].Header.Height
 }
 
-// GetBalance retorna o saldo de um endereço
+// GetBalance retorna o saldo gastável de um endereço: o saldo sem as recompensas de bloco
+// ainda imaturas (ver GetImmatureBalance)
 func (c *Chain) GetBalance(address string) uint64 {
-	return c.context.GetBalance(address)
+	balance := c.context.GetBalance(address)
+	if immature := c.context.GetImmatureBalance(address); immature < balance {
+		return balance - immature
+	}
+	return 0
+}
+
+// GetImmatureBalance retorna as recompensas de bloco do endereço que ainda não completaram
+// CoinbaseMaturity confirmações e por isso não podem ser gastas no próximo bloco
+func (c *Chain) GetImmatureBalance(address string) uint64 {
+	return c.context.GetImmatureBalance(address)
 }
 
 // GetStake retorna o stake de um endereço
@@ -205,11 +501,31 @@ func (c *Chain) GetStake(address string) uint64 {
 	return c.context.GetStake(address)
 }
 
+// GetPendingUnbonding retorna o valor retirado do stake que ainda não foi liberado como saldo
+func (c *Chain) GetPendingUnbonding(address string) uint64 {
+	return c.context.GetPendingUnbonding(address)
+}
+
+// GetUnbondingReleaseHeight retorna a altura em que o unbonding pendente vira saldo (0 se não houver)
+func (c *Chain) GetUnbondingReleaseHeight(address string) uint64 {
+	return c.context.GetUnbondingReleaseHeight(address)
+}
+
+// GetSlashHeight retorna a altura da última assinatura dupla do endereço punida (0 se nunca foi)
+func (c *Chain) GetSlashHeight(address string) uint64 {
+	return c.context.GetSlashHeight(address)
+}
+
 // GetNonce retorna o nonce de um endereço
 func (c *Chain) GetNonce(address string) uint64 {
 	return c.context.GetNonce(address)
 }
 
+// GetName retorna o nome de exibição registrado para um endereço ("" se não houver)
+func (c *Chain) GetName(address string) string {
+	return c.context.GetName(address)
+}
+
 // GetValidators retorna os validadores ativos
 func (c *Chain) GetValidators() ValidatorList {
 	validators := c.context.GetValidators()
@@ -225,17 +541,119 @@ func (c *Chain) GetValidators() ValidatorList {
 	return filtered
 }
 
+// RewardProjection projeção da produção de blocos e recompensa de um validador
+type RewardProjection struct {
+	Address              string        // Endereço do validador
+	Stake                uint64        // Stake atual do endereço
+	TotalStake           uint64        // Stake total dos validadores ativos
+	StakeShare           float64       // Fração do stake total
+	SelectionProbability float64       // Probabilidade de ser o validador prioritário em cada bloco
+	BlockTime            time.Duration // Tempo entre blocos
+	BlockReward          uint64        // Recompensa por bloco
+}
+
+// ExpectedBlocks retorna o número esperado de blocos produzidos na janela de tempo
+func (p RewardProjection) ExpectedBlocks(window time.Duration) float64 {
+	if p.BlockTime <= 0 {
+		return 0
+	}
+	return p.SelectionProbability * float64(window) / float64(p.BlockTime)
+}
+
+// ExpectedReward retorna a recompensa esperada na janela de tempo (fees são queimadas)
+func (p RewardProjection) ExpectedReward(window time.Duration) float64 {
+	return p.ExpectedBlocks(window) * float64(p.BlockReward)
+}
+
+// PendingReward projeta a taxa de produção de blocos e a recompensa esperada de um
+// endereço com base nos stakes atuais e nos pesos da seleção determinística.
+// Endereços sem stake mínimo de validador têm probabilidade zero.
+func (c *Chain) PendingReward(address string) RewardProjection {
+	validators := c.GetValidators()
+	totalStake := validators.TotalStake()
+
+	projection := RewardProjection{
+		Address:     address,
+		Stake:       c.GetStake(address),
+		TotalStake:  totalStake,
+		BlockTime:   c.config.BlockTime,
+		BlockReward: c.CoinbaseReward(c.GetHeight() + 1),
+	}
+
+	if validator := validators.GetValidator(address); validator != nil && totalStake > 0 {
+		projection.StakeShare = float64(validator.Stake) / float64(totalStake)
+		projection.SelectionProbability = GetSelectionProbability(address, validators)
+	}
+
+	return projection
+}
+
 // ValidateTransaction valida uma transação no contexto atual
 func (c *Chain) ValidateTransaction(tx *Transaction) error {
 	_, err := c.context.ExecuteTransaction(tx)
 	return err
 }
 
+// CurrentBlockReward retorna a recompensa de um bloco na altura informada: BlockReward
+// dividida por dois a cada HalvingInterval blocos, até chegar a zero
+func (c *Chain) CurrentBlockReward(height uint64) uint64 {
+	if c.config.HalvingInterval == 0 {
+		return c.config.BlockReward
+	}
+
+	halvings := height / c.config.HalvingInterval
+	if halvings >= 64 {
+		return 0
+	}
+	return c.config.BlockReward >> halvings
+}
+
+// CoinbaseReward retorna quanto o coinbase de um bloco na altura informada pode emitir:
+// a recompensa do cronograma, truncada ao que falta para MaxSupply (possivelmente zero)
+func (c *Chain) CoinbaseReward(height uint64) uint64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.coinbaseRewardFor(height, c.minted)
+}
+
+// coinbaseRewardFor implementa CoinbaseReward com minted tokens já emitidos
+func (c *Chain) coinbaseRewardFor(height, minted uint64) uint64 {
+	reward := c.CurrentBlockReward(height)
+	if c.config.MaxSupply == 0 {
+		return reward
+	}
+	if minted >= c.config.MaxSupply {
+		return 0
+	}
+	if remaining := c.config.MaxSupply - minted; reward > remaining {
+		return remaining
+	}
+	return reward
+}
+
+// TotalSupply retorna o total de tokens emitidos até o último bloco (gênesis + coinbases).
+// Após restaurar de um checkpoint, o total vem do supply registrado no checkpoint.
+func (c *Chain) TotalSupply() uint64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.minted
+}
+
 // GetConfig retorna a configuração da chain
 func (c *Chain) GetConfig() ChainConfig {
 	return c.config
 }
 
+// Clock retorna o relógio da chain, ajustado pelo horário informado pelos peers
+func (c *Chain) Clock() *NetworkClock {
+	return c.clock
+}
+
+// Now retorna o horário usado pela chain para carimbar e validar blocos
+func (c *Chain) Now() time.Time {
+	return c.clock.Now()
+}
+
 // GetGenesis retorna o bloco gênesis
 func (c *Chain) GetGenesis() *Block {
 	return c.genesis
@@ -256,6 +674,14 @@ func (c *Chain) GetAllBlocksPointer() *BlockSlice {
 	return &c.blocks
 }
 
+// PruneToDepth mantém em memória só os últimos keep blocos, salvando os demais em db antes de
+// removê-los (ver PruneOldBlocks). Diferente de GetAllBlocksPointer, trava a chain.
+func (c *Chain) PruneToDepth(db *leveldb.DB, keep int) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return PruneOldBlocks(db, &c.blocks, keep)
+}
+
 // GetContext retorna o contexto da chain
 func (c *Chain) GetContext() *Context {
 	return c.context
@@ -265,9 +691,66 @@ func (c *Chain) GetContext() *Context {
 func (c *Chain) SetContext(ctx *Context) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
+	c.configureContext(ctx)
 	c.context = ctx
 }
 
+// RestoreFromState substitui o contexto em memória pelo estado de um checkpoint (fast sync),
+// sem reexecutar os blocos anteriores. A chain passa a terminar em um bloco âncora com a
+// altura e o hash do bloco do checkpoint, ao qual os próximos blocos se conectam normalmente.
+// minted é o total emitido até a altura, registrado no checkpoint.
+func (c *Chain) RestoreFromState(height uint64, accounts map[string]*AccountState, minted uint64, blockHash string) error {
+	if height == 0 {
+		return fmt.Errorf("cannot restore state at genesis height")
+	}
+	if blockHash == "" {
+		return fmt.Errorf("checkpoint block hash is required")
+	}
+	if accounts == nil {
+		return fmt.Errorf("accounts map cannot be nil")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.blocks) > 0 {
+		if currentHeight := c.blocks[len(c.blocks)-1].Header.Height; height <= currentHeight {
+			return fmt.Errorf("checkpoint height %d is not ahead of current height %d", height, currentHeight)
+		}
+	}
+
+	ctx := NewContextFromState(height, blockHash, accounts)
+	c.configureContext(ctx)
+
+	// O supply emitido não pode ser menor que o que existe nas contas (taxas e punições
+	// queimadas fazem com que seja maior)
+	var held uint64
+	for _, account := range accounts {
+		if account != nil {
+			held += account.Balance + account.Stake + account.Unbonding
+		}
+	}
+	if minted < held {
+		return fmt.Errorf("checkpoint minted supply %d is below the %d held by accounts", minted, held)
+	}
+	if c.config.MaxSupply > 0 && minted > c.config.MaxSupply {
+		return fmt.Errorf("checkpoint minted supply %d exceeds max supply %d", minted, c.config.MaxSupply)
+	}
+
+	anchor := NewCheckpointAnchorBlock(height, blockHash)
+
+	c.context = ctx
+	c.minted = minted
+	c.blocks = BlockSlice{anchor}
+	c.blocksByHash = map[string]*Block{
+		c.genesis.Hash: c.genesis,
+		blockHash:      anchor,
+	}
+	c.weights = map[string]uint64{blockHash: 0}
+
+	return nil
+}
+
 // GetBlockRange retorna blocos em um intervalo de altura
 func (c *Chain) GetBlockRange(start, end uint64) []*Block {
 	c.mu.RLock()
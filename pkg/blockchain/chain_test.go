@@ -0,0 +1,1101 @@
+package blockchain
+
+import (
+	"fmt"
+	"math"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/krakovia/blockchain/pkg/consensus"
+	"github.com/krakovia/blockchain/pkg/wallet"
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+// Helper: cria chain com limite de tamanho e um mempool com count transações do mesmo remetente
+func createSizeLimitedChain(t *testing.T, maxBlockSize, count int) (*Chain, *Mempool, *wallet.Wallet) {
+	t.Helper()
+
+	w, _ := wallet.NewWallet()
+	dest, _ := wallet.NewWallet()
+
+	config := DefaultChainConfig()
+	config.Consensus.MaxBlockTxs = maxBlockSize
+	config.BlockTime = 100 * time.Millisecond
+
+	genesis := GenesisBlock(NewCoinbaseTransaction(w.GetAddress(), 10000, 0))
+	chain, err := NewChain(genesis, config)
+	if err != nil {
+		t.Fatalf("Failed to create chain: %v", err)
+	}
+
+	mp := NewMempool()
+	for i := 0; i < count; i++ {
+		if err := mp.AddTransaction(newSignedTx(t, w, dest.GetAddress(), 1, uint64(i))); err != nil {
+			t.Fatalf("Failed to add transaction: %v", err)
+		}
+	}
+
+	return chain, mp, w
+}
+
+func TestMinerCapsTransactionsAtMaxBlockSize(t *testing.T) {
+	chain, mp, w := createSizeLimitedChain(t, 3, 5)
+
+	block, err := NewMiner(w, chain, mp).CreateBlock()
+	if err != nil {
+		t.Fatalf("Failed to create block: %v", err)
+	}
+
+	if got := len(block.GetRegularTransactions()); got != 3 {
+		t.Errorf("Expected 3 non-coinbase transactions, got %d", got)
+	}
+}
+
+// MaxBlockTxs não conta a coinbase: um bloco com o limite de transações mais a coinbase é válido
+func TestChainAddBlockAtMaxBlockSize(t *testing.T) {
+	chain, mp, w := createSizeLimitedChain(t, 3, 3)
+
+	block, err := NewMiner(w, chain, mp).CreateBlock()
+	if err != nil {
+		t.Fatalf("Failed to create block: %v", err)
+	}
+	if got := len(block.GetRegularTransactions()); got != 3 {
+		t.Fatalf("Expected block with exactly 3 non-coinbase transactions, got %d", got)
+	}
+	if got := len(block.Transactions); got != 4 {
+		t.Fatalf("Expected coinbase on top of the limit (4 transactions), got %d", got)
+	}
+
+	if err := chain.AddBlock(block); err != nil {
+		t.Errorf("Block at the size limit should be accepted: %v", err)
+	}
+}
+
+func TestChainAddBlockOverMaxBlockSize(t *testing.T) {
+	chain, mp, w := createSizeLimitedChain(t, 3, 4)
+
+	// Monta o bloco manualmente, já que o miner respeita o limite
+	lastBlock := chain.GetLastBlock()
+	txs := TransactionSlice{NewCoinbaseTransaction(w.GetAddress(), chain.GetConfig().BlockReward, 1)}
+	txs = append(txs, mp.GetTransactionsByFee(0)...)
+
+	block := NewBlock(1, lastBlock.Hash, txs, w.GetAddress())
+	block.Header.Timestamp = lastBlock.Header.Timestamp + 1
+	hash, err := block.CalculateHash()
+	if err != nil {
+		t.Fatalf("Failed to calculate hash: %v", err)
+	}
+	block.Hash = hash
+
+	err = chain.AddBlock(block)
+	if err == nil {
+		t.Fatal("Block over the size limit should be rejected")
+	}
+	if !strings.Contains(err.Error(), "exceeds max size") {
+		t.Errorf("Expected max size error, got: %v", err)
+	}
+	if chain.GetHeight() != 0 {
+		t.Errorf("Chain should remain at height 0, got %d", chain.GetHeight())
+	}
+}
+
+// Helper: snapshot do estado de todas as contas (equivalente ao estado de um checkpoint)
+func snapshotAccounts(ctx *Context) map[string]*AccountState {
+	accounts := make(map[string]*AccountState)
+	account := func(addr string) *AccountState {
+		if accounts[addr] == nil {
+			accounts[addr] = &AccountState{Address: addr}
+		}
+		return accounts[addr]
+	}
+
+	for addr, balance := range ctx.GetAllBalances() {
+		account(addr).Balance = balance
+	}
+	for addr, stake := range ctx.GetAllStakes() {
+		account(addr).Stake = stake
+	}
+	for addr, nonce := range ctx.GetAllNonces() {
+		account(addr).Nonce = nonce
+	}
+	for addr, name := range ctx.GetAllNames() {
+		account(addr).Name = name
+	}
+	releases := ctx.GetAllUnbondingReleases()
+	for addr, amount := range ctx.GetAllUnbonding() {
+		account(addr).Unbonding = amount
+		account(addr).UnbondingRelease = releases[addr]
+	}
+	return accounts
+}
+
+func TestChainRestoreFromStateFastSync(t *testing.T) {
+	w, _ := wallet.NewWallet()
+	dest, _ := wallet.NewWallet()
+
+	genesis := GenesisBlock(NewCoinbaseTransaction(w.GetAddress(), 100000, 0))
+	config := DefaultChainConfig()
+
+	chain1, err := NewChain(genesis, config)
+	if err != nil {
+		t.Fatalf("Failed to create chain1: %v", err)
+	}
+	mp := NewMempool()
+	miner := NewMiner(w, chain1, mp)
+
+	// Chain1 minera 510 blocos, com transferências antes e depois do checkpoint
+	var checkpointAccounts map[string]*AccountState
+	var checkpointHash string
+	var checkpointMinted uint64
+	for h := uint64(1); h <= 510; h++ {
+		if h == 3 || h == 505 {
+			tx, err := miner.CreateTransaction(dest.GetAddress(), 100, 1, "")
+			if err != nil {
+				t.Fatalf("Failed to create transaction: %v", err)
+			}
+			if err := mp.AddTransaction(tx); err != nil {
+				t.Fatalf("Failed to add transaction: %v", err)
+			}
+		}
+
+		block, err := miner.CreateBlock()
+		if err != nil {
+			t.Fatalf("Failed to create block %d: %v", h, err)
+		}
+		if err := chain1.AddBlock(block); err != nil {
+			t.Fatalf("Failed to add block %d: %v", h, err)
+		}
+		for _, tx := range block.GetRegularTransactions() {
+			mp.RemoveTransactions([]string{tx.ID})
+		}
+
+		if h == 500 {
+			checkpointAccounts = snapshotAccounts(chain1.GetContext())
+			checkpointHash = block.Hash
+			checkpointMinted = chain1.TotalSupply()
+		}
+	}
+
+	// Chain2 tem apenas o gênesis e recebe o checkpoint em 500 + 10 blocos seguintes
+	chain2, err := NewChain(genesis, config)
+	if err != nil {
+		t.Fatalf("Failed to create chain2: %v", err)
+	}
+
+	if err := chain2.RestoreFromState(500, checkpointAccounts, checkpointMinted, checkpointHash); err != nil {
+		t.Fatalf("Failed to restore from state: %v", err)
+	}
+	if chain2.GetHeight() != 500 {
+		t.Fatalf("Expected height 500 after restore, got %d", chain2.GetHeight())
+	}
+
+	for h := uint64(501); h <= 510; h++ {
+		block, _ := chain1.GetBlockByHeight(h)
+		if err := chain2.AddBlock(block); err != nil {
+			t.Fatalf("Failed to add block %d after restore: %v", h, err)
+		}
+	}
+
+	if chain2.GetHeight() != 510 {
+		t.Errorf("Expected height 510, got %d", chain2.GetHeight())
+	}
+	if chain2.GetLastBlock().Hash != chain1.GetLastBlock().Hash {
+		t.Error("Chains should share the same tip")
+	}
+	// As taxas queimadas não estão nas contas, mas o supply vem do checkpoint
+	if chain2.TotalSupply() != chain1.TotalSupply() {
+		t.Errorf("Supply mismatch: chain1=%d chain2=%d", chain1.TotalSupply(), chain2.TotalSupply())
+	}
+	for _, addr := range []string{w.GetAddress(), dest.GetAddress()} {
+		if chain2.GetBalance(addr) != chain1.GetBalance(addr) {
+			t.Errorf("Balance mismatch for %s: chain1=%d chain2=%d", addr[:8], chain1.GetBalance(addr), chain2.GetBalance(addr))
+		}
+		if chain2.GetNonce(addr) != chain1.GetNonce(addr) {
+			t.Errorf("Nonce mismatch for %s: chain1=%d chain2=%d", addr[:8], chain1.GetNonce(addr), chain2.GetNonce(addr))
+		}
+	}
+
+	// Blocos 1-500 nunca foram vistos pela chain2
+	for _, h := range []uint64{1, 250, 499} {
+		if block, exists := chain2.GetBlockByHeight(h); exists && block.Header.Height == h {
+			t.Errorf("Chain2 should not have block %d", h)
+		}
+	}
+}
+
+func TestChainRestoreFromStateRejectsOlderHeight(t *testing.T) {
+	w, _ := wallet.NewWallet()
+	genesis := GenesisBlock(NewCoinbaseTransaction(w.GetAddress(), 1000, 0))
+	chain, _ := NewChain(genesis, DefaultChainConfig())
+
+	if err := chain.RestoreFromState(0, map[string]*AccountState{}, 1000, "abc"); err == nil {
+		t.Error("Restore at genesis height should fail")
+	}
+	if err := chain.RestoreFromState(10, map[string]*AccountState{}, 1000, ""); err == nil {
+		t.Error("Restore without block hash should fail")
+	}
+	held := map[string]*AccountState{"addr": {Address: "addr", Balance: 900, Stake: 100}}
+	if err := chain.RestoreFromState(10, held, 999, "abc"); err == nil {
+		t.Error("Restore with a supply below the account holdings should fail")
+	}
+	if err := chain.RestoreFromState(10, held, 1200, "abc"); err != nil {
+		t.Fatalf("Restore should succeed: %v", err)
+	}
+	if supply := chain.TotalSupply(); supply != 1200 {
+		t.Errorf("Expected restored supply 1200, got %d", supply)
+	}
+	if err := chain.RestoreFromState(5, map[string]*AccountState{}, 1200, "def"); err == nil {
+		t.Error("Restore to a height behind the chain should fail")
+	}
+}
+
+// Helper: cria chain cujo estado tem os stakes informados
+func createStakedChain(t *testing.T, stakes map[string]uint64) *Chain {
+	t.Helper()
+
+	w, _ := wallet.NewWallet()
+	genesis := GenesisBlock(NewCoinbaseTransaction(w.GetAddress(), 10000, 0))
+	config := DefaultChainConfig()
+	config.BlockTime = time.Second
+
+	chain, err := NewChain(genesis, config)
+	if err != nil {
+		t.Fatalf("Failed to create chain: %v", err)
+	}
+
+	accounts := make(map[string]*AccountState, len(stakes))
+	for addr, stake := range stakes {
+		accounts[addr] = &AccountState{Address: addr, Stake: stake}
+	}
+	chain.context = NewContextFromState(0, genesis.Hash, accounts)
+
+	return chain
+}
+
+func TestPendingRewardHalfStake(t *testing.T) {
+	chain := createStakedChain(t, map[string]uint64{
+		"half":  5000,
+		"other": 5000,
+	})
+
+	projection := chain.PendingReward("half")
+	if projection.StakeShare != 0.5 {
+		t.Errorf("Expected stake share 0.5, got %.4f", projection.StakeShare)
+	}
+
+	// BlockTime de 1s: 3600 blocos por hora, metade para o validador
+	blocks := projection.ExpectedBlocks(time.Hour)
+	if math.Abs(blocks-1800) > 1 {
+		t.Errorf("Expected ~1800 blocks per hour, got %.2f", blocks)
+	}
+	reward := projection.ExpectedReward(time.Hour)
+	if math.Abs(reward-1800*float64(chain.GetConfig().BlockReward)) > 50 {
+		t.Errorf("Expected ~%d reward per hour, got %.2f", 1800*chain.GetConfig().BlockReward, reward)
+	}
+
+	// A soma das projeções de todos os validadores cobre todos os blocos
+	total := projection.ExpectedBlocks(time.Hour) + chain.PendingReward("other").ExpectedBlocks(time.Hour)
+	if math.Abs(total-3600) > 1e-6 {
+		t.Errorf("Projections should sum to 3600 blocks per hour, got %.4f", total)
+	}
+}
+
+func TestPendingRewardNonValidator(t *testing.T) {
+	chain := createStakedChain(t, map[string]uint64{
+		"validator": 5000,
+		"tiny":      10, // abaixo do MinValidatorStake
+	})
+
+	for _, addr := range []string{"tiny", "unknown"} {
+		projection := chain.PendingReward(addr)
+		if projection.SelectionProbability != 0 || projection.ExpectedReward(time.Hour) != 0 {
+			t.Errorf("%s should not project any reward, got %+v", addr, projection)
+		}
+	}
+
+	if p := chain.PendingReward("validator").SelectionProbability; p != 1 {
+		t.Errorf("Single validator should always be selected, got %.4f", p)
+	}
+}
+
+func TestChainValidatorSchedule(t *testing.T) {
+	validator, _ := wallet.NewWallet()
+	chain, block := createUnsignedBlock(t, validator)
+	if err := block.Sign(validator); err != nil {
+		t.Fatalf("Failed to sign block: %v", err)
+	}
+	if err := chain.AddBlock(block); err != nil {
+		t.Fatalf("Failed to add block: %v", err)
+	}
+
+	// Sem validadores ativos não há previsão para a próxima altura
+	if next := chain.NextValidator(); next != "" {
+		t.Errorf("Expected no next validator without stake, got %s", next)
+	}
+
+	stakes := map[string]uint64{"alice": 5000, "bob": 3000, "carol": 2000}
+	accounts := make(map[string]*AccountState, len(stakes))
+	for addr, stake := range stakes {
+		accounts[addr] = &AccountState{Address: addr, Stake: stake}
+	}
+	chain.context = NewContextFromState(1, block.Hash, accounts)
+
+	// Altura 0 vira 1 e o intervalo para na altura seguinte à ponta
+	schedule := chain.ValidatorSchedule(0, 10)
+	if len(schedule) != 2 {
+		t.Fatalf("Expected heights 1 and 2, got %+v", schedule)
+	}
+
+	produced := schedule[0]
+	if produced.Height != 1 || !produced.Produced || produced.Validator != validator.GetAddress() || produced.PreviousHash != block.Header.PreviousHash {
+		t.Errorf("Height 1 should report the block producer, got %+v", produced)
+	}
+
+	predicted := schedule[1]
+	expected := consensus.SelectValidator(2, block.Hash, stakes)
+	if predicted.Height != 2 || predicted.Produced || predicted.PreviousHash != block.Hash || predicted.Validator != expected {
+		t.Errorf("Height 2 should predict %s, got %+v", expected, predicted)
+	}
+	if next := chain.NextValidator(); next != expected {
+		t.Errorf("NextValidator should match the schedule, got %s want %s", next, expected)
+	}
+
+	if schedule := chain.ValidatorSchedule(5, 10); len(schedule) != 0 {
+		t.Errorf("Heights beyond the next block cannot be predicted, got %+v", schedule)
+	}
+}
+
+// Helper: chain vazia e um bloco 1 ainda não assinado produzido por validator
+func createUnsignedBlock(t *testing.T, validator *wallet.Wallet) (*Chain, *Block) {
+	t.Helper()
+
+	config := DefaultChainConfig()
+	config.BlockTime = 100 * time.Millisecond
+
+	genesis := GenesisBlock(NewCoinbaseTransaction(validator.GetAddress(), 10000, 0))
+	chain, err := NewChain(genesis, config)
+	if err != nil {
+		t.Fatalf("Failed to create chain: %v", err)
+	}
+
+	txs := TransactionSlice{NewCoinbaseTransaction(validator.GetAddress(), config.BlockReward, 1)}
+	block := NewBlock(1, genesis.Hash, txs, validator.GetAddress())
+	block.Header.Timestamp = genesis.Header.Timestamp + 1
+
+	return chain, block
+}
+
+func TestChainAddBlockSignedByValidator(t *testing.T) {
+	validator, _ := wallet.NewWallet()
+	chain, block := createUnsignedBlock(t, validator)
+
+	if err := block.Sign(validator); err != nil {
+		t.Fatalf("Failed to sign block: %v", err)
+	}
+
+	if err := chain.AddBlock(block); err != nil {
+		t.Fatalf("Properly signed block should be accepted: %v", err)
+	}
+	if chain.GetHeight() != 1 {
+		t.Errorf("Expected height 1, got %d", chain.GetHeight())
+	}
+}
+
+func TestChainAddBlockRejectsMissingSignature(t *testing.T) {
+	validator, _ := wallet.NewWallet()
+	chain, block := createUnsignedBlock(t, validator)
+
+	hash, err := block.CalculateHash()
+	if err != nil {
+		t.Fatalf("Failed to calculate hash: %v", err)
+	}
+	block.Hash = hash
+
+	err = chain.AddBlock(block)
+	if err == nil || !strings.Contains(err.Error(), "signature is missing") {
+		t.Fatalf("Expected missing signature error, got: %v", err)
+	}
+	if chain.GetHeight() != 0 {
+		t.Errorf("Chain should remain at height 0, got %d", chain.GetHeight())
+	}
+}
+
+func TestChainAddBlockRejectsForgedSignature(t *testing.T) {
+	validator, _ := wallet.NewWallet()
+	attacker, _ := wallet.NewWallet()
+
+	// Atacante assina com a própria chave um bloco que diz ser do validador
+	chain, block := createUnsignedBlock(t, validator)
+	block.Header.PublicKey = attacker.GetPublicKeyHex()
+	hash, err := block.CalculateHash()
+	if err != nil {
+		t.Fatalf("Failed to calculate hash: %v", err)
+	}
+	block.Hash = hash
+	block.Header.Signature, err = attacker.Sign([]byte(hash))
+	if err != nil {
+		t.Fatalf("Failed to sign: %v", err)
+	}
+
+	err = chain.AddBlock(block)
+	if err == nil || !strings.Contains(err.Error(), "does not match validator address") {
+		t.Fatalf("Expected forged block to be rejected, got: %v", err)
+	}
+
+	// Atacante usa a chave pública do validador, mas não consegue produzir a assinatura
+	chain, block = createUnsignedBlock(t, validator)
+	block.Header.PublicKey = validator.GetPublicKeyHex()
+	hash, err = block.CalculateHash()
+	if err != nil {
+		t.Fatalf("Failed to calculate hash: %v", err)
+	}
+	block.Hash = hash
+	block.Header.Signature, err = attacker.Sign([]byte(hash))
+	if err != nil {
+		t.Fatalf("Failed to sign: %v", err)
+	}
+
+	err = chain.AddBlock(block)
+	if err == nil || !strings.Contains(err.Error(), "invalid block signature") {
+		t.Fatalf("Expected invalid signature error, got: %v", err)
+	}
+}
+
+func TestBlockSignRequiresValidatorWallet(t *testing.T) {
+	validator, _ := wallet.NewWallet()
+	other, _ := wallet.NewWallet()
+	_, block := createUnsignedBlock(t, validator)
+
+	if err := block.Sign(other); err == nil {
+		t.Error("Signing with a wallet other than the validator's should fail")
+	}
+}
+
+func TestChainUnstakeUnbondingPeriod(t *testing.T) {
+	staker, _ := wallet.NewWallet()
+	validator, _ := wallet.NewWallet()
+	dest, _ := wallet.NewWallet()
+
+	config := DefaultChainConfig()
+	config.BlockTime = 100 * time.Millisecond
+	config.UnbondingPeriod = 3
+
+	genesis := GenesisBlock(NewCoinbaseTransaction(staker.GetAddress(), 10000, 0))
+	chain, err := NewChainWithStake(genesis, config, staker.GetAddress(), 1000)
+	if err != nil {
+		t.Fatalf("Failed to create chain: %v", err)
+	}
+	addr := staker.GetAddress()
+
+	unstakeData, _ := NewUnstakeData(200).Serialize()
+	unstake := NewTransaction(addr, addr, 200, 1, 0, unstakeData)
+	if err := unstake.Sign(staker); err != nil {
+		t.Fatalf("Failed to sign unstake: %v", err)
+	}
+	mineBlockWith(t, chain, validator, unstake)
+
+	// Bloco 1: stake sai imediatamente, mas o valor fica bloqueado até a altura 4
+	if stake := chain.GetStake(addr); stake != 800 {
+		t.Errorf("Expected stake 800 after unstake, got %d", stake)
+	}
+	if balance := chain.GetBalance(addr); balance != 8999 {
+		t.Errorf("Expected balance 8999 (only fee paid), got %d", balance)
+	}
+	if pending := chain.GetPendingUnbonding(addr); pending != 200 {
+		t.Errorf("Expected 200 pending unbonding, got %d", pending)
+	}
+	if release := chain.GetUnbondingReleaseHeight(addr); release != 4 {
+		t.Errorf("Expected release at height 4, got %d", release)
+	}
+
+	// Gastar o valor em unbonding antes do prazo falha
+	spend := NewTransaction(addr, dest.GetAddress(), 9100, 1, 1, "")
+	if err := spend.Sign(staker); err != nil {
+		t.Fatalf("Failed to sign transfer: %v", err)
+	}
+
+	mineBlockWith(t, chain, validator)
+	if _, err := chain.GetContext().ExecuteTransaction(spend); err == nil {
+		t.Error("Spending unbonding tokens at height 3 should fail")
+	}
+
+	// O estado pendente sobrevive a um checkpoint
+	restored, err := NewChain(genesis, config)
+	if err != nil {
+		t.Fatalf("Failed to create chain: %v", err)
+	}
+	if err := restored.RestoreFromState(2, snapshotAccounts(chain.GetContext()), chain.TotalSupply(), chain.GetLastBlock().Hash); err != nil {
+		t.Fatalf("Failed to restore state: %v", err)
+	}
+	if restored.GetPendingUnbonding(addr) != 200 || restored.GetUnbondingReleaseHeight(addr) != 4 {
+		t.Errorf("Unbonding not restored from checkpoint: pending=%d release=%d",
+			restored.GetPendingUnbonding(addr), restored.GetUnbondingReleaseHeight(addr))
+	}
+
+	mineBlockWith(t, chain, validator)
+	if pending := chain.GetPendingUnbonding(addr); pending != 200 {
+		t.Errorf("Tokens should still be locked at height 3, got pending %d", pending)
+	}
+	if balance := chain.GetBalance(addr); balance != 8999 {
+		t.Errorf("Expected balance 8999 at height 3, got %d", balance)
+	}
+
+	// Bloco 4: o unbonding é liberado antes das transações do bloco, que já podem gastá-lo
+	mineBlockWith(t, chain, validator, spend)
+	if pending := chain.GetPendingUnbonding(addr); pending != 0 {
+		t.Errorf("Expected no pending unbonding after release, got %d", pending)
+	}
+	if release := chain.GetUnbondingReleaseHeight(addr); release != 0 {
+		t.Errorf("Expected no release height after release, got %d", release)
+	}
+	if balance := chain.GetBalance(addr); balance != 8999+200-9100-1 {
+		t.Errorf("Expected balance %d after spending released tokens, got %d", 8999+200-9100-1, balance)
+	}
+	if balance := chain.GetBalance(dest.GetAddress()); balance != 9100 {
+		t.Errorf("Expected recipient balance 9100, got %d", balance)
+	}
+}
+
+func TestChainCoinbaseMaturity(t *testing.T) {
+	owner, _ := wallet.NewWallet()
+	miner, _ := wallet.NewWallet()
+	dest, _ := wallet.NewWallet()
+
+	config := DefaultChainConfig()
+	config.BlockTime = 100 * time.Millisecond
+	config.CoinbaseMaturity = 3
+
+	genesis := GenesisBlock(NewCoinbaseTransaction(owner.GetAddress(), 10000, 0))
+	chain, err := NewChain(genesis, config)
+	if err != nil {
+		t.Fatalf("Failed to create chain: %v", err)
+	}
+	addr := miner.GetAddress()
+	reward := config.BlockReward
+
+	// A alocação do gênesis não é recompensa e pode ser gasta de imediato
+	if immature := chain.GetImmatureBalance(owner.GetAddress()); immature != 0 {
+		t.Errorf("Genesis allocation should not be immature, got %d", immature)
+	}
+
+	// Bloco 1: a recompensa entra no saldo, mas só pode ser gasta a partir do bloco 4
+	mineBlockWith(t, chain, miner)
+	if immature := chain.GetImmatureBalance(addr); immature != reward {
+		t.Errorf("Expected reward %d to be immature, got %d", reward, immature)
+	}
+	if balance := chain.GetBalance(addr); balance != 0 {
+		t.Errorf("Immature reward should not be spendable, got balance %d", balance)
+	}
+
+	spend := NewTransaction(addr, dest.GetAddress(), reward-1, 1, 0, "")
+	if err := spend.Sign(miner); err != nil {
+		t.Fatalf("Failed to sign transfer: %v", err)
+	}
+	if _, err := chain.GetContext().ExecuteTransaction(spend); err == nil || !strings.Contains(err.Error(), "immature coinbase") {
+		t.Errorf("Spending an immature reward should fail, got %v", err)
+	}
+
+	// O minerador não consegue incluir o gasto antes da maturação
+	mineBlockWith(t, chain, miner, spend)
+	if balance := chain.GetBalance(dest.GetAddress()); balance != 0 {
+		t.Errorf("Immature reward was spent at height 2: recipient has %d", balance)
+	}
+
+	// Bloco 3: a recompensa do bloco 1 amadurece para o bloco 4; as dos blocos 2 e 3 não
+	mineBlockWith(t, chain, miner)
+	if immature := chain.GetImmatureBalance(addr); immature != 2*reward {
+		t.Errorf("Expected %d immature after height 3, got %d", 2*reward, immature)
+	}
+	if balance := chain.GetBalance(addr); balance != reward {
+		t.Errorf("Expected the first reward (%d) to be spendable, got %d", reward, balance)
+	}
+
+	// Bloco 4: o gasto entra, e gastar além da parte madura continua falhando
+	overspend := NewTransaction(addr, dest.GetAddress(), reward, 1, 1, "")
+	if err := overspend.Sign(miner); err != nil {
+		t.Fatalf("Failed to sign transfer: %v", err)
+	}
+	mineBlockWith(t, chain, miner, spend, overspend)
+	if balance := chain.GetBalance(dest.GetAddress()); balance != reward-1 {
+		t.Errorf("Expected recipient balance %d after spending the mature reward, got %d", reward-1, balance)
+	}
+	if nonce := chain.GetNonce(addr); nonce != 1 {
+		t.Errorf("Only the mature spend should be mined, got nonce %d", nonce)
+	}
+
+	// Os mesmos blocos aplicados em lote chegam ao mesmo estado
+	replayed, err := NewChain(genesis, config)
+	if err != nil {
+		t.Fatalf("Failed to create chain: %v", err)
+	}
+	if err := replayed.AddBlocks(chain.GetAllBlocks()[1:]); err != nil {
+		t.Fatalf("Failed to replay blocks: %v", err)
+	}
+	if replayed.GetBalance(addr) != chain.GetBalance(addr) || replayed.GetImmatureBalance(addr) != chain.GetImmatureBalance(addr) {
+		t.Errorf("Batch replay diverged: balance %d/%d, immature %d/%d",
+			replayed.GetBalance(addr), chain.GetBalance(addr), replayed.GetImmatureBalance(addr), chain.GetImmatureBalance(addr))
+	}
+}
+
+func TestChainUnstakeWithoutUnbondingPeriod(t *testing.T) {
+	staker, _ := wallet.NewWallet()
+
+	config := DefaultChainConfig()
+	config.BlockTime = 100 * time.Millisecond
+	config.UnbondingPeriod = 0
+
+	genesis := GenesisBlock(NewCoinbaseTransaction(staker.GetAddress(), 10000, 0))
+	chain, err := NewChainWithStake(genesis, config, staker.GetAddress(), 1000)
+	if err != nil {
+		t.Fatalf("Failed to create chain: %v", err)
+	}
+	addr := staker.GetAddress()
+
+	unstakeData, _ := NewUnstakeData(200).Serialize()
+	unstake := NewTransaction(addr, addr, 200, 1, 0, unstakeData)
+	if err := unstake.Sign(staker); err != nil {
+		t.Fatalf("Failed to sign unstake: %v", err)
+	}
+	validator, _ := wallet.NewWallet()
+	mineBlockWith(t, chain, validator, unstake)
+
+	if balance := chain.GetBalance(addr); balance != 9199 {
+		t.Errorf("Expected unstake to be spendable immediately (9199), got %d", balance)
+	}
+	if pending := chain.GetPendingUnbonding(addr); pending != 0 {
+		t.Errorf("Expected no pending unbonding, got %d", pending)
+	}
+}
+
+func TestChainCurrentBlockReward(t *testing.T) {
+	w, _ := wallet.NewWallet()
+
+	config := DefaultChainConfig()
+	config.BlockReward = 50
+	config.HalvingInterval = 100
+
+	chain, err := NewChain(GenesisBlock(NewCoinbaseTransaction(w.GetAddress(), 10000, 0)), config)
+	if err != nil {
+		t.Fatalf("Failed to create chain: %v", err)
+	}
+
+	tests := []struct {
+		height uint64
+		reward uint64
+	}{
+		{0, 50},
+		{99, 50},  // antes do primeiro halving
+		{100, 25}, // primeiro halving
+		{199, 25}, // antes do segundo halving
+		{200, 12}, // segundo halving (arredonda para baixo)
+		{599, 1},
+		{600, 0}, // 50 >> 6: cauda sem recompensa
+		{10000, 0},
+		{^uint64(0), 0},
+	}
+	for _, tt := range tests {
+		if reward := chain.CurrentBlockReward(tt.height); reward != tt.reward {
+			t.Errorf("Reward at height %d: expected %d, got %d", tt.height, tt.reward, reward)
+		}
+	}
+
+	// Sem HalvingInterval a recompensa é constante
+	config.HalvingInterval = 0
+	flat, _ := NewChain(GenesisBlock(NewCoinbaseTransaction(w.GetAddress(), 10000, 0)), config)
+	if reward := flat.CurrentBlockReward(1000000); reward != 50 {
+		t.Errorf("Expected flat reward 50 without halving, got %d", reward)
+	}
+}
+
+func TestChainHalvingCoinbase(t *testing.T) {
+	validator, _ := wallet.NewWallet()
+
+	config := DefaultChainConfig()
+	config.BlockTime = 100 * time.Millisecond
+	config.BlockReward = 4
+	config.HalvingInterval = 2
+
+	genesis := GenesisBlock(NewCoinbaseTransaction(validator.GetAddress(), 10000, 0))
+	chain, err := NewChain(genesis, config)
+	if err != nil {
+		t.Fatalf("Failed to create chain: %v", err)
+	}
+
+	// Alturas 1..7: 4, 2, 2, 1, 1, 0, 0
+	expected := []uint64{4, 2, 2, 1, 1, 0, 0}
+	for i, reward := range expected {
+		mineBlockWith(t, chain, validator)
+		coinbase := chain.GetLastBlock().GetCoinbaseTransaction()
+		if coinbase == nil || coinbase.Amount != reward {
+			t.Fatalf("Block %d: expected coinbase reward %d, got %+v", i+1, reward, coinbase)
+		}
+	}
+	if balance := chain.GetBalance(validator.GetAddress()); balance != 10000+4+2+2+1+1 {
+		t.Errorf("Expected balance %d, got %d", 10000+4+2+2+1+1, balance)
+	}
+
+	// Coinbase acima do cronograma é rejeitado
+	last := chain.GetLastBlock()
+	txs := TransactionSlice{NewCoinbaseTransaction(validator.GetAddress(), 1, last.Header.Height+1)}
+	block := NewBlock(last.Header.Height+1, last.Hash, txs, validator.GetAddress())
+	block.Header.Timestamp = last.Header.Timestamp + 1
+	if err := block.Sign(validator); err != nil {
+		t.Fatalf("Failed to sign block: %v", err)
+	}
+	if err := chain.AddBlock(block); err == nil || !strings.Contains(err.Error(), "exceeds block reward") {
+		t.Errorf("Expected coinbase above schedule to be rejected, got %v", err)
+	}
+}
+
+func TestChainMaxSupply(t *testing.T) {
+	validator, _ := wallet.NewWallet()
+
+	config := DefaultChainConfig()
+	config.BlockTime = 100 * time.Millisecond
+	config.BlockReward = 4
+	config.MaxSupply = 10010
+
+	genesis := GenesisBlock(NewCoinbaseTransaction(validator.GetAddress(), 10000, 0))
+	chain, err := NewChain(genesis, config)
+	if err != nil {
+		t.Fatalf("Failed to create chain: %v", err)
+	}
+	if supply := chain.TotalSupply(); supply != 10000 {
+		t.Fatalf("Expected genesis supply 10000, got %d", supply)
+	}
+
+	// Alturas 1..5: 4, 4, 2 (só a diferença até o teto), 0, 0
+	expected := []uint64{4, 4, 2, 0, 0}
+	for i, reward := range expected {
+		mineBlockWith(t, chain, validator)
+		coinbase := chain.GetLastBlock().GetCoinbaseTransaction()
+		if coinbase == nil || coinbase.Amount != reward {
+			t.Fatalf("Block %d: expected coinbase reward %d, got %+v", i+1, reward, coinbase)
+		}
+	}
+	if supply := chain.TotalSupply(); supply != config.MaxSupply {
+		t.Errorf("Expected total supply %d, got %d", config.MaxSupply, supply)
+	}
+	if balance := chain.GetBalance(validator.GetAddress()); balance != config.MaxSupply {
+		t.Errorf("Expected balance %d, got %d", config.MaxSupply, balance)
+	}
+
+	// Coinbase que ultrapassa o teto é rejeitado
+	last := chain.GetLastBlock()
+	txs := TransactionSlice{NewCoinbaseTransaction(validator.GetAddress(), 1, last.Header.Height+1)}
+	block := NewBlock(last.Header.Height+1, last.Hash, txs, validator.GetAddress())
+	block.Header.Timestamp = last.Header.Timestamp + 1
+	if err := block.Sign(validator); err != nil {
+		t.Fatalf("Failed to sign block: %v", err)
+	}
+	if err := chain.AddBlock(block); err == nil || !strings.Contains(err.Error(), "exceeds block reward") {
+		t.Errorf("Expected coinbase above max supply to be rejected, got %v", err)
+	}
+
+	// Gênesis acima do teto é inválido
+	config.MaxSupply = 9999
+	if _, err := NewChain(genesis, config); err == nil {
+		t.Error("Genesis allocation above max supply should be rejected")
+	}
+}
+
+func TestChainRejectsExpiredTransaction(t *testing.T) {
+	w, _ := wallet.NewWallet()
+	dest, _ := wallet.NewWallet()
+
+	config := DefaultChainConfig()
+	config.BlockTime = 100 * time.Millisecond
+
+	genesis := GenesisBlock(NewCoinbaseTransaction(w.GetAddress(), 10000, 0))
+	chain, err := NewChain(genesis, config)
+	if err != nil {
+		t.Fatalf("Failed to create chain: %v", err)
+	}
+
+	// Válida até a altura 1, mas a chain já está nela
+	tx := NewTransaction(w.GetAddress(), dest.GetAddress(), 10, 1, 0, "")
+	tx.ExpiryHeight = 1
+	if err := tx.Sign(w); err != nil {
+		t.Fatalf("Failed to sign transaction: %v", err)
+	}
+	mineBlockWith(t, chain, w)
+
+	// O miner não inclui a transação expirada
+	mp := NewMempool()
+	mp.AddTransaction(tx)
+	block, err := NewMiner(w, chain, mp).CreateBlock()
+	if err != nil {
+		t.Fatalf("Failed to create block: %v", err)
+	}
+	if len(block.GetRegularTransactions()) != 0 {
+		t.Error("Miner should skip expired transactions")
+	}
+
+	// Um bloco que a inclua é rejeitado
+	last := chain.GetLastBlock()
+	txs := TransactionSlice{NewCoinbaseTransaction(w.GetAddress(), config.BlockReward, last.Header.Height+1), tx}
+	forged := NewBlock(last.Header.Height+1, last.Hash, txs, w.GetAddress())
+	forged.Header.Timestamp = last.Header.Timestamp + 1
+	if err := forged.Sign(w); err != nil {
+		t.Fatalf("Failed to sign block: %v", err)
+	}
+	if err := chain.AddBlock(forged); err == nil || !strings.Contains(err.Error(), "expired") {
+		t.Errorf("Expected block with expired transaction to be rejected, got %v", err)
+	}
+
+	// Alterar a expiração depois de assinar invalida a transação
+	tx.ExpiryHeight = 100
+	if err := tx.Verify(); err == nil {
+		t.Error("ExpiryHeight should be covered by the transaction signature")
+	}
+}
+
+// Helper: gênesis com saldo para accounts contas além do remetente e count blocos consecutivos
+// assinados, cada um com coinbase e uma transferência
+func createBlockSequence(tb testing.TB, count, accounts int) (*Block, ChainConfig, []*Block) {
+	tb.Helper()
+
+	sender, _ := wallet.NewWallet()
+	recipient, _ := wallet.NewWallet()
+	validator, _ := wallet.NewWallet()
+
+	config := DefaultChainConfig()
+	config.BlockTime = 100 * time.Millisecond
+
+	allocations := []GenesisAllocation{{Address: sender.GetAddress(), Amount: uint64(count) * 100}}
+	for i := 0; i < accounts; i++ {
+		holder, _ := wallet.NewWallet()
+		allocations = append(allocations, GenesisAllocation{Address: holder.GetAddress(), Amount: 1000})
+	}
+
+	// Gênesis no passado para que os blocos (um por segundo) não fiquem no futuro
+	genesis, err := GenesisBlockWithAllocations(allocations, time.Now().Unix()-int64(count))
+	if err != nil {
+		tb.Fatalf("Failed to create genesis: %v", err)
+	}
+
+	blocks := make([]*Block, 0, count)
+	previous := genesis
+	for i := 1; i <= count; i++ {
+		height := uint64(i)
+		tx := NewTransaction(sender.GetAddress(), recipient.GetAddress(), 10, 1, height-1, "")
+		if err := tx.Sign(sender); err != nil {
+			tb.Fatalf("Failed to sign transaction: %v", err)
+		}
+
+		txs := TransactionSlice{NewCoinbaseTransaction(validator.GetAddress(), config.BlockReward, height), tx}
+		block := NewBlock(height, previous.Hash, txs, validator.GetAddress())
+		block.Header.Timestamp = genesis.Header.Timestamp + int64(i)
+		if err := block.Sign(validator); err != nil {
+			tb.Fatalf("Failed to sign block: %v", err)
+		}
+
+		blocks = append(blocks, block)
+		previous = block
+	}
+
+	return genesis, config, blocks
+}
+
+// Helper: chain com banco LevelDB configurado
+func createChainWithDB(tb testing.TB, genesis *Block, config ChainConfig) (*Chain, *leveldb.DB) {
+	tb.Helper()
+
+	chain, err := NewChain(genesis, config)
+	if err != nil {
+		tb.Fatalf("Failed to create chain: %v", err)
+	}
+
+	db, err := leveldb.OpenFile(filepath.Join(tb.TempDir(), "chain.db"), nil)
+	if err != nil {
+		tb.Fatalf("Failed to open DB: %v", err)
+	}
+	tb.Cleanup(func() { db.Close() })
+
+	if err := chain.SetDB(db); err != nil {
+		tb.Fatalf("Failed to set DB: %v", err)
+	}
+	return chain, db
+}
+
+func TestChainAddBlocksMatchesIndividualImport(t *testing.T) {
+	genesis, config, blocks := createBlockSequence(t, 20, 0)
+
+	individual, _ := createChainWithDB(t, genesis, config)
+	for _, block := range blocks {
+		if err := individual.AddBlock(block); err != nil {
+			t.Fatalf("Failed to add block %d: %v", block.Header.Height, err)
+		}
+	}
+
+	batched, db := createChainWithDB(t, genesis, config)
+	for start := 0; start < len(blocks); start += 8 {
+		batch := blocks[start:min(start+8, len(blocks))]
+		if err := batched.AddBlocks(batch); err != nil {
+			t.Fatalf("Failed to add batch starting at %d: %v", start, err)
+		}
+		if err := SaveBlocksToDB(db, batch); err != nil {
+			t.Fatalf("Failed to save batch starting at %d: %v", start, err)
+		}
+	}
+
+	if batched.GetHeight() != individual.GetHeight() {
+		t.Fatalf("Expected height %d, got %d", individual.GetHeight(), batched.GetHeight())
+	}
+	if batched.TotalSupply() != individual.TotalSupply() {
+		t.Errorf("Expected total supply %d, got %d", individual.TotalSupply(), batched.TotalSupply())
+	}
+
+	want := snapshotAccounts(individual.GetContext())
+	got := snapshotAccounts(batched.GetContext())
+	for addr, account := range want {
+		if *got[addr] != *account {
+			t.Errorf("Account %s: expected %+v, got %+v", addr, *account, *got[addr])
+		}
+	}
+
+	// O estado histórico por bloco (modificações de cada bloco) também deve ser o mesmo
+	sender := genesis.Transactions[0].To
+	for _, block := range blocks {
+		key := MakeNonceKey(sender)
+		wantMods := individual.GetContext().blocks[block.Hash].Modifications
+		gotMods := batched.GetContext().blocks[block.Hash].Modifications
+		if gotMods[key] != wantMods[key] || len(gotMods) != len(wantMods) {
+			t.Errorf("Block %d: expected modifications %v, got %v", block.Header.Height, wantMods, gotMods)
+		}
+	}
+
+	// Blocos, índice de transações e altura gravados no batch
+	for _, block := range blocks {
+		loaded, err := LoadBlockFromDB(db, block.Header.Height)
+		if err != nil || loaded.Hash != block.Hash {
+			t.Fatalf("Block %d not saved correctly: %v", block.Header.Height, err)
+		}
+	}
+	if tx, height, found := batched.FindTransaction(blocks[3].Transactions[1].ID); !found || height != 4 || tx == nil {
+		t.Errorf("Transaction of block 4 should be indexed, got height %d found %v", height, found)
+	}
+	history, err := batched.GetAddressHistory(sender, 0)
+	if err != nil {
+		t.Fatalf("Failed to get address history: %v", err)
+	}
+	if len(history) != len(blocks)+1 {
+		t.Errorf("Expected %d transactions in sender history, got %d", len(blocks)+1, len(history))
+	}
+}
+
+func TestChainAddBlocksFailingBlockLeavesNoPartialState(t *testing.T) {
+	genesis, config, blocks := createBlockSequence(t, 10, 0)
+	chain, _ := createChainWithDB(t, genesis, config)
+
+	if err := chain.AddBlocks(blocks[:3]); err != nil {
+		t.Fatalf("Failed to add first batch: %v", err)
+	}
+
+	before := snapshotAccounts(chain.GetContext())
+	supply := chain.TotalSupply()
+
+	// Bloco 7 com uma transação adulterada: os blocos 4-6 do lote são válidos
+	tampered := *blocks[6]
+	tampered.Transactions = append(TransactionSlice{}, blocks[6].Transactions...)
+	forged := *tampered.Transactions[1]
+	forged.Amount = 1000
+	tampered.Transactions[1] = &forged
+
+	batch := append(append([]*Block{}, blocks[3:6]...), &tampered)
+	batch = append(batch, blocks[7:]...)
+	if err := chain.AddBlocks(batch); err == nil {
+		t.Fatal("Batch with an invalid block should be rejected")
+	}
+
+	if chain.GetHeight() != 3 {
+		t.Errorf("Height should stay at 3, got %d", chain.GetHeight())
+	}
+	if chain.TotalSupply() != supply {
+		t.Errorf("Total supply should stay at %d, got %d", supply, chain.TotalSupply())
+	}
+	for _, block := range blocks[3:] {
+		if _, exists := chain.GetBlock(block.Hash); exists {
+			t.Errorf("Block %d of the rejected batch should not be in the chain", block.Header.Height)
+		}
+	}
+	after := snapshotAccounts(chain.GetContext())
+	if len(after) != len(before) {
+		t.Errorf("Expected %d accounts, got %d", len(before), len(after))
+	}
+	for addr, account := range before {
+		if *after[addr] != *account {
+			t.Errorf("Account %s changed: expected %+v, got %+v", addr, *account, *after[addr])
+		}
+	}
+	if _, _, found := chain.FindTransaction(blocks[3].Transactions[1].ID); found {
+		t.Error("Transactions of the rejected batch should not be indexed")
+	}
+	sender := genesis.Transactions[0].To
+	if history, _ := chain.GetAddressHistory(sender, 0); len(history) != 4 {
+		t.Errorf("Address index should only contain the first batch, got %d entries", len(history))
+	}
+
+	// Uma falha de execução (não só de validação) no meio do lote também não deixa estado parcial
+	overspend := NewTransaction(sender, sender, 1_000_000, 1, 6, "")
+	failing := NewBlock(7, blocks[5].Hash, TransactionSlice{overspend}, blocks[6].Header.ValidatorAddr)
+	if err := chain.GetContext().AddBlocks([]*Block{blocks[3], blocks[4], blocks[5], failing}); err == nil {
+		t.Fatal("Context batch with a failing transaction should be rejected")
+	}
+	if got := chain.GetContext().GetNonce(sender); got != 3 {
+		t.Errorf("Sender nonce should stay at 3, got %d", got)
+	}
+
+	// O lote corrigido é aceito
+	if err := chain.AddBlocks(blocks[3:]); err != nil {
+		t.Fatalf("Valid batch should be accepted after the rejected one: %v", err)
+	}
+	if chain.GetHeight() != 10 {
+		t.Errorf("Expected height 10, got %d", chain.GetHeight())
+	}
+}
+
+// Importa 1000 blocos sobre um estado com 10000 contas, um a um e em lotes
+func BenchmarkImportBlocks(b *testing.B) {
+	const count = 1000
+	genesis, config, blocks := createBlockSequence(b, count, 10000)
+
+	b.Run("individual", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			b.StopTimer()
+			chain, db := createChainWithDB(b, genesis, config)
+			b.StartTimer()
+
+			for _, block := range blocks {
+				if err := chain.AddBlock(block); err != nil {
+					b.Fatalf("Failed to add block: %v", err)
+				}
+				if err := SaveBlockToDB(db, block); err != nil {
+					b.Fatalf("Failed to save block: %v", err)
+				}
+			}
+		}
+	})
+
+	for _, size := range []int{100, count} {
+		b.Run(fmt.Sprintf("batched-%d", size), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				chain, db := createChainWithDB(b, genesis, config)
+				b.StartTimer()
+
+				for start := 0; start < count; start += size {
+					batch := blocks[start:min(start+size, count)]
+					if err := chain.AddBlocks(batch); err != nil {
+						b.Fatalf("Failed to add batch: %v", err)
+					}
+					if err := SaveBlocksToDB(db, batch); err != nil {
+						b.Fatalf("Failed to save batch: %v", err)
+					}
+				}
+			}
+		})
+	}
+}
@@ -6,12 +6,15 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"sort"
 	"strings"
 
 	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/opt"
+	"github.com/syndtr/goleveldb/leveldb/util"
 )
 
 // Checkpoint representa um snapshot do estado da blockchain em uma determinada altura
@@ -20,15 +23,23 @@ type Checkpoint struct {
 	Timestamp int64             `json:"timestamp"` // Timestamp do checkpoint
 	Accounts  map[string]*AccountState `json:"accounts"`  // Estado de todas as contas
 	Hash      string            `json:"hash"`      // Hash SHA-256 do CSV
+	BlockHash string            `json:"block_hash,omitempty"` // Hash do bloco na altura do checkpoint (coberto pelas assinaturas)
+	Minted    uint64            `json:"minted"`    // Total emitido até a altura (gênesis + coinbases), coberto pelo hash
 	CSV       string            `json:"-"`         // CSV gerado (não serializado em JSON)
+
+	Signatures []CheckpointSignature `json:"signatures,omitempty"` // Endossos de validadores (opcional)
 }
 
 // AccountState representa o estado de uma conta em um checkpoint
 type AccountState struct {
-	Address string `json:"address"` // Endereço da conta
-	Balance uint64 `json:"balance"` // Saldo da conta
-	Stake   uint64 `json:"stake"`   // Stake da conta
-	Nonce   uint64 `json:"nonce"`   // Nonce da conta
+	Address          string `json:"address"`                     // Endereço da conta
+	Balance          uint64 `json:"balance"`                     // Saldo da conta
+	Stake            uint64 `json:"stake"`                       // Stake da conta
+	Nonce            uint64 `json:"nonce"`                       // Nonce da conta
+	Name             string `json:"name,omitempty"`              // Nome de exibição registrado (opcional)
+	Unbonding        uint64 `json:"unbonding,omitempty"`         // Stake retirado aguardando liberação
+	UnbondingRelease uint64 `json:"unbonding_release,omitempty"` // Altura em que o unbonding vira saldo
+	SlashHeight      uint64 `json:"slash_height,omitempty"`      // Altura da última assinatura dupla punida
 }
 
 // CheckpointMetadata contém metadados sobre um checkpoint
@@ -41,7 +52,8 @@ type CheckpointMetadata struct {
 }
 
 // GenerateCheckpointCSV gera um CSV ordenado com o estado de todas as contas
-// Formato: address,balance,stake,nonce
+// Formato: address,balance,stake,nonce[,name[,unbonding,unbonding_release[,slash_height]]]
+// (cada campo opcional só aparece quando ele ou um dos seguintes está presente, vazio ou zero)
 // Ordenado alfabeticamente por address para garantir determinismo
 func GenerateCheckpointCSV(accounts map[string]*AccountState, delimiter string) string {
 	if len(accounts) == 0 {
@@ -59,11 +71,21 @@ func GenerateCheckpointCSV(accounts map[string]*AccountState, delimiter string)
 	var csv strings.Builder
 	for _, addr := range addresses {
 		account := accounts[addr]
-		csv.WriteString(fmt.Sprintf("%s%s%d%s%d%s%d\n",
+		csv.WriteString(fmt.Sprintf("%s%s%d%s%d%s%d",
 			account.Address, delimiter,
 			account.Balance, delimiter,
 			account.Stake, delimiter,
 			account.Nonce))
+		if account.Name != "" || account.Unbonding > 0 || account.SlashHeight > 0 {
+			csv.WriteString(delimiter + account.Name)
+		}
+		if account.Unbonding > 0 || account.SlashHeight > 0 {
+			csv.WriteString(fmt.Sprintf("%s%d%s%d", delimiter, account.Unbonding, delimiter, account.UnbondingRelease))
+		}
+		if account.SlashHeight > 0 {
+			csv.WriteString(fmt.Sprintf("%s%d", delimiter, account.SlashHeight))
+		}
+		csv.WriteString("\n")
 	}
 
 	return csv.String()
@@ -75,13 +97,19 @@ func CalculateCheckpointHash(csv string) string {
 	return hex.EncodeToString(hash[:])
 }
 
-// CreateCheckpoint cria um checkpoint a partir do estado atual
-func CreateCheckpoint(height uint64, timestamp int64, accounts map[string]*AccountState, delimiter string) (*Checkpoint, error) {
+// checkpointSupplyLine gera a linha final do CSV com o total emitido, para que o hash
+// (e as assinaturas sobre ele) cubra também o supply
+func checkpointSupplyLine(minted uint64, delimiter string) string {
+	return fmt.Sprintf("minted%s%d\n", delimiter, minted)
+}
+
+// CreateCheckpoint cria um checkpoint a partir do estado atual, com minted tokens emitidos até a altura
+func CreateCheckpoint(height uint64, timestamp int64, accounts map[string]*AccountState, minted uint64, delimiter string) (*Checkpoint, error) {
 	if accounts == nil {
 		return nil, fmt.Errorf("accounts map cannot be nil")
 	}
 
-	csv := GenerateCheckpointCSV(accounts, delimiter)
+	csv := GenerateCheckpointCSV(accounts, delimiter) + checkpointSupplyLine(minted, delimiter)
 	hash := CalculateCheckpointHash(csv)
 
 	checkpoint := &Checkpoint{
@@ -89,6 +117,7 @@ func CreateCheckpoint(height uint64, timestamp int64, accounts map[string]*Accou
 		Timestamp: timestamp,
 		Accounts:  accounts,
 		Hash:      hash,
+		Minted:    minted,
 		CSV:       csv,
 	}
 
@@ -101,8 +130,8 @@ func ValidateCheckpointHash(checkpoint *Checkpoint, delimiter string) error {
 		return fmt.Errorf("checkpoint cannot be nil")
 	}
 
-	// Regenerar CSV
-	csv := GenerateCheckpointCSV(checkpoint.Accounts, delimiter)
+	// Regenerar CSV (contas + supply)
+	csv := GenerateCheckpointCSV(checkpoint.Accounts, delimiter) + checkpointSupplyLine(checkpoint.Minted, delimiter)
 
 	// Calcular hash
 	calculatedHash := CalculateCheckpointHash(csv)
@@ -267,6 +296,38 @@ func GetLastCheckpointHeight(db *leveldb.DB) (uint64, error) {
 	return height, nil
 }
 
+// ListCheckpointHeights retorna as alturas dos checkpoints salvos no LevelDB, em ordem crescente
+func ListCheckpointHeights(db *leveldb.DB) ([]uint64, error) {
+	if db == nil {
+		return nil, fmt.Errorf("database cannot be nil")
+	}
+
+	iter := db.NewIterator(util.BytesPrefix([]byte("checkpoint-")), nil)
+	defer iter.Release()
+
+	heights := make([]uint64, 0)
+	for iter.Next() {
+		if !strings.HasSuffix(string(iter.Key()), "-metadata") {
+			continue
+		}
+		var metadata CheckpointMetadata
+		if err := json.Unmarshal(iter.Value(), &metadata); err != nil {
+			continue
+		}
+		heights = append(heights, metadata.Height)
+	}
+
+	if err := iter.Error(); err != nil {
+		return nil, fmt.Errorf("failed to iterate checkpoints: %w", err)
+	}
+
+	sort.Slice(heights, func(i, j int) bool {
+		return heights[i] < heights[j]
+	})
+
+	return heights, nil
+}
+
 // PruneOldCheckpoints remove checkpoints antigos, mantendo apenas os últimos N
 func PruneOldCheckpoints(db *leveldb.DB, keepLast int) error {
 	if db == nil {
@@ -285,31 +346,11 @@ func PruneOldCheckpoints(db *leveldb.DB, keepLast int) error {
 		return nil // Nenhum checkpoint para fazer pruning
 	}
 
-	// Coletar todos os checkpoints
-	iter := db.NewIterator(nil, nil)
-	defer iter.Release()
-
-	checkpointHeights := make([]uint64, 0)
-	for iter.Next() {
-		key := string(iter.Key())
-		if strings.HasPrefix(key, "checkpoint-") && strings.HasSuffix(key, "-metadata") {
-			var metadata CheckpointMetadata
-			if err := json.Unmarshal(iter.Value(), &metadata); err != nil {
-				continue
-			}
-			checkpointHeights = append(checkpointHeights, metadata.Height)
-		}
-	}
-
-	if err := iter.Error(); err != nil {
-		return fmt.Errorf("failed to iterate checkpoints: %w", err)
+	checkpointHeights, err := ListCheckpointHeights(db)
+	if err != nil {
+		return err
 	}
 
-	// Ordenar alturas
-	sort.Slice(checkpointHeights, func(i, j int) bool {
-		return checkpointHeights[i] < checkpointHeights[j]
-	})
-
 	// Remover checkpoints antigos
 	if len(checkpointHeights) > keepLast {
 		toRemove := checkpointHeights[:len(checkpointHeights)-keepLast]
@@ -345,7 +386,17 @@ func DeleteCheckpoint(db *leveldb.DB, height uint64) error {
 	return nil
 }
 
-// SaveBlockToDB salva um bloco no LevelDB
+// Chaves da ponta salva da chain: altura e hash do último bloco gravado como ponta
+const (
+	chainHeightKey = "metadata-chain-height"
+	chainTipKey    = "metadata-chain-tip"
+)
+
+// syncWrite força o fsync das escritas de blocos e da ponta: após uma queda, a altura salva
+// nunca aponta para blocos que não chegaram ao disco
+var syncWrite = &opt.WriteOptions{Sync: true}
+
+// SaveBlockToDB salva um bloco no LevelDB como nova ponta da chain
 func SaveBlockToDB(db *leveldb.DB, block *Block) error {
 	if db == nil {
 		return fmt.Errorf("database cannot be nil")
@@ -354,33 +405,107 @@ func SaveBlockToDB(db *leveldb.DB, block *Block) error {
 		return fmt.Errorf("block cannot be nil")
 	}
 
-	// Serializar bloco
-	blockData, err := json.Marshal(block)
-	if err != nil {
-		return fmt.Errorf("failed to marshal block: %w", err)
+	return writeBlocks(db, []*Block{block}, true)
+}
+
+// SaveBlocksToDB salva blocos consecutivos no LevelDB em uma única escrita (batch):
+// ou todos os blocos e seus índices são gravados, ou nenhum. O último vira a ponta salva.
+func SaveBlocksToDB(db *leveldb.DB, blocks []*Block) error {
+	if db == nil {
+		return fmt.Errorf("database cannot be nil")
+	}
+	if len(blocks) == 0 {
+		return nil
 	}
 
-	// Salvar por altura
-	heightKey := fmt.Sprintf("block-%d", block.Header.Height)
-	if err := db.Put([]byte(heightKey), blockData, nil); err != nil {
-		return fmt.Errorf("failed to save block by height: %w", err)
+	return writeBlocks(db, blocks, true)
+}
+
+// writeBlocks grava os blocos e seus índices em uma única escrita sincronizada. Com tip, a
+// altura e o hash do último bloco passam a ser a ponta salva; sem tip (blocos antigos gravados
+// pelo pruning de memória) a ponta não muda.
+func writeBlocks(db *leveldb.DB, blocks []*Block, tip bool) error {
+	batch := new(leveldb.Batch)
+	for _, block := range blocks {
+		if block == nil {
+			return fmt.Errorf("block cannot be nil")
+		}
+
+		blockData, err := json.Marshal(block)
+		if err != nil {
+			return fmt.Errorf("failed to marshal block %d: %w", block.Header.Height, err)
+		}
+
+		heightBytes := []byte(fmt.Sprintf("%d", block.Header.Height))
+		batch.Put([]byte(fmt.Sprintf("block-%d", block.Header.Height)), blockData)
+		batch.Put([]byte(fmt.Sprintf("block-hash-%s", block.Hash)), heightBytes)
+		for _, tx := range block.Transactions {
+			batch.Put([]byte(txIndexKey(tx.ID)), heightBytes)
+		}
+	}
+	if tip {
+		putChainTip(batch, blocks[len(blocks)-1])
 	}
 
-	// Salvar índice por hash
-	hashKey := fmt.Sprintf("block-hash-%s", block.Hash)
-	heightBytes := []byte(fmt.Sprintf("%d", block.Header.Height))
-	if err := db.Put([]byte(hashKey), heightBytes, nil); err != nil {
-		return fmt.Errorf("failed to save block hash index: %w", err)
+	if err := db.Write(batch, syncWrite); err != nil {
+		if len(blocks) == 1 {
+			return fmt.Errorf("failed to save block %d: %w", blocks[0].Header.Height, err)
+		}
+		return fmt.Errorf("failed to save blocks %d-%d: %w",
+			blocks[0].Header.Height, blocks[len(blocks)-1].Header.Height, err)
 	}
 
-	// Atualizar altura da chain
-	if err := db.Put([]byte("metadata-chain-height"), heightBytes, nil); err != nil {
-		return fmt.Errorf("failed to update chain height: %w", err)
+	return nil
+}
+
+// putChainTip registra block como ponta salva da chain
+func putChainTip(batch *leveldb.Batch, block *Block) {
+	batch.Put([]byte(chainHeightKey), []byte(fmt.Sprintf("%d", block.Header.Height)))
+	batch.Put([]byte(chainTipKey), []byte(block.Hash))
+}
+
+// SaveChainTip grava de forma síncrona a altura e o hash de block como ponta salva da chain.
+// O bloco deve já estar no disco.
+func SaveChainTip(db *leveldb.DB, block *Block) error {
+	if db == nil {
+		return fmt.Errorf("database cannot be nil")
+	}
+	if block == nil {
+		return fmt.Errorf("block cannot be nil")
 	}
 
+	batch := new(leveldb.Batch)
+	putChainTip(batch, block)
+	if err := db.Write(batch, syncWrite); err != nil {
+		return fmt.Errorf("failed to save chain tip: %w", err)
+	}
 	return nil
 }
 
+// LoadChainTip retorna a altura e o hash da ponta salva. O hash é vazio em bancos gravados
+// antes de ele ser registrado; sem chain salva retorna leveldb.ErrNotFound.
+func LoadChainTip(db *leveldb.DB) (uint64, string, error) {
+	if db == nil {
+		return 0, "", fmt.Errorf("database cannot be nil")
+	}
+
+	heightData, err := db.Get([]byte(chainHeightKey), nil)
+	if err != nil {
+		return 0, "", fmt.Errorf("failed to load saved chain height: %w", err)
+	}
+	var height uint64
+	if _, err := fmt.Sscanf(string(heightData), "%d", &height); err != nil {
+		return 0, "", fmt.Errorf("failed to parse saved chain height: %w", err)
+	}
+
+	hash, err := db.Get([]byte(chainTipKey), nil)
+	if err != nil && !errors.Is(err, leveldb.ErrNotFound) {
+		return 0, "", fmt.Errorf("failed to load saved chain tip: %w", err)
+	}
+
+	return height, string(hash), nil
+}
+
 // LoadBlockFromDB carrega um bloco do LevelDB pela altura
 func LoadBlockFromDB(db *leveldb.DB, height uint64) (*Block, error) {
 	if db == nil {
@@ -401,6 +526,122 @@ func LoadBlockFromDB(db *leveldb.DB, height uint64) (*Block, error) {
 	return &block, nil
 }
 
+// LoadBlockByHashFromDB carrega um bloco do LevelDB pelo hash (via índice block-hash)
+func LoadBlockByHashFromDB(db *leveldb.DB, hash string) (*Block, error) {
+	if db == nil {
+		return nil, fmt.Errorf("database cannot be nil")
+	}
+
+	hashKey := fmt.Sprintf("block-hash-%s", hash)
+	heightData, err := db.Get([]byte(hashKey), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load block hash index for %s: %w", hash, err)
+	}
+
+	var height uint64
+	if _, err := fmt.Sscanf(string(heightData), "%d", &height); err != nil {
+		return nil, fmt.Errorf("failed to parse block height: %w", err)
+	}
+
+	block, err := LoadBlockFromDB(db, height)
+	if err != nil {
+		return nil, err
+	}
+
+	// A altura pode ter sido sobrescrita por outro bloco (reorganização)
+	if block.Hash != hash {
+		return nil, fmt.Errorf("block at height %d has hash %s, expected %s", height, block.Hash, hash)
+	}
+
+	return block, nil
+}
+
+// IndexBlockTransactions grava o índice txid -> altura para as transações do bloco
+func IndexBlockTransactions(db *leveldb.DB, block *Block) error {
+	if db == nil {
+		return fmt.Errorf("database cannot be nil")
+	}
+	if block == nil {
+		return fmt.Errorf("block cannot be nil")
+	}
+
+	heightBytes := []byte(fmt.Sprintf("%d", block.Header.Height))
+	batch := new(leveldb.Batch)
+	for _, tx := range block.Transactions {
+		batch.Put([]byte(txIndexKey(tx.ID)), heightBytes)
+	}
+	if err := db.Write(batch, nil); err != nil {
+		return fmt.Errorf("failed to save transaction index: %w", err)
+	}
+
+	return nil
+}
+
+// LoadTransactionHeightFromDB retorna a altura do bloco que contém a transação (via índice tx)
+func LoadTransactionHeightFromDB(db *leveldb.DB, txID string) (uint64, error) {
+	if db == nil {
+		return 0, fmt.Errorf("database cannot be nil")
+	}
+
+	heightData, err := db.Get([]byte(txIndexKey(txID)), nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load transaction index for %s: %w", txID, err)
+	}
+
+	var height uint64
+	if _, err := fmt.Sscanf(string(heightData), "%d", &height); err != nil {
+		return 0, fmt.Errorf("failed to parse transaction height: %w", err)
+	}
+
+	return height, nil
+}
+
+// BackfillTransactionIndex indexa as transações dos blocos salvos antes da existência do
+// índice. Roda uma única vez por banco (marcado em metadata-tx-index) e retorna quantos
+// blocos foram indexados. Blocos ausentes (anteriores a um checkpoint) são ignorados.
+func BackfillTransactionIndex(db *leveldb.DB) (int, error) {
+	if db == nil {
+		return 0, fmt.Errorf("database cannot be nil")
+	}
+
+	if _, err := db.Get([]byte(txIndexMetadataKey), nil); err == nil {
+		return 0, nil
+	}
+
+	var savedHeight uint64
+	if heightData, err := db.Get([]byte(chainHeightKey), nil); err == nil {
+		if _, err := fmt.Sscanf(string(heightData), "%d", &savedHeight); err != nil {
+			return 0, fmt.Errorf("failed to parse saved chain height: %w", err)
+		}
+	}
+
+	indexed := 0
+	for height := uint64(0); height <= savedHeight; height++ {
+		block, err := LoadBlockFromDB(db, height)
+		if err != nil {
+			continue
+		}
+		if err := IndexBlockTransactions(db, block); err != nil {
+			return indexed, fmt.Errorf("failed to index block %d: %w", height, err)
+		}
+		indexed++
+	}
+
+	if err := db.Put([]byte(txIndexMetadataKey), []byte("1"), nil); err != nil {
+		return indexed, fmt.Errorf("failed to mark transaction index: %w", err)
+	}
+
+	return indexed, nil
+}
+
+// txIndexMetadataKey marca que o índice de transações já cobre todos os blocos do banco
+const txIndexMetadataKey = "metadata-tx-index"
+
+// txIndexKey chave do índice txid -> altura
+func txIndexKey(txID string) string {
+	return fmt.Sprintf("tx-%s", txID)
+}
+
 // DeleteBlockFromDB remove um bloco do LevelDB
 func DeleteBlockFromDB(db *leveldb.DB, block *Block) error {
 	if db == nil {
@@ -422,6 +663,15 @@ func DeleteBlockFromDB(db *leveldb.DB, block *Block) error {
 		return fmt.Errorf("failed to delete block hash index: %w", err)
 	}
 
+	// Remover índice das transações que ainda apontam para este bloco
+	for _, tx := range block.Transactions {
+		if height, err := LoadTransactionHeightFromDB(db, tx.ID); err == nil && height == block.Header.Height {
+			if err := db.Delete([]byte(txIndexKey(tx.ID)), nil); err != nil {
+				return fmt.Errorf("failed to delete transaction index: %w", err)
+			}
+		}
+	}
+
 	return nil
 }
 
@@ -447,10 +697,18 @@ func PruneOldBlocks(db *leveldb.DB, blocks *BlockSlice, keepInMemory int) error
 	toRemove := len(*blocks) - keepInMemory
 	blocksToRemove := (*blocks)[:toRemove]
 
-	// Salvar blocos no disco antes de remover da memória
+	// Salvar blocos no disco antes de remover da memória, sem mexer na ponta salva (são os
+	// blocos mais antigos da chain)
+	toSave := make([]*Block, 0, len(blocksToRemove))
 	for _, block := range blocksToRemove {
-		if err := SaveBlockToDB(db, block); err != nil {
-			return fmt.Errorf("failed to save block %d to disk: %w", block.Header.Height, err)
+		// Âncoras de checkpoint não são blocos reais
+		if !block.IsCheckpointAnchor() {
+			toSave = append(toSave, block)
+		}
+	}
+	if len(toSave) > 0 {
+		if err := writeBlocks(db, toSave, false); err != nil {
+			return fmt.Errorf("failed to save pruned blocks to disk: %w", err)
 		}
 	}
 
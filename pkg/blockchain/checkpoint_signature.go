@@ -0,0 +1,154 @@
+package blockchain
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/krakovia/blockchain/pkg/wallet"
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+// CheckpointSignature assinatura de um validador endossando o estado de um checkpoint
+type CheckpointSignature struct {
+	Validator string `json:"validator"`  // Endereço do validador
+	PublicKey string `json:"public_key"` // Chave pública do validador
+	Signature string `json:"signature"`  // Assinatura ECDSA de CheckpointSignData
+}
+
+// CheckpointSignData retorna a mensagem assinada pelos validadores. Incluir o hash do gênesis
+// e a altura impede que uma assinatura seja reaproveitada em outra rede ou em outro checkpoint;
+// o hash do bloco na altura do checkpoint fixa a âncora em que o estado restaurado continua.
+func CheckpointSignData(genesisHash string, height uint64, blockHash, hash string) []byte {
+	return []byte(fmt.Sprintf("krakovia-checkpoint:%s:%d:%s:%s", genesisHash, height, blockHash, hash))
+}
+
+// Sign adiciona a assinatura da carteira ao checkpoint
+func (cp *Checkpoint) Sign(w *wallet.Wallet, genesisHash string) error {
+	if cp.BlockHash == "" {
+		return fmt.Errorf("cannot sign checkpoint %d without its block hash", cp.Height)
+	}
+
+	signature, err := w.Sign(CheckpointSignData(genesisHash, cp.Height, cp.BlockHash, cp.Hash))
+	if err != nil {
+		return fmt.Errorf("failed to sign checkpoint: %w", err)
+	}
+
+	_, err = cp.AddSignature(CheckpointSignature{
+		Validator: w.GetAddress(),
+		PublicKey: w.GetPublicKeyHex(),
+		Signature: signature,
+	}, genesisHash)
+	return err
+}
+
+// VerifySignature verifica uma assinatura contra o checkpoint
+func (cp *Checkpoint) VerifySignature(sig CheckpointSignature, genesisHash string) error {
+	address, err := wallet.AddressFromPublicKey(sig.PublicKey)
+	if err != nil {
+		return fmt.Errorf("failed to derive address from public key: %w", err)
+	}
+	if address != sig.Validator {
+		return fmt.Errorf("public key does not match validator %s", sig.Validator)
+	}
+
+	if cp.BlockHash == "" {
+		return fmt.Errorf("checkpoint %d has no block hash", cp.Height)
+	}
+
+	valid, err := wallet.Verify(sig.PublicKey, CheckpointSignData(genesisHash, cp.Height, cp.BlockHash, cp.Hash), sig.Signature)
+	if err != nil {
+		return fmt.Errorf("failed to verify checkpoint signature: %w", err)
+	}
+	if !valid {
+		return fmt.Errorf("invalid checkpoint signature from %s", sig.Validator)
+	}
+
+	return nil
+}
+
+// AddSignature verifica e adiciona uma assinatura. Retorna false se o validador já havia assinado.
+func (cp *Checkpoint) AddSignature(sig CheckpointSignature, genesisHash string) (bool, error) {
+	for _, existing := range cp.Signatures {
+		if existing.Validator == sig.Validator {
+			return false, nil
+		}
+	}
+
+	if err := cp.VerifySignature(sig, genesisHash); err != nil {
+		return false, err
+	}
+
+	cp.Signatures = append(cp.Signatures, sig)
+	return true, nil
+}
+
+// VerifyCheckpointQuorum exige que validadores com pelo menos quorumPercent% do stake conhecido
+// tenham assinado o checkpoint; quorumPercent <= 0 usa a supermaioria padrão (mais de 2/3).
+// O conjunto de validadores é o do nó que verifica (o estado do próprio checkpoint não serve,
+// pois seria fornecido por quem pode estar forjando-o).
+func VerifyCheckpointQuorum(cp *Checkpoint, genesisHash string, validators ValidatorList, quorumPercent int) error {
+	if cp == nil {
+		return fmt.Errorf("checkpoint cannot be nil")
+	}
+
+	totalStake := validators.TotalStake()
+	if totalStake == 0 {
+		return fmt.Errorf("no staked validators known to verify checkpoint endorsement")
+	}
+
+	stakes := make(map[string]uint64, len(validators))
+	for _, v := range validators {
+		stakes[v.Address] = v.Stake
+	}
+
+	var signedStake uint64
+	counted := make(map[string]bool)
+	for _, sig := range cp.Signatures {
+		if counted[sig.Validator] || stakes[sig.Validator] == 0 {
+			continue
+		}
+		if err := cp.VerifySignature(sig, genesisHash); err != nil {
+			continue
+		}
+		counted[sig.Validator] = true
+		signedStake += stakes[sig.Validator]
+	}
+
+	if quorumPercent <= 0 {
+		if signedStake*3 <= totalStake*2 {
+			return fmt.Errorf("checkpoint at height %d endorsed by %d of %d staked tokens (more than 2/3 required)",
+				cp.Height, signedStake, totalStake)
+		}
+		return nil
+	}
+
+	if signedStake*100 < totalStake*uint64(quorumPercent) {
+		return fmt.Errorf("checkpoint at height %d endorsed by %d of %d staked tokens (%d%% required)",
+			cp.Height, signedStake, totalStake, quorumPercent)
+	}
+
+	return nil
+}
+
+// SaveCheckpointSignaturesToDB regrava o estado de um checkpoint já salvo com as assinaturas atuais,
+// sem alterar o último checkpoint registrado
+func SaveCheckpointSignaturesToDB(db *leveldb.DB, checkpoint *Checkpoint) error {
+	if db == nil {
+		return fmt.Errorf("database cannot be nil")
+	}
+	if checkpoint == nil {
+		return fmt.Errorf("checkpoint cannot be nil")
+	}
+
+	stateData, err := json.Marshal(checkpoint)
+	if err != nil {
+		return fmt.Errorf("failed to marshal checkpoint: %w", err)
+	}
+
+	stateKey := fmt.Sprintf("checkpoint-%d-state", checkpoint.Height)
+	if err := db.Put([]byte(stateKey), stateData, nil); err != nil {
+		return fmt.Errorf("failed to save checkpoint signatures: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,212 @@
+package blockchain
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/krakovia/blockchain/pkg/wallet"
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+const testGenesisHash = "genesis-hash"
+
+func TestCheckpointEndorsement(t *testing.T) {
+	v1, _ := wallet.NewWallet()
+	v2, _ := wallet.NewWallet()
+	v3, _ := wallet.NewWallet()
+	outsider, _ := wallet.NewWallet()
+
+	validators := ValidatorList{
+		{Address: v1.GetAddress(), Stake: 600},
+		{Address: v2.GetAddress(), Stake: 300},
+		{Address: v3.GetAddress(), Stake: 100},
+	}
+
+	checkpoint, err := CreateCheckpoint(10, 1000, createTestAccounts(), 3650, ",")
+	if err != nil {
+		t.Fatalf("Failed to create checkpoint: %v", err)
+	}
+	checkpoint.BlockHash = "block-10"
+
+	if err := VerifyCheckpointQuorum(checkpoint, testGenesisHash, validators, 0); err == nil {
+		t.Error("Unsigned checkpoint should be rejected")
+	}
+
+	// 60% do stake não é supermaioria; assinaturas sem stake não contam
+	if err := checkpoint.Sign(v1, testGenesisHash); err != nil {
+		t.Fatalf("Failed to sign checkpoint: %v", err)
+	}
+	if err := checkpoint.Sign(outsider, testGenesisHash); err != nil {
+		t.Fatalf("Failed to sign checkpoint: %v", err)
+	}
+	if err := VerifyCheckpointQuorum(checkpoint, testGenesisHash, validators, 0); err == nil {
+		t.Error("Checkpoint endorsed by 60% of stake should be rejected")
+	}
+
+	// 70% do stake é suficiente
+	if err := checkpoint.Sign(v3, testGenesisHash); err != nil {
+		t.Fatalf("Failed to sign checkpoint: %v", err)
+	}
+	if err := VerifyCheckpointQuorum(checkpoint, testGenesisHash, validators, 0); err != nil {
+		t.Errorf("Checkpoint endorsed by 70%% of stake should be accepted: %v", err)
+	}
+
+	// Sem validadores conhecidos não há como verificar o endosso
+	if err := VerifyCheckpointQuorum(checkpoint, testGenesisHash, ValidatorList{}, 0); err == nil {
+		t.Error("Endorsement cannot be verified without staked validators")
+	}
+}
+
+func TestCheckpointSignatureQuorum(t *testing.T) {
+	v1, _ := wallet.NewWallet()
+	v2, _ := wallet.NewWallet()
+
+	validators := ValidatorList{
+		{Address: v1.GetAddress(), Stake: 600},
+		{Address: v2.GetAddress(), Stake: 300},
+		{Address: "validator-3", Stake: 100},
+	}
+
+	checkpoint, err := CreateCheckpoint(10, 1000, createTestAccounts(), 3650, ",")
+	if err != nil {
+		t.Fatalf("Failed to create checkpoint: %v", err)
+	}
+	checkpoint.BlockHash = "block-10"
+	if err := checkpoint.Sign(v1, testGenesisHash); err != nil {
+		t.Fatalf("Failed to sign checkpoint: %v", err)
+	}
+
+	// 60% do stake atinge um quorum de 60%, mas não um de 90%
+	if err := VerifyCheckpointQuorum(checkpoint, testGenesisHash, validators, 60); err != nil {
+		t.Errorf("Checkpoint endorsed by 60%% of stake should meet a 60%% quorum: %v", err)
+	}
+	if err := VerifyCheckpointQuorum(checkpoint, testGenesisHash, validators, 90); err == nil {
+		t.Error("Checkpoint endorsed by 60% of stake should not meet a 90% quorum")
+	}
+
+	// Com mais um validador, 90% do stake
+	if err := checkpoint.Sign(v2, testGenesisHash); err != nil {
+		t.Fatalf("Failed to sign checkpoint: %v", err)
+	}
+	if err := VerifyCheckpointQuorum(checkpoint, testGenesisHash, validators, 90); err != nil {
+		t.Errorf("Checkpoint endorsed by 90%% of stake should meet a 90%% quorum: %v", err)
+	}
+
+	// As assinaturas cobrem o hash: um estado alterado depois de assinado não bate com ele
+	checkpoint.Accounts["addr1"].Balance = 999999
+	if err := ValidateCheckpointHash(checkpoint, ","); err == nil {
+		t.Error("Checkpoint with tampered state should be rejected")
+	}
+}
+
+func TestCheckpointSignatureReplay(t *testing.T) {
+	validator, _ := wallet.NewWallet()
+	validators := ValidatorList{{Address: validator.GetAddress(), Stake: 1000}}
+
+	checkpoint, _ := CreateCheckpoint(10, 1000, createTestAccounts(), 3650, ",")
+	checkpoint.BlockHash = "block-10"
+	if err := checkpoint.Sign(validator, testGenesisHash); err != nil {
+		t.Fatalf("Failed to sign checkpoint: %v", err)
+	}
+
+	// A mesma assinatura não vale em outra rede
+	if err := VerifyCheckpointQuorum(checkpoint, "other-genesis", validators, 0); err == nil {
+		t.Error("Signature should not be valid for a different genesis")
+	}
+
+	// Nem em outro checkpoint com estado diferente
+	accounts := createTestAccounts()
+	accounts["addr1"].Balance = 999999
+	forged, _ := CreateCheckpoint(10, 1000, accounts, 3650, ",")
+	forged.BlockHash = checkpoint.BlockHash
+	if _, err := forged.AddSignature(checkpoint.Signatures[0], testGenesisHash); err == nil {
+		t.Error("Signature should not be valid for a different checkpoint hash")
+	}
+	forged.Signatures = checkpoint.Signatures
+	if err := VerifyCheckpointQuorum(forged, testGenesisHash, validators, 0); err == nil {
+		t.Error("Copied signatures should not endorse a forged checkpoint")
+	}
+
+	// Nem em outra altura com o mesmo estado
+	otherHeight, _ := CreateCheckpoint(20, 1000, createTestAccounts(), 3650, ",")
+	otherHeight.BlockHash = checkpoint.BlockHash
+	otherHeight.Signatures = checkpoint.Signatures
+	if err := VerifyCheckpointQuorum(otherHeight, testGenesisHash, validators, 0); err == nil {
+		t.Error("Signature should not be valid for a different height")
+	}
+
+	// Nem ancorada em outro bloco com o mesmo estado e altura
+	otherBlock, _ := CreateCheckpoint(10, 1000, createTestAccounts(), 3650, ",")
+	otherBlock.BlockHash = "block-10-fork"
+	if _, err := otherBlock.AddSignature(checkpoint.Signatures[0], testGenesisHash); err == nil {
+		t.Error("Signature should not be valid for a different anchor block")
+	}
+	otherBlock.Signatures = checkpoint.Signatures
+	if err := VerifyCheckpointQuorum(otherBlock, testGenesisHash, validators, 0); err == nil {
+		t.Error("Copied signatures should not endorse a checkpoint on another block")
+	}
+
+	// Checkpoint sem bloco âncora não pode ser assinado
+	unanchored, _ := CreateCheckpoint(10, 1000, createTestAccounts(), 3650, ",")
+	if err := unanchored.Sign(validator, testGenesisHash); err == nil {
+		t.Error("Checkpoint without a block hash should not be signable")
+	}
+
+	// Assinatura atribuída a outro validador é rejeitada
+	impostor := checkpoint.Signatures[0]
+	impostor.Validator = "someone-else"
+	if _, err := checkpoint.AddSignature(impostor, testGenesisHash); err == nil {
+		t.Error("Signature with mismatched validator should be rejected")
+	}
+
+	// Assinar de novo não duplica
+	if err := checkpoint.Sign(validator, testGenesisHash); err != nil {
+		t.Fatalf("Failed to re-sign checkpoint: %v", err)
+	}
+	if len(checkpoint.Signatures) != 1 {
+		t.Errorf("Expected 1 signature, got %d", len(checkpoint.Signatures))
+	}
+}
+
+func TestSaveCheckpointSignaturesToDB(t *testing.T) {
+	db, err := leveldb.OpenFile(filepath.Join(t.TempDir(), "signatures.db"), nil)
+	if err != nil {
+		t.Fatalf("Failed to open DB: %v", err)
+	}
+	defer db.Close()
+
+	validator, _ := wallet.NewWallet()
+
+	older, _ := CreateCheckpoint(10, 1000, createTestAccounts(), 3650, ",")
+	newer, _ := CreateCheckpoint(20, 2000, createTestAccounts(), 3650, ",")
+	older.BlockHash = "block-10"
+	newer.BlockHash = "block-20"
+	for _, cp := range []*Checkpoint{older, newer} {
+		if err := SaveCheckpointToDB(db, cp, true); err != nil {
+			t.Fatalf("Failed to save checkpoint: %v", err)
+		}
+	}
+
+	if err := older.Sign(validator, testGenesisHash); err != nil {
+		t.Fatalf("Failed to sign checkpoint: %v", err)
+	}
+	if err := SaveCheckpointSignaturesToDB(db, older); err != nil {
+		t.Fatalf("Failed to save signatures: %v", err)
+	}
+
+	loaded, err := LoadCheckpointFromDB(db, 10)
+	if err != nil {
+		t.Fatalf("Failed to load checkpoint: %v", err)
+	}
+	if len(loaded.Signatures) != 1 || loaded.Signatures[0].Validator != validator.GetAddress() {
+		t.Errorf("Expected the validator signature to be persisted, got %+v", loaded.Signatures)
+	}
+	if loaded.CSV != older.CSV {
+		t.Error("Checkpoint CSV should be preserved")
+	}
+
+	// Atualizar assinaturas de um checkpoint antigo não muda o último checkpoint
+	if last, _ := GetLastCheckpointHeight(db); last != 20 {
+		t.Errorf("Expected last checkpoint to remain 20, got %d", last)
+	}
+}
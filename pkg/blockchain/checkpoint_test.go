@@ -51,6 +51,32 @@ func TestGenerateCheckpointCSV(t *testing.T) {
 	}
 }
 
+// TestGenerateCheckpointCSV_WithName testa que o nome registrado entra como coluna extra
+func TestGenerateCheckpointCSV_WithName(t *testing.T) {
+	accounts := createTestAccounts()
+	accounts["addr1"].Name = "Pool One"
+
+	csv := GenerateCheckpointCSV(accounts, ",")
+
+	expected := "addr1,1000,100,5,Pool One\naddr2,2000,0,3\naddr3,500,50,1\n"
+	if csv != expected {
+		t.Errorf("CSV mismatch.\nExpected:\n%s\nGot:\n%s", expected, csv)
+	}
+}
+
+// TestGenerateCheckpointCSV_WithSlashHeight testa que a altura punida entra depois das colunas opcionais
+func TestGenerateCheckpointCSV_WithSlashHeight(t *testing.T) {
+	accounts := createTestAccounts()
+	accounts["addr3"].SlashHeight = 7
+
+	csv := GenerateCheckpointCSV(accounts, ",")
+
+	expected := "addr1,1000,100,5\naddr2,2000,0,3\naddr3,500,50,1,,0,0,7\n"
+	if csv != expected {
+		t.Errorf("CSV mismatch.\nExpected:\n%s\nGot:\n%s", expected, csv)
+	}
+}
+
 // TestGenerateCheckpointCSV_Empty testa CSV com mapa vazio
 func TestGenerateCheckpointCSV_Empty(t *testing.T) {
 	accounts := make(map[string]*AccountState)
@@ -114,7 +140,7 @@ func TestCreateCheckpoint(t *testing.T) {
 	timestamp := int64(1234567890)
 	delimiter := ","
 
-	checkpoint, err := CreateCheckpoint(height, timestamp, accounts, delimiter)
+	checkpoint, err := CreateCheckpoint(height, timestamp, accounts, 3650, delimiter)
 	if err != nil {
 		t.Fatalf("Failed to create checkpoint: %v", err)
 	}
@@ -139,7 +165,7 @@ func TestCreateCheckpoint(t *testing.T) {
 
 // TestCreateCheckpoint_NilAccounts testa erro ao criar checkpoint com contas nil
 func TestCreateCheckpoint_NilAccounts(t *testing.T) {
-	_, err := CreateCheckpoint(100, 1234567890, nil, ",")
+	_, err := CreateCheckpoint(100, 1234567890, nil, 0, ",")
 	if err == nil {
 		t.Error("Expected error for nil accounts")
 	}
@@ -148,7 +174,7 @@ func TestCreateCheckpoint_NilAccounts(t *testing.T) {
 // TestValidateCheckpointHash testa a validação de hash
 func TestValidateCheckpointHash(t *testing.T) {
 	accounts := createTestAccounts()
-	checkpoint, _ := CreateCheckpoint(100, 1234567890, accounts, ",")
+	checkpoint, _ := CreateCheckpoint(100, 1234567890, accounts, 3650, ",")
 
 	err := ValidateCheckpointHash(checkpoint, ",")
 	if err != nil {
@@ -159,7 +185,7 @@ func TestValidateCheckpointHash(t *testing.T) {
 // TestValidateCheckpointHash_Invalid testa detecção de hash inválido
 func TestValidateCheckpointHash_Invalid(t *testing.T) {
 	accounts := createTestAccounts()
-	checkpoint, _ := CreateCheckpoint(100, 1234567890, accounts, ",")
+	checkpoint, _ := CreateCheckpoint(100, 1234567890, accounts, 3650, ",")
 
 	// Corromper hash
 	checkpoint.Hash = "invalid_hash"
@@ -170,6 +196,16 @@ func TestValidateCheckpointHash_Invalid(t *testing.T) {
 	}
 }
 
+// TestValidateCheckpointHash_TamperedSupply testa que o supply emitido é coberto pelo hash
+func TestValidateCheckpointHash_TamperedSupply(t *testing.T) {
+	checkpoint, _ := CreateCheckpoint(100, 1234567890, createTestAccounts(), 3650, ",")
+
+	checkpoint.Minted = 1000000
+	if err := ValidateCheckpointHash(checkpoint, ","); err == nil {
+		t.Error("Expected error for tampered minted supply")
+	}
+}
+
 // TestValidateCheckpointHash_NilCheckpoint testa erro ao validar checkpoint nil
 func TestValidateCheckpointHash_NilCheckpoint(t *testing.T) {
 	err := ValidateCheckpointHash(nil, ",")
@@ -199,7 +235,7 @@ func TestSaveAndLoadCheckpoint(t *testing.T) {
 
 	// Criar checkpoint
 	accounts := createTestAccounts()
-	checkpoint, _ := CreateCheckpoint(100, 1234567890, accounts, ",")
+	checkpoint, _ := CreateCheckpoint(100, 1234567890, accounts, 3650, ",")
 
 	// Salvar
 	err = SaveCheckpointToDB(db, checkpoint, false)
@@ -247,7 +283,7 @@ func TestSaveAndLoadCheckpoint_Compressed(t *testing.T) {
 	}()
 
 	accounts := createTestAccounts()
-	checkpoint, _ := CreateCheckpoint(100, 1234567890, accounts, ",")
+	checkpoint, _ := CreateCheckpoint(100, 1234567890, accounts, 3650, ",")
 
 	// Salvar com compressão
 	err = SaveCheckpointToDB(db, checkpoint, true)
@@ -296,7 +332,7 @@ func TestGetLastCheckpointHeight(t *testing.T) {
 
 	// Salvar checkpoint
 	accounts := createTestAccounts()
-	checkpoint, _ := CreateCheckpoint(100, 1234567890, accounts, ",")
+	checkpoint, _ := CreateCheckpoint(100, 1234567890, accounts, 3650, ",")
 	if err := SaveCheckpointToDB(db, checkpoint, false); err != nil {
 		t.Fatalf("Failed to save checkpoint: %v", err)
 	}
@@ -332,7 +368,7 @@ func TestPruneOldCheckpoints(t *testing.T) {
 	// Criar 5 checkpoints
 	accounts := createTestAccounts()
 	for i := 0; i < 5; i++ {
-		checkpoint, _ := CreateCheckpoint(uint64(i*100), 1234567890, accounts, ",")
+		checkpoint, _ := CreateCheckpoint(uint64(i*100), 1234567890, accounts, 3650, ",")
 		if err := SaveCheckpointToDB(db, checkpoint, false); err != nil {
 			t.Fatalf("Failed to save checkpoint: %v", err)
 		}
@@ -380,7 +416,7 @@ func TestDeleteCheckpoint(t *testing.T) {
 
 	// Criar checkpoint
 	accounts := createTestAccounts()
-	checkpoint, _ := CreateCheckpoint(100, 1234567890, accounts, ",")
+	checkpoint, _ := CreateCheckpoint(100, 1234567890, accounts, 3650, ",")
 	if err := SaveCheckpointToDB(db, checkpoint, false); err != nil {
 		t.Fatalf("Failed to save checkpoint: %v", err)
 	}
@@ -538,8 +574,8 @@ func TestPruneBlocksBeforeCheckpoint(t *testing.T) {
 
 	// Criar 2 checkpoints (altura 10 e 15)
 	accounts := createTestAccounts()
-	checkpoint1, _ := CreateCheckpoint(10, 1234567890, accounts, ",")
-	checkpoint2, _ := CreateCheckpoint(15, 1234567891, accounts, ",")
+	checkpoint1, _ := CreateCheckpoint(10, 1234567890, accounts, 3650, ",")
+	checkpoint2, _ := CreateCheckpoint(15, 1234567891, accounts, 3650, ",")
 	if err := SaveCheckpointToDB(db, checkpoint1, false); err != nil {
 		t.Fatalf("Failed to save checkpoint1: %v", err)
 	}
@@ -0,0 +1,92 @@
+package blockchain
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultMaxClockDrift tolerância padrão para timestamps no futuro
+const DefaultMaxClockDrift = 5 * time.Minute
+
+// NetworkClock relógio do nó ajustado pela mediana do horário informado pelos peers. Cada peer
+// contribui com uma amostra (a diferença entre o horário dele e o local) e o próprio nó conta
+// como uma amostra de diferença zero. O ajuste é limitado a maxOffset, para que peers
+// mentindo sobre o horário não consigam empurrar o relógio para longe.
+type NetworkClock struct {
+	mu        sync.RWMutex
+	samples   map[string]int64 // Diferença em segundos entre o horário do peer e o local
+	offset    int64            // Ajuste aplicado ao horário local, em segundos
+	maxOffset int64
+	now       func() time.Time // Horário local (substituído nos testes para simular um relógio errado)
+}
+
+// NewNetworkClock cria um relógio sem ajuste que aceita ajustes de até maxOffset
+func NewNetworkClock(maxOffset time.Duration) *NetworkClock {
+	return &NetworkClock{
+		samples:   make(map[string]int64),
+		maxOffset: int64(maxOffset.Seconds()),
+		now:       time.Now,
+	}
+}
+
+// Now retorna o horário local ajustado pelo offset da rede
+func (c *NetworkClock) Now() time.Time {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.now().Add(time.Duration(c.offset) * time.Second)
+}
+
+// Offset retorna o ajuste aplicado ao horário local
+func (c *NetworkClock) Offset() time.Duration {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return time.Duration(c.offset) * time.Second
+}
+
+// AddSample registra o horário (unix, em segundos) informado por um peer e recalcula o
+// offset. Retorna o offset resultante.
+func (c *NetworkClock) AddSample(peerID string, peerTime int64) time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.samples[peerID] = peerTime - c.now().Unix()
+	c.recalculateLocked()
+	return time.Duration(c.offset) * time.Second
+}
+
+// RemoveSample descarta a amostra de um peer desconectado e recalcula o offset
+func (c *NetworkClock) RemoveSample(peerID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.samples[peerID]; !ok {
+		return
+	}
+	delete(c.samples, peerID)
+	c.recalculateLocked()
+}
+
+// recalculateLocked define o offset como a mediana das amostras, limitada a maxOffset (deve
+// ser chamado com lock)
+func (c *NetworkClock) recalculateLocked() {
+	offsets := make([]int64, 0, len(c.samples)+1)
+	offsets = append(offsets, 0) // O próprio nó
+	for _, offset := range c.samples {
+		offsets = append(offsets, offset)
+	}
+	sort.Slice(offsets, func(i, j int) bool { return offsets[i] < offsets[j] })
+
+	mid := len(offsets) / 2
+	median := offsets[mid]
+	if len(offsets)%2 == 0 {
+		median = (offsets[mid-1] + offsets[mid]) / 2
+	}
+
+	if median > c.maxOffset {
+		median = c.maxOffset
+	} else if median < -c.maxOffset {
+		median = -c.maxOffset
+	}
+	c.offset = median
+}
@@ -0,0 +1,82 @@
+package blockchain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/krakovia/blockchain/pkg/wallet"
+)
+
+func TestNetworkClockUsesMedianOfPeers(t *testing.T) {
+	clock := NewNetworkClock(time.Hour)
+	now := time.Now().Unix()
+
+	// Um único peer mentindo não move a mediana quando os outros concordam com o nó
+	clock.AddSample("honest-1", now)
+	clock.AddSample("honest-2", now)
+	if offset := clock.AddSample("liar", now+3000); offset != 0 {
+		t.Errorf("A single outlier should not move the clock, offset %v", offset)
+	}
+
+	// Com a maioria adiantada 60s, o relógio acompanha a rede
+	clock.AddSample("honest-1", now+60)
+	if offset := clock.AddSample("honest-2", now+60); offset != time.Minute {
+		t.Errorf("Expected offset 1m, got %v", offset)
+	}
+
+	// Peers desconectados deixam de contar
+	clock.RemoveSample("honest-1")
+	clock.RemoveSample("honest-2")
+	clock.RemoveSample("liar")
+	if offset := clock.Offset(); offset != 0 {
+		t.Errorf("Expected offset 0 without peers, got %v", offset)
+	}
+}
+
+func TestSkewedNodeProducesAcceptableTimestampsAfterAdjustment(t *testing.T) {
+	validator, _ := wallet.NewWallet()
+	genesis := GenesisBlock(NewCoinbaseTransaction(validator.GetAddress(), 10000, 0))
+
+	config := DefaultChainConfig()
+	config.BlockTime = 100 * time.Millisecond
+	config.MaxClockDrift = 2 * time.Minute
+
+	peer, err := NewChainWithStake(genesis, config, validator.GetAddress(), 1000)
+	if err != nil {
+		t.Fatalf("Failed to create peer chain: %v", err)
+	}
+	skewed, err := NewChainWithStake(genesis, config, validator.GetAddress(), 1000)
+	if err != nil {
+		t.Fatalf("Failed to create skewed chain: %v", err)
+	}
+
+	// Relógio do nó 3 minutos adiantado, além da tolerância dos peers
+	skewed.clock.now = func() time.Time { return time.Now().Add(3 * time.Minute) }
+	miner := NewMiner(validator, skewed, NewMempool())
+
+	block, err := miner.CreateBlock()
+	if err != nil {
+		t.Fatalf("Failed to create block: %v", err)
+	}
+	if err := peer.AddBlock(block); err == nil {
+		t.Fatal("Block stamped by the unadjusted clock should be too far in the future")
+	}
+
+	// Dois peers informam o horário real: o ajuste é limitado a MaxClockDrift
+	skewed.Clock().AddSample("peer-1", time.Now().Unix())
+	offset := skewed.Clock().AddSample("peer-2", time.Now().Unix())
+	if offset != -2*time.Minute {
+		t.Fatalf("Expected offset clamped to -2m, got %v", offset)
+	}
+
+	block, err = miner.CreateBlock()
+	if err != nil {
+		t.Fatalf("Failed to create block after adjustment: %v", err)
+	}
+	if err := peer.AddBlock(block); err != nil {
+		t.Fatalf("Block stamped by the adjusted clock should be accepted: %v", err)
+	}
+	if err := skewed.AddBlock(block); err != nil {
+		t.Errorf("Skewed node should accept its own adjusted block: %v", err)
+	}
+}
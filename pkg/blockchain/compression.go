@@ -0,0 +1,77 @@
+package blockchain
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+)
+
+// PayloadGzip é o byte que prefixa payloads comprimidos com gzip. Payloads JSON nunca começam
+// com ele, então dados sem o prefixo (peers que não comprimem) continuam sendo lidos como JSON.
+const PayloadGzip byte = 0x01
+
+// MaxDecompressedPayloadSize limita o tamanho de um payload descomprimido (proteção contra
+// payloads que expandem demais)
+const MaxDecompressedPayloadSize = 64 * 1024 * 1024
+
+// CompressPayload comprime um payload serializado com gzip, prefixado pelo byte PayloadGzip
+func CompressPayload(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte(PayloadGzip)
+
+	writer := gzip.NewWriter(&buf)
+	if _, err := writer.Write(data); err != nil {
+		return nil, fmt.Errorf("failed to compress payload: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("failed to compress payload: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// IsCompressedPayload verifica se o payload tem o prefixo de compressão
+func IsCompressedPayload(data []byte) bool {
+	return len(data) > 0 && data[0] == PayloadGzip
+}
+
+// DecompressPayload retorna o payload original. Payloads sem o prefixo de compressão são
+// retornados como estão.
+func DecompressPayload(data []byte) ([]byte, error) {
+	if !IsCompressedPayload(data) {
+		return data, nil
+	}
+
+	reader, err := gzip.NewReader(bytes.NewReader(data[1:]))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress payload: %w", err)
+	}
+	defer reader.Close()
+
+	decompressed, err := io.ReadAll(io.LimitReader(reader, MaxDecompressedPayloadSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress payload: %w", err)
+	}
+	if len(decompressed) > MaxDecompressedPayloadSize {
+		return nil, fmt.Errorf("decompressed payload exceeds %d bytes", MaxDecompressedPayloadSize)
+	}
+	return decompressed, nil
+}
+
+// SerializeCompressed serializa o bloco para JSON comprimido (prefixado por PayloadGzip)
+func (b *Block) SerializeCompressed() ([]byte, error) {
+	data, err := b.Serialize()
+	if err != nil {
+		return nil, err
+	}
+	return CompressPayload(data)
+}
+
+// SerializeCompressed serializa a transação para JSON comprimido (prefixado por PayloadGzip)
+func (tx *Transaction) SerializeCompressed() ([]byte, error) {
+	data, err := tx.Serialize()
+	if err != nil {
+		return nil, err
+	}
+	return CompressPayload(data)
+}
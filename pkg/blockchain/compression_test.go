@@ -0,0 +1,79 @@
+package blockchain
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+func TestBlockSerializeCompressedRoundTrip(t *testing.T) {
+	txs := TransactionSlice{NewCoinbaseTransaction("validator_addr", 50, 1)}
+	for i := 0; i < 50; i++ {
+		txs = append(txs, NewTransaction("sender_addr", fmt.Sprintf("recipient_%d", i), 10, 1, uint64(i), "payment"))
+	}
+	block := NewBlock(1, "prev_hash", txs, "validator_addr")
+
+	raw, err := block.Serialize()
+	if err != nil {
+		t.Fatalf("Failed to serialize block: %v", err)
+	}
+	compressed, err := block.SerializeCompressed()
+	if err != nil {
+		t.Fatalf("Failed to serialize compressed block: %v", err)
+	}
+
+	if !IsCompressedPayload(compressed) || IsCompressedPayload(raw) {
+		t.Fatal("Only the compressed payload should carry the compression header")
+	}
+	if len(compressed) >= len(raw) {
+		t.Errorf("Compressed block (%d bytes) should be smaller than raw (%d bytes)", len(compressed), len(raw))
+	}
+
+	// Os dois formatos desserializam para o mesmo bloco
+	for name, data := range map[string][]byte{"raw": raw, "compressed": compressed} {
+		decoded, err := DeserializeBlock(data)
+		if err != nil {
+			t.Fatalf("Failed to deserialize %s block: %v", name, err)
+		}
+		if decoded.Hash != block.Hash || len(decoded.Transactions) != len(block.Transactions) {
+			t.Errorf("%s block changed after round trip", name)
+		}
+	}
+}
+
+func TestTransactionSerializeCompressedRoundTrip(t *testing.T) {
+	tx := NewTransaction("sender_addr", "recipient_addr", 100, 1, 1, "hello")
+
+	raw, err := tx.Serialize()
+	if err != nil {
+		t.Fatalf("Failed to serialize transaction: %v", err)
+	}
+	compressed, err := tx.SerializeCompressed()
+	if err != nil {
+		t.Fatalf("Failed to serialize compressed transaction: %v", err)
+	}
+
+	for name, data := range map[string][]byte{"raw": raw, "compressed": compressed} {
+		decoded, err := DeserializeTransaction(data)
+		if err != nil {
+			t.Fatalf("Failed to deserialize %s transaction: %v", name, err)
+		}
+		if decoded.ID != tx.ID || decoded.Amount != tx.Amount || decoded.Data != tx.Data {
+			t.Errorf("%s transaction changed after round trip", name)
+		}
+	}
+
+	decompressed, err := DecompressPayload(compressed)
+	if err != nil || !bytes.Equal(decompressed, raw) {
+		t.Errorf("Decompressed payload should equal the raw JSON (%v)", err)
+	}
+}
+
+func TestDecompressPayloadRejectsCorruptData(t *testing.T) {
+	if _, err := DecompressPayload([]byte{PayloadGzip, 'n', 'o', 't', 'g', 'z'}); err == nil {
+		t.Error("Corrupt compressed payload should fail")
+	}
+	if _, err := DeserializeBlock([]byte{PayloadGzip}); err == nil {
+		t.Error("Truncated compressed block should fail")
+	}
+}
@@ -0,0 +1,141 @@
+package blockchain
+
+import (
+	"fmt"
+)
+
+// ConsensusParams reúne os limites de tamanho que fazem parte do consenso. O mempool e a
+// validação de blocos usam as mesmas verificações (CheckTransaction e CheckBlock), então uma
+// transação aceita no mempool sempre cabe em um bloco válido. Zero desativa o limite.
+type ConsensusParams struct {
+	MaxBlockBytes int // Tamanho máximo do bloco serializado em JSON
+	MaxBlockTxs   int // Máximo de transações por bloco, sem contar a coinbase
+	MaxTxBytes    int // Tamanho máximo de uma transação serializada em JSON
+	MaxMemoBytes  int // Tamanho máximo do campo Data de uma transação
+}
+
+// Limites padrão. O bloco máximo cabe em uma mensagem da rede (1MB) mesmo em base64.
+const (
+	DefaultMaxBlockBytes = 512 * 1024
+	DefaultMaxBlockTxs   = 1000
+	DefaultMaxTxBytes    = 16 * 1024
+	DefaultMaxMemoBytes  = 1024
+)
+
+// blockHeaderReserve bytes reservados ao header, hash e assinatura ao montar um bloco dentro
+// de MaxBlockBytes (ver FitTransactions)
+const blockHeaderReserve = 1024
+
+// DefaultConsensusParams retorna os limites padrão
+func DefaultConsensusParams() ConsensusParams {
+	return ConsensusParams{
+		MaxBlockBytes: DefaultMaxBlockBytes,
+		MaxBlockTxs:   DefaultMaxBlockTxs,
+		MaxTxBytes:    DefaultMaxTxBytes,
+		MaxMemoBytes:  DefaultMaxMemoBytes,
+	}
+}
+
+// Validate verifica se os limites são coerentes entre si: nenhum negativo, o memo cabe na
+// transação e a transação (com o header) cabe no bloco
+func (p ConsensusParams) Validate() error {
+	if p.MaxBlockBytes < 0 || p.MaxBlockTxs < 0 || p.MaxTxBytes < 0 || p.MaxMemoBytes < 0 {
+		return fmt.Errorf("consensus limits cannot be negative")
+	}
+	if p.MaxMemoBytes > 0 && p.MaxTxBytes > 0 && p.MaxMemoBytes >= p.MaxTxBytes {
+		return fmt.Errorf("max memo bytes (%d) must be smaller than max tx bytes (%d)", p.MaxMemoBytes, p.MaxTxBytes)
+	}
+	if p.MaxBlockBytes > 0 && p.MaxBlockBytes <= blockHeaderReserve {
+		return fmt.Errorf("max block bytes (%d) must be larger than %d", p.MaxBlockBytes, blockHeaderReserve)
+	}
+	if p.MaxTxBytes > 0 && p.MaxBlockBytes > 0 && p.MaxTxBytes > p.MaxBlockBytes-blockHeaderReserve {
+		return fmt.Errorf("max tx bytes (%d) does not fit in max block bytes (%d)", p.MaxTxBytes, p.MaxBlockBytes)
+	}
+	return nil
+}
+
+// CheckTransaction verifica os limites de uma transação isolada (memo e tamanho serializado).
+// Denúncias de assinatura dupla carregam dois headers no campo Data e só respeitam o tamanho máximo.
+func (p ConsensusParams) CheckTransaction(tx *Transaction) error {
+	if p.MaxMemoBytes > 0 && len(tx.Data) > p.MaxMemoBytes && !isDoubleSignEvidence(tx) {
+		return fmt.Errorf("transaction data exceeds max memo size: %d bytes (limit %d)", len(tx.Data), p.MaxMemoBytes)
+	}
+	if p.MaxTxBytes > 0 {
+		size, err := transactionSize(tx)
+		if err != nil {
+			return err
+		}
+		if size > p.MaxTxBytes {
+			return fmt.Errorf("transaction exceeds max size: %d bytes (limit %d)", size, p.MaxTxBytes)
+		}
+	}
+	return nil
+}
+
+// CheckBlock verifica os limites do bloco e de cada transação dele, incluindo a coinbase
+func (p ConsensusParams) CheckBlock(block *Block) error {
+	if p.MaxBlockTxs > 0 {
+		if regularTxs := len(block.GetRegularTransactions()); regularTxs > p.MaxBlockTxs {
+			return fmt.Errorf("block exceeds max size: %d transactions (limit %d, excluding coinbase)",
+				regularTxs, p.MaxBlockTxs)
+		}
+	}
+
+	if p.MaxBlockBytes > 0 {
+		data, err := block.Serialize()
+		if err != nil {
+			return fmt.Errorf("failed to measure block size: %w", err)
+		}
+		if len(data) > p.MaxBlockBytes {
+			return fmt.Errorf("block exceeds max size: %d bytes (limit %d)", len(data), p.MaxBlockBytes)
+		}
+	}
+
+	for _, tx := range block.Transactions {
+		if err := p.CheckTransaction(tx); err != nil {
+			return fmt.Errorf("transaction %s: %w", tx.ID, err)
+		}
+	}
+	return nil
+}
+
+// FitTransactions retorna o maior prefixo de txs que cabe em um bloco com a coinbase
+// informada, respeitando MaxBlockTxs e MaxBlockBytes. Corta sempre no fim para não quebrar a
+// sequência de nonces das transações escolhidas.
+func (p ConsensusParams) FitTransactions(coinbase *Transaction, txs TransactionSlice) TransactionSlice {
+	if p.MaxBlockTxs > 0 && len(txs) > p.MaxBlockTxs {
+		txs = txs[:p.MaxBlockTxs]
+	}
+	if p.MaxBlockBytes <= 0 {
+		return txs
+	}
+
+	used := blockHeaderReserve
+	if coinbase != nil {
+		size, _ := transactionSize(coinbase)
+		used += size + 1
+	}
+	for i, tx := range txs {
+		size, err := transactionSize(tx)
+		if err != nil || used+size+1 > p.MaxBlockBytes {
+			return txs[:i]
+		}
+		used += size + 1 // Vírgula entre as transações do JSON
+	}
+	return txs
+}
+
+// transactionSize tamanho da transação serializada em JSON (como vai no bloco)
+func transactionSize(tx *Transaction) (int, error) {
+	data, err := tx.Serialize()
+	if err != nil {
+		return 0, fmt.Errorf("failed to measure transaction size: %w", err)
+	}
+	return len(data), nil
+}
+
+// isDoubleSignEvidence verifica se a transação é uma denúncia de assinatura dupla
+func isDoubleSignEvidence(tx *Transaction) bool {
+	txData, _ := DeserializeTransactionData(tx.Data)
+	return txData.IsDoubleSignEvidence()
+}
@@ -0,0 +1,179 @@
+package blockchain
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/krakovia/blockchain/pkg/wallet"
+)
+
+// Helper: chain com saldo para w e mempool com os mesmos limites de consenso (como o nó configura)
+func createConsensusLimitedChain(t *testing.T, w *wallet.Wallet, params ConsensusParams) (*Chain, *Mempool) {
+	t.Helper()
+
+	config := DefaultChainConfig()
+	config.BlockTime = 100 * time.Millisecond
+	config.Consensus = params
+
+	genesis := GenesisBlock(NewCoinbaseTransaction(w.GetAddress(), 10000, 0))
+	chain, err := NewChain(genesis, config)
+	if err != nil {
+		t.Fatalf("Failed to create chain: %v", err)
+	}
+
+	mempoolConfig := DefaultMempoolConfig()
+	mempoolConfig.Consensus = params
+	return chain, NewMempoolWithConfig(mempoolConfig)
+}
+
+// Helper: bloco 1 assinado com as transações informadas, montado sem o miner
+func buildSignedBlock(t *testing.T, chain *Chain, w *wallet.Wallet, txs ...*Transaction) *Block {
+	t.Helper()
+
+	lastBlock := chain.GetLastBlock()
+	all := TransactionSlice{NewCoinbaseTransaction(w.GetAddress(), chain.GetConfig().BlockReward, 1)}
+	all = append(all, txs...)
+
+	block := NewBlock(1, lastBlock.Hash, all, w.GetAddress())
+	block.Header.Timestamp = lastBlock.Header.Timestamp + 1
+	if err := block.Sign(w); err != nil {
+		t.Fatalf("Failed to sign block: %v", err)
+	}
+	return block
+}
+
+// Helper: transação assinada com o memo informado
+func newMemoTx(t *testing.T, w *wallet.Wallet, to, memo string, nonce uint64) *Transaction {
+	t.Helper()
+	tx := NewTransaction(w.GetAddress(), to, 10, 1, nonce, memo)
+	if err := tx.Sign(w); err != nil {
+		t.Fatalf("Failed to sign transaction: %v", err)
+	}
+	return tx
+}
+
+// expectLimitEnforced verifica que o mempool e o AddBlock concordam sobre a transação de w:
+// os dois aceitam ou os dois rejeitam por exceder um limite
+func expectLimitEnforced(t *testing.T, w *wallet.Wallet, tx *Transaction, params ConsensusParams, accept bool) {
+	t.Helper()
+
+	chain, mp := createConsensusLimitedChain(t, w, params)
+
+	mempoolErr := mp.AddTransaction(tx)
+	blockErr := chain.AddBlock(buildSignedBlock(t, chain, w, tx))
+
+	if accept {
+		if mempoolErr != nil {
+			t.Errorf("Mempool should accept the transaction: %v", mempoolErr)
+		}
+		if blockErr != nil {
+			t.Errorf("AddBlock should accept the transaction: %v", blockErr)
+		}
+		return
+	}
+
+	if mempoolErr == nil || !strings.Contains(mempoolErr.Error(), "exceeds max") {
+		t.Errorf("Mempool should reject the transaction over the limit, got: %v", mempoolErr)
+	}
+	if blockErr == nil || !strings.Contains(blockErr.Error(), "exceeds max") {
+		t.Errorf("AddBlock should reject the transaction over the limit, got: %v", blockErr)
+	}
+	if chain.GetHeight() != 0 {
+		t.Errorf("Chain should remain at height 0, got %d", chain.GetHeight())
+	}
+}
+
+func TestConsensusMaxMemoBytes(t *testing.T) {
+	w, _ := wallet.NewWallet()
+	dest, _ := wallet.NewWallet()
+	tx := newMemoTx(t, w, dest.GetAddress(), strings.Repeat("m", 100), 0)
+
+	t.Run("at limit", func(t *testing.T) {
+		expectLimitEnforced(t, w, tx, ConsensusParams{MaxMemoBytes: 100}, true)
+	})
+	t.Run("over limit", func(t *testing.T) {
+		expectLimitEnforced(t, w, tx, ConsensusParams{MaxMemoBytes: 99}, false)
+	})
+}
+
+func TestConsensusMaxTxBytes(t *testing.T) {
+	w, _ := wallet.NewWallet()
+	dest, _ := wallet.NewWallet()
+	tx := newMemoTx(t, w, dest.GetAddress(), "payload", 0)
+	size, err := transactionSize(tx)
+	if err != nil {
+		t.Fatalf("Failed to measure transaction: %v", err)
+	}
+
+	t.Run("at limit", func(t *testing.T) {
+		expectLimitEnforced(t, w, tx, ConsensusParams{MaxTxBytes: size}, true)
+	})
+	t.Run("over limit", func(t *testing.T) {
+		expectLimitEnforced(t, w, tx, ConsensusParams{MaxTxBytes: size - 1}, false)
+	})
+}
+
+func TestConsensusMaxBlockBytes(t *testing.T) {
+	w, _ := wallet.NewWallet()
+	dest, _ := wallet.NewWallet()
+	memo := strings.Repeat("x", 500)
+
+	// Cabem duas transações de ~1KB além da coinbase e do header
+	chain, mp := createConsensusLimitedChain(t, w, ConsensusParams{MaxBlockBytes: 4200})
+	var txs []*Transaction
+	for i := 0; i < 4; i++ {
+		tx := newMemoTx(t, w, dest.GetAddress(), memo, uint64(i))
+		if err := mp.AddTransaction(tx); err != nil {
+			t.Fatalf("Mempool should accept transaction %d: %v", i, err)
+		}
+		txs = append(txs, tx)
+	}
+
+	// Um bloco com todas passa do limite
+	if err := chain.AddBlock(buildSignedBlock(t, chain, w, txs...)); err == nil || !strings.Contains(err.Error(), "exceeds max size") {
+		t.Fatalf("Block over max bytes should be rejected, got: %v", err)
+	}
+
+	// O miner monta, a partir do mesmo mempool, um bloco que cabe no limite
+	block, err := NewMiner(w, chain, mp).CreateBlock()
+	if err != nil {
+		t.Fatalf("Failed to create block: %v", err)
+	}
+	regular := block.GetRegularTransactions()
+	if len(regular) == 0 || len(regular) >= len(txs) {
+		t.Fatalf("Expected miner to fit some but not all transactions, got %d of %d", len(regular), len(txs))
+	}
+	for i, tx := range regular {
+		if tx.Nonce != uint64(i) {
+			t.Errorf("Miner should keep the nonce sequence, got nonce %d at position %d", tx.Nonce, i)
+		}
+	}
+	if err := chain.AddBlock(block); err != nil {
+		t.Errorf("Block built by the miner should respect max bytes: %v", err)
+	}
+}
+
+func TestConsensusParamsValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  ConsensusParams
+		wantErr bool
+	}{
+		{"defaults", DefaultConsensusParams(), false},
+		{"unlimited", ConsensusParams{}, false},
+		{"negative", ConsensusParams{MaxTxBytes: -1}, true},
+		{"memo not smaller than tx", ConsensusParams{MaxTxBytes: 1000, MaxMemoBytes: 1000}, true},
+		{"tx does not fit block", ConsensusParams{MaxBlockBytes: 8 * 1024, MaxTxBytes: 8 * 1024}, true},
+		{"block smaller than header", ConsensusParams{MaxBlockBytes: 100}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.params.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -98,6 +98,31 @@ func waitForConvergence(t *testing.T, nodes []*Node, targetHeight uint64, timeou
 	t.Fatalf("Nodes did not converge to height %d within %v", targetHeight, timeout)
 }
 
+// Helper: aguarda todos os nós terem a mesma ponta. Usado depois de StopMining, quando nenhum
+// bloco novo é criado e só falta a propagação dos já minerados chegar a todos
+func waitForSameTip(t *testing.T, nodes []*Node, timeout time.Duration) {
+	t.Helper()
+
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		tip := nodes[0].GetChain().GetLastBlock().Hash
+		same := true
+		for _, node := range nodes[1:] {
+			if node.GetChain().GetLastBlock().Hash != tip {
+				same = false
+				break
+			}
+		}
+		if same {
+			return
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	t.Logf("Nodes did not reach the same tip within %v", timeout)
+}
+
 // Teste 1: Criação básica de nó
 func TestNodeCreation(t *testing.T) {
 	allocations := map[string]uint64{
@@ -225,8 +250,7 @@ func TestBlockPropagation(t *testing.T) {
 	coinbase := NewCoinbaseTransaction(w1.GetAddress(), config.BlockReward, 1)
 	txs := TransactionSlice{coinbase, stakeTx}
 	block1 := NewBlock(1, genesis.Hash, txs, w1.GetAddress())
-	hash, _ := block1.CalculateHash()
-	block1.Hash = hash
+	_ = block1.Sign(w1)
 	_ = chain1.AddBlock(block1)
 	_ = chain2.AddBlock(block1)
 
@@ -298,8 +322,7 @@ func TestSingleValidatorMining(t *testing.T) {
 
 	block1 := NewBlock(1, genesis.Hash, txs, addr)
 	block1.Header.Timestamp = genesis.Header.Timestamp + 1
-	hash, _ := block1.CalculateHash()
-	block1.Hash = hash
+	_ = block1.Sign(w)
 
 	err = chain.AddBlock(block1)
 	if err != nil {
@@ -325,6 +348,80 @@ func TestSingleValidatorMining(t *testing.T) {
 	}
 }
 
+// TestMineLoopRespectsBlockTime testa que o MineLoop espera BlockTime desde o bloco pai antes de
+// selar o próximo, mesmo sendo sempre a vez do único validador. Os timestamps dos headers têm
+// precisão de segundos, então o intervalo é medido pelo horário em que cada bloco foi criado.
+func TestMineLoopRespectsBlockTime(t *testing.T) {
+	w, err := wallet.NewWallet()
+	if err != nil {
+		t.Fatalf("Failed to create wallet: %v", err)
+	}
+	addr := w.GetAddress()
+
+	genesis := createTestGenesis(t, map[string]uint64{addr: 10000})
+
+	config := DefaultChainConfig()
+	config.BlockTime = 500 * time.Millisecond
+
+	chain, err := NewChain(genesis, config)
+	if err != nil {
+		t.Fatalf("Failed to create chain: %v", err)
+	}
+
+	// Primeiro bloco com o stake do validador
+	stakeData := NewStakeData(1000)
+	dataStr, _ := stakeData.Serialize()
+	stakeTx := NewTransaction(addr, addr, 1000, 1, 0, dataStr)
+	_ = stakeTx.Sign(w)
+
+	block1 := NewBlock(1, genesis.Hash, TransactionSlice{NewCoinbaseTransaction(addr, config.BlockReward, 1), stakeTx}, addr)
+	block1.Header.Timestamp = genesis.Header.Timestamp + 1
+	_ = block1.Sign(w)
+	if err := chain.AddBlock(block1); err != nil {
+		t.Fatalf("Failed to add first block with stake: %v", err)
+	}
+
+	var (
+		mu      sync.Mutex
+		created []time.Time
+		blocks  []*Block
+	)
+	miner := NewMiner(w, chain, NewMempool())
+	miner.SetOnBlockCreated(func(block *Block) {
+		mu.Lock()
+		defer mu.Unlock()
+		created = append(created, time.Now())
+		blocks = append(blocks, block)
+	})
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		miner.MineLoop(stop)
+		close(done)
+	}()
+	time.Sleep(3 * time.Second)
+	close(stop)
+	<-done
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if len(blocks) < 3 {
+		t.Fatalf("Expected at least 3 blocks mined, got %d", len(blocks))
+	}
+	for i := 1; i < len(blocks); i++ {
+		if gap := created[i].Sub(created[i-1]); gap < config.BlockTime {
+			t.Errorf("Block %d sealed %v after its parent, expected at least %v",
+				blocks[i].Header.Height, gap, config.BlockTime)
+		}
+		if blocks[i].Header.Timestamp < blocks[i-1].Header.Timestamp {
+			t.Errorf("Block %d timestamp %d is before its parent's %d",
+				blocks[i].Header.Height, blocks[i].Header.Timestamp, blocks[i-1].Header.Timestamp)
+		}
+	}
+}
+
 // Teste 6: Mineração com múltiplos validadores
 func TestMultipleValidatorMining(t *testing.T) {
 	// Cria 3 validadores com stakes diferentes
@@ -396,8 +493,7 @@ func TestMultipleValidatorMining(t *testing.T) {
 	}
 
 	block1 := NewBlock(1, genesis.Hash, txs, wallets[0].GetAddress())
-	hash, _ := block1.CalculateHash()
-	block1.Hash = hash
+	_ = block1.Sign(wallets[0])
 
 	// Adiciona o bloco em todos os nós
 	for i := range validators {
@@ -431,6 +527,9 @@ func TestMultipleValidatorMining(t *testing.T) {
 		node.StopMining()
 	}
 
+	// Nenhum bloco novo é criado após StopMining; aguarda os já minerados chegarem a todos
+	waitForSameTip(t, validators, 5*time.Second)
+
 	// Verifica que todos têm a mesma chain
 	height := validators[0].GetChain().GetHeight()
 	lastHash := validators[0].GetChain().GetLastBlock().Hash
@@ -502,8 +601,7 @@ func TestStakeUnstakeDuringMining(t *testing.T) {
 
 	txs := TransactionSlice{coinbase, transferTx, stakeTx1, stakeTx2}
 	block1 := NewBlock(1, genesis.Hash, txs, w1.GetAddress())
-	hash, _ := block1.CalculateHash()
-	block1.Hash = hash
+	_ = block1.Sign(w1)
 	_ = chain1.AddBlock(block1)
 	_ = chain2.AddBlock(block1)
 
@@ -581,8 +679,7 @@ func TestNodeSynchronization(t *testing.T) {
 	coinbase := NewCoinbaseTransaction(w.GetAddress(), config.BlockReward, 1)
 	txs := TransactionSlice{coinbase, stakeTx}
 	block1 := NewBlock(1, genesis.Hash, txs, w.GetAddress())
-	hash, _ := block1.CalculateHash()
-	block1.Hash = hash
+	_ = block1.Sign(w)
 	_ = chain1.AddBlock(block1)
 
 	// Minera mais alguns blocos
@@ -689,8 +786,7 @@ func TestProportionalBlockDistribution(t *testing.T) {
 	}
 
 	block1 := NewBlock(1, genesis.Hash, txs, wallets[0].GetAddress())
-	hash, _ := block1.CalculateHash()
-	block1.Hash = hash
+	_ = block1.Sign(wallets[0])
 
 	for i := range validators {
 		_ = validators[i].GetChain().AddBlock(block1)
@@ -820,8 +916,7 @@ func TestChainConsistency(t *testing.T) {
 
 	block1 := NewBlock(1, genesis.Hash, txs, wallets[0].GetAddress())
 	block1.Header.Timestamp = genesis.Header.Timestamp + 1
-	hash, _ := block1.CalculateHash()
-	block1.Hash = hash
+	_ = block1.Sign(wallets[0])
 
 	for i := range nodes {
 		_ = nodes[i].GetChain().AddBlock(block1)
@@ -839,6 +934,9 @@ func TestChainConsistency(t *testing.T) {
 		node.StopMining()
 	}
 
+	// Nenhum bloco novo é criado após StopMining; aguarda os já minerados chegarem a todos
+	waitForSameTip(t, nodes, 5*time.Second)
+
 	// Verifica que todas as chains são idênticas
 	referenceChain := nodes[0].GetChain()
 	referenceBlocks := referenceChain.GetAllBlocks()
@@ -15,6 +15,10 @@ const (
 	PrefixStake   = "stake"   // stake-<address> = stake amount
 	PrefixNonce   = "nonce"   // nonce-<address> = nonce
 	PrefixCustom  = "custom"  // custom-<key> = valor customizado
+
+	PrefixUnbonding       = "unbonding"    // unbonding-<address> = stake retirado aguardando liberação
+	PrefixUnbondingHeight = "unbondheight" // unbondheight-<address> = altura em que o unbonding vira saldo
+	PrefixSlashHeight     = "slashheight"  // slashheight-<address> = altura da última assinatura dupla punida
 )
 
 // StateModifications representa as modificações de estado em um bloco
@@ -27,6 +31,14 @@ type BlockContext struct {
 	Height        uint64             // Altura do bloco
 	Transactions  TransactionSlice   // Transações do bloco
 	Modifications StateModifications // Modificações de estado causadas por este bloco
+
+	// Desfazer o bloco (reorganização): valores anteriores das chaves alteradas e dos nomes
+	// registrados ("" = sem nome)
+	undo      StateModifications
+	undoNames map[string]string
+
+	// Stake do produtor antes do bloco (peso do bloco na escolha entre forks)
+	weight uint64
 }
 
 // Context representa o banco de dados em memória da blockchain
@@ -44,6 +56,24 @@ type Context struct {
 
 	// Estado atual acumulado (cache para performance)
 	currentState StateModifications
+
+	// Stake mínimo total exigido após uma transação de stake (0 = sem limite)
+	minStake uint64
+
+	// Blocos entre o unstake e a liberação do valor como saldo (0 = imediato)
+	unbondingPeriod uint64
+
+	// Blocos até a recompensa de um bloco poder ser gasta (0 = imediato)
+	coinbaseMaturity uint64
+
+	// Parte do stake queimada por uma denúncia de assinatura dupla, em pontos base (0 = sem punição)
+	slashBasisPoints uint64
+
+	// Saldo bloqueado do gênesis por endereço (liberado conforme a altura)
+	vesting map[string]VestingSchedule
+
+	// Nomes de exibição registrados (endereço -> nome); únicos sem diferenciar maiúsculas
+	names map[string]string
 }
 
 // NewContext cria um novo contexto vazio
@@ -51,6 +81,7 @@ func NewContext() *Context {
 	return &Context{
 		blocks:       make(map[string]*BlockContext),
 		currentState: make(StateModifications),
+		names:        make(map[string]string),
 	}
 }
 
@@ -67,6 +98,42 @@ func NewContextWithGenesis(genesisBlock *Block) (*Context, error) {
 	return ctx, nil
 }
 
+// NewContextFromState cria um contexto a partir do estado de um checkpoint, sem
+// reexecutar blocos. O contexto passa a considerar blockHash como último bloco.
+func NewContextFromState(height uint64, blockHash string, accounts map[string]*AccountState) *Context {
+	ctx := NewContext()
+
+	for addr, account := range accounts {
+		if account == nil {
+			continue
+		}
+		if account.Balance > 0 {
+			ctx.currentState[MakeBalanceKey(addr)] = account.Balance
+		}
+		if account.Stake > 0 {
+			ctx.currentState[MakeStakeKey(addr)] = account.Stake
+		}
+		if account.Nonce > 0 {
+			ctx.currentState[MakeNonceKey(addr)] = account.Nonce
+		}
+		if account.Name != "" {
+			ctx.names[addr] = account.Name
+		}
+		if account.Unbonding > 0 {
+			ctx.currentState[MakeUnbondingKey(addr)] = account.Unbonding
+			ctx.currentState[MakeUnbondingHeightKey(addr)] = account.UnbondingRelease
+		}
+		if account.SlashHeight > 0 {
+			ctx.currentState[MakeSlashHeightKey(addr)] = account.SlashHeight
+		}
+	}
+
+	ctx.lastBlockHash = blockHash
+	ctx.lastBlockHeight = height
+
+	return ctx
+}
+
 // GetBlock retorna o contexto de um bloco pelo hash
 func (c *Context) GetBlock(blockHash string) (*BlockContext, bool) {
 	c.mu.RLock()
@@ -96,6 +163,129 @@ func (c *Context) GetBalance(address string) uint64 {
 	return c.GetState(key)
 }
 
+// SetMinStake define o stake total mínimo que uma transação de stake deve deixar no endereço
+func (c *Context) SetMinStake(amount uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.minStake = amount
+}
+
+// SetUnbondingPeriod define quantos blocos o valor de um unstake fica bloqueado antes de virar saldo
+func (c *Context) SetUnbondingPeriod(blocks uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.unbondingPeriod = blocks
+}
+
+// SetCoinbaseMaturity define quantos blocos a recompensa de um bloco espera antes de poder ser
+// gasta: a coinbase do bloco H só é gasta a partir do bloco H+blocks
+func (c *Context) SetCoinbaseMaturity(blocks uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.coinbaseMaturity = blocks
+}
+
+// SetSlashBasisPoints define a parte do stake queimada por uma denúncia de assinatura dupla,
+// em pontos base (1/10000); valores acima de 10000 queimam o stake inteiro
+func (c *Context) SetSlashBasisPoints(basisPoints uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.slashBasisPoints = basisPoints
+}
+
+// SetVesting define os bloqueios de saldo do gênesis; gastos só podem usar a parte liberada
+func (c *Context) SetVesting(schedules []VestingSchedule) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.vesting = make(map[string]VestingSchedule, len(schedules))
+	for _, schedule := range schedules {
+		c.vesting[schedule.Address] = schedule
+	}
+}
+
+// GetLockedBalance retorna quanto do saldo do endereço ainda está bloqueado pelo vesting
+// para o próximo bloco
+func (c *Context) GetLockedBalance(address string) uint64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.lockedAt(address, c.lastBlockHeight+1)
+}
+
+// lockedAt retorna o valor bloqueado pelo vesting do endereço na altura (não thread-safe)
+func (c *Context) lockedAt(address string, height uint64) uint64 {
+	schedule, ok := c.vesting[address]
+	if !ok {
+		return 0
+	}
+	return schedule.LockedAt(height)
+}
+
+// GetImmatureBalance retorna quanto do saldo do endereço são recompensas de bloco que ainda não
+// podem ser gastas no próximo bloco
+func (c *Context) GetImmatureBalance(address string) uint64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.immatureRewards(c.lastBlockHeight+1, nil, nil)[address]
+}
+
+// immatureRewards soma, por endereço, as coinbases que ainda não podem ser gastas na altura
+// height: as de current (bloco em execução), as de pending (blocos do lote já executados, ainda
+// fora de c.blocks) e as dos blocos aplicados, a partir do último. Blocos anteriores a um
+// checkpoint restaurado não estão no contexto e contam como maduros (não thread-safe).
+func (c *Context) immatureRewards(height uint64, pending []*BlockContext, current *Block) map[string]uint64 {
+	immature := make(map[string]uint64)
+	if c.coinbaseMaturity == 0 {
+		return immature
+	}
+
+	// A coinbase do gênesis é a alocação inicial, não recompensa
+	isImmature := func(blockHeight uint64) bool {
+		return blockHeight > 0 && blockHeight+c.coinbaseMaturity > height
+	}
+	addCoinbases := func(txs TransactionSlice) {
+		for _, tx := range txs {
+			if tx.IsCoinbase() {
+				immature[tx.To] += tx.Amount
+			}
+		}
+	}
+
+	if current != nil && isImmature(current.Header.Height) {
+		addCoinbases(current.Transactions)
+	}
+	for i := len(pending) - 1; i >= 0 && isImmature(pending[i].Height); i-- {
+		addCoinbases(pending[i].Transactions)
+	}
+	for hash := c.lastBlockHash; hash != ""; {
+		blockCtx, ok := c.blocks[hash]
+		if !ok || !isImmature(blockCtx.Height) {
+			break
+		}
+		addCoinbases(blockCtx.Transactions)
+		hash = blockCtx.PreviousHash
+	}
+
+	return immature
+}
+
+// GetPendingUnbonding retorna o valor retirado do stake que ainda não foi liberado como saldo
+func (c *Context) GetPendingUnbonding(address string) uint64 {
+	return c.GetState(MakeUnbondingKey(address))
+}
+
+// GetUnbondingReleaseHeight retorna a altura em que o unbonding pendente vira saldo (0 se não houver)
+func (c *Context) GetUnbondingReleaseHeight(address string) uint64 {
+	if c.GetPendingUnbonding(address) == 0 {
+		return 0
+	}
+	return c.GetState(MakeUnbondingHeightKey(address))
+}
+
+// GetSlashHeight retorna a altura da última assinatura dupla do endereço punida (0 se nunca foi)
+func (c *Context) GetSlashHeight(address string) uint64 {
+	return c.GetState(MakeSlashHeightKey(address))
+}
+
 // GetStake retorna o stake de um endereço
 func (c *Context) GetStake(address string) uint64 {
 	key := MakeStakeKey(address)
@@ -163,72 +353,207 @@ func (c *Context) AddBlock(block *Block) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	// Valida que o bloco conecta corretamente
-	if block.Header.Height > 0 {
-		if block.Header.PreviousHash != c.lastBlockHash {
-			return fmt.Errorf("block does not connect to last block: expected previous hash %s, got %s",
-				c.lastBlockHash, block.Header.PreviousHash)
+	_, err := c.addBlocksLocked([]*Block{block})
+	return err
+}
+
+// AddBlocks adiciona blocos consecutivos ao contexto copiando o estado uma única vez.
+// A operação é atômica: se algum bloco falhar, nenhum bloco do lote é aplicado.
+func (c *Context) AddBlocks(blocks []*Block) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if i, err := c.addBlocksLocked(blocks); err != nil {
+		return &BatchError{Index: i, Height: blocks[i].Header.Height, Err: err}
+	}
+	return nil
+}
+
+// RollbackTo desfaz os blocos aplicados depois de blockHash, voltando o estado ao daquele
+// bloco (reorganização). Se algum bloco do caminho não puder ser desfeito, o estado não é
+// alterado.
+func (c *Context) RollbackTo(blockHash string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Confere todo o caminho antes de alterar o estado
+	var path []*BlockContext
+	for hash := c.lastBlockHash; hash != blockHash; {
+		blockCtx, ok := c.blocks[hash]
+		if !ok || blockCtx.undo == nil {
+			return fmt.Errorf("cannot roll back to block %s: block %s cannot be undone", blockHash, hash)
+		}
+		path = append(path, blockCtx)
+		hash = blockCtx.PreviousHash
+	}
+
+	// Desfaz do último para o primeiro
+	for _, blockCtx := range path {
+		for key, value := range blockCtx.undo {
+			c.currentState[key] = value
 		}
-		if block.Header.Height != c.lastBlockHeight+1 {
-			return fmt.Errorf("block height mismatch: expected %d, got %d",
-				c.lastBlockHeight+1, block.Header.Height)
+		for address, name := range blockCtx.undoNames {
+			if name == "" {
+				delete(c.names, address)
+			} else {
+				c.names[address] = name
+			}
 		}
+		delete(c.blocks, blockCtx.BlockHash)
+		c.lastBlockHash = blockCtx.PreviousHash
+		c.lastBlockHeight = blockCtx.Height - 1
 	}
 
-	// Cria contexto temporário para executar o bloco
-	tempModifications := make(StateModifications)
+	return nil
+}
+
+// blockWeight retorna o peso de um bloco aplicado: o stake do produtor antes do bloco
+func (c *Context) blockWeight(blockHash string) uint64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if blockCtx, ok := c.blocks[blockHash]; ok {
+		return blockCtx.weight
+	}
+	return 0
+}
+
+// BatchError erro ao adicionar um lote de blocos: identifica o bloco que invalidou o lote
+type BatchError struct {
+	Index  int    // Posição do bloco no lote
+	Height uint64 // Altura do bloco
+	Err    error
+}
+
+// Error implementa a interface error
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("block %d of batch (height %d): %v", e.Index+1, e.Height, e.Err)
+}
+
+// Unwrap retorna o erro do bloco
+func (e *BatchError) Unwrap() error {
+	return e.Err
+}
 
-	// Copia o estado atual para o temporário
+// addBlocksLocked executa os blocos em sequência sobre uma cópia do estado e só a torna o
+// estado atual se todos forem aplicados. Retorna o índice do bloco que falhou (deve ser
+// chamado com lock).
+func (c *Context) addBlocksLocked(blocks []*Block) (int, error) {
+	// Cria estado temporário para executar os blocos
+	tempState := make(StateModifications, len(c.currentState))
 	for k, v := range c.currentState {
-		tempModifications[k] = v
+		tempState[k] = v
+	}
+	tempNames := c.copyNames()
+
+	lastHash, lastHeight := c.lastBlockHash, c.lastBlockHeight
+	blockCtxs := make([]*BlockContext, 0, len(blocks))
+
+	for i, block := range blocks {
+		// Valida que o bloco conecta corretamente
+		if block.Header.Height > 0 {
+			if block.Header.PreviousHash != lastHash {
+				return i, fmt.Errorf("block does not connect to last block: expected previous hash %s, got %s",
+					lastHash, block.Header.PreviousHash)
+			}
+			if block.Header.Height != lastHeight+1 {
+				return i, fmt.Errorf("block height mismatch: expected %d, got %d",
+					lastHeight+1, block.Header.Height)
+			}
+		}
+
+		blockCtx, err := c.executeBlock(block, tempState, tempNames, blockCtxs)
+		if err != nil {
+			return i, err
+		}
+		blockCtxs = append(blockCtxs, blockCtx)
+		lastHash, lastHeight = block.Hash, block.Header.Height
+	}
+
+	// Todos os blocos foram aplicados: adiciona ao mapa de blocos e atualiza o estado atual
+	for _, blockCtx := range blockCtxs {
+		c.blocks[blockCtx.BlockHash] = blockCtx
+	}
+	c.currentState = tempState
+	c.names = tempNames
+
+	// Atualiza referências do último bloco
+	c.lastBlockHash = lastHash
+	c.lastBlockHeight = lastHeight
+
+	return 0, nil
+}
+
+// executeBlock executa o bloco modificando state e names diretamente e retorna o contexto
+// do bloco com apenas as modificações feitas por ele. pending são os blocos do mesmo lote já
+// executados (não thread-safe).
+func (c *Context) executeBlock(block *Block, state StateModifications, names map[string]string, pending []*BlockContext) (*BlockContext, error) {
+	// Peso do bloco: stake do produtor no estado anterior a ele
+	weight := state[MakeStakeKey(block.Header.ValidatorAddr)]
+
+	// Valores anteriores ao bloco das chaves que ele altera
+	previous := make(StateModifications)
+	previousNames := make(map[string]string)
+	record := func(key StateKey) {
+		if _, ok := previous[key]; !ok {
+			previous[key] = state[key]
+		}
+	}
+
+	// Libera os unbondings que vencem neste bloco antes de executar as transações
+	for _, address := range dueUnbondings(state, block.Header.Height) {
+		record(MakeBalanceKey(address))
+		record(MakeUnbondingKey(address))
+		record(MakeUnbondingHeightKey(address))
+		releaseUnbondingOf(state, address)
 	}
 
+	// Recompensas (inclusive a deste bloco) que ainda não podem ser gastas
+	immature := c.immatureRewards(block.Header.Height, pending, block)
+
 	// Executa todas as transações do bloco
 	for i, tx := range block.Transactions {
-		modifications, err := c.executeTransactionInternal(tx, tempModifications, block.Header.Height)
+		oldName := names[tx.From]
+		modifications, err := c.executeTransactionInternal(tx, state, names, block.Header.Height, immature)
 		if err != nil {
-			return fmt.Errorf("failed to execute transaction %d (%s): %w", i, tx.ID, err)
+			return nil, fmt.Errorf("failed to execute transaction %d (%s): %w", i, tx.ID, err)
+		}
+		if _, recorded := previousNames[tx.From]; !recorded && names[tx.From] != oldName {
+			previousNames[tx.From] = oldName
 		}
 
 		// Aplica as modificações ao estado temporário
 		for key, value := range modifications {
-			tempModifications[key] = value
+			record(key)
+			state[key] = value
 		}
 	}
 
 	// Calcula apenas as modificações deste bloco (diferença do estado anterior)
 	blockModifications := make(StateModifications)
-	for key, newValue := range tempModifications {
-		oldValue := c.currentState[key]
-		if newValue != oldValue {
+	for key, oldValue := range previous {
+		if newValue := state[key]; newValue != oldValue {
 			blockModifications[key] = newValue
 		}
 	}
 
-	// Cria o contexto do bloco
-	blockCtx := &BlockContext{
+	return &BlockContext{
 		BlockHash:     block.Hash,
 		PreviousHash:  block.Header.PreviousHash,
 		Height:        block.Header.Height,
 		Transactions:  block.Transactions,
 		Modifications: blockModifications,
-	}
-
-	// Adiciona ao mapa de blocos
-	c.blocks[block.Hash] = blockCtx
-
-	// Atualiza o estado atual
-	c.currentState = tempModifications
-
-	// Atualiza referências do último bloco
-	c.lastBlockHash = block.Hash
-	c.lastBlockHeight = block.Header.Height
-
-	return nil
+		undo:          previous,
+		undoNames:     previousNames,
+		weight:        weight,
+	}, nil
 }
 
-// executeTransactionInternal executa uma transação e retorna as modificações (não thread-safe)
-func (c *Context) executeTransactionInternal(tx *Transaction, currentState StateModifications, blockHeight uint64) (StateModifications, error) {
+// executeTransactionInternal executa uma transação e retorna as modificações (não thread-safe).
+// Registros de nome são aplicados diretamente em names (cópia de trabalho do chamador),
+// somente quando a transação é executada com sucesso. immature são as recompensas de bloco de
+// cada endereço que ainda não podem ser gastas (ver immatureRewards).
+func (c *Context) executeTransactionInternal(tx *Transaction, currentState StateModifications, names map[string]string, blockHeight uint64, immature map[string]uint64) (StateModifications, error) {
 	modifications := make(StateModifications)
 
 	// Valida a transação
@@ -237,6 +562,11 @@ func (c *Context) executeTransactionInternal(tx *Transaction, currentState State
 			return nil, fmt.Errorf("transaction validation failed: %w", err)
 		}
 
+		// Transação com ExpiryHeight não pode ser minerada depois dessa altura
+		if tx.IsExpiredAt(blockHeight) {
+			return nil, fmt.Errorf("transaction expired at height %d (block height %d)", tx.ExpiryHeight, blockHeight)
+		}
+
 		// Verifica nonce
 		expectedNonce := currentState[MakeNonceKey(tx.From)]
 		if tx.Nonce != expectedNonce {
@@ -253,6 +583,18 @@ func (c *Context) executeTransactionInternal(tx *Transaction, currentState State
 		if balance < totalCost {
 			return nil, fmt.Errorf("insufficient balance: have %d, need %d", balance, totalCost)
 		}
+
+		// Saldo ainda bloqueado pelo vesting do gênesis ou por recompensas imaturas não pode ser gasto
+		vesting, immatureReward := c.lockedAt(tx.From, blockHeight), immature[tx.From]
+		if locked := vesting + immatureReward; locked > 0 {
+			var unlocked uint64
+			if balance > locked {
+				unlocked = balance - locked
+			}
+			if unlocked < totalCost {
+				return nil, fmt.Errorf("insufficient unlocked balance: have %d unlocked (%d locked by vesting, %d immature coinbase), need %d", unlocked, vesting, immatureReward, totalCost)
+			}
+		}
 	}
 
 	// Parse transaction data
@@ -287,6 +629,11 @@ func (c *Context) executeTransactionInternal(tx *Transaction, currentState State
 		fromBalance := currentState[MakeBalanceKey(tx.From)]
 		fromStake := currentState[MakeStakeKey(tx.From)]
 
+		// Stake abaixo do mínimo não qualifica como validador e só prende saldo
+		if c.minStake > 0 && fromStake+tx.Amount < c.minStake {
+			return nil, fmt.Errorf("stake below minimum: total stake would be %d, minimum is %d", fromStake+tx.Amount, c.minStake)
+		}
+
 		modifications[MakeBalanceKey(tx.From)] = fromBalance - tx.Amount - tx.Fee
 		modifications[MakeStakeKey(tx.From)] = fromStake + tx.Amount
 	} else if txData != nil && txData.Type == TransactionTypeUnstake {
@@ -307,8 +654,87 @@ func (c *Context) executeTransactionInternal(tx *Transaction, currentState State
 			return nil, fmt.Errorf("insufficient stake: have %d, need %d", fromStake, unstakeAmount)
 		}
 
-		modifications[MakeBalanceKey(tx.From)] = fromBalance - tx.Fee + tx.Amount
 		modifications[MakeStakeKey(tx.From)] = fromStake - tx.Amount
+
+		if c.unbondingPeriod == 0 {
+			modifications[MakeBalanceKey(tx.From)] = fromBalance - tx.Fee + tx.Amount
+		} else {
+			// O valor fica em unbonding até unbondingPeriod blocos depois deste. Um novo unstake
+			// soma ao valor pendente e reinicia o prazo de todo o montante.
+			modifications[MakeBalanceKey(tx.From)] = fromBalance - tx.Fee
+			modifications[MakeUnbondingKey(tx.From)] = currentState[MakeUnbondingKey(tx.From)] + tx.Amount
+			modifications[MakeUnbondingHeightKey(tx.From)] = blockHeight + c.unbondingPeriod
+		}
+	} else if txData.IsRegisterName() {
+		// Registro de nome: associa um nome de exibição ao remetente (paga apenas a fee)
+		if tx.To != tx.From {
+			return nil, fmt.Errorf("name registration must be sent to the sender's own address")
+		}
+		if tx.Amount != 0 {
+			return nil, fmt.Errorf("name registration cannot transfer tokens: amount=%d", tx.Amount)
+		}
+
+		name, _ := txData.GetString("name")
+		if owner := nameOwner(names, name); owner != "" && owner != tx.From {
+			return nil, fmt.Errorf("name %q is already registered by %s", name, owner)
+		}
+
+		fromBalance := currentState[MakeBalanceKey(tx.From)]
+		modifications[MakeBalanceKey(tx.From)] = fromBalance - tx.Fee
+		names[tx.From] = name
+	} else if txData.IsDoubleSignEvidence() {
+		// Denúncia de assinatura dupla: queima slashBasisPoints do stake do validador (paga apenas a fee)
+		if tx.To != tx.From {
+			return nil, fmt.Errorf("double sign evidence must be sent to the sender's own address")
+		}
+		if tx.Amount != 0 {
+			return nil, fmt.Errorf("double sign evidence cannot transfer tokens: amount=%d", tx.Amount)
+		}
+		if c.slashBasisPoints == 0 {
+			return nil, fmt.Errorf("slashing is disabled")
+		}
+
+		// A evidência já foi verificada em txData.Validate
+		evidence, err := txData.GetDoubleSignEvidence()
+		if err != nil {
+			return nil, err
+		}
+		if evidence.Height >= blockHeight {
+			return nil, fmt.Errorf("double sign evidence at height %d is not below block height %d", evidence.Height, blockHeight)
+		}
+		if maxAge := evidenceMaxAge(c.unbondingPeriod); blockHeight-evidence.Height > maxAge {
+			return nil, fmt.Errorf("double sign evidence at height %d is older than %d blocks (unbonding period %d)", evidence.Height, maxAge, c.unbondingPeriod)
+		}
+
+		// Cada validador é punido uma vez por altura, em ordem crescente de altura
+		slashHeightKey := MakeSlashHeightKey(evidence.Validator)
+		if last := currentState[slashHeightKey]; evidence.Height <= last {
+			return nil, fmt.Errorf("validator %s already slashed at height %d", evidence.Validator, last)
+		}
+
+		stakeKey := MakeStakeKey(evidence.Validator)
+		stake := currentState[stakeKey]
+
+		// O valor retirado a partir da altura denunciada ainda está em unbonding (a evidência
+		// expira antes da liberação) e é punido como o stake. Um novo unstake reinicia o prazo de
+		// todo o valor pendente, então o valor inteiro conta como retirado no último unstake.
+		unbondingKey := MakeUnbondingKey(evidence.Validator)
+		var unbonding uint64
+		if release := currentState[MakeUnbondingHeightKey(evidence.Validator)]; release >= evidence.Height+c.unbondingPeriod {
+			unbonding = currentState[unbondingKey]
+		}
+
+		if stake == 0 && unbonding == 0 {
+			return nil, fmt.Errorf("validator %s has no stake to slash", evidence.Validator)
+		}
+
+		fromBalance := currentState[MakeBalanceKey(tx.From)]
+		modifications[MakeBalanceKey(tx.From)] = fromBalance - tx.Fee
+		modifications[stakeKey] = stake - slashAmount(stake, c.slashBasisPoints)
+		if unbonding > 0 {
+			modifications[unbondingKey] = unbonding - slashAmount(unbonding, c.slashBasisPoints)
+		}
+		modifications[slashHeightKey] = evidence.Height
 	} else {
 		// Transfer: transferência normal
 		fromBalance := currentState[MakeBalanceKey(tx.From)]
@@ -332,9 +758,74 @@ func (c *Context) ExecuteTransaction(tx *Transaction) (StateModifications, error
 	for k, v := range c.currentState {
 		tempState[k] = v
 	}
+	releaseUnbonding(tempState, c.lastBlockHeight+1)
 
 	// Executa a transação
-	return c.executeTransactionInternal(tx, tempState, c.lastBlockHeight+1)
+	return c.executeTransactionInternal(tx, tempState, c.copyNames(), c.lastBlockHeight+1, c.immatureRewards(c.lastBlockHeight+1, nil, nil))
+}
+
+// SelectExecutableTransactions simula a execução sequencial das transações (na ordem dada)
+// sobre o estado atual e retorna as que podem ser aplicadas, até maxCount (<= 0 = sem limite).
+// Transações que falham são ignoradas; as seguintes do mesmo remetente falham por nonce.
+func (c *Context) SelectExecutableTransactions(txs []*Transaction, maxCount int) TransactionSlice {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	tempState := make(StateModifications)
+	for k, v := range c.currentState {
+		tempState[k] = v
+	}
+	tempNames := c.copyNames()
+	releaseUnbonding(tempState, c.lastBlockHeight+1)
+	immature := c.immatureRewards(c.lastBlockHeight+1, nil, nil)
+
+	selected := make(TransactionSlice, 0)
+	for _, tx := range txs {
+		if maxCount > 0 && len(selected) >= maxCount {
+			break
+		}
+
+		modifications, err := c.executeTransactionInternal(tx, tempState, tempNames, c.lastBlockHeight+1, immature)
+		if err != nil {
+			continue
+		}
+
+		for key, value := range modifications {
+			tempState[key] = value
+		}
+		selected = append(selected, tx)
+	}
+
+	return selected
+}
+
+// dueUnbondings retorna os endereços cujo unbonding é liberado em height
+func dueUnbondings(state StateModifications, height uint64) []string {
+	addresses := make([]string, 0)
+	for key, releaseHeight := range state {
+		prefix, address := ParseStateKey(key)
+		if prefix == PrefixUnbondingHeight && releaseHeight != 0 && releaseHeight <= height {
+			addresses = append(addresses, address)
+		}
+	}
+	return addresses
+}
+
+// releaseUnbonding move para o saldo os unbondings cuja altura de liberação é <= height.
+// Opera diretamente sobre state (cópia de trabalho do chamador).
+func releaseUnbonding(state StateModifications, height uint64) {
+	for _, address := range dueUnbondings(state, height) {
+		releaseUnbondingOf(state, address)
+	}
+}
+
+// releaseUnbondingOf move o unbonding do endereço para o saldo
+func releaseUnbondingOf(state StateModifications, address string) {
+	unbondingKey := MakeUnbondingKey(address)
+	balanceKey := MakeBalanceKey(address)
+	state[balanceKey] = state[balanceKey] + state[unbondingKey]
+	state[unbondingKey] = 0
+	state[MakeUnbondingHeightKey(address)] = 0
 }
 
 // MakeBalanceKey cria uma chave para saldo
@@ -352,6 +843,21 @@ func MakeNonceKey(address string) StateKey {
 	return StateKey(fmt.Sprintf("%s-%s", PrefixNonce, address))
 }
 
+// MakeUnbondingKey cria uma chave para o valor em unbonding
+func MakeUnbondingKey(address string) StateKey {
+	return StateKey(fmt.Sprintf("%s-%s", PrefixUnbonding, address))
+}
+
+// MakeUnbondingHeightKey cria uma chave para a altura de liberação do unbonding
+func MakeUnbondingHeightKey(address string) StateKey {
+	return StateKey(fmt.Sprintf("%s-%s", PrefixUnbondingHeight, address))
+}
+
+// MakeSlashHeightKey cria uma chave para a altura da última assinatura dupla punida
+func MakeSlashHeightKey(address string) StateKey {
+	return StateKey(fmt.Sprintf("%s-%s", PrefixSlashHeight, address))
+}
+
 // MakeCustomKey cria uma chave customizada
 func MakeCustomKey(key string) StateKey {
 	return StateKey(fmt.Sprintf("%s-%s", PrefixCustom, key))
@@ -411,9 +917,88 @@ func (c *Context) GetAllNonces() map[string]uint64 {
 	return nonces
 }
 
+// GetAllUnbonding retorna todos os valores em unbonding no estado atual
+func (c *Context) GetAllUnbonding() map[string]uint64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	unbonding := make(map[string]uint64)
+	for key, value := range c.currentState {
+		prefix, address := ParseStateKey(key)
+		if prefix == PrefixUnbonding && value > 0 {
+			unbonding[address] = value
+		}
+	}
+	return unbonding
+}
+
+// GetAllUnbondingReleases retorna a altura de liberação de cada unbonding pendente
+func (c *Context) GetAllUnbondingReleases() map[string]uint64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	releases := make(map[string]uint64)
+	for key, value := range c.currentState {
+		prefix, address := ParseStateKey(key)
+		if prefix == PrefixUnbondingHeight && value > 0 && c.currentState[MakeUnbondingKey(address)] > 0 {
+			releases[address] = value
+		}
+	}
+	return releases
+}
+
+// GetAllSlashHeights retorna a altura da última assinatura dupla punida de cada endereço
+func (c *Context) GetAllSlashHeights() map[string]uint64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	heights := make(map[string]uint64)
+	for key, value := range c.currentState {
+		prefix, address := ParseStateKey(key)
+		if prefix == PrefixSlashHeight && value > 0 {
+			heights[address] = value
+		}
+	}
+	return heights
+}
+
+// copyNames copia os nomes registrados (não thread-safe, deve ser chamado com lock)
+func (c *Context) copyNames() map[string]string {
+	names := make(map[string]string, len(c.names))
+	for addr, name := range c.names {
+		names[addr] = name
+	}
+	return names
+}
+
+// nameOwner retorna o endereço que registrou o nome (comparação sem diferenciar maiúsculas)
+func nameOwner(names map[string]string, name string) string {
+	for addr, registered := range names {
+		if strings.EqualFold(registered, name) {
+			return addr
+		}
+	}
+	return ""
+}
+
+// GetName retorna o nome de exibição registrado para um endereço ("" se não houver)
+func (c *Context) GetName(address string) string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.names[address]
+}
+
+// GetAllNames retorna todos os nomes registrados (endereço -> nome)
+func (c *Context) GetAllNames() map[string]string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.copyNames()
+}
+
 // GetValidators retorna a lista de validadores ativos (endereços com stake > 0)
 func (c *Context) GetValidators() ValidatorList {
 	stakes := c.GetAllStakes()
+	names := c.GetAllNames()
 
 	validators := make(ValidatorList, 0, len(stakes))
 	for address, stake := range stakes {
@@ -421,6 +1006,7 @@ func (c *Context) GetValidators() ValidatorList {
 			validators = append(validators, Validator{
 				Address: address,
 				Stake:   stake,
+				Name:    names[address],
 			})
 		}
 	}
@@ -442,6 +1028,7 @@ func (c *Context) Reset() {
 
 	c.blocks = make(map[string]*BlockContext)
 	c.currentState = make(StateModifications)
+	c.names = make(map[string]string)
 	c.lastBlockHash = ""
 	c.lastBlockHeight = 0
 }
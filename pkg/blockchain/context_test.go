@@ -1,6 +1,7 @@
 package blockchain
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/krakovia/blockchain/pkg/wallet"
@@ -319,3 +320,250 @@ func TestContextReset(t *testing.T) {
 		t.Error("Expected 0 balance after reset")
 	}
 }
+
+// Helper: cria transação de stake assinada
+func newStakeTx(t *testing.T, w *wallet.Wallet, amount, nonce uint64) *Transaction {
+	t.Helper()
+
+	dataStr, _ := NewStakeData(amount).Serialize()
+	tx := NewTransaction(w.GetAddress(), w.GetAddress(), amount, 1, nonce, dataStr)
+	if err := tx.Sign(w); err != nil {
+		t.Fatalf("Failed to sign stake transaction: %v", err)
+	}
+	return tx
+}
+
+func TestContextStakeBelowMinimumRejected(t *testing.T) {
+	w, _ := wallet.NewWallet()
+	genesis := GenesisBlock(NewCoinbaseTransaction(w.GetAddress(), 1000, 0))
+
+	ctx, _ := NewContextWithGenesis(genesis)
+	ctx.SetMinStake(500)
+
+	if _, err := ctx.ExecuteTransaction(newStakeTx(t, w, 499, 0)); err == nil {
+		t.Error("Stake below minimum should be rejected")
+	}
+}
+
+func TestContextStakeAtMinimumAccepted(t *testing.T) {
+	w, _ := wallet.NewWallet()
+	genesis := GenesisBlock(NewCoinbaseTransaction(w.GetAddress(), 1000, 0))
+
+	ctx, _ := NewContextWithGenesis(genesis)
+	ctx.SetMinStake(500)
+
+	if _, err := ctx.ExecuteTransaction(newStakeTx(t, w, 500, 0)); err != nil {
+		t.Errorf("Stake at minimum should be accepted: %v", err)
+	}
+
+	// Com stake existente, o mínimo vale para o total acumulado
+	ctx.SetStake(w.GetAddress(), 400)
+	if _, err := ctx.ExecuteTransaction(newStakeTx(t, w, 100, 0)); err != nil {
+		t.Errorf("Top-up reaching the minimum should be accepted: %v", err)
+	}
+}
+
+func TestChainAppliesMinValidatorStake(t *testing.T) {
+	w, _ := wallet.NewWallet()
+	genesis := GenesisBlock(NewCoinbaseTransaction(w.GetAddress(), 1000, 0))
+
+	config := DefaultChainConfig()
+	config.MinValidatorStake = 300
+	chain, err := NewChain(genesis, config)
+	if err != nil {
+		t.Fatalf("Failed to create chain: %v", err)
+	}
+
+	if _, err := chain.context.ExecuteTransaction(newStakeTx(t, w, 299, 0)); err == nil {
+		t.Error("Chain context should enforce MinValidatorStake")
+	}
+}
+
+func newRegisterNameTx(t *testing.T, w *wallet.Wallet, name string, nonce uint64) *Transaction {
+	t.Helper()
+
+	dataStr, _ := NewRegisterNameData(name).Serialize()
+	tx := NewTransaction(w.GetAddress(), w.GetAddress(), 0, 1, nonce, dataStr)
+	if err := tx.Sign(w); err != nil {
+		t.Fatalf("Failed to sign register name transaction: %v", err)
+	}
+	return tx
+}
+
+// Helper: adiciona um bloco com as transações ao contexto
+func addTestBlock(t *testing.T, ctx *Context, txs TransactionSlice, validator string) {
+	t.Helper()
+
+	block := NewBlock(ctx.GetLastBlockHeight()+1, ctx.GetLastBlockHash(), txs, validator)
+	hash, _ := block.CalculateHash()
+	block.Hash = hash
+	if err := ctx.AddBlock(block); err != nil {
+		t.Fatalf("Failed to add block: %v", err)
+	}
+}
+
+func TestContextRegisterName(t *testing.T) {
+	w, _ := wallet.NewWallet()
+	genesis := GenesisBlock(NewCoinbaseTransaction(w.GetAddress(), 2000, 0))
+	ctx, _ := NewContextWithGenesis(genesis)
+
+	addTestBlock(t, ctx, TransactionSlice{newStakeTx(t, w, 1000, 0), newRegisterNameTx(t, w, "Krakow Pool", 1)}, w.GetAddress())
+
+	if name := ctx.GetName(w.GetAddress()); name != "Krakow Pool" {
+		t.Errorf("Expected name 'Krakow Pool', got %q", name)
+	}
+	// Registro paga apenas a fee (stake 1000 + fee 1 + fee 1)
+	if balance := ctx.GetBalance(w.GetAddress()); balance != 998 {
+		t.Errorf("Expected balance 998, got %d", balance)
+	}
+	if nonce := ctx.GetNonce(w.GetAddress()); nonce != 2 {
+		t.Errorf("Expected nonce 2, got %d", nonce)
+	}
+
+	validators := ctx.GetValidators()
+	if len(validators) != 1 || validators[0].Name != "Krakow Pool" {
+		t.Errorf("Validator should carry registered name, got %+v", validators)
+	}
+}
+
+func TestContextRegisterNameUniqueness(t *testing.T) {
+	w1, _ := wallet.NewWallet()
+	w2, _ := wallet.NewWallet()
+	genesis := GenesisBlock(NewCoinbaseTransaction(w1.GetAddress(), 2000, 0))
+	ctx, _ := NewContextWithGenesis(genesis)
+
+	tx := NewTransaction(w1.GetAddress(), w2.GetAddress(), 500, 1, 0, "")
+	_ = tx.Sign(w1)
+	addTestBlock(t, ctx, TransactionSlice{tx, newRegisterNameTx(t, w1, "alpha", 1)}, w1.GetAddress())
+
+	// Nome já registrado (sem diferenciar maiúsculas) é rejeitado para outro endereço
+	_, err := ctx.ExecuteTransaction(newRegisterNameTx(t, w2, "ALPHA", 0))
+	if err == nil || !strings.Contains(err.Error(), "already registered") {
+		t.Errorf("Expected duplicate name error, got: %v", err)
+	}
+
+	// Dois registros do mesmo nome no mesmo bloco: só o primeiro é selecionado
+	selected := ctx.SelectExecutableTransactions([]*Transaction{
+		newRegisterNameTx(t, w2, "beta", 0),
+		newRegisterNameTx(t, w1, "Beta", 2),
+	}, 0)
+	if len(selected) != 1 || selected[0].From != w2.GetAddress() {
+		t.Errorf("Expected only w2's registration to be selected, got %d", len(selected))
+	}
+
+	// O dono pode trocar de nome, liberando o anterior
+	addTestBlock(t, ctx, TransactionSlice{newRegisterNameTx(t, w1, "gamma", 2)}, w1.GetAddress())
+	addTestBlock(t, ctx, TransactionSlice{newRegisterNameTx(t, w2, "alpha", 0)}, w1.GetAddress())
+
+	if ctx.GetName(w1.GetAddress()) != "gamma" || ctx.GetName(w2.GetAddress()) != "alpha" {
+		t.Errorf("Unexpected names: w1=%q w2=%q", ctx.GetName(w1.GetAddress()), ctx.GetName(w2.GetAddress()))
+	}
+}
+
+func TestContextRegisterNameInvalid(t *testing.T) {
+	w, _ := wallet.NewWallet()
+	other, _ := wallet.NewWallet()
+	genesis := GenesisBlock(NewCoinbaseTransaction(w.GetAddress(), 1000, 0))
+	ctx, _ := NewContextWithGenesis(genesis)
+
+	for _, name := range []string{"ab", strings.Repeat("a", MaxValidatorNameLength+1), " padded", "bad,name", ""} {
+		if _, err := ctx.ExecuteTransaction(newRegisterNameTx(t, w, name, 0)); err == nil {
+			t.Errorf("Name %q should be rejected", name)
+		}
+	}
+	if _, err := ctx.ExecuteTransaction(newRegisterNameTx(t, w, strings.Repeat("a", MaxValidatorNameLength), 0)); err != nil {
+		t.Errorf("Name at max length should be accepted: %v", err)
+	}
+
+	// Registro não pode mover tokens nem ser destinado a outro endereço
+	dataStr, _ := NewRegisterNameData("valid").Serialize()
+	withAmount := NewTransaction(w.GetAddress(), w.GetAddress(), 10, 1, 0, dataStr)
+	_ = withAmount.Sign(w)
+	if _, err := ctx.ExecuteTransaction(withAmount); err == nil {
+		t.Error("Name registration with amount should be rejected")
+	}
+	toOther := NewTransaction(w.GetAddress(), other.GetAddress(), 0, 1, 0, dataStr)
+	_ = toOther.Sign(w)
+	if _, err := ctx.ExecuteTransaction(toOther); err == nil {
+		t.Error("Name registration to another address should be rejected")
+	}
+}
+
+func TestContextNamesFromState(t *testing.T) {
+	accounts := map[string]*AccountState{
+		"addr1": {Address: "addr1", Balance: 100, Stake: 1000, Name: "node-one"},
+		"addr2": {Address: "addr2", Balance: 50},
+	}
+
+	ctx := NewContextFromState(10, "hash10", accounts)
+
+	if ctx.GetName("addr1") != "node-one" {
+		t.Errorf("Expected restored name 'node-one', got %q", ctx.GetName("addr1"))
+	}
+	if ctx.GetName("addr2") != "" {
+		t.Errorf("Expected no name for addr2, got %q", ctx.GetName("addr2"))
+	}
+}
+
+func TestContextVestingLocksGenesisBalance(t *testing.T) {
+	w1, _ := wallet.NewWallet()
+	w2, _ := wallet.NewWallet()
+	genesis := GenesisBlock(NewCoinbaseTransaction(w1.GetAddress(), 1000, 0))
+	ctx, _ := NewContextWithGenesis(genesis)
+
+	// Tudo bloqueado até a altura 5; depois 100 por bloco até a altura 15
+	ctx.SetVesting([]VestingSchedule{{Address: w1.GetAddress(), Amount: 1000, CliffHeight: 5, VestingBlocks: 10}})
+
+	// Antes do cliff nenhum gasto é aceito
+	tx := NewTransaction(w1.GetAddress(), w2.GetAddress(), 10, 1, 0, "")
+	_ = tx.Sign(w1)
+	if _, err := ctx.ExecuteTransaction(tx); err == nil || !strings.Contains(err.Error(), "insufficient unlocked balance") {
+		t.Fatalf("Expected pre-cliff spend to fail with unlocked balance error, got %v", err)
+	}
+	if locked := ctx.GetLockedBalance(w1.GetAddress()); locked != 1000 {
+		t.Errorf("Expected 1000 locked before cliff, got %d", locked)
+	}
+
+	// Avança até a altura 6: o próximo bloco (7) libera 2 * 100
+	for ctx.GetLastBlockHeight() < 6 {
+		addTestBlock(t, ctx, TransactionSlice{}, w1.GetAddress())
+	}
+	if locked := ctx.GetLockedBalance(w1.GetAddress()); locked != 800 {
+		t.Fatalf("Expected 800 locked at height 7, got %d", locked)
+	}
+
+	// Gasta exatamente o liberado (199 + fee 1)
+	tx = NewTransaction(w1.GetAddress(), w2.GetAddress(), 199, 1, 0, "")
+	_ = tx.Sign(w1)
+	addTestBlock(t, ctx, TransactionSlice{tx}, w1.GetAddress())
+	if balance := ctx.GetBalance(w2.GetAddress()); balance != 199 {
+		t.Errorf("Expected recipient balance 199, got %d", balance)
+	}
+
+	// Altura 8 libera mais 100; 101 ultrapassa o liberado
+	over := NewTransaction(w1.GetAddress(), w2.GetAddress(), 100, 1, 1, "")
+	_ = over.Sign(w1)
+	if _, err := ctx.ExecuteTransaction(over); err == nil || !strings.Contains(err.Error(), "insufficient unlocked balance") {
+		t.Errorf("Expected spend above released amount to fail, got %v", err)
+	}
+	within := NewTransaction(w1.GetAddress(), w2.GetAddress(), 99, 1, 1, "")
+	_ = within.Sign(w1)
+	if _, err := ctx.ExecuteTransaction(within); err != nil {
+		t.Errorf("Expected spend within released amount to succeed, got %v", err)
+	}
+
+	// Stake também é um gasto do saldo bloqueado
+	if _, err := ctx.ExecuteTransaction(newStakeTx(t, w1, 500, 1)); err == nil {
+		t.Error("Expected stake of locked balance to fail")
+	}
+}
+
+func TestChainRejectsVestingAboveAllocation(t *testing.T) {
+	genesis := GenesisBlock(NewCoinbaseTransaction("genesis_addr", 1000, 0))
+	config := DefaultChainConfig()
+	config.Vesting = []VestingSchedule{{Address: "genesis_addr", Amount: 1001, CliffHeight: 10}}
+
+	if _, err := NewChain(genesis, config); err == nil {
+		t.Error("Expected vesting above genesis allocation to be rejected")
+	}
+}
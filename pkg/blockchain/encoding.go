@@ -0,0 +1,44 @@
+package blockchain
+
+import (
+	"encoding/binary"
+)
+
+// payloadWriter monta os bytes cobertos pelos hashes no formato BlockFormatVersion: um byte com a
+// versão do formato seguido dos campos em ordem fixa, inteiros em big-endian com largura fixa e
+// strings prefixadas pelo tamanho (uint32). Não depende da codificação JSON do Go nem da
+// arquitetura, então o mesmo bloco tem o mesmo hash em qualquer build.
+type payloadWriter struct {
+	buf []byte
+}
+
+// newPayloadWriter cria o writer já com o byte de versão do formato
+func newPayloadWriter() *payloadWriter {
+	return &payloadWriter{buf: []byte{byte(BlockFormatVersion)}}
+}
+
+// uint32 escreve v em 4 bytes big-endian
+func (w *payloadWriter) uint32(v uint32) {
+	w.buf = binary.BigEndian.AppendUint32(w.buf, v)
+}
+
+// uint64 escreve v em 8 bytes big-endian
+func (w *payloadWriter) uint64(v uint64) {
+	w.buf = binary.BigEndian.AppendUint64(w.buf, v)
+}
+
+// int64 escreve v em 8 bytes big-endian (complemento de dois)
+func (w *payloadWriter) int64(v int64) {
+	w.uint64(uint64(v))
+}
+
+// string escreve o tamanho de s (uint32) seguido dos bytes de s
+func (w *payloadWriter) string(s string) {
+	w.uint32(uint32(len(s)))
+	w.buf = append(w.buf, s...)
+}
+
+// bytes retorna o payload montado
+func (w *payloadWriter) bytes() []byte {
+	return w.buf
+}
@@ -0,0 +1,154 @@
+package blockchain
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/krakovia/blockchain/pkg/wallet"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/opt"
+)
+
+// Helper: grava no disco uma chain com 4 blocos de transferências e reabre o banco somente
+// leitura, como o chain-export faz; o bloco 1 é podado do disco
+func createExportDB(t *testing.T) (*leveldb.DB, *Chain) {
+	t.Helper()
+
+	owner, _ := wallet.NewWallet()
+	miner, _ := wallet.NewWallet()
+	dest, _ := wallet.NewWallet()
+
+	config := DefaultChainConfig()
+	config.BlockTime = 100 * time.Millisecond
+
+	genesis := GenesisBlock(NewCoinbaseTransaction(owner.GetAddress(), 10000, 0))
+	chain, err := NewChain(genesis, config)
+	if err != nil {
+		t.Fatalf("Failed to create chain: %v", err)
+	}
+
+	// Blocos 1-4 com 1, 2, 0 e 2 transferências (mais a coinbase de cada um)
+	nonce := uint64(0)
+	for _, count := range []int{1, 2, 0, 2} {
+		txs := make([]*Transaction, count)
+		for i := range txs {
+			txs[i] = NewTransaction(owner.GetAddress(), dest.GetAddress(), 100, 2, nonce, "")
+			if err := txs[i].Sign(owner); err != nil {
+				t.Fatalf("Failed to sign transaction: %v", err)
+			}
+			nonce++
+		}
+		mineBlockWith(t, chain, miner, txs...)
+	}
+
+	path := filepath.Join(t.TempDir(), "export.db")
+	db, err := leveldb.OpenFile(path, nil)
+	if err != nil {
+		t.Fatalf("Failed to open DB: %v", err)
+	}
+	if err := SaveBlocksToDB(db, chain.GetAllBlocks()); err != nil {
+		t.Fatalf("Failed to save blocks: %v", err)
+	}
+	pruned, _ := chain.GetBlockByHeight(1)
+	if err := DeleteBlockFromDB(db, pruned); err != nil {
+		t.Fatalf("Failed to delete block: %v", err)
+	}
+	if err := db.Close(); err != nil {
+		t.Fatalf("Failed to close DB: %v", err)
+	}
+
+	db, err = leveldb.OpenFile(path, &opt.Options{ReadOnly: true})
+	if err != nil {
+		t.Fatalf("Failed to reopen DB read-only: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	return db, chain
+}
+
+func TestExportBlocksJSON(t *testing.T) {
+	db, chain := createExportDB(t)
+
+	var out bytes.Buffer
+	result, err := ExportBlocks(db, &out, ExportFormatJSON, 0, 100)
+	if err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+
+	// Gênesis, 2, 3 e 4 (o bloco 1 foi podado); "to" é limitado à ponta
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	if len(lines) != 4 || result.Blocks != 4 || result.ToHeight != 4 {
+		t.Fatalf("Expected 4 blocks up to height 4, got %d lines and %+v", len(lines), result)
+	}
+	for i, height := range []uint64{0, 2, 3, 4} {
+		var block Block
+		if err := json.Unmarshal([]byte(lines[i]), &block); err != nil {
+			t.Fatalf("Line %d is not a block: %v", i+1, err)
+		}
+		expected, _ := chain.GetBlockByHeight(height)
+		if block.Header.Height != height || block.Hash != expected.Hash || len(block.Transactions) != len(expected.Transactions) {
+			t.Errorf("Line %d: expected block %d (%s), got %d (%s)", i+1, height, expected.Hash, block.Header.Height, block.Hash)
+		}
+	}
+
+	// Limites de altura
+	out.Reset()
+	result, err = ExportBlocks(db, &out, ExportFormatJSON, 2, 3)
+	if err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	if lines := strings.Count(out.String(), "\n"); lines != 2 || result.Blocks != 2 {
+		t.Errorf("Expected blocks 2 and 3, got %d lines and %+v", lines, result)
+	}
+}
+
+func TestExportBlocksCSV(t *testing.T) {
+	db, chain := createExportDB(t)
+
+	var out bytes.Buffer
+	result, err := ExportBlocks(db, &out, ExportFormatCSV, 0, 100)
+	if err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+
+	records, err := csv.NewReader(&out).ReadAll()
+	if err != nil {
+		t.Fatalf("Export is not valid CSV: %v", err)
+	}
+
+	// Cabeçalho + gênesis (1) + blocos 2 (3), 3 (1) e 4 (3)
+	if len(records) != 1+8 || result.Transactions != 8 {
+		t.Fatalf("Expected header and 8 transaction rows, got %d rows and %+v", len(records), result)
+	}
+	if strings.Join(records[0], ",") != "height,txid,from,to,amount,fee" {
+		t.Errorf("Unexpected header: %v", records[0])
+	}
+
+	block, _ := chain.GetBlockByHeight(2)
+	transfer := block.Transactions[1]
+	row := records[3] // Gênesis, coinbase do bloco 2, primeira transferência do bloco 2
+	expected := []string{"2", transfer.ID, transfer.From, transfer.To, "100", "2"}
+	if strings.Join(row, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected row %v, got %v", expected, row)
+	}
+}
+
+func TestExportBlocksInvalidInput(t *testing.T) {
+	db, _ := createExportDB(t)
+
+	var out bytes.Buffer
+	if _, err := ExportBlocks(db, &out, "xml", 0, 10); err == nil {
+		t.Error("Unknown format should fail")
+	}
+	if _, err := ExportBlocks(db, &out, ExportFormatJSON, 5, 2); err == nil {
+		t.Error("from greater than to should fail")
+	}
+	if _, err := ExportBlocks(nil, &out, ExportFormatJSON, 0, 10); err == nil {
+		t.Error("Nil database should fail")
+	}
+}
@@ -0,0 +1,100 @@
+package blockchain
+
+import (
+	"math"
+	"sort"
+)
+
+// ValidatorFairness blocos produzidos por um validador comparados com o esperado pelo stake
+type ValidatorFairness struct {
+	Address        string  `json:"address"`
+	Stake          uint64  `json:"stake"`
+	Blocks         int     `json:"blocks"`          // Blocos produzidos no intervalo
+	Share          float64 `json:"share"`           // Fração dos blocos produzidos
+	ExpectedShare  float64 `json:"expected_share"`  // Probabilidade de seleção (GetSelectionProbability)
+	ExpectedBlocks float64 `json:"expected_blocks"` // Blocos esperados no intervalo
+	Deviation      float64 `json:"deviation"`       // Share - ExpectedShare
+	ZScore         float64 `json:"z_score"`         // Desvio em desvios-padrão da binomial
+}
+
+// FairnessReport resultado da auditoria de rotação dos produtores de bloco
+type FairnessReport struct {
+	FromHeight       uint64              `json:"from_height"`
+	ToHeight         uint64              `json:"to_height"`
+	TotalBlocks      int                 `json:"total_blocks"`
+	Validators       []ValidatorFairness `json:"validators"`
+	ChiSquare        float64             `json:"chi_square"`         // Σ (observado - esperado)² / esperado
+	DegreesOfFreedom int                 `json:"degrees_of_freedom"` // Validadores com chance de seleção - 1
+	UnexpectedBlocks int                 `json:"unexpected_blocks"`  // Blocos de produtores sem stake na lista
+}
+
+// AuditFairness conta os blocos produzidos por cada validador e compara com a probabilidade
+// de seleção calculada a partir dos stakes. O gênesis e âncoras de checkpoint são ignorados.
+// Blocos de produtores fora da lista (ou sem stake) entram em UnexpectedBlocks e não no
+// chi-quadrado. Como a lista é uma só, stakes que mudaram ao longo do intervalo distorcem
+// o resultado: use intervalos em que o conjunto de validadores foi estável.
+func AuditFairness(blocks []*Block, validators ValidatorList) FairnessReport {
+	report := FairnessReport{}
+
+	counts := make(map[string]int)
+	for _, block := range blocks {
+		if block == nil || block.Header.Height == 0 || block.IsCheckpointAnchor() {
+			continue
+		}
+		if report.TotalBlocks == 0 || block.Header.Height < report.FromHeight {
+			report.FromHeight = block.Header.Height
+		}
+		if block.Header.Height > report.ToHeight {
+			report.ToHeight = block.Header.Height
+		}
+		counts[block.Header.ValidatorAddr]++
+		report.TotalBlocks++
+	}
+
+	total := float64(report.TotalBlocks)
+	for _, v := range validators {
+		probability := GetSelectionProbability(v.Address, validators)
+		entry := ValidatorFairness{
+			Address:        v.Address,
+			Stake:          v.Stake,
+			Blocks:         counts[v.Address],
+			ExpectedShare:  probability,
+			ExpectedBlocks: probability * total,
+		}
+		delete(counts, v.Address)
+
+		if total > 0 {
+			entry.Share = float64(entry.Blocks) / total
+			entry.Deviation = entry.Share - entry.ExpectedShare
+		}
+
+		if entry.ExpectedBlocks > 0 {
+			diff := float64(entry.Blocks) - entry.ExpectedBlocks
+			report.ChiSquare += diff * diff / entry.ExpectedBlocks
+			report.DegreesOfFreedom++
+
+			if variance := entry.ExpectedBlocks * (1 - probability); variance > 0 {
+				entry.ZScore = diff / math.Sqrt(variance)
+			}
+		} else {
+			report.UnexpectedBlocks += entry.Blocks
+		}
+
+		report.Validators = append(report.Validators, entry)
+	}
+
+	// Produtores que não estão na lista de validadores
+	for _, count := range counts {
+		report.UnexpectedBlocks += count
+	}
+
+	if report.DegreesOfFreedom > 0 {
+		report.DegreesOfFreedom--
+	}
+
+	sort.Slice(report.Validators, func(i, j int) bool {
+		return report.Validators[i].ExpectedShare > report.Validators[j].ExpectedShare
+	})
+
+	return report
+}
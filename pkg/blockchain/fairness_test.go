@@ -0,0 +1,123 @@
+package blockchain
+
+import (
+	"math"
+	"testing"
+)
+
+// Helper: chain sintética em que cada bloco é produzido pelo validador no topo da fila
+// de prioridade do bloco anterior (mesma regra da mineração)
+func buildSyntheticChain(t *testing.T, validators ValidatorList, length int) []*Block {
+	t.Helper()
+
+	genesis := NewBlock(0, "", TransactionSlice{}, "genesis")
+	genesis.Header.Timestamp = 1700000000
+	hash, err := genesis.CalculateHash()
+	if err != nil {
+		t.Fatalf("Failed to hash genesis: %v", err)
+	}
+	genesis.Hash = hash
+
+	blocks := []*Block{genesis}
+	for height := uint64(1); height <= uint64(length); height++ {
+		previous := blocks[len(blocks)-1]
+		pq, err := CalculateValidatorPriority(previous.Hash, validators)
+		if err != nil {
+			t.Fatalf("Failed to calculate priority: %v", err)
+		}
+
+		block := NewBlock(height, previous.Hash, TransactionSlice{}, pq.GetTopValidator().Address)
+		block.Header.Timestamp = genesis.Header.Timestamp + int64(height)
+		if block.Hash, err = block.CalculateHash(); err != nil {
+			t.Fatalf("Failed to hash block: %v", err)
+		}
+		blocks = append(blocks, block)
+	}
+	return blocks
+}
+
+func TestAuditFairnessMatchesStakes(t *testing.T) {
+	validators := ValidatorList{
+		{Address: "big", Stake: 500},
+		{Address: "mid", Stake: 300},
+		{Address: "small", Stake: 150},
+		{Address: "tiny", Stake: 50},
+	}
+	blocks := buildSyntheticChain(t, validators, 5000)
+
+	report := AuditFairness(blocks, validators)
+	if report.TotalBlocks != 5000 || report.FromHeight != 1 || report.ToHeight != 5000 {
+		t.Fatalf("Expected 5000 blocks from 1 to 5000, got %d from %d to %d", report.TotalBlocks, report.FromHeight, report.ToHeight)
+	}
+	if report.DegreesOfFreedom != 3 || report.UnexpectedBlocks != 0 {
+		t.Errorf("Expected 3 degrees of freedom and no unexpected blocks, got %d and %d", report.DegreesOfFreedom, report.UnexpectedBlocks)
+	}
+
+	totalShare := 0.0
+	for _, v := range report.Validators {
+		t.Logf("Validator %s: %d blocks, share %.4f, expected %.4f (z=%.2f)", v.Address, v.Blocks, v.Share, v.ExpectedShare, v.ZScore)
+
+		if math.Abs(v.Deviation) > 0.02 {
+			t.Errorf("Validator %s: share %.4f too far from expected %.4f", v.Address, v.Share, v.ExpectedShare)
+		}
+		if math.Abs(v.ZScore) > 4 {
+			t.Errorf("Validator %s: z-score %.2f too large for a fair selection", v.Address, v.ZScore)
+		}
+		totalShare += v.ExpectedShare
+	}
+	if math.Abs(totalShare-1) > 1e-9 {
+		t.Errorf("Expected shares should sum to 1, got %.12f", totalShare)
+	}
+
+	// Ordenado do maior para o menor stake
+	if report.Validators[0].Address != "big" || report.Validators[3].Address != "tiny" {
+		t.Errorf("Expected validators sorted by expected share, got %s first and %s last", report.Validators[0].Address, report.Validators[3].Address)
+	}
+
+	// Valor crítico do chi-quadrado com 3 graus de liberdade a 0.1%: 16.27
+	if report.ChiSquare > 16.27 {
+		t.Errorf("Chi-square %.2f too large for a fair selection", report.ChiSquare)
+	}
+}
+
+func TestAuditFairnessDetectsBias(t *testing.T) {
+	validators := ValidatorList{
+		{Address: "a", Stake: 100},
+		{Address: "b", Stake: 100},
+	}
+
+	// Seleção quebrada: "a" produz 3 de cada 4 blocos apesar do stake igual
+	var blocks []*Block
+	for height := uint64(1); height <= 400; height++ {
+		producer := "a"
+		if height%4 == 0 {
+			producer = "b"
+		}
+		blocks = append(blocks, NewBlock(height, "", TransactionSlice{}, producer))
+	}
+	// Produtor sem stake na lista
+	blocks = append(blocks, NewBlock(401, "", TransactionSlice{}, "outsider"))
+
+	report := AuditFairness(blocks, validators)
+	if report.UnexpectedBlocks != 1 {
+		t.Errorf("Expected 1 block from an unknown producer, got %d", report.UnexpectedBlocks)
+	}
+
+	// Esperado 200.5 para cada: (300-200.5)² / 200.5 + (100-200.5)² / 200.5 ≈ 99.5
+	if math.Abs(report.ChiSquare-99.5) > 0.5 {
+		t.Errorf("Expected chi-square around 99.5, got %.2f", report.ChiSquare)
+	}
+	if report.Validators[0].ZScore < 9 && report.Validators[1].ZScore < 9 {
+		t.Errorf("Expected a large z-score for the favored validator, got %.2f and %.2f", report.Validators[0].ZScore, report.Validators[1].ZScore)
+	}
+}
+
+func TestAuditFairnessEmptyChain(t *testing.T) {
+	validators := ValidatorList{{Address: "a", Stake: 100}}
+
+	// Só o gênesis: nada a auditar
+	report := AuditFairness([]*Block{NewBlock(0, "", TransactionSlice{}, "a")}, validators)
+	if report.TotalBlocks != 0 || report.ChiSquare != 0 || report.Validators[0].Share != 0 {
+		t.Errorf("Expected empty report, got %+v", report)
+	}
+}
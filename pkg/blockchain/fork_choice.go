@@ -0,0 +1,193 @@
+package blockchain
+
+import "fmt"
+
+// ReorgResult resultado da escolha entre a chain principal e um fork
+type ReorgResult struct {
+	Reorganized bool     // O fork era mais pesado e passou a ser a chain principal
+	ForkHeight  uint64   // Altura do último bloco em comum
+	Replaced    []*Block // Blocos que saíram da chain principal (do mais antigo ao mais novo)
+	OldWeight   uint64   // Peso acumulado da chain principal antes da escolha
+	NewWeight   uint64   // Peso acumulado do fork
+}
+
+// CumulativeWeight retorna o peso acumulado da chain principal: a soma, bloco a bloco, do
+// stake que o produtor tinha antes do bloco. Após um fast sync conta só a partir do checkpoint.
+func (c *Chain) CumulativeWeight() uint64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.weights[c.blocks[len(c.blocks)-1].Hash]
+}
+
+// GetBlockWeight retorna o peso acumulado até um bloco da chain principal
+func (c *Chain) GetBlockWeight(hash string) (uint64, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	weight, ok := c.weights[hash]
+	return weight, ok
+}
+
+// Reorganize decide entre a chain principal e um fork formado por branch (blocos consecutivos
+// cujo primeiro se conecta a um bloco da chain principal). O fork vence se tiver peso
+// acumulado maior; em caso de empate, só se for mais longo. Blocos do início do fork que já
+// estão na chain principal são ignorados. Um fork inválido retorna erro e a chain permanece
+// como estava; um fork válido porém mais leve retorna Reorganized = false. Forks que substituiriam
+// mais que MaxReorgDepth blocos da chain principal são rejeitados (esses blocos são finais).
+func (c *Chain) Reorganize(branch []*Block) (*ReorgResult, error) {
+	for i, block := range branch {
+		if evidence := c.checkDoubleSign(block); evidence != nil {
+			c.notifyDoubleSign(evidence)
+			err := fmt.Errorf("double sign detected: validator %s already signed another block at height %d",
+				block.Header.ValidatorAddr, block.Header.Height)
+			return nil, &BatchError{Index: i, Height: block.Header.Height, Err: err}
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Descarta o prefixo que a chain principal já tem
+	for len(branch) > 0 {
+		if _, onMain := c.mainChainIndexLocked(branch[0].Hash); !onMain {
+			break
+		}
+		branch = branch[1:]
+	}
+	if len(branch) == 0 {
+		return nil, fmt.Errorf("fork has no blocks outside the main chain")
+	}
+
+	forkIndex, ok := c.mainChainIndexLocked(branch[0].Header.PreviousHash)
+	if !ok {
+		return nil, fmt.Errorf("fork does not connect to the main chain: unknown parent %s", branch[0].Header.PreviousHash)
+	}
+	fork := c.blocks[forkIndex]
+	tip := c.blocks[len(c.blocks)-1]
+	if depth := tip.Header.Height - fork.Header.Height; c.config.MaxReorgDepth > 0 && depth > c.config.MaxReorgDepth {
+		return nil, fmt.Errorf("fork replaces %d blocks, finalized chain allows at most %d", depth, c.config.MaxReorgDepth)
+	}
+	suffix := append([]*Block(nil), c.blocks[forkIndex+1:]...)
+
+	result := &ReorgResult{
+		ForkHeight: fork.Header.Height,
+		OldWeight:  c.weights[tip.Hash],
+	}
+
+	// Tokens emitidos até o bloco em comum
+	minted := c.minted
+	for _, block := range suffix {
+		if coinbase := block.GetCoinbaseTransaction(); coinbase != nil {
+			minted -= coinbase.Amount
+		}
+	}
+
+	// Os blocos da chain principal depois do fork saem do índice durante a validação do fork
+	for _, block := range suffix {
+		delete(c.blocksByHash, block.Hash)
+	}
+	restoreIndex := func() {
+		for _, block := range suffix {
+			c.blocksByHash[block.Hash] = block
+		}
+	}
+
+	// Valida o fork sobre o bloco em comum
+	lastBlock := fork
+	rewards := make([]uint64, len(branch))
+	branchMinted := minted
+	for i, block := range branch {
+		reward, err := c.validateBlockLocked(block, lastBlock, branchMinted)
+		if err != nil {
+			restoreIndex()
+			return nil, &BatchError{Index: i, Height: block.Header.Height, Err: err}
+		}
+		rewards[i] = reward
+		branchMinted += reward
+		lastBlock = block
+	}
+
+	// Executa o fork sobre o estado do bloco em comum
+	if err := c.context.RollbackTo(fork.Hash); err != nil {
+		restoreIndex()
+		return nil, fmt.Errorf("failed to roll back to fork height %d: %w", fork.Header.Height, err)
+	}
+	if err := c.context.AddBlocks(branch); err != nil {
+		restoreIndex()
+		if restoreErr := c.restoreSuffixLocked(fork, suffix); restoreErr != nil {
+			return nil, restoreErr
+		}
+		return nil, fmt.Errorf("failed to execute fork: %w", err)
+	}
+
+	result.NewWeight = c.weights[fork.Hash]
+	for _, block := range branch {
+		result.NewWeight += c.context.blockWeight(block.Hash)
+	}
+
+	// Mantém a chain principal se o fork não for mais pesado
+	longer := branch[len(branch)-1].Header.Height > tip.Header.Height
+	if result.NewWeight < result.OldWeight || (result.NewWeight == result.OldWeight && !longer) {
+		restoreIndex()
+		if err := c.restoreSuffixLocked(fork, suffix); err != nil {
+			return nil, err
+		}
+		return result, nil
+	}
+
+	// Troca a chain principal pelo fork
+	for _, block := range suffix {
+		delete(c.weights, block.Hash)
+	}
+	c.blocks = c.blocks[:forkIndex+1]
+	c.minted = minted
+	for i, block := range branch {
+		c.appendBlockLocked(block, rewards[i])
+	}
+
+	// Entradas do índice de endereços dos blocos substituídos são ignoradas na leitura
+	if c.db != nil {
+		for _, block := range branch {
+			if err := IndexBlockAddresses(c.db, block); err != nil {
+				fmt.Printf("⚠️  Failed to index addresses of block %d: %v\n", block.Header.Height, err)
+			}
+		}
+	}
+
+	result.Reorganized = true
+	result.Replaced = suffix
+	return result, nil
+}
+
+// mainChainIndexLocked retorna a posição de um bloco na chain principal em memória (deve ser
+// chamado com lock)
+func (c *Chain) mainChainIndexLocked(hash string) (int, bool) {
+	block, ok := c.blocksByHash[hash]
+	if !ok || len(c.blocks) == 0 {
+		return 0, false
+	}
+
+	first := c.blocks[0].Header.Height
+	if block.Header.Height < first {
+		return 0, false
+	}
+	index := int(block.Header.Height - first)
+	if index >= len(c.blocks) || c.blocks[index].Hash != hash {
+		return 0, false
+	}
+	return index, true
+}
+
+// restoreSuffixLocked volta o contexto para a chain principal depois de executar um fork
+// que não foi adotado (deve ser chamado com lock)
+func (c *Chain) restoreSuffixLocked(fork *Block, suffix []*Block) error {
+	if err := c.context.RollbackTo(fork.Hash); err != nil {
+		return fmt.Errorf("failed to roll back fork: %w", err)
+	}
+	if len(suffix) == 0 {
+		return nil
+	}
+	if err := c.context.AddBlocks(suffix); err != nil {
+		return fmt.Errorf("failed to restore main chain after fork: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,177 @@
+package blockchain
+
+import (
+	"testing"
+
+	"github.com/krakovia/blockchain/pkg/wallet"
+)
+
+// Helper: count blocos assinados por validator a partir de parent, com timestamps a partir de start
+func createForkBlocks(t *testing.T, parent *Block, validator *wallet.Wallet, count int, start int64) []*Block {
+	t.Helper()
+
+	blocks := make([]*Block, 0, count)
+	for i := 0; i < count; i++ {
+		height := parent.Header.Height + 1
+		txs := TransactionSlice{NewCoinbaseTransaction(validator.GetAddress(), 50, height)}
+		block := NewBlock(height, parent.Hash, txs, validator.GetAddress())
+		block.Header.Timestamp = start + int64(i)
+		if err := block.Sign(validator); err != nil {
+			t.Fatalf("Failed to sign block: %v", err)
+		}
+		blocks = append(blocks, block)
+		parent = block
+	}
+	return blocks
+}
+
+func TestReorganizePrefersHigherStakeWeight(t *testing.T) {
+	heavy, _ := wallet.NewWallet()
+	light, _ := wallet.NewWallet()
+	lighter, _ := wallet.NewWallet()
+	chain := createStakedChain(t, map[string]uint64{
+		heavy.GetAddress():   1000,
+		light.GetAddress():   10,
+		lighter.GetAddress(): 5,
+	})
+	genesis := chain.GetGenesis()
+
+	// Chain principal: 4 blocos do validador com pouco stake (peso 40)
+	lightBlocks := createForkBlocks(t, genesis, light, 4, genesis.Header.Timestamp+1)
+	if err := chain.AddBlocks(lightBlocks); err != nil {
+		t.Fatalf("Failed to add light blocks: %v", err)
+	}
+	if weight := chain.CumulativeWeight(); weight != 40 {
+		t.Fatalf("Expected cumulative weight 40, got %d", weight)
+	}
+
+	// Fork mais longo (altura 6) porém mais leve (10 + 5*5 = 35) não substitui a chain
+	longer := append([]*Block{lightBlocks[0]}, createForkBlocks(t, lightBlocks[0], lighter, 5, genesis.Header.Timestamp+10)...)
+	result, err := chain.Reorganize(longer)
+	if err != nil {
+		t.Fatalf("Valid lighter fork should not fail: %v", err)
+	}
+	if result.Reorganized || result.NewWeight != 35 || result.ForkHeight != 1 {
+		t.Fatalf("Longer but lighter fork should lose: %+v", result)
+	}
+	if last := chain.GetLastBlock(); last.Hash != lightBlocks[3].Hash {
+		t.Fatal("Main chain tip should be kept")
+	}
+	if balance := chain.GetBalance(lighter.GetAddress()); balance != 0 {
+		t.Errorf("Losing fork should not change the state, balance %d", balance)
+	}
+
+	// Fork mais curto (2 blocos) do validador com mais stake vence (peso 2000 > 40)
+	heavyBlocks := createForkBlocks(t, genesis, heavy, 2, genesis.Header.Timestamp+20)
+	result, err = chain.Reorganize(heavyBlocks)
+	if err != nil {
+		t.Fatalf("Failed to reorganize: %v", err)
+	}
+	if !result.Reorganized || result.OldWeight != 40 || result.NewWeight != 2000 {
+		t.Fatalf("Heavier fork should win: %+v", result)
+	}
+	if len(result.Replaced) != 4 || result.ForkHeight != 0 {
+		t.Errorf("Expected 4 replaced blocks from height 0, got %d from height %d", len(result.Replaced), result.ForkHeight)
+	}
+
+	if height := chain.GetHeight(); height != 2 {
+		t.Errorf("Expected height 2 after reorganization, got %d", height)
+	}
+	if last := chain.GetLastBlock(); last.Hash != heavyBlocks[1].Hash {
+		t.Errorf("Tip should be the heavy fork tip")
+	}
+	if weight := chain.CumulativeWeight(); weight != 2000 {
+		t.Errorf("Expected cumulative weight 2000, got %d", weight)
+	}
+	if _, exists := chain.GetBlock(lightBlocks[3].Hash); exists {
+		t.Error("Replaced blocks should leave the main chain")
+	}
+
+	// O estado é o do fork: recompensas do validador leve desfeitas
+	if balance := chain.GetBalance(light.GetAddress()); balance != 0 {
+		t.Errorf("Light validator rewards should be reverted, balance %d", balance)
+	}
+	if balance := chain.GetBalance(heavy.GetAddress()); balance != 100 {
+		t.Errorf("Heavy validator should have 2 rewards, balance %d", balance)
+	}
+
+	// A chain continua crescendo a partir do fork adotado
+	next := createForkBlocks(t, heavyBlocks[1], heavy, 1, genesis.Header.Timestamp+30)
+	if err := chain.AddBlock(next[0]); err != nil {
+		t.Fatalf("Failed to extend reorganized chain: %v", err)
+	}
+	if weight := chain.CumulativeWeight(); weight != 3000 {
+		t.Errorf("Expected cumulative weight 3000, got %d", weight)
+	}
+}
+
+func TestReorganizeInvalidForkKeepsChain(t *testing.T) {
+	heavy, _ := wallet.NewWallet()
+	light, _ := wallet.NewWallet()
+	chain := createStakedChain(t, map[string]uint64{
+		heavy.GetAddress(): 1000,
+		light.GetAddress(): 10,
+	})
+	genesis := chain.GetGenesis()
+
+	lightBlocks := createForkBlocks(t, genesis, light, 3, genesis.Header.Timestamp+1)
+	if err := chain.AddBlocks(lightBlocks); err != nil {
+		t.Fatalf("Failed to add light blocks: %v", err)
+	}
+
+	// Segundo bloco do fork com coinbase acima da recompensa
+	forged := createForkBlocks(t, genesis, heavy, 2, genesis.Header.Timestamp+10)
+	forged[1].Transactions[0].Amount = 1_000_000
+	forged[1].Header.MerkleRoot = forged[1].Transactions.CalculateMerkleRoot()
+	if err := forged[1].Sign(heavy); err != nil {
+		t.Fatalf("Failed to sign block: %v", err)
+	}
+
+	if _, err := chain.Reorganize(forged); err == nil {
+		t.Fatal("Invalid fork should be rejected")
+	}
+	if last := chain.GetLastBlock(); last.Hash != lightBlocks[2].Hash || chain.GetHeight() != 3 {
+		t.Errorf("Main chain should be kept after an invalid fork")
+	}
+	if balance := chain.GetBalance(light.GetAddress()); balance != 150 {
+		t.Errorf("Main chain state should be kept, light balance %d", balance)
+	}
+	if weight := chain.CumulativeWeight(); weight != 30 {
+		t.Errorf("Expected cumulative weight 30, got %d", weight)
+	}
+}
+
+func TestReorganizeRespectsMaxReorgDepth(t *testing.T) {
+	heavy, _ := wallet.NewWallet()
+	light, _ := wallet.NewWallet()
+	chain := createStakedChain(t, map[string]uint64{
+		heavy.GetAddress(): 1000,
+		light.GetAddress(): 10,
+	})
+	chain.config.MaxReorgDepth = 2
+	genesis := chain.GetGenesis()
+
+	lightBlocks := createForkBlocks(t, genesis, light, 3, genesis.Header.Timestamp+1)
+	if err := chain.AddBlocks(lightBlocks); err != nil {
+		t.Fatalf("Failed to add light blocks: %v", err)
+	}
+
+	// Fork a partir do gênesis substituiria 3 blocos finais, mesmo sendo mais pesado
+	deep := createForkBlocks(t, genesis, heavy, 1, genesis.Header.Timestamp+10)
+	if _, err := chain.Reorganize(deep); err == nil {
+		t.Fatal("Fork deeper than MaxReorgDepth should be rejected")
+	}
+	if last := chain.GetLastBlock(); last.Hash != lightBlocks[2].Hash {
+		t.Fatal("Main chain should be kept after a too deep fork")
+	}
+
+	// Fork a partir da altura 1 substitui 2 blocos e é aceito
+	shallow := createForkBlocks(t, lightBlocks[0], heavy, 1, genesis.Header.Timestamp+20)
+	result, err := chain.Reorganize(shallow)
+	if err != nil {
+		t.Fatalf("Fork within MaxReorgDepth should be accepted: %v", err)
+	}
+	if !result.Reorganized || len(result.Replaced) != 2 {
+		t.Errorf("Expected reorganization replacing 2 blocks, got %+v", result)
+	}
+}
@@ -0,0 +1,177 @@
+package blockchain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/krakovia/blockchain/pkg/wallet"
+)
+
+// CalculateHash calcula o hash do header (sem incluir a assinatura). É o hash do bloco:
+// as transações entram pela raiz de Merkle.
+func (h *BlockHeader) CalculateHash() (string, error) {
+	data, err := h.hashPayload()
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:]), nil
+}
+
+// hashPayload retorna os bytes do header cobertos pelo hash (sem a assinatura), no formato
+// BlockFormatVersion (ver payloadWriter). Headers de uma versão mais nova que BlockFormatVersion
+// são recusados em vez de calculados no formato antigo.
+func (h *BlockHeader) hashPayload() ([]byte, error) {
+	if h.Version > BlockFormatVersion {
+		return nil, fmt.Errorf("unsupported block format version %d (max %d)", h.Version, BlockFormatVersion)
+	}
+
+	w := newPayloadWriter()
+	w.uint32(h.Version)
+	w.uint64(h.Height)
+	w.int64(h.Timestamp)
+	w.string(h.PreviousHash)
+	w.string(h.MerkleRoot)
+	w.string(h.ValidatorAddr)
+	w.string(h.PublicKey)
+	w.uint64(h.Nonce)
+	return w.bytes(), nil
+}
+
+// VerifySignature verifica, sem o corpo do bloco, se o header foi assinado pelo validador
+// indicado em ValidatorAddr
+func (h *BlockHeader) VerifySignature() error {
+	if err := h.verifyValidatorKey(); err != nil {
+		return err
+	}
+
+	hash, err := h.CalculateHash()
+	if err != nil {
+		return err
+	}
+
+	return h.verifySignatureOf(hash)
+}
+
+// verifyValidatorKey verifica que o header tem assinatura e que a chave pública pertence ao validador
+func (h *BlockHeader) verifyValidatorKey() error {
+	if h.Signature == "" {
+		return fmt.Errorf("block signature is missing")
+	}
+	if h.PublicKey == "" {
+		return fmt.Errorf("block public key is missing")
+	}
+
+	// A chave pública precisa pertencer ao validador declarado
+	expectedAddress, err := wallet.AddressFromPublicKey(h.PublicKey)
+	if err != nil {
+		return fmt.Errorf("failed to derive address from block public key: %w", err)
+	}
+	if expectedAddress != h.ValidatorAddr {
+		return fmt.Errorf("block public key does not match validator address")
+	}
+
+	return nil
+}
+
+// verifySignatureOf verifica a assinatura do validador sobre o hash do bloco
+func (h *BlockHeader) verifySignatureOf(hash string) error {
+	valid, err := wallet.Verify(h.PublicKey, []byte(hash), h.Signature)
+	if err != nil {
+		return fmt.Errorf("failed to verify block signature: %w", err)
+	}
+	if !valid {
+		return fmt.Errorf("invalid block signature")
+	}
+
+	return nil
+}
+
+// Serialize serializa o header para JSON
+func (h *BlockHeader) Serialize() ([]byte, error) {
+	return json.Marshal(h)
+}
+
+// DeserializeBlockHeader desserializa um header de JSON
+func DeserializeBlockHeader(data []byte) (*BlockHeader, error) {
+	var header BlockHeader
+	if err := json.Unmarshal(data, &header); err != nil {
+		return nil, fmt.Errorf("failed to deserialize block header: %w", err)
+	}
+	return &header, nil
+}
+
+// UnknownValidatorError indica um header assinado por um endereço que não está entre os
+// validadores com stake informados. Os headers antes de Index são válidos e, como um deles pode
+// ser o stake do novo validador, o chamador pode aplicá-los e validar o resto com o estado novo.
+type UnknownValidatorError struct {
+	Index     int    // Posição do header no lote
+	Height    uint64 // Altura do header
+	Validator string // Endereço declarado no header
+}
+
+// Error implementa a interface error
+func (e *UnknownValidatorError) Error() string {
+	return fmt.Sprintf("header %d (height %d): validator %s has no stake", e.Index, e.Height, e.Validator)
+}
+
+// VerifyHeaderChain valida headers consecutivos que continuam o bloco previous (com hash
+// previousHash) sem os corpos dos blocos: hash, assinatura do validador, validador com stake em
+// validators, encadeamento, altura e tempo mínimo entre blocos. Timestamps são aceitos até
+// config.MaxClockDrift depois de now. Retorna o hash de cada header; com um
+// *UnknownValidatorError, retorna também os hashes dos headers válidos antes dele.
+func VerifyHeaderChain(previous BlockHeader, previousHash string, headers []BlockHeader, validators ValidatorList, config ChainConfig, now time.Time) ([]string, error) {
+	minBlockTime := int64(config.BlockTime.Seconds() * 0.8)
+	limit := now.Add(config.ClockDrift()).Unix()
+
+	staked := make(map[string]bool, len(validators))
+	for _, v := range validators {
+		if v.Stake > 0 {
+			staked[v.Address] = true
+		}
+	}
+
+	hashes := make([]string, 0, len(headers))
+	for i := range headers {
+		header := &headers[i]
+
+		if header.Height != previous.Height+1 {
+			return nil, fmt.Errorf("header %d: invalid height: expected %d, got %d",
+				i, previous.Height+1, header.Height)
+		}
+		if header.PreviousHash != previousHash {
+			return nil, fmt.Errorf("header %d (height %d) does not connect: expected previous hash %s, got %s",
+				i, header.Height, previousHash, header.PreviousHash)
+		}
+		if header.Timestamp > limit {
+			return nil, fmt.Errorf("header %d (height %d): timestamp is too far in the future", i, header.Height)
+		}
+		if header.Timestamp < previous.Timestamp+minBlockTime {
+			return nil, fmt.Errorf("header %d (height %d): mined too fast: timestamp %d < minimum %d",
+				i, header.Height, header.Timestamp, previous.Timestamp+minBlockTime)
+		}
+
+		hash, err := header.CalculateHash()
+		if err != nil {
+			return nil, fmt.Errorf("header %d (height %d): %w", i, header.Height, err)
+		}
+		if err := header.verifyValidatorKey(); err != nil {
+			return nil, fmt.Errorf("header %d (height %d): %w", i, header.Height, err)
+		}
+		if err := header.verifySignatureOf(hash); err != nil {
+			return nil, fmt.Errorf("header %d (height %d): %w", i, header.Height, err)
+		}
+		if !staked[header.ValidatorAddr] {
+			return hashes, &UnknownValidatorError{Index: i, Height: header.Height, Validator: header.ValidatorAddr}
+		}
+
+		hashes = append(hashes, hash)
+		previous, previousHash = *header, hash
+	}
+
+	return hashes, nil
+}
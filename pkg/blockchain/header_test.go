@@ -0,0 +1,158 @@
+package blockchain
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/krakovia/blockchain/pkg/wallet"
+)
+
+func headersOf(blocks []*Block) []BlockHeader {
+	headers := make([]BlockHeader, len(blocks))
+	for i, block := range blocks {
+		headers[i] = block.Header
+	}
+	return headers
+}
+
+func TestBlockHeaderSerialization(t *testing.T) {
+	_, _, blocks := createBlockSequence(t, 1, 0)
+	block := blocks[0]
+
+	data, err := block.Header.Serialize()
+	if err != nil {
+		t.Fatalf("Failed to serialize header: %v", err)
+	}
+	header, err := DeserializeBlockHeader(data)
+	if err != nil {
+		t.Fatalf("Failed to deserialize header: %v", err)
+	}
+
+	if *header != block.Header {
+		t.Errorf("Header changed after round trip: %+v", *header)
+	}
+	hash, err := header.CalculateHash()
+	if err != nil || hash != block.Hash {
+		t.Errorf("Header hash should be the block hash %s, got %s (%v)", block.Hash, hash, err)
+	}
+	if err := header.VerifySignature(); err != nil {
+		t.Errorf("Header signature should verify without the block body: %v", err)
+	}
+
+	if _, err := DeserializeBlockHeader([]byte("{invalid")); err == nil {
+		t.Error("Invalid JSON should fail to deserialize")
+	}
+}
+
+// stakedValidators retorna o validador que assinou os blocos de createBlockSequence com stake
+func stakedValidators(blocks []*Block) ValidatorList {
+	return ValidatorList{{Address: blocks[0].Header.ValidatorAddr, Stake: 1000}}
+}
+
+func TestVerifyHeaderChain(t *testing.T) {
+	genesis, config, blocks := createBlockSequence(t, 50, 0)
+	validators := stakedValidators(blocks)
+
+	hashes, err := VerifyHeaderChain(genesis.Header, genesis.Hash, headersOf(blocks), validators, config, time.Now())
+	if err != nil {
+		t.Fatalf("Valid headers should be accepted: %v", err)
+	}
+	for i, block := range blocks {
+		if hashes[i] != block.Hash {
+			t.Errorf("Header %d: expected hash %s, got %s", i, block.Hash, hashes[i])
+		}
+	}
+
+	// Continuação a partir do último header de um lote anterior
+	if _, err := VerifyHeaderChain(blocks[24].Header, blocks[24].Hash, headersOf(blocks[25:]), validators, config, time.Now()); err != nil {
+		t.Errorf("Headers continuing a previous batch should be accepted: %v", err)
+	}
+}
+
+func TestVerifyHeaderChainRejectsBogusChain(t *testing.T) {
+	genesis, config, blocks := createBlockSequence(t, 10, 0)
+	validators := stakedValidators(blocks)
+	forger, _ := wallet.NewWallet()
+
+	tests := []struct {
+		name   string
+		tamper func(headers []BlockHeader)
+	}{
+		{"tampered merkle root", func(headers []BlockHeader) {
+			headers[5].MerkleRoot = "forged"
+		}},
+		{"signature from another key", func(headers []BlockHeader) {
+			forged := &Block{Header: headers[5]}
+			forged.Header.ValidatorAddr = forger.GetAddress()
+			if err := forged.Sign(forger); err != nil {
+				t.Fatalf("Failed to sign forged header: %v", err)
+			}
+			forged.Header.ValidatorAddr = headers[5].ValidatorAddr
+			headers[5] = forged.Header
+		}},
+		{"validator without stake", func(headers []BlockHeader) {
+			forged := &Block{Header: headers[0]}
+			forged.Header.ValidatorAddr = forger.GetAddress()
+			if err := forged.Sign(forger); err != nil {
+				t.Fatalf("Failed to sign forged header: %v", err)
+			}
+			headers[0] = forged.Header
+		}},
+		{"missing signature", func(headers []BlockHeader) {
+			headers[5].Signature = ""
+		}},
+		{"broken linkage", func(headers []BlockHeader) {
+			headers[5].PreviousHash = genesis.Hash
+		}},
+		{"skipped height", func(headers []BlockHeader) {
+			headers[5].Height = 8
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			headers := headersOf(blocks)
+			tt.tamper(headers)
+			if _, err := VerifyHeaderChain(genesis.Header, genesis.Hash, headers, validators, config, time.Now()); err == nil {
+				t.Error("Bogus header chain should be rejected")
+			}
+		})
+	}
+
+	// Headers que não continuam a âncora informada
+	if _, err := VerifyHeaderChain(blocks[0].Header, blocks[0].Hash, headersOf(blocks[2:]), validators, config, time.Now()); err == nil {
+		t.Error("Headers that do not continue the anchor should be rejected")
+	}
+}
+
+func TestVerifyHeaderChainStopsAtUnknownValidator(t *testing.T) {
+	genesis, config, blocks := createBlockSequence(t, 10, 0)
+	newcomer, _ := wallet.NewWallet()
+
+	// Um validador sem stake no estado atual assina o header 6: os anteriores continuam válidos
+	headers := headersOf(blocks)
+	forged := &Block{Header: headers[6]}
+	forged.Header.ValidatorAddr = newcomer.GetAddress()
+	if err := forged.Sign(newcomer); err != nil {
+		t.Fatalf("Failed to sign header: %v", err)
+	}
+	headers[6] = forged.Header
+
+	hashes, err := VerifyHeaderChain(genesis.Header, genesis.Hash, headers, stakedValidators(blocks), config, time.Now())
+	var unknown *UnknownValidatorError
+	if !errors.As(err, &unknown) {
+		t.Fatalf("Expected UnknownValidatorError, got %v", err)
+	}
+	if unknown.Index != 6 || unknown.Validator != newcomer.GetAddress() {
+		t.Errorf("Expected unknown validator %s at index 6, got %+v", newcomer.GetAddress(), unknown)
+	}
+	if len(hashes) != 6 || hashes[5] != blocks[5].Hash {
+		t.Errorf("Expected the 6 headers before the unknown validator to be returned, got %d", len(hashes))
+	}
+
+	// Sem validadores conhecidos, nenhum header é aceito
+	if _, err := VerifyHeaderChain(genesis.Header, genesis.Hash, headersOf(blocks), nil, config, time.Now()); err == nil {
+		t.Error("Headers should be rejected when no validator has stake")
+	}
+}
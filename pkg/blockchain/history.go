@@ -0,0 +1,138 @@
+package blockchain
+
+import (
+	"fmt"
+
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+// GetBalanceAtHeight retorna o saldo de address após o bloco height. O estado é reconstruído
+// reexecutando os blocos a partir do checkpoint salvo mais próximo abaixo da altura (ou do
+// gênesis, se não houver), buscando no banco os blocos que já saíram da memória. O saldo é o
+// total da conta, incluindo recompensas ainda imaturas.
+func (c *Chain) GetBalanceAtHeight(address string, height uint64) (uint64, error) {
+	c.mu.RLock()
+	tip := c.blocks[len(c.blocks)-1].Header.Height
+	db := c.db
+	c.mu.RUnlock()
+
+	if height > tip {
+		return 0, fmt.Errorf("height %d is above chain height %d", height, tip)
+	}
+	if height == tip {
+		return c.context.GetBalance(address), nil
+	}
+
+	// Ponto de partida: checkpoint mais próximo abaixo da altura ou o gênesis
+	base, err := c.historicalBase(height)
+	if err != nil {
+		return 0, err
+	}
+	var baseHeight uint64
+	if base != nil {
+		baseHeight = base.Height
+		if baseHeight == height {
+			if account := base.Accounts[address]; account != nil {
+				return account.Balance, nil
+			}
+			return 0, nil
+		}
+	}
+
+	blocks, err := c.blocksForReplay(db, baseHeight+1, height)
+	if err != nil {
+		return 0, err
+	}
+
+	var ctx *Context
+	if base != nil {
+		ctx = NewContextFromState(base.Height, blocks[0].Header.PreviousHash, base.Accounts)
+		c.configureContext(ctx)
+	} else {
+		ctx, err = c.newGenesisContext()
+		if err != nil {
+			return 0, err
+		}
+		if height == 0 {
+			return ctx.GetBalance(address), nil
+		}
+	}
+
+	if err := ctx.AddBlocks(blocks); err != nil {
+		return 0, fmt.Errorf("failed to replay blocks %d-%d: %w", baseHeight+1, height, err)
+	}
+
+	return ctx.GetBalance(address), nil
+}
+
+// historicalBase retorna o checkpoint salvo mais próximo com altura <= height (nil = partir do
+// gênesis). O último checkpoint carregado fica em cache: consultas seguidas em alturas próximas
+// não voltam a ler e decodificar o estado do banco, e ele continua valendo depois de o
+// checkpoint ser podado do disco.
+func (c *Chain) historicalBase(height uint64) (*Checkpoint, error) {
+	c.mu.RLock()
+	db := c.db
+	c.mu.RUnlock()
+
+	c.historyMu.Lock()
+	defer c.historyMu.Unlock()
+
+	var nearest uint64
+	if db != nil {
+		heights, err := ListCheckpointHeights(db)
+		if err != nil {
+			return nil, err
+		}
+		for _, h := range heights {
+			if h <= height && h > nearest {
+				nearest = h
+			}
+		}
+	}
+
+	cached := c.historyBase
+	if cached != nil && cached.Height <= height && cached.Height >= nearest {
+		return cached, nil
+	}
+	if nearest == 0 {
+		return nil, nil
+	}
+
+	checkpoint, err := LoadCheckpointFromDB(db, nearest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load checkpoint %d: %w", nearest, err)
+	}
+	c.historyBase = checkpoint
+	return checkpoint, nil
+}
+
+// blocksForReplay retorna os blocos da chain principal de from a to, da memória quando
+// possível e, para os que já saíram dela (pruning), do banco
+func (c *Chain) blocksForReplay(db *leveldb.DB, from, to uint64) ([]*Block, error) {
+	blocks := make([]*Block, to-from+1)
+
+	c.mu.RLock()
+	for _, block := range c.blocks {
+		if h := block.Header.Height; h >= from && h <= to && !block.IsCheckpointAnchor() {
+			blocks[h-from] = block
+		}
+	}
+	c.mu.RUnlock()
+
+	for i, block := range blocks {
+		if block != nil {
+			continue
+		}
+		height := from + uint64(i)
+		if db == nil {
+			return nil, fmt.Errorf("block %d is not in memory and no database is configured", height)
+		}
+		loaded, err := LoadBlockFromDB(db, height)
+		if err != nil {
+			return nil, fmt.Errorf("block %d is not available to rebuild historical state: %w", height, err)
+		}
+		blocks[i] = loaded
+	}
+
+	return blocks, nil
+}
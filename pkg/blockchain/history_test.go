@@ -0,0 +1,132 @@
+package blockchain
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/krakovia/blockchain/pkg/wallet"
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+// Helper: estado de todas as contas da chain no momento (para gravar um checkpoint)
+func currentAccounts(chain *Chain) map[string]*AccountState {
+	ctx := chain.GetContext()
+	accounts := make(map[string]*AccountState)
+	account := func(addr string) *AccountState {
+		if accounts[addr] == nil {
+			accounts[addr] = &AccountState{Address: addr}
+		}
+		return accounts[addr]
+	}
+	for addr, balance := range ctx.GetAllBalances() {
+		account(addr).Balance = balance
+	}
+	for addr, stake := range ctx.GetAllStakes() {
+		account(addr).Stake = stake
+	}
+	for addr, nonce := range ctx.GetAllNonces() {
+		account(addr).Nonce = nonce
+	}
+	return accounts
+}
+
+func TestChainGetBalanceAtHeight(t *testing.T) {
+	owner, _ := wallet.NewWallet()
+	miner, _ := wallet.NewWallet()
+	dest, _ := wallet.NewWallet()
+
+	config := DefaultChainConfig()
+	config.BlockTime = 100 * time.Millisecond
+
+	genesis := GenesisBlock(NewCoinbaseTransaction(owner.GetAddress(), 10000, 0))
+	chain, err := NewChain(genesis, config)
+	if err != nil {
+		t.Fatalf("Failed to create chain: %v", err)
+	}
+
+	transfer := func(amount, nonce uint64) *Transaction {
+		tx := NewTransaction(owner.GetAddress(), dest.GetAddress(), amount, 2, nonce, "")
+		if err := tx.Sign(owner); err != nil {
+			t.Fatalf("Failed to sign transaction: %v", err)
+		}
+		return tx
+	}
+
+	path := filepath.Join(t.TempDir(), "history.db")
+	db, err := leveldb.OpenFile(path, nil)
+	if err != nil {
+		t.Fatalf("Failed to open DB: %v", err)
+	}
+	defer db.Close()
+	if err := chain.SetDB(db); err != nil {
+		t.Fatalf("Failed to set DB: %v", err)
+	}
+
+	// Transferências de 300 na altura 2 e de 200 na altura 4; checkpoint gravado na altura 3
+	// com uma conta marcadora que não existe na chain, para saber de onde a reexecução partiu
+	mineBlockWith(t, chain, miner)
+	mineBlockWith(t, chain, miner, transfer(300, 0))
+	mineBlockWith(t, chain, miner)
+	accounts := currentAccounts(chain)
+	accounts["marker"] = &AccountState{Address: "marker", Balance: 7}
+	checkpoint, err := CreateCheckpoint(3, time.Now().Unix(), accounts, chain.TotalSupply(), ",")
+	if err != nil {
+		t.Fatalf("Failed to create checkpoint: %v", err)
+	}
+	if err := SaveCheckpointToDB(db, checkpoint, false); err != nil {
+		t.Fatalf("Failed to save checkpoint: %v", err)
+	}
+	mineBlockWith(t, chain, miner, transfer(200, 1))
+	mineBlockWith(t, chain, miner)
+
+	// Blocos 1-4 saem da memória e só podem ser lidos do banco
+	if err := chain.PruneToDepth(db, 1); err != nil {
+		t.Fatalf("Failed to prune chain: %v", err)
+	}
+
+	expected := []struct {
+		address string
+		height  uint64
+		balance uint64
+	}{
+		{owner.GetAddress(), 0, 10000},
+		{dest.GetAddress(), 1, 0},
+		{owner.GetAddress(), 1, 10000},
+		{dest.GetAddress(), 2, 300},
+		{owner.GetAddress(), 2, 9698},
+		{dest.GetAddress(), 3, 300},
+		{dest.GetAddress(), 4, 500},
+		{owner.GetAddress(), 4, 9496},
+		{dest.GetAddress(), 5, 500},
+	}
+	for _, e := range expected {
+		balance, err := chain.GetBalanceAtHeight(e.address, e.height)
+		if err != nil {
+			t.Fatalf("Failed to get balance at height %d: %v", e.height, err)
+		}
+		if balance != e.balance {
+			t.Errorf("Balance of %s at height %d: expected %d, got %d", e.address[:8], e.height, e.balance, balance)
+		}
+	}
+
+	// Abaixo do checkpoint a reexecução parte do gênesis; acima dele, do checkpoint
+	if balance, _ := chain.GetBalanceAtHeight("marker", 2); balance != 0 {
+		t.Errorf("Heights before the checkpoint should replay from genesis, marker balance %d", balance)
+	}
+	if balance, _ := chain.GetBalanceAtHeight("marker", 4); balance != 7 {
+		t.Errorf("Heights after the checkpoint should replay from it, marker balance %d", balance)
+	}
+
+	// O checkpoint carregado fica em cache mesmo depois de podado do disco
+	if err := DeleteCheckpoint(db, 3); err != nil {
+		t.Fatalf("Failed to delete checkpoint: %v", err)
+	}
+	if balance, _ := chain.GetBalanceAtHeight("marker", 4); balance != 7 {
+		t.Errorf("Cached checkpoint should still be used, marker balance %d", balance)
+	}
+
+	if _, err := chain.GetBalanceAtHeight(dest.GetAddress(), 6); err == nil {
+		t.Error("Height above the chain should fail")
+	}
+}
@@ -1,6 +1,7 @@
 package blockchain
 
 import (
+	"container/heap"
 	"fmt"
 	"sort"
 	"sync"
@@ -22,14 +23,43 @@ type Mempool struct {
 	maxTxAge        time.Duration // Idade máxima de uma transação
 	minFee          uint64        // Taxa mínima aceita
 	maxTxPerAddress int           // Máximo de transações por endereço
+	minBumpPercent  uint64        // Aumento mínimo da fee (%) para substituir uma transação pendente
+
+	// Máximo de transações por endereço esperando uma lacuna de nonce ser preenchida
+	maxQueuedPerAddress int
+
+	// Remetentes aceitos na admissão (nil = todos)
+	senderFilter *SenderFilter
+
+	// Próximo nonce de cada remetente na chain (nil = o menor nonce pendente do remetente)
+	nonceSource func(address string) uint64
+
+	// Altura atual da chain, usada para recusar transações já expiradas
+	chainHeight uint64
+
+	// Limites de consenso (os mesmos da validação de blocos)
+	consensus ConsensusParams
 }
 
+// DefaultMinBumpPercent aumento mínimo padrão da fee, em porcentagem, para uma transação
+// substituir a pendente com o mesmo remetente e nonce (replace-by-fee)
+const DefaultMinBumpPercent = 10
+
 // MempoolConfig configurações do mempool
 type MempoolConfig struct {
 	MaxSize         int           // Padrão: 10000
 	MaxTxAge        time.Duration // Padrão: 1 hora
 	MinFee          uint64        // Padrão: 1
 	MaxTxPerAddress int           // Padrão: 100
+	MinBumpPercent  uint64        // Padrão: 10
+
+	// Transações por endereço com nonce depois de uma lacuna, guardadas até a lacuna ser
+	// preenchida. Padrão: 16
+	MaxQueuedPerAddress int
+
+	// Limites de consenso; use os da chain (ChainConfig.Consensus) para o mempool não aceitar
+	// transações que nenhum bloco válido comporta
+	Consensus ConsensusParams
 }
 
 // DefaultMempoolConfig retorna configurações padrão
@@ -39,6 +69,10 @@ func DefaultMempoolConfig() MempoolConfig {
 		MaxTxAge:        1 * time.Hour,
 		MinFee:          1,
 		MaxTxPerAddress: 100,
+		MinBumpPercent:  DefaultMinBumpPercent,
+		Consensus:       DefaultConsensusParams(),
+
+		MaxQueuedPerAddress: 16,
 	}
 }
 
@@ -56,44 +90,159 @@ func NewMempoolWithConfig(config MempoolConfig) *Mempool {
 		maxTxAge:              config.MaxTxAge,
 		minFee:                config.MinFee,
 		maxTxPerAddress:       config.MaxTxPerAddress,
+		minBumpPercent:        config.MinBumpPercent,
+		maxQueuedPerAddress:   config.MaxQueuedPerAddress,
+		consensus:             config.Consensus,
+	}
+}
+
+// MinFee retorna a fee mínima aceita na admissão de transações
+func (mp *Mempool) MinFee() uint64 {
+	mp.mu.RLock()
+	defer mp.mu.RUnlock()
+	return mp.minFee
+}
+
+// SetSenderFilter define quais remetentes podem ter transações admitidas no mempool
+func (mp *Mempool) SetSenderFilter(filter *SenderFilter) {
+	mp.mu.Lock()
+	defer mp.mu.Unlock()
+	mp.senderFilter = filter
+}
+
+// SetNonceSource define de onde vem o próximo nonce de cada remetente (ex.: Chain.GetNonce).
+// Só as transações em sequência a partir dele são entregues ao minerador; as que vêm depois de
+// uma lacuna ficam na fila até as que faltam chegarem.
+func (mp *Mempool) SetNonceSource(source func(address string) uint64) {
+	mp.mu.Lock()
+	defer mp.mu.Unlock()
+	mp.nonceSource = source
+}
+
+// splitReady separa as transações pendentes de um remetente (ordenadas por nonce) nas prontas
+// para mineração, em sequência a partir do próximo nonce do remetente, e no número das que estão
+// na fila depois de uma lacuna. Transações com nonce já usado na chain não entram em nenhuma das
+// duas (não thread-safe).
+func (mp *Mempool) splitReady(address string, txs []*Transaction) (ready []*Transaction, queued int) {
+	if len(txs) == 0 {
+		return nil, 0
+	}
+
+	nonce := txs[0].Nonce
+	if mp.nonceSource != nil {
+		nonce = mp.nonceSource(address)
 	}
+
+	start := 0
+	for start < len(txs) && txs[start].Nonce < nonce {
+		start++
+	}
+	end := start
+	for end < len(txs) && txs[end].Nonce == nonce {
+		end++
+		nonce++
+	}
+	return txs[start:end], len(txs) - end
 }
 
-// AddTransaction adiciona uma transação ao mempool
+// AddTransaction adiciona uma transação ao mempool. Uma transação com o mesmo remetente e
+// nonce de outra pendente a substitui (replace-by-fee) se a fee for pelo menos
+// minBumpPercent maior; caso contrário é rejeitada. Com o mempool cheio (maxSize), a
+// transação de menor fee é despejada se a nova pagar mais que ela; senão a nova é rejeitada.
+// Uma transação com nonce à frente do próximo esperado fica na fila do remetente (até
+// maxQueuedPerAddress) e passa a ser minerável quando a lacuna é preenchida.
 func (mp *Mempool) AddTransaction(tx *Transaction) error {
 	mp.mu.Lock()
 	defer mp.mu.Unlock()
 
+	// Verifica se o remetente é aceito por este nó
+	if !mp.senderFilter.AllowsTransaction(tx) {
+		return fmt.Errorf("sender %s is not allowed", tx.From)
+	}
+
+	// Limites de consenso antes da verificação da assinatura
+	if err := mp.consensus.CheckTransaction(tx); err != nil {
+		return err
+	}
+
 	// Valida a transação
 	if err := tx.Validate(); err != nil {
 		return fmt.Errorf("transaction validation failed: %w", err)
 	}
 
+	// Transação que não cabe mais no próximo bloco nunca será minerada
+	if tx.IsExpiredAt(mp.chainHeight + 1) {
+		return fmt.Errorf("transaction expired at height %d (chain height %d)", tx.ExpiryHeight, mp.chainHeight)
+	}
+
 	// Verifica se já existe
 	if _, exists := mp.transactions[tx.ID]; exists {
 		return fmt.Errorf("transaction already in mempool")
 	}
 
-	// Verifica taxa mínima
+	// Verifica taxa mínima (vale também para stake/unstake; coinbase nunca passa pelo mempool)
 	if tx.Fee < mp.minFee {
 		return fmt.Errorf("transaction fee %d is below minimum %d", tx.Fee, mp.minFee)
 	}
 
-	// Verifica tamanho do mempool
-	if len(mp.transactions) >= mp.maxSize {
-		// Remove transação com menor taxa para dar espaço
-		if !mp.removeLowFeeTx(tx.Fee) {
-			return fmt.Errorf("mempool is full and transaction fee is too low")
+	// Replace-by-fee: a pendente com o mesmo remetente e nonce só sai por uma fee maior
+	var replaced *Transaction
+	for _, pending := range mp.transactionsByAddress[tx.From] {
+		if pending.Nonce == tx.Nonce {
+			replaced = pending
+			break
 		}
 	}
+	if replaced != nil {
+		requiredFee := replaced.Fee + replaced.Fee*mp.minBumpPercent/100
+		if requiredFee <= replaced.Fee {
+			requiredFee = replaced.Fee + 1
+		}
+		if tx.Fee < requiredFee {
+			return fmt.Errorf("replacement fee %d is below required %d (%d%% over pending fee %d for nonce %d)",
+				tx.Fee, requiredFee, mp.minBumpPercent, replaced.Fee, tx.Nonce)
+		}
 
-	// Verifica limite de transações por endereço
-	addressTxs := mp.transactionsByAddress[tx.From]
-	if len(addressTxs) >= mp.maxTxPerAddress {
+		// Remove a substituída; a nova ocupa o lugar dela (limites de tamanho já respeitados)
+		delete(mp.transactions, replaced.ID)
+		addressTxs := mp.transactionsByAddress[tx.From]
+		for i, addrTx := range addressTxs {
+			if addrTx.ID == replaced.ID {
+				mp.transactionsByAddress[tx.From] = append(addressTxs[:i], addressTxs[i+1:]...)
+				break
+			}
+		}
+	}
+
+	// Verifica limite de transações por endereço (antes de despejar outra transação por ela)
+	if replaced == nil && len(mp.transactionsByAddress[tx.From]) >= mp.maxTxPerAddress {
 		return fmt.Errorf("address %s has reached maximum pending transactions (%d)",
 			tx.From, mp.maxTxPerAddress)
 	}
 
+	// Nonce depois de uma lacuna: entra na fila do remetente, que é limitada
+	if replaced == nil && mp.nonceSource != nil {
+		ready, queued := mp.splitReady(tx.From, mp.transactionsByAddress[tx.From])
+		nextNonce := mp.nonceSource(tx.From) + uint64(len(ready))
+		if tx.Nonce > nextNonce && queued >= mp.maxQueuedPerAddress {
+			return fmt.Errorf("address %s has reached maximum queued transactions (%d) waiting for nonce %d",
+				tx.From, mp.maxQueuedPerAddress, nextNonce)
+		}
+	}
+
+	// Mempool cheio: a transação de menor fee sai para dar espaço, se a nova pagar mais
+	if replaced == nil && len(mp.transactions) >= mp.maxSize {
+		lowest, evicted := mp.removeLowFeeTx(tx.Fee)
+		if !evicted {
+			if lowest == nil {
+				return fmt.Errorf("mempool is full (max size %d)", mp.maxSize)
+			}
+			return fmt.Errorf("mempool is full (%d transactions) and fee %d does not exceed the lowest pending fee %d",
+				mp.maxSize, tx.Fee, lowest.Fee)
+		}
+	}
+	addressTxs := mp.transactionsByAddress[tx.From]
+
 	// Adiciona ao mempool
 	mp.transactions[tx.ID] = tx
 
@@ -170,6 +319,23 @@ func (mp *Mempool) GetTransactionsByAddress(address string) []*Transaction {
 	return result
 }
 
+// NextNonce retorna o próximo nonce livre do endereço: o da chain seguido dos nonces que já
+// têm transação pendente. Reusar um nonce pendente é uma substituição (replace-by-fee).
+func (mp *Mempool) NextNonce(address string, chainNonce uint64) uint64 {
+	mp.mu.RLock()
+	defer mp.mu.RUnlock()
+
+	nonce := chainNonce
+	for _, tx := range mp.transactionsByAddress[address] { // Ordenadas por nonce
+		if tx.Nonce == nonce {
+			nonce++
+		} else if tx.Nonce > nonce {
+			break
+		}
+	}
+	return nonce
+}
+
 // GetPendingTransactions retorna transações ordenadas por fee (maior primeiro)
 // Útil para mineração
 func (mp *Mempool) GetPendingTransactions(maxCount int) []*Transaction {
@@ -196,6 +362,85 @@ func (mp *Mempool) GetPendingTransactions(maxCount int) []*Transaction {
 	return txs
 }
 
+// GetTransactionsByFee retorna transações pendentes priorizadas por fee (maior primeiro),
+// preservando a ordem crescente de nonce entre transações do mesmo remetente.
+// Uma transação só é escolhida depois de todas as de nonce menor do mesmo endereço,
+// mesmo que tenha fee maior, e só as em sequência a partir do próximo nonce do remetente são
+// retornadas (as que estão na fila depois de uma lacuna ficam de fora). maxCount <= 0 retorna todas.
+func (mp *Mempool) GetTransactionsByFee(maxCount int) []*Transaction {
+	mp.mu.RLock()
+	defer mp.mu.RUnlock()
+
+	total := len(mp.transactions)
+	if maxCount > 0 && maxCount < total {
+		total = maxCount
+	}
+	result := make([]*Transaction, 0, total)
+
+	// Heap com a próxima transação (menor nonce) de cada remetente
+	queue := make(senderQueue, 0, len(mp.transactionsByAddress))
+	for address, addressTxs := range mp.transactionsByAddress {
+		if ready, _ := mp.splitReady(address, addressTxs); len(ready) > 0 {
+			queue = append(queue, &senderCursor{txs: ready})
+		}
+	}
+	heap.Init(&queue)
+
+	for queue.Len() > 0 && len(result) < total {
+		cursor := queue[0]
+		result = append(result, cursor.next())
+		cursor.index++
+
+		if cursor.index < len(cursor.txs) {
+			heap.Fix(&queue, 0)
+		} else {
+			heap.Pop(&queue)
+		}
+	}
+
+	return result
+}
+
+// senderCursor aponta para a próxima transação pendente de um remetente
+type senderCursor struct {
+	txs   []*Transaction // Transações do remetente ordenadas por nonce
+	index int
+}
+
+func (c *senderCursor) next() *Transaction {
+	return c.txs[c.index]
+}
+
+// senderQueue é um max-heap de remetentes ordenado pela fee da próxima transação
+type senderQueue []*senderCursor
+
+func (q senderQueue) Len() int { return len(q) }
+
+func (q senderQueue) Less(i, j int) bool {
+	a, b := q[i].next(), q[j].next()
+	if a.Fee != b.Fee {
+		return a.Fee > b.Fee
+	}
+	if a.Timestamp != b.Timestamp {
+		return a.Timestamp < b.Timestamp
+	}
+	return a.ID < b.ID
+}
+
+func (q senderQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+func (q *senderQueue) Push(x interface{}) {
+	*q = append(*q, x.(*senderCursor))
+}
+
+func (q *senderQueue) Pop() interface{} {
+	old := *q
+	n := len(old)
+	cursor := old[n-1]
+	*q = old[:n-1]
+	return cursor
+}
+
 // GetValidTransactions retorna transações válidas para inclusão em um bloco
 // Filtra por nonce correto e valida no contexto atual
 func (mp *Mempool) GetValidTransactions(ctx *Context, maxCount int) []*Transaction {
@@ -293,6 +538,14 @@ func (mp *Mempool) Size() int {
 	return len(mp.transactions)
 }
 
+// IsFull indica se o mempool atingiu o tamanho máximo: novas transações só entram despejando
+// a de menor fee, e apenas se pagarem mais que ela
+func (mp *Mempool) IsFull() bool {
+	mp.mu.RLock()
+	defer mp.mu.RUnlock()
+	return len(mp.transactions) >= mp.maxSize
+}
+
 // PruneExpired remove transações expiradas
 func (mp *Mempool) PruneExpired() int {
 	mp.mu.Lock()
@@ -330,35 +583,51 @@ func (mp *Mempool) PruneExpired() int {
 	return len(expired)
 }
 
-// removeLowFeeTx remove a transação com menor taxa (não thread-safe)
-// Retorna true se conseguiu remover uma transação com taxa menor que minFee
-func (mp *Mempool) removeLowFeeTx(minFee uint64) bool {
-	var lowestFeeTx *Transaction
-	lowestFee := uint64(^uint64(0)) // MaxUint64
+// RemoveExpiredTransactions registra a altura atual da chain e remove as transações cujo
+// ExpiryHeight não permite mais entrar no próximo bloco. Retorna quantas foram removidas.
+func (mp *Mempool) RemoveExpiredTransactions(chainHeight uint64) int {
+	mp.mu.Lock()
+	mp.chainHeight = chainHeight
+	expired := make([]string, 0)
+	for txID, tx := range mp.transactions {
+		if tx.IsExpiredAt(chainHeight + 1) {
+			expired = append(expired, txID)
+		}
+	}
+	mp.mu.Unlock()
+
+	return mp.RemoveTransactions(expired)
+}
 
+// removeLowFeeTx remove a transação com menor taxa se ela pagar menos que minFee (não
+// thread-safe). Retorna a transação de menor taxa (nil com o mempool vazio) e se ela foi removida.
+func (mp *Mempool) removeLowFeeTx(minFee uint64) (*Transaction, bool) {
+	var lowestFeeTx *Transaction
 	for _, tx := range mp.transactions {
-		if tx.Fee < lowestFee {
-			lowestFee = tx.Fee
+		if lowestFeeTx == nil || tx.Fee < lowestFeeTx.Fee {
 			lowestFeeTx = tx
 		}
 	}
 
-	if lowestFeeTx != nil && lowestFeeTx.Fee < minFee {
-		delete(mp.transactions, lowestFeeTx.ID)
+	if lowestFeeTx == nil || lowestFeeTx.Fee >= minFee {
+		return lowestFeeTx, false
+	}
 
-		// Remove do índice
-		addressTxs := mp.transactionsByAddress[lowestFeeTx.From]
-		for i, tx := range addressTxs {
-			if tx.ID == lowestFeeTx.ID {
-				mp.transactionsByAddress[lowestFeeTx.From] = append(addressTxs[:i], addressTxs[i+1:]...)
-				break
-			}
-		}
+	delete(mp.transactions, lowestFeeTx.ID)
 
-		return true
+	// Remove do índice
+	addressTxs := mp.transactionsByAddress[lowestFeeTx.From]
+	for i, tx := range addressTxs {
+		if tx.ID == lowestFeeTx.ID {
+			mp.transactionsByAddress[lowestFeeTx.From] = append(addressTxs[:i], addressTxs[i+1:]...)
+			break
+		}
+	}
+	if len(mp.transactionsByAddress[lowestFeeTx.From]) == 0 {
+		delete(mp.transactionsByAddress, lowestFeeTx.From)
 	}
 
-	return false
+	return lowestFeeTx, true
 }
 
 // GetStats retorna estatísticas do mempool
@@ -370,6 +639,10 @@ func (mp *Mempool) GetStats() MempoolStats {
 		TotalTransactions: len(mp.transactions),
 		UniqueAddresses:   len(mp.transactionsByAddress),
 	}
+	for address, addressTxs := range mp.transactionsByAddress {
+		_, queued := mp.splitReady(address, addressTxs)
+		stats.QueuedTransactions += queued
+	}
 
 	// Calcula taxa média e total de fees
 	var totalFees uint64
@@ -398,10 +671,11 @@ func (mp *Mempool) GetStats() MempoolStats {
 
 // MempoolStats estatísticas do mempool
 type MempoolStats struct {
-	TotalTransactions int    // Total de transações
-	UniqueAddresses   int    // Número de endereços únicos
-	TotalFees         uint64 // Soma de todas as taxas
-	AverageFee        uint64 // Taxa média
-	MinFee            uint64 // Taxa mínima
-	MaxFee            uint64 // Taxa máxima
+	TotalTransactions  int    // Total de transações
+	QueuedTransactions int    // Transações esperando uma lacuna de nonce ser preenchida
+	UniqueAddresses    int    // Número de endereços únicos
+	TotalFees          uint64 // Soma de todas as taxas
+	AverageFee         uint64 // Taxa média
+	MinFee             uint64 // Taxa mínima
+	MaxFee             uint64 // Taxa máxima
 }
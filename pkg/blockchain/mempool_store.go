@@ -0,0 +1,90 @@
+package blockchain
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+// mempoolDBKey é a chave do LevelDB onde as transações pendentes são persistidas
+const mempoolDBKey = "mempool-transactions"
+
+// SaveToDB persiste as transações pendentes no LevelDB (substitui o conteúdo anterior)
+func (mp *Mempool) SaveToDB(db *leveldb.DB) error {
+	if db == nil {
+		return fmt.Errorf("database cannot be nil")
+	}
+
+	txs := mp.GetTransactions()
+
+	// Ordena por remetente e nonce para um reload determinístico
+	sort.Slice(txs, func(i, j int) bool {
+		if txs[i].From != txs[j].From {
+			return txs[i].From < txs[j].From
+		}
+		return txs[i].Nonce < txs[j].Nonce
+	})
+
+	data, err := json.Marshal(txs)
+	if err != nil {
+		return fmt.Errorf("failed to marshal mempool: %w", err)
+	}
+
+	if err := db.Put([]byte(mempoolDBKey), data, nil); err != nil {
+		return fmt.Errorf("failed to save mempool: %w", err)
+	}
+
+	return nil
+}
+
+// LoadFromDB carrega as transações persistidas com SaveToDB.
+// Cada transação passa pela validação normal do mempool; transações inválidas,
+// expiradas ou duplicadas são descartadas. Use RemoveStaleTransactions em seguida
+// para descartar as que já foram incluídas na chain.
+func (mp *Mempool) LoadFromDB(db *leveldb.DB) error {
+	if db == nil {
+		return fmt.Errorf("database cannot be nil")
+	}
+
+	data, err := db.Get([]byte(mempoolDBKey), nil)
+	if err == leveldb.ErrNotFound {
+		return nil // Nenhum mempool salvo
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load mempool: %w", err)
+	}
+
+	var txs []*Transaction
+	if err := json.Unmarshal(data, &txs); err != nil {
+		return fmt.Errorf("failed to unmarshal mempool: %w", err)
+	}
+
+	now := time.Now().Unix()
+	for _, tx := range txs {
+		if tx == nil {
+			continue
+		}
+		if time.Duration(now-tx.Timestamp)*time.Second > mp.maxTxAge {
+			continue
+		}
+		_ = mp.AddTransaction(tx)
+	}
+
+	return nil
+}
+
+// RemoveStaleTransactions remove transações cujo nonce já foi usado no estado atual
+// (já mineradas ou substituídas). Retorna quantas foram removidas.
+func (mp *Mempool) RemoveStaleTransactions(ctx *Context) int {
+	stale := make([]string, 0)
+	for _, tx := range mp.GetTransactions() {
+		if tx.Nonce < ctx.GetNonce(tx.From) {
+			stale = append(stale, tx.ID)
+		}
+	}
+
+	return mp.RemoveTransactions(stale)
+}
@@ -0,0 +1,463 @@
+package blockchain
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/krakovia/blockchain/pkg/wallet"
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+// newSignedTx cria uma transação assinada para testes de mempool
+func newSignedTx(t *testing.T, w *wallet.Wallet, to string, fee, nonce uint64) *Transaction {
+	tx := NewTransaction(w.GetAddress(), to, 10, fee, nonce, "")
+	if err := tx.Sign(w); err != nil {
+		t.Fatalf("Failed to sign transaction: %v", err)
+	}
+	return tx
+}
+
+func TestMempoolGetTransactionsByFee(t *testing.T) {
+	wA, _ := wallet.NewWallet()
+	wB, _ := wallet.NewWallet()
+	wC, _ := wallet.NewWallet()
+	dest, _ := wallet.NewWallet()
+
+	a0 := newSignedTx(t, wA, dest.GetAddress(), 1, 0)
+	a1 := newSignedTx(t, wA, dest.GetAddress(), 10, 1) // Fee maior, mas depende de a0
+	b0 := newSignedTx(t, wB, dest.GetAddress(), 5, 0)
+	c0 := newSignedTx(t, wC, dest.GetAddress(), 3, 0)
+	c1 := newSignedTx(t, wC, dest.GetAddress(), 2, 1)
+
+	mp := NewMempool()
+	// Adiciona fora de ordem para garantir que a ordenação não depende da inserção
+	for _, tx := range []*Transaction{a1, c1, b0, a0, c0} {
+		if err := mp.AddTransaction(tx); err != nil {
+			t.Fatalf("Failed to add transaction: %v", err)
+		}
+	}
+
+	expected := []*Transaction{b0, c0, c1, a0, a1}
+	ordered := mp.GetTransactionsByFee(0)
+
+	if len(ordered) != len(expected) {
+		t.Fatalf("Expected %d transactions, got %d", len(expected), len(ordered))
+	}
+	for i, tx := range expected {
+		if ordered[i].ID != tx.ID {
+			t.Errorf("Position %d: expected tx with fee %d nonce %d, got fee %d nonce %d",
+				i, tx.Fee, tx.Nonce, ordered[i].Fee, ordered[i].Nonce)
+		}
+	}
+
+	// maxCount limita o resultado mantendo a prioridade
+	limited := mp.GetTransactionsByFee(2)
+	if len(limited) != 2 || limited[0].ID != b0.ID || limited[1].ID != c0.ID {
+		t.Errorf("Expected first two transactions to be b0 and c0")
+	}
+}
+
+func TestMinerCreateBlockOrdersByFeeAndNonce(t *testing.T) {
+	w, _ := wallet.NewWallet()
+	dest, _ := wallet.NewWallet()
+
+	genesis := GenesisBlock(NewCoinbaseTransaction(w.GetAddress(), 1000, 0))
+	chain, err := NewChain(genesis, DefaultChainConfig())
+	if err != nil {
+		t.Fatalf("Failed to create chain: %v", err)
+	}
+
+	mp := NewMempool()
+	tx0 := newSignedTx(t, w, dest.GetAddress(), 1, 0)
+	tx1 := newSignedTx(t, w, dest.GetAddress(), 50, 1)
+	for _, tx := range []*Transaction{tx1, tx0} {
+		if err := mp.AddTransaction(tx); err != nil {
+			t.Fatalf("Failed to add transaction: %v", err)
+		}
+	}
+
+	miner := NewMiner(w, chain, mp)
+	block, err := miner.CreateBlock()
+	if err != nil {
+		t.Fatalf("Failed to create block: %v", err)
+	}
+
+	if len(block.Transactions) != 3 {
+		t.Fatalf("Expected 3 transactions (coinbase + 2), got %d", len(block.Transactions))
+	}
+	if !block.Transactions[0].IsCoinbase() {
+		t.Error("First transaction must be coinbase")
+	}
+	if block.Transactions[1].ID != tx0.ID || block.Transactions[2].ID != tx1.ID {
+		t.Error("Transactions from the same sender must be ordered by nonce")
+	}
+}
+
+func TestMempoolPersistAcrossRestart(t *testing.T) {
+	w, _ := wallet.NewWallet()
+	old, _ := wallet.NewWallet()
+	dest, _ := wallet.NewWallet()
+
+	genesis := GenesisBlock(NewCoinbaseTransaction(w.GetAddress(), 100000, 0))
+	chain, err := NewChain(genesis, DefaultChainConfig())
+	if err != nil {
+		t.Fatalf("Failed to create chain: %v", err)
+	}
+
+	mp := NewMempool()
+	txs := make([]*Transaction, 4)
+	for i := range txs {
+		txs[i] = newSignedTx(t, w, dest.GetAddress(), 1, uint64(i))
+		if err := mp.AddTransaction(txs[i]); err != nil {
+			t.Fatalf("Failed to add transaction: %v", err)
+		}
+	}
+
+	// Transação antiga demais para sobreviver ao reload
+	expired := NewTransaction(old.GetAddress(), dest.GetAddress(), 10, 1, 0, "")
+	expired.Timestamp = time.Now().Add(-2 * time.Hour).Unix()
+	if err := expired.Sign(old); err != nil {
+		t.Fatalf("Failed to sign transaction: %v", err)
+	}
+	if err := mp.AddTransaction(expired); err != nil {
+		t.Fatalf("Failed to add transaction: %v", err)
+	}
+
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+	db, err := leveldb.OpenFile(dbPath, nil)
+	if err != nil {
+		t.Fatalf("Failed to open DB: %v", err)
+	}
+	if err := mp.SaveToDB(db); err != nil {
+		t.Fatalf("Failed to save mempool: %v", err)
+	}
+	if err := db.Close(); err != nil {
+		t.Fatalf("Failed to close DB: %v", err)
+	}
+
+	// Enquanto o nó estava parado, as duas primeiras transações foram mineradas
+	mined := NewMempool()
+	_ = mined.AddTransaction(txs[0])
+	_ = mined.AddTransaction(txs[1])
+	block, err := NewMiner(w, chain, mined).CreateBlock()
+	if err != nil {
+		t.Fatalf("Failed to create block: %v", err)
+	}
+	if err := chain.AddBlock(block); err != nil {
+		t.Fatalf("Failed to add block: %v", err)
+	}
+
+	db, err = leveldb.OpenFile(dbPath, nil)
+	if err != nil {
+		t.Fatalf("Failed to reopen DB: %v", err)
+	}
+	defer db.Close()
+
+	restored := NewMempool()
+	if err := restored.LoadFromDB(db); err != nil {
+		t.Fatalf("Failed to load mempool: %v", err)
+	}
+	if restored.Size() != 4 {
+		t.Errorf("Expected 4 transactions after load (expired dropped), got %d", restored.Size())
+	}
+
+	if removed := restored.RemoveStaleTransactions(chain.GetContext()); removed != 2 {
+		t.Errorf("Expected 2 stale transactions removed, got %d", removed)
+	}
+	for i, tx := range txs {
+		_, exists := restored.GetTransaction(tx.ID)
+		if mined := i < 2; exists == mined {
+			t.Errorf("Transaction with nonce %d: present=%v, mined=%v", i, exists, mined)
+		}
+	}
+
+	// As transações restantes continuam executáveis em sequência
+	valid := chain.GetContext().SelectExecutableTransactions(restored.GetTransactionsByFee(0), 0)
+	if len(valid) != 2 {
+		t.Errorf("Expected 2 executable transactions, got %d", len(valid))
+	}
+}
+
+func TestMempoolLoadFromEmptyDB(t *testing.T) {
+	db, err := leveldb.OpenFile(filepath.Join(t.TempDir(), "test.db"), nil)
+	if err != nil {
+		t.Fatalf("Failed to open DB: %v", err)
+	}
+	defer db.Close()
+
+	mp := NewMempool()
+	if err := mp.LoadFromDB(db); err != nil {
+		t.Fatalf("Loading without saved mempool should not fail: %v", err)
+	}
+	if mp.Size() != 0 {
+		t.Errorf("Expected empty mempool, got %d", mp.Size())
+	}
+}
+
+// newExpiringTx cria uma transação assinada que só pode ser minerada até expiryHeight
+func newExpiringTx(t *testing.T, w *wallet.Wallet, to string, nonce, expiryHeight uint64) *Transaction {
+	tx := NewTransaction(w.GetAddress(), to, 10, 1, nonce, "")
+	tx.ExpiryHeight = expiryHeight
+	if err := tx.Sign(w); err != nil {
+		t.Fatalf("Failed to sign transaction: %v", err)
+	}
+	return tx
+}
+
+func TestMempoolEvictsExpiredTransactions(t *testing.T) {
+	alice, _ := wallet.NewWallet()
+	bob, _ := wallet.NewWallet()
+	carol, _ := wallet.NewWallet()
+	dest, _ := wallet.NewWallet()
+
+	mp := NewMempool()
+	expiring := newExpiringTx(t, alice, dest.GetAddress(), 0, 2)
+	later := newExpiringTx(t, bob, dest.GetAddress(), 0, 5)
+	forever := newSignedTx(t, carol, dest.GetAddress(), 1, 0)
+	for _, tx := range []*Transaction{expiring, later, forever} {
+		if err := mp.AddTransaction(tx); err != nil {
+			t.Fatalf("Failed to add transaction: %v", err)
+		}
+	}
+
+	// Na altura 1 o próximo bloco (2) ainda aceita todas
+	if removed := mp.RemoveExpiredTransactions(1); removed != 0 {
+		t.Errorf("Expected no expired transactions at height 1, removed %d", removed)
+	}
+
+	// Na altura 2 a transação com expiração 2 não cabe mais no próximo bloco
+	if removed := mp.RemoveExpiredTransactions(2); removed != 1 {
+		t.Errorf("Expected 1 expired transaction at height 2, removed %d", removed)
+	}
+	if _, exists := mp.GetTransaction(expiring.ID); exists {
+		t.Error("Expired transaction should be evicted")
+	}
+	if mp.Size() != 2 {
+		t.Errorf("Expected 2 transactions left, got %d", mp.Size())
+	}
+
+	// Transações já expiradas são recusadas na admissão
+	if err := mp.AddTransaction(newExpiringTx(t, alice, dest.GetAddress(), 0, 2)); err == nil {
+		t.Error("Expected expired transaction to be rejected")
+	}
+	if err := mp.AddTransaction(newExpiringTx(t, alice, dest.GetAddress(), 0, 3)); err != nil {
+		t.Errorf("Transaction still mineable in the next block should be accepted: %v", err)
+	}
+}
+
+func TestMempoolReplaceByFee(t *testing.T) {
+	w, _ := wallet.NewWallet()
+	dest, _ := wallet.NewWallet()
+
+	mp := NewMempool() // MinBumpPercent padrão: 10%
+
+	low := newSignedTx(t, w, dest.GetAddress(), 10, 0)
+	if err := mp.AddTransaction(low); err != nil {
+		t.Fatalf("Failed to add low-fee transaction: %v", err)
+	}
+
+	// Mesmo nonce com fee igual ou menor é rejeitada
+	for _, fee := range []uint64{10, 5} {
+		if err := mp.AddTransaction(newSignedTx(t, w, dest.GetAddress(), fee, 0)); err == nil {
+			t.Errorf("Expected replacement with fee %d to be rejected", fee)
+		}
+	}
+
+	// Fee 11 = 10% acima: substitui a pendente
+	high := newSignedTx(t, w, dest.GetAddress(), 11, 0)
+	if err := mp.AddTransaction(high); err != nil {
+		t.Fatalf("Failed to replace transaction: %v", err)
+	}
+
+	if mp.Size() != 1 {
+		t.Fatalf("Expected only the replacement in mempool, got %d transactions", mp.Size())
+	}
+	if _, exists := mp.GetTransaction(low.ID); exists {
+		t.Error("Replaced transaction should have been evicted")
+	}
+	pending := mp.GetTransactionsByAddress(w.GetAddress())
+	if len(pending) != 1 || pending[0].ID != high.ID {
+		t.Errorf("Expected address index to hold only the replacement, got %d transactions", len(pending))
+	}
+
+	// Outro nonce não é substituição e convive com a pendente
+	if err := mp.AddTransaction(newSignedTx(t, w, dest.GetAddress(), 1, 1)); err != nil {
+		t.Errorf("Failed to add transaction with next nonce: %v", err)
+	}
+	if mp.Size() != 2 {
+		t.Errorf("Expected 2 transactions, got %d", mp.Size())
+	}
+}
+
+func TestMempoolReplaceByFeeCustomBump(t *testing.T) {
+	w, _ := wallet.NewWallet()
+	dest, _ := wallet.NewWallet()
+
+	config := DefaultMempoolConfig()
+	config.MinBumpPercent = 50
+	mp := NewMempoolWithConfig(config)
+
+	if err := mp.AddTransaction(newSignedTx(t, w, dest.GetAddress(), 10, 0)); err != nil {
+		t.Fatalf("Failed to add transaction: %v", err)
+	}
+	if err := mp.AddTransaction(newSignedTx(t, w, dest.GetAddress(), 14, 0)); err == nil {
+		t.Error("Expected 40% bump to be rejected with 50% minimum")
+	}
+	if err := mp.AddTransaction(newSignedTx(t, w, dest.GetAddress(), 15, 0)); err != nil {
+		t.Errorf("Expected 50%% bump to replace transaction: %v", err)
+	}
+}
+
+func TestMempoolMinFee(t *testing.T) {
+	w, _ := wallet.NewWallet()
+	dest, _ := wallet.NewWallet()
+
+	chainConfig := DefaultChainConfig()
+	chainConfig.MinFee = 5
+
+	config := DefaultMempoolConfig()
+	config.MinFee = chainConfig.MinFee
+	mp := NewMempoolWithConfig(config)
+
+	if mp.MinFee() != 5 {
+		t.Fatalf("Expected min fee 5, got %d", mp.MinFee())
+	}
+
+	err := mp.AddTransaction(newSignedTx(t, w, dest.GetAddress(), 4, 0))
+	if err == nil || !strings.Contains(err.Error(), "below minimum 5") {
+		t.Errorf("Expected fee below minimum to be rejected, got %v", err)
+	}
+	if err := mp.AddTransaction(newSignedTx(t, w, dest.GetAddress(), 5, 0)); err != nil {
+		t.Errorf("Expected fee at minimum to be accepted: %v", err)
+	}
+
+	// Stake e unstake também pagam a fee mínima
+	stakeData, _ := NewStakeData(100).Serialize()
+	stakeTx := NewTransaction(w.GetAddress(), w.GetAddress(), 100, 0, 1, stakeData)
+	if err := stakeTx.Sign(w); err != nil {
+		t.Fatalf("Failed to sign stake transaction: %v", err)
+	}
+	if err := mp.AddTransaction(stakeTx); err == nil {
+		t.Error("Expected zero-fee stake transaction to be rejected")
+	}
+
+	// Sem mínimo, fee zero é aceita
+	config.MinFee = 0
+	free := NewMempoolWithConfig(config)
+	if err := free.AddTransaction(newSignedTx(t, w, dest.GetAddress(), 0, 0)); err != nil {
+		t.Errorf("Expected zero fee to be accepted without a minimum: %v", err)
+	}
+}
+
+func TestMempoolEvictsLowestFeeWhenFull(t *testing.T) {
+	dest, _ := wallet.NewWallet()
+
+	config := DefaultMempoolConfig()
+	config.MaxSize = 3
+	mp := NewMempoolWithConfig(config)
+
+	// Um remetente por transação: fees 5, 2 e 8
+	senders := make([]*wallet.Wallet, 5)
+	for i := range senders {
+		senders[i], _ = wallet.NewWallet()
+	}
+	pending := make([]*Transaction, 0, 3)
+	for i, fee := range []uint64{5, 2, 8} {
+		tx := newSignedTx(t, senders[i], dest.GetAddress(), fee, 0)
+		if err := mp.AddTransaction(tx); err != nil {
+			t.Fatalf("Failed to add transaction with fee %d: %v", fee, err)
+		}
+		pending = append(pending, tx)
+	}
+	if !mp.IsFull() || mp.Size() != 3 {
+		t.Fatalf("Expected full mempool with 3 transactions, got size %d", mp.Size())
+	}
+
+	// Fee igual à menor pendente não despeja ninguém
+	err := mp.AddTransaction(newSignedTx(t, senders[3], dest.GetAddress(), 2, 0))
+	if err == nil || !strings.Contains(err.Error(), "mempool is full") {
+		t.Fatalf("Expected transaction as cheap as the lowest to be rejected, got %v", err)
+	}
+
+	// Fee maior despeja a de menor fee (2)
+	richer := newSignedTx(t, senders[4], dest.GetAddress(), 3, 0)
+	if err := mp.AddTransaction(richer); err != nil {
+		t.Fatalf("Expected higher-fee transaction to be accepted: %v", err)
+	}
+	if mp.Size() != 3 || !mp.IsFull() {
+		t.Errorf("Mempool should stay at its maximum size, got %d", mp.Size())
+	}
+	if _, exists := mp.GetTransaction(pending[1].ID); exists {
+		t.Error("Lowest-fee transaction should be evicted")
+	}
+	if len(mp.GetTransactionsByAddress(senders[1].GetAddress())) != 0 {
+		t.Error("Evicted sender should have no pending transactions")
+	}
+	for _, tx := range []*Transaction{pending[0], pending[2], richer} {
+		if _, exists := mp.GetTransaction(tx.ID); !exists {
+			t.Errorf("Transaction with fee %d should be kept", tx.Fee)
+		}
+	}
+
+	// Com espaço livre o mempool deixa de estar cheio
+	mp.RemoveTransaction(richer.ID)
+	if mp.IsFull() {
+		t.Error("Mempool should not be full after removing a transaction")
+	}
+}
+
+func TestMempoolQueuesNonceGaps(t *testing.T) {
+	w, _ := wallet.NewWallet()
+	dest, _ := wallet.NewWallet()
+
+	config := DefaultMempoolConfig()
+	config.MaxQueuedPerAddress = 2
+	mp := NewMempoolWithConfig(config)
+	mp.SetNonceSource(func(address string) uint64 { return 4 }) // Nonces 0-3 já na chain
+
+	// Nonce 5 chega antes do 4: fica na fila, fora do alcance do minerador
+	tx5 := newSignedTx(t, w, dest.GetAddress(), 50, 5)
+	if err := mp.AddTransaction(tx5); err != nil {
+		t.Fatalf("Failed to queue transaction with nonce 5: %v", err)
+	}
+	if ready := mp.GetTransactionsByFee(0); len(ready) != 0 {
+		t.Fatalf("Expected no mineable transactions while nonce 4 is missing, got %d", len(ready))
+	}
+	if queued := mp.GetStats().QueuedTransactions; queued != 1 {
+		t.Errorf("Expected 1 queued transaction, got %d", queued)
+	}
+
+	// Nonce 4 preenche a lacuna: as duas ficam mineráveis, em ordem de nonce
+	tx4 := newSignedTx(t, w, dest.GetAddress(), 1, 4)
+	if err := mp.AddTransaction(tx4); err != nil {
+		t.Fatalf("Failed to add transaction with nonce 4: %v", err)
+	}
+	ready := mp.GetTransactionsByFee(0)
+	if len(ready) != 2 || ready[0].ID != tx4.ID || ready[1].ID != tx5.ID {
+		t.Fatalf("Expected nonces 4 and 5 to be mineable in order, got %d transactions", len(ready))
+	}
+	if queued := mp.GetStats().QueuedTransactions; queued != 0 {
+		t.Errorf("Expected empty queue after the gap is filled, got %d", queued)
+	}
+
+	// A fila do remetente é limitada: com 7 e 8 esperando o 6, o 9 é recusado
+	for _, nonce := range []uint64{7, 8} {
+		if err := mp.AddTransaction(newSignedTx(t, w, dest.GetAddress(), 1, nonce)); err != nil {
+			t.Fatalf("Failed to queue transaction with nonce %d: %v", nonce, err)
+		}
+	}
+	err := mp.AddTransaction(newSignedTx(t, w, dest.GetAddress(), 1, 9))
+	if err == nil || !strings.Contains(err.Error(), "maximum queued transactions") {
+		t.Fatalf("Expected queue limit to reject nonce 9, got %v", err)
+	}
+
+	// O nonce que falta continua aceito com a fila cheia e libera o resto
+	if err := mp.AddTransaction(newSignedTx(t, w, dest.GetAddress(), 1, 6)); err != nil {
+		t.Fatalf("Failed to fill the gap with nonce 6: %v", err)
+	}
+	if ready := mp.GetTransactionsByFee(0); len(ready) != 5 {
+		t.Errorf("Expected nonces 4-8 to be mineable, got %d transactions", len(ready))
+	}
+}
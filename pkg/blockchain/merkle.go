@@ -0,0 +1,117 @@
+package blockchain
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+)
+
+// Direção do irmão em cada passo da prova de Merkle (primeiro byte de cada elemento)
+const (
+	merkleSiblingLeft  byte = 0x00
+	merkleSiblingRight byte = 0x01
+)
+
+// merkleLeaf calcula a folha da árvore a partir do ID da transação
+func merkleLeaf(txID string) []byte {
+	hash := sha256.Sum256([]byte(txID))
+	return hash[:]
+}
+
+// merkleParent calcula o nó pai como sha256(esquerda || direita)
+func merkleParent(left, right []byte) []byte {
+	combined := make([]byte, 0, len(left)+len(right))
+	combined = append(combined, left...)
+	combined = append(combined, right...)
+	hash := sha256.Sum256(combined)
+	return hash[:]
+}
+
+// merkleLevels constrói todos os níveis da árvore, das folhas até a raiz.
+// Em níveis com quantidade ímpar de nós, o último é duplicado.
+func (txs TransactionSlice) merkleLevels() [][][]byte {
+	leaves := make([][]byte, len(txs))
+	for i, tx := range txs {
+		leaves[i] = merkleLeaf(tx.ID)
+	}
+
+	levels := [][][]byte{leaves}
+	for current := leaves; len(current) > 1; {
+		if len(current)%2 != 0 {
+			current = append(current, current[len(current)-1])
+		}
+
+		next := make([][]byte, 0, len(current)/2)
+		for i := 0; i < len(current); i += 2 {
+			next = append(next, merkleParent(current[i], current[i+1]))
+		}
+		levels = append(levels, next)
+		current = next
+	}
+
+	return levels
+}
+
+// MerkleProof gera a prova de inclusão de uma transação no bloco.
+// Cada elemento contém um byte de direção (irmão à esquerda ou à direita)
+// seguido do hash do irmão, da folha até a raiz.
+func (b *Block) MerkleProof(txID string) ([][]byte, error) {
+	index := -1
+	for i, tx := range b.Transactions {
+		if tx.ID == txID {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return nil, fmt.Errorf("transaction %s not found in block", txID)
+	}
+
+	levels := b.Transactions.merkleLevels()
+	proof := make([][]byte, 0, len(levels)-1)
+
+	for _, level := range levels[:len(levels)-1] {
+		var step []byte
+		if index%2 == 0 {
+			// Último nó de um nível ímpar é combinado com ele mesmo
+			sibling := level[index]
+			if index+1 < len(level) {
+				sibling = level[index+1]
+			}
+			step = append([]byte{merkleSiblingRight}, sibling...)
+		} else {
+			step = append([]byte{merkleSiblingLeft}, level[index-1]...)
+		}
+		proof = append(proof, step)
+		index /= 2
+	}
+
+	return proof, nil
+}
+
+// VerifyMerkleProof verifica se a transação está incluída na árvore com a raiz informada
+func VerifyMerkleProof(root, txID string, proof [][]byte) bool {
+	expected, err := hex.DecodeString(root)
+	if err != nil || len(expected) != sha256.Size {
+		return false
+	}
+
+	hash := merkleLeaf(txID)
+	for _, step := range proof {
+		if len(step) != 1+sha256.Size {
+			return false
+		}
+
+		switch step[0] {
+		case merkleSiblingLeft:
+			hash = merkleParent(step[1:], hash)
+		case merkleSiblingRight:
+			hash = merkleParent(hash, step[1:])
+		default:
+			return false
+		}
+	}
+
+	return bytes.Equal(hash, expected)
+}
@@ -0,0 +1,122 @@
+package blockchain
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/krakovia/blockchain/pkg/wallet"
+)
+
+func newMerkleTestBlock(t *testing.T, txCount int) *Block {
+	t.Helper()
+	w, _ := wallet.NewWallet()
+
+	txs := TransactionSlice{NewCoinbaseTransaction(w.GetAddress(), 50, 1)}
+	for i := 1; i < txCount; i++ {
+		tx := NewTransaction(w.GetAddress(), fmt.Sprintf("addr%d", i), 10, 1, uint64(i), "")
+		if err := tx.Sign(w); err != nil {
+			t.Fatalf("Failed to sign transaction: %v", err)
+		}
+		txs = append(txs, tx)
+	}
+
+	return NewBlock(1, "prev_hash", txs, w.GetAddress())
+}
+
+func TestMerkleProof(t *testing.T) {
+	for _, txCount := range []int{1, 2, 7} {
+		t.Run(fmt.Sprintf("%d transactions", txCount), func(t *testing.T) {
+			block := newMerkleTestBlock(t, txCount)
+			root := block.Header.MerkleRoot
+
+			for i, tx := range block.Transactions {
+				proof, err := block.MerkleProof(tx.ID)
+				if err != nil {
+					t.Fatalf("Failed to build proof for tx %d: %v", i, err)
+				}
+				if !VerifyMerkleProof(root, tx.ID, proof) {
+					t.Errorf("Proof for tx %d should be valid", i)
+				}
+
+				// A prova não vale para outra transação nem para outra raiz
+				other := block.Transactions[(i+1)%len(block.Transactions)]
+				if other.ID != tx.ID && VerifyMerkleProof(root, other.ID, proof) {
+					t.Errorf("Proof for tx %d should not verify tx %s", i, other.ID)
+				}
+				if VerifyMerkleProof(newMerkleTestBlock(t, txCount).Header.MerkleRoot, tx.ID, proof) {
+					t.Errorf("Proof for tx %d should not verify against another root", i)
+				}
+			}
+		})
+	}
+}
+
+func TestMerkleProofSingleTransaction(t *testing.T) {
+	block := newMerkleTestBlock(t, 1)
+	txID := block.Transactions[0].ID
+
+	// Com uma transação a raiz é a própria folha e a prova é vazia
+	proof, _ := block.MerkleProof(txID)
+	if len(proof) != 0 {
+		t.Errorf("Expected empty proof, got %d steps", len(proof))
+	}
+}
+
+func TestMerkleProofOddLevelDuplication(t *testing.T) {
+	block := newMerkleTestBlock(t, 7)
+	last := block.Transactions[6]
+
+	// 7 folhas -> 4 -> 2 -> 1: três passos, o primeiro com o último nó duplicado
+	proof, err := block.MerkleProof(last.ID)
+	if err != nil {
+		t.Fatalf("Failed to build proof: %v", err)
+	}
+	if len(proof) != 3 {
+		t.Fatalf("Expected 3 proof steps, got %d", len(proof))
+	}
+	if proof[0][0] != merkleSiblingRight || string(proof[0][1:]) != string(merkleLeaf(last.ID)) {
+		t.Error("Last leaf of an odd level should be paired with itself")
+	}
+}
+
+func TestMerkleProofErrors(t *testing.T) {
+	block := newMerkleTestBlock(t, 2)
+	txID := block.Transactions[1].ID
+
+	if _, err := block.MerkleProof("missing"); err == nil {
+		t.Error("Proof for unknown transaction should fail")
+	}
+
+	proof, _ := block.MerkleProof(txID)
+
+	tampered := [][]byte{append([]byte{}, proof[0]...)}
+	tampered[0][1] ^= 0xff
+	if VerifyMerkleProof(block.Header.MerkleRoot, txID, tampered) {
+		t.Error("Tampered proof should not verify")
+	}
+
+	flipped := [][]byte{append([]byte{}, proof[0]...)}
+	flipped[0][0] = merkleSiblingRight
+	if VerifyMerkleProof(block.Header.MerkleRoot, txID, flipped) {
+		t.Error("Proof with wrong direction should not verify")
+	}
+
+	if VerifyMerkleProof("not-hex", txID, proof) {
+		t.Error("Invalid root should not verify")
+	}
+	if VerifyMerkleProof(block.Header.MerkleRoot, txID, [][]byte{{merkleSiblingLeft}}) {
+		t.Error("Malformed proof step should not verify")
+	}
+}
+
+func TestMerkleRootChangesBlockHash(t *testing.T) {
+	block := newMerkleTestBlock(t, 2)
+
+	hash, _ := block.CalculateHash()
+	block.Header.MerkleRoot = newMerkleTestBlock(t, 2).Header.MerkleRoot
+	changed, _ := block.CalculateHash()
+
+	if hash == changed {
+		t.Error("Block hash should commit to the Merkle root")
+	}
+}
@@ -2,6 +2,7 @@ package blockchain
 
 import (
 	"fmt"
+	"sync/atomic"
 	"time"
 
 	"github.com/krakovia/blockchain/pkg/wallet"
@@ -19,11 +20,20 @@ type Miner struct {
 
 	// Callbacks para propagação
 	onBlockCreated func(*Block)
+	onBlockAdded   func(*Block)
 	onTxCreated    func(*Transaction)
 
+	// Remetentes aceitos na montagem de blocos (nil = todos)
+	senderFilter *SenderFilter
+
 	// Controle
-	mining    bool
+	mining    atomic.Bool
 	lastMined time.Time
+
+	// Bloco pai do MineLoop e o último instante em que ele pode ter sido selado (usado para
+	// esperar BlockTime desde o pai, já que o timestamp do header tem precisão de segundos)
+	parentHash     string
+	parentSealedBy time.Time
 }
 
 // NewMiner cria um novo minerador
@@ -41,11 +51,21 @@ func (m *Miner) SetOnBlockCreated(callback func(*Block)) {
 	m.onBlockCreated = callback
 }
 
+// SetOnBlockAdded define callback para quando um bloco minerado é adicionado à chain
+func (m *Miner) SetOnBlockAdded(callback func(*Block)) {
+	m.onBlockAdded = callback
+}
+
 // SetOnTxCreated define callback para quando uma transação é criada
 func (m *Miner) SetOnTxCreated(callback func(*Transaction)) {
 	m.onTxCreated = callback
 }
 
+// SetSenderFilter define quais remetentes podem ter transações incluídas nos blocos minerados
+func (m *Miner) SetSenderFilter(filter *SenderFilter) {
+	m.senderFilter = filter
+}
+
 // GetAddress retorna o endereço do minerador
 func (m *Miner) GetAddress() string {
 	return m.address
@@ -65,25 +85,8 @@ func (m *Miner) CanMine() bool {
 
 // IsMyTurn verifica se é a vez deste minerador criar o bloco
 func (m *Miner) IsMyTurn() bool {
-	validators := m.chain.GetValidators()
-	if len(validators) == 0 {
-		return false
-	}
-
-	// Usa o hash do último bloco para determinar prioridade
-	lastBlock := m.chain.GetLastBlock()
-	if lastBlock == nil {
-		return false
-	}
-
-	// Calcula prioridade dos validadores
-	pq, err := CalculateValidatorPriority(lastBlock.Hash, validators)
-	if err != nil {
-		return false
-	}
-
-	// Verifica se somos o top validator
-	return pq.IsTopValidator(m.address)
+	next := m.chain.NextValidator()
+	return next != "" && next == m.address
 }
 
 // TryMineBlock tenta criar um bloco se for a vez do minerador
@@ -102,7 +105,7 @@ func (m *Miner) TryMineBlock() (*Block, error) {
 	lastBlock := m.chain.GetLastBlock()
 	config := m.chain.GetConfig()
 
-	timeSinceLastBlock := time.Since(time.Unix(lastBlock.Header.Timestamp, 0))
+	timeSinceLastBlock := m.chain.Now().Sub(time.Unix(lastBlock.Header.Timestamp, 0))
 	if timeSinceLastBlock < config.BlockTime {
 		return nil, fmt.Errorf("too soon to mine (need to wait %v)", config.BlockTime-timeSinceLastBlock)
 	}
@@ -126,52 +129,19 @@ func (m *Miner) TryMineBlock() (*Block, error) {
 
 // CreateBlock cria um novo bloco com transações do mempool
 func (m *Miner) CreateBlock() (*Block, error) {
-	lastBlock := m.chain.GetLastBlock()
-	if lastBlock == nil {
-		return nil, fmt.Errorf("no last block")
-	}
-
-	config := m.chain.GetConfig()
-
-	// Cria transação coinbase (recompensa)
-	coinbase := NewCoinbaseTransaction(
-		m.address,
-		config.BlockReward,
-		lastBlock.Header.Height+1,
-	)
-
-	// Pega transações válidas do mempool
-	validTxs := m.mempool.GetValidTransactions(m.chain.context, config.MaxBlockSize-1)
-
-	// Monta lista de transações (coinbase primeiro)
-	transactions := make(TransactionSlice, 0, len(validTxs)+1)
-	transactions = append(transactions, coinbase)
-	transactions = append(transactions, validTxs...)
-
-	// Cria o bloco
-	block := NewBlock(
-		lastBlock.Header.Height+1,
-		lastBlock.Hash,
-		transactions,
-		m.address,
-	)
-
-	// Garante que o timestamp respeita o tempo mínimo entre blocos (80% do BlockTime)
-	minBlockTime := int64(config.BlockTime.Seconds() * 0.8)
-	minTimestamp := lastBlock.Header.Timestamp + minBlockTime
-	if block.Header.Timestamp < minTimestamp {
-		block.Header.Timestamp = minTimestamp
+	template, err := m.buildTemplate()
+	if err != nil {
+		return nil, err
 	}
+	block := template.NewBlock()
 
-	// Calcula hash
-	hash, err := block.CalculateHash()
-	if err != nil {
-		return nil, fmt.Errorf("failed to calculate block hash: %w", err)
+	// Calcula hash e assina com a chave do validador
+	if err := block.Sign(m.wallet); err != nil {
+		return nil, fmt.Errorf("failed to sign block: %w", err)
 	}
-	block.Hash = hash
 
 	// Valida bloco
-	if err := block.Validate(); err != nil {
+	if err := block.ValidateAt(m.chain.Now(), m.chain.GetConfig().ClockDrift()); err != nil {
 		return nil, fmt.Errorf("created invalid block: %w", err)
 	}
 
@@ -180,9 +150,16 @@ func (m *Miner) CreateBlock() (*Block, error) {
 
 // CreateTransaction cria uma nova transação assinada
 func (m *Miner) CreateTransaction(to string, amount, fee uint64, data string) (*Transaction, error) {
-	nonce := m.chain.GetNonce(m.address)
+	return m.createTransaction(to, amount, fee, data, 0)
+}
+
+// createTransaction cria e assina uma transação que expira depois de expiryHeight (0 = nunca)
+func (m *Miner) createTransaction(to string, amount, fee uint64, data string, expiryHeight uint64) (*Transaction, error) {
+	// Segue as transações ainda pendentes no mempool para não substituí-las
+	nonce := m.mempool.NextNonce(m.address, m.chain.GetNonce(m.address))
 
 	tx := NewTransaction(m.address, to, amount, fee, nonce, data)
+	tx.ExpiryHeight = expiryHeight
 
 	if err := tx.Sign(m.wallet); err != nil {
 		return nil, fmt.Errorf("failed to sign transaction: %w", err)
@@ -198,6 +175,12 @@ func (m *Miner) CreateTransaction(to string, amount, fee uint64, data string) (*
 
 // CreateStakeTransaction cria uma transação para fazer stake
 func (m *Miner) CreateStakeTransaction(amount, fee uint64) (*Transaction, error) {
+	// Avisa quando o stake resultante não qualifica (a transação será rejeitada na execução)
+	minStake := m.chain.GetConfig().MinValidatorStake
+	if total := m.chain.GetStake(m.address) + amount; total < minStake {
+		fmt.Printf("⚠️  Stake total after this transaction (%d) is below the minimum validator stake (%d); it will be rejected\n", total, minStake)
+	}
+
 	stakeData := NewStakeData(amount)
 	dataStr, err := stakeData.Serialize()
 	if err != nil {
@@ -209,6 +192,10 @@ func (m *Miner) CreateStakeTransaction(amount, fee uint64) (*Transaction, error)
 
 // CreateUnstakeTransaction cria uma transação para fazer unstake
 func (m *Miner) CreateUnstakeTransaction(amount, fee uint64) (*Transaction, error) {
+	if period := m.chain.GetConfig().UnbondingPeriod; period > 0 {
+		fmt.Printf("ℹ️  Unstaked tokens stay locked for %d blocks before becoming spendable\n", period)
+	}
+
 	unstakeData := NewUnstakeData(amount)
 	dataStr, err := unstakeData.Serialize()
 	if err != nil {
@@ -218,11 +205,76 @@ func (m *Miner) CreateUnstakeTransaction(amount, fee uint64) (*Transaction, erro
 	return m.CreateTransaction(m.address, amount, fee, dataStr)
 }
 
+// CreateRegisterNameTransaction cria uma transação que registra o nome de exibição do minerador
+func (m *Miner) CreateRegisterNameTransaction(name string, fee uint64) (*Transaction, error) {
+	if err := ValidateValidatorName(name); err != nil {
+		return nil, fmt.Errorf("invalid name: %w", err)
+	}
+
+	nameData := NewRegisterNameData(name)
+	dataStr, err := nameData.Serialize()
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize register name data: %w", err)
+	}
+
+	return m.CreateTransaction(m.address, 0, fee, dataStr)
+}
+
+// CreateDoubleSignEvidenceTransaction cria uma transação que denuncia uma assinatura dupla. Ela
+// expira junto com a evidência, EvidenceMaxAge blocos depois da altura denunciada.
+func (m *Miner) CreateDoubleSignEvidenceTransaction(evidence *SlashingEvidence, fee uint64) (*Transaction, error) {
+	evidenceData, err := NewDoubleSignEvidenceData(evidence)
+	if err != nil {
+		return nil, err
+	}
+	dataStr, err := evidenceData.Serialize()
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize double sign evidence data: %w", err)
+	}
+
+	return m.createTransaction(m.address, 0, fee, dataStr, evidence.Height+m.chain.GetConfig().EvidenceMaxAge())
+}
+
+// nextBlockTime retorna quando o próximo bloco pode ser selado sobre parent: BlockTime depois do
+// último instante em que parent pode ter sido selado. Um bloco próprio é selado no momento em que
+// o MineLoop o adiciona; um bloco de outro validador foi selado antes de ser visto aqui pela
+// primeira vez e no máximo 1s depois do seu timestamp (truncado para segundos).
+func (m *Miner) nextBlockTime(parent *Block) time.Time {
+	if parent.Hash != m.parentHash {
+		m.parentHash = parent.Hash
+		m.parentSealedBy = m.chain.Now()
+		if byTimestamp := time.Unix(parent.Header.Timestamp+1, 0); byTimestamp.Before(m.parentSealedBy) {
+			m.parentSealedBy = byTimestamp
+		}
+	}
+	return m.parentSealedBy.Add(m.chain.GetConfig().BlockTime)
+}
+
+// waitBlockTime espera até que BlockTime tenha passado desde o bloco pai atual, refazendo a conta
+// se a ponta da chain mudar durante a espera. Retorna o pai sobre o qual o próximo bloco pode
+// ser selado, ou nil se stopChan foi sinalizado.
+func (m *Miner) waitBlockTime(stopChan <-chan struct{}) *Block {
+	for {
+		parent := m.chain.GetLastBlock()
+		wait := m.nextBlockTime(parent).Sub(m.chain.Now())
+		if wait <= 0 {
+			return parent
+		}
+
+		select {
+		case <-stopChan:
+			return nil
+		case <-time.After(wait):
+		}
+	}
+}
+
 // MineLoop inicia loop de mineração (para testes)
-// Retorna quando stopChan recebe sinal
+// Retorna quando stopChan recebe sinal. Um bloco só é selado depois de BlockTime desde o bloco
+// pai, mesmo que a seleção de validadores permita antes.
 func (m *Miner) MineLoop(stopChan <-chan struct{}) {
-	m.mining = true
-	defer func() { m.mining = false }()
+	m.mining.Store(true)
+	defer m.mining.Store(false)
 
 	config := m.chain.GetConfig()
 	ticker := time.NewTicker(config.BlockTime / 4) // Verifica 4x por período de bloco
@@ -234,6 +286,16 @@ func (m *Miner) MineLoop(stopChan <-chan struct{}) {
 			return
 
 		case <-ticker.C:
+			// Só tenta minerar se for a vez, para não esperar à toa
+			if !m.CanMine() || !m.IsMyTurn() {
+				continue
+			}
+
+			// Espera o tempo de bloco desde o pai
+			if m.waitBlockTime(stopChan) == nil {
+				return
+			}
+
 			// Tenta minerar
 			block, err := m.TryMineBlock()
 			if err != nil {
@@ -247,19 +309,33 @@ func (m *Miner) MineLoop(stopChan <-chan struct{}) {
 				continue
 			}
 
+			// O próximo bloco espera BlockTime a partir de agora
+			m.parentHash = block.Hash
+			m.parentSealedBy = m.chain.Now()
+
 			// Remove transações do mempool
-			txIDs := make([]string, 0, len(block.Transactions)-1)
-			for i := 1; i < len(block.Transactions); i++ { // Pula coinbase
-				txIDs = append(txIDs, block.Transactions[i].ID)
+			m.removeMinedTransactions(block)
+
+			if m.onBlockAdded != nil {
+				m.onBlockAdded(block)
 			}
-			m.mempool.RemoveTransactions(txIDs)
 		}
 	}
 }
 
+// removeMinedTransactions remove do mempool as transações incluídas no bloco e as expiradas
+func (m *Miner) removeMinedTransactions(block *Block) {
+	txIDs := make([]string, 0, len(block.Transactions)-1)
+	for i := 1; i < len(block.Transactions); i++ { // Pula coinbase
+		txIDs = append(txIDs, block.Transactions[i].ID)
+	}
+	m.mempool.RemoveTransactions(txIDs)
+	m.mempool.RemoveExpiredTransactions(block.Header.Height)
+}
+
 // IsMining retorna se o minerador está ativamente minerando
 func (m *Miner) IsMining() bool {
-	return m.mining
+	return m.mining.Load()
 }
 
 // GetLastMinedTime retorna o tempo desde a última mineração
@@ -26,6 +26,7 @@ type Node struct {
 	// Controle de mineração
 	mining   bool
 	stopChan chan struct{}
+	loopDone chan struct{} // Fechado quando o MineLoop retorna
 
 	// Callbacks para testes
 	onBlockReceived func(*Block)
@@ -35,6 +36,7 @@ type Node struct {
 // NewNode cria um novo nó com uma blockchain
 func NewNode(id string, w *wallet.Wallet, chain *Chain, mempool *Mempool) *Node {
 	miner := NewMiner(w, chain, mempool)
+	mempool.SetNonceSource(chain.GetNonce)
 
 	node := &Node{
 		id:      id,
@@ -163,6 +165,7 @@ func (n *Node) ReceiveBlock(block *Block) error {
 		}
 	}
 	n.mempool.RemoveTransactions(txIDs)
+	n.mempool.RemoveExpiredTransactions(block.Header.Height)
 
 	// Propaga para outros peers (exceto quem enviou)
 	// Nota: em uma rede real, teríamos lógica de roteamento mais sofisticada
@@ -205,21 +208,30 @@ func (n *Node) StartMining() {
 
 	n.mining = true
 	n.stopChan = make(chan struct{})
+	n.loopDone = make(chan struct{})
 
-	go n.miner.MineLoop(n.stopChan)
+	go func(stop <-chan struct{}, done chan<- struct{}) {
+		defer close(done)
+		n.miner.MineLoop(stop)
+	}(n.stopChan, n.loopDone)
 }
 
-// StopMining para a mineração
+// StopMining para a mineração e espera o MineLoop retornar: depois dela o nó não cria mais
+// blocos (os já criados podem ainda estar sendo propagados)
 func (n *Node) StopMining() {
 	n.mu.Lock()
-	defer n.mu.Unlock()
-
 	if !n.mining {
+		n.mu.Unlock()
 		return
 	}
 
 	close(n.stopChan)
 	n.mining = false
+	done := n.loopDone
+	n.mu.Unlock()
+
+	// Espera fora do lock: o MineLoop propaga blocos via métodos que usam n.mu
+	<-done
 }
 
 // IsMining retorna se o nó está minerando
@@ -346,6 +358,7 @@ func (n *Node) SyncWithPeer(peer *Node) error {
 			}
 		}
 		n.mempool.RemoveTransactions(txIDs)
+		n.mempool.RemoveExpiredTransactions(block.Header.Height)
 	}
 
 	return nil
@@ -0,0 +1,60 @@
+package blockchain
+
+import "github.com/krakovia/blockchain/pkg/consensus"
+
+// ScheduleEntry validador de uma altura na agenda de produção de blocos
+type ScheduleEntry struct {
+	Height       uint64 `json:"height"`
+	PreviousHash string `json:"previous_hash"` // Hash do bloco anterior (semente do sorteio)
+	Validator    string `json:"validator"`     // Quem produziu o bloco, ou o validador previsto
+	Produced     bool   `json:"produced"`      // O bloco já está na chain
+}
+
+// NextValidator retorna o validador prioritário para o bloco seguinte à ponta, com os stakes
+// atuais (consensus.SelectValidator). Retorna "" se não houver validadores ativos.
+func (c *Chain) NextValidator() string {
+	lastBlock := c.GetLastBlock()
+	if lastBlock == nil {
+		return ""
+	}
+	return consensus.SelectValidator(lastBlock.Header.Height+1, lastBlock.Hash, c.GetValidators().Stakes())
+}
+
+// ValidatorSchedule retorna a agenda de validadores de fromHeight a toHeight. Alturas já na
+// chain trazem quem produziu o bloco; a altura seguinte à ponta traz o validador previsto.
+// Alturas além dela não entram: o sorteio depende do hash de um bloco que ainda não existe.
+func (c *Chain) ValidatorSchedule(fromHeight, toHeight uint64) []ScheduleEntry {
+	if fromHeight == 0 {
+		fromHeight = 1 // O gênesis não tem validador
+	}
+
+	lastBlock := c.GetLastBlock()
+	if next := lastBlock.Header.Height + 1; toHeight > next {
+		toHeight = next
+	}
+
+	schedule := make([]ScheduleEntry, 0)
+	for height := fromHeight; height <= toHeight; height++ {
+		if height == lastBlock.Header.Height+1 {
+			schedule = append(schedule, ScheduleEntry{
+				Height:       height,
+				PreviousHash: lastBlock.Hash,
+				Validator:    consensus.SelectValidator(height, lastBlock.Hash, c.GetValidators().Stakes()),
+			})
+			break
+		}
+
+		block, exists := c.GetBlockByHeight(height)
+		if !exists || block.IsCheckpointAnchor() {
+			continue // Podado da memória ou anterior ao checkpoint restaurado
+		}
+		schedule = append(schedule, ScheduleEntry{
+			Height:       height,
+			PreviousHash: block.Header.PreviousHash,
+			Validator:    block.Header.ValidatorAddr,
+			Produced:     true,
+		})
+	}
+
+	return schedule
+}
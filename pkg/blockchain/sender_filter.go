@@ -0,0 +1,45 @@
+package blockchain
+
+// SenderFilter restringe os remetentes cujas transações entram em blocos (e, opcionalmente,
+// no mempool) em deployments permissionados. Sem listas, aceita todos os remetentes;
+// com allowlist, apenas os endereços listados; a denylist sempre exclui.
+type SenderFilter struct {
+	allow map[string]bool
+	deny  map[string]bool
+}
+
+// NewSenderFilter cria um filtro a partir das listas de endereços permitidos e bloqueados
+func NewSenderFilter(allow, deny []string) *SenderFilter {
+	f := &SenderFilter{
+		allow: make(map[string]bool, len(allow)),
+		deny:  make(map[string]bool, len(deny)),
+	}
+	for _, addr := range allow {
+		f.allow[addr] = true
+	}
+	for _, addr := range deny {
+		f.deny[addr] = true
+	}
+	return f
+}
+
+// IsEmpty verifica se o filtro aceita qualquer remetente
+func (f *SenderFilter) IsEmpty() bool {
+	return f == nil || (len(f.allow) == 0 && len(f.deny) == 0)
+}
+
+// Allows verifica se transações do endereço são aceitas (filtro nil aceita todos)
+func (f *SenderFilter) Allows(address string) bool {
+	if f == nil {
+		return true
+	}
+	if f.deny[address] {
+		return false
+	}
+	return len(f.allow) == 0 || f.allow[address]
+}
+
+// AllowsTransaction verifica se a transação é aceita; coinbase nunca é filtrada
+func (f *SenderFilter) AllowsTransaction(tx *Transaction) bool {
+	return tx.IsCoinbase() || f.Allows(tx.From)
+}
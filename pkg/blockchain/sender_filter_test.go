@@ -0,0 +1,127 @@
+package blockchain
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/krakovia/blockchain/pkg/wallet"
+)
+
+// Helper: chain com dois remetentes com saldo e um mempool com uma transação de cada
+func createTwoSenderChain(t *testing.T) (*Chain, *Mempool, *wallet.Wallet, *wallet.Wallet) {
+	t.Helper()
+
+	allowed, _ := wallet.NewWallet()
+	other, _ := wallet.NewWallet()
+	dest, _ := wallet.NewWallet()
+
+	config := DefaultChainConfig()
+	config.BlockTime = 100 * time.Millisecond
+
+	genesis := GenesisBlock(NewCoinbaseTransaction(allowed.GetAddress(), 10000, 0))
+	chain, err := NewChain(genesis, config)
+	if err != nil {
+		t.Fatalf("Failed to create chain: %v", err)
+	}
+	chain.context = NewContextFromState(0, genesis.Hash, map[string]*AccountState{
+		allowed.GetAddress(): {Address: allowed.GetAddress(), Balance: 10000},
+		other.GetAddress():   {Address: other.GetAddress(), Balance: 10000},
+	})
+
+	mp := NewMempool()
+	for _, w := range []*wallet.Wallet{allowed, other} {
+		if err := mp.AddTransaction(newSignedTx(t, w, dest.GetAddress(), 1, 0)); err != nil {
+			t.Fatalf("Failed to add transaction: %v", err)
+		}
+	}
+
+	return chain, mp, allowed, other
+}
+
+func TestSenderFilterAllows(t *testing.T) {
+	var empty *SenderFilter
+	if !empty.IsEmpty() || !empty.Allows("anyone") {
+		t.Error("Nil filter should allow every sender")
+	}
+	if !NewSenderFilter(nil, nil).IsEmpty() {
+		t.Error("Filter without lists should be empty")
+	}
+
+	filter := NewSenderFilter([]string{"alice", "bob"}, []string{"bob"})
+	if !filter.Allows("alice") {
+		t.Error("Allowlisted sender should be allowed")
+	}
+	if filter.Allows("bob") {
+		t.Error("Denylist should take precedence over allowlist")
+	}
+	if filter.Allows("carol") {
+		t.Error("Sender outside the allowlist should be rejected")
+	}
+
+	denyOnly := NewSenderFilter(nil, []string{"mallory"})
+	if denyOnly.Allows("mallory") || !denyOnly.Allows("carol") {
+		t.Error("Denylist-only filter should reject only listed senders")
+	}
+	if !denyOnly.AllowsTransaction(NewCoinbaseTransaction("mallory", 50, 1)) {
+		t.Error("Coinbase should never be filtered")
+	}
+}
+
+func TestMinerSenderAllowlist(t *testing.T) {
+	chain, mp, allowed, _ := createTwoSenderChain(t)
+
+	miner := NewMiner(allowed, chain, mp)
+	miner.SetSenderFilter(NewSenderFilter([]string{allowed.GetAddress()}, nil))
+
+	block, err := miner.CreateBlock()
+	if err != nil {
+		t.Fatalf("Failed to create block: %v", err)
+	}
+
+	regular := block.GetRegularTransactions()
+	if len(regular) != 1 {
+		t.Fatalf("Expected 1 non-coinbase transaction, got %d", len(regular))
+	}
+	if regular[0].From != allowed.GetAddress() {
+		t.Errorf("Expected only allowlisted sender to be mined, got %s", regular[0].From)
+	}
+
+	// A transação filtrada continua no mempool para outros validadores
+	if mp.Size() != 2 {
+		t.Errorf("Filtered transaction should remain in the mempool, size %d", mp.Size())
+	}
+}
+
+func TestMinerWithoutSenderFilter(t *testing.T) {
+	chain, mp, allowed, _ := createTwoSenderChain(t)
+
+	block, err := NewMiner(allowed, chain, mp).CreateBlock()
+	if err != nil {
+		t.Fatalf("Failed to create block: %v", err)
+	}
+	if got := len(block.GetRegularTransactions()); got != 2 {
+		t.Errorf("Expected both senders to be mined by default, got %d", got)
+	}
+}
+
+func TestMempoolSenderFilter(t *testing.T) {
+	allowed, _ := wallet.NewWallet()
+	denied, _ := wallet.NewWallet()
+	dest, _ := wallet.NewWallet()
+
+	mp := NewMempool()
+	mp.SetSenderFilter(NewSenderFilter(nil, []string{denied.GetAddress()}))
+
+	if err := mp.AddTransaction(newSignedTx(t, allowed, dest.GetAddress(), 1, 0)); err != nil {
+		t.Errorf("Sender outside the denylist should be admitted: %v", err)
+	}
+
+	err := mp.AddTransaction(newSignedTx(t, denied, dest.GetAddress(), 1, 0))
+	if err == nil || !strings.Contains(err.Error(), "not allowed") {
+		t.Errorf("Expected denylisted sender to be rejected, got %v", err)
+	}
+	if mp.Size() != 1 {
+		t.Errorf("Expected 1 transaction in mempool, got %d", mp.Size())
+	}
+}
@@ -0,0 +1,205 @@
+package blockchain
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/bits"
+)
+
+// DoubleSignWindow quantas alturas abaixo do topo a chain lembra quem assinou cada bloco
+const DoubleSignWindow = 1000
+
+// evidenceMaxAge retorna até quantos blocos depois da altura denunciada uma evidência de assinatura
+// dupla ainda pode entrar em um bloco: DoubleSignWindow, limitado a unbondingPeriod-1 para que um
+// unstake feito a partir da altura denunciada ainda esteja em unbonding quando a evidência expira.
+// Sem unbonding (0 ou 1 bloco) o stake sai antes de qualquer denúncia e não há punição possível.
+func evidenceMaxAge(unbondingPeriod uint64) uint64 {
+	if unbondingPeriod == 0 {
+		return 0
+	}
+	if unbondingPeriod-1 < DoubleSignWindow {
+		return unbondingPeriod - 1
+	}
+	return DoubleSignWindow
+}
+
+// BasisPointsDenominator pontos base em 100% (SlashBasisPoints = 10000 queima o stake inteiro)
+const BasisPointsDenominator = 10000
+
+// slashAmount retorna quanto de amount é queimado com basisPoints pontos base, arredondando para
+// baixo. Usa apenas aritmética inteira de 128 bits, para que todos os nós cheguem ao mesmo valor.
+func slashAmount(amount, basisPoints uint64) uint64 {
+	if basisPoints >= BasisPointsDenominator {
+		return amount
+	}
+	// hi < basisPoints < BasisPointsDenominator, então a divisão não estoura
+	hi, lo := bits.Mul64(amount, basisPoints)
+	quotient, _ := bits.Div64(hi, lo, BasisPointsDenominator)
+	return quotient
+}
+
+// SlashingEvidence prova de que um validador assinou dois blocos diferentes na mesma altura.
+// Os blocos carregam apenas header, hash e assinatura (o hash não cobre as transações),
+// o que basta para qualquer nó verificar a evidência recebida pela rede.
+type SlashingEvidence struct {
+	Validator string `json:"validator"`
+	Height    uint64 `json:"height"`
+	BlockA    *Block `json:"block_a"`
+	BlockB    *Block `json:"block_b"`
+}
+
+// NewSlashingEvidence cria a evidência a partir de dois blocos conflitantes.
+// Os blocos são ordenados pelo hash para que a mesma evidência seja idêntica em todos os nós.
+func NewSlashingEvidence(a, b *Block) *SlashingEvidence {
+	first, second := signedHeader(a), signedHeader(b)
+	if second.Hash < first.Hash {
+		first, second = second, first
+	}
+
+	return &SlashingEvidence{
+		Validator: first.Header.ValidatorAddr,
+		Height:    first.Header.Height,
+		BlockA:    first,
+		BlockB:    second,
+	}
+}
+
+// signedHeader retorna uma cópia do bloco sem as transações
+func signedHeader(block *Block) *Block {
+	return &Block{
+		Header: block.Header,
+		Hash:   block.Hash,
+	}
+}
+
+// Verify verifica que os dois blocos são diferentes, da mesma altura e assinados pelo validador
+func (e *SlashingEvidence) Verify() error {
+	if e.BlockA == nil || e.BlockB == nil {
+		return fmt.Errorf("evidence must contain two blocks")
+	}
+	if e.BlockA.Hash == e.BlockB.Hash {
+		return fmt.Errorf("evidence blocks are identical")
+	}
+
+	for _, block := range []*Block{e.BlockA, e.BlockB} {
+		if block.Header.ValidatorAddr != e.Validator {
+			return fmt.Errorf("evidence block validator %s does not match %s", block.Header.ValidatorAddr, e.Validator)
+		}
+		if block.Header.Height != e.Height {
+			return fmt.Errorf("evidence block height %d does not match %d", block.Header.Height, e.Height)
+		}
+		if err := block.VerifySignature(); err != nil {
+			return fmt.Errorf("evidence block %s: %w", block.Hash, err)
+		}
+	}
+
+	return nil
+}
+
+// Serialize serializa a evidência para JSON
+func (e *SlashingEvidence) Serialize() ([]byte, error) {
+	return json.Marshal(e)
+}
+
+// DeserializeSlashingEvidence desserializa uma evidência de JSON
+func DeserializeSlashingEvidence(data []byte) (*SlashingEvidence, error) {
+	var evidence SlashingEvidence
+	if err := json.Unmarshal(data, &evidence); err != nil {
+		return nil, fmt.Errorf("failed to deserialize double sign evidence: %w", err)
+	}
+	return &evidence, nil
+}
+
+// CheckSlashingEvidence verifica se a evidência ainda pune o validador no próximo bloco: os dois
+// blocos conflitam e foram assinados por ele, a altura está dentro de EvidenceMaxAge e acima da
+// última altura punida. Não altera o estado; a punição só acontece quando a evidência entra em um
+// bloco, em uma transação de denúncia.
+func (c *Chain) CheckSlashingEvidence(evidence *SlashingEvidence) error {
+	if err := evidence.Verify(); err != nil {
+		return err
+	}
+
+	next := c.GetHeight() + 1
+	if evidence.Height >= next {
+		return fmt.Errorf("evidence height %d is not below next block height %d", evidence.Height, next)
+	}
+	if maxAge := c.config.EvidenceMaxAge(); next-evidence.Height > maxAge {
+		return fmt.Errorf("evidence at height %d is older than %d blocks", evidence.Height, maxAge)
+	}
+	if last := c.GetSlashHeight(evidence.Validator); evidence.Height <= last {
+		return fmt.Errorf("validator %s already slashed at height %d", evidence.Validator, last)
+	}
+	return nil
+}
+
+// SetOnDoubleSign define o callback chamado quando a chain detecta que um validador assinou dois
+// blocos na mesma altura. A detecção não pune ninguém: o stake só é queimado quando a evidência
+// entra em um bloco, em uma transação de denúncia (ver NewDoubleSignEvidenceData).
+func (c *Chain) SetOnDoubleSign(callback func(*SlashingEvidence)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.onDoubleSign = callback
+}
+
+// notifyDoubleSign chama o callback de assinatura dupla, se configurado (sem lock)
+func (c *Chain) notifyDoubleSign(evidence *SlashingEvidence) {
+	c.mu.RLock()
+	callback := c.onDoubleSign
+	c.mu.RUnlock()
+
+	if callback != nil {
+		callback(evidence)
+	}
+}
+
+// checkDoubleSign compara o bloco com o que o mesmo validador já assinou nessa altura.
+// Retorna a evidência se o bloco conflita com um bloco aceito, uma única vez por validador e altura.
+func (c *Chain) checkDoubleSign(block *Block) *SlashingEvidence {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	recorded, exists := c.signedBlocks[block.Header.Height][block.Header.ValidatorAddr]
+	if !exists || recorded.Hash == block.Hash {
+		return nil
+	}
+
+	// Só um bloco realmente assinado pelo validador pode incriminá-lo
+	if err := block.VerifySignature(); err != nil {
+		return nil
+	}
+
+	key := fmt.Sprintf("%s-%d", block.Header.ValidatorAddr, block.Header.Height)
+	if c.reported[key] {
+		return nil
+	}
+	c.reported[key] = true
+
+	evidence := NewSlashingEvidence(recorded, block)
+	fmt.Printf("⚠️  Double sign by %s at height %d (%s / %s)\n",
+		evidence.Validator, evidence.Height, evidence.BlockA.Hash[:8], evidence.BlockB.Hash[:8])
+	return evidence
+}
+
+// HasSignedAt verifica se a chain já viu um bloco do validador na altura (dentro da janela de
+// DoubleSignWindow alturas). Um validador não deve assinar outro bloco nessa altura.
+func (c *Chain) HasSignedAt(height uint64, validator string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	_, exists := c.signedBlocks[height][validator]
+	return exists
+}
+
+// recordSignedBlock registra o bloco aceito como o assinado pelo validador nessa altura
+// e esquece alturas fora da janela (deve ser chamado com lock)
+func (c *Chain) recordSignedBlock(block *Block) {
+	height := block.Header.Height
+	if c.signedBlocks[height] == nil {
+		c.signedBlocks[height] = make(map[string]*Block)
+	}
+	c.signedBlocks[height][block.Header.ValidatorAddr] = signedHeader(block)
+
+	if height > DoubleSignWindow {
+		delete(c.signedBlocks, height-DoubleSignWindow-1)
+	}
+}
@@ -0,0 +1,337 @@
+package blockchain
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/krakovia/blockchain/pkg/wallet"
+)
+
+// Helper: bloco assinado pelo validador na altura 1, com nonce distinto para gerar hashes diferentes
+func createConflictingBlock(t *testing.T, genesis *Block, validator *wallet.Wallet, nonce uint64) *Block {
+	t.Helper()
+
+	txs := TransactionSlice{NewCoinbaseTransaction(validator.GetAddress(), 50, 1)}
+	block := NewBlock(1, genesis.Hash, txs, validator.GetAddress())
+	block.Header.Timestamp = genesis.Header.Timestamp + 1
+	block.Header.Nonce = nonce
+	if err := block.Sign(validator); err != nil {
+		t.Fatalf("Failed to sign block: %v", err)
+	}
+	return block
+}
+
+func newSlashingChain(t *testing.T, genesis *Block, validator *wallet.Wallet) *Chain {
+	t.Helper()
+
+	config := DefaultChainConfig()
+	config.BlockTime = 100 * time.Millisecond
+	config.SlashBasisPoints = 2500
+
+	chain, err := NewChainWithStake(genesis, config, validator.GetAddress(), 1000)
+	if err != nil {
+		t.Fatalf("Failed to create chain: %v", err)
+	}
+	return chain
+}
+
+// Helper: gênesis com saldo para o validador e para quem denuncia
+func createSlashingGenesis(t *testing.T, validator, reporter *wallet.Wallet) *Block {
+	t.Helper()
+
+	genesis, err := GenesisBlockWithAllocations([]GenesisAllocation{
+		{Address: validator.GetAddress(), Amount: 10000},
+		{Address: reporter.GetAddress(), Amount: 100},
+	}, time.Now().Unix())
+	if err != nil {
+		t.Fatalf("Failed to create genesis: %v", err)
+	}
+	return genesis
+}
+
+// Helper: transação de denúncia assinada por reporter, com fee 10
+func createEvidenceTransaction(t *testing.T, chain *Chain, reporter *wallet.Wallet, evidence *SlashingEvidence) *Transaction {
+	t.Helper()
+
+	tx, err := NewMiner(reporter, chain, NewMempool()).CreateDoubleSignEvidenceTransaction(evidence, 10)
+	if err != nil {
+		t.Fatalf("Failed to create evidence transaction: %v", err)
+	}
+	return tx
+}
+
+func TestChainDoubleSignDetection(t *testing.T) {
+	validator, _ := wallet.NewWallet()
+	genesis := GenesisBlock(NewCoinbaseTransaction(validator.GetAddress(), 10000, 0))
+	chain := newSlashingChain(t, genesis, validator)
+
+	var events []*SlashingEvidence
+	chain.SetOnDoubleSign(func(evidence *SlashingEvidence) {
+		events = append(events, evidence)
+	})
+
+	first := createConflictingBlock(t, genesis, validator, 1)
+	second := createConflictingBlock(t, genesis, validator, 2)
+
+	if err := chain.AddBlock(first); err != nil {
+		t.Fatalf("Failed to add first block: %v", err)
+	}
+	if !chain.HasSignedAt(1, validator.GetAddress()) {
+		t.Error("Validator should be recorded as signer at height 1")
+	}
+
+	err := chain.AddBlock(second)
+	if err == nil || !strings.Contains(err.Error(), "double sign") {
+		t.Fatalf("Expected double sign error, got %v", err)
+	}
+
+	// A detecção não pune: o stake só muda quando a evidência entra em um bloco
+	if stake := chain.GetStake(validator.GetAddress()); stake != 1000 {
+		t.Errorf("Detection alone should not slash, got stake %d", stake)
+	}
+	if len(events) != 1 {
+		t.Fatalf("Expected 1 double sign event, got %d", len(events))
+	}
+	if events[0].Validator != validator.GetAddress() || events[0].Height != 1 {
+		t.Errorf("Unexpected evidence: validator %s height %d", events[0].Validator, events[0].Height)
+	}
+	if err := events[0].Verify(); err != nil {
+		t.Errorf("Reported evidence should verify: %v", err)
+	}
+
+	// Reenviar o bloco conflitante não gera outra denúncia
+	if err := chain.AddBlock(second); err == nil {
+		t.Error("Conflicting block should still be rejected")
+	}
+	if len(events) != 1 {
+		t.Errorf("Expected no new double sign event, got %d", len(events))
+	}
+}
+
+func TestDoubleSignEvidenceTransactionSlashesValidator(t *testing.T) {
+	validator, _ := wallet.NewWallet()
+	reporter, _ := wallet.NewWallet()
+	genesis := createSlashingGenesis(t, validator, reporter)
+	chain := newSlashingChain(t, genesis, validator)
+
+	first := createConflictingBlock(t, genesis, validator, 1)
+	second := createConflictingBlock(t, genesis, validator, 2)
+	if err := chain.AddBlock(first); err != nil {
+		t.Fatalf("Failed to add block: %v", err)
+	}
+
+	// Evidência serializada como seria recebida de outro nó, dentro de uma transação
+	data, err := NewSlashingEvidence(first, second).Serialize()
+	if err != nil {
+		t.Fatalf("Failed to serialize evidence: %v", err)
+	}
+	evidence, err := DeserializeSlashingEvidence(data)
+	if err != nil {
+		t.Fatalf("Failed to deserialize evidence: %v", err)
+	}
+
+	mineBlockWith(t, chain, validator, createEvidenceTransaction(t, chain, reporter, evidence))
+	slashBlock := chain.GetLastBlock()
+	if len(slashBlock.Transactions) != 2 {
+		t.Fatalf("Expected the evidence transaction in the block, got %d transactions", len(slashBlock.Transactions))
+	}
+
+	if stake := chain.GetStake(validator.GetAddress()); stake != 750 {
+		t.Errorf("Expected stake 750 after slashing 25%%, got %d", stake)
+	}
+	if height := chain.GetSlashHeight(validator.GetAddress()); height != 1 {
+		t.Errorf("Expected slash height 1, got %d", height)
+	}
+	if balance := chain.GetBalance(reporter.GetAddress()); balance != 90 {
+		t.Errorf("Reporter should pay only the fee, got balance %d", balance)
+	}
+
+	// A mesma altura não é punida duas vezes
+	duplicate := createEvidenceTransaction(t, chain, reporter, evidence)
+	if _, err := chain.context.ExecuteTransaction(duplicate); err == nil || !strings.Contains(err.Error(), "already slashed") {
+		t.Errorf("Duplicate evidence should be rejected, got %v", err)
+	}
+
+	// Outro nó que reexecuta os blocos chega ao mesmo stake
+	replay := newSlashingChain(t, genesis, validator)
+	if err := replay.AddBlocks([]*Block{first, slashBlock}); err != nil {
+		t.Fatalf("Failed to replay blocks: %v", err)
+	}
+	if stake := replay.GetStake(validator.GetAddress()); stake != 750 {
+		t.Errorf("Replayed chain should slash the validator, got stake %d", stake)
+	}
+
+	// Desfazer o bloco desfaz a punição
+	if err := chain.context.RollbackTo(first.Hash); err != nil {
+		t.Fatalf("Failed to roll back: %v", err)
+	}
+	if stake := chain.GetStake(validator.GetAddress()); stake != 1000 {
+		t.Errorf("Rollback should restore the stake, got %d", stake)
+	}
+	if height := chain.GetSlashHeight(validator.GetAddress()); height != 0 {
+		t.Errorf("Rollback should clear the slash height, got %d", height)
+	}
+}
+
+func TestDoubleSignEvidenceSlashesUnbondingAfterInfraction(t *testing.T) {
+	validator, _ := wallet.NewWallet()
+	reporter, _ := wallet.NewWallet()
+	genesis := createSlashingGenesis(t, validator, reporter)
+	chain := newSlashingChain(t, genesis, validator)
+	addr := validator.GetAddress()
+
+	first := createConflictingBlock(t, genesis, validator, 1)
+	second := createConflictingBlock(t, genesis, validator, 2)
+	if err := chain.AddBlock(first); err != nil {
+		t.Fatalf("Failed to add block: %v", err)
+	}
+
+	// O validador tenta fugir da punição retirando o stake logo depois da assinatura dupla
+	unstake, err := NewMiner(validator, chain, NewMempool()).CreateUnstakeTransaction(400, 1)
+	if err != nil {
+		t.Fatalf("Failed to create unstake: %v", err)
+	}
+	mineBlockWith(t, chain, validator, unstake)
+	if pending := chain.GetPendingUnbonding(addr); pending != 400 {
+		t.Fatalf("Expected 400 pending unbonding, got %d", pending)
+	}
+
+	// A evidência vale até um bloco antes da liberação do unbonding
+	config := chain.GetConfig()
+	if maxAge := config.EvidenceMaxAge(); maxAge != config.UnbondingPeriod-1 {
+		t.Fatalf("Expected evidence max age %d, got %d", config.UnbondingPeriod-1, maxAge)
+	}
+	for chain.GetHeight() < config.EvidenceMaxAge() {
+		mineBlockWith(t, chain, validator)
+	}
+
+	mineBlockWith(t, chain, validator, createEvidenceTransaction(t, chain, reporter, NewSlashingEvidence(first, second)))
+	if count := len(chain.GetLastBlock().Transactions); count != 2 {
+		t.Fatalf("Expected the evidence transaction in the block, got %d transactions", count)
+	}
+
+	if stake := chain.GetStake(addr); stake != 450 {
+		t.Errorf("Expected stake 450 after slashing 25%% of 600, got %d", stake)
+	}
+	if pending := chain.GetPendingUnbonding(addr); pending != 300 {
+		t.Errorf("Expected unbonding 300 after slashing 25%% of 400, got %d", pending)
+	}
+
+	// Só o que sobrou do unbonding vira saldo
+	balance := chain.GetBalance(addr)
+	for chain.GetPendingUnbonding(addr) > 0 {
+		balance += chain.CoinbaseReward(chain.GetHeight() + 1)
+		mineBlockWith(t, chain, validator)
+	}
+	if got := chain.GetBalance(addr); got != balance+300 {
+		t.Errorf("Expected balance %d after release, got %d", balance+300, got)
+	}
+
+	// Depois do prazo a evidência não entra mais em um bloco, mesmo sem ExpiryHeight na transação
+	evidenceData, err := NewDoubleSignEvidenceData(NewSlashingEvidence(first, second))
+	if err != nil {
+		t.Fatalf("Failed to create evidence data: %v", err)
+	}
+	dataStr, _ := evidenceData.Serialize()
+	late := NewTransaction(reporter.GetAddress(), reporter.GetAddress(), 0, 10, chain.GetNonce(reporter.GetAddress()), dataStr)
+	if err := late.Sign(reporter); err != nil {
+		t.Fatalf("Failed to sign evidence transaction: %v", err)
+	}
+	if _, err := chain.context.ExecuteTransaction(late); err == nil || !strings.Contains(err.Error(), "older than") {
+		t.Errorf("Evidence older than the unbonding period should be rejected, got %v", err)
+	}
+}
+
+func TestDoubleSignEvidenceRejectsForgery(t *testing.T) {
+	validator, _ := wallet.NewWallet()
+	reporter, _ := wallet.NewWallet()
+	attacker, _ := wallet.NewWallet()
+	genesis := createSlashingGenesis(t, validator, reporter)
+	chain := newSlashingChain(t, genesis, validator)
+
+	var events int
+	chain.SetOnDoubleSign(func(*SlashingEvidence) {
+		events++
+	})
+
+	first := createConflictingBlock(t, genesis, validator, 1)
+	if err := chain.AddBlock(first); err != nil {
+		t.Fatalf("Failed to add block: %v", err)
+	}
+
+	// Bloco atribuído ao validador mas assinado por outra carteira
+	forged := createConflictingBlock(t, genesis, attacker, 2)
+	forged.Header.ValidatorAddr = validator.GetAddress()
+	forged.Hash, _ = forged.CalculateHash()
+
+	if err := chain.AddBlock(forged); err == nil || strings.Contains(err.Error(), "double sign") {
+		t.Errorf("Forged block should be rejected without a double sign, got %v", err)
+	}
+	if events != 0 {
+		t.Errorf("Forged block should not be reported, got %d events", events)
+	}
+
+	tx := createEvidenceTransaction(t, chain, reporter, NewSlashingEvidence(first, forged))
+	if _, err := chain.context.ExecuteTransaction(tx); err == nil {
+		t.Error("Forged evidence should be rejected")
+	}
+
+	// O minerador descarta a denúncia inválida
+	mineBlockWith(t, chain, validator, tx)
+	if count := len(chain.GetLastBlock().Transactions); count != 1 {
+		t.Errorf("Forged evidence should not be mined, got %d transactions", count)
+	}
+	if stake := chain.GetStake(validator.GetAddress()); stake != 1000 {
+		t.Errorf("Validator should not be slashed by forged evidence, got stake %d", stake)
+	}
+}
+
+func TestMinerDoesNotSignTwiceAtHeight(t *testing.T) {
+	validator, _ := wallet.NewWallet()
+	heavy, _ := wallet.NewWallet()
+	chain := createStakedChain(t, map[string]uint64{
+		validator.GetAddress(): 10,
+		heavy.GetAddress():     1000,
+	})
+	genesis := chain.GetGenesis()
+
+	// O validador assina as alturas 1 e 2, depois um fork mais pesado substitui os dois blocos
+	if err := chain.AddBlocks(createForkBlocks(t, genesis, validator, 2, genesis.Header.Timestamp+1)); err != nil {
+		t.Fatalf("Failed to add blocks: %v", err)
+	}
+	result, err := chain.Reorganize(createForkBlocks(t, genesis, heavy, 1, genesis.Header.Timestamp+10))
+	if err != nil || !result.Reorganized {
+		t.Fatalf("Expected reorganization, got %+v err=%v", result, err)
+	}
+
+	// Minerar sobre o novo topo seria um segundo bloco do validador na altura 2
+	_, err = NewMiner(validator, chain, NewMempool()).CreateBlock()
+	if err == nil || !strings.Contains(err.Error(), "already signed") {
+		t.Errorf("Miner should refuse to sign height 2 again, got %v", err)
+	}
+}
+
+func TestSlashAmountUsesIntegerMath(t *testing.T) {
+	tests := []struct {
+		amount      uint64
+		basisPoints uint64
+		expected    uint64
+	}{
+		{1000, 2500, 250},
+		{999, 1000, 99},
+		{1000, 0, 0},
+		{1000, 20000, 1000},
+		// float64 arredondaria estes valores
+		{1<<53 + 1, 5000, 1 << 52},
+		{math.MaxUint64, 2500, math.MaxUint64 / 4},
+		{math.MaxUint64, 9999, math.MaxUint64 - math.MaxUint64/10000 - 1},
+	}
+
+	for _, tt := range tests {
+		if got := slashAmount(tt.amount, tt.basisPoints); got != tt.expected {
+			t.Errorf("slashAmount(%d, %d) = %d, want %d", tt.amount, tt.basisPoints, got, tt.expected)
+		}
+	}
+}
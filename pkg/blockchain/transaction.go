@@ -6,6 +6,9 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"runtime"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/krakovia/blockchain/pkg/wallet"
@@ -23,6 +26,11 @@ type Transaction struct {
 	PublicKey string    `json:"public_key"` // Chave pública do remetente
 	Nonce     uint64    `json:"nonce"`      // Nonce para prevenir replay attacks
 	Data      string    `json:"data"`       // Dados adicionais (opcional)
+
+	// Última altura em que a transação pode entrar em um bloco (0 = sem expiração).
+	// Omitido do JSON quando zero só para deixar a mensagem menor e compatível com nós que não
+	// conhecem o campo; o hash usa o formato binário de canonicalPayload, que sempre o inclui.
+	ExpiryHeight uint64 `json:"expiry_height,omitempty"`
 }
 
 // NewTransaction cria uma nova transação
@@ -39,21 +47,36 @@ func NewTransaction(from, to string, amount, fee, nonce uint64, data string) *Tr
 	return tx
 }
 
-// CalculateHash calcula o hash da transação (sem incluir a assinatura)
-func (tx *Transaction) CalculateHash() (string, error) {
-	// Cria uma cópia da transação sem assinatura e ID para calcular o hash
-	txCopy := Transaction{
-		From:      tx.From,
-		To:        tx.To,
-		Amount:    tx.Amount,
-		Fee:       tx.Fee,
-		Timestamp: tx.Timestamp,
-		PublicKey: tx.PublicKey,
-		Nonce:     tx.Nonce,
-		Data:      tx.Data,
-	}
+// canonicalPayload retorna os bytes canônicos do conteúdo da transação, no formato
+// BlockFormatVersion (ver payloadWriter), sem ID e sem assinatura (assinaturas ECDSA são
+// aleatórias). forID monta o preimage do ID: inclui a chave pública, que não entra nos dados
+// assinados, e escreve o timestamp zerado, já que ele vem do relógio de quem cria a transação
+// (From e Nonce já tornam o ID único). Nos dados assinados é o contrário: o timestamp entra e a
+// chave pública é escrita vazia.
+func (tx *Transaction) canonicalPayload(forID bool) ([]byte, error) {
+	publicKey, timestamp := "", tx.Timestamp
+	if forID {
+		publicKey, timestamp = tx.PublicKey, 0
+	}
+
+	w := newPayloadWriter()
+	w.string(tx.From)
+	w.string(tx.To)
+	w.uint64(tx.Amount)
+	w.uint64(tx.Fee)
+	w.int64(timestamp)
+	w.string(publicKey)
+	w.uint64(tx.Nonce)
+	w.string(tx.Data)
+	w.uint64(tx.ExpiryHeight)
+	return w.bytes(), nil
+}
 
-	data, err := json.Marshal(txCopy)
+// CalculateHash calcula o ID da transação: o SHA-256 do conteúdo mais a chave pública, sem o
+// timestamp (ver canonicalPayload). Não depende da assinatura nem do relógio, então todos os nós
+// calculam o mesmo ID para a mesma transação.
+func (tx *Transaction) CalculateHash() (string, error) {
+	data, err := tx.canonicalPayload(true)
 	if err != nil {
 		return "", fmt.Errorf("failed to marshal transaction: %w", err)
 	}
@@ -62,19 +85,26 @@ func (tx *Transaction) CalculateHash() (string, error) {
 	return hex.EncodeToString(hash[:]), nil
 }
 
-// GetSignData retorna os dados que devem ser assinados
-func (tx *Transaction) GetSignData() ([]byte, error) {
-	txCopy := Transaction{
-		From:      tx.From,
-		To:        tx.To,
-		Amount:    tx.Amount,
-		Fee:       tx.Fee,
-		Timestamp: tx.Timestamp,
-		Nonce:     tx.Nonce,
-		Data:      tx.Data,
+// VerifyID verifica se o ID informado é o derivado do conteúdo da transação
+func (tx *Transaction) VerifyID() error {
+	if tx.ID == "" {
+		return fmt.Errorf("transaction ID is empty")
+	}
+
+	calculatedHash, err := tx.CalculateHash()
+	if err != nil {
+		return err
 	}
+	if tx.ID != calculatedHash {
+		return fmt.Errorf("transaction hash mismatch: expected %s, got %s", calculatedHash, tx.ID)
+	}
+
+	return nil
+}
 
-	data, err := json.Marshal(txCopy)
+// GetSignData retorna os dados que devem ser assinados
+func (tx *Transaction) GetSignData() ([]byte, error) {
+	data, err := tx.canonicalPayload(false)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal transaction for signing: %w", err)
 	}
@@ -145,13 +175,9 @@ func (tx *Transaction) Verify() error {
 	}
 
 	// Verifica o hash da transação
-	calculatedHash, err := tx.CalculateHash()
-	if err != nil {
+	if err := tx.VerifyID(); err != nil {
 		return err
 	}
-	if tx.ID != calculatedHash {
-		return fmt.Errorf("transaction hash mismatch: expected %s, got %s", calculatedHash, tx.ID)
-	}
 
 	// Obtém os dados que foram assinados
 	signData, err := tx.GetSignData()
@@ -178,8 +204,17 @@ func (tx *Transaction) Validate() error {
 		return err
 	}
 
-	// Valida valores
-	if tx.Amount == 0 {
+	return tx.validateRules()
+}
+
+// validateRules valida as regras de negócio da transação, sem verificar a assinatura
+func (tx *Transaction) validateRules() error {
+	// Parse transaction data para verificar se é stake operation ou registro de nome
+	txData, _ := DeserializeTransactionData(tx.Data)
+
+	// Valida valores (registro de nome e denúncia de assinatura dupla não movimentam tokens,
+	// pagam apenas a fee)
+	if tx.Amount == 0 && !txData.IsRegisterName() && !txData.IsDoubleSignEvidence() {
 		return fmt.Errorf("transaction amount must be greater than 0")
 	}
 
@@ -189,13 +224,10 @@ func (tx *Transaction) Validate() error {
 		return fmt.Errorf("transaction timestamp is too far in the future")
 	}
 
-	// Parse transaction data para verificar se é stake operation
-	txData, _ := DeserializeTransactionData(tx.Data)
-
 	// Valida que remetente e destinatário são diferentes (exceto para operações de stake)
 	if tx.From == tx.To {
-		// Permite From == To apenas para stake/unstake
-		if txData == nil || !txData.IsStakeOperation() {
+		// Permite From == To apenas para stake/unstake, registro de nome e denúncias
+		if !txData.IsStakeOperation() && !txData.IsRegisterName() && !txData.IsDoubleSignEvidence() {
 			return fmt.Errorf("sender and receiver cannot be the same")
 		}
 	}
@@ -208,16 +240,26 @@ func (tx *Transaction) Serialize() ([]byte, error) {
 	return json.Marshal(tx)
 }
 
-// DeserializeTransaction desserializa uma transação de JSON
+// DeserializeTransaction desserializa uma transação de JSON (comprimido ou não)
 func DeserializeTransaction(data []byte) (*Transaction, error) {
+	data, err := DecompressPayload(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to deserialize transaction: %w", err)
+	}
+
 	var tx Transaction
-	err := json.Unmarshal(data, &tx)
+	err = json.Unmarshal(data, &tx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to deserialize transaction: %w", err)
 	}
 	return &tx, nil
 }
 
+// IsExpiredAt verifica se a transação não pode mais entrar em um bloco na altura informada
+func (tx *Transaction) IsExpiredAt(height uint64) bool {
+	return tx.ExpiryHeight > 0 && height > tx.ExpiryHeight
+}
+
 // IsCoinbase verifica se a transação é uma transação coinbase (recompensa de mineração)
 func (tx *Transaction) IsCoinbase() bool {
 	return tx.From == ""
@@ -261,9 +303,8 @@ func (tx *Transaction) VerifyCoinbase() error {
 		return fmt.Errorf("coinbase transaction to address is empty")
 	}
 
-	if tx.Amount == 0 {
-		return fmt.Errorf("coinbase transaction amount must be greater than 0")
-	}
+	// Amount zero é válido: depois dos halvings a recompensa chega a zero, mas o bloco
+	// continua começando pelo coinbase do validador
 
 	// Verifica o hash
 	calculatedHash, err := tx.CalculateHash()
@@ -298,6 +339,8 @@ func (tx *Transaction) Copy() *Transaction {
 		PublicKey: tx.PublicKey,
 		Nonce:     tx.Nonce,
 		Data:      tx.Data,
+
+		ExpiryHeight: tx.ExpiryHeight,
 	}
 }
 
@@ -314,35 +357,14 @@ func (tx *Transaction) Hash() []byte {
 // TransactionSlice é um slice de transações com métodos auxiliares
 type TransactionSlice []*Transaction
 
-// CalculateMerkleRoot calcula a raiz da árvore de Merkle das transações
+// CalculateMerkleRoot calcula a raiz da árvore de Merkle sobre os IDs das transações
 func (txs TransactionSlice) CalculateMerkleRoot() string {
 	if len(txs) == 0 {
 		return ""
 	}
 
-	// Coleta os hashes de todas as transações
-	var hashes [][]byte
-	for _, tx := range txs {
-		hashes = append(hashes, tx.Hash())
-	}
-
-	// Constrói a árvore de Merkle
-	for len(hashes) > 1 {
-		// Se o número de hashes for ímpar, duplica o último
-		if len(hashes)%2 != 0 {
-			hashes = append(hashes, hashes[len(hashes)-1])
-		}
-
-		var newLevel [][]byte
-		for i := 0; i < len(hashes); i += 2 {
-			combined := append(hashes[i], hashes[i+1]...)
-			hash := sha256.Sum256(combined)
-			newLevel = append(newLevel, hash[:])
-		}
-		hashes = newLevel
-	}
-
-	return hex.EncodeToString(hashes[0])
+	levels := txs.merkleLevels()
+	return hex.EncodeToString(levels[len(levels)-1][0])
 }
 
 // TotalAmount retorna a soma total de valores das transações
@@ -373,6 +395,16 @@ func (txs TransactionSlice) ContainsID(id string) bool {
 	return false
 }
 
+// FindByID retorna a transação com o ID especificado, ou nil se não existir no slice
+func (txs TransactionSlice) FindByID(id string) *Transaction {
+	for _, tx := range txs {
+		if tx.ID == id {
+			return tx
+		}
+	}
+	return nil
+}
+
 // Filter filtra transações usando uma função predicado
 func (txs TransactionSlice) Filter(predicate func(*Transaction) bool) TransactionSlice {
 	var result TransactionSlice
@@ -394,6 +426,77 @@ func (txs TransactionSlice) Validate() error {
 	return nil
 }
 
+// TransactionError erro de uma transação de um slice: identifica a transação inválida
+type TransactionError struct {
+	Index int // Posição da transação no slice
+	Err   error
+}
+
+// Error implementa a interface error
+func (e *TransactionError) Error() string {
+	return fmt.Sprintf("transaction %d: %v", e.Index, e.Err)
+}
+
+// Unwrap retorna o erro da transação
+func (e *TransactionError) Unwrap() error {
+	return e.Err
+}
+
+// VerifySignatures verifica as assinaturas das transações (Transaction.Verify) em paralelo, com
+// até GOMAXPROCS workers. Coinbases não têm assinatura e são ignoradas. Na primeira falha os
+// workers param de pegar transações novas, e o erro retornado (*TransactionError) é sempre o da
+// transação inválida de menor índice.
+func (txs TransactionSlice) VerifySignatures() error {
+	workers := runtime.GOMAXPROCS(0)
+	if workers > len(txs) {
+		workers = len(txs)
+	}
+
+	var (
+		next     atomic.Int64 // Próximo índice a verificar
+		failed   atomic.Bool
+		mu       sync.Mutex
+		firstErr *TransactionError
+		wg       sync.WaitGroup
+	)
+
+	// Os índices são distribuídos em ordem e failed é consultado antes de pegar um índice, então
+	// todo índice menor que o de uma falha já foi pego e é verificado até o fim
+	worker := func() {
+		defer wg.Done()
+		for !failed.Load() {
+			i := int(next.Add(1) - 1)
+			if i >= len(txs) {
+				return
+			}
+			tx := txs[i]
+			if tx.IsCoinbase() {
+				continue
+			}
+			if err := tx.Verify(); err != nil {
+				mu.Lock()
+				if firstErr == nil || i < firstErr.Index {
+					firstErr = &TransactionError{Index: i, Err: err}
+				}
+				mu.Unlock()
+				failed.Store(true)
+				return
+			}
+		}
+	}
+
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go worker()
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+	return nil
+}
+
 // HasDuplicates verifica se há transações duplicadas no slice
 func (txs TransactionSlice) HasDuplicates() bool {
 	seen := make(map[string]bool)
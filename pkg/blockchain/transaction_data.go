@@ -3,6 +3,7 @@ package blockchain
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 )
 
 // TransactionType define os tipos de transação suportados
@@ -13,6 +14,16 @@ const (
 	TransactionTypeStake    TransactionType = "stake"    // Depositar tokens em stake
 	TransactionTypeUnstake  TransactionType = "unstake"  // Sacar tokens do stake
 	TransactionTypeData     TransactionType = "data"     // Dados arbitrários (para futuro)
+
+	TransactionTypeRegisterName TransactionType = "register_name" // Registrar nome de exibição do endereço
+
+	TransactionTypeDoubleSignEvidence TransactionType = "double_sign_evidence" // Denunciar um validador que assinou dois blocos na mesma altura
+)
+
+// Limites para nomes de exibição de validadores
+const (
+	MinValidatorNameLength = 3
+	MaxValidatorNameLength = 32
 )
 
 // TransactionData representa os dados específicos de uma transação
@@ -49,6 +60,31 @@ func NewUnstakeData(amount uint64) *TransactionData {
 	}
 }
 
+// NewRegisterNameData cria dados para uma transação de registro de nome
+func NewRegisterNameData(name string) *TransactionData {
+	return &TransactionData{
+		Type: TransactionTypeRegisterName,
+		Payload: map[string]interface{}{
+			"name": name,
+		},
+	}
+}
+
+// NewDoubleSignEvidenceData cria dados para uma transação que denuncia uma assinatura dupla.
+// A evidência vai serializada no payload e é verificada por todos os nós ao executar o bloco.
+func NewDoubleSignEvidenceData(evidence *SlashingEvidence) (*TransactionData, error) {
+	data, err := evidence.Serialize()
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize double sign evidence: %w", err)
+	}
+	return &TransactionData{
+		Type: TransactionTypeDoubleSignEvidence,
+		Payload: map[string]interface{}{
+			"evidence": string(data),
+		},
+	}, nil
+}
+
 // NewCustomData cria dados customizados (para extensibilidade futura)
 func NewCustomData(dataType string, payload map[string]interface{}) *TransactionData {
 	return &TransactionData{
@@ -176,6 +212,20 @@ func (td *TransactionData) Validate() error {
 		}
 		return nil
 
+	case TransactionTypeRegisterName:
+		name, ok := td.GetString("name")
+		if !ok {
+			return fmt.Errorf("register name transaction missing name in payload")
+		}
+		return ValidateValidatorName(name)
+
+	case TransactionTypeDoubleSignEvidence:
+		evidence, err := td.GetDoubleSignEvidence()
+		if err != nil {
+			return err
+		}
+		return evidence.Verify()
+
 	case TransactionTypeData:
 		// Dados arbitrários - sem validação específica
 		return nil
@@ -194,6 +244,55 @@ func (td *TransactionData) IsStakeOperation() bool {
 	return td.Type == TransactionTypeStake || td.Type == TransactionTypeUnstake
 }
 
+// IsRegisterName verifica se é um registro de nome
+func (td *TransactionData) IsRegisterName() bool {
+	if td == nil {
+		return false
+	}
+	return td.Type == TransactionTypeRegisterName
+}
+
+// IsDoubleSignEvidence verifica se é uma denúncia de assinatura dupla
+func (td *TransactionData) IsDoubleSignEvidence() bool {
+	if td == nil {
+		return false
+	}
+	return td.Type == TransactionTypeDoubleSignEvidence
+}
+
+// GetDoubleSignEvidence retorna a evidência de uma denúncia de assinatura dupla
+func (td *TransactionData) GetDoubleSignEvidence() (*SlashingEvidence, error) {
+	if !td.IsDoubleSignEvidence() {
+		return nil, fmt.Errorf("not a double sign evidence")
+	}
+
+	data, ok := td.GetString("evidence")
+	if !ok {
+		return nil, fmt.Errorf("double sign evidence transaction missing evidence in payload")
+	}
+	return DeserializeSlashingEvidence([]byte(data))
+}
+
+// ValidateValidatorName valida o formato de um nome de exibição: entre
+// MinValidatorNameLength e MaxValidatorNameLength caracteres, apenas letras,
+// números, espaço, '.', '-' e '_', sem espaços nas pontas
+func ValidateValidatorName(name string) error {
+	if len(name) < MinValidatorNameLength || len(name) > MaxValidatorNameLength {
+		return fmt.Errorf("name must be between %d and %d characters, got %d", MinValidatorNameLength, MaxValidatorNameLength, len(name))
+	}
+	if strings.TrimSpace(name) != name {
+		return fmt.Errorf("name cannot start or end with spaces")
+	}
+	for _, r := range name {
+		isLetter := (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+		isDigit := r >= '0' && r <= '9'
+		if !isLetter && !isDigit && r != ' ' && r != '.' && r != '-' && r != '_' {
+			return fmt.Errorf("name contains invalid character %q", r)
+		}
+	}
+	return nil
+}
+
 // GetStakeAmount retorna o amount de uma operação de stake/unstake
 func (td *TransactionData) GetStakeAmount() (uint64, error) {
 	if !td.IsStakeOperation() {
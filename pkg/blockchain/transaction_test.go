@@ -1,6 +1,8 @@
 package blockchain
 
 import (
+	"errors"
+	"strings"
 	"testing"
 	"time"
 
@@ -124,6 +126,73 @@ func TestTransactionCalculateHash(t *testing.T) {
 	}
 }
 
+func TestTransactionIDIsDeterministicAcrossConstructions(t *testing.T) {
+	w, _ := wallet.NewWallet()
+
+	build := func() *Transaction {
+		tx := NewTransaction(w.GetAddress(), "recipient_addr", 100, 1, 3, "payment")
+		if err := tx.Sign(w); err != nil {
+			t.Fatalf("Failed to sign transaction: %v", err)
+		}
+		return tx
+	}
+
+	// Assinaturas ECDSA são aleatórias e o timestamp vem do relógio, mas nenhum dos dois entra no ID
+	first, second := build(), build()
+	if first.ID != second.ID {
+		t.Fatalf("Same content should yield the same ID: %s != %s", first.ID, second.ID)
+	}
+	later := NewTransaction(w.GetAddress(), "recipient_addr", 100, 1, 3, "payment")
+	later.Timestamp = first.Timestamp + 60
+	if err := later.Sign(w); err != nil {
+		t.Fatalf("Failed to sign transaction: %v", err)
+	}
+	if later.ID != first.ID {
+		t.Errorf("Transaction built at another time should keep the ID: %s != %s", later.ID, first.ID)
+	}
+
+	// O timestamp continua coberto pela assinatura
+	tampered := *first
+	tampered.Timestamp++
+	if err := tampered.Verify(); err == nil {
+		t.Error("Verify should reject a transaction with a changed timestamp")
+	}
+
+	// Outro nó recebe a transação serializada e chega ao mesmo ID
+	data, err := first.Serialize()
+	if err != nil {
+		t.Fatalf("Failed to serialize transaction: %v", err)
+	}
+	received, err := DeserializeTransaction(data)
+	if err != nil {
+		t.Fatalf("Failed to deserialize transaction: %v", err)
+	}
+	if err := received.VerifyID(); err != nil || received.ID != second.ID {
+		t.Errorf("Received transaction should verify with the same ID: %v", err)
+	}
+
+	// ID atribuído por fora é rejeitado no recebimento
+	received.ID = second.ID[:len(second.ID)-1] + "0"
+	if received.ID == second.ID {
+		received.ID = second.ID[:len(second.ID)-1] + "1"
+	}
+	if err := received.VerifyID(); err == nil {
+		t.Error("Transaction with a forged ID should fail verification")
+	}
+	if err := received.Verify(); err == nil {
+		t.Error("Verify should reject a forged ID")
+	}
+
+	// Conteúdo diferente gera ID diferente
+	other := NewTransaction(w.GetAddress(), "recipient_addr", 101, 1, 3, "payment")
+	if err := other.Sign(w); err != nil {
+		t.Fatalf("Failed to sign transaction: %v", err)
+	}
+	if other.ID == first.ID {
+		t.Error("Different content should yield a different ID")
+	}
+}
+
 func TestTransactionValidate(t *testing.T) {
 	w, _ := wallet.NewWallet()
 	tx := NewTransaction(w.GetAddress(), "recipient_addr", 100, 1, 0, "payment")
@@ -229,12 +298,22 @@ func TestVerifyCoinbase(t *testing.T) {
 	}
 }
 
-func TestVerifyCoinbaseInvalidAmount(t *testing.T) {
+func TestVerifyCoinbaseZeroAmount(t *testing.T) {
+	// Recompensa zero (após os halvings) continua sendo um coinbase válido
 	tx := NewCoinbaseTransaction("miner_addr", 0, 1)
 
+	err := tx.VerifyCoinbase()
+	if err != nil {
+		t.Errorf("Zero-reward coinbase should be valid: %v", err)
+	}
+}
+
+func TestVerifyCoinbaseMissingRecipient(t *testing.T) {
+	tx := NewCoinbaseTransaction("", 50, 1)
+
 	err := tx.VerifyCoinbase()
 	if err == nil {
-		t.Error("Expected coinbase verification to fail for zero amount")
+		t.Error("Expected coinbase verification to fail without recipient")
 	}
 }
 
@@ -436,6 +515,66 @@ func TestTransactionSliceValidate(t *testing.T) {
 	}
 }
 
+// signedTransactions cria n transações assinadas pela mesma carteira
+func signedTransactions(tb testing.TB, n int) TransactionSlice {
+	tb.Helper()
+	w, _ := wallet.NewWallet()
+
+	txs := make(TransactionSlice, 0, n)
+	for i := 0; i < n; i++ {
+		tx := NewTransaction(w.GetAddress(), "recipient", 100, 1, uint64(i), "payment")
+		if err := tx.Sign(w); err != nil {
+			tb.Fatalf("Failed to sign transaction: %v", err)
+		}
+		txs = append(txs, tx)
+	}
+	return txs
+}
+
+func TestTransactionSliceVerifySignatures(t *testing.T) {
+	txs := signedTransactions(t, 50)
+	// Coinbase não tem assinatura e não deve ser rejeitado
+	txs = append(TransactionSlice{NewCoinbaseTransaction("validator", 50, 1)}, txs...)
+
+	if err := txs.VerifySignatures(); err != nil {
+		t.Fatalf("Expected valid signatures, got: %v", err)
+	}
+	if err := (TransactionSlice{}).VerifySignatures(); err != nil {
+		t.Errorf("Expected empty slice to verify, got: %v", err)
+	}
+
+	// Troca a assinatura de duas transações pela de outra: o erro aponta a de menor índice
+	txs[40].Signature = txs[1].Signature
+	txs[12].Signature = txs[1].Signature
+
+	err := txs.VerifySignatures()
+	var txErr *TransactionError
+	if !errors.As(err, &txErr) {
+		t.Fatalf("Expected *TransactionError, got: %v", err)
+	}
+	if txErr.Index != 12 {
+		t.Errorf("Expected failure at index 12, got %d", txErr.Index)
+	}
+}
+
+func TestBlockWithTamperedSignatureFailsValidation(t *testing.T) {
+	txs := signedTransactions(t, 20)
+	txs = append(TransactionSlice{NewCoinbaseTransaction(txs[0].From, 50, 1)}, txs...)
+	txs[7].Signature = txs[3].Signature
+
+	block := NewBlock(1, "prev_hash", txs, txs[0].To)
+	hash, _ := block.CalculateHash()
+	block.Hash = hash
+
+	err := block.Validate()
+	if err == nil {
+		t.Fatal("Expected block with a tampered signature to fail validation")
+	}
+	if !strings.Contains(err.Error(), "invalid transaction at index 7") {
+		t.Errorf("Expected error to point at index 7, got: %v", err)
+	}
+}
+
 func BenchmarkTransactionSign(b *testing.B) {
 	w, _ := wallet.NewWallet()
 
@@ -483,3 +622,22 @@ func BenchmarkMerkleRoot(b *testing.B) {
 		txs.CalculateMerkleRoot()
 	}
 }
+
+// BenchmarkVerifySignatures compara a verificação serial com a paralela em um bloco cheio
+func BenchmarkVerifySignatures(b *testing.B) {
+	txs := signedTransactions(b, 500)
+
+	b.Run("serial", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for _, tx := range txs {
+				_ = tx.Verify()
+			}
+		}
+	})
+
+	b.Run("parallel", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_ = txs.VerifySignatures()
+		}
+	})
+}
@@ -7,12 +7,15 @@ import (
 	"fmt"
 	"math/big"
 	"sort"
+
+	"github.com/krakovia/blockchain/pkg/consensus"
 )
 
 // Validator representa um validador com seu endereço e stake
 type Validator struct {
 	Address string // Endereço do validador
 	Stake   uint64 // Quantidade de tokens em stake
+	Name    string // Nome de exibição registrado (opcional)
 }
 
 // ValidatorList é uma lista de validadores
@@ -50,6 +53,15 @@ func (vl ValidatorList) ValidateList() error {
 	return nil
 }
 
+// Stakes retorna o stake de cada validador da lista, pelo endereço
+func (vl ValidatorList) Stakes() map[string]uint64 {
+	stakes := make(map[string]uint64, len(vl))
+	for _, v := range vl {
+		stakes[v.Address] = v.Stake
+	}
+	return stakes
+}
+
 // GetValidator retorna um validador pelo endereço
 func (vl ValidatorList) GetValidator(address string) *Validator {
 	for i := range vl {
@@ -99,26 +111,19 @@ func CalculateValidatorPriority(previousBlockHash string, validators ValidatorLi
 	scores := make([]validatorScore, len(validators))
 
 	for i, validator := range validators {
-		// Combina hash do bloco anterior com endereço do validador
-		data := previousBlockHash + validator.Address
-		hash := sha256.Sum256([]byte(data))
-
-		// Converte hash para big.Int (256 bits)
-		hashInt := new(big.Int).SetBytes(hash[:])
-
-		// Multiplica pelo stake para dar peso proporcional
-		stake := new(big.Int).SetUint64(validator.Stake)
-		score := new(big.Int).Mul(hashInt, stake)
-
+		// Hash(previousBlockHash + endereço) × stake, o mesmo score de consensus.SelectValidator
 		scores[i] = validatorScore{
 			validator: validator,
-			score:     score,
+			score:     consensus.Score(previousBlockHash, validator.Address, validator.Stake),
 		}
 	}
 
-	// Ordena por score (maior primeiro)
+	// Ordena por score (maior primeiro; em empate, menor endereço)
 	sort.Slice(scores, func(i, j int) bool {
-		return scores[i].score.Cmp(scores[j].score) > 0
+		if cmp := scores[i].score.Cmp(scores[j].score); cmp != 0 {
+			return cmp > 0
+		}
+		return scores[i].validator.Address < scores[j].validator.Address
 	})
 
 	// Cria a fila de prioridade
@@ -336,6 +341,63 @@ func GetExpectedProbability(validator Validator, totalStake uint64) float64 {
 	return float64(validator.Stake) / float64(totalStake)
 }
 
+// GetSelectionProbability retorna a probabilidade exata de um validador ficar no topo
+// da fila de prioridade (CalculateValidatorPriority).
+//
+// O score de cada validador é U * stake, com U uniforme em [0, 1) derivado do hash.
+// O validador i vence quando todos os outros têm score menor, logo:
+//
+//	P(i) = ∫₀¹ Π_{j≠i} min(1, u * stake_i / stake_j) du
+//
+// A integral é calculada por partes entre os pontos u = stake_j / stake_i, onde o
+// integrando é um monômio. Note que o resultado favorece stakes grandes em relação
+// à proporção simples de GetExpectedProbability.
+func GetSelectionProbability(address string, validators ValidatorList) float64 {
+	target := validators.GetValidator(address)
+	if target == nil || target.Stake == 0 {
+		return 0
+	}
+
+	// Razões stake_j / stake_i dos demais validadores com stake
+	ratios := make([]float64, 0, len(validators))
+	for _, v := range validators {
+		if v.Address == address || v.Stake == 0 {
+			continue
+		}
+		ratios = append(ratios, float64(v.Stake)/float64(target.Stake))
+	}
+	sort.Float64s(ratios)
+
+	// Em cada trecho [a, b], os fatores com razão r > u valem u/r e os demais valem 1.
+	// ∫ₐᵇ Π(u/r) du = (b·Π(b/r) - a·Π(a/r)) / (k+1), com cada fator ≤ 1 (sem overflow).
+	segmentIntegral := func(a, b float64, active []float64) float64 {
+		upper, lower := b, a
+		for _, r := range active {
+			upper *= b / r
+			lower *= a / r
+		}
+		return (upper - lower) / float64(len(active)+1)
+	}
+
+	probability := 0.0
+	start := 0.0
+	for i := 0; i <= len(ratios); i++ {
+		end := 1.0
+		if i < len(ratios) && ratios[i] < 1 {
+			end = ratios[i]
+		}
+		if end > start {
+			probability += segmentIntegral(start, end, ratios[i:])
+			start = end
+		}
+		if end >= 1 {
+			break
+		}
+	}
+
+	return probability
+}
+
 // ValidatorSet representa um conjunto de validadores com métodos auxiliares
 type ValidatorSet struct {
 	Validators ValidatorList
@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"math"
 	"testing"
+
+	"github.com/krakovia/blockchain/pkg/consensus"
 )
 
 func TestValidatorListTotalStake(t *testing.T) {
@@ -131,6 +133,30 @@ func TestCalculateValidatorPriorityDeterminism(t *testing.T) {
 	}
 }
 
+// TestCalculateValidatorPriorityMatchesSelectValidator testa que o topo da fila de prioridade
+// é o validador escolhido por consensus.SelectValidator (o mesmo usado pelo minerador)
+func TestCalculateValidatorPriorityMatchesSelectValidator(t *testing.T) {
+	validators := ValidatorList{
+		{Address: "validator1", Stake: 100},
+		{Address: "validator2", Stake: 200},
+		{Address: "validator3", Stake: 300},
+		{Address: "validator4", Stake: 300},
+	}
+
+	for i := 0; i < 1000; i++ {
+		hash := sha256.Sum256([]byte(fmt.Sprintf("block-%d", i)))
+		prevHash := hex.EncodeToString(hash[:])
+
+		pq, err := CalculateValidatorPriority(prevHash, validators)
+		if err != nil {
+			t.Fatalf("Failed to calculate priority: %v", err)
+		}
+		if selected := consensus.SelectValidator(uint64(i+1), prevHash, validators.Stakes()); selected != pq.GetTopValidator().Address {
+			t.Fatalf("Hash %s: SelectValidator chose %s, priority queue top is %s", prevHash, selected, pq.GetTopValidator().Address)
+		}
+	}
+}
+
 func TestCalculateValidatorPriorityDifferentHashes(t *testing.T) {
 	validators := ValidatorList{
 		{Address: "validator1", Stake: 100},
@@ -571,3 +597,60 @@ func BenchmarkWeightedRandomSelection(b *testing.B) {
 		_, _ = WeightedRandomSelection(hash, validators)
 	}
 }
+
+func TestGetSelectionProbability(t *testing.T) {
+	validators := ValidatorList{
+		{Address: "big", Stake: 500},
+		{Address: "mid", Stake: 300},
+		{Address: "small", Stake: 150},
+		{Address: "tiny", Stake: 50},
+	}
+
+	iterations := 20000
+	distribution, err := SimulateSelectionDistribution(validators, iterations)
+	if err != nil {
+		t.Fatalf("Failed to simulate distribution: %v", err)
+	}
+
+	total := 0.0
+	for _, v := range validators {
+		probability := GetSelectionProbability(v.Address, validators)
+		simulated := float64(distribution[v.Address]) / float64(iterations)
+		t.Logf("Validator %s: exact %.4f, simulated %.4f, stake share %.4f",
+			v.Address, probability, simulated, GetExpectedProbability(v, validators.TotalStake()))
+
+		// A probabilidade exata deve bater com a seleção real
+		if math.Abs(probability-simulated) > 0.02 {
+			t.Errorf("Validator %s: exact probability %.4f too far from simulated %.4f", v.Address, probability, simulated)
+		}
+		total += probability
+	}
+
+	if math.Abs(total-1) > 1e-9 {
+		t.Errorf("Probabilities should sum to 1, got %.12f", total)
+	}
+}
+
+func TestGetSelectionProbabilityEdgeCases(t *testing.T) {
+	equal := ValidatorList{
+		{Address: "a", Stake: 100},
+		{Address: "b", Stake: 100},
+		{Address: "c", Stake: 100},
+	}
+	if p := GetSelectionProbability("a", equal); math.Abs(p-1.0/3) > 1e-9 {
+		t.Errorf("Equal stakes should give 1/3, got %.6f", p)
+	}
+
+	// 10% contra 90%: o menor vence com probabilidade (10/90)/2
+	pair := ValidatorList{{Address: "small", Stake: 10}, {Address: "large", Stake: 90}}
+	if p := GetSelectionProbability("small", pair); math.Abs(p-1.0/18) > 1e-9 {
+		t.Errorf("Expected 1/18, got %.6f", p)
+	}
+
+	if p := GetSelectionProbability("missing", equal); p != 0 {
+		t.Errorf("Unknown validator should have probability 0, got %.6f", p)
+	}
+	if p := GetSelectionProbability("a", ValidatorList{{Address: "a", Stake: 100}}); p != 1 {
+		t.Errorf("Single validator should have probability 1, got %.6f", p)
+	}
+}
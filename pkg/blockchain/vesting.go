@@ -0,0 +1,35 @@
+package blockchain
+
+// VestingSchedule bloqueio de parte do saldo de um endereço do gênesis. Nada é liberado antes
+// de CliffHeight; a partir dela o valor é liberado linearmente ao longo de VestingBlocks blocos
+// (0 = tudo na altura do cliff).
+type VestingSchedule struct {
+	Address       string // Endereço com saldo bloqueado
+	Amount        uint64 // Valor total bloqueado no gênesis
+	CliffHeight   uint64 // Altura até a qual nada é liberado
+	VestingBlocks uint64 // Blocos de liberação linear após o cliff
+}
+
+// ReleasedAt retorna quanto do valor bloqueado já foi liberado na altura informada
+func (v VestingSchedule) ReleasedAt(height uint64) uint64 {
+	if height < v.CliffHeight {
+		return 0
+	}
+	elapsed := height - v.CliffHeight
+	if v.VestingBlocks == 0 || elapsed >= v.VestingBlocks {
+		return v.Amount
+	}
+	if elapsed == 0 {
+		return 0
+	}
+	// Com valores grandes, divide antes de multiplicar para não estourar uint64
+	if v.Amount > ^uint64(0)/elapsed {
+		return v.Amount / v.VestingBlocks * elapsed
+	}
+	return v.Amount * elapsed / v.VestingBlocks
+}
+
+// LockedAt retorna quanto do valor ainda está bloqueado na altura informada
+func (v VestingSchedule) LockedAt(height uint64) uint64 {
+	return v.Amount - v.ReleasedAt(height)
+}
@@ -0,0 +1,51 @@
+// Package consensus contém as regras puras do proof of stake: funções deterministas, sem
+// estado da chain, que qualquer nó (ou auditor externo) pode reexecutar com os mesmos dados.
+package consensus
+
+import (
+	"crypto/sha256"
+	"math/big"
+)
+
+// SelectValidator retorna o validador prioritário para produzir o bloco da altura height sobre
+// o bloco de hash prevHash, dados os stakes dos validadores ativos.
+//
+// Cada validador recebe o score sha256(prevHash + endereço) × stake e vence o maior score (em
+// empate, o menor endereço). É a mesma ordem de blockchain.CalculateValidatorPriority. O
+// sorteio depende só do hash anterior e dos stakes; height não entra no score (o hash anterior
+// já fixa a altura) e serve para recusar a altura 0, que não tem validador. Como o score
+// multiplica um valor uniforme pelo stake, stakes maiores vencem um pouco mais do que a
+// proporção simples do stake (ver blockchain.GetSelectionProbability).
+//
+// Retorna "" se prevHash for vazio ou nenhum validador tiver stake.
+func SelectValidator(height uint64, prevHash string, stakes map[string]uint64) string {
+	if height == 0 || prevHash == "" {
+		return ""
+	}
+
+	var selected string
+	var best *big.Int
+	for address, stake := range stakes {
+		if stake == 0 || address == "" {
+			continue
+		}
+
+		score := Score(prevHash, address, stake)
+		if best == nil {
+			selected, best = address, score
+			continue
+		}
+		if cmp := score.Cmp(best); cmp > 0 || (cmp == 0 && address < selected) {
+			selected, best = address, score
+		}
+	}
+
+	return selected
+}
+
+// Score calcula o score de prioridade de um validador sobre o bloco de hash prevHash
+func Score(prevHash, address string, stake uint64) *big.Int {
+	hash := sha256.Sum256([]byte(prevHash + address))
+	score := new(big.Int).SetBytes(hash[:])
+	return score.Mul(score, new(big.Int).SetUint64(stake))
+}
@@ -0,0 +1,109 @@
+package consensus
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"testing"
+)
+
+// Helper: hash de bloco anterior distinto para cada altura simulada
+func simulatedHash(i int) string {
+	hash := sha256.Sum256([]byte(fmt.Sprintf("block-%d", i)))
+	return hex.EncodeToString(hash[:])
+}
+
+// Helper: quantas vezes cada validador é escolhido em heights alturas simuladas
+func selectionCounts(t *testing.T, stakes map[string]uint64, heights int) map[string]int {
+	t.Helper()
+	counts := make(map[string]int)
+	for i := 1; i <= heights; i++ {
+		selected := SelectValidator(uint64(i), simulatedHash(i), stakes)
+		if selected == "" {
+			t.Fatalf("No validator selected at height %d", i)
+		}
+		counts[selected]++
+	}
+	return counts
+}
+
+func TestSelectValidatorDeterministic(t *testing.T) {
+	stakes := map[string]uint64{"alice": 1000, "bob": 2500, "carol": 400, "dave": 1000}
+
+	for i := 1; i <= 50; i++ {
+		hash := simulatedHash(i)
+		expected := SelectValidator(uint64(i), hash, stakes)
+
+		// Mesmas entradas (inclusive um mapa novo, com outra ordem de iteração) dão o mesmo validador
+		for attempt := 0; attempt < 20; attempt++ {
+			copied := make(map[string]uint64, len(stakes))
+			for address, stake := range stakes {
+				copied[address] = stake
+			}
+			if selected := SelectValidator(uint64(i), hash, copied); selected != expected {
+				t.Fatalf("Height %d: selection changed from %s to %s with the same inputs", i, expected, selected)
+			}
+		}
+	}
+
+	// O hash anterior muda o sorteio
+	seen := make(map[string]bool)
+	for i := 1; i <= 50; i++ {
+		seen[SelectValidator(uint64(i), simulatedHash(i), stakes)] = true
+	}
+	if len(seen) < 2 {
+		t.Errorf("Different previous hashes should select different validators, got only %v", seen)
+	}
+}
+
+func TestSelectValidatorInvalidInput(t *testing.T) {
+	stakes := map[string]uint64{"alice": 1000}
+
+	if selected := SelectValidator(0, simulatedHash(0), stakes); selected != "" {
+		t.Errorf("Genesis height should have no validator, got %s", selected)
+	}
+	if selected := SelectValidator(1, "", stakes); selected != "" {
+		t.Errorf("Empty previous hash should select no validator, got %s", selected)
+	}
+	if selected := SelectValidator(1, simulatedHash(1), map[string]uint64{}); selected != "" {
+		t.Errorf("No validators should select no one, got %s", selected)
+	}
+
+	// Stake zero nunca é escolhido
+	withZero := map[string]uint64{"alice": 1000, "idle": 0}
+	for i := 1; i <= 200; i++ {
+		if selected := SelectValidator(uint64(i), simulatedHash(i), withZero); selected != "alice" {
+			t.Fatalf("Height %d: expected alice, got %q", i, selected)
+		}
+	}
+}
+
+func TestSelectValidatorFrequencyFollowsStake(t *testing.T) {
+	const heights = 20000
+
+	// Stakes iguais: cada um é escolhido em ~1/4 das alturas
+	equal := map[string]uint64{"a": 1000, "b": 1000, "c": 1000, "d": 1000}
+	for address, count := range selectionCounts(t, equal, heights) {
+		if share := float64(count) / heights; math.Abs(share-0.25) > 0.02 {
+			t.Errorf("Equal stakes: %s selected in %.3f of heights, want ~0.25", address, share)
+		}
+	}
+
+	// Stake 1:3: o menor vence quando u_small·1 > u_large·3, ou seja, com probabilidade
+	// 1/6 (score uniforme × stake favorece um pouco o maior em relação a 1/4)
+	counts := selectionCounts(t, map[string]uint64{"small": 1000, "large": 3000}, heights)
+	if share := float64(counts["small"]) / heights; math.Abs(share-1.0/6) > 0.02 {
+		t.Errorf("Stake 1:3: small validator selected in %.3f of heights, want ~%.3f", share, 1.0/6)
+	}
+
+	// Mais stake, mais blocos
+	counts = selectionCounts(t, map[string]uint64{"s10": 10, "s20": 20, "s30": 30, "s40": 40}, heights)
+	order := []string{"s10", "s20", "s30", "s40"}
+	for i := 1; i < len(order); i++ {
+		if counts[order[i]] <= counts[order[i-1]] {
+			t.Errorf("Selections should grow with stake, got %v", counts)
+			break
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
+
+// ContentType é o content type do formato texto de exposição do Prometheus
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// Tipos de métrica do formato de exposição
+const (
+	typeCounter = "counter"
+	typeGauge   = "gauge"
+)
+
+// metric é uma métrica registrada, com o valor lido no momento da coleta
+type metric struct {
+	name  string
+	help  string
+	kind  string
+	value func() float64
+}
+
+// Registry guarda as métricas de um nó e as expõe no formato texto do Prometheus
+type Registry struct {
+	mu      sync.RWMutex
+	metrics []metric
+	names   map[string]bool
+}
+
+// Counter contador monotônico, incrementado quando o evento acontece
+type Counter struct {
+	value atomic.Uint64
+}
+
+// NewRegistry cria um registro vazio
+func NewRegistry() *Registry {
+	return &Registry{names: make(map[string]bool)}
+}
+
+// NewCounter registra um contador. Nomes de contadores devem terminar em _total.
+func (r *Registry) NewCounter(name, help string) *Counter {
+	c := &Counter{}
+	r.register(metric{name: name, help: help, kind: typeCounter, value: func() float64 {
+		return float64(c.Value())
+	}})
+	return c
+}
+
+// NewGaugeFunc registra um gauge cujo valor é lido de fn a cada coleta
+func (r *Registry) NewGaugeFunc(name, help string, fn func() float64) {
+	r.register(metric{name: name, help: help, kind: typeGauge, value: fn})
+}
+
+// register adiciona uma métrica; registrar o mesmo nome duas vezes é erro de programação
+func (r *Registry) register(m metric) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.names[m.name] {
+		panic(fmt.Sprintf("metric %s already registered", m.name))
+	}
+	r.names[m.name] = true
+	r.metrics = append(r.metrics, m)
+}
+
+// WriteText escreve todas as métricas no formato texto do Prometheus
+func (r *Registry) WriteText(w io.Writer) error {
+	r.mu.RLock()
+	metrics := make([]metric, len(r.metrics))
+	copy(metrics, r.metrics)
+	r.mu.RUnlock()
+
+	bw := bufio.NewWriter(w)
+	for _, m := range metrics {
+		fmt.Fprintf(bw, "# HELP %s %s\n", m.name, m.help)
+		fmt.Fprintf(bw, "# TYPE %s %s\n", m.name, m.kind)
+		fmt.Fprintf(bw, "%s %s\n", m.name, formatValue(m.value()))
+	}
+	return bw.Flush()
+}
+
+// Handler retorna o handler HTTP que serve as métricas (GET /metrics)
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		w.Header().Set("Content-Type", ContentType)
+		if err := r.WriteText(w); err != nil {
+			fmt.Printf("Failed to write metrics: %v\n", err)
+		}
+	})
+}
+
+// formatValue formata um valor como o Prometheus espera (inteiros sem notação científica)
+func formatValue(v float64) string {
+	switch {
+	case math.IsNaN(v):
+		return "NaN"
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case v == math.Trunc(v) && math.Abs(v) < 1e15:
+		return strconv.FormatInt(int64(v), 10)
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// Inc incrementa o contador em 1
+func (c *Counter) Inc() {
+	c.value.Add(1)
+}
+
+// Add incrementa o contador em n
+func (c *Counter) Add(n uint64) {
+	c.value.Add(n)
+}
+
+// Value retorna o valor atual do contador
+func (c *Counter) Value() uint64 {
+	return c.value.Load()
+}
@@ -0,0 +1,72 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRegistryWriteText(t *testing.T) {
+	registry := NewRegistry()
+	blocks := registry.NewCounter("test_blocks_total", "Blocos processados")
+	height := uint64(42)
+	registry.NewGaugeFunc("test_height", "Altura atual", func() float64 { return float64(height) })
+	registry.NewGaugeFunc("test_ratio", "Uma fração", func() float64 { return 0.25 })
+
+	blocks.Inc()
+	blocks.Add(2)
+	height = 43
+
+	var sb strings.Builder
+	if err := registry.WriteText(&sb); err != nil {
+		t.Fatalf("Failed to write metrics: %v", err)
+	}
+	out := sb.String()
+
+	expected := []string{
+		"# HELP test_blocks_total Blocos processados\n# TYPE test_blocks_total counter\ntest_blocks_total 3\n",
+		"# TYPE test_height gauge\ntest_height 43\n",
+		"test_ratio 0.25\n",
+	}
+	for _, e := range expected {
+		if !strings.Contains(out, e) {
+			t.Errorf("Expected output to contain %q, got:\n%s", e, out)
+		}
+	}
+}
+
+func TestRegistryDuplicateName(t *testing.T) {
+	registry := NewRegistry()
+	registry.NewCounter("dup_total", "")
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Registering a duplicate metric name should panic")
+		}
+	}()
+	registry.NewGaugeFunc("dup_total", "", func() float64 { return 0 })
+}
+
+func TestRegistryHandler(t *testing.T) {
+	registry := NewRegistry()
+	registry.NewCounter("handler_total", "Teste")
+
+	rec := httptest.NewRecorder()
+	registry.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != ContentType {
+		t.Errorf("Expected content type %q, got %q", ContentType, ct)
+	}
+	if !strings.Contains(rec.Body.String(), "handler_total 0") {
+		t.Errorf("Unexpected body: %s", rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	registry.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/metrics", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected status 405, got %d", rec.Code)
+	}
+}
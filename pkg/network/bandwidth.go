@@ -0,0 +1,142 @@
+package network
+
+import (
+	"sync"
+	"time"
+)
+
+// BandwidthLimit limites de banda em bytes por segundo (0 = sem limite)
+type BandwidthLimit struct {
+	PeerUpload    int64 // Envio para cada peer
+	PeerDownload  int64 // Recebimento de cada peer
+	TotalUpload   int64 // Envio somado de todos os peers
+	TotalDownload int64 // Recebimento somado de todos os peers
+}
+
+// IsZero indica se nenhum limite foi configurado
+func (l BandwidthLimit) IsZero() bool {
+	return l.PeerUpload <= 0 && l.PeerDownload <= 0 && l.TotalUpload <= 0 && l.TotalDownload <= 0
+}
+
+// BandwidthDirection sentido do tráfego contabilizado
+type BandwidthDirection int
+
+const (
+	Upload BandwidthDirection = iota
+	Download
+)
+
+// PacedMessageTypes mensagens em massa sujeitas aos limites de banda. As demais (blocos,
+// transações, handshake) só são contabilizadas, para não atrasar a propagação.
+var PacedMessageTypes = map[string]bool{
+	"sync_response":       true,
+	"headers_response":    true,
+	"checkpoint_response": true,
+}
+
+// BandwidthLimiter limita a banda por peer e total com token buckets de bytes. Cada bucket
+// acumula até um segundo de tráfego; uma mensagem maior que o saldo é enviada mesmo assim e
+// deixa o bucket negativo, atrasando as seguintes até a taxa média voltar ao limite.
+type BandwidthLimiter struct {
+	limit BandwidthLimit
+
+	mu    sync.Mutex
+	peers map[string]*[2]*byteBucket // peer -> [Upload, Download]
+	total [2]*byteBucket
+
+	now   func() time.Time    // Relógio (substituído nos testes)
+	sleep func(time.Duration) // Espera (substituída nos testes)
+}
+
+// byteBucket saldo de bytes de um sentido
+type byteBucket struct {
+	rate   float64 // Bytes repostos por segundo
+	tokens float64
+	last   time.Time
+}
+
+// NewBandwidthLimiter cria um limitador com os limites informados
+func NewBandwidthLimiter(limit BandwidthLimit) *BandwidthLimiter {
+	return newBandwidthLimiterWithClock(limit, time.Now, time.Sleep)
+}
+
+// newBandwidthLimiterWithClock cria um limitador com relógio e espera próprios
+func newBandwidthLimiterWithClock(limit BandwidthLimit, now func() time.Time, sleep func(time.Duration)) *BandwidthLimiter {
+	start := now()
+	return &BandwidthLimiter{
+		limit: limit,
+		peers: make(map[string]*[2]*byteBucket),
+		total: [2]*byteBucket{newByteBucket(limit.TotalUpload, start), newByteBucket(limit.TotalDownload, start)},
+		now:   now,
+		sleep: sleep,
+	}
+}
+
+// newByteBucket cria um bucket cheio (nil = sem limite)
+func newByteBucket(rate int64, now time.Time) *byteBucket {
+	if rate <= 0 {
+		return nil
+	}
+	return &byteBucket{rate: float64(rate), tokens: float64(rate), last: now}
+}
+
+// reserve desconta n bytes e retorna quanto esperar até o saldo voltar a zero
+func (b *byteBucket) reserve(n int, now time.Time) time.Duration {
+	if b == nil {
+		return 0
+	}
+
+	// Repõe os bytes acumulados desde a última reserva, até um segundo de tráfego
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.rate {
+		b.tokens = b.rate
+	}
+	b.last = now
+
+	b.tokens -= float64(n)
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// Reserve desconta n bytes trafegados com o peer no sentido informado e retorna quanto
+// esperar antes de trafegá-los para respeitar os limites
+func (l *BandwidthLimiter) Reserve(peerID string, direction BandwidthDirection, n int) time.Duration {
+	if l == nil {
+		return 0
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	buckets, exists := l.peers[peerID]
+	if !exists {
+		buckets = &[2]*byteBucket{newByteBucket(l.limit.PeerUpload, now), newByteBucket(l.limit.PeerDownload, now)}
+		l.peers[peerID] = buckets
+	}
+
+	wait := buckets[direction].reserve(n, now)
+	if totalWait := l.total[direction].reserve(n, now); totalWait > wait {
+		wait = totalWait
+	}
+	return wait
+}
+
+// Wait reserva n bytes e bloqueia até eles poderem trafegar
+func (l *BandwidthLimiter) Wait(peerID string, direction BandwidthDirection, n int) {
+	if wait := l.Reserve(peerID, direction, n); wait > 0 {
+		l.sleep(wait)
+	}
+}
+
+// RemovePeer descarta os buckets de um peer desconectado
+func (l *BandwidthLimiter) RemovePeer(peerID string) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.peers, peerID)
+}
@@ -0,0 +1,76 @@
+package network
+
+import (
+	"testing"
+	"time"
+)
+
+// fakeClock relógio simulado: sleep avança o horário sem esperar de verdade
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time                      { return c.now }
+func (c *fakeClock) Sleep(d time.Duration)               { c.now = c.now.Add(d) }
+func (c *fakeClock) since(start time.Time) time.Duration { return c.now.Sub(start) }
+
+func newTestBandwidthLimiter(limit BandwidthLimit) (*BandwidthLimiter, *fakeClock) {
+	clock := &fakeClock{now: time.Unix(1700000000, 0)}
+	return newBandwidthLimiterWithClock(limit, clock.Now, clock.Sleep), clock
+}
+
+func TestBandwidthLimiterPacesLargeSyncResponse(t *testing.T) {
+	const capBytes = 100_000
+	limiter, clock := newTestBandwidthLimiter(BandwidthLimit{PeerUpload: capBytes})
+	start := clock.now
+
+	// Resposta de sync de 1 MB enviada em lotes de 50 KB
+	const chunk, total = 50_000, 1_000_000
+	sent := 0
+	for sent < total {
+		limiter.Wait("peer1", Upload, chunk)
+		sent += chunk
+
+		// Em nenhum momento a média passa do limite mais a rajada de um segundo
+		elapsed := clock.since(start).Seconds()
+		if allowed := capBytes*elapsed + capBytes; float64(sent) > allowed+chunk {
+			t.Fatalf("Sent %d bytes in %.2fs, above the %d B/s cap", sent, elapsed, capBytes)
+		}
+	}
+
+	// 1 MB a 100 KB/s com um segundo de rajada: pelo menos 9 segundos
+	if elapsed := clock.since(start); elapsed < 9*time.Second {
+		t.Errorf("Expected the response paced over at least 9s, took %v", elapsed)
+	}
+
+	// Outro peer e o sentido contrário têm buckets próprios
+	if wait := limiter.Reserve("peer2", Upload, chunk); wait != 0 {
+		t.Errorf("Other peer should not be throttled, wait %v", wait)
+	}
+	if wait := limiter.Reserve("peer1", Download, total); wait != 0 {
+		t.Errorf("Download is unlimited in this configuration, wait %v", wait)
+	}
+}
+
+func TestBandwidthLimiterTotalCapSharedByPeers(t *testing.T) {
+	limiter, clock := newTestBandwidthLimiter(BandwidthLimit{TotalDownload: 10_000})
+
+	if wait := limiter.Reserve("peer1", Download, 10_000); wait != 0 {
+		t.Fatalf("First second of traffic should pass, wait %v", wait)
+	}
+	// O saldo total já foi consumido pelo peer1
+	if wait := limiter.Reserve("peer2", Download, 5_000); wait != 500*time.Millisecond {
+		t.Errorf("Expected peer2 to wait 500ms for the shared cap, got %v", wait)
+	}
+
+	clock.Sleep(2 * time.Second)
+	if wait := limiter.Reserve("peer2", Download, 5_000); wait != 0 {
+		t.Errorf("Bucket should have refilled, wait %v", wait)
+	}
+
+	// Sem limites, nada espera (limitador nil também é válido)
+	var disabled *BandwidthLimiter
+	if wait := disabled.Reserve("peer1", Upload, 1<<30); wait != 0 {
+		t.Errorf("Nil limiter should not wait, got %v", wait)
+	}
+}
@@ -16,6 +16,9 @@ type PeerInfo struct {
 	IsConnected   bool
 }
 
+// DefaultMaxParallelDials é o número padrão de conexões de saída estabelecidas em paralelo
+const DefaultMaxParallelDials = 4
+
 // PeerDiscovery gerencia a descoberta e seleção de peers
 type PeerDiscovery struct {
 	knownPeers   map[string]*PeerInfo
@@ -23,18 +26,37 @@ type PeerDiscovery struct {
 	maxPeers     int
 	minPeers     int
 	nodeID       string
+
+	// Conexões de saída em andamento (reservam vaga em maxPeers até concluírem)
+	pendingDials     map[string]bool
+	maxParallelDials int
+
+	// Peers carregados do disco a tentar ao iniciar, antes da lista do signaling
+	reconnectQueue []string
 }
 
 // NewPeerDiscovery cria uma nova instância de descoberta de peers
 func NewPeerDiscovery(nodeID string, maxPeers, minPeers int) *PeerDiscovery {
 	return &PeerDiscovery{
-		knownPeers: make(map[string]*PeerInfo),
-		maxPeers:   maxPeers,
-		minPeers:   minPeers,
-		nodeID:     nodeID,
+		knownPeers:       make(map[string]*PeerInfo),
+		maxPeers:         maxPeers,
+		minPeers:         minPeers,
+		nodeID:           nodeID,
+		pendingDials:     make(map[string]bool),
+		maxParallelDials: DefaultMaxParallelDials,
 	}
 }
 
+// SetMaxParallelDials define quantas conexões de saída podem ser estabelecidas ao mesmo tempo
+func (pd *PeerDiscovery) SetMaxParallelDials(n int) {
+	if n < 1 {
+		n = 1
+	}
+	pd.peersMutex.Lock()
+	pd.maxParallelDials = n
+	pd.peersMutex.Unlock()
+}
+
 // AddKnownPeer adiciona um peer à lista de peers conhecidos
 func (pd *PeerDiscovery) AddKnownPeer(peerID string) {
 	pd.peersMutex.Lock()
@@ -100,6 +122,11 @@ func (pd *PeerDiscovery) GetConnectedPeersCount() int {
 	pd.peersMutex.RLock()
 	defer pd.peersMutex.RUnlock()
 
+	return pd.connectedCountLocked()
+}
+
+// connectedCountLocked conta os peers conectados (requer peersMutex)
+func (pd *PeerDiscovery) connectedCountLocked() int {
 	count := 0
 	for _, peer := range pd.knownPeers {
 		if peer.IsConnected {
@@ -109,9 +136,24 @@ func (pd *PeerDiscovery) GetConnectedPeersCount() int {
 	return count
 }
 
+// usedSlotsLocked conta peers conectados mais conexões de saída ainda em andamento (requer peersMutex)
+func (pd *PeerDiscovery) usedSlotsLocked() int {
+	used := pd.connectedCountLocked()
+	for peerID := range pd.pendingDials {
+		if peer, exists := pd.knownPeers[peerID]; !exists || !peer.IsConnected {
+			used++
+		}
+	}
+	return used
+}
+
 // ShouldAcceptNewPeer verifica se deve aceitar um novo peer
+// (conexões de saída em andamento também ocupam vaga)
 func (pd *PeerDiscovery) ShouldAcceptNewPeer() bool {
-	return pd.GetConnectedPeersCount() < pd.maxPeers
+	pd.peersMutex.RLock()
+	defer pd.peersMutex.RUnlock()
+
+	return pd.usedSlotsLocked() < pd.maxPeers
 }
 
 // NeedsMorePeers verifica se precisa de mais peers
@@ -126,12 +168,7 @@ func (pd *PeerDiscovery) SelectPeersToConnect(availablePeers []string, currently
 
 	// Nota: peers já devem ter sido adicionados via AddKnownPeer antes de chamar este método
 
-	connectedCount := 0
-	for _, peer := range pd.knownPeers {
-		if peer.IsConnected {
-			connectedCount++
-		}
-	}
+	connectedCount := pd.usedSlotsLocked()
 
 	// Calcular quantos peers precisamos conectar
 	needCount := pd.minPeers - connectedCount
@@ -145,7 +182,7 @@ func (pd *PeerDiscovery) SelectPeersToConnect(availablePeers []string, currently
 		if peerID == pd.nodeID {
 			continue
 		}
-		if currentlyConnected[peerID] {
+		if currentlyConnected[peerID] || pd.pendingDials[peerID] {
 			continue
 		}
 		candidates = append(candidates, peerID)
@@ -164,6 +201,74 @@ func (pd *PeerDiscovery) SelectPeersToConnect(availablePeers []string, currently
 	return candidates[:min(needCount, maxToConnect)]
 }
 
+// reserveDial reserva uma vaga para uma conexão de saída, respeitando maxPeers
+func (pd *PeerDiscovery) reserveDial(peerID string) bool {
+	pd.peersMutex.Lock()
+	defer pd.peersMutex.Unlock()
+
+	if pd.pendingDials[peerID] {
+		return false
+	}
+	if peer, exists := pd.knownPeers[peerID]; exists && peer.IsConnected {
+		return false
+	}
+	if pd.usedSlotsLocked() >= pd.maxPeers {
+		return false
+	}
+
+	pd.pendingDials[peerID] = true
+	return true
+}
+
+// releaseDial libera a vaga reservada por uma conexão de saída
+func (pd *PeerDiscovery) releaseDial(peerID string) {
+	pd.peersMutex.Lock()
+	delete(pd.pendingDials, peerID)
+	pd.peersMutex.Unlock()
+}
+
+// ConnectPeers estabelece conexões com os peers em paralelo, com no máximo
+// maxParallelDials tentativas simultâneas. Cada tentativa reserva uma vaga em
+// maxPeers; peers excedentes são ignorados. Retorna os peers conectados com sucesso.
+func (pd *PeerDiscovery) ConnectPeers(peerIDs []string, connect func(peerID string) error) []string {
+	pd.peersMutex.RLock()
+	parallel := pd.maxParallelDials
+	pd.peersMutex.RUnlock()
+
+	sem := make(chan struct{}, parallel)
+	var wg sync.WaitGroup
+	var resultMutex sync.Mutex
+	var connected []string
+
+	for _, peerID := range peerIDs {
+		sem <- struct{}{}
+
+		if !pd.reserveDial(peerID) {
+			<-sem
+			continue
+		}
+
+		wg.Add(1)
+		go func(pid string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			defer pd.releaseDial(pid)
+
+			if err := connect(pid); err != nil {
+				fmt.Printf("[%s] Failed to connect to peer %s: %v\n", pd.nodeID, pid, err)
+				return
+			}
+
+			resultMutex.Lock()
+			connected = append(connected, pid)
+			resultMutex.Unlock()
+		}(peerID)
+	}
+
+	wg.Wait()
+	return connected
+}
+
 // SelectPeersToDisconnect seleciona peers para desconectar quando exceder o limite
 func (pd *PeerDiscovery) SelectPeersToDisconnect(connectedPeerIDs []string) []string {
 	pd.peersMutex.RLock()
@@ -218,18 +323,13 @@ func (pd *PeerDiscovery) GetPeerStats() map[string]interface{} {
 	pd.peersMutex.RLock()
 	defer pd.peersMutex.RUnlock()
 
-	connected := 0
+	connected := pd.connectedCountLocked()
 	known := len(pd.knownPeers)
 
-	for _, peer := range pd.knownPeers {
-		if peer.IsConnected {
-			connected++
-		}
-	}
-
 	return map[string]interface{}{
 		"connected": connected,
 		"known":     known,
+		"pending":   len(pd.pendingDials),
 		"max":       pd.maxPeers,
 		"min":       pd.minPeers,
 		"need_more": connected < pd.minPeers,
@@ -0,0 +1,120 @@
+package network
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+// knownPeersDBKey é a chave do LevelDB onde os peers conhecidos são persistidos
+const knownPeersDBKey = "known-peers"
+
+// DefaultPeerMaxAge idade máxima padrão de um peer salvo para ele ser carregado no próximo início
+const DefaultPeerMaxAge = 24 * time.Hour
+
+// storedPeer peer conhecido persistido entre reinícios
+type storedPeer struct {
+	ID       string `json:"id"`
+	LastSeen int64  `json:"last_seen"` // Última atividade (unix, em segundos)
+}
+
+// SavePeers persiste os peers conhecidos e quando foram vistos pela última vez no LevelDB
+// (substitui o conteúdo anterior). Peers conectados contam como vistos agora.
+func (pd *PeerDiscovery) SavePeers(db *leveldb.DB) error {
+	if db == nil {
+		return fmt.Errorf("database cannot be nil")
+	}
+
+	pd.peersMutex.RLock()
+	now := time.Now()
+	peers := make([]storedPeer, 0, len(pd.knownPeers))
+	for _, peer := range pd.knownPeers {
+		lastSeen := peer.LastSeen
+		if peer.IsConnected {
+			lastSeen = now
+		}
+		peers = append(peers, storedPeer{ID: peer.ID, LastSeen: lastSeen.Unix()})
+	}
+	pd.peersMutex.RUnlock()
+
+	sort.Slice(peers, func(i, j int) bool { return peers[i].ID < peers[j].ID })
+
+	data, err := json.Marshal(peers)
+	if err != nil {
+		return fmt.Errorf("failed to marshal known peers: %w", err)
+	}
+
+	if err := db.Put([]byte(knownPeersDBKey), data, nil); err != nil {
+		return fmt.Errorf("failed to save known peers: %w", err)
+	}
+
+	return nil
+}
+
+// LoadPeers carrega os peers salvos com SavePeers, descartando os vistos há mais de maxAge
+// (0 = DefaultPeerMaxAge). Os peers carregados entram como desconectados e ficam na fila de
+// reconexão, do mais recente ao mais antigo (ver TakeReconnectQueue). Retorna quantos
+// foram carregados.
+func (pd *PeerDiscovery) LoadPeers(db *leveldb.DB, maxAge time.Duration) (int, error) {
+	if db == nil {
+		return 0, fmt.Errorf("database cannot be nil")
+	}
+	if maxAge <= 0 {
+		maxAge = DefaultPeerMaxAge
+	}
+
+	data, err := db.Get([]byte(knownPeersDBKey), nil)
+	if err == leveldb.ErrNotFound {
+		return 0, nil // Nenhum peer salvo
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to load known peers: %w", err)
+	}
+
+	var peers []storedPeer
+	if err := json.Unmarshal(data, &peers); err != nil {
+		return 0, fmt.Errorf("failed to unmarshal known peers: %w", err)
+	}
+
+	// Mais recentes primeiro: são os que têm mais chance de ainda estarem online
+	sort.Slice(peers, func(i, j int) bool { return peers[i].LastSeen > peers[j].LastSeen })
+
+	cutoff := time.Now().Add(-maxAge)
+
+	pd.peersMutex.Lock()
+	defer pd.peersMutex.Unlock()
+
+	loaded := 0
+	for _, stored := range peers {
+		lastSeen := time.Unix(stored.LastSeen, 0)
+		if stored.ID == "" || stored.ID == pd.nodeID || lastSeen.Before(cutoff) {
+			continue
+		}
+		if _, exists := pd.knownPeers[stored.ID]; exists {
+			continue
+		}
+
+		pd.knownPeers[stored.ID] = &PeerInfo{
+			ID:       stored.ID,
+			LastSeen: lastSeen,
+		}
+		pd.reconnectQueue = append(pd.reconnectQueue, stored.ID)
+		loaded++
+	}
+
+	return loaded, nil
+}
+
+// TakeReconnectQueue retorna e esvazia a fila de peers carregados do disco que ainda não
+// foram tentados, do visto mais recentemente ao mais antigo
+func (pd *PeerDiscovery) TakeReconnectQueue() []string {
+	pd.peersMutex.Lock()
+	defer pd.peersMutex.Unlock()
+
+	queue := pd.reconnectQueue
+	pd.reconnectQueue = nil
+	return queue
+}
@@ -0,0 +1,242 @@
+package network
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+// mockTransport simula o estabelecimento de conexões: cada dial fica bloqueado
+// até o teste liberar, registrando o pico de conexões simultâneas
+type mockTransport struct {
+	discovery *PeerDiscovery
+	release   chan struct{}
+	started   chan string
+
+	mu        sync.Mutex
+	inFlight  int
+	maxFlight int
+}
+
+func newMockTransport(pd *PeerDiscovery) *mockTransport {
+	return &mockTransport{
+		discovery: pd,
+		release:   make(chan struct{}),
+		started:   make(chan string, 100),
+	}
+}
+
+func (m *mockTransport) dial(peerID string) error {
+	m.mu.Lock()
+	m.inFlight++
+	if m.inFlight > m.maxFlight {
+		m.maxFlight = m.inFlight
+	}
+	m.mu.Unlock()
+
+	m.started <- peerID
+	<-m.release
+
+	m.mu.Lock()
+	m.inFlight--
+	m.mu.Unlock()
+
+	// Como o WebRTCClient, o handler marca o peer como conectado
+	m.discovery.MarkPeerConnected(peerID)
+	return nil
+}
+
+func peerIDs(n int) []string {
+	ids := make([]string, n)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("peer%d", i)
+	}
+	return ids
+}
+
+func waitStarted(t *testing.T, m *mockTransport, n int) {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		select {
+		case <-m.started:
+		case <-time.After(2 * time.Second):
+			t.Fatalf("Only %d of %d dials started", i, n)
+		}
+	}
+}
+
+func TestConnectPeersParallelLimit(t *testing.T) {
+	pd := NewPeerDiscovery("node1", 20, 10)
+	pd.SetMaxParallelDials(3)
+	transport := newMockTransport(pd)
+
+	done := make(chan []string)
+	go func() {
+		done <- pd.ConnectPeers(peerIDs(10), transport.dial)
+	}()
+
+	// As 3 primeiras conexões devem estar em andamento ao mesmo tempo
+	waitStarted(t, transport, 3)
+	select {
+	case id := <-transport.started:
+		t.Fatalf("Dial to %s started beyond the parallel limit", id)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	close(transport.release)
+
+	var connected []string
+	select {
+	case connected = <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("ConnectPeers did not finish")
+	}
+
+	if len(connected) != 10 {
+		t.Errorf("Expected 10 connected peers, got %d", len(connected))
+	}
+	if transport.maxFlight != 3 {
+		t.Errorf("Expected 3 concurrent dials, got %d", transport.maxFlight)
+	}
+	if pd.GetConnectedPeersCount() != 10 {
+		t.Errorf("Expected 10 peers marked connected, got %d", pd.GetConnectedPeersCount())
+	}
+}
+
+func TestConnectPeersRespectsMaxPeers(t *testing.T) {
+	pd := NewPeerDiscovery("node1", 5, 5)
+	pd.SetMaxParallelDials(4)
+	pd.MarkPeerConnected("existing1")
+	pd.MarkPeerConnected("existing2")
+	transport := newMockTransport(pd)
+
+	done := make(chan []string)
+	go func() {
+		done <- pd.ConnectPeers(peerIDs(10), transport.dial)
+	}()
+
+	// Restam 3 vagas: dials pendentes reservam vaga e bloqueiam peers de entrada
+	waitStarted(t, transport, 3)
+	if pd.ShouldAcceptNewPeer() {
+		t.Error("Should not accept inbound peers while pending dials fill the remaining slots")
+	}
+	if selected := pd.SelectPeersToConnect(peerIDs(10), map[string]bool{}); len(selected) != 0 {
+		t.Errorf("Should not select more peers while at the limit, got %v", selected)
+	}
+
+	close(transport.release)
+
+	connected := <-done
+	if len(connected) != 3 {
+		t.Errorf("Expected 3 new connections, got %d", len(connected))
+	}
+	if transport.maxFlight > 3 {
+		t.Errorf("Dials exceeded available slots: %d", transport.maxFlight)
+	}
+	if pd.GetConnectedPeersCount() != 5 {
+		t.Errorf("Expected 5 connected peers, got %d", pd.GetConnectedPeersCount())
+	}
+}
+
+func TestConnectPeersSkipsFailedAndDuplicate(t *testing.T) {
+	pd := NewPeerDiscovery("node1", 10, 5)
+	pd.MarkPeerConnected("peer0")
+
+	connect := func(peerID string) error {
+		if peerID == "peer1" {
+			return fmt.Errorf("handshake failed")
+		}
+		pd.MarkPeerConnected(peerID)
+		return nil
+	}
+
+	connected := pd.ConnectPeers([]string{"peer0", "peer1", "peer2", "peer2"}, connect)
+	if len(connected) != 1 || connected[0] != "peer2" {
+		t.Errorf("Expected only peer2 to connect, got %v", connected)
+	}
+
+	// Falha libera a vaga reservada
+	stats := pd.GetPeerStats()
+	if stats["pending"] != 0 {
+		t.Errorf("Expected no pending dials, got %v", stats["pending"])
+	}
+}
+
+func TestSaveAndLoadPeersQueuesReconnection(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "peers.db")
+	db, err := leveldb.OpenFile(dbPath, nil)
+	if err != nil {
+		t.Fatalf("Failed to open DB: %v", err)
+	}
+
+	pd := NewPeerDiscovery("node1", 10, 5)
+	pd.MarkPeerConnected("connected")
+	pd.AddKnownPeer("recent")
+	pd.AddKnownPeer("stale")
+	pd.knownPeers["recent"].LastSeen = time.Now().Add(-time.Hour)
+	pd.knownPeers["stale"].LastSeen = time.Now().Add(-48 * time.Hour)
+
+	if err := pd.SavePeers(db); err != nil {
+		t.Fatalf("Failed to save peers: %v", err)
+	}
+	if err := db.Close(); err != nil {
+		t.Fatalf("Failed to close DB: %v", err)
+	}
+
+	// Reabrir como se o nó tivesse reiniciado
+	db, err = leveldb.OpenFile(dbPath, nil)
+	if err != nil {
+		t.Fatalf("Failed to reopen DB: %v", err)
+	}
+	defer db.Close()
+
+	restarted := NewPeerDiscovery("node1", 10, 5)
+	loaded, err := restarted.LoadPeers(db, 24*time.Hour)
+	if err != nil {
+		t.Fatalf("Failed to load peers: %v", err)
+	}
+	if loaded != 2 {
+		t.Errorf("Expected 2 peers loaded (stale pruned), got %d", loaded)
+	}
+	if restarted.GetConnectedPeersCount() != 0 {
+		t.Error("Loaded peers should start disconnected")
+	}
+
+	queue := restarted.TakeReconnectQueue()
+	if len(queue) != 2 || queue[0] != "connected" || queue[1] != "recent" {
+		t.Fatalf("Expected reconnection queued for [connected recent], got %v", queue)
+	}
+	if again := restarted.TakeReconnectQueue(); len(again) != 0 {
+		t.Errorf("Queue should be empty after being taken, got %v", again)
+	}
+
+	var attempted []string
+	var mu sync.Mutex
+	restarted.ConnectPeers(queue, func(peerID string) error {
+		mu.Lock()
+		attempted = append(attempted, peerID)
+		mu.Unlock()
+		return nil
+	})
+	if len(attempted) != 2 {
+		t.Errorf("Expected 2 reconnection attempts, got %v", attempted)
+	}
+}
+
+func TestLoadPeersFromEmptyDB(t *testing.T) {
+	db, err := leveldb.OpenFile(filepath.Join(t.TempDir(), "empty.db"), nil)
+	if err != nil {
+		t.Fatalf("Failed to open DB: %v", err)
+	}
+	defer db.Close()
+
+	pd := NewPeerDiscovery("node1", 10, 5)
+	loaded, err := pd.LoadPeers(db, 0)
+	if err != nil || loaded != 0 {
+		t.Errorf("Expected nothing loaded from empty DB, got %d (%v)", loaded, err)
+	}
+}
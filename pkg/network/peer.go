@@ -4,7 +4,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"sync"
+	"sync/atomic"
 
+	"github.com/krakovia/blockchain/pkg/wallet"
 	"github.com/pion/webrtc/v3"
 )
 
@@ -17,6 +19,21 @@ type Peer struct {
 	dataChannelReady bool
 	OnMessage       func(msgType string, data []byte)
 	OnDisconnect    func(peerID string)
+
+	// Handshake de identidade (ver EnableAuth)
+	authMux         sync.Mutex
+	identity        *wallet.Wallet
+	localID         string
+	authNonce       string
+	authenticated   bool
+	authFailed      bool
+	pendingMessages []Message
+
+	// Bytes trafegados com o peer (mensagens completas, como vão no data channel) e limites
+	// de banda das mensagens em massa (nil = sem limite)
+	bytesSent     atomic.Uint64
+	bytesReceived atomic.Uint64
+	bandwidth     *BandwidthLimiter
 }
 
 // Message representa uma mensagem entre peers
@@ -45,16 +62,35 @@ func (p *Peer) SetDataChannel(dc *webrtc.DataChannel) {
 		p.dataChannelMux.Lock()
 		p.dataChannelReady = true
 		p.dataChannelMux.Unlock()
+
+		p.authMux.Lock()
+		requireAuth := p.identity != nil
+		p.authMux.Unlock()
+		if requireAuth {
+			go p.runAuth()
+		}
 	})
 
 	// Handler para mensagens recebidas
 	dc.OnMessage(func(msg webrtc.DataChannelMessage) {
+		p.bytesReceived.Add(uint64(len(msg.Data)))
+
 		var message Message
 		if err := json.Unmarshal(msg.Data, &message); err != nil {
 			fmt.Printf("Failed to unmarshal message from peer %s: %v\n", p.ID, err)
 			return
 		}
 
+		// Segurar a leitura do data channel atrasa o próximo pedido de sync e, pelo controle
+		// de fluxo do SCTP, o envio do peer
+		if PacedMessageTypes[message.Type] {
+			p.bandwidth.Wait(p.ID, Download, len(msg.Data))
+		}
+
+		if p.handleAuthMessage(message) || p.holdUntilAuthenticated(message) {
+			return
+		}
+
 		if p.OnMessage != nil {
 			p.OnMessage(message.Type, message.Data)
 		}
@@ -73,14 +109,24 @@ func (p *Peer) SetDataChannel(dc *webrtc.DataChannel) {
 }
 
 // IsReady retorna se o data channel está pronto para enviar mensagens
+// (e, com autenticação habilitada, se o peer já provou sua identidade)
 func (p *Peer) IsReady() bool {
 	p.dataChannelMux.RLock()
-	defer p.dataChannelMux.RUnlock()
-	return p.dataChannelReady && p.DataChannel != nil
+	ready := p.dataChannelReady && p.DataChannel != nil
+	p.dataChannelMux.RUnlock()
+	return ready && p.IsAuthenticated()
 }
 
 // SendMessage envia uma mensagem para o peer
 func (p *Peer) SendMessage(msgType string, data []byte) error {
+	if !p.IsAuthenticated() {
+		return fmt.Errorf("peer %s not authenticated", p.ID)
+	}
+	return p.sendMessage(msgType, data)
+}
+
+// sendMessage envia uma mensagem sem exigir autenticação (usado pelo handshake)
+func (p *Peer) sendMessage(msgType string, data []byte) error {
 	p.dataChannelMux.RLock()
 	dc := p.DataChannel
 	ready := p.dataChannelReady
@@ -100,7 +146,30 @@ func (p *Peer) SendMessage(msgType string, data []byte) error {
 		return fmt.Errorf("failed to marshal message: %w", err)
 	}
 
-	return dc.Send(messageBytes)
+	if PacedMessageTypes[msgType] {
+		p.bandwidth.Wait(p.ID, Upload, len(messageBytes))
+	}
+
+	if err := dc.Send(messageBytes); err != nil {
+		return err
+	}
+	p.bytesSent.Add(uint64(len(messageBytes)))
+	return nil
+}
+
+// SetBandwidthLimiter define os limites de banda aplicados às mensagens em massa do peer
+func (p *Peer) SetBandwidthLimiter(limiter *BandwidthLimiter) {
+	p.bandwidth = limiter
+}
+
+// BytesSent retorna quantos bytes foram enviados ao peer
+func (p *Peer) BytesSent() uint64 {
+	return p.bytesSent.Load()
+}
+
+// BytesReceived retorna quantos bytes foram recebidos do peer
+func (p *Peer) BytesReceived() uint64 {
+	return p.bytesReceived.Load()
 }
 
 // Close fecha a conexão com o peer
@@ -0,0 +1,245 @@
+package network
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/krakovia/blockchain/pkg/wallet"
+)
+
+// Tipos de mensagem do handshake de identidade
+const (
+	MsgTypeAuthChallenge = "auth-challenge"
+	MsgTypeAuthResponse  = "auth-response"
+)
+
+const (
+	// peerAuthTimeout é o tempo máximo para o peer provar sua identidade após o data channel abrir
+	peerAuthTimeout = 10 * time.Second
+	// peerAuthRetryInterval é o intervalo de reenvio do desafio enquanto o peer não responde
+	peerAuthRetryInterval = 500 * time.Millisecond
+	// maxPendingAuthMessages limita as mensagens guardadas enquanto o peer não se autentica
+	maxPendingAuthMessages = 256
+)
+
+// AuthChallenge desafio enviado ao peer, que deve assiná-lo com a chave do seu ID
+type AuthChallenge struct {
+	Nonce string `json:"nonce"`
+}
+
+// AuthResponse resposta ao desafio: chave pública do peer e assinatura de PeerAuthSignData
+type AuthResponse struct {
+	PublicKey string `json:"public_key"`
+	Signature string `json:"signature"`
+}
+
+// PeerIDFromPublicKey deriva o ID de rede de um nó a partir da sua chave pública
+// (o mesmo endereço da carteira)
+func PeerIDFromPublicKey(publicKeyHex string) (string, error) {
+	return wallet.AddressFromPublicKey(publicKeyHex)
+}
+
+// PeerAuthSignData retorna a mensagem assinada no handshake. Incluir os dois IDs impede que
+// a resposta a um desafio seja reaproveitada em uma conexão com outro nó.
+func PeerAuthSignData(challengerID, responderID, nonce string) []byte {
+	return []byte(fmt.Sprintf("krakovia-peer-auth:%s:%s:%s", challengerID, responderID, nonce))
+}
+
+// SignAuthChallenge responde a um desafio de challengerID provando a posse da chave de responderID
+func SignAuthChallenge(identity *wallet.Wallet, challengerID, responderID, nonce string) (AuthResponse, error) {
+	signature, err := identity.Sign(PeerAuthSignData(challengerID, responderID, nonce))
+	if err != nil {
+		return AuthResponse{}, fmt.Errorf("failed to sign auth challenge: %w", err)
+	}
+
+	return AuthResponse{
+		PublicKey: identity.GetPublicKeyHex(),
+		Signature: signature,
+	}, nil
+}
+
+// VerifyPeerIdentity verifica que a resposta prova que o peer possui a chave do ID que alegou
+func VerifyPeerIdentity(localID, peerID, nonce string, resp AuthResponse) error {
+	derivedID, err := PeerIDFromPublicKey(resp.PublicKey)
+	if err != nil {
+		return fmt.Errorf("failed to derive peer ID from public key: %w", err)
+	}
+	if derivedID != peerID {
+		return fmt.Errorf("peer ID %s does not match its public key", peerID)
+	}
+
+	valid, err := wallet.Verify(resp.PublicKey, PeerAuthSignData(localID, peerID, nonce), resp.Signature)
+	if err != nil {
+		return fmt.Errorf("failed to verify auth signature: %w", err)
+	}
+	if !valid {
+		return fmt.Errorf("invalid auth signature from peer %s", peerID)
+	}
+
+	return nil
+}
+
+// EnableAuth exige que o peer prove a posse da chave do seu ID antes de trocar mensagens.
+// Também permite responder aos desafios do peer com a identidade local.
+// Deve ser chamado antes de SetDataChannel.
+func (p *Peer) EnableAuth(localID string, identity *wallet.Wallet) error {
+	nonce := make([]byte, 32)
+	if _, err := rand.Read(nonce); err != nil {
+		return fmt.Errorf("failed to generate auth nonce: %w", err)
+	}
+
+	p.authMux.Lock()
+	defer p.authMux.Unlock()
+	p.localID = localID
+	p.identity = identity
+	p.authNonce = hex.EncodeToString(nonce)
+	return nil
+}
+
+// IsAuthenticated retorna se o peer já provou sua identidade (sempre true sem autenticação)
+func (p *Peer) IsAuthenticated() bool {
+	p.authMux.Lock()
+	defer p.authMux.Unlock()
+	return p.identity == nil || p.authenticated
+}
+
+// runAuth envia o desafio até o peer responder, desconectando-o se não responder a tempo
+func (p *Peer) runAuth() {
+	deadline := time.Now().Add(peerAuthTimeout)
+	for time.Now().Before(deadline) {
+		p.authMux.Lock()
+		done := p.authenticated || p.authFailed
+		nonce := p.authNonce
+		p.authMux.Unlock()
+		if done {
+			return
+		}
+
+		p.dataChannelMux.RLock()
+		open := p.dataChannelReady
+		p.dataChannelMux.RUnlock()
+		if !open {
+			return
+		}
+
+		data, _ := json.Marshal(AuthChallenge{Nonce: nonce})
+		if err := p.sendMessage(MsgTypeAuthChallenge, data); err != nil {
+			fmt.Printf("Failed to send auth challenge to peer %s: %v\n", p.ID, err)
+		}
+		time.Sleep(peerAuthRetryInterval)
+	}
+
+	if !p.IsAuthenticated() {
+		p.failAuth(fmt.Errorf("timeout waiting for peer to prove its identity"))
+	}
+}
+
+// handleAuthMessage trata mensagens do handshake. Retorna false se não for uma delas.
+func (p *Peer) handleAuthMessage(message Message) bool {
+	switch message.Type {
+	case MsgTypeAuthChallenge:
+		p.authMux.Lock()
+		identity, localID := p.identity, p.localID
+		p.authMux.Unlock()
+
+		// Sem identidade local não há como responder; o peer decide se aceita a conexão
+		if identity == nil {
+			return true
+		}
+
+		var challenge AuthChallenge
+		if err := json.Unmarshal(message.Data, &challenge); err != nil {
+			fmt.Printf("Invalid auth challenge from peer %s: %v\n", p.ID, err)
+			return true
+		}
+
+		resp, err := SignAuthChallenge(identity, p.ID, localID, challenge.Nonce)
+		if err != nil {
+			fmt.Printf("Failed to answer auth challenge from peer %s: %v\n", p.ID, err)
+			return true
+		}
+		data, _ := json.Marshal(resp)
+		if err := p.sendMessage(MsgTypeAuthResponse, data); err != nil {
+			fmt.Printf("Failed to send auth response to peer %s: %v\n", p.ID, err)
+		}
+		return true
+
+	case MsgTypeAuthResponse:
+		p.authMux.Lock()
+		required := p.identity != nil && !p.authenticated && !p.authFailed
+		localID, nonce := p.localID, p.authNonce
+		p.authMux.Unlock()
+		if !required {
+			return true
+		}
+
+		var resp AuthResponse
+		if err := json.Unmarshal(message.Data, &resp); err != nil {
+			p.failAuth(fmt.Errorf("invalid auth response: %w", err))
+			return true
+		}
+		if err := VerifyPeerIdentity(localID, p.ID, nonce, resp); err != nil {
+			p.failAuth(err)
+			return true
+		}
+
+		p.authMux.Lock()
+		p.authenticated = true
+		pending := p.pendingMessages
+		p.pendingMessages = nil
+		p.authMux.Unlock()
+
+		fmt.Printf("Peer %s authenticated\n", p.ID)
+
+		// Entrega as mensagens recebidas antes da autenticação, na ordem em que chegaram
+		for _, msg := range pending {
+			if p.OnMessage != nil {
+				p.OnMessage(msg.Type, msg.Data)
+			}
+		}
+		return true
+	}
+
+	return false
+}
+
+// holdUntilAuthenticated guarda mensagens de um peer ainda não autenticado (o peer pode ter
+// terminado sua parte do handshake antes da nossa). Retorna false se a mensagem puder ser entregue.
+func (p *Peer) holdUntilAuthenticated(message Message) bool {
+	p.authMux.Lock()
+	defer p.authMux.Unlock()
+
+	if p.identity == nil || p.authenticated {
+		return false
+	}
+	if !p.authFailed && len(p.pendingMessages) < maxPendingAuthMessages {
+		p.pendingMessages = append(p.pendingMessages, message)
+	}
+	return true
+}
+
+// failAuth desconecta um peer que não provou sua identidade
+func (p *Peer) failAuth(err error) {
+	p.authMux.Lock()
+	if p.authFailed {
+		p.authMux.Unlock()
+		return
+	}
+	p.authFailed = true
+	p.pendingMessages = nil
+	p.authMux.Unlock()
+
+	fmt.Printf("Peer %s failed authentication: %v\n", p.ID, err)
+
+	go func() {
+		if err := p.Close(); err != nil {
+			fmt.Printf("Error closing unauthenticated peer %s: %v\n", p.ID, err)
+		}
+	}()
+	if p.OnDisconnect != nil {
+		p.OnDisconnect(p.ID)
+	}
+}
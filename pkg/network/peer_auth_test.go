@@ -0,0 +1,149 @@
+package network
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/krakovia/blockchain/pkg/wallet"
+)
+
+func TestPeerIdentityHandshake(t *testing.T) {
+	local, _ := wallet.NewWallet()
+	remote, _ := wallet.NewWallet()
+
+	remoteID, err := PeerIDFromPublicKey(remote.GetPublicKeyHex())
+	if err != nil {
+		t.Fatalf("Failed to derive peer ID: %v", err)
+	}
+	if remoteID != remote.GetAddress() {
+		t.Errorf("Peer ID should be the wallet address, got %s", remoteID)
+	}
+
+	resp, err := SignAuthChallenge(remote, local.GetAddress(), remoteID, "nonce-1")
+	if err != nil {
+		t.Fatalf("Failed to sign challenge: %v", err)
+	}
+	if err := VerifyPeerIdentity(local.GetAddress(), remoteID, "nonce-1", resp); err != nil {
+		t.Errorf("Honest peer should pass the handshake: %v", err)
+	}
+
+	// A resposta vale só para o desafio e a conexão em que foi gerada
+	if err := VerifyPeerIdentity(local.GetAddress(), remoteID, "nonce-2", resp); err == nil {
+		t.Error("Response to another challenge should be rejected")
+	}
+	other, _ := wallet.NewWallet()
+	if err := VerifyPeerIdentity(other.GetAddress(), remoteID, "nonce-1", resp); err == nil {
+		t.Error("Response relayed from another connection should be rejected")
+	}
+}
+
+func TestPeerImpersonationFailsHandshake(t *testing.T) {
+	local, _ := wallet.NewWallet()
+	attacker, _ := wallet.NewWallet()
+	victim, _ := wallet.NewWallet()
+
+	// O atacante alega o ID da vítima mas só pode assinar com a própria chave
+	resp, err := SignAuthChallenge(attacker, local.GetAddress(), victim.GetAddress(), "nonce")
+	if err != nil {
+		t.Fatalf("Failed to sign challenge: %v", err)
+	}
+	if err := VerifyPeerIdentity(local.GetAddress(), victim.GetAddress(), "nonce", resp); err == nil {
+		t.Error("Peer presenting an ID that does not match its key should fail the handshake")
+	}
+
+	// Apresentar a chave pública da vítima não basta sem a assinatura dela
+	resp.PublicKey = victim.GetPublicKeyHex()
+	if err := VerifyPeerIdentity(local.GetAddress(), victim.GetAddress(), "nonce", resp); err == nil {
+		t.Error("Peer without the private key of the claimed ID should fail the handshake")
+	}
+}
+
+func TestPeerHoldsMessagesUntilAuthenticated(t *testing.T) {
+	local, _ := wallet.NewWallet()
+	remote, _ := wallet.NewWallet()
+
+	peer := NewPeer(remote.GetAddress(), nil)
+	if err := peer.EnableAuth(local.GetAddress(), local); err != nil {
+		t.Fatalf("Failed to enable auth: %v", err)
+	}
+
+	var delivered []string
+	peer.OnMessage = func(msgType string, data []byte) {
+		delivered = append(delivered, msgType)
+	}
+
+	if peer.IsAuthenticated() {
+		t.Fatal("Peer should not be authenticated before the handshake")
+	}
+	if !peer.holdUntilAuthenticated(Message{Type: "sync_request"}) {
+		t.Fatal("Messages from an unauthenticated peer should be held")
+	}
+
+	resp, _ := SignAuthChallenge(remote, local.GetAddress(), remote.GetAddress(), peer.authNonce)
+	peer.handleAuthMessage(authResponseMessage(t, resp))
+
+	if !peer.IsAuthenticated() {
+		t.Fatal("Peer should be authenticated after a valid response")
+	}
+	if len(delivered) != 1 || delivered[0] != "sync_request" {
+		t.Errorf("Held messages should be delivered after authentication, got %v", delivered)
+	}
+	if peer.holdUntilAuthenticated(Message{Type: "block"}) {
+		t.Error("Messages from an authenticated peer should not be held")
+	}
+}
+
+func TestPeerDisconnectedOnFailedHandshake(t *testing.T) {
+	local, _ := wallet.NewWallet()
+	attacker, _ := wallet.NewWallet()
+	victim, _ := wallet.NewWallet()
+
+	peer := NewPeer(victim.GetAddress(), nil)
+	if err := peer.EnableAuth(local.GetAddress(), local); err != nil {
+		t.Fatalf("Failed to enable auth: %v", err)
+	}
+
+	disconnected := make(chan string, 1)
+	peer.OnDisconnect = func(peerID string) {
+		disconnected <- peerID
+	}
+	peer.holdUntilAuthenticated(Message{Type: "sync_request"})
+
+	resp, _ := SignAuthChallenge(attacker, local.GetAddress(), victim.GetAddress(), peer.authNonce)
+	peer.handleAuthMessage(authResponseMessage(t, resp))
+
+	if peer.IsAuthenticated() || peer.IsReady() {
+		t.Error("Impersonating peer should not be authenticated")
+	}
+	select {
+	case id := <-disconnected:
+		if id != victim.GetAddress() {
+			t.Errorf("Expected disconnect of %s, got %s", victim.GetAddress(), id)
+		}
+	default:
+		t.Error("Impersonating peer should be disconnected")
+	}
+	if len(peer.pendingMessages) != 0 {
+		t.Error("Held messages should be discarded after a failed handshake")
+	}
+}
+
+func TestPeerWithoutAuth(t *testing.T) {
+	peer := NewPeer("legacy", nil)
+	if !peer.IsAuthenticated() {
+		t.Error("Peer without auth enabled should be considered authenticated")
+	}
+	if peer.holdUntilAuthenticated(Message{Type: "block"}) {
+		t.Error("Messages should not be held without auth enabled")
+	}
+}
+
+// Helper: mensagem auth-response serializada
+func authResponseMessage(t *testing.T, resp AuthResponse) Message {
+	t.Helper()
+	data, err := json.Marshal(resp)
+	if err != nil {
+		t.Fatalf("Failed to marshal auth response: %v", err)
+	}
+	return Message{Type: MsgTypeAuthResponse, Data: data}
+}
@@ -0,0 +1,118 @@
+package network
+
+import (
+	"sync"
+	"time"
+)
+
+// TokenBucketLimit define o limite de um tipo de mensagem
+type TokenBucketLimit struct {
+	Rate  float64 // Mensagens repostas por segundo (<= 0 = sem limite)
+	Burst int     // Máximo de mensagens acumuladas para rajadas (mínimo 1)
+}
+
+// DefaultMessageRateLimits retorna os limites padrão por tipo de mensagem recebida.
+// Respostas a requisições do próprio nó (sync_response, headers_response, block_response,
+// checkpoint_response) não têm limite para não atrapalhar a sincronização; sync_request tem
+// folga para o catch-up de um peer, que envia um pedido por resposta recebida.
+func DefaultMessageRateLimits() map[string]TokenBucketLimit {
+	return map[string]TokenBucketLimit{
+		"transaction":          {Rate: 100, Burst: 500},
+		"block":                {Rate: 20, Burst: 100},
+		"sync_request":         {Rate: 10, Burst: 50},
+		"headers_request":      {Rate: 10, Burst: 50},
+		"get_block":            {Rate: 10, Burst: 50},
+		"checkpoint_request":   {Rate: 1, Burst: 10},
+		"checkpoint_signature": {Rate: 10, Burst: 100},
+		"slash_evidence":       {Rate: 5, Burst: 50},
+		"capabilities":         {Rate: 1, Burst: 5},
+		"time":                 {Rate: 1, Burst: 5},
+	}
+}
+
+// TokenBucketLimiter limita mensagens recebidas por peer e tipo de mensagem (token bucket)
+type TokenBucketLimiter struct {
+	limits  map[string]TokenBucketLimit
+	buckets map[string]map[string]*tokenBucket // peer -> tipo -> bucket
+	drops   map[string]int                     // Mensagens descartadas por peer
+	mu      sync.Mutex
+}
+
+// tokenBucket estado do bucket de um peer para um tipo de mensagem
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// NewTokenBucketLimiter cria um limitador com os limites por tipo de mensagem.
+// Tipos sem limite configurado não são limitados.
+func NewTokenBucketLimiter(limits map[string]TokenBucketLimit) *TokenBucketLimiter {
+	copied := make(map[string]TokenBucketLimit, len(limits))
+	for msgType, limit := range limits {
+		if limit.Burst < 1 {
+			limit.Burst = 1
+		}
+		copied[msgType] = limit
+	}
+
+	return &TokenBucketLimiter{
+		limits:  copied,
+		buckets: make(map[string]map[string]*tokenBucket),
+		drops:   make(map[string]int),
+	}
+}
+
+// Allow consome um token do bucket do peer para o tipo de mensagem.
+// Retorna false se a mensagem excede a taxa e deve ser descartada.
+func (l *TokenBucketLimiter) Allow(peerID, msgType string) bool {
+	limit, limited := l.limits[msgType]
+	if !limited || limit.Rate <= 0 {
+		return true
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	peerBuckets, exists := l.buckets[peerID]
+	if !exists {
+		peerBuckets = make(map[string]*tokenBucket)
+		l.buckets[peerID] = peerBuckets
+	}
+
+	now := time.Now()
+	bucket, exists := peerBuckets[msgType]
+	if !exists {
+		bucket = &tokenBucket{tokens: float64(limit.Burst), last: now}
+		peerBuckets[msgType] = bucket
+	}
+
+	// Repõe os tokens acumulados desde a última mensagem, até o tamanho da rajada
+	bucket.tokens += now.Sub(bucket.last).Seconds() * limit.Rate
+	if bucket.tokens > float64(limit.Burst) {
+		bucket.tokens = float64(limit.Burst)
+	}
+	bucket.last = now
+
+	if bucket.tokens < 1 {
+		l.drops[peerID]++
+		return false
+	}
+
+	bucket.tokens--
+	return true
+}
+
+// Drops retorna quantas mensagens do peer foram descartadas
+func (l *TokenBucketLimiter) Drops(peerID string) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.drops[peerID]
+}
+
+// RemovePeer descarta os buckets de um peer desconectado. A contagem de descartes é mantida
+// para que reconectar não zere a penalidade do peer.
+func (l *TokenBucketLimiter) RemovePeer(peerID string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.buckets, peerID)
+}
@@ -0,0 +1,58 @@
+package network
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTokenBucketLimiterBurst(t *testing.T) {
+	limiter := NewTokenBucketLimiter(map[string]TokenBucketLimit{
+		"transaction": {Rate: 10, Burst: 5},
+	})
+
+	allowed := 0
+	for i := 0; i < 20; i++ {
+		if limiter.Allow("peer1", "transaction") {
+			allowed++
+		}
+	}
+	if allowed != 5 {
+		t.Errorf("Expected burst of 5 messages, got %d", allowed)
+	}
+	if drops := limiter.Drops("peer1"); drops != 15 {
+		t.Errorf("Expected 15 drops, got %d", drops)
+	}
+
+	// Limite é por peer e por tipo de mensagem
+	if !limiter.Allow("peer2", "transaction") {
+		t.Error("Another peer should have its own bucket")
+	}
+	if !limiter.Allow("peer1", "sync_response") {
+		t.Error("Message types without a limit should not be limited")
+	}
+
+	// Tokens são repostos com o tempo (10/s = 1 a cada 100ms)
+	time.Sleep(250 * time.Millisecond)
+	if !limiter.Allow("peer1", "transaction") {
+		t.Error("Bucket should refill over time")
+	}
+}
+
+func TestTokenBucketLimiterRemovePeer(t *testing.T) {
+	limiter := NewTokenBucketLimiter(map[string]TokenBucketLimit{
+		"block": {Rate: 1, Burst: 1},
+	})
+
+	limiter.Allow("peer1", "block")
+	if limiter.Allow("peer1", "block") {
+		t.Fatal("Second block within the burst window should be dropped")
+	}
+
+	limiter.RemovePeer("peer1")
+	if !limiter.Allow("peer1", "block") {
+		t.Error("Reconnected peer should start with a full bucket")
+	}
+	if limiter.Drops("peer1") != 1 {
+		t.Error("Drop count should survive a reconnection")
+	}
+}
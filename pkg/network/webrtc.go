@@ -3,11 +3,31 @@ package network
 import (
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/gorilla/websocket"
+	"github.com/krakovia/blockchain/pkg/wallet"
 	"github.com/pion/webrtc/v3"
 )
 
+// peerDialTimeout é o tempo máximo de espera para o data channel abrir após enviar a oferta
+const peerDialTimeout = 10 * time.Second
+
+// DefaultSTUNServer servidor STUN usado quando nenhum servidor ICE é configurado
+const DefaultSTUNServer = "stun:stun.l.google.com:19302"
+
+// ICEServer servidor STUN ou TURN usado para estabelecer as conexões com os peers
+type ICEServer struct {
+	URLs       []string // Ex: "stun:stun.example.com:3478", "turn:turn.example.com:3478?transport=udp"
+	Username   string   // Usuário do TURN (opcional)
+	Credential string   // Senha do TURN (opcional)
+}
+
+// DefaultICEServers retorna os servidores ICE padrão (apenas STUN público)
+func DefaultICEServers() []ICEServer {
+	return []ICEServer{{URLs: []string{DefaultSTUNServer}}}
+}
+
 // PeerHandler define a interface para lidar com eventos de peers
 type PeerHandler interface {
 	AddPeer(peer *Peer)
@@ -26,6 +46,10 @@ type WebRTCClient struct {
 	handler         PeerHandler
 	discovery       *PeerDiscovery
 	gossipManager   *GossipManager
+	identity        *wallet.Wallet // Identidade provada aos peers (nil = sem autenticação)
+
+	// Limites de banda compartilhados pelos peers (nil = sem limite)
+	bandwidth *BandwidthLimiter
 }
 
 // SignalingMessage representa uma mensagem do servidor de signaling
@@ -46,11 +70,7 @@ func NewWebRTCClient(id, signalingServer string, handler PeerHandler) (*WebRTCCl
 // NewWebRTCClientWithDiscovery cria um novo cliente WebRTC com sistema de descoberta
 func NewWebRTCClientWithDiscovery(id, signalingServer string, handler PeerHandler, discovery *PeerDiscovery) (*WebRTCClient, error) {
 	config := webrtc.Configuration{
-		ICEServers: []webrtc.ICEServer{
-			{
-				URLs: []string{"stun:stun.l.google.com:19302"},
-			},
-		},
+		ICEServers: toWebRTCICEServers(DefaultICEServers()),
 	}
 
 	// Criar gerenciador gossip
@@ -68,6 +88,63 @@ func NewWebRTCClientWithDiscovery(id, signalingServer string, handler PeerHandle
 	}, nil
 }
 
+// SetIdentity exige que todo peer prove, com um desafio assinado, a posse da chave do seu ID
+// e usa a carteira para provar o ID local. O ID local deve ser o endereço da carteira.
+func (w *WebRTCClient) SetIdentity(identity *wallet.Wallet) {
+	w.identity = identity
+}
+
+// SetBandwidthLimit limita a banda das mensagens em massa (respostas de sync, headers e
+// checkpoints) por peer e no total. A contagem de bytes de cada peer é feita sempre.
+func (w *WebRTCClient) SetBandwidthLimit(limit BandwidthLimit) {
+	if limit.IsZero() {
+		w.bandwidth = nil
+		return
+	}
+	w.bandwidth = NewBandwidthLimiter(limit)
+}
+
+// SetICEServers define os servidores STUN/TURN usados nas próximas conexões (vazio = padrão).
+// Com um servidor TURN, peers atrás de NATs restritivos conectam pelo relay.
+func (w *WebRTCClient) SetICEServers(servers []ICEServer) {
+	if len(servers) == 0 {
+		servers = DefaultICEServers()
+	}
+	w.config.ICEServers = toWebRTCICEServers(servers)
+}
+
+// ICEConfiguration retorna a configuração ICE usada nas conexões com os peers
+func (w *WebRTCClient) ICEConfiguration() webrtc.Configuration {
+	return w.config
+}
+
+// toWebRTCICEServers converte os servidores configurados para o formato do pion
+func toWebRTCICEServers(servers []ICEServer) []webrtc.ICEServer {
+	converted := make([]webrtc.ICEServer, 0, len(servers))
+	for _, server := range servers {
+		ice := webrtc.ICEServer{URLs: append([]string(nil), server.URLs...)}
+		if server.Username != "" || server.Credential != "" {
+			ice.Username = server.Username
+			ice.Credential = server.Credential
+			ice.CredentialType = webrtc.ICECredentialTypePassword
+		}
+		converted = append(converted, ice)
+	}
+	return converted
+}
+
+// newPeer cria um peer, habilitando o handshake de identidade se configurado
+func (w *WebRTCClient) newPeer(peerID string, connection *webrtc.PeerConnection) (*Peer, error) {
+	peer := NewPeer(peerID, connection)
+	peer.SetBandwidthLimiter(w.bandwidth)
+	if w.identity != nil {
+		if err := peer.EnableAuth(w.ID, w.identity); err != nil {
+			return nil, err
+		}
+	}
+	return peer, nil
+}
+
 // Connect conecta ao servidor de signaling
 func (w *WebRTCClient) Connect() error {
 	conn, _, err := websocket.DefaultDialer.Dial(w.SignalingServer, nil)
@@ -124,13 +201,10 @@ func (w *WebRTCClient) handleSignalingMessages() {
 				// Selecionar quais peers conectar
 				toConnect := w.discovery.SelectPeersToConnect(msg.PeerList, currentlyConnected)
 				fmt.Printf("[%s] Selected peers to connect: %v\n", w.ID, toConnect)
-				for _, peerID := range toConnect {
-					go func(pid string) {
-					if err := w.ConnectToPeer(pid); err != nil {
-						fmt.Printf("Failed to connect to peer %s: %v\n", pid, err)
-					}
-				}(peerID)
-				}
+
+				// Conecta em paralelo (limitado pela descoberta) sem bloquear o loop de signaling,
+				// que precisa continuar recebendo answers e ICE candidates
+				go w.discovery.ConnectPeers(toConnect, w.dialPeer)
 			} else {
 				// Modo legado: conectar a todos
 				for _, peerID := range msg.PeerList {
@@ -148,7 +222,7 @@ func (w *WebRTCClient) handleSignalingMessages() {
 			// Recebeu uma oferta de conexão - verificar se deve aceitar
 			if w.discovery != nil && !w.discovery.ShouldAcceptNewPeer() {
 				fmt.Printf("Rejecting offer from %s (peer limit reached)\n", msg.From)
-				return
+				continue
 			}
 			go w.handleOffer(msg.From, msg.SDP)
 
@@ -173,7 +247,10 @@ func (w *WebRTCClient) ConnectToPeer(peerID string) error {
 		return fmt.Errorf("failed to create peer connection: %w", err)
 	}
 
-	peer := NewPeer(peerID, peerConnection)
+	peer, err := w.newPeer(peerID, peerConnection)
+	if err != nil {
+		return fmt.Errorf("failed to create peer: %w", err)
+	}
 
 	// Criar data channel
 	dataChannel, err := peerConnection.CreateDataChannel("data", nil)
@@ -218,6 +295,31 @@ func (w *WebRTCClient) ConnectToPeer(peerID string) error {
 	return nil
 }
 
+// dialPeer inicia a conexão com um peer e aguarda o data channel abrir,
+// para que a descoberta limite o número de handshakes simultâneos
+func (w *WebRTCClient) dialPeer(peerID string) error {
+	if err := w.ConnectToPeer(peerID); err != nil {
+		return err
+	}
+
+	deadline := time.Now().Add(peerDialTimeout)
+	for time.Now().Before(deadline) {
+		w.peersMutex.RLock()
+		peer, exists := w.peers[peerID]
+		w.peersMutex.RUnlock()
+
+		if !exists {
+			return fmt.Errorf("peer %s removed before connection was established", peerID)
+		}
+		if peer.IsReady() {
+			return nil
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	return fmt.Errorf("timeout waiting for data channel with peer %s", peerID)
+}
+
 // handleOffer processa uma oferta recebida
 func (w *WebRTCClient) handleOffer(peerID string, sdp *webrtc.SessionDescription) {
 	fmt.Printf("Received offer from peer %s\n", peerID)
@@ -247,7 +349,11 @@ func (w *WebRTCClient) handleOffer(peerID string, sdp *webrtc.SessionDescription
 		return
 	}
 
-	peer := NewPeer(peerID, peerConnection)
+	peer, err := w.newPeer(peerID, peerConnection)
+	if err != nil {
+		fmt.Printf("Failed to create peer: %v\n", err)
+		return
+	}
 	peer.OnDisconnect = func(id string) {
 		w.removePeer(id)
 	}
@@ -393,12 +499,27 @@ func (w *WebRTCClient) removePeer(peerID string) {
 	w.peersMutex.Lock()
 	delete(w.peers, peerID)
 	w.peersMutex.Unlock()
+	w.bandwidth.RemovePeer(peerID)
 
 	if w.handler != nil {
 		w.handler.RemovePeer(peerID)
 	}
 }
 
+// ReconnectKnownPeers tenta reconectar aos peers carregados do disco pela descoberta (ver
+// PeerDiscovery.LoadPeers). Bloqueia até as tentativas terminarem e retorna os peers conectados.
+func (w *WebRTCClient) ReconnectKnownPeers() []string {
+	if w.discovery == nil {
+		return nil
+	}
+	queue := w.discovery.TakeReconnectQueue()
+	if len(queue) == 0 {
+		return nil
+	}
+	fmt.Printf("[%s] Reconnecting to %d previously known peers\n", w.ID, len(queue))
+	return w.discovery.ConnectPeers(queue, w.dialPeer)
+}
+
 // RequestPeerList solicita a lista de peers do servidor de signaling
 func (w *WebRTCClient) RequestPeerList() {
 	msg := SignalingMessage{
@@ -0,0 +1,57 @@
+package network
+
+import (
+	"testing"
+
+	"github.com/pion/webrtc/v3"
+)
+
+func TestSetICEServersPopulatesConfiguration(t *testing.T) {
+	client, err := NewWebRTCClient("node-a", "ws://localhost:0/ws", nil)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	// Sem configuração: STUN público padrão
+	servers := client.ICEConfiguration().ICEServers
+	if len(servers) != 1 || len(servers[0].URLs) != 1 || servers[0].URLs[0] != DefaultSTUNServer {
+		t.Fatalf("Expected default STUN server, got %+v", servers)
+	}
+
+	client.SetICEServers([]ICEServer{
+		{URLs: []string{"stun:stun.example.com:3478"}},
+		{
+			URLs:       []string{"turn:turn.example.com:3478?transport=udp", "turns:turn.example.com:5349"},
+			Username:   "krakovia",
+			Credential: "secret",
+		},
+	})
+
+	servers = client.ICEConfiguration().ICEServers
+	if len(servers) != 2 {
+		t.Fatalf("Expected 2 ICE servers, got %d", len(servers))
+	}
+	if servers[0].URLs[0] != "stun:stun.example.com:3478" || servers[0].Username != "" || servers[0].Credential != nil {
+		t.Errorf("STUN server should have no credentials, got %+v", servers[0])
+	}
+	turn := servers[1]
+	if len(turn.URLs) != 2 || turn.URLs[1] != "turns:turn.example.com:5349" {
+		t.Errorf("TURN urls not copied, got %v", turn.URLs)
+	}
+	if turn.Username != "krakovia" || turn.Credential != "secret" || turn.CredentialType != webrtc.ICECredentialTypePassword {
+		t.Errorf("TURN credentials not set, got %+v", turn)
+	}
+
+	// A configuração é aceita pelo pion ao criar a conexão (sem conectar a nada)
+	pc, err := webrtc.NewPeerConnection(client.ICEConfiguration())
+	if err != nil {
+		t.Fatalf("ICE configuration should be valid for a peer connection: %v", err)
+	}
+	_ = pc.Close()
+
+	// Lista vazia volta ao padrão
+	client.SetICEServers(nil)
+	if servers := client.ICEConfiguration().ICEServers; len(servers) != 1 || servers[0].URLs[0] != DefaultSTUNServer {
+		t.Errorf("Empty list should restore the default STUN server, got %+v", servers)
+	}
+}
@@ -0,0 +1,237 @@
+package node
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/krakovia/blockchain/pkg/blockchain"
+)
+
+const (
+	// maxBlockFetchDepth limita quantos pais seguidos são buscados por hash para um mesmo bloco
+	maxBlockFetchDepth = 32
+	// maxPendingBlockFetches limita as buscas por hash em andamento ao mesmo tempo
+	maxPendingBlockFetches = 16
+	// blockFetchTimeout tempo para um peer responder um get_block antes de a busca ser descartada
+	blockFetchTimeout = 30 * time.Second
+)
+
+// GetBlockRequest mensagem de requisição de um bloco pelo hash
+type GetBlockRequest struct {
+	Hash string `json:"hash"`
+}
+
+// BlockResponse mensagem de resposta a um get_block (Block nil se o peer não tem o bloco)
+type BlockResponse struct {
+	Hash  string            `json:"hash"`
+	Block *blockchain.Block `json:"block,omitempty"`
+}
+
+// pendingBlockFetch ramo recebido cujo pai foi pedido por hash a um peer
+type pendingBlockFetch struct {
+	peerID    string
+	branch    []*blockchain.Block // Blocos em ordem de altura; o pai de branch[0] é o hash pedido
+	requested time.Time
+}
+
+// blockFetcher buscas por hash em andamento, indexadas pelo hash pedido
+type blockFetcher struct {
+	mu      sync.Mutex
+	pending map[string]*pendingBlockFetch
+}
+
+// newBlockFetcher cria o estado vazio (nenhuma busca em andamento)
+func newBlockFetcher() *blockFetcher {
+	return &blockFetcher{pending: make(map[string]*pendingBlockFetch)}
+}
+
+// add registra a busca do pai do ramo. Retorna false se ele já estiver sendo buscado ou se
+// houver buscas demais em andamento.
+func (f *blockFetcher) add(peerID string, branch []*blockchain.Block) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	now := time.Now()
+	for hash, fetch := range f.pending {
+		if now.Sub(fetch.requested) > blockFetchTimeout {
+			delete(f.pending, hash)
+		}
+	}
+
+	hash := branch[0].Header.PreviousHash
+	if _, exists := f.pending[hash]; exists || len(f.pending) >= maxPendingBlockFetches {
+		return false
+	}
+	f.pending[hash] = &pendingBlockFetch{peerID: peerID, branch: branch, requested: now}
+	return true
+}
+
+// take remove e retorna a busca do hash feita ao peer (nil se não houver)
+func (f *blockFetcher) take(peerID, hash string) *pendingBlockFetch {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	fetch := f.pending[hash]
+	if fetch == nil || fetch.peerID != peerID {
+		return nil
+	}
+	delete(f.pending, hash)
+	return fetch
+}
+
+// fetchMissingParent guarda o ramo e pede ao peer o pai do primeiro bloco pelo hash. Quando ele
+// chega, o ramo é aplicado ou o pai seguinte é pedido (até maxBlockFetchDepth blocos).
+func (n *Node) fetchMissingParent(peerID string, branch []*blockchain.Block) {
+	block := branch[0]
+
+	// Só blocos bem formados e assinados pelo validador disparam buscas
+	if err := block.ValidateAt(n.chain.Now(), n.chain.GetConfig().ClockDrift()); err != nil {
+		fmt.Printf("[%s] Ignoring invalid block %d from %s: %v\n", n.ID, block.Header.Height, peerID, err)
+		n.logRejectedBlock(peerID, "fetch", block, err)
+		return
+	}
+	if err := block.VerifySignature(); err != nil {
+		fmt.Printf("[%s] Ignoring block %d from %s: %v\n", n.ID, block.Header.Height, peerID, err)
+		n.logRejectedBlock(peerID, "fetch", block, err)
+		return
+	}
+
+	if len(branch) > maxBlockFetchDepth || block.Header.Height <= 1 {
+		err := fmt.Errorf("parent %s not found after fetching %d blocks", block.Header.PreviousHash, len(branch)-1)
+		fmt.Printf("[%s] Giving up on block %d from %s: %v\n", n.ID, branch[len(branch)-1].Header.Height, peerID, err)
+		n.logRejectedBlock(peerID, "fetch", branch[len(branch)-1], err)
+		return
+	}
+
+	if !n.blockFetches.add(peerID, branch) {
+		return
+	}
+
+	hash := block.Header.PreviousHash
+	fmt.Printf("[%s] 🔎 Parent of block %d unknown, requesting %s from %s\n", n.ID, block.Header.Height, hash[:8], peerID)
+	if err := n.sendGetBlock(peerID, GetBlockRequest{Hash: hash}); err != nil {
+		fmt.Printf("[%s] Failed to request block %s from %s: %v\n", n.ID, hash[:8], peerID, err)
+		n.blockFetches.take(peerID, hash)
+	}
+}
+
+// connectFetchedBranch aplica um ramo cujo primeiro bloco tem pai conhecido: estende a ponta
+// bloco a bloco ou compete com a chain principal como fork
+func (n *Node) connectFetchedBranch(peerID string, branch []*blockchain.Block) {
+	// Descarta o prefixo que o nó já tem
+	for len(branch) > 0 {
+		if _, exists := n.chain.GetBlock(branch[0].Hash); !exists {
+			break
+		}
+		branch = branch[1:]
+	}
+	if len(branch) == 0 {
+		return
+	}
+
+	if branch[0].Header.PreviousHash != n.chain.GetLastBlock().Hash {
+		n.considerFork(peerID, branch)
+		n.connectOrphans()
+		return
+	}
+
+	for _, block := range branch {
+		if !n.applyGossipBlock(peerID, block) {
+			return
+		}
+	}
+	n.connectOrphans()
+}
+
+// handleGetBlock responde um get_block com o bloco do hash pedido (da memória ou do disco)
+func (n *Node) handleGetBlock(peerID string, data []byte) {
+	var req GetBlockRequest
+	if err := json.Unmarshal(data, &req); err != nil {
+		fmt.Printf("[%s] Failed to parse get_block from %s: %v\n", n.ID, peerID, err)
+		return
+	}
+
+	responseData, err := json.Marshal(n.BuildBlockResponse(req.Hash))
+	if err != nil {
+		fmt.Printf("[%s] Failed to marshal block response: %v\n", n.ID, err)
+		return
+	}
+
+	n.peersMutex.RLock()
+	peer := n.peers[peerID]
+	n.peersMutex.RUnlock()
+
+	if peer == nil {
+		fmt.Printf("[%s] ❌ Peer %s not found, cannot send block response\n", n.ID, peerID)
+		return
+	}
+	if err := n.sendPayload(peer, newOutgoingPayload("block_response", responseData)); err != nil {
+		fmt.Printf("[%s] ❌ Failed to send block response to %s: %v\n", n.ID, peerID, err)
+	}
+}
+
+// BuildBlockResponse monta a resposta a um get_block. Block fica nil se o nó não tem o bloco.
+func (n *Node) BuildBlockResponse(hash string) BlockResponse {
+	response := BlockResponse{Hash: hash}
+	if block, found := n.GetBlockByHash(hash); found && !block.IsCheckpointAnchor() {
+		response.Block = block
+	}
+	return response
+}
+
+// handleBlockResponse recebe o bloco pedido por hash e continua a busca do ramo que esperava por ele
+func (n *Node) handleBlockResponse(peerID string, data []byte) {
+	var resp BlockResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		fmt.Printf("[%s] Failed to parse block response from %s: %v\n", n.ID, peerID, err)
+		return
+	}
+
+	fetch := n.blockFetches.take(peerID, resp.Hash)
+	if fetch == nil {
+		return // Não pedido a este peer, ou a busca expirou
+	}
+
+	block := resp.Block
+	if block == nil {
+		fmt.Printf("[%s] Peer %s does not have block %s\n", n.ID, peerID, resp.Hash)
+		return
+	}
+	if block.Hash != resp.Hash {
+		err := fmt.Errorf("peer sent block %s for requested hash %s", block.Hash, resp.Hash)
+		fmt.Printf("[%s] Rejected block response from %s: %v\n", n.ID, peerID, err)
+		n.logRejectedBlock(peerID, "fetch", block, err)
+		return
+	}
+
+	branch := append([]*blockchain.Block{block}, fetch.branch...)
+	if _, known := n.GetBlockByHash(block.Header.PreviousHash); known {
+		fmt.Printf("[%s] 🔎 Fetched block %d from %s connects %d blocks to the chain\n", n.ID, block.Header.Height, peerID, len(branch))
+		n.connectFetchedBranch(peerID, branch)
+		return
+	}
+	n.fetchMissingParent(peerID, branch)
+}
+
+// sendGetBlock envia um get_block ao peer (ou ao hook de testes, se configurado)
+func (n *Node) sendGetBlock(peerID string, req GetBlockRequest) error {
+	if n.sendGetBlockHook != nil {
+		return n.sendGetBlockHook(peerID, req)
+	}
+
+	n.peersMutex.RLock()
+	peer := n.peers[peerID]
+	n.peersMutex.RUnlock()
+
+	if peer == nil {
+		return fmt.Errorf("peer %s not found", peerID)
+	}
+
+	data, err := json.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("failed to marshal get_block: %w", err)
+	}
+	return peer.SendMessage("get_block", data)
+}
@@ -0,0 +1,44 @@
+package node
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/krakovia/blockchain/pkg/network"
+)
+
+// TimeMessage informa ao peer o horário do nó ao conectar, para ele ajustar o relógio pela
+// mediana da rede. Peers que não conhecem a mensagem a ignoram.
+type TimeMessage struct {
+	Time int64 `json:"time"` // Horário local (unix, em segundos), sem o ajuste da rede
+}
+
+// sendTime envia ao peer o horário local
+func (n *Node) sendTime(peer *network.Peer) {
+	data, err := json.Marshal(TimeMessage{Time: time.Now().Unix()})
+	if err != nil {
+		fmt.Printf("[%s] Failed to marshal time: %v\n", n.ID, err)
+		return
+	}
+	if err := peer.SendMessage("time", data); err != nil {
+		fmt.Printf("[%s] Failed to send time to %s: %v\n", n.ID, peer.ID, err)
+	}
+}
+
+// handleTime registra o horário informado pelo peer no relógio da chain
+func (n *Node) handleTime(peerID string, data []byte) {
+	var msg TimeMessage
+	if err := json.Unmarshal(data, &msg); err != nil {
+		fmt.Printf("[%s] Failed to unmarshal time from %s: %v\n", n.ID, peerID, err)
+		return
+	}
+
+	clock := n.chain.Clock()
+	previous := clock.Offset()
+	offset := clock.AddSample(peerID, msg.Time)
+	if offset != previous {
+		fmt.Printf("[%s] 🕒 Clock offset adjusted to %v by peer times (%s reported %+ds)\n",
+			n.ID, offset, peerID, msg.Time-time.Now().Unix())
+	}
+}
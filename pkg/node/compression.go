@@ -0,0 +1,121 @@
+package node
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/krakovia/blockchain/pkg/blockchain"
+	"github.com/krakovia/blockchain/pkg/network"
+)
+
+// CompressionGzip formato de compressão anunciado em CapabilitiesMessage
+const CompressionGzip = "gzip"
+
+// minCompressSize payloads menores que isso vão sem compressão (o ganho não compensa o custo)
+const minCompressSize = 512
+
+// compressibleMessages tipos de mensagem comprimidos para peers que aceitam compressão
+var compressibleMessages = map[string]bool{
+	"block":               true,
+	"transaction":         true,
+	"sync_response":       true,
+	"headers_response":    true,
+	"block_response":      true,
+	"checkpoint_response": true,
+}
+
+// CapabilitiesMessage anuncia recursos opcionais do protocolo aceitos pelo nó. Peers que não
+// conhecem a mensagem a ignoram e continuam recebendo payloads sem compressão.
+type CapabilitiesMessage struct {
+	Compression []string `json:"compression,omitempty"` // Formatos de compressão aceitos
+}
+
+// outgoingPayload payload de uma mensagem a enviar, comprimido no máximo uma vez mesmo quando
+// vai para vários peers
+type outgoingPayload struct {
+	msgType    string
+	raw        []byte
+	compressed []byte
+	skip       bool // Compressão já tentada sem ganho
+}
+
+// newOutgoingPayload cria o payload de uma mensagem
+func newOutgoingPayload(msgType string, data []byte) *outgoingPayload {
+	return &outgoingPayload{msgType: msgType, raw: data}
+}
+
+// sendCapabilities anuncia ao peer os recursos opcionais do nó
+func (n *Node) sendCapabilities(peer *network.Peer) {
+	data, err := json.Marshal(CapabilitiesMessage{Compression: []string{CompressionGzip}})
+	if err != nil {
+		fmt.Printf("[%s] Failed to marshal capabilities: %v\n", n.ID, err)
+		return
+	}
+	if err := peer.SendMessage("capabilities", data); err != nil {
+		fmt.Printf("[%s] Failed to send capabilities to %s: %v\n", n.ID, peer.ID, err)
+	}
+}
+
+// handleCapabilities registra os recursos opcionais anunciados pelo peer
+func (n *Node) handleCapabilities(peerID string, data []byte) {
+	var msg CapabilitiesMessage
+	if err := json.Unmarshal(data, &msg); err != nil {
+		fmt.Printf("[%s] Failed to unmarshal capabilities from %s: %v\n", n.ID, peerID, err)
+		return
+	}
+
+	supported := false
+	for _, format := range msg.Compression {
+		if format == CompressionGzip {
+			supported = true
+			break
+		}
+	}
+
+	n.compressionMutex.Lock()
+	defer n.compressionMutex.Unlock()
+	if supported {
+		n.compressionPeers[peerID] = true
+	} else {
+		delete(n.compressionPeers, peerID)
+	}
+}
+
+// PeerSupportsCompression verifica se o peer anunciou que aceita payloads comprimidos
+func (n *Node) PeerSupportsCompression(peerID string) bool {
+	n.compressionMutex.RLock()
+	defer n.compressionMutex.RUnlock()
+	return n.compressionPeers[peerID]
+}
+
+// forgetPeerCapabilities descarta os recursos anunciados por um peer desconectado
+func (n *Node) forgetPeerCapabilities(peerID string) {
+	n.compressionMutex.Lock()
+	defer n.compressionMutex.Unlock()
+	delete(n.compressionPeers, peerID)
+}
+
+// payloadFor retorna o payload a enviar ao peer: comprimido se a compressão estiver ativa no
+// nó, o peer a aceitar e ela reduzir o tamanho; caso contrário o JSON original
+func (n *Node) payloadFor(peerID string, payload *outgoingPayload) []byte {
+	if !n.compressMessages || payload.skip || !compressibleMessages[payload.msgType] ||
+		len(payload.raw) < minCompressSize || !n.PeerSupportsCompression(peerID) {
+		return payload.raw
+	}
+
+	if payload.compressed == nil {
+		compressed, err := blockchain.CompressPayload(payload.raw)
+		if err != nil || len(compressed) >= len(payload.raw) {
+			payload.skip = true
+			return payload.raw
+		}
+		payload.compressed = compressed
+		logCompressionRatio(n.ID, payload.msgType, len(payload.raw), len(compressed))
+	}
+	return payload.compressed
+}
+
+// sendPayload envia a mensagem ao peer no formato que ele aceita
+func (n *Node) sendPayload(peer *network.Peer, payload *outgoingPayload) error {
+	return peer.SendMessage(payload.msgType, n.payloadFor(peer.ID, payload))
+}
@@ -0,0 +1,11 @@
+//go:build debug
+
+package node
+
+import "fmt"
+
+// logCompressionRatio registra o ganho da compressão de cada payload (builds com -tags debug)
+func logCompressionRatio(nodeID, msgType string, rawSize, compressedSize int) {
+	fmt.Printf("[%s] 🗜️  %s compressed %d -> %d bytes (%.1f%% of original)\n",
+		nodeID, msgType, rawSize, compressedSize, float64(compressedSize)*100/float64(rawSize))
+}
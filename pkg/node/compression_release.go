@@ -0,0 +1,6 @@
+//go:build !debug
+
+package node
+
+// logCompressionRatio só registra o ganho da compressão em builds com -tags debug
+func logCompressionRatio(nodeID, msgType string, rawSize, compressedSize int) {}
@@ -0,0 +1,452 @@
+package node
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/krakovia/blockchain/pkg/blockchain"
+)
+
+// Valores padrão do download paralelo de blocos
+const (
+	DefaultParallelSyncPeers = 4                // Peers consultados ao mesmo tempo
+	DefaultDownloadTimeout   = 10 * time.Second // Prazo para um peer entregar o trecho pedido
+)
+
+// downloadChunkSize blocos pedidos a cada peer por vez (o limite de uma resposta de sync)
+const downloadChunkSize = 100
+
+// blockRange trecho de alturas [from, to] a baixar
+type blockRange struct {
+	from, to uint64
+	tried    map[string]bool // Peers que já falharam em entregar este trecho
+}
+
+// downloadAssignment trecho pedido a um peer, que deve ser entregue até deadline
+type downloadAssignment struct {
+	peerID   string
+	rng      blockRange
+	deadline time.Time
+}
+
+// downloadedBlock bloco recebido fora de ordem, com o peer que o enviou
+type downloadedBlock struct {
+	block  *blockchain.Block
+	peerID string
+}
+
+// blockDownloader divide as alturas que faltam em trechos e os distribui entre vários peers.
+// Os blocos chegam fora de ordem e ficam em received até os anteriores chegarem.
+type blockDownloader struct {
+	mu       sync.Mutex
+	maxPeers int
+	timeout  time.Duration
+	target   uint64                         // Última altura a baixar (0 = nenhum download em andamento)
+	next     uint64                         // Primeira altura ainda não atribuída a nenhum peer
+	queue    []blockRange                   // Trechos devolvidos por peers, a reatribuir
+	pending  map[string]*downloadAssignment // Trecho em download por peer (um por peer)
+	received map[uint64]downloadedBlock     // Blocos aguardando os anteriores
+	tips     map[string]uint64              // Altura da chain informada por cada peer
+
+	// stepMu serializa entrega, aplicação e reatribuição de trechos (respostas chegam em paralelo)
+	stepMu sync.Mutex
+}
+
+// newBlockDownloader cria o agendador sem download em andamento
+func newBlockDownloader(maxPeers int, timeout time.Duration) *blockDownloader {
+	if timeout <= 0 {
+		timeout = DefaultDownloadTimeout
+	}
+	return &blockDownloader{
+		maxPeers: maxPeers,
+		timeout:  timeout,
+		pending:  make(map[string]*downloadAssignment),
+		received: make(map[uint64]downloadedBlock),
+		tips:     make(map[string]uint64),
+	}
+}
+
+// start inicia o download de from até target. Com um download em andamento apenas estende o
+// alvo; retorna true se um novo download foi iniciado.
+func (d *blockDownloader) start(from, target uint64) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.target != 0 {
+		if target > d.target {
+			d.target = target
+		}
+		return false
+	}
+	d.target = target
+	d.next = from
+	return true
+}
+
+// isActive retorna se há download em andamento
+func (d *blockDownloader) isActive() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.target != 0
+}
+
+// reset encerra o download, descartando trechos pendentes e blocos recebidos
+func (d *blockDownloader) reset() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.target = 0
+	d.next = 0
+	d.queue = nil
+	d.pending = make(map[string]*downloadAssignment)
+	d.received = make(map[uint64]downloadedBlock)
+}
+
+// setTip registra a altura informada pelo peer; trechos acima dela não são pedidos a ele
+func (d *blockDownloader) setTip(peerID string, tip uint64) {
+	if tip == 0 {
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.tips[peerID] = tip
+	if d.target != 0 && tip > d.target {
+		d.target = tip
+	}
+}
+
+// assign atribui um trecho a cada peer ocioso, até maxPeers downloads simultâneos
+func (d *blockDownloader) assign(peers []string, now time.Time) []downloadAssignment {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.target == 0 {
+		return nil
+	}
+
+	var assigned []downloadAssignment
+	for _, peerID := range peers {
+		if len(d.pending) >= d.maxPeers {
+			break
+		}
+		if _, busy := d.pending[peerID]; busy {
+			continue
+		}
+		rng, ok := d.nextRangeLocked(peerID)
+		if !ok {
+			continue
+		}
+		a := &downloadAssignment{peerID: peerID, rng: rng, deadline: now.Add(d.timeout)}
+		d.pending[peerID] = a
+		assigned = append(assigned, *a)
+	}
+	return assigned
+}
+
+// nextRangeLocked escolhe o próximo trecho para o peer: primeiro os devolvidos por outros
+// peers, depois alturas ainda não atribuídas (deve ser chamado com lock)
+func (d *blockDownloader) nextRangeLocked(peerID string) (blockRange, bool) {
+	tip, tipKnown := d.tips[peerID]
+
+	for i, rng := range d.queue {
+		if tipKnown && tip < rng.from {
+			continue
+		}
+		if rng.tried[peerID] {
+			continue
+		}
+		d.queue = append(d.queue[:i], d.queue[i+1:]...)
+		return rng, true
+	}
+
+	if d.next > d.target || (tipKnown && tip < d.next) {
+		return blockRange{}, false
+	}
+	to := min(d.next+downloadChunkSize-1, d.target)
+	if tipKnown {
+		to = min(to, tip)
+	}
+	rng := blockRange{from: d.next, to: to}
+	d.next = to + 1
+	return rng, true
+}
+
+// requeueLocked devolve o restante de um trecho à fila, marcando o peer que falhou
+// (deve ser chamado com lock)
+func (d *blockDownloader) requeueLocked(rng blockRange, from uint64, failedPeer string) {
+	if from > rng.to {
+		return
+	}
+
+	tried := make(map[string]bool, len(rng.tried)+1)
+	for peerID := range rng.tried {
+		tried[peerID] = true
+	}
+	tried[failedPeer] = true
+
+	// Trechos devolvidos vão para o início da fila: são os que seguram a reordenação
+	d.queue = append([]blockRange{{from: from, to: rng.to, tried: tried}}, d.queue...)
+}
+
+// deliver registra os blocos recebidos do peer para o trecho pedido a ele. Blocos devem ser
+// consecutivos a partir do início do trecho; o que faltar volta para a fila. Retorna false
+// se não havia trecho pedido ao peer.
+func (d *blockDownloader) deliver(peerID string, blocks []*blockchain.Block) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	a, ok := d.pending[peerID]
+	if !ok {
+		return false
+	}
+	delete(d.pending, peerID)
+
+	height := a.rng.from
+	for _, block := range blocks {
+		if block.Header.Height != height || height > a.rng.to {
+			break
+		}
+		d.received[height] = downloadedBlock{block: block, peerID: peerID}
+		height++
+	}
+	d.requeueLocked(a.rng, height, peerID)
+
+	return true
+}
+
+// fail devolve à fila o trecho pedido ao peer (falha no envio ou peer desconectado)
+func (d *blockDownloader) fail(peerID string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if a, ok := d.pending[peerID]; ok {
+		delete(d.pending, peerID)
+		d.requeueLocked(a.rng, a.rng.from, peerID)
+	}
+}
+
+// removePeer devolve o trecho do peer desconectado e esquece sua altura
+func (d *blockDownloader) removePeer(peerID string) {
+	d.fail(peerID)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.tips, peerID)
+}
+
+// expire devolve à fila os trechos cujo prazo venceu e retorna os peers que não responderam
+func (d *blockDownloader) expire(now time.Time) []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var expired []string
+	for peerID, a := range d.pending {
+		if now.After(a.deadline) {
+			delete(d.pending, peerID)
+			d.requeueLocked(a.rng, a.rng.from, peerID)
+			expired = append(expired, peerID)
+		}
+	}
+	sort.Strings(expired)
+	return expired
+}
+
+// ready remove e retorna, em ordem, os blocos recebidos que continuam a ponta (tip)
+func (d *blockDownloader) ready(tip uint64) []downloadedBlock {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var blocks []downloadedBlock
+	for height := tip + 1; ; height++ {
+		b, ok := d.received[height]
+		if !ok {
+			break
+		}
+		delete(d.received, height)
+		blocks = append(blocks, b)
+	}
+	return blocks
+}
+
+// finish encerra o download se a chain alcançou o alvo. Sem nenhum trecho pendente (nenhum
+// peer capaz de entregar os que faltam), o download também é abandonado.
+func (d *blockDownloader) finish(height uint64) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.target == 0 {
+		return false
+	}
+	if height < d.target && len(d.pending) > 0 {
+		return false
+	}
+
+	d.target = 0
+	d.next = 0
+	d.queue = nil
+	d.pending = make(map[string]*downloadAssignment)
+	d.received = make(map[uint64]downloadedBlock)
+	return true
+}
+
+// continueSync segue a sincronização com um peer que tem mais blocos: com mais de um peer
+// permitido, distribui as alturas que faltam até tipHeight entre os peers conectados
+func (n *Node) continueSync(peerID string, tipHeight uint64) {
+	height := n.chain.GetHeight()
+	if n.parallelSyncPeers <= 1 || tipHeight <= height {
+		go n.sendSyncRequest(peerID, height+1)
+		return
+	}
+
+	n.downloader.stepMu.Lock()
+	defer n.downloader.stepMu.Unlock()
+
+	if n.downloader.start(height+1, tipHeight) {
+		fmt.Printf("[%s] ⬇️  Downloading blocks %d-%d from up to %d peers\n", n.ID, height+1, tipHeight, n.parallelSyncPeers)
+		n.metrics.syncing.Store(true)
+		go n.runDownload()
+	}
+	n.dispatchDownloads()
+	n.finishDownload()
+}
+
+// joinDownload inclui um peer recém-conectado no download em andamento. Retorna false se
+// não houver download em andamento.
+func (n *Node) joinDownload() bool {
+	n.downloader.stepMu.Lock()
+	defer n.downloader.stepMu.Unlock()
+
+	if !n.downloader.isActive() {
+		return false
+	}
+	n.dispatchDownloads()
+	return true
+}
+
+// syncPeerIDs retorna os IDs dos peers conectados, em ordem
+func (n *Node) syncPeerIDs() []string {
+	n.peersMutex.RLock()
+	defer n.peersMutex.RUnlock()
+
+	ids := make([]string, 0, len(n.peers))
+	for id := range n.peers {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// dispatchDownloads pede um trecho a cada peer ocioso (deve ser chamado com stepMu)
+func (n *Node) dispatchDownloads() {
+	for _, a := range n.downloader.assign(n.syncPeerIDs(), time.Now()) {
+		if err := n.sendSyncRequestRange(a.peerID, a.rng.from, a.rng.to); err != nil {
+			n.downloader.fail(a.peerID)
+		}
+	}
+}
+
+// runDownload reatribui periodicamente os trechos de peers que não responderam no prazo,
+// até o download terminar
+func (n *Node) runDownload() {
+	ticker := time.NewTicker(max(n.downloader.timeout/4, 10*time.Millisecond))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-n.ctx.Done():
+			return
+		case now := <-ticker.C:
+			if !n.downloadTick(now) {
+				return
+			}
+		}
+	}
+}
+
+// downloadTick reatribui os trechos vencidos; retorna false quando o download terminou
+func (n *Node) downloadTick(now time.Time) bool {
+	n.downloader.stepMu.Lock()
+	defer n.downloader.stepMu.Unlock()
+
+	if !n.downloader.isActive() {
+		return false
+	}
+	for _, peerID := range n.downloader.expire(now) {
+		fmt.Printf("[%s] ⏱️  Peer %s did not deliver its blocks in time, reassigning\n", n.ID, peerID)
+	}
+	n.dispatchDownloads()
+	n.finishDownload()
+	return true
+}
+
+// handleDownloadResponse processa a resposta a um trecho pedido pelo download paralelo:
+// guarda os blocos, aplica os que continuam a chain e pede os próximos trechos. Retorna
+// false se não houver download em andamento.
+func (n *Node) handleDownloadResponse(peerID string, resp *SyncResponse) bool {
+	n.downloader.stepMu.Lock()
+	defer n.downloader.stepMu.Unlock()
+
+	n.downloader.setTip(peerID, resp.TipHeight)
+	if !n.downloader.deliver(peerID, resp.Blocks) {
+		// Durante o download, respostas sem trecho pendente chegaram após o prazo: o trecho
+		// já foi pedido a outro peer
+		if n.downloader.isActive() {
+			fmt.Printf("[%s] Ignoring late sync response from %s\n", n.ID, peerID)
+			return true
+		}
+		return false
+	}
+
+	n.applyDownloadedBlocks()
+	n.dispatchDownloads()
+	n.finishDownload()
+	return true
+}
+
+// applyDownloadedBlocks aplica, em ordem, os blocos baixados que continuam a ponta da chain.
+// Um bloco inválido encerra o download (a sincronização recomeça com o próximo peer).
+// Deve ser chamado com stepMu.
+func (n *Node) applyDownloadedBlocks() {
+	ready := n.downloader.ready(n.chain.GetHeight())
+	if len(ready) == 0 {
+		return
+	}
+
+	// Aplica em sequências do mesmo peer, para atribuir rejeições a quem enviou
+	added := 0
+	for start := 0; start < len(ready); {
+		end := start
+		blocks := make([]*blockchain.Block, 0, len(ready)-start)
+		for end < len(ready) && ready[end].peerID == ready[start].peerID {
+			blocks = append(blocks, ready[end].block)
+			end++
+		}
+
+		applied := n.applySyncedBlocks(ready[start].peerID, blocks)
+		added += applied
+		if applied < len(blocks) {
+			fmt.Printf("[%s] ❌ Invalid block from %s, aborting parallel download\n", n.ID, ready[start].peerID)
+			n.downloader.reset()
+			n.metrics.syncing.Store(false)
+			break
+		}
+		start = end
+	}
+
+	if added > 0 {
+		fmt.Printf("[%s] ✨ Successfully synced %d blocks, current height: %d\n", n.ID, added, n.chain.GetHeight())
+		n.connectOrphans()
+	}
+}
+
+// finishDownload encerra o download quando a chain alcança o alvo (ou não há mais de quem
+// baixar). Deve ser chamado com stepMu.
+func (n *Node) finishDownload() {
+	if n.downloader.finish(n.chain.GetHeight()) {
+		n.metrics.syncing.Store(false)
+		fmt.Printf("[%s] ✨ Parallel download finished at height %d\n", n.ID, n.chain.GetHeight())
+	}
+}
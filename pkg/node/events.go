@@ -0,0 +1,59 @@
+package node
+
+import "github.com/krakovia/blockchain/pkg/blockchain"
+
+// Eventos enviados aos clientes de /api/ws e /api/stream/raw (no-op quando a API está desativada)
+
+// publishNewBlock publica um bloco que entrou na chain
+func (n *Node) publishNewBlock(block *blockchain.Block) {
+	if n.apiServer != nil {
+		n.apiServer.PublishNewBlock(block)
+		n.apiServer.PublishRawBlock(block)
+	}
+}
+
+// publishSyncedBlocks publica blocos aplicados pela sincronização: todos no stream bruto e só o
+// último em /api/ws, para a UI não receber um evento por bloco baixado
+func (n *Node) publishSyncedBlocks(blocks []*blockchain.Block) {
+	if n.apiServer == nil || len(blocks) == 0 {
+		return
+	}
+	for _, block := range blocks {
+		n.apiServer.PublishRawBlock(block)
+	}
+	n.apiServer.PublishNewBlock(blocks[len(blocks)-1])
+}
+
+// publishReorg publica a troca da chain principal por um fork (newTip é a nova ponta)
+func (n *Node) publishReorg(result *blockchain.ReorgResult, newTip *blockchain.Block) {
+	if n.apiServer != nil {
+		n.apiServer.PublishReorg(result, newTip)
+	}
+}
+
+// publishNewTransaction publica uma transação que entrou no mempool
+func (n *Node) publishNewTransaction(tx *blockchain.Transaction) {
+	if n.apiServer != nil {
+		n.apiServer.PublishNewTransaction(tx)
+		n.apiServer.PublishRawTransaction(tx)
+	}
+}
+
+// publishPeerChange publica a conexão ou desconexão de um peer (chamado com peersMutex)
+func (n *Node) publishPeerChange(peerID string, connected bool) {
+	if n.apiServer == nil {
+		return
+	}
+	if connected {
+		n.apiServer.PublishPeerConnected(peerID, len(n.peers))
+	} else {
+		n.apiServer.PublishPeerDisconnected(peerID, len(n.peers))
+	}
+}
+
+// publishMiningState publica o início ou a parada da mineração
+func (n *Node) publishMiningState(mining bool) {
+	if n.apiServer != nil {
+		n.apiServer.PublishMiningState(mining)
+	}
+}
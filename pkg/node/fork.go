@@ -0,0 +1,96 @@
+package node
+
+import (
+	"fmt"
+
+	"github.com/krakovia/blockchain/pkg/blockchain"
+)
+
+// considerFork decide entre a chain principal e um ramo recebido que compete com ela (pai do
+// primeiro bloco na chain principal, mas não a ponta). Fica com o fork de maior peso acumulado de
+// stake. Blocos até o último checkpoint são finais e nunca são substituídos.
+func (n *Node) considerFork(peerID string, branch []*blockchain.Block) {
+	first, block := branch[0], branch[len(branch)-1]
+
+	n.checkpointMutex.RLock()
+	checkpointHeight := n.lastCheckpointHeight
+	n.checkpointMutex.RUnlock()
+
+	if checkpointHeight > 0 && first.Header.Height <= checkpointHeight {
+		err := fmt.Errorf("fork at height %d would replace a block finalized by checkpoint %d",
+			first.Header.Height, checkpointHeight)
+		fmt.Printf("[%s] Rejected fork block %d from %s: %v\n", n.ID, block.Header.Height, peerID, err)
+		n.logRejectedBlock(peerID, "fork", block, err)
+		return
+	}
+
+	result, err := n.chain.Reorganize(branch)
+	if err != nil {
+		fmt.Printf("[%s] Rejected fork block %d from %s: %v\n", n.ID, block.Header.Height, peerID, err)
+		n.logRejectedBlock(peerID, "fork", block, err)
+		return
+	}
+
+	if !result.Reorganized {
+		fmt.Printf("[%s] Kept main chain over fork block %d from %s (weight %d vs %d)\n",
+			n.ID, block.Header.Height, peerID, result.OldWeight, result.NewWeight)
+		return
+	}
+
+	fmt.Printf("[%s] 🔀 Reorganized at height %d: %d blocks replaced, weight %d -> %d\n",
+		n.ID, result.ForkHeight, len(result.Replaced), result.OldWeight, result.NewWeight)
+
+	n.applyReorg(branch, result.Replaced)
+	n.metrics.reorgs.Inc()
+	n.publishReorg(result, block)
+	n.publishNewBlock(block)
+	n.broadcastBlockExcept(block, peerID)
+}
+
+// applyReorg atualiza disco e mempool depois de a chain principal trocar replaced por branch
+func (n *Node) applyReorg(branch, replaced []*blockchain.Block) {
+	// Blocos substituídos saem do disco antes de o fork ser gravado nas mesmas alturas
+	if n.db != nil {
+		for _, block := range replaced {
+			if err := blockchain.DeleteBlockFromDB(n.db, block); err != nil {
+				fmt.Printf("[%s] Warning: failed to delete replaced block %d: %v\n", n.ID, block.Header.Height, err)
+			}
+		}
+	}
+	if err := n.saveBlocks(branch); err != nil {
+		fmt.Printf("[%s] Warning: failed to save fork blocks: %v\n", n.ID, err)
+	}
+
+	// Transações do fork saem do mempool; as dos blocos substituídos voltam, se ainda válidas
+	included := make(map[string]bool)
+	txIDs := make([]string, 0)
+	for _, block := range branch {
+		for _, tx := range block.Transactions {
+			if !tx.IsCoinbase() {
+				included[tx.ID] = true
+				txIDs = append(txIDs, tx.ID)
+			}
+		}
+	}
+	n.mempool.RemoveTransactions(txIDs)
+
+	restored := 0
+	for _, block := range replaced {
+		for _, tx := range block.Transactions {
+			if tx.IsCoinbase() || included[tx.ID] {
+				continue
+			}
+			if err := n.mempool.AddTransaction(tx); err == nil {
+				restored++
+			}
+		}
+	}
+	if restored > 0 {
+		fmt.Printf("[%s] Returned %d transactions from replaced blocks to the mempool\n", n.ID, restored)
+	}
+}
+
+// GetReorgCount retorna quantas vezes a chain principal foi trocada por um fork mais pesado
+func (n *Node) GetReorgCount() uint64 {
+	return n.metrics.reorgs.Value()
+}
@@ -0,0 +1,284 @@
+package node
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/krakovia/blockchain/pkg/blockchain"
+)
+
+// maxHeadersPerResponse limita os headers enviados por resposta. Headers são pequenos; os
+// corpos continuam limitados a 100 blocos por resposta de sync.
+const maxHeadersPerResponse = 500
+
+// HeadersRequest mensagem de requisição de headers (sincronização headers-first)
+type HeadersRequest struct {
+	FromHeight uint64 `json:"from_height"`
+}
+
+// HeadersResponse mensagem de resposta com headers consecutivos a partir da altura pedida
+type HeadersResponse struct {
+	Headers []blockchain.BlockHeader `json:"headers"`
+	HasMore bool                     `json:"has_more,omitempty"` // Há headers além dos enviados
+}
+
+// headerSyncState sincronização headers-first em andamento: headers já validados de um peer
+// cujos corpos ainda não foram aplicados
+type headerSyncState struct {
+	mu       sync.Mutex
+	peerID   string
+	hashes   map[uint64]string      // Hash validado por altura, até o corpo ser aplicado
+	last     blockchain.BlockHeader // Último header validado (âncora do próximo lote)
+	lastHash string
+	hasMore  bool // O peer tem headers além do último validado
+}
+
+// newHeaderSyncState cria o estado vazio (nenhuma sincronização por headers em andamento)
+func newHeaderSyncState() *headerSyncState {
+	return &headerSyncState{hashes: make(map[uint64]string)}
+}
+
+// isActive retorna se há sincronização por headers em andamento com o peer
+func (s *headerSyncState) isActive(peerID string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.isActiveLocked(peerID)
+}
+
+// isActiveLocked implementa isActive (deve ser chamado com lock)
+func (s *headerSyncState) isActiveLocked(peerID string) bool {
+	return s.lastHash != "" && s.peerID == peerID
+}
+
+// reset encerra a sincronização por headers
+func (s *headerSyncState) reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.peerID = ""
+	s.hashes = make(map[uint64]string)
+	s.last = blockchain.BlockHeader{}
+	s.lastHash = ""
+	s.hasMore = false
+}
+
+// anchor retorna o último header validado do peer se o lote que começa em firstHeight o continua
+func (s *headerSyncState) anchor(peerID string, firstHeight uint64) (blockchain.BlockHeader, string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.isActiveLocked(peerID) || s.last.Height+1 != firstHeight {
+		return blockchain.BlockHeader{}, "", false
+	}
+	return s.last, s.lastHash, true
+}
+
+// accept registra headers validados do peer
+func (s *headerSyncState) accept(peerID string, headers []blockchain.BlockHeader, hashes []string, hasMore bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.peerID != peerID {
+		s.hashes = make(map[uint64]string)
+	}
+	s.peerID = peerID
+	for i, header := range headers {
+		s.hashes[header.Height] = hashes[i]
+	}
+	s.last = headers[len(headers)-1]
+	s.lastHash = hashes[len(hashes)-1]
+	s.hasMore = hasMore
+}
+
+// firstMismatch retorna o índice do primeiro bloco do peer que difere do header validado na
+// mesma altura (-1 se todos correspondem)
+func (s *headerSyncState) firstMismatch(peerID string, blocks []*blockchain.Block) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.isActiveLocked(peerID) {
+		return -1
+	}
+	for i, block := range blocks {
+		if expected, ok := s.hashes[block.Header.Height]; ok && expected != block.Hash {
+			return i
+		}
+	}
+	return -1
+}
+
+// progress descarta os headers cujos corpos já estão na chain (até height) e retorna a altura
+// do último header validado e se o peer tem mais headers
+func (s *headerSyncState) progress(peerID string, height uint64) (uint64, bool, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.isActiveLocked(peerID) {
+		return 0, false, false
+	}
+	for h := range s.hashes {
+		if h <= height {
+			delete(s.hashes, h)
+		}
+	}
+	return s.last.Height, s.hasMore, true
+}
+
+// GetHeadersValidated retorna quantos headers recebidos na sincronização headers-first foram validados
+func (n *Node) GetHeadersValidated() uint64 {
+	return n.metrics.headersValidated.Value()
+}
+
+// requestHeaders pede ao peer os headers a partir de fromHeight. Os corpos só são pedidos
+// depois que os headers são validados (handleHeadersResponse).
+func (n *Node) requestHeaders(peerID string, fromHeight uint64) {
+	n.peersMutex.RLock()
+	peer := n.peers[peerID]
+	n.peersMutex.RUnlock()
+
+	if peer == nil {
+		fmt.Printf("[%s] Peer %s not found for headers sync\n", n.ID, peerID)
+		return
+	}
+
+	data, err := json.Marshal(HeadersRequest{FromHeight: fromHeight})
+	if err != nil {
+		fmt.Printf("[%s] Failed to marshal headers request: %v\n", n.ID, err)
+		return
+	}
+
+	if err := peer.SendMessage("headers_request", data); err != nil {
+		fmt.Printf("[%s] Failed to send headers request to %s: %v\n", n.ID, peerID, err)
+	} else {
+		n.metrics.syncing.Store(true)
+		fmt.Printf("[%s] 📤 Requested headers from %s (from height %d)\n", n.ID, peerID, fromHeight)
+	}
+}
+
+// handleHeadersRequest processa uma requisição de headers
+func (n *Node) handleHeadersRequest(peerID string, data []byte) {
+	var req HeadersRequest
+	if err := json.Unmarshal(data, &req); err != nil {
+		fmt.Printf("[%s] Failed to parse headers request from %s: %v\n", n.ID, peerID, err)
+		return
+	}
+
+	response := n.BuildHeadersResponse(req.FromHeight)
+	responseData, err := json.Marshal(response)
+	if err != nil {
+		fmt.Printf("[%s] Failed to marshal headers response: %v\n", n.ID, err)
+		return
+	}
+
+	n.peersMutex.RLock()
+	peer := n.peers[peerID]
+	n.peersMutex.RUnlock()
+
+	if peer == nil {
+		fmt.Printf("[%s] ❌ Peer %s not found, cannot send headers response\n", n.ID, peerID)
+		return
+	}
+	if err := n.sendPayload(peer, newOutgoingPayload("headers_response", responseData)); err != nil {
+		fmt.Printf("[%s] ❌ Failed to send headers response to %s: %v\n", n.ID, peerID, err)
+	} else {
+		fmt.Printf("[%s] ✅ Sent %d headers to %s (from height %d)\n", n.ID, len(response.Headers), peerID, req.FromHeight)
+	}
+}
+
+// BuildHeadersResponse monta a resposta a uma requisição de headers a partir de fromHeight
+// (no máximo maxHeadersPerResponse). HasMore indica que o requisitante deve pedir os seguintes.
+func (n *Node) BuildHeadersResponse(fromHeight uint64) HeadersResponse {
+	currentHeight := n.chain.GetHeight()
+	if fromHeight == 0 || fromHeight > currentHeight {
+		return HeadersResponse{Headers: []blockchain.BlockHeader{}}
+	}
+
+	toHeight := fromHeight + maxHeadersPerResponse - 1
+	if toHeight > currentHeight {
+		toHeight = currentHeight
+	}
+
+	blocks := n.collectBlockRange(fromHeight, toHeight)
+	headers := make([]blockchain.BlockHeader, len(blocks))
+	for i, block := range blocks {
+		headers[i] = block.Header
+	}
+
+	response := HeadersResponse{Headers: headers}
+	if len(headers) > 0 && headers[len(headers)-1].Height < currentHeight {
+		response.HasMore = true
+	}
+
+	return response
+}
+
+// handleHeadersResponse valida os headers recebidos (assinaturas e encadeamento) e só então
+// pede os corpos dos blocos. Uma chain inválida é detectada sem baixar nenhum corpo.
+func (n *Node) handleHeadersResponse(peerID string, data []byte) {
+	var resp HeadersResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		fmt.Printf("[%s] Failed to parse headers response from %s: %v\n", n.ID, peerID, err)
+		return
+	}
+
+	if len(resp.Headers) == 0 {
+		fmt.Printf("[%s] ℹ️  No new headers from %s\n", n.ID, peerID)
+		n.metrics.syncing.Store(false)
+		return
+	}
+
+	// Os headers continuam o último lote validado deste peer ou a ponta da chain
+	first, last := resp.Headers[0].Height, resp.Headers[len(resp.Headers)-1].Height
+	anchor, anchorHash, ok := n.headerSync.anchor(peerID, first)
+	if !ok {
+		tip := n.chain.GetLastBlock()
+		anchor, anchorHash = tip.Header, tip.Hash
+	}
+
+	hashes, err := blockchain.VerifyHeaderChain(anchor, anchorHash, resp.Headers, n.chain.GetValidators(), n.chain.GetConfig(), n.chain.Now())
+	var unknown *blockchain.UnknownValidatorError
+	if errors.As(err, &unknown) && unknown.Index > 0 {
+		// O validador pode ter entrado em stake nos headers anteriores: aplica esse prefixo e
+		// valida o resto no próximo lote, já com o estado atualizado
+		fmt.Printf("[%s] ⏸️  Headers from %s stop at height %d: %v\n", n.ID, peerID, unknown.Height, err)
+		resp.Headers, resp.HasMore, err = resp.Headers[:unknown.Index], true, nil
+		last = resp.Headers[len(resp.Headers)-1].Height
+	}
+	if err != nil {
+		fmt.Printf("[%s] ❌ Rejecting headers %d-%d from %s, bodies not requested: %v\n", n.ID, first, last, peerID, err)
+		n.headerSync.reset()
+		n.metrics.syncing.Store(false)
+		return
+	}
+
+	n.headerSync.accept(peerID, resp.Headers, hashes, resp.HasMore)
+	n.metrics.headersValidated.Add(uint64(len(hashes)))
+	fmt.Printf("[%s] ✅ Validated %d headers from %s (height %d-%d)\n", n.ID, len(hashes), peerID, first, last)
+
+	// Pede os corpos dos headers aceitos
+	go n.sendSyncRequestRange(peerID, n.chain.GetHeight()+1, last)
+}
+
+// continueHeaderSync segue a sincronização por headers após aplicar corpos: pede os corpos
+// restantes dos headers validados ou o próximo lote de headers. Retorna false se não houver
+// sincronização por headers em andamento com o peer.
+func (n *Node) continueHeaderSync(peerID string) bool {
+	height := n.chain.GetHeight()
+	lastHeader, hasMore, active := n.headerSync.progress(peerID, height)
+	if !active {
+		return false
+	}
+
+	switch {
+	case height < lastHeader:
+		go n.sendSyncRequestRange(peerID, height+1, lastHeader)
+	case hasMore:
+		go n.requestHeaders(peerID, height+1)
+	default:
+		n.headerSync.reset()
+		n.metrics.syncing.Store(false)
+		fmt.Printf("[%s] ✨ Headers-first sync with %s complete at height %d\n", n.ID, peerID, height)
+	}
+	return true
+}
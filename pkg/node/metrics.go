@@ -0,0 +1,59 @@
+package node
+
+import (
+	"sync/atomic"
+
+	"github.com/krakovia/blockchain/pkg/metrics"
+)
+
+// nodeMetrics métricas do nó expostas em /metrics. Gauges são lidos do estado do nó na coleta;
+// contadores são incrementados quando o evento acontece.
+type nodeMetrics struct {
+	registry         *metrics.Registry
+	blocksMined      *metrics.Counter
+	headersValidated *metrics.Counter
+	reorgs           *metrics.Counter
+	syncing          atomic.Bool
+}
+
+// newNodeMetrics registra as métricas do nó
+func newNodeMetrics(n *Node) *nodeMetrics {
+	m := &nodeMetrics{registry: metrics.NewRegistry()}
+
+	m.registry.NewGaugeFunc("krakovia_chain_height", "Altura atual da blockchain", func() float64 {
+		return float64(n.chain.GetHeight())
+	})
+	m.registry.NewGaugeFunc("krakovia_mempool_size", "Transações pendentes no mempool", func() float64 {
+		return float64(n.mempool.Size())
+	})
+	m.registry.NewGaugeFunc("krakovia_peers", "Peers conectados", func() float64 {
+		n.peersMutex.RLock()
+		defer n.peersMutex.RUnlock()
+		return float64(len(n.peers))
+	})
+	m.registry.NewGaugeFunc("krakovia_syncing", "1 enquanto o nó baixa blocos de um peer", func() float64 {
+		if m.syncing.Load() {
+			return 1
+		}
+		return 0
+	})
+	m.registry.NewGaugeFunc("krakovia_last_block_timestamp_seconds", "Timestamp do último bloco da chain", func() float64 {
+		if last := n.chain.GetLastBlock(); last != nil {
+			return float64(last.Header.Timestamp)
+		}
+		return 0
+	})
+	m.registry.NewGaugeFunc("krakovia_orphan_blocks", "Blocos de gossip aguardando a chain alcançá-los", func() float64 {
+		return float64(n.orphans.size())
+	})
+	m.blocksMined = m.registry.NewCounter("krakovia_blocks_mined_total", "Blocos minerados por este nó")
+	m.headersValidated = m.registry.NewCounter("krakovia_headers_validated_total", "Headers validados na sincronização headers-first")
+	m.reorgs = m.registry.NewCounter("krakovia_reorgs_total", "Reorganizações para um fork mais pesado")
+
+	return m
+}
+
+// GetMetrics retorna o registro de métricas do nó
+func (n *Node) GetMetrics() *metrics.Registry {
+	return n.metrics.registry
+}
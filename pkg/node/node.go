@@ -3,6 +3,7 @@ package node
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"sync"
 	"time"
@@ -34,9 +35,11 @@ type Node struct {
 	mempool *blockchain.Mempool
 	miner   *blockchain.Miner
 
-	// Controle de mineração
-	mining   bool
-	stopMine chan struct{}
+	// Controle de mineração (protegido por miningMutex)
+	miningMutex sync.Mutex
+	mining      bool
+	stopMine    chan struct{}
+	mineDone    chan struct{} // Fechado quando o MineLoop atual termina
 
 	// Checkpoint
 	checkpointConfig     *config.CheckpointConfig
@@ -44,28 +47,142 @@ type Node struct {
 	lastCheckpointHeight uint64
 	checkpointMutex      sync.RWMutex
 
+	// Pruning de blocos independente dos checkpoints (ver pruning.go)
+	pruneConfig      *config.PruneConfig
+	pruneMutex       sync.Mutex
+	diskPrunedHeight uint64 // Blocos até esta altura já saíram do disco (o gênesis nunca sai)
+
+	// Endossos de checkpoints: serializa leitura/gravação das assinaturas e guarda as
+	// recebidas antes de o checkpoint ser criado localmente
+	checkpointSigMutex    sync.Mutex
+	pendingCheckpointSigs map[uint64][]CheckpointSignatureMessage
+
+	// Persistência de blocos (com retry)
+	blockSaver *blockSaver
+
+	// Leitura de blocos do disco e limite de tempo para montar respostas de sync
+	blockLoader         BlockLoader
+	blockLoaderMutex    sync.RWMutex
+	syncAssemblyTimeout time.Duration
+	syncBatchSize       int
+
+	// Blocos de gossip à frente da ponta aguardando a chain alcançá-los
+	orphans         *orphanPool
+	maxFutureBlocks uint64
+
+	// Busca por hash dos pais desconhecidos de blocos recebidos (get_block)
+	blockFetches     *blockFetcher
+	sendGetBlockHook func(peerID string, req GetBlockRequest) error
+
+	// Sincronização headers-first (valida headers antes de baixar os corpos)
+	headersFirstSync bool
+	headerSync       *headerSyncState
+
+	// Download paralelo de blocos de vários peers
+	downloader          *blockDownloader
+	parallelSyncPeers   int
+	sendSyncRequestHook func(peerID string, req SyncRequest) error
+
+	// Compressão de payloads para peers que anunciaram suporte
+	compressMessages bool
+	compressionPeers map[string]bool
+	compressionMutex sync.RWMutex
+
+	// Rate limit de mensagens recebidas (nil = desativado)
+	rateLimiter       *network.TokenBucketLimiter
+	rateLimitMaxDrops int
+
+	// Evidências de assinatura dupla detectadas ou recebidas, por validador e altura (ver slashing.go)
+	slashEvidence      map[string]*blockchain.SlashingEvidence
+	slashEvidenceMutex sync.Mutex
+
+	// Log de blocos e transações rejeitados (nil = desativado)
+	rejectLog *RejectLog
+
+	// Métricas expostas em /metrics
+	metrics *nodeMetrics
+
 	// API HTTP
 	apiServer *api.Server
 }
 
 // Config contém as configurações para criar um nó
 type Config struct {
-	ID                string
+	ID                string // Opcional: derivado da carteira; se informado deve ser o endereço dela
 	Address           string
 	DBPath            string
 	SignalingServer   string
 	MaxPeers          int
 	MinPeers          int
 	DiscoveryInterval int // em segundos
+	MaxParallelDials  int // Conexões de saída simultâneas (0 = padrão)
+
+	// Servidores STUN/TURN das conexões WebRTC (vazio = network.DefaultICEServers)
+	ICEServers []network.ICEServer
+
+	// Idade máxima dos peers salvos no último Stop para tentar reconectar ao iniciar
+	// (0 = network.DefaultPeerMaxAge)
+	PeerMaxAge time.Duration
+
+	// Limites de banda das respostas de sync, headers e checkpoints (zero = sem limite)
+	Bandwidth network.BandwidthLimit
 
 	// Configurações blockchain
 	Wallet           *wallet.Wallet
 	GenesisBlock     *blockchain.Block
+	GenesisHash      string // Hash esperado do gênesis (genesis.hash da config); vazio = sem verificação
 	ChainConfig      blockchain.ChainConfig
 	CheckpointConfig *config.CheckpointConfig
+	PruneConfig      *config.PruneConfig // Profundidade de pruning dos blocos (nil = só nos checkpoints)
 	APIConfig        *config.APIConfig
 	InitialStake     uint64 // Stake inicial (0 = sem stake inicial)
 	InitialStakeAddr string // Endereço que receberá o stake inicial
+
+	// Persistência
+	BlockSaveRetries int           // Tentativas extras ao falhar ao salvar bloco (0 = padrão, <0 = nenhuma)
+	BlockSaveBackoff time.Duration // Espera inicial entre tentativas, dobra a cada falha (0 = padrão)
+	CompactOnStartup bool          // Compactar o LevelDB ao iniciar (respeitando CompactInterval)
+	CompactInterval  time.Duration // Intervalo mínimo entre compactações (0 = padrão)
+
+	// WrapCompactor permite envolver o compactador do banco (opcional, usado em testes)
+	WrapCompactor func(DBCompactor) DBCompactor
+
+	// Sync
+	SyncAssemblyTimeout time.Duration // Tempo máximo para montar uma resposta de sync (0 = padrão)
+	SyncBatchSize       int           // Blocos recebidos aplicados e gravados por lote (0 = padrão, 1 = um a um)
+	MaxFutureBlocks     uint64        // Alturas à frente da ponta que um bloco de gossip pode estar para virar órfão (0 = padrão)
+	HeadersFirstSync    bool          // Sincroniza validando os headers antes de baixar os corpos dos blocos
+	ParallelSyncPeers   int           // Peers dos quais trechos de blocos são baixados ao mesmo tempo (0 = padrão, 1 = um peer por vez)
+	DownloadTimeout     time.Duration // Tempo para um peer entregar o trecho pedido antes de ele ir para outro peer (0 = padrão)
+
+	// SendSyncRequest substitui o envio de requisições de blocos aos peers (opcional, usado em testes)
+	SendSyncRequest func(peerID string, req SyncRequest) error
+	// SendGetBlock substitui o envio de get_block aos peers (opcional, usado em testes)
+	SendGetBlock func(peerID string, req GetBlockRequest) error
+
+	// Comprime blocos, transações e respostas de sync enviados a peers que também comprimem
+	CompressMessages bool
+
+	// Filtro de remetentes (deployments permissionados)
+	SenderAllowlist []string // Só transações destes remetentes entram nos blocos (vazio = todos)
+	SenderDenylist  []string // Transações destes remetentes nunca entram nos blocos
+	FilterMempool   bool     // Aplica o filtro também na admissão ao mempool
+
+	// Aumento mínimo da fee (%) para substituir uma transação pendente (0 = blockchain.DefaultMinBumpPercent)
+	MempoolMinBumpPercent uint64
+
+	// Máximo de transações pendentes; cheio, a de menor fee é despejada por outra que pague mais (0 = padrão)
+	MempoolMaxSize int
+
+	// Rate limit de mensagens recebidas, por peer e tipo de mensagem
+	MessageRateLimits map[string]network.TokenBucketLimit // Sobrescreve os limites padrão por tipo (Rate <= 0 remove o limite)
+	DisableRateLimit  bool                                // Não limita mensagens recebidas
+	RateLimitMaxDrops int                                 // Desconecta o peer após N mensagens descartadas (0 = apenas descarta)
+
+	// Log de rejeições (análise forense de ataques)
+	RejectLogPath     string // Arquivo JSONL de blocos e transações rejeitados (vazio = desativado)
+	RejectLogMaxSize  int64  // Tamanho máximo do arquivo antes de rotacionar, em bytes (0 = padrão)
+	RejectLogMaxFiles int    // Arquivos rotacionados mantidos (0 = padrão)
 }
 
 // NewNode cria uma nova instância de nó
@@ -78,6 +195,29 @@ func NewNode(config Config) (*Node, error) {
 		return nil, fmt.Errorf("genesis block is required")
 	}
 
+	// Recalcula o hash do gênesis: um timestamp ou alocação diferente da config geraria
+	// silenciosamente outra chain, incompatível com a dos peers
+	if config.GenesisHash != "" {
+		genesisHash, err := config.GenesisBlock.CalculateHash()
+		if err != nil {
+			return nil, fmt.Errorf("failed to calculate genesis hash: %w", err)
+		}
+		if genesisHash != config.GenesisHash {
+			return nil, fmt.Errorf("genesis hash mismatch: computed %s but config expects %s (check genesis timestamp and allocations)", genesisHash, config.GenesisHash)
+		}
+	}
+
+	// O ID do nó é o endereço da carteira (derivado da chave pública) e é provado aos peers
+	// no handshake, para que nenhum nó possa se apresentar com o ID de outro
+	nodeID, err := network.PeerIDFromPublicKey(config.Wallet.GetPublicKeyHex())
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive node ID: %w", err)
+	}
+	if config.ID != "" && config.ID != nodeID {
+		return nil, fmt.Errorf("node ID %s does not match wallet address %s", config.ID, nodeID)
+	}
+	config.ID = nodeID
+
 	// Abrir banco de dados LevelDB
 	db, err := leveldb.OpenFile(config.DBPath, nil)
 	if err != nil {
@@ -105,6 +245,16 @@ func NewNode(config Config) (*Node, error) {
 
 	// Criar sistema de descoberta de peers
 	discovery := network.NewPeerDiscovery(config.ID, config.MaxPeers, config.MinPeers)
+	if config.MaxParallelDials > 0 {
+		discovery.SetMaxParallelDials(config.MaxParallelDials)
+	}
+
+	// Recarregar os peers conhecidos no último Stop; Start tenta reconectar a eles
+	if loaded, err := discovery.LoadPeers(db, config.PeerMaxAge); err != nil {
+		fmt.Printf("[%s] Warning: failed to load known peers from disk: %v\n", config.ID, err)
+	} else if loaded > 0 {
+		fmt.Printf("[%s] 📥 Restored %d known peers from disk\n", config.ID, loaded)
+	}
 
 	// Inicializar blockchain com stake inicial se fornecido
 	var chain *blockchain.Chain
@@ -121,12 +271,48 @@ func NewNode(config Config) (*Node, error) {
 		return nil, fmt.Errorf("failed to create chain: %w", err)
 	}
 
+	// Log de rejeições (opcional)
+	var rejectLog *RejectLog
+	if config.RejectLogPath != "" {
+		rejectLog, err = OpenRejectLog(config.RejectLogPath, config.RejectLogMaxSize, config.RejectLogMaxFiles)
+		if err != nil {
+			if closeErr := db.Close(); closeErr != nil {
+				fmt.Printf("Warning: failed to close DB: %v\n", closeErr)
+			}
+			cancel()
+			return nil, err
+		}
+	}
+
+	if err := chain.SetDB(db); err != nil {
+		fmt.Printf("[%s] Warning: failed to index chain addresses: %v\n", config.ID, err)
+	}
+
 	// Criar mempool
-	mempool := blockchain.NewMempool()
+	mempoolConfig := blockchain.DefaultMempoolConfig()
+	mempoolConfig.Consensus = chainConfig.Consensus
+	mempoolConfig.MinFee = chainConfig.MinFee
+	if config.MempoolMinBumpPercent > 0 {
+		mempoolConfig.MinBumpPercent = config.MempoolMinBumpPercent
+	}
+	if config.MempoolMaxSize > 0 {
+		mempoolConfig.MaxSize = config.MempoolMaxSize
+	}
+	mempool := blockchain.NewMempoolWithConfig(mempoolConfig)
+	mempool.SetNonceSource(chain.GetNonce)
 
 	// Criar minerador
 	miner := blockchain.NewMiner(config.Wallet, chain, mempool)
 
+	// Filtro de remetentes (opcional)
+	if len(config.SenderAllowlist) > 0 || len(config.SenderDenylist) > 0 {
+		filter := blockchain.NewSenderFilter(config.SenderAllowlist, config.SenderDenylist)
+		miner.SetSenderFilter(filter)
+		if config.FilterMempool {
+			mempool.SetSenderFilter(filter)
+		}
+	}
+
 	node := &Node{
 		ID:                config.ID,
 		Address:           config.Address,
@@ -141,6 +327,61 @@ func NewNode(config Config) (*Node, error) {
 		mempool:           mempool,
 		miner:             miner,
 		checkpointConfig:  config.CheckpointConfig,
+		pruneConfig:       config.PruneConfig,
+
+		pendingCheckpointSigs: make(map[uint64][]CheckpointSignatureMessage),
+		slashEvidence:         make(map[string]*blockchain.SlashingEvidence),
+	}
+
+	node.metrics = newNodeMetrics(node)
+	node.blockSaver = newBlockSaver(&levelDBBlockStore{db: db}, config.BlockSaveRetries, config.BlockSaveBackoff)
+	node.blockLoader = &levelDBBlockStore{db: db}
+	node.syncAssemblyTimeout = config.SyncAssemblyTimeout
+	if node.syncAssemblyTimeout <= 0 {
+		node.syncAssemblyTimeout = DefaultSyncAssemblyTimeout
+	}
+	node.syncBatchSize = config.SyncBatchSize
+	if node.syncBatchSize <= 0 {
+		node.syncBatchSize = DefaultSyncBatchSize
+	}
+	node.orphans = newOrphanPool()
+	node.blockFetches = newBlockFetcher()
+	node.sendGetBlockHook = config.SendGetBlock
+	node.maxFutureBlocks = config.MaxFutureBlocks
+	if node.maxFutureBlocks == 0 {
+		node.maxFutureBlocks = DefaultMaxFutureBlocks
+	}
+	node.headersFirstSync = config.HeadersFirstSync
+	node.headerSync = newHeaderSyncState()
+	node.parallelSyncPeers = config.ParallelSyncPeers
+	if node.parallelSyncPeers <= 0 {
+		node.parallelSyncPeers = DefaultParallelSyncPeers
+	}
+	node.downloader = newBlockDownloader(node.parallelSyncPeers, config.DownloadTimeout)
+	node.sendSyncRequestHook = config.SendSyncRequest
+	node.compressMessages = config.CompressMessages
+	node.compressionPeers = make(map[string]bool)
+	node.rejectLog = rejectLog
+
+	// Rate limit de mensagens recebidas: limites padrão sobrescritos pelos configurados
+	if !config.DisableRateLimit {
+		limits := network.DefaultMessageRateLimits()
+		for msgType, limit := range config.MessageRateLimits {
+			limits[msgType] = limit
+		}
+		node.rateLimiter = network.NewTokenBucketLimiter(limits)
+		node.rateLimitMaxDrops = config.RateLimitMaxDrops
+	}
+
+	// Compactar o banco antes de carregar a chain (no máximo uma vez por CompactInterval)
+	if config.CompactOnStartup {
+		var compactor DBCompactor = db
+		if config.WrapCompactor != nil {
+			compactor = config.WrapCompactor(compactor)
+		}
+		if _, err := node.compactOnStartup(compactor, config.CompactInterval); err != nil {
+			fmt.Printf("[%s] Warning: %v\n", config.ID, err)
+		}
 	}
 
 	// Carregar blockchain existente do disco
@@ -153,29 +394,57 @@ func NewNode(config Config) (*Node, error) {
 		node.loadLastCheckpoint()
 	}
 
+	// Recarregar transações pendentes salvas no último Stop, descartando as já mineradas
+	if err := mempool.LoadFromDB(db); err != nil {
+		fmt.Printf("[%s] Warning: failed to load mempool from disk: %v\n", config.ID, err)
+	} else if mempool.Size() > 0 {
+		removed := mempool.RemoveStaleTransactions(node.chain.GetContext())
+		fmt.Printf("[%s] 📥 Restored %d pending transactions from disk (%d stale discarded)\n", config.ID, mempool.Size(), removed)
+	}
+
+	// Informar a altura atual ao mempool, descartando transações restauradas já expiradas
+	if removed := mempool.RemoveExpiredTransactions(node.chain.GetHeight()); removed > 0 {
+		fmt.Printf("[%s] Discarded %d expired pending transactions\n", config.ID, removed)
+	}
+
 	// Configurar callbacks do minerador para broadcast via rede
 	miner.SetOnBlockCreated(func(block *blockchain.Block) {
 		// Adicionar checkpoint hash ao bloco se disponível
 		node.addCheckpointHashToBlock(block)
 		// Salvar bloco no disco
-		if err := blockchain.SaveBlockToDB(node.db, block); err != nil {
+		if err := node.saveBlock(block); err != nil {
 			fmt.Printf("[%s] ⚠️  Warning: failed to save mined block %d to disk: %v\n", node.ID, block.Header.Height, err)
 		} else {
 			fmt.Printf("[%s] 💾 Mined block %d saved to disk successfully\n", node.ID, block.Header.Height)
 		}
-		// Tentar criar checkpoint se necessário
-		node.tryCreateCheckpoint(block.Header.Height)
 		// Broadcast do bloco
 		node.broadcastBlock(block)
 	})
 
+	// Checkpoint só depois que o bloco entra na chain, para capturar o estado da altura correta
+	miner.SetOnBlockAdded(func(block *blockchain.Block) {
+		node.metrics.blocksMined.Inc()
+		node.tryCreateCheckpoint(block.Header.Height)
+		node.pruneAfterBlock(block.Header.Height)
+		node.publishNewBlock(block)
+	})
+
 	miner.SetOnTxCreated(func(tx *blockchain.Transaction) {
 		node.broadcastTransaction(tx)
 	})
 
+	// Assinaturas duplas detectadas são repassadas aos peers e viram transações de denúncia, que
+	// punem o validador em um bloco
+	chain.SetOnDoubleSign(node.reportDoubleSign)
+
 	// Inicializar cliente WebRTC com sistema de descoberta
 	webRTCClient, err := network.NewWebRTCClientWithDiscovery(config.ID, config.SignalingServer, node, discovery)
 	if err != nil {
+		if rejectLog != nil {
+			if closeErr := rejectLog.Close(); closeErr != nil {
+				fmt.Printf("Warning: failed to close reject log: %v\n", closeErr)
+			}
+		}
 		if closeErr := db.Close(); closeErr != nil {
 			fmt.Printf("Warning: failed to close DB: %v\n", closeErr)
 		}
@@ -183,6 +452,9 @@ func NewNode(config Config) (*Node, error) {
 		return nil, fmt.Errorf("failed to create WebRTC client: %w", err)
 	}
 
+	webRTCClient.SetIdentity(config.Wallet)
+	webRTCClient.SetICEServers(config.ICEServers)
+	webRTCClient.SetBandwidthLimit(config.Bandwidth)
 	node.webRTC = webRTCClient
 
 	// Registrar handlers de mensagens
@@ -195,10 +467,19 @@ func NewNode(config Config) (*Node, error) {
 			Address:  config.APIConfig.Address,
 			Username: config.APIConfig.Username,
 			Password: config.APIConfig.Password,
+
+			MetricsEnabled: config.APIConfig.MetricsEnabled,
+
+			BasicAuth:   config.APIConfig.BasicAuth,
+			TokenSecret: config.APIConfig.TokenSecret,
+			TokenTTL:    time.Duration(config.APIConfig.TokenTTL) * time.Second,
+			TLSCertFile: config.APIConfig.TLSCertFile,
+			TLSKeyFile:  config.APIConfig.TLSKeyFile,
 		}
 		// Criar wrapper para o node
 		nodeWrapper := api.NewNodeWrapper(node)
 		node.apiServer = api.NewServer(nodeWrapper, apiConfig)
+		node.apiServer.SetMetricsHandler(node.metrics.registry.Handler())
 	}
 
 	return node, nil
@@ -213,6 +494,10 @@ func (n *Node) Start() error {
 		return fmt.Errorf("failed to connect to signaling server: %w", err)
 	}
 
+	// Reconectar primeiro aos peers vistos recentemente; a lista do signaling completa o
+	// que faltar (peers já em conexão são ignorados por ela)
+	go n.webRTC.ReconnectKnownPeers()
+
 	// Iniciar goroutine de descoberta periódica
 	go n.discoveryLoop()
 
@@ -290,7 +575,28 @@ func (n *Node) Stop() error {
 		n.webRTC.Close()
 	}
 
+	if n.rejectLog != nil {
+		if err := n.rejectLog.Close(); err != nil {
+			fmt.Printf("[%s] Warning: failed to close reject log: %v\n", n.ID, err)
+		}
+	}
+
 	if n.db != nil {
+		// Persistir transações pendentes para recarregar no próximo início
+		if err := n.mempool.SaveToDB(n.db); err != nil {
+			fmt.Printf("[%s] Warning: failed to save mempool: %v\n", n.ID, err)
+		}
+
+		// Persistir os peers conhecidos para reconectar a eles no próximo início
+		if err := n.discovery.SavePeers(n.db); err != nil {
+			fmt.Printf("[%s] Warning: failed to save known peers: %v\n", n.ID, err)
+		}
+
+		// Gravar a ponta da chain de forma síncrona para o próximo início carregar todos os blocos
+		if err := n.flushChainTip(); err != nil {
+			fmt.Printf("[%s] Warning: failed to flush chain tip: %v\n", n.ID, err)
+		}
+
 		if err := n.db.Close(); err != nil {
 			return fmt.Errorf("failed to close database: %w", err)
 		}
@@ -312,6 +618,7 @@ func (n *Node) AddPeer(peer *network.Peer) {
 	}
 
 	fmt.Printf("🔗 Peer %s connected to node %s\n", peer.ID, n.ID)
+	n.publishPeerChange(peer.ID, true)
 
 	// Solicita sincronização com o peer
 	go n.requestSync(peer.ID)
@@ -323,7 +630,14 @@ func (n *Node) RemovePeer(peerID string) {
 	defer n.peersMutex.Unlock()
 	delete(n.peers, peerID)
 	n.discovery.MarkPeerDisconnected(peerID)
+	n.downloader.removePeer(peerID)
+	n.forgetPeerCapabilities(peerID)
+	n.chain.Clock().RemoveSample(peerID)
+	if n.rateLimiter != nil {
+		n.rateLimiter.RemovePeer(peerID)
+	}
 	fmt.Printf("Peer %s disconnected from node %s\n", peerID, n.ID)
+	n.publishPeerChange(peerID, false)
 }
 
 // GetPeers retorna a lista de peers conectados
@@ -340,11 +654,13 @@ func (n *Node) GetPeers() []*network.Peer {
 
 // BroadcastMessage envia uma mensagem para todos os peers
 func (n *Node) BroadcastMessage(msgType string, data []byte) {
+	payload := newOutgoingPayload(msgType, data)
+
 	n.peersMutex.RLock()
 	defer n.peersMutex.RUnlock()
 
 	for _, peer := range n.peers {
-		if err := peer.SendMessage(msgType, data); err != nil {
+		if err := n.sendPayload(peer, payload); err != nil {
 			fmt.Printf("Failed to send message to peer %s: %v\n", peer.ID, err)
 		}
 	}
@@ -364,6 +680,22 @@ func (n *Node) registerMessageHandlers() {
 
 // HandlePeerMessage processa mensagens recebidas de peers (chamado pelo Peer.OnMessage)
 func (n *Node) HandlePeerMessage(peerID string, msgType string, data []byte) {
+	// Descarta mensagens acima da taxa permitida para o peer
+	if n.rateLimiter != nil && !n.rateLimiter.Allow(peerID, msgType) {
+		n.handleRateLimitedMessage(peerID, msgType)
+		return
+	}
+
+	// Payloads comprimidos são aceitos mesmo com a compressão desativada no nó
+	if blockchain.IsCompressedPayload(data) {
+		decompressed, err := blockchain.DecompressPayload(data)
+		if err != nil {
+			fmt.Printf("[%s] Invalid compressed %s from peer %s: %v\n", n.ID, msgType, peerID, err)
+			return
+		}
+		data = decompressed
+	}
+
 	switch msgType {
 	case "block":
 		n.handleBlockMessage(peerID, data)
@@ -373,15 +705,51 @@ func (n *Node) HandlePeerMessage(peerID string, msgType string, data []byte) {
 		n.handleSyncRequest(peerID, data)
 	case "sync_response":
 		n.handleSyncResponse(peerID, data)
+	case "get_block":
+		n.handleGetBlock(peerID, data)
+	case "block_response":
+		n.handleBlockResponse(peerID, data)
+	case "headers_request":
+		n.handleHeadersRequest(peerID, data)
+	case "headers_response":
+		n.handleHeadersResponse(peerID, data)
 	case "checkpoint_request":
 		n.handleCheckpointRequest(peerID, data)
 	case "checkpoint_response":
 		n.handleCheckpointResponse(peerID, data)
+	case "checkpoint_signature":
+		n.handleCheckpointSignature(peerID, data)
+	case "slash_evidence":
+		n.handleSlashEvidence(peerID, data)
+	case "capabilities":
+		n.handleCapabilities(peerID, data)
+	case "time":
+		n.handleTime(peerID, data)
 	default:
 		fmt.Printf("[%s] Unknown message type '%s' from peer %s\n", n.ID, msgType, peerID)
 	}
 }
 
+// handleRateLimitedMessage registra o descarte e, se configurado, desconecta o peer que
+// continua excedendo a taxa
+func (n *Node) handleRateLimitedMessage(peerID, msgType string) {
+	drops := n.rateLimiter.Drops(peerID)
+
+	// Loga só alguns descartes para o flood não virar flood de log
+	if drops == 1 || drops%100 == 0 {
+		fmt.Printf("[%s] ⚠️  Rate limit exceeded by peer %s (%s), %d messages dropped\n", n.ID, peerID, msgType, drops)
+	}
+
+	if n.rateLimitMaxDrops > 0 && drops >= n.rateLimitMaxDrops && n.webRTC != nil {
+		fmt.Printf("[%s] Disconnecting peer %s after %d rate-limited messages\n", n.ID, peerID, drops)
+		go func() {
+			if err := n.webRTC.DisconnectPeer(peerID); err != nil {
+				fmt.Printf("[%s] Failed to disconnect peer %s: %v\n", n.ID, peerID, err)
+			}
+		}()
+	}
+}
+
 // handleBlockMessage processa um bloco recebido da rede
 func (n *Node) handleBlockMessage(peerID string, data []byte) {
 	block, err := blockchain.DeserializeBlock(data)
@@ -397,24 +765,55 @@ func (n *Node) handleBlockMessage(peerID string, data []byte) {
 		return // Já tem, ignora
 	}
 
+	// Gossip só aplica o sucessor imediato da ponta; blocos mais à frente (alturas puladas)
+	// aguardam como órfãos até a sincronização preencher o intervalo
+	tip := n.chain.GetHeight()
+	if block.Header.Height > tip+1 {
+		n.bufferOrphan(peerID, block, tip)
+		return
+	}
+
+	// Pai desconhecido (fork que começou antes do que o nó viu): busca o pai por hash antes de desistir
+	if _, known := n.GetBlockByHash(block.Header.PreviousHash); !known {
+		n.fetchMissingParent(peerID, []*blockchain.Block{block})
+		return
+	}
+
+	// Bloco em altura já ocupada: compete com a chain principal pela escolha de fork
+	if block.Header.Height <= tip {
+		n.considerFork(peerID, []*blockchain.Block{block})
+		n.connectOrphans()
+		return
+	}
+
+	if n.applyGossipBlock(peerID, block) {
+		n.connectOrphans()
+	}
+}
+
+// applyGossipBlock adiciona um bloco recebido por gossip à chain, salva, limpa o mempool e
+// o repassa aos peers (exceto peerID). Retorna false se o bloco for rejeitado.
+func (n *Node) applyGossipBlock(peerID string, block *blockchain.Block) bool {
 	// Validar checkpoint hash se presente no bloco
 	if block.Header.CheckpointHash != "" && n.checkpointConfig != nil && n.checkpointConfig.Enabled {
 		if err := n.validateBlockCheckpointHash(block); err != nil {
 			fmt.Printf("[%s] Block checkpoint validation failed: %v\n", n.ID, err)
-			return
+			n.logRejectedBlock(peerID, "gossip", block, err)
+			return false
 		}
 	}
 
 	// Tenta adicionar à chain
 	if err := n.chain.AddBlock(block); err != nil {
 		fmt.Printf("[%s] Failed to add block: %v\n", n.ID, err)
-		return
+		n.logRejectedBlock(peerID, "gossip", block, err)
+		return false
 	}
 
 	fmt.Printf("[%s] Block %d added to chain successfully\n", n.ID, block.Header.Height)
 
 	// Salvar bloco no disco
-	if err := blockchain.SaveBlockToDB(n.db, block); err != nil {
+	if err := n.saveBlock(block); err != nil {
 		fmt.Printf("[%s] ⚠️  Warning: failed to save block %d to disk: %v\n", n.ID, block.Header.Height, err)
 	} else {
 		fmt.Printf("[%s] 💾 Block %d saved to disk successfully\n", n.ID, block.Header.Height)
@@ -422,6 +821,7 @@ func (n *Node) handleBlockMessage(peerID string, data []byte) {
 
 	// Tentar criar checkpoint se necessário
 	n.tryCreateCheckpoint(block.Header.Height)
+	n.pruneAfterBlock(block.Header.Height)
 
 	// Remove transações do mempool que estão no bloco
 	txIDs := make([]string, 0, len(block.Transactions))
@@ -431,12 +831,17 @@ func (n *Node) handleBlockMessage(peerID string, data []byte) {
 		}
 	}
 	removed := n.mempool.RemoveTransactions(txIDs)
+	removed += n.mempool.RemoveExpiredTransactions(block.Header.Height)
 	if removed > 0 {
 		fmt.Printf("[%s] Removed %d transactions from mempool\n", n.ID, removed)
 	}
 
+	n.publishNewBlock(block)
+
 	// Propaga para outros peers (exceto quem enviou)
 	n.broadcastBlockExcept(block, peerID)
+
+	return true
 }
 
 // handleTransactionMessage processa uma transação recebida da rede
@@ -447,6 +852,14 @@ func (n *Node) handleTransactionMessage(peerID string, data []byte) {
 		return
 	}
 
+	// O ID precisa ser o derivado do conteúdo antes de ser usado para deduplicar
+	if err := tx.VerifyID(); err != nil {
+		err = fmt.Errorf("transaction validation failed: %w", err)
+		fmt.Printf("[%s] Rejected transaction from %s: %v\n", n.ID, peerID, err)
+		n.logRejectedTransaction(peerID, "gossip", tx, err)
+		return
+	}
+
 	fmt.Printf("[%s] Received transaction %s from %s\n", n.ID, tx.ID[:8], peerID)
 
 	// Verifica se já tem a transação
@@ -457,10 +870,12 @@ func (n *Node) handleTransactionMessage(peerID string, data []byte) {
 	// Tenta adicionar ao mempool
 	if err := n.mempool.AddTransaction(tx); err != nil {
 		fmt.Printf("[%s] Failed to add transaction to mempool: %v\n", n.ID, err)
+		n.logRejectedTransaction(peerID, "gossip", tx, err)
 		return
 	}
 
 	fmt.Printf("[%s] Transaction %s added to mempool\n", n.ID, tx.ID[:8])
+	n.publishNewTransaction(tx)
 
 	// Propaga para outros peers (exceto quem enviou)
 	n.broadcastTransactionExcept(tx, peerID)
@@ -469,11 +884,14 @@ func (n *Node) handleTransactionMessage(peerID string, data []byte) {
 // SyncRequest mensagem de requisição de sincronização
 type SyncRequest struct {
 	FromHeight uint64 `json:"from_height"`
+	ToHeight   uint64 `json:"to_height,omitempty"` // Última altura desejada (0 = até o limite da resposta)
 }
 
 // SyncResponse mensagem de resposta de sincronização
 type SyncResponse struct {
-	Blocks []*blockchain.Block `json:"blocks"`
+	Blocks    []*blockchain.Block `json:"blocks"`
+	HasMore   bool                `json:"has_more,omitempty"`   // Há blocos além dos enviados (o requisitante deve pedir de novo)
+	TipHeight uint64              `json:"tip_height,omitempty"` // Altura da chain de quem respondeu
 }
 
 // CheckpointRequest mensagem de requisição de checkpoint
@@ -490,6 +908,13 @@ type CheckpointResponse struct {
 	AllCheckpoints   []*blockchain.Checkpoint `json:"all_checkpoints,omitempty"`   // todos os checkpoints necessários
 }
 
+// CheckpointSignatureMessage endosso de um checkpoint enviado por um validador
+type CheckpointSignatureMessage struct {
+	Height    uint64                         `json:"height"`
+	Hash      string                         `json:"hash"`
+	Signature blockchain.CheckpointSignature `json:"signature"`
+}
+
 // handleSyncRequest processa uma requisição de sincronização
 func (n *Node) handleSyncRequest(peerID string, data []byte) {
 	var req SyncRequest
@@ -500,7 +925,6 @@ func (n *Node) handleSyncRequest(peerID string, data []byte) {
 
 	fmt.Printf("[%s] 📥 Received sync request from %s (from height %d)\n", n.ID, peerID, req.FromHeight)
 
-	// Pega blocos a partir da altura solicitada
 	currentHeight := n.chain.GetHeight()
 	fmt.Printf("[%s] 📊 Current height: %d, peer requested from: %d\n", n.ID, currentHeight, req.FromHeight)
 
@@ -509,42 +933,11 @@ func (n *Node) handleSyncRequest(peerID string, data []byte) {
 		return
 	}
 
-	// Limita a quantidade de blocos por vez
-	maxBlocks := uint64(100)
-	toHeight := req.FromHeight + maxBlocks
-	if toHeight > currentHeight {
-		toHeight = currentHeight
-	}
-
-	blocks := n.chain.GetBlockRange(req.FromHeight, toHeight)
-
-	// Se não conseguiu todos os blocos (devido ao bug de pruning), carregar do DB
-	expectedCount := int(toHeight - req.FromHeight + 1)
-	if len(blocks) < expectedCount {
-		fmt.Printf("[%s] GetBlockRange returned %d/%d blocks, loading from DB for heights %d-%d\n",
-			n.ID, len(blocks), expectedCount, req.FromHeight, toHeight)
-
-		blocks = make([]*blockchain.Block, 0, expectedCount)
-		for h := req.FromHeight; h <= toHeight; h++ {
-			block, exists := n.chain.GetBlockByHeight(h)
-			if exists && block != nil && block.Header.Height == h {
-				blocks = append(blocks, block)
-			} else {
-				// Carregar do DB
-				block, err := blockchain.LoadBlockFromDB(n.db, h)
-				if err != nil {
-					fmt.Printf("[%s] Failed to load block %d from DB: %v\n", n.ID, h, err)
-					break
-				}
-				blocks = append(blocks, block)
-			}
-		}
-		fmt.Printf("[%s] After DB loading: have %d blocks for sync\n", n.ID, len(blocks))
-	}
-
-	// Envia resposta
-	response := SyncResponse{
-		Blocks: blocks,
+	response := n.buildSyncResponse(req.FromHeight, req.ToHeight)
+	blocks := response.Blocks
+	toHeight := req.FromHeight
+	if len(blocks) > 0 {
+		toHeight = blocks[len(blocks)-1].Header.Height
 	}
 
 	responseData, err := json.Marshal(response)
@@ -559,7 +952,7 @@ func (n *Node) handleSyncRequest(peerID string, data []byte) {
 	n.peersMutex.RUnlock()
 
 	if peer != nil {
-		if err := peer.SendMessage("sync_response", responseData); err != nil {
+		if err := n.sendPayload(peer, newOutgoingPayload("sync_response", responseData)); err != nil {
 			fmt.Printf("[%s] ❌ Failed to send sync response to %s: %v\n", n.ID, peerID, err)
 		} else {
 			fmt.Printf("[%s] ✅ Sent %d blocks to %s (height %d-%d)\n", n.ID, len(blocks), peerID, req.FromHeight, toHeight)
@@ -569,6 +962,131 @@ func (n *Node) handleSyncRequest(peerID string, data []byte) {
 	}
 }
 
+// BuildSyncResponse monta a resposta a uma requisição de sync a partir de fromHeight.
+// Envia no máximo 100 blocos; se a montagem estourar o SyncAssemblyTimeout, envia os
+// blocos já coletados. HasMore indica que o requisitante deve pedir os seguintes.
+func (n *Node) BuildSyncResponse(fromHeight uint64) SyncResponse {
+	return n.buildSyncResponse(fromHeight, 0)
+}
+
+// buildSyncResponse implementa BuildSyncResponse parando em lastHeight (0 = sem limite),
+// usado para baixar os corpos de headers já validados
+func (n *Node) buildSyncResponse(fromHeight, lastHeight uint64) SyncResponse {
+	currentHeight := n.chain.GetHeight()
+	if lastHeight == 0 || lastHeight > currentHeight {
+		lastHeight = currentHeight
+	}
+	if fromHeight > lastHeight {
+		return SyncResponse{Blocks: []*blockchain.Block{}, TipHeight: currentHeight}
+	}
+
+	// Limita a quantidade de blocos por vez
+	maxBlocks := uint64(100)
+	toHeight := fromHeight + maxBlocks
+	if toHeight > lastHeight {
+		toHeight = lastHeight
+	}
+
+	blocks := n.collectBlockRange(fromHeight, toHeight)
+
+	response := SyncResponse{Blocks: blocks, TipHeight: currentHeight}
+	if len(blocks) > 0 && blocks[len(blocks)-1].Header.Height < lastHeight {
+		response.HasMore = true
+	}
+
+	return response
+}
+
+// collectBlockRange retorna os blocos de fromHeight a toHeight para enviar a um peer.
+// Blocos fora da memória (pruned) ou âncoras de checkpoint são carregados do disco;
+// a coleta para no primeiro bloco indisponível ou ao atingir o SyncAssemblyTimeout.
+func (n *Node) collectBlockRange(fromHeight, toHeight uint64) []*blockchain.Block {
+	if toHeight < fromHeight {
+		return []*blockchain.Block{}
+	}
+	deadline := time.Now().Add(n.syncAssemblyTimeout)
+
+	// GetBlockRange indexa pela posição no slice, o que só é válido sem pruning/restore
+	blocks := n.chain.GetBlockRange(fromHeight, toHeight)
+	expectedCount := int(toHeight - fromHeight + 1)
+	if len(blocks) == expectedCount && blocks[0].Header.Height == fromHeight && !containsAnchor(blocks) {
+		return blocks
+	}
+
+	fmt.Printf("[%s] Blocks partially in memory (%d/%d), loading remaining from DB: height %d-%d\n",
+		n.ID, len(blocks), expectedCount, fromHeight, toHeight)
+
+	blocks = make([]*blockchain.Block, 0, expectedCount)
+	for h := fromHeight; h <= toHeight; h++ {
+		block, exists := n.chain.GetBlockByHeight(h)
+		if exists && block != nil && block.Header.Height == h && !block.IsCheckpointAnchor() {
+			blocks = append(blocks, block)
+			continue
+		}
+
+		block, err := n.loadBlockBefore(h, deadline)
+		if err != nil {
+			fmt.Printf("[%s] Failed to load block %d from DB: %v\n", n.ID, h, err)
+			break
+		}
+		blocks = append(blocks, block)
+	}
+	fmt.Printf("[%s] After DB loading: have %d blocks\n", n.ID, len(blocks))
+
+	return blocks
+}
+
+// GetBlockByHeight retorna um bloco pela altura, carregando do disco se não estiver em memória
+func (n *Node) GetBlockByHeight(height uint64) (*blockchain.Block, bool) {
+	if height > n.chain.GetHeight() {
+		return nil, false
+	}
+
+	memBlock, inMemory := n.chain.GetBlockByHeight(height)
+	if inMemory && !memBlock.IsCheckpointAnchor() {
+		return memBlock, true
+	}
+
+	if block, err := blockchain.LoadBlockFromDB(n.db, height); err == nil {
+		return block, true
+	}
+
+	// Âncora de checkpoint sem cópia em disco: retorna ao menos o header
+	return memBlock, inMemory
+}
+
+// GetBlockByHash retorna um bloco pelo hash, carregando do disco se não estiver em memória
+func (n *Node) GetBlockByHash(hash string) (*blockchain.Block, bool) {
+	memBlock, inMemory := n.chain.GetBlock(hash)
+	if inMemory && !memBlock.IsCheckpointAnchor() {
+		return memBlock, true
+	}
+
+	if block, err := blockchain.LoadBlockByHashFromDB(n.db, hash); err == nil {
+		return block, true
+	}
+
+	return memBlock, inMemory
+}
+
+// GetBlockRange retorna os blocos de fromHeight a toHeight (inclusive), parando no primeiro indisponível
+func (n *Node) GetBlockRange(fromHeight, toHeight uint64) []*blockchain.Block {
+	if height := n.chain.GetHeight(); toHeight > height {
+		toHeight = height
+	}
+	return n.collectBlockRange(fromHeight, toHeight)
+}
+
+// containsAnchor verifica se algum bloco é uma âncora de checkpoint
+func containsAnchor(blocks []*blockchain.Block) bool {
+	for _, block := range blocks {
+		if block.IsCheckpointAnchor() {
+			return true
+		}
+	}
+	return false
+}
+
 // handleSyncResponse processa uma resposta de sincronização
 func (n *Node) handleSyncResponse(peerID string, data []byte) {
 	var resp SyncResponse
@@ -579,47 +1097,93 @@ func (n *Node) handleSyncResponse(peerID string, data []byte) {
 
 	fmt.Printf("[%s] 🔄 Received sync response from %s with %d blocks\n", n.ID, peerID, len(resp.Blocks))
 
-	// Adiciona blocos à chain
-	added := 0
-	for i, block := range resp.Blocks {
-		fmt.Printf("[%s] 📦 Processing block %d/%d: height=%d, hash=%s\n",
-			n.ID, i+1, len(resp.Blocks), block.Header.Height, block.Hash[:8])
+	// Trecho pedido pelo download paralelo: reordenado antes de ser aplicado
+	if n.handleDownloadResponse(peerID, &resp) {
+		return
+	}
 
-		// Verifica se já tem o bloco
+	// Descarta os blocos que já temos
+	newBlocks := make([]*blockchain.Block, 0, len(resp.Blocks))
+	for _, block := range resp.Blocks {
 		if _, exists := n.chain.GetBlock(block.Hash); exists {
 			fmt.Printf("[%s] ⏭️  Block %d already exists, skipping\n", n.ID, block.Header.Height)
 			continue
 		}
+		newBlocks = append(newBlocks, block)
+	}
 
-		// Adiciona à chain
-		if err := n.chain.AddBlock(block); err != nil {
-			fmt.Printf("[%s] ❌ Failed to add synced block %d: %v\n", n.ID, block.Header.Height, err)
+	// Corpos pedidos após validar os headers precisam corresponder a eles
+	if i := n.headerSync.firstMismatch(peerID, newBlocks); i >= 0 {
+		fmt.Printf("[%s] ❌ Block %d from %s does not match its validated header, aborting headers-first sync\n",
+			n.ID, newBlocks[i].Header.Height, peerID)
+		n.logRejectedBlock(peerID, "sync", newBlocks[i], fmt.Errorf("block does not match its validated header"))
+		n.headerSync.reset()
+		newBlocks = newBlocks[:i]
+	}
+
+	added := n.applySyncedBlocks(peerID, newBlocks)
+
+	// A sincronização continua se o peer tiver mais blocos ou se houver sincronização por
+	// headers em andamento (próximo pedido abaixo)
+	n.metrics.syncing.Store(added > 0 && (resp.HasMore || n.headerSync.isActive(peerID)))
+
+	if added > 0 {
+		fmt.Printf("[%s] ✨ Successfully synced %d blocks, current height: %d\n", n.ID, added, n.chain.GetHeight())
+
+		// Órfãos de gossip que a sincronização alcançou
+		n.connectOrphans()
+
+		// Resposta parcial (limite de blocos ou de tempo no peer): pede os próximos
+		if !n.continueHeaderSync(peerID) && resp.HasMore {
+			n.continueSync(peerID, resp.TipHeight)
+		}
+	} else if len(resp.Blocks) > 0 {
+		fmt.Printf("[%s] ℹ️  No new blocks added (all already exist)\n", n.ID)
+	}
+}
+
+// applySyncedBlocks adiciona à chain blocos consecutivos recebidos de peerID na sincronização
+// e retorna quantos foram adicionados.
+func (n *Node) applySyncedBlocks(peerID string, blocks []*blockchain.Block) int {
+	// Adiciona blocos à chain em lotes (estado e disco atualizados uma vez por lote).
+	// Um bloco inválido descarta o lote inteiro; lotes anteriores já aplicados são mantidos.
+	added := 0
+	for start := 0; start < len(blocks); start += n.syncBatchSize {
+		batch := blocks[start:min(start+n.syncBatchSize, len(blocks))]
+		first, last := batch[0].Header.Height, batch[len(batch)-1].Header.Height
+
+		if err := n.chain.AddBlocks(batch); err != nil {
+			fmt.Printf("[%s] ❌ Failed to add synced blocks %d-%d: %v\n", n.ID, first, last, err)
+			n.logRejectedBatch(peerID, batch, err)
 			break
 		}
 
-		fmt.Printf("[%s] ✅ Successfully added block %d\n", n.ID, block.Header.Height)
+		fmt.Printf("[%s] ✅ Successfully added blocks %d-%d\n", n.ID, first, last)
 
-		// Salvar bloco no disco
-		if err := blockchain.SaveBlockToDB(n.db, block); err != nil {
-			fmt.Printf("[%s] Warning: failed to save synced block %d to disk: %v\n", n.ID, block.Header.Height, err)
+		// Salvar blocos no disco
+		if err := n.saveBlocks(batch); err != nil {
+			fmt.Printf("[%s] Warning: failed to save synced blocks %d-%d to disk: %v\n", n.ID, first, last, err)
 		}
+		n.pruneAfterBlock(last)
 
 		// Remove transações do mempool
-		txIDs := make([]string, 0, len(block.Transactions))
-		for _, tx := range block.Transactions {
-			if !tx.IsCoinbase() {
-				txIDs = append(txIDs, tx.ID)
+		for _, block := range batch {
+			txIDs := make([]string, 0, len(block.Transactions))
+			for _, tx := range block.Transactions {
+				if !tx.IsCoinbase() {
+					txIDs = append(txIDs, tx.ID)
+				}
 			}
+			n.mempool.RemoveTransactions(txIDs)
 		}
-		n.mempool.RemoveTransactions(txIDs)
-		added++
+		n.mempool.RemoveExpiredTransactions(last)
+		added += len(batch)
 	}
 
 	if added > 0 {
-		fmt.Printf("[%s] ✨ Successfully synced %d blocks, current height: %d\n", n.ID, added, n.chain.GetHeight())
-	} else if len(resp.Blocks) > 0 {
-		fmt.Printf("[%s] ℹ️  No new blocks added (all already exist)\n", n.ID)
+		n.publishSyncedBlocks(blocks[:added])
 	}
+	return added
 }
 
 // handleCheckpointRequest processa uma requisição de checkpoint
@@ -665,50 +1229,21 @@ func (n *Node) handleCheckpointRequest(peerID string, data []byte) {
 		return
 	}
 
-	// Pegar blocos desde o genesis até a altura atual (limitado)
-	// NOTA: O peer precisa de TODOS os blocos desde o genesis para reconstruir a chain!
+	// Enviar blocos a partir da altura do checkpoint (inclusive): o bloco do checkpoint
+	// fornece o hash âncora e os seguintes são aplicados sobre o estado restaurado
 	currentHeight := n.chain.GetHeight()
-	maxBlocks := uint64(100) // Limitar quantidade de blocos (aumentado para cobrir mais blocos)
+	maxBlocks := uint64(100) // Limitar quantidade de blocos
 
-	// Enviar blocos desde o GENESIS (altura 1), não após o checkpoint!
-	// O checkpoint contém o estado, mas o peer ainda precisa dos blocos para validação
-	fromHeight := uint64(1) // Começar do primeiro bloco após genesis
+	fromHeight := checkpointHeight
+	if fromHeight == 0 {
+		fromHeight = 1
+	}
 	toHeight := currentHeight
 	if toHeight-fromHeight+1 > maxBlocks {
 		toHeight = fromHeight + maxBlocks - 1
 	}
 
-	// Tentar pegar blocos da chain (memória)
-	blocks := n.chain.GetBlockRange(fromHeight, toHeight)
-
-	// Se não conseguiu blocos da memória (foram pruned), buscar do DB
-	if len(blocks) < int(toHeight-fromHeight+1) {
-		fmt.Printf("[%s] Blocks partially in memory (%d/%d), loading remaining from DB: height %d-%d\n",
-			n.ID, len(blocks), toHeight-fromHeight+1, fromHeight, toHeight)
-
-		blocks = make([]*blockchain.Block, 0, toHeight-fromHeight+1)
-		for h := fromHeight; h <= toHeight; h++ {
-			// Primeiro tenta da chain (memória)
-			// NOTA: GetBlockByHeight tem um bug após pruning onde o índice não corresponde à altura
-			// Então precisamos verificar a altura real do bloco retornado
-			block, exists := n.chain.GetBlockByHeight(h)
-			if exists && block != nil && block.Header.Height == h {
-				blocks = append(blocks, block)
-				fmt.Printf("[%s] Got block %d from memory\n", n.ID, h)
-			} else {
-				// Se não está em memória (ou índice errado), busca do DB
-				var err error
-				block, err = blockchain.LoadBlockFromDB(n.db, h)
-				if err != nil {
-					fmt.Printf("[%s] Failed to load block %d from DB: %v\n", n.ID, h, err)
-					break
-				}
-				fmt.Printf("[%s] Loaded block %d from DB\n", n.ID, h)
-				blocks = append(blocks, block)
-			}
-		}
-		fmt.Printf("[%s] After DB loading: have %d blocks\n", n.ID, len(blocks))
-	}
+	blocks := n.collectBlockRange(fromHeight, toHeight)
 
 	// Carregar TODOS os checkpoints disponíveis para o peer poder validar os blocos
 	// Os blocos contêm hashes de checkpoints anteriores, então precisamos enviá-los todos
@@ -751,16 +1286,21 @@ func (n *Node) handleCheckpointResponse(peerID string, data []byte) {
 	fmt.Printf("[%s] Received checkpoint from %s at height %d with %d blocks and %d checkpoints\n",
 		n.ID, peerID, resp.Checkpoint.Height, len(resp.BlocksSince), len(resp.AllCheckpoints))
 
-	// Validar checkpoint
-	if err := blockchain.ValidateCheckpointHash(resp.Checkpoint, n.checkpointConfig.CSVDelimiter); err != nil {
-		fmt.Printf("[%s] Invalid checkpoint received from %s: %v\n", n.ID, peerID, err)
+	// Validar checkpoint (hash e endosso dos validadores conhecidos)
+	if err := n.validateReceivedCheckpoint(resp.Checkpoint); err != nil {
+		fmt.Printf("[%s] Rejecting checkpoint from %s: %v\n", n.ID, peerID, err)
 		return
 	}
 
-	// Salvar todos os checkpoints adicionais no DB para validação de blocos
+	// Salvar os checkpoints adicionais válidos no DB para validação de blocos: um checkpoint
+	// salvo passa a ser a referência para aceitar ou recusar blocos.
 	if len(resp.AllCheckpoints) > 0 {
 		fmt.Printf("[%s] Saving %d additional checkpoints for validation\n", n.ID, len(resp.AllCheckpoints))
 		for _, cp := range resp.AllCheckpoints {
+			if err := n.validateReceivedCheckpoint(cp); err != nil {
+				fmt.Printf("[%s] Skipping checkpoint from %s: %v\n", n.ID, peerID, err)
+				continue
+			}
 			if err := blockchain.SaveCheckpointToDB(n.db, cp, n.checkpointConfig.Compression); err != nil {
 				fmt.Printf("[%s] Warning: failed to save checkpoint at height %d: %v\n", n.ID, cp.Height, err)
 			}
@@ -783,7 +1323,7 @@ func (n *Node) handleCheckpointResponse(peerID string, data []byte) {
 	// Restaurar estado a partir do checkpoint
 	fmt.Printf("[%s] Restoring state from checkpoint at height %d\n", n.ID, resp.Checkpoint.Height)
 
-	if err := n.restoreFromCheckpoint(resp.Checkpoint); err != nil {
+	if err := n.restoreFromCheckpoint(resp.Checkpoint, resp.BlocksSince); err != nil {
 		fmt.Printf("[%s] Failed to restore from checkpoint: %v\n", n.ID, err)
 		return
 	}
@@ -821,33 +1361,58 @@ func (n *Node) sendCheckpointResponse(peerID string, response CheckpointResponse
 	n.peersMutex.RUnlock()
 
 	if peer != nil {
-		if err := peer.SendMessage("checkpoint_response", responseData); err != nil {
+		if err := n.sendPayload(peer, newOutgoingPayload("checkpoint_response", responseData)); err != nil {
 			fmt.Printf("[%s] Failed to send checkpoint response to %s: %v\n", n.ID, peerID, err)
 		}
 	}
 }
 
-// restoreFromCheckpoint restaura o estado da blockchain a partir de um checkpoint
-func (n *Node) restoreFromCheckpoint(checkpoint *blockchain.Checkpoint) error {
-	// Por enquanto, vamos apenas registrar que recebemos o checkpoint
-	// O estado será restaurado através dos blocos recebidos via BlocksSince
-	// que já contêm todas as transações necessárias
+// restoreFromCheckpoint restaura o estado da blockchain a partir de um checkpoint (fast sync).
+// O contexto é reconstruído diretamente de checkpoint.Accounts; apenas os blocos após
+// o checkpoint precisam ser aplicados depois. A chain continua do bloco checkpoint.BlockHash,
+// coberto pelas assinaturas do checkpoint; os blocos do peer precisam corresponder a ele.
+func (n *Node) restoreFromCheckpoint(checkpoint *blockchain.Checkpoint, blocks []*blockchain.Block) error {
+	blockHash := checkpoint.BlockHash
+	if blockHash == "" {
+		return fmt.Errorf("checkpoint at height %d has no block hash to anchor the restored chain", checkpoint.Height)
+	}
+
+	var checkpointBlock *blockchain.Block
+	for _, block := range blocks {
+		if block.Header.Height == checkpoint.Height {
+			if block.Hash != blockHash {
+				return fmt.Errorf("block %d does not match checkpoint block hash %s", checkpoint.Height, blockHash)
+			}
+			if err := block.VerifyHash(); err != nil {
+				return fmt.Errorf("invalid checkpoint block: %w", err)
+			}
+			if err := block.VerifySignature(); err != nil {
+				return fmt.Errorf("invalid checkpoint block: %w", err)
+			}
+			checkpointBlock = block
+			break
+		}
+		if block.Header.Height == checkpoint.Height+1 {
+			if block.Header.PreviousHash != blockHash {
+				return fmt.Errorf("block %d does not continue checkpoint block hash %s", block.Header.Height, blockHash)
+			}
+			break
+		}
+	}
+
+	if err := n.chain.RestoreFromState(checkpoint.Height, checkpoint.Accounts, checkpoint.Minted, blockHash); err != nil {
+		return err
+	}
 
-	fmt.Printf("[%s] Checkpoint received: %d accounts at height %d\n",
-		n.ID, len(checkpoint.Accounts), checkpoint.Height)
+	// Salvar o bloco do checkpoint para permitir restaurar a partir do disco ao reiniciar
+	if checkpointBlock != nil {
+		if err := n.saveBlock(checkpointBlock); err != nil {
+			fmt.Printf("[%s] Warning: failed to save checkpoint block %d to disk: %v\n", n.ID, checkpoint.Height, err)
+		}
+	}
 
-	// NOTA: Uma implementação completa de "fast sync" requereria:
-	// 1. Criar um novo contexto com o estado do checkpoint injetado
-	// 2. Recriar a chain a partir do bloco do checkpoint
-	// 3. Atualizar todos os blocos em memória
-	//
-	// Por enquanto, o protocolo funciona assim:
-	// - Node2 recebe checkpoint em altura H
-	// - Node2 recebe blocos desde H+1 até altura atual
-	// - Os blocos são processados normalmente, reconstruindo o estado
-	//
-	// Isso é mais seguro e garante consistência, mas requer mais banda.
-	// Uma otimização futura seria injetar o estado diretamente.
+	fmt.Printf("[%s] State restored from checkpoint: %d accounts at height %d\n",
+		n.ID, len(checkpoint.Accounts), checkpoint.Height)
 
 	return nil
 }
@@ -881,12 +1446,13 @@ func (n *Node) processSyncedBlocks(blocks []*blockchain.Block) {
 		fmt.Printf("[%s] Successfully added synced block %d\n", n.ID, block.Header.Height)
 
 		// Salvar bloco no disco
-		if err := blockchain.SaveBlockToDB(n.db, block); err != nil {
+		if err := n.saveBlock(block); err != nil {
 			fmt.Printf("[%s] Warning: failed to save synced block %d to disk: %v\n", n.ID, block.Header.Height, err)
 		}
 
 		// Tentar criar checkpoint se necessário
 		n.tryCreateCheckpoint(block.Header.Height)
+		n.pruneAfterBlock(block.Header.Height)
 
 		// Remove transações do mempool
 		txIDs := make([]string, 0, len(block.Transactions))
@@ -896,6 +1462,7 @@ func (n *Node) processSyncedBlocks(blocks []*blockchain.Block) {
 			}
 		}
 		n.mempool.RemoveTransactions(txIDs)
+		n.mempool.RemoveExpiredTransactions(block.Header.Height)
 		added++
 	}
 
@@ -951,12 +1518,14 @@ func (n *Node) broadcastBlockExcept(block *blockchain.Block, exceptPeerID string
 		return
 	}
 
+	payload := newOutgoingPayload("block", data)
+
 	n.peersMutex.RLock()
 	defer n.peersMutex.RUnlock()
 
 	for _, peer := range n.peers {
 		if peer.ID != exceptPeerID {
-			if err := peer.SendMessage("block", data); err != nil {
+			if err := n.sendPayload(peer, payload); err != nil {
 				fmt.Printf("[%s] Failed to send block to peer %s: %v\n", n.ID, peer.ID, err)
 			}
 		}
@@ -983,12 +1552,14 @@ func (n *Node) broadcastTransactionExcept(tx *blockchain.Transaction, exceptPeer
 		return
 	}
 
+	payload := newOutgoingPayload("transaction", data)
+
 	n.peersMutex.RLock()
 	defer n.peersMutex.RUnlock()
 
 	for _, peer := range n.peers {
 		if peer.ID != exceptPeerID {
-			if err := peer.SendMessage("transaction", data); err != nil {
+			if err := n.sendPayload(peer, payload); err != nil {
 				fmt.Printf("[%s] Failed to send transaction to peer %s: %v\n", n.ID, peer.ID, err)
 			}
 		}
@@ -997,38 +1568,76 @@ func (n *Node) broadcastTransactionExcept(tx *blockchain.Transaction, exceptPeer
 
 // Blockchain API methods
 
-// StartMining inicia a mineração em background
+// StartMining inicia a mineração em background. Retorna erro se já estiver minerando.
 func (n *Node) StartMining() error {
+	n.miningMutex.Lock()
 	if n.mining {
+		n.miningMutex.Unlock()
 		return fmt.Errorf("already mining")
 	}
 
 	n.mining = true
-	n.stopMine = make(chan struct{})
-
-	go n.miner.MineLoop(n.stopMine)
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	previous := n.mineDone
+	n.stopMine = stop
+	n.mineDone = done
+	n.miningMutex.Unlock()
+
+	// O novo loop só começa quando o anterior termina, para nunca haver dois minerando.
+	// A espera fica fora do lock: o loop anterior pode chamar StopMining (falha ao salvar).
+	go func() {
+		defer close(done)
+		if previous != nil {
+			<-previous
+		}
+		n.miner.MineLoop(stop)
+	}()
 
 	fmt.Printf("[%s] Mining started\n", n.ID)
+	n.publishMiningState(true)
 	return nil
 }
 
-// StopMining para a mineração
+// StopMining para a mineração (no-op se não estiver minerando). Não espera o loop
+// terminar, já que pode ser chamado de dentro dele.
 func (n *Node) StopMining() {
+	n.miningMutex.Lock()
 	if !n.mining {
+		n.miningMutex.Unlock()
 		return
 	}
 
 	close(n.stopMine)
 	n.mining = false
+	n.miningMutex.Unlock()
 
 	fmt.Printf("[%s] Mining stopped\n", n.ID)
+	n.publishMiningState(false)
 }
 
 // IsMining retorna se o nó está minerando
 func (n *Node) IsMining() bool {
+	n.miningMutex.Lock()
+	defer n.miningMutex.Unlock()
 	return n.mining
 }
 
+// GetBlockTemplate retorna o template do próximo bloco para mineração externa
+func (n *Node) GetBlockTemplate() (*blockchain.BlockTemplate, error) {
+	return n.miner.GetBlockTemplate()
+}
+
+// SubmitBlock adiciona à chain um bloco montado externamente, salvando e propagando como um
+// bloco minerado pelo nó
+func (n *Node) SubmitBlock(block *blockchain.Block) error {
+	if err := n.miner.SubmitBlock(block); err != nil {
+		return err
+	}
+	fmt.Printf("[%s] ⛏️  Externally built block %d accepted\n", n.ID, block.Header.Height)
+	return nil
+}
+
 // CreateTransaction cria uma nova transação e adiciona ao mempool
 func (n *Node) CreateTransaction(to string, amount, fee uint64, data string) (*blockchain.Transaction, error) {
 	tx, err := n.miner.CreateTransaction(to, amount, fee, data)
@@ -1040,6 +1649,7 @@ func (n *Node) CreateTransaction(to string, amount, fee uint64, data string) (*b
 	if err := n.mempool.AddTransaction(tx); err != nil {
 		return nil, fmt.Errorf("failed to add transaction to mempool: %w", err)
 	}
+	n.publishNewTransaction(tx)
 
 	// Broadcast é feito automaticamente pelo callback do minerador
 
@@ -1056,6 +1666,7 @@ func (n *Node) CreateStakeTransaction(amount, fee uint64) (*blockchain.Transacti
 	if err := n.mempool.AddTransaction(tx); err != nil {
 		return nil, fmt.Errorf("failed to add stake transaction to mempool: %w", err)
 	}
+	n.publishNewTransaction(tx)
 
 	return tx, nil
 }
@@ -1070,6 +1681,37 @@ func (n *Node) CreateUnstakeTransaction(amount, fee uint64) (*blockchain.Transac
 	if err := n.mempool.AddTransaction(tx); err != nil {
 		return nil, fmt.Errorf("failed to add unstake transaction to mempool: %w", err)
 	}
+	n.publishNewTransaction(tx)
+
+	return tx, nil
+}
+
+// CreateRegisterNameTransaction cria uma transação de registro do nome de exibição do nó
+func (n *Node) CreateRegisterNameTransaction(name string, fee uint64) (*blockchain.Transaction, error) {
+	tx, err := n.miner.CreateRegisterNameTransaction(name, fee)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := n.mempool.AddTransaction(tx); err != nil {
+		return nil, fmt.Errorf("failed to add register name transaction to mempool: %w", err)
+	}
+	n.publishNewTransaction(tx)
+
+	return tx, nil
+}
+
+// CreateDoubleSignEvidenceTransaction cria uma transação que denuncia uma assinatura dupla
+func (n *Node) CreateDoubleSignEvidenceTransaction(evidence *blockchain.SlashingEvidence, fee uint64) (*blockchain.Transaction, error) {
+	tx, err := n.miner.CreateDoubleSignEvidenceTransaction(evidence, fee)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := n.mempool.AddTransaction(tx); err != nil {
+		return nil, fmt.Errorf("failed to add double sign evidence transaction to mempool: %w", err)
+	}
+	n.publishNewTransaction(tx)
 
 	return tx, nil
 }
@@ -1182,6 +1824,17 @@ func (n *Node) requestSync(peerID string) {
 
 	fmt.Printf("[%s] 📡 Data channel with %s is ready, starting sync\n", n.ID, peerID)
 
+	// O horário vai antes dos blocos, para o peer já validá-los com o relógio ajustado
+	n.sendTime(peer)
+
+	// Anuncia a compressão antes dos pedidos de sync, para o peer já responder comprimido
+	if n.compressMessages {
+		n.sendCapabilities(peer)
+	}
+
+	// Evidências de assinatura dupla ainda puníveis: o peer pode ter conectado depois do repasse
+	n.sendSlashEvidence(peer)
+
 	currentHeight := n.chain.GetHeight()
 	fmt.Printf("[%s] 📊 Current chain height: %d\n", n.ID, currentHeight)
 
@@ -1197,25 +1850,97 @@ func (n *Node) requestSync(peerID string) {
 		}
 	}
 
-	// Solicita blocos a partir da próxima altura (sync regular ou complementar ao checkpoint)
+	// Download paralelo em andamento: o peer passa a receber trechos dele
+	if n.joinDownload() {
+		return
+	}
+
+	// Solicita blocos a partir da próxima altura (sync regular ou complementar ao checkpoint).
+	// Em headers-first, os corpos só são pedidos depois que os headers forem validados.
+	if n.headersFirstSync {
+		n.requestHeaders(peerID, currentHeight+1)
+		return
+	}
+	n.sendSyncRequest(peerID, currentHeight+1)
+}
+
+// ForceSyncFrom dispara agora o sync (checkpoint + blocos) com um peer já conectado, sem
+// esperar um novo anúncio de bloco. Uso administrativo, p.ex. para destravar um nó atrasado.
+func (n *Node) ForceSyncFrom(peerID string) error {
+	n.peersMutex.RLock()
+	peer := n.peers[peerID]
+	n.peersMutex.RUnlock()
+
+	if peer == nil {
+		return fmt.Errorf("peer %s not found", peerID)
+	}
+	if !peer.IsReady() {
+		return fmt.Errorf("peer %s is not ready", peerID)
+	}
+
+	currentHeight := n.chain.GetHeight()
+	fmt.Printf("[%s] 🔄 Forced sync from %s (current height: %d)\n", n.ID, peerID, currentHeight)
+
+	if n.checkpointConfig != nil && n.checkpointConfig.Enabled {
+		if err := n.RequestCheckpointFromPeer(peerID, 0); err != nil {
+			fmt.Printf("[%s] ⚠️  Failed to request checkpoint from %s: %v, falling back to regular sync\n",
+				n.ID, peerID, err)
+		}
+	}
+
+	if n.headersFirstSync {
+		n.requestHeaders(peerID, currentHeight+1)
+		return nil
+	}
+	if err := n.sendSyncRequestRange(peerID, currentHeight+1, 0); err != nil {
+		return fmt.Errorf("failed to request blocks from %s: %w", peerID, err)
+	}
+	return nil
+}
+
+// sendSyncRequest envia uma requisição de blocos a partir de fromHeight para o peer
+func (n *Node) sendSyncRequest(peerID string, fromHeight uint64) {
+	n.sendSyncRequestRange(peerID, fromHeight, 0)
+}
+
+// sendSyncRequestRange envia uma requisição dos blocos de fromHeight a toHeight (0 = sem limite)
+func (n *Node) sendSyncRequestRange(peerID string, fromHeight, toHeight uint64) error {
 	req := SyncRequest{
-		FromHeight: currentHeight + 1,
+		FromHeight: fromHeight,
+		ToHeight:   toHeight,
 	}
 
 	fmt.Printf("[%s] 📤 Requesting blocks from height %d\n", n.ID, req.FromHeight)
 
-	data, err := json.Marshal(req)
-	if err != nil {
-		fmt.Printf("[%s] Failed to marshal sync request: %v\n", n.ID, err)
-		return
+	if err := n.deliverSyncRequest(peerID, req); err != nil {
+		fmt.Printf("[%s] Failed to send sync request to %s: %v\n", n.ID, peerID, err)
+		return err
 	}
 
-	// peer já foi obtido anteriormente, pode reutilizar
-	if err := peer.SendMessage("sync_request", data); err != nil {
-		fmt.Printf("[%s] Failed to send sync request to %s: %v\n", n.ID, peerID, err)
-	} else {
-		fmt.Printf("[%s] Requested sync from %s (from height %d)\n", n.ID, peerID, req.FromHeight)
+	n.metrics.syncing.Store(true)
+	fmt.Printf("[%s] Requested sync from %s (from height %d)\n", n.ID, peerID, req.FromHeight)
+	return nil
+}
+
+// deliverSyncRequest envia a requisição ao peer (ou ao sendSyncRequest configurado)
+func (n *Node) deliverSyncRequest(peerID string, req SyncRequest) error {
+	if n.sendSyncRequestHook != nil {
+		return n.sendSyncRequestHook(peerID, req)
+	}
+
+	n.peersMutex.RLock()
+	peer := n.peers[peerID]
+	n.peersMutex.RUnlock()
+
+	if peer == nil {
+		return fmt.Errorf("peer %s not found", peerID)
 	}
+
+	data, err := json.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("failed to marshal sync request: %w", err)
+	}
+	return peer.SendMessage("sync_request", data)
 }
 
 // addCheckpointHashToBlock adiciona o hash do último checkpoint ao bloco
@@ -1262,9 +1987,9 @@ func (n *Node) tryCreateCheckpoint(currentHeight uint64) {
 		return
 	}
 
-	// Altura do bloco para o qual vamos criar o checkpoint
-	// Checkpoint é do estado no bloco (currentHeight - interval)
-	checkpointHeight := currentHeight - interval
+	// O checkpoint registra o estado após o bloco currentHeight (chamado depois de adicioná-lo),
+	// permitindo que peers restaurem esse estado e continuem a partir do bloco seguinte
+	checkpointHeight := currentHeight
 
 	// Verificar se já existe um checkpoint nesta altura (pode ter sido recebido via sync)
 	if existingCP, err := blockchain.LoadCheckpointFromDB(n.db, checkpointHeight); err == nil && existingCP != nil {
@@ -1288,6 +2013,7 @@ func (n *Node) tryCreateCheckpoint(currentHeight uint64) {
 		checkpointHeight,
 		time.Now().Unix(),
 		accounts,
+		n.chain.TotalSupply(),
 		n.checkpointConfig.CSVDelimiter,
 	)
 	if err != nil {
@@ -1295,15 +2021,34 @@ func (n *Node) tryCreateCheckpoint(currentHeight uint64) {
 		return
 	}
 
-	// Salvar checkpoint no LevelDB
+	// Âncora do checkpoint: o bloco em que o estado foi tirado (coberto pelas assinaturas)
+	anchor, found := n.chain.GetBlockByHeight(checkpointHeight)
+	if !found {
+		fmt.Printf("[%s] Failed to create checkpoint: block %d not found\n", n.ID, checkpointHeight)
+		return
+	}
+	checkpoint.BlockHash = anchor.Hash
+
+	// Assinar (se validador) e salvar checkpoint no LevelDB
+	n.checkpointSigMutex.Lock()
+	ownSignature := n.endorseCheckpoint(checkpoint)
 	err = blockchain.SaveCheckpointToDB(n.db, checkpoint, n.checkpointConfig.Compression)
+	n.checkpointSigMutex.Unlock()
 	if err != nil {
 		fmt.Printf("[%s] Failed to save checkpoint: %v\n", n.ID, err)
 		return
 	}
 
-	fmt.Printf("[%s] Checkpoint created and saved: height=%d, hash=%s, accounts=%d\n",
-		n.ID, checkpointHeight, checkpoint.Hash[:16], len(checkpoint.Accounts))
+	fmt.Printf("[%s] Checkpoint created and saved: height=%d, hash=%s, accounts=%d, signatures=%d\n",
+		n.ID, checkpointHeight, checkpoint.Hash[:16], len(checkpoint.Accounts), len(checkpoint.Signatures))
+
+	if ownSignature != nil {
+		n.broadcastCheckpointSignatureExcept(CheckpointSignatureMessage{
+			Height:    checkpoint.Height,
+			Hash:      checkpoint.Hash,
+			Signature: *ownSignature,
+		}, "")
+	}
 
 	// Armazenar checkpoint hash para incluir em próximos blocos
 	n.checkpointMutex.Lock()
@@ -1321,11 +2066,125 @@ func (n *Node) tryCreateCheckpoint(currentHeight uint64) {
 	n.tryPruneBlocks(currentHeight)
 }
 
+// endorseCheckpoint incorpora as assinaturas de peers recebidas antes da criação do checkpoint
+// e o assina se o nó tiver stake. Retorna a assinatura do nó (nil se não assinou).
+// Deve ser chamado com checkpointSigMutex.
+func (n *Node) endorseCheckpoint(checkpoint *blockchain.Checkpoint) *blockchain.CheckpointSignature {
+	genesisHash := n.chain.GetGenesis().Hash
+
+	for height := range n.pendingCheckpointSigs {
+		if height <= checkpoint.Height {
+			for _, msg := range n.pendingCheckpointSigs[height] {
+				if height != checkpoint.Height || msg.Hash != checkpoint.Hash {
+					continue
+				}
+				if _, err := checkpoint.AddSignature(msg.Signature, genesisHash); err != nil {
+					fmt.Printf("[%s] Discarding checkpoint signature from %s: %v\n", n.ID, msg.Signature.Validator, err)
+				}
+			}
+			delete(n.pendingCheckpointSigs, height)
+		}
+	}
+
+	if n.wallet == nil || n.chain.GetStake(n.wallet.GetAddress()) == 0 {
+		return nil
+	}
+	if err := checkpoint.Sign(n.wallet, genesisHash); err != nil {
+		fmt.Printf("[%s] Failed to sign checkpoint %d: %v\n", n.ID, checkpoint.Height, err)
+		return nil
+	}
+
+	for _, sig := range checkpoint.Signatures {
+		if sig.Validator == n.wallet.GetAddress() {
+			return &sig
+		}
+	}
+	return nil
+}
+
+// handleCheckpointSignature adiciona o endosso de um validador ao checkpoint local e o repassa.
+// Endossos de checkpoints ainda não criados aguardam até a próxima altura de checkpoint.
+func (n *Node) handleCheckpointSignature(peerID string, data []byte) {
+	if n.checkpointConfig == nil || !n.checkpointConfig.Enabled {
+		return
+	}
+
+	var msg CheckpointSignatureMessage
+	if err := json.Unmarshal(data, &msg); err != nil {
+		fmt.Printf("[%s] Failed to parse checkpoint signature from %s: %v\n", n.ID, peerID, err)
+		return
+	}
+
+	n.checkpointSigMutex.Lock()
+	defer n.checkpointSigMutex.Unlock()
+
+	checkpoint, err := blockchain.LoadCheckpointFromDB(n.db, msg.Height)
+	if err != nil {
+		n.checkpointMutex.RLock()
+		lastHeight := n.lastCheckpointHeight
+		n.checkpointMutex.RUnlock()
+
+		// Só guarda endossos do próximo checkpoint, para não acumular mensagens arbitrárias
+		if msg.Height > lastHeight && msg.Height <= lastHeight+uint64(n.checkpointConfig.Interval) {
+			n.pendingCheckpointSigs[msg.Height] = append(n.pendingCheckpointSigs[msg.Height], msg)
+		}
+		return
+	}
+
+	if checkpoint.Hash != msg.Hash {
+		fmt.Printf("[%s] Checkpoint signature from %s does not match local checkpoint %d\n", n.ID, msg.Signature.Validator, msg.Height)
+		return
+	}
+
+	added, err := checkpoint.AddSignature(msg.Signature, n.chain.GetGenesis().Hash)
+	if err != nil {
+		fmt.Printf("[%s] Invalid checkpoint signature from %s: %v\n", n.ID, peerID, err)
+		return
+	}
+	if !added {
+		return
+	}
+
+	if err := blockchain.SaveCheckpointSignaturesToDB(n.db, checkpoint); err != nil {
+		fmt.Printf("[%s] Failed to save checkpoint signature: %v\n", n.ID, err)
+		return
+	}
+
+	fmt.Printf("[%s] Checkpoint %d endorsed by %s (%d signatures)\n",
+		n.ID, msg.Height, msg.Signature.Validator, len(checkpoint.Signatures))
+
+	n.broadcastCheckpointSignatureExcept(msg, peerID)
+}
+
+// broadcastCheckpointSignatureExcept envia um endosso de checkpoint para todos os peers exceto um
+func (n *Node) broadcastCheckpointSignatureExcept(msg CheckpointSignatureMessage, exceptPeerID string) {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		fmt.Printf("[%s] Failed to marshal checkpoint signature: %v\n", n.ID, err)
+		return
+	}
+
+	n.peersMutex.RLock()
+	defer n.peersMutex.RUnlock()
+
+	for _, peer := range n.peers {
+		if peer.ID != exceptPeerID {
+			if err := peer.SendMessage("checkpoint_signature", data); err != nil {
+				fmt.Printf("[%s] Failed to send checkpoint signature to peer %s: %v\n", n.ID, peer.ID, err)
+			}
+		}
+	}
+}
+
 // collectCurrentState coleta o estado atual de todas as contas
 func (n *Node) collectCurrentState() map[string]*blockchain.AccountState {
 	balances := n.chain.GetContext().GetAllBalances()
 	stakes := n.chain.GetContext().GetAllStakes()
 	nonces := n.chain.GetContext().GetAllNonces()
+	names := n.chain.GetContext().GetAllNames()
+	unbonding := n.chain.GetContext().GetAllUnbonding()
+	releases := n.chain.GetContext().GetAllUnbondingReleases()
+	slashHeights := n.chain.GetContext().GetAllSlashHeights()
 
 	// Unir todos os endereços
 	allAddresses := make(map[string]bool)
@@ -1338,54 +2197,60 @@ func (n *Node) collectCurrentState() map[string]*blockchain.AccountState {
 	for addr := range nonces {
 		allAddresses[addr] = true
 	}
+	for addr := range names {
+		allAddresses[addr] = true
+	}
+	for addr := range unbonding {
+		allAddresses[addr] = true
+	}
+	for addr := range slashHeights {
+		allAddresses[addr] = true
+	}
 
 	// Criar mapa de estados
 	accounts := make(map[string]*blockchain.AccountState)
 	for addr := range allAddresses {
 		accounts[addr] = &blockchain.AccountState{
-			Address: addr,
-			Balance: balances[addr],
-			Stake:   stakes[addr],
-			Nonce:   nonces[addr],
+			Address:          addr,
+			Balance:          balances[addr],
+			Stake:            stakes[addr],
+			Nonce:            nonces[addr],
+			Name:             names[addr],
+			Unbonding:        unbonding[addr],
+			UnbondingRelease: releases[addr],
+			SlashHeight:      slashHeights[addr],
 		}
 	}
 
 	return accounts
 }
 
-// tryPruneBlocks tenta fazer pruning de blocos antigos
+// tryPruneBlocks tenta fazer pruning de blocos antigos na criação de um checkpoint
+// (checkpoint.keep_in_memory). Com PruneConfig o pruning é feito a cada bloco (pruneAfterBlock).
 func (n *Node) tryPruneBlocks(currentHeight uint64) {
-	if n.checkpointConfig == nil || !n.checkpointConfig.Enabled {
+	if n.pruneConfig != nil || n.checkpointConfig == nil || !n.checkpointConfig.Enabled {
 		return
 	}
 
-	// Verificar se temos blocos suficientes para fazer pruning
-	blocksInMemory := n.GetBlocksInMemory()
-	keepInMemory := n.checkpointConfig.KeepInMemory
-
-	if blocksInMemory <= keepInMemory {
-		return // Não precisa fazer pruning ainda
-	}
-
-	fmt.Printf("[%s] Pruning old blocks: current=%d, in_memory=%d, keep=%d\n",
-		n.ID, blocksInMemory, blocksInMemory, keepInMemory)
+	n.pruneMemory(n.checkpointConfig.KeepInMemory)
+}
 
-	// Obter ponteiro para o slice de blocos da chain
-	allBlocks := n.chain.GetAllBlocksPointer()
-	if allBlocks == nil {
-		return
+// validateReceivedCheckpoint valida um checkpoint recebido de um peer: o hash do estado e, a menos
+// que allow_unsigned esteja ativo, as assinaturas de validadores conhecidos com o quorum
+// configurado do stake (só elas garantem que o estado não foi forjado por quem o enviou)
+func (n *Node) validateReceivedCheckpoint(cp *blockchain.Checkpoint) error {
+	if err := blockchain.ValidateCheckpointHash(cp, n.checkpointConfig.CSVDelimiter); err != nil {
+		return err
 	}
-
-	err := blockchain.PruneOldBlocks(n.db, allBlocks, keepInMemory)
-	if err != nil {
-		fmt.Printf("[%s] Failed to prune old blocks: %v\n", n.ID, err)
-		return
+	if n.checkpointConfig.AllowUnsigned {
+		return nil
 	}
-
-	fmt.Printf("[%s] Blocks pruned successfully: now %d blocks in memory\n", n.ID, len(*allBlocks))
+	return blockchain.VerifyCheckpointQuorum(cp, n.chain.GetGenesis().Hash, n.chain.GetValidators(), n.checkpointConfig.SignatureQuorum)
 }
 
-// validateBlockCheckpointHash valida o hash de checkpoint em um bloco recebido
+// validateBlockCheckpointHash valida o hash de checkpoint em um bloco recebido. O checkpoint
+// referenciado precisa ser um dos nossos (criado localmente ou recebido com o endosso dos
+// validadores); com allow_unsigned, o checkpoint do peer é aceito.
 func (n *Node) validateBlockCheckpointHash(block *blockchain.Block) error {
 	if block.Header.CheckpointHash == "" {
 		return nil // Nenhum checkpoint para validar
@@ -1401,6 +2266,11 @@ func (n *Node) validateBlockCheckpointHash(block *blockchain.Block) error {
 	if err == nil {
 		// Temos o checkpoint no disco, validar hash
 		if checkpoint.Hash != block.Header.CheckpointHash {
+			if !n.checkpointConfig.AllowUnsigned {
+				return fmt.Errorf("checkpoint hash mismatch at height %d: block=%s, local=%s",
+					checkpointHeight, block.Header.CheckpointHash[:16], checkpoint.Hash[:16])
+			}
+
 			// Se o hash não bate, mas estamos recebendo de um peer,
 			// aceitar o checkpoint do peer e atualizar o nosso
 			fmt.Printf("[%s] ⚠️  Checkpoint hash mismatch, accepting peer's checkpoint: peer=%s, local=%s\n",
@@ -1423,8 +2293,11 @@ func (n *Node) validateBlockCheckpointHash(block *blockchain.Block) error {
 		return nil
 	}
 
-	// Se estamos na altura correta mas não temos o checkpoint salvo,
-	// aceitar o checkpoint do peer (ele é a fonte confiável)
+	// Se estamos na altura correta mas não temos o checkpoint salvo, não há como verificá-lo:
+	// o bloco é recusado, a menos que allow_unsigned aceite o checkpoint do peer
+	if !n.checkpointConfig.AllowUnsigned {
+		return fmt.Errorf("no trusted checkpoint at height %d to validate block %d", checkpointHeight, block.Header.Height)
+	}
 	fmt.Printf("[%s] No local checkpoint found, accepting peer's checkpoint hash\n", n.ID)
 	return nil
 }
@@ -1455,30 +2328,85 @@ func (n *Node) loadLastCheckpoint() {
 		n.ID, checkpoint.Height, checkpoint.Hash[:16])
 }
 
+// restoreFromDiskCheckpoint restaura a chain a partir do último checkpoint salvo cujo bloco está no disco
+func (n *Node) restoreFromDiskCheckpoint() error {
+	height, err := blockchain.GetLastCheckpointHeight(n.db)
+	if err != nil {
+		return err
+	}
+
+	checkpoint, err := blockchain.LoadCheckpointFromDB(n.db, height)
+	if err != nil {
+		return err
+	}
+
+	block, err := blockchain.LoadBlockFromDB(n.db, height)
+	if err != nil {
+		return fmt.Errorf("checkpoint block %d not on disk: %w", height, err)
+	}
+	if checkpoint.BlockHash != "" && block.Hash != checkpoint.BlockHash {
+		return fmt.Errorf("block %d on disk does not match checkpoint block hash %s", height, checkpoint.BlockHash)
+	}
+
+	if err := n.chain.RestoreFromState(checkpoint.Height, checkpoint.Accounts, checkpoint.Minted, block.Hash); err != nil {
+		return err
+	}
+
+	fmt.Printf("[%s] Restored state from checkpoint on disk at height %d\n", n.ID, height)
+	return nil
+}
+
 // loadChainFromDisk carrega a blockchain salva no disco
 func (n *Node) loadChainFromDisk() error {
+	// Bancos criados antes do índice de transações precisam ser indexados uma vez
+	if indexed, err := blockchain.BackfillTransactionIndex(n.db); err != nil {
+		fmt.Printf("[%s] Warning: failed to backfill transaction index: %v\n", n.ID, err)
+	} else if indexed > 0 {
+		fmt.Printf("[%s] 🗂️  Indexed transactions of %d blocks from disk\n", n.ID, indexed)
+	}
+
 	// Obter altura da chain salva
-	chainHeightData, err := n.db.Get([]byte("metadata-chain-height"), nil)
-	if err != nil {
+	savedHeight, savedTip, err := blockchain.LoadChainTip(n.db)
+	if errors.Is(err, leveldb.ErrNotFound) {
 		// Não há chain salva, isso é normal na primeira execução
 		return nil
 	}
+	if err != nil {
+		return err
+	}
 
-	var savedHeight uint64
-	if _, err := fmt.Sscanf(string(chainHeightData), "%d", &savedHeight); err != nil {
-		return fmt.Errorf("failed to parse saved chain height: %w", err)
+	// Versões anteriores podiam regravar uma altura menor ao salvar blocos podados da memória:
+	// blocos consecutivos gravados além da altura salva também são carregados
+	extendedHeight := savedHeight
+	for {
+		if _, err := blockchain.LoadBlockFromDB(n.db, extendedHeight+1); err != nil {
+			break
+		}
+		extendedHeight++
 	}
 
 	currentHeight := n.chain.GetHeight()
+	if extendedHeight > savedHeight && extendedHeight > currentHeight {
+		fmt.Printf("[%s] Found blocks on disk up to height %d beyond saved height %d\n", n.ID, extendedHeight, savedHeight)
+	}
 
 	// Se a altura salva é menor ou igual à atual, não precisa carregar
-	if savedHeight <= currentHeight {
+	if extendedHeight <= currentHeight {
 		fmt.Printf("[%s] Chain already up to date (saved: %d, current: %d)\n", n.ID, savedHeight, currentHeight)
 		return nil
 	}
 
 	fmt.Printf("[%s] Loading chain from disk: saved height=%d, current=%d\n", n.ID, savedHeight, currentHeight)
 
+	// Se a chain foi sincronizada via checkpoint, os blocos anteriores a ele não estão no disco:
+	// restaurar o estado do último checkpoint e carregar apenas os blocos seguintes
+	if _, err := blockchain.LoadBlockFromDB(n.db, currentHeight+1); err != nil {
+		if restoreErr := n.restoreFromDiskCheckpoint(); restoreErr != nil {
+			return fmt.Errorf("failed to load block at height %d: %w", currentHeight+1, err)
+		}
+		currentHeight = n.chain.GetHeight()
+	}
+
 	// Carregar blocos do disco a partir da próxima altura
 	blocksLoaded := 0
 	for height := currentHeight + 1; height <= savedHeight; height++ {
@@ -1495,6 +2423,23 @@ func (n *Node) loadChainFromDisk() error {
 		blocksLoaded++
 	}
 
+	// Blocos além da altura salva que não se encaixam (ex.: restos de um fork) são ignorados
+	for height := n.chain.GetHeight() + 1; height <= extendedHeight; height++ {
+		block, err := blockchain.LoadBlockFromDB(n.db, height)
+		if err == nil {
+			err = n.chain.AddBlock(block)
+		}
+		if err != nil {
+			fmt.Printf("[%s] Warning: stopped loading unsaved blocks at height %d: %v\n", n.ID, height, err)
+			break
+		}
+		blocksLoaded++
+	}
+
+	if last := n.chain.GetLastBlock(); savedTip != "" && n.chain.GetHeight() == savedHeight && last.Hash != savedTip {
+		fmt.Printf("[%s] Warning: loaded tip %s does not match saved tip %s\n", n.ID, last.Hash, savedTip)
+	}
+
 	fmt.Printf("[%s] Successfully loaded %d blocks from disk. New height: %d\n",
 		n.ID, blocksLoaded, n.chain.GetHeight())
 
@@ -0,0 +1,236 @@
+package node
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/krakovia/blockchain/pkg/blockchain"
+)
+
+// DefaultMaxFutureBlocks é quantas alturas à frente da ponta um bloco recebido por gossip
+// pode estar para ser guardado como órfão
+const DefaultMaxFutureBlocks = 100
+
+const (
+	// maxOrphans limita o total de órfãos guardados; acima disso sai o mais antigo do peer que
+	// mais ocupa o buffer
+	maxOrphans = 256
+	// maxOrphansPerPeer limita os órfãos guardados de um mesmo peer; acima disso sai o mais
+	// antigo dele, sem afetar os dos outros peers
+	maxOrphansPerPeer = 32
+	// maxOrphansPerParent limita os blocos concorrentes guardados sobre o mesmo pai
+	maxOrphansPerParent = 4
+	// orphanExpiry tempo que um órfão fica guardado esperando o pai chegar
+	orphanExpiry = 10 * time.Minute
+)
+
+// orphanEntry órfão guardado, o peer que o enviou e quando ele chegou
+type orphanEntry struct {
+	block    *blockchain.Block
+	peerID   string
+	received time.Time
+}
+
+// orphanPool guarda blocos recebidos por gossip cujo pai ainda não chegou, indexados pelo hash
+// do pai: quando o pai entra na chain, os filhos são aplicados em seguida
+type orphanPool struct {
+	mu       sync.Mutex
+	byParent map[string][]*orphanEntry
+	byPeer   map[string]int // Órfãos guardados por peer
+	count    int
+}
+
+// newOrphanPool cria um buffer de órfãos vazio
+func newOrphanPool() *orphanPool {
+	return &orphanPool{
+		byParent: make(map[string][]*orphanEntry),
+		byPeer:   make(map[string]int),
+	}
+}
+
+// add guarda o bloco enviado por peerID. Retorna false se ele já estiver no buffer ou se o pai
+// já tiver filhos demais guardados. Com o peer no seu limite ou o buffer cheio, abre espaço
+// descartando o órfão mais antigo do próprio peer ou do peer que mais ocupa o buffer, para que
+// um peer não tome as vagas dos outros.
+func (p *orphanPool) add(peerID string, block *blockchain.Block, now time.Time) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.expireLocked(now)
+
+	parent := block.Header.PreviousHash
+	entries := p.byParent[parent]
+	if len(entries) >= maxOrphansPerParent {
+		return false
+	}
+	for _, entry := range entries {
+		if entry.block.Hash == block.Hash {
+			return false
+		}
+	}
+
+	switch {
+	case p.byPeer[peerID] >= maxOrphansPerPeer:
+		p.evictOldestLocked(peerID)
+	case p.count >= maxOrphans:
+		p.evictOldestLocked(p.largestPeerLocked())
+	}
+
+	p.byParent[parent] = append(p.byParent[parent], &orphanEntry{block: block, peerID: peerID, received: now})
+	p.byPeer[peerID]++
+	p.count++
+	return true
+}
+
+// largestPeerLocked retorna o peer com mais órfãos guardados (deve ser chamado com lock)
+func (p *orphanPool) largestPeerLocked() string {
+	var largest string
+	for peerID, count := range p.byPeer {
+		if count > p.byPeer[largest] {
+			largest = peerID
+		}
+	}
+	return largest
+}
+
+// evictOldestLocked descarta o órfão mais antigo enviado por peerID (deve ser chamado com lock)
+func (p *orphanPool) evictOldestLocked(peerID string) {
+	var oldest *orphanEntry
+	for _, entries := range p.byParent {
+		for _, entry := range entries {
+			if entry.peerID == peerID && (oldest == nil || entry.received.Before(oldest.received)) {
+				oldest = entry
+			}
+		}
+	}
+	if oldest != nil {
+		p.removeLocked(func(entry *orphanEntry) bool { return entry == oldest })
+	}
+}
+
+// takeChildren remove e retorna os órfãos cujo pai é parentHash
+func (p *orphanPool) takeChildren(parentHash string) []*blockchain.Block {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	entries := p.byParent[parentHash]
+	delete(p.byParent, parentHash)
+
+	children := make([]*blockchain.Block, len(entries))
+	for i, entry := range entries {
+		children[i] = entry.block
+		p.forgetLocked(entry)
+	}
+	return children
+}
+
+// prune descarta os órfãos em alturas já cobertas pela chain e os que expiraram
+func (p *orphanPool) prune(tip uint64, now time.Time) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.expireLocked(now)
+	p.removeLocked(func(entry *orphanEntry) bool { return entry.block.Header.Height <= tip })
+}
+
+// expireLocked descarta os órfãos guardados há mais de orphanExpiry (deve ser chamado com lock)
+func (p *orphanPool) expireLocked(now time.Time) {
+	p.removeLocked(func(entry *orphanEntry) bool { return now.Sub(entry.received) > orphanExpiry })
+}
+
+// removeLocked descarta os órfãos para os quais drop retorna true (deve ser chamado com lock)
+func (p *orphanPool) removeLocked(drop func(entry *orphanEntry) bool) {
+	for parent, entries := range p.byParent {
+		kept := entries[:0]
+		for _, entry := range entries {
+			if drop(entry) {
+				p.forgetLocked(entry)
+			} else {
+				kept = append(kept, entry)
+			}
+		}
+		if len(kept) == 0 {
+			delete(p.byParent, parent)
+		} else {
+			p.byParent[parent] = kept
+		}
+	}
+}
+
+// forgetLocked desconta um órfão que saiu do buffer dos contadores (deve ser chamado com lock)
+func (p *orphanPool) forgetLocked(entry *orphanEntry) {
+	p.count--
+	if p.byPeer[entry.peerID]--; p.byPeer[entry.peerID] <= 0 {
+		delete(p.byPeer, entry.peerID)
+	}
+}
+
+// size retorna quantos órfãos estão guardados
+func (p *orphanPool) size() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.count
+}
+
+// GetOrphanCount retorna quantos blocos recebidos por gossip aguardam a chain alcançá-los
+func (n *Node) GetOrphanCount() int {
+	return n.orphans.size()
+}
+
+// bufferOrphan guarda um bloco de gossip à frente da ponta (tip) e pede ao peer os blocos que
+// faltam. Blocos além de maxFutureBlocks são descartados.
+func (n *Node) bufferOrphan(peerID string, block *blockchain.Block, tip uint64) {
+	if block.Header.Height-tip > n.maxFutureBlocks {
+		fmt.Printf("[%s] Ignoring block %d from %s: too far ahead of tip %d (max %d)\n",
+			n.ID, block.Header.Height, peerID, tip, n.maxFutureBlocks)
+		return
+	}
+
+	// Só blocos bem formados e assinados por um validador com stake ocupam o buffer
+	if stake := n.chain.GetStake(block.Header.ValidatorAddr); stake == 0 || stake < n.chain.GetConfig().MinValidatorStake {
+		err := fmt.Errorf("validator %s has no stake", block.Header.ValidatorAddr)
+		fmt.Printf("[%s] Ignoring orphan block %d from %s: %v\n", n.ID, block.Header.Height, peerID, err)
+		n.logRejectedBlock(peerID, "orphan", block, err)
+		return
+	}
+	if err := block.ValidateAt(n.chain.Now(), n.chain.GetConfig().ClockDrift()); err != nil {
+		fmt.Printf("[%s] Ignoring invalid orphan block %d from %s: %v\n", n.ID, block.Header.Height, peerID, err)
+		n.logRejectedBlock(peerID, "orphan", block, err)
+		return
+	}
+	if err := block.VerifySignature(); err != nil {
+		fmt.Printf("[%s] Ignoring orphan block %d from %s: %v\n", n.ID, block.Header.Height, peerID, err)
+		n.logRejectedBlock(peerID, "orphan", block, err)
+		return
+	}
+
+	if !n.orphans.add(peerID, block, time.Now()) {
+		return
+	}
+
+	fmt.Printf("[%s] 🧩 Buffered orphan block %d from %s (tip %d)\n", n.ID, block.Header.Height, peerID, tip)
+
+	// Faltam blocos entre a ponta e o órfão: pede ao peer que o enviou
+	if !n.metrics.syncing.Load() {
+		go n.sendSyncRequest(peerID, tip+1)
+	}
+}
+
+// connectOrphans aplica os órfãos que passaram a conectar à ponta da chain: os filhos da ponta
+// e, recursivamente, os filhos de cada órfão aplicado
+func (n *Node) connectOrphans() {
+	last := n.chain.GetLastBlock()
+	n.orphans.prune(last.Header.Height, time.Now())
+	n.connectOrphanChildren(last.Hash)
+}
+
+// connectOrphanChildren aplica os órfãos cujo pai é parentHash e, em seguida, os filhos deles
+func (n *Node) connectOrphanChildren(parentHash string) {
+	for _, orphan := range n.orphans.takeChildren(parentHash) {
+		fmt.Printf("[%s] 🧩 Orphan block %d now connects to the chain\n", n.ID, orphan.Header.Height)
+		if n.applyGossipBlock("", orphan) {
+			n.connectOrphanChildren(orphan.Hash)
+		}
+	}
+}
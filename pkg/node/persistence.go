@@ -0,0 +1,285 @@
+package node
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/krakovia/blockchain/pkg/blockchain"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+// Valores padrão para as tentativas de salvar blocos
+const (
+	DefaultBlockSaveRetries = 3
+	DefaultBlockSaveBackoff = 100 * time.Millisecond
+)
+
+// DefaultSyncAssemblyTimeout é o tempo máximo padrão para montar uma resposta de sync
+const DefaultSyncAssemblyTimeout = 2 * time.Second
+
+// DefaultSyncBatchSize é o número padrão de blocos aplicados e gravados por lote na sincronização
+const DefaultSyncBatchSize = 100
+
+// DefaultCompactInterval é o intervalo mínimo padrão entre compactações do LevelDB na inicialização
+const DefaultCompactInterval = 24 * time.Hour
+
+// lastCompactionKey guarda o instante (unix) da última compactação do banco
+const lastCompactionKey = "metadata-last-compaction"
+
+// DBCompactor abstrai a compactação do banco (implementado por *leveldb.DB)
+type DBCompactor interface {
+	CompactRange(r util.Range) error
+}
+
+// BlockStore abstrai a persistência de blocos (permite injetar mocks em testes)
+type BlockStore interface {
+	SaveBlock(block *blockchain.Block) error
+}
+
+// BatchBlockStore é implementado por stores capazes de gravar vários blocos em uma única
+// escrita atômica. Stores que não o implementam recebem os blocos do lote um a um.
+type BatchBlockStore interface {
+	SaveBlocks(blocks []*blockchain.Block) error
+}
+
+// BlockLoader abstrai a leitura de blocos do disco (permite injetar mocks em testes)
+type BlockLoader interface {
+	LoadBlock(height uint64) (*blockchain.Block, error)
+}
+
+// levelDBBlockStore implementa BlockStore e BlockLoader usando o LevelDB do nó
+type levelDBBlockStore struct {
+	db *leveldb.DB
+}
+
+// SaveBlock salva o bloco no LevelDB
+func (s *levelDBBlockStore) SaveBlock(block *blockchain.Block) error {
+	return blockchain.SaveBlockToDB(s.db, block)
+}
+
+// SaveBlocks salva os blocos no LevelDB em um único batch
+func (s *levelDBBlockStore) SaveBlocks(blocks []*blockchain.Block) error {
+	return blockchain.SaveBlocksToDB(s.db, blocks)
+}
+
+// LoadBlock carrega o bloco do LevelDB pela altura
+func (s *levelDBBlockStore) LoadBlock(height uint64) (*blockchain.Block, error) {
+	return blockchain.LoadBlockFromDB(s.db, height)
+}
+
+// blockSaver salva blocos com retry e backoff exponencial
+type blockSaver struct {
+	mu      sync.RWMutex
+	store   BlockStore
+	retries int           // Tentativas extras após a primeira falha
+	backoff time.Duration // Espera antes da primeira nova tentativa (dobra a cada falha)
+	lastErr error         // Último erro definitivo (após esgotar as tentativas)
+}
+
+// newBlockSaver cria um blockSaver aplicando valores padrão
+func newBlockSaver(store BlockStore, retries int, backoff time.Duration) *blockSaver {
+	if retries == 0 {
+		retries = DefaultBlockSaveRetries
+	}
+	if retries < 0 {
+		retries = 0
+	}
+	if backoff <= 0 {
+		backoff = DefaultBlockSaveBackoff
+	}
+
+	return &blockSaver{
+		store:   store,
+		retries: retries,
+		backoff: backoff,
+	}
+}
+
+// save tenta salvar o bloco até retries+1 vezes, aguardando entre as tentativas
+func (s *blockSaver) save(block *blockchain.Block) error {
+	s.mu.RLock()
+	store := s.store
+	s.mu.RUnlock()
+
+	return s.retry(fmt.Sprintf("block %d", block.Header.Height), func() error {
+		return store.SaveBlock(block)
+	})
+}
+
+// saveBatch salva blocos consecutivos em uma única escrita se o store suportar lotes
+// (BatchBlockStore); caso contrário salva um a um
+func (s *blockSaver) saveBatch(blocks []*blockchain.Block) error {
+	s.mu.RLock()
+	store := s.store
+	s.mu.RUnlock()
+
+	batchStore, ok := store.(BatchBlockStore)
+	if !ok {
+		for _, block := range blocks {
+			if err := s.save(block); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	what := fmt.Sprintf("blocks %d-%d", blocks[0].Header.Height, blocks[len(blocks)-1].Header.Height)
+	return s.retry(what, func() error {
+		return batchStore.SaveBlocks(blocks)
+	})
+}
+
+// retry executa fn até retries+1 vezes, aguardando entre as tentativas
+func (s *blockSaver) retry(what string, fn func() error) error {
+	var err error
+	wait := s.backoff
+	for attempt := 0; attempt <= s.retries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(wait)
+			wait *= 2
+		}
+
+		if err = fn(); err == nil {
+			return nil
+		}
+	}
+
+	err = fmt.Errorf("failed to save %s after %d attempts: %w", what, s.retries+1, err)
+
+	s.mu.Lock()
+	s.lastErr = err
+	s.mu.Unlock()
+
+	return err
+}
+
+// SetBlockStore substitui o armazenamento de blocos do nó (útil para testes)
+func (n *Node) SetBlockStore(store BlockStore) {
+	n.blockSaver.mu.Lock()
+	defer n.blockSaver.mu.Unlock()
+	n.blockSaver.store = store
+}
+
+// GetLastBlockSaveError retorna o último erro definitivo ao salvar um bloco (nil se nenhum)
+func (n *Node) GetLastBlockSaveError() error {
+	n.blockSaver.mu.RLock()
+	defer n.blockSaver.mu.RUnlock()
+	return n.blockSaver.lastErr
+}
+
+// saveBlock salva um bloco no disco com retry. Se todas as tentativas falharem,
+// a mineração é interrompida para evitar que a chain em memória divirja do disco.
+func (n *Node) saveBlock(block *blockchain.Block) error {
+	return n.handleSaveError(n.blockSaver.save(block))
+}
+
+// saveBlocks salva um lote de blocos no disco com retry (uma única escrita se o store suportar)
+func (n *Node) saveBlocks(blocks []*blockchain.Block) error {
+	return n.handleSaveError(n.blockSaver.saveBatch(blocks))
+}
+
+// flushChainTip grava a ponta atual da chain como ponta salva no disco. Se o bloco da ponta não
+// estiver no disco (falha ao salvar), a ponta salva é mantida para o próximo início não buscar
+// blocos que não existem.
+func (n *Node) flushChainTip() error {
+	tip := n.chain.GetLastBlock()
+	if tip == nil || tip.IsCheckpointAnchor() {
+		return nil
+	}
+
+	saved, err := blockchain.LoadBlockFromDB(n.db, tip.Header.Height)
+	if err != nil || saved.Hash != tip.Hash {
+		return fmt.Errorf("tip block %d is not on disk, keeping saved chain height", tip.Header.Height)
+	}
+
+	return blockchain.SaveChainTip(n.db, tip)
+}
+
+// handleSaveError interrompe a mineração se um bloco já aplicado em memória não foi salvo
+func (n *Node) handleSaveError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	fmt.Printf("[%s] ❌ %v\n", n.ID, err)
+
+	if n.IsMining() {
+		fmt.Printf("[%s] 🛑 Halting mining: in-memory chain is ahead of disk\n", n.ID)
+		n.StopMining()
+	}
+
+	return err
+}
+
+// SetBlockLoader substitui a leitura de blocos do disco (útil para testes)
+func (n *Node) SetBlockLoader(loader BlockLoader) {
+	n.blockLoaderMutex.Lock()
+	defer n.blockLoaderMutex.Unlock()
+	n.blockLoader = loader
+}
+
+// loadBlockBefore carrega um bloco do disco, desistindo se o deadline for atingido antes.
+// Uma leitura lenta que estoura o deadline continua em background e é descartada.
+func (n *Node) loadBlockBefore(height uint64, deadline time.Time) (*blockchain.Block, error) {
+	n.blockLoaderMutex.RLock()
+	loader := n.blockLoader
+	n.blockLoaderMutex.RUnlock()
+
+	remaining := time.Until(deadline)
+	if remaining <= 0 {
+		return nil, fmt.Errorf("deadline exceeded before loading block %d", height)
+	}
+
+	type result struct {
+		block *blockchain.Block
+		err   error
+	}
+	done := make(chan result, 1)
+	go func() {
+		block, err := loader.LoadBlock(height)
+		done <- result{block, err}
+	}()
+
+	timer := time.NewTimer(remaining)
+	defer timer.Stop()
+
+	select {
+	case r := <-done:
+		return r.block, r.err
+	case <-timer.C:
+		return nil, fmt.Errorf("deadline exceeded while loading block %d", height)
+	}
+}
+
+// compactOnStartup compacta todo o LevelDB para descartar tombstones acumulados, a menos que
+// a última compactação tenha ocorrido há menos de interval. Retorna se a compactação rodou.
+func (n *Node) compactOnStartup(compactor DBCompactor, interval time.Duration) (bool, error) {
+	if interval <= 0 {
+		interval = DefaultCompactInterval
+	}
+
+	if data, err := n.db.Get([]byte(lastCompactionKey), nil); err == nil {
+		var last int64
+		if _, err := fmt.Sscanf(string(data), "%d", &last); err == nil {
+			if elapsed := time.Since(time.Unix(last, 0)); elapsed < interval {
+				fmt.Printf("[%s] Skipping DB compaction: last run %v ago (interval %v)\n",
+					n.ID, elapsed.Round(time.Second), interval)
+				return false, nil
+			}
+		}
+	}
+
+	start := time.Now()
+	if err := compactor.CompactRange(util.Range{}); err != nil {
+		return false, fmt.Errorf("failed to compact database: %w", err)
+	}
+
+	if err := n.db.Put([]byte(lastCompactionKey), []byte(fmt.Sprintf("%d", time.Now().Unix())), nil); err != nil {
+		return true, fmt.Errorf("failed to record compaction time: %w", err)
+	}
+
+	fmt.Printf("[%s] 🧹 Database compacted in %v\n", n.ID, time.Since(start).Round(time.Millisecond))
+	return true, nil
+}
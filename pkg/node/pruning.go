@@ -0,0 +1,90 @@
+package node
+
+import (
+	"fmt"
+
+	"github.com/krakovia/blockchain/pkg/blockchain"
+)
+
+// PruneToDepth mantém em memória só os últimos keep blocos. Os blocos removidos são salvos no
+// LevelDB antes, então continuam servindo consultas e o sync (GetBlockByHeight, GetBlockRange).
+func (n *Node) PruneToDepth(keep int) error {
+	if keep < 1 {
+		return fmt.Errorf("must keep at least 1 block in memory, got %d", keep)
+	}
+
+	n.pruneMutex.Lock()
+	defer n.pruneMutex.Unlock()
+
+	blocksInMemory := n.GetBlocksInMemory()
+	if blocksInMemory <= keep {
+		return nil // Não precisa fazer pruning ainda
+	}
+
+	fmt.Printf("[%s] Pruning old blocks: in_memory=%d, keep=%d\n", n.ID, blocksInMemory, keep)
+
+	if err := n.chain.PruneToDepth(n.db, keep); err != nil {
+		return fmt.Errorf("failed to prune old blocks: %w", err)
+	}
+
+	fmt.Printf("[%s] Blocks pruned successfully: now %d blocks in memory\n", n.ID, n.GetBlocksInMemory())
+	return nil
+}
+
+// pruneMemory chama PruneToDepth registrando a falha (pruning não interrompe o nó)
+func (n *Node) pruneMemory(keep int) {
+	if err := n.PruneToDepth(keep); err != nil {
+		fmt.Printf("[%s] %v\n", n.ID, err)
+	}
+}
+
+// pruneAfterBlock aplica o PruneConfig depois de um bloco entrar na chain (sem PruneConfig não
+// faz nada; o pruning fica com os checkpoints, ver tryPruneBlocks)
+func (n *Node) pruneAfterBlock(height uint64) {
+	if n.pruneConfig == nil {
+		return
+	}
+
+	if n.pruneConfig.KeepInMemory > 0 {
+		n.pruneMemory(n.pruneConfig.KeepInMemory)
+	}
+	if n.pruneConfig.KeepOnDisk > 0 {
+		n.pruneDisk(height, n.pruneConfig.KeepOnDisk)
+	}
+}
+
+// pruneDisk remove do LevelDB os blocos abaixo dos últimos keep (até a altura height). O gênesis
+// e os blocos ainda em memória são mantidos; peers que precisem de blocos mais antigos
+// sincronizam a partir de outro nó ou de um checkpoint.
+func (n *Node) pruneDisk(height uint64, keep int) {
+	if n.db == nil || height < uint64(keep) {
+		return
+	}
+	cutoff := height - uint64(keep)
+
+	n.pruneMutex.Lock()
+	defer n.pruneMutex.Unlock()
+
+	deleted := 0
+	for h := n.diskPrunedHeight + 1; h <= cutoff; h++ {
+		if block, inMemory := n.chain.GetBlockByHeight(h); inMemory && !block.IsCheckpointAnchor() {
+			break
+		}
+
+		block, err := blockchain.LoadBlockFromDB(n.db, h)
+		if err != nil {
+			n.diskPrunedHeight = h // Já fora do disco
+			continue
+		}
+		if err := blockchain.DeleteBlockFromDB(n.db, block); err != nil {
+			fmt.Printf("[%s] Failed to prune block %d from disk: %v\n", n.ID, h, err)
+			return
+		}
+		n.diskPrunedHeight = h
+		deleted++
+	}
+
+	if deleted > 0 {
+		fmt.Printf("[%s] Pruned %d blocks from disk (kept heights above %d)\n", n.ID, deleted, n.diskPrunedHeight)
+	}
+}
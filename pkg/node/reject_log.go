@@ -0,0 +1,206 @@
+package node
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/krakovia/blockchain/pkg/blockchain"
+)
+
+// Valores padrão do log de rejeições
+const (
+	DefaultRejectLogMaxSize  = 10 * 1024 * 1024 // Tamanho máximo do arquivo antes de rotacionar (bytes)
+	DefaultRejectLogMaxFiles = 3                // Arquivos rotacionados mantidos (path.1 ... path.N)
+)
+
+// Tipos de objeto registrados no log de rejeições
+const (
+	RejectKindBlock       = "block"
+	RejectKindTransaction = "transaction"
+)
+
+// RejectLogEntry registro de um bloco ou transação rejeitado (uma linha JSON do arquivo)
+type RejectLogEntry struct {
+	Time   time.Time       `json:"time"`
+	Kind   string          `json:"kind"`             // RejectKindBlock ou RejectKindTransaction
+	ID     string          `json:"id"`               // Hash do bloco ou ID da transação
+	Height uint64          `json:"height,omitempty"` // Altura do bloco
+	Peer   string          `json:"peer,omitempty"`   // Peer que enviou o objeto
+	Source string          `json:"source"`           // Caminho em que foi rejeitado (gossip, orphan, sync, fork, fetch)
+	Reason string          `json:"reason"`           // Erro de validação
+	Data   json.RawMessage `json:"data,omitempty"`   // Objeto rejeitado, para análise posterior
+}
+
+// RejectLog grava blocos e transações rejeitados em um arquivo JSONL, rotacionando o arquivo
+// ao atingir maxSize (path -> path.1 -> ... -> path.maxFiles, o mais antigo é descartado)
+type RejectLog struct {
+	mu       sync.Mutex
+	path     string
+	maxSize  int64
+	maxFiles int
+	file     *os.File
+	size     int64
+}
+
+// OpenRejectLog abre (ou cria) o log de rejeições em path, acrescentando ao conteúdo existente.
+// maxSize <= 0 e maxFiles <= 0 usam os valores padrão.
+func OpenRejectLog(path string, maxSize int64, maxFiles int) (*RejectLog, error) {
+	if maxSize <= 0 {
+		maxSize = DefaultRejectLogMaxSize
+	}
+	if maxFiles <= 0 {
+		maxFiles = DefaultRejectLogMaxFiles
+	}
+
+	l := &RejectLog{path: path, maxSize: maxSize, maxFiles: maxFiles}
+	if err := l.open(); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// open abre o arquivo atual em modo append (deve ser chamado com lock)
+func (l *RejectLog) open() error {
+	file, err := os.OpenFile(l.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open reject log: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to stat reject log: %w", err)
+	}
+
+	l.file = file
+	l.size = info.Size()
+	return nil
+}
+
+// Write acrescenta uma entrada ao log, rotacionando antes se ela ultrapassar o tamanho máximo
+func (l *RejectLog) Write(entry RejectLogEntry) error {
+	if entry.Time.IsZero() {
+		entry.Time = time.Now()
+	}
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal reject log entry: %w", err)
+	}
+	line = append(line, '\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.file == nil {
+		return fmt.Errorf("reject log is closed")
+	}
+	if l.size > 0 && l.size+int64(len(line)) > l.maxSize {
+		if err := l.rotate(); err != nil {
+			return err
+		}
+	}
+
+	n, err := l.file.Write(line)
+	l.size += int64(n)
+	if err != nil {
+		return fmt.Errorf("failed to write reject log entry: %w", err)
+	}
+	return nil
+}
+
+// rotate fecha o arquivo atual, desloca os rotacionados e abre um arquivo vazio (deve ser
+// chamado com lock)
+func (l *RejectLog) rotate() error {
+	if err := l.file.Close(); err != nil {
+		return fmt.Errorf("failed to close reject log: %w", err)
+	}
+	l.file = nil
+
+	// O mais antigo é sobrescrito pelo penúltimo
+	for i := l.maxFiles - 1; i >= 1; i-- {
+		from := fmt.Sprintf("%s.%d", l.path, i)
+		if _, err := os.Stat(from); err != nil {
+			continue
+		}
+		if err := os.Rename(from, fmt.Sprintf("%s.%d", l.path, i+1)); err != nil {
+			return fmt.Errorf("failed to rotate reject log: %w", err)
+		}
+	}
+	if err := os.Rename(l.path, l.path+".1"); err != nil {
+		return fmt.Errorf("failed to rotate reject log: %w", err)
+	}
+
+	return l.open()
+}
+
+// Close fecha o arquivo do log
+func (l *RejectLog) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.file == nil {
+		return nil
+	}
+	err := l.file.Close()
+	l.file = nil
+	return err
+}
+
+// logRejectedTransaction registra uma transação recebida que falhou na validação (no-op se o
+// log de rejeições estiver desativado)
+func (n *Node) logRejectedTransaction(peerID, source string, tx *blockchain.Transaction, reason error) {
+	if n.rejectLog == nil {
+		return
+	}
+
+	data, _ := tx.Serialize()
+	n.writeRejectLog(RejectLogEntry{
+		Kind:   RejectKindTransaction,
+		ID:     tx.ID,
+		Peer:   peerID,
+		Source: source,
+		Reason: reason.Error(),
+		Data:   data,
+	})
+}
+
+// logRejectedBlock registra um bloco recebido que falhou na validação (no-op se o log de
+// rejeições estiver desativado)
+func (n *Node) logRejectedBlock(peerID, source string, block *blockchain.Block, reason error) {
+	if n.rejectLog == nil {
+		return
+	}
+
+	data, _ := block.Serialize()
+	n.writeRejectLog(RejectLogEntry{
+		Kind:   RejectKindBlock,
+		ID:     block.Hash,
+		Height: block.Header.Height,
+		Peer:   peerID,
+		Source: source,
+		Reason: reason.Error(),
+		Data:   data,
+	})
+}
+
+// writeRejectLog grava a entrada; falhas de escrita só são reportadas no log do nó
+func (n *Node) writeRejectLog(entry RejectLogEntry) {
+	if err := n.rejectLog.Write(entry); err != nil {
+		fmt.Printf("[%s] ⚠️  Warning: failed to write reject log: %v\n", n.ID, err)
+	}
+}
+
+// logRejectedBatch registra o bloco que invalidou um lote da sincronização. Se o erro não
+// identificar o bloco, registra o primeiro do lote.
+func (n *Node) logRejectedBatch(peerID string, batch []*blockchain.Block, reason error) {
+	block := batch[0]
+	var batchErr *blockchain.BatchError
+	if errors.As(reason, &batchErr) && batchErr.Index < len(batch) {
+		block = batch[batchErr.Index]
+		reason = batchErr.Err
+	}
+	n.logRejectedBlock(peerID, "sync", block, reason)
+}
@@ -0,0 +1,134 @@
+package node
+
+import (
+	"fmt"
+
+	"github.com/krakovia/blockchain/pkg/blockchain"
+	"github.com/krakovia/blockchain/pkg/network"
+)
+
+// reportDoubleSign recebe da chain uma assinatura dupla detectada localmente
+func (n *Node) reportDoubleSign(evidence *blockchain.SlashingEvidence) {
+	n.addSlashEvidence(evidence, "")
+}
+
+// handleSlashEvidence processa uma evidência de assinatura dupla recebida de um peer
+func (n *Node) handleSlashEvidence(peerID string, data []byte) {
+	evidence, err := blockchain.DeserializeSlashingEvidence(data)
+	if err != nil {
+		fmt.Printf("[%s] Failed to deserialize slash evidence from %s: %v\n", n.ID, peerID, err)
+		return
+	}
+
+	n.addSlashEvidence(evidence, peerID)
+}
+
+// addSlashEvidence verifica a evidência e, se for nova, coloca no mempool a transação de denúncia
+// antes de repassá-la aos peers (exceto fromPeerID). A punição em si acontece quando a transação
+// entra em um bloco, para que todos os nós cheguem ao mesmo stake; o repasse garante que algum nó
+// com saldo para a fee faça a denúncia mesmo que quem detectou não possa pagá-la.
+func (n *Node) addSlashEvidence(evidence *blockchain.SlashingEvidence, fromPeerID string) {
+	if err := n.chain.CheckSlashingEvidence(evidence); err != nil {
+		if fromPeerID != "" {
+			fmt.Printf("[%s] Rejected slash evidence from %s: %v\n", n.ID, fromPeerID, err)
+		}
+		return
+	}
+
+	key := slashEvidenceKey(evidence)
+	n.slashEvidenceMutex.Lock()
+	if _, exists := n.slashEvidence[key]; exists {
+		n.slashEvidenceMutex.Unlock()
+		return // Já vista, não repassa de novo
+	}
+	n.slashEvidence[key] = evidence
+	n.slashEvidenceMutex.Unlock()
+
+	n.submitSlashEvidence(evidence)
+
+	data, err := evidence.Serialize()
+	if err != nil {
+		fmt.Printf("[%s] Failed to serialize slash evidence: %v\n", n.ID, err)
+		return
+	}
+
+	payload := newOutgoingPayload("slash_evidence", data)
+
+	n.peersMutex.RLock()
+	defer n.peersMutex.RUnlock()
+
+	for _, peer := range n.peers {
+		if peer.ID != fromPeerID {
+			if err := n.sendPayload(peer, payload); err != nil {
+				fmt.Printf("[%s] Failed to send slash evidence to peer %s: %v\n", n.ID, peer.ID, err)
+			}
+		}
+	}
+}
+
+// submitSlashEvidence coloca no mempool uma transação de denúncia da evidência, a menos que já
+// exista uma pendente ou que o nó não tenha saldo para a fee
+func (n *Node) submitSlashEvidence(evidence *blockchain.SlashingEvidence) {
+	for _, tx := range n.mempool.GetTransactions() {
+		txData, err := blockchain.DeserializeTransactionData(tx.Data)
+		if err != nil || !txData.IsDoubleSignEvidence() {
+			continue
+		}
+		if pending, err := txData.GetDoubleSignEvidence(); err == nil &&
+			pending.Validator == evidence.Validator && pending.Height == evidence.Height {
+			return
+		}
+	}
+
+	fee := n.mempool.MinFee()
+	if balance := n.chain.GetBalance(n.wallet.GetAddress()); balance < fee {
+		fmt.Printf("[%s] Relaying double sign by %s at height %d without reporting it (balance %d is below fee %d)\n",
+			n.ID, evidence.Validator, evidence.Height, balance, fee)
+		return
+	}
+
+	tx, err := n.CreateDoubleSignEvidenceTransaction(evidence, fee)
+	if err != nil {
+		fmt.Printf("[%s] Failed to report double sign by %s at height %d: %v\n", n.ID, evidence.Validator, evidence.Height, err)
+		return
+	}
+
+	fmt.Printf("[%s] Reported double sign by %s at height %d in transaction %s\n", n.ID, evidence.Validator, evidence.Height, tx.ID[:16])
+}
+
+// sendSlashEvidence envia ao peer as evidências que ainda punem o validador e esquece as demais
+func (n *Node) sendSlashEvidence(peer *network.Peer) {
+	for _, evidence := range n.GetSlashEvidence() {
+		data, err := evidence.Serialize()
+		if err != nil {
+			fmt.Printf("[%s] Failed to serialize slash evidence: %v\n", n.ID, err)
+			continue
+		}
+		if err := peer.SendMessage("slash_evidence", data); err != nil {
+			fmt.Printf("[%s] Failed to send slash evidence to %s: %v\n", n.ID, peer.ID, err)
+			return
+		}
+	}
+}
+
+// GetSlashEvidence retorna as evidências de assinatura dupla que ainda punem o validador no
+// próximo bloco, descartando as já punidas ou expiradas
+func (n *Node) GetSlashEvidence() []*blockchain.SlashingEvidence {
+	n.slashEvidenceMutex.Lock()
+	defer n.slashEvidenceMutex.Unlock()
+
+	evidences := make([]*blockchain.SlashingEvidence, 0, len(n.slashEvidence))
+	for key, evidence := range n.slashEvidence {
+		if err := n.chain.CheckSlashingEvidence(evidence); err != nil {
+			delete(n.slashEvidence, key)
+			continue
+		}
+		evidences = append(evidences, evidence)
+	}
+	return evidences
+}
+
+// slashEvidenceKey identifica a evidência pelo validador e altura (uma punição por altura)
+func slashEvidenceKey(evidence *blockchain.SlashingEvidence) string {
+	return fmt.Sprintf("%s-%d", evidence.Validator, evidence.Height)
+}
@@ -7,6 +7,7 @@ import (
 	"log"
 	"net/http"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gorilla/websocket"
@@ -19,17 +20,47 @@ var upgrader = websocket.Upgrader{
 	},
 }
 
+// DefaultRoom sala dos clientes que conectam sem o parâmetro room
+const DefaultRoom = ""
+
+const (
+	// DefaultPingInterval intervalo padrão entre pings de heartbeat
+	DefaultPingInterval = 10 * time.Second
+	// DefaultMaxMissedPongs pings seguidos sem pong até o cliente ser removido
+	DefaultMaxMissedPongs = 3
+	// DefaultSendQueueSize mensagens pendentes por cliente antes de ele ser removido por não
+	// acompanhar (leitor lento)
+	DefaultSendQueueSize = 256
+	// writeWait tempo máximo para escrever um ping
+	writeWait = 5 * time.Second
+)
+
 // Client representa um cliente conectado ao servidor de signaling
 type Client struct {
-	ID       string
-	Conn     *websocket.Conn
-	Send     chan []byte
-	connMux  sync.Mutex
+	ID      string
+	Room    string // Sala do cliente (parâmetro room da URL); só vê e fala com peers da mesma sala
+	Conn    *websocket.Conn
+	Send    chan []byte
+	connMux sync.Mutex
+
+	missedPongs int32 // Pings sem pong desde o último pong (acesso atômico)
 }
 
-// Server é o servidor de signaling WebSocket
+// Server é o servidor de signaling WebSocket. Os clientes ficam separados em salas, para que
+// redes diferentes (ex: testnet e mainnet) compartilhem o mesmo servidor sem se enxergar.
+// Clientes que deixam de responder ao heartbeat (ping a cada PingInterval) por MaxMissedPongs
+// pings seguidos são desconectados, e os peers da sala recebem um "peer-left".
+// Com MaxClients > 0, conexões além do limite são recusadas com 503; cada cliente tem uma fila
+// de SendQueueSize mensagens e é desconectado se ela enche (não acompanha o que recebe).
 type Server struct {
-	clients      map[string]*Client
+	PingInterval   time.Duration
+	MaxMissedPongs int
+	MaxClients     int // Conexões WebSocket simultâneas (0 = sem limite)
+	SendQueueSize  int
+
+	connections int // Conexões WebSocket abertas, registradas ou não (protegido por clientsMutex)
+
+	rooms        map[string]map[string]*Client // sala -> ID do cliente -> cliente
 	clientsMutex sync.RWMutex
 	register     chan *Client
 	unregister   chan *Client
@@ -54,7 +85,11 @@ type Message struct {
 func NewServer() *Server {
 	ctx, cancel := context.WithCancel(context.Background())
 	return &Server{
-		clients:    make(map[string]*Client),
+		PingInterval:   DefaultPingInterval,
+		MaxMissedPongs: DefaultMaxMissedPongs,
+		SendQueueSize:  DefaultSendQueueSize,
+
+		rooms:      make(map[string]map[string]*Client),
 		register:   make(chan *Client),
 		unregister: make(chan *Client),
 		broadcast:  make(chan []byte),
@@ -71,45 +106,72 @@ func (s *Server) Run() {
 		case <-s.ctx.Done():
 			// Fechar todos os clientes conectados
 			s.clientsMutex.Lock()
-			for _, client := range s.clients {
-				close(client.Send)
+			for _, clients := range s.rooms {
+				for _, client := range clients {
+					close(client.Send)
+				}
 			}
-			s.clients = make(map[string]*Client)
+			s.rooms = make(map[string]map[string]*Client)
 			s.clientsMutex.Unlock()
 			return
 
 		case client := <-s.register:
 			s.clientsMutex.Lock()
-			s.clients[client.ID] = client
+			clients, ok := s.rooms[client.Room]
+			if !ok {
+				clients = make(map[string]*Client)
+				s.rooms[client.Room] = clients
+			}
+			clients[client.ID] = client
 			s.clientsMutex.Unlock()
 
-			fmt.Printf("Client %s registered\n", client.ID)
+			fmt.Printf("Client %s registered in room %q\n", client.ID, client.Room)
 
 			// Enviar lista de peers existentes para o novo cliente
 			s.sendPeerList(client)
 
-			// Notificar outros clientes sobre o novo peer
-			s.notifyNewPeer(client.ID)
+			// Notificar outros clientes da sala sobre o novo peer
+			s.notifyNewPeer(client)
 
 		case client := <-s.unregister:
 			s.clientsMutex.Lock()
-			if _, ok := s.clients[client.ID]; ok {
-				delete(s.clients, client.ID)
-				close(client.Send)
-				fmt.Printf("Client %s unregistered\n", client.ID)
+			// Só remove se o ID não foi registrado de novo por outra conexão (reconexão após queda)
+			registered := client.ID != "" && s.rooms[client.Room][client.ID] == client
+			if registered {
+				s.removeClient(client.Room, client.ID)
+				fmt.Printf("Client %s unregistered from room %q\n", client.ID, client.Room)
 			}
 			s.clientsMutex.Unlock()
+
+			if registered {
+				s.notifyPeerLeft(client)
+			}
 		}
 	}
 }
 
-// sendPeerList envia a lista de peers conectados para um cliente
+// removeClient fecha o canal do cliente e o remove da sala (apagando a sala vazia).
+// Deve ser chamado com clientsMutex travado.
+func (s *Server) removeClient(room, id string) {
+	clients := s.rooms[room]
+	client, ok := clients[id]
+	if !ok {
+		return
+	}
+	close(client.Send)
+	delete(clients, id)
+	if len(clients) == 0 {
+		delete(s.rooms, room)
+	}
+}
+
+// sendPeerList envia a lista de peers conectados na sala do cliente
 func (s *Server) sendPeerList(client *Client) {
 	s.clientsMutex.RLock()
 	defer s.clientsMutex.RUnlock()
 
 	peerList := make([]string, 0)
-	for id := range s.clients {
+	for id := range s.rooms[client.Room] {
 		if id != client.ID {
 			peerList = append(peerList, id)
 		}
@@ -133,16 +195,16 @@ func (s *Server) sendPeerList(client *Client) {
 		fmt.Printf("Peer list sent to %s\n", client.ID)
 	default:
 		fmt.Printf("Failed to send peer list to %s (channel blocked)\n", client.ID)
-		close(client.Send)
-		delete(s.clients, client.ID)
+		s.removeClient(client.Room, client.ID)
 	}
 }
 
-// notifyNewPeer notifica todos os clientes sobre um novo peer
-func (s *Server) notifyNewPeer(newPeerID string) {
+// notifyNewPeer notifica os clientes da sala sobre um novo peer
+func (s *Server) notifyNewPeer(newPeer *Client) {
 	s.clientsMutex.RLock()
 	defer s.clientsMutex.RUnlock()
 
+	newPeerID := newPeer.ID
 	msg := Message{
 		Type:     "peer-list",
 		PeerList: []string{newPeerID},
@@ -154,31 +216,131 @@ func (s *Server) notifyNewPeer(newPeerID string) {
 		return
 	}
 
-	for id, client := range s.clients {
+	for id, client := range s.rooms[newPeer.Room] {
 		if id != newPeerID {
 			select {
 			case client.Send <- data:
 			default:
-				close(client.Send)
-				delete(s.clients, id)
+				s.removeClient(newPeer.Room, id)
 			}
 		}
 	}
 }
 
-// HandleWebSocket gerencia conexões WebSocket
+// notifyPeerLeft avisa os clientes da sala que um peer saiu (desconectou ou foi removido
+// pelo heartbeat), para que deixem de tentar conectar nele
+func (s *Server) notifyPeerLeft(peer *Client) {
+	s.clientsMutex.RLock()
+	defer s.clientsMutex.RUnlock()
+
+	msg := Message{
+		Type:     "peer-left",
+		PeerList: []string{peer.ID},
+	}
+
+	data, err := json.Marshal(msg)
+	if err != nil {
+		log.Printf("Error marshaling peer left notification: %v", err)
+		return
+	}
+
+	for id, client := range s.rooms[peer.Room] {
+		select {
+		case client.Send <- data:
+		default:
+			s.removeClient(peer.Room, id)
+		}
+	}
+}
+
+// ClientCount retorna o número de clientes registrados (em todas as salas), para monitoramento
+func (s *Server) ClientCount() int {
+	s.clientsMutex.RLock()
+	defer s.clientsMutex.RUnlock()
+
+	count := 0
+	for _, clients := range s.rooms {
+		count += len(clients)
+	}
+	return count
+}
+
+// ConnectionCount retorna o número de conexões WebSocket abertas (as que contam para MaxClients,
+// inclusive as que ainda não se registraram)
+func (s *Server) ConnectionCount() int {
+	s.clientsMutex.RLock()
+	defer s.clientsMutex.RUnlock()
+	return s.connections
+}
+
+// acquireConnection reserva uma vaga para uma nova conexão; false se MaxClients foi atingido
+func (s *Server) acquireConnection() bool {
+	s.clientsMutex.Lock()
+	defer s.clientsMutex.Unlock()
+
+	if s.MaxClients > 0 && s.connections >= s.MaxClients {
+		return false
+	}
+	s.connections++
+	return true
+}
+
+// releaseConnection libera a vaga de uma conexão encerrada
+func (s *Server) releaseConnection() {
+	s.clientsMutex.Lock()
+	s.connections--
+	s.clientsMutex.Unlock()
+}
+
+// handleStats retorna o número de clientes registrados, de conexões abertas e o limite (GET /stats)
+func (s *Server) handleStats(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]int{
+		"clients":     s.ClientCount(),
+		"connections": s.ConnectionCount(),
+		"max_clients": s.MaxClients,
+	})
+}
+
+// HandleWebSocket gerencia conexões WebSocket. O parâmetro room da URL (ex: /ws?room=testnet)
+// escolhe a sala do cliente; sem ele, o cliente entra em DefaultRoom. Com o servidor cheio
+// (MaxClients), responde 503 com o motivo em JSON sem fazer o upgrade.
 func (s *Server) HandleWebSocket(w http.ResponseWriter, r *http.Request) {
+	room := r.URL.Query().Get("room")
+
+	if !s.acquireConnection() {
+		fmt.Printf("Rejecting connection from %s: server full (%d clients)\n", r.RemoteAddr, s.MaxClients)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"error": fmt.Sprintf("signaling server is full (%d clients)", s.MaxClients),
+		})
+		return
+	}
+
 	conn, err := upgrader.Upgrade(w, r, nil)
 	if err != nil {
+		s.releaseConnection()
 		log.Printf("Error upgrading connection: %v", err)
 		return
 	}
 
+	queueSize := s.SendQueueSize
+	if queueSize <= 0 {
+		queueSize = DefaultSendQueueSize
+	}
 	client := &Client{
+		Room: room,
 		Conn: conn,
-		Send: make(chan []byte, 256),
+		Send: make(chan []byte, queueSize),
 	}
 
+	// Qualquer pong zera a contagem do heartbeat
+	conn.SetPongHandler(func(string) error {
+		atomic.StoreInt32(&client.missedPongs, 0)
+		return nil
+	})
+
 	// Ler goroutine - recebe mensagens do cliente
 	go s.readPump(client)
 
@@ -189,6 +351,7 @@ func (s *Server) HandleWebSocket(w http.ResponseWriter, r *http.Request) {
 // readPump lê mensagens do cliente
 func (s *Server) readPump(client *Client) {
 	defer func() {
+		s.releaseConnection()
 		s.unregister <- client
 		if err := client.Conn.Close(); err != nil {
 			fmt.Printf("Error closing client connection: %v\n", err)
@@ -216,13 +379,14 @@ func (s *Server) readPump(client *Client) {
 			s.sendPeerList(client)
 
 		case "offer", "answer", "ice":
-			// Encaminhar mensagem para o destinatário
-			s.forwardMessage(msg)
+			// Encaminhar mensagem para o destinatário (na mesma sala)
+			s.forwardMessage(client, msg)
 		}
 	}
 }
 
-// writePump envia mensagens para o cliente
+// writePump envia mensagens e os pings de heartbeat para o cliente. Fechar a conexão (fim das
+// mensagens, erro ou pongs perdidos) encerra o readPump, que remove o cliente.
 func (s *Server) writePump(client *Client) {
 	defer func() {
 		if err := client.Conn.Close(); err != nil {
@@ -230,24 +394,62 @@ func (s *Server) writePump(client *Client) {
 		}
 	}()
 
-	for message := range client.Send {
-		client.connMux.Lock()
-		err := client.Conn.WriteMessage(websocket.TextMessage, message)
-		client.connMux.Unlock()
+	interval := s.PingInterval
+	if interval <= 0 {
+		interval = DefaultPingInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
 
-		if err != nil {
-			log.Printf("Error writing message: %v", err)
-			return
+	for {
+		select {
+		case message, ok := <-client.Send:
+			if !ok {
+				return
+			}
+
+			client.connMux.Lock()
+			err := client.Conn.WriteMessage(websocket.TextMessage, message)
+			client.connMux.Unlock()
+
+			if err != nil {
+				log.Printf("Error writing message: %v", err)
+				return
+			}
+
+		case <-ticker.C:
+			if missed := atomic.LoadInt32(&client.missedPongs); int(missed) >= s.maxMissedPongs() {
+				fmt.Printf("Client %s missed %d pongs, evicting\n", client.ID, missed)
+				return
+			}
+			atomic.AddInt32(&client.missedPongs, 1)
+
+			client.connMux.Lock()
+			err := client.Conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(writeWait))
+			client.connMux.Unlock()
+
+			if err != nil {
+				log.Printf("Error writing ping: %v", err)
+				return
+			}
 		}
 	}
 }
 
-// forwardMessage encaminha uma mensagem de um cliente para outro
-func (s *Server) forwardMessage(msg Message) {
+// maxMissedPongs retorna MaxMissedPongs (ou o padrão, se não configurado)
+func (s *Server) maxMissedPongs() int {
+	if s.MaxMissedPongs <= 0 {
+		return DefaultMaxMissedPongs
+	}
+	return s.MaxMissedPongs
+}
+
+// forwardMessage encaminha uma mensagem de um cliente para outro da mesma sala
+func (s *Server) forwardMessage(from *Client, msg Message) {
 	s.clientsMutex.RLock()
 	defer s.clientsMutex.RUnlock()
 
-	if targetClient, ok := s.clients[msg.To]; ok {
+	if targetClient, ok := s.rooms[from.Room][msg.To]; ok {
 		data, err := json.Marshal(msg)
 		if err != nil {
 			log.Printf("Error marshaling message: %v", err)
@@ -257,8 +459,7 @@ func (s *Server) forwardMessage(msg Message) {
 		select {
 		case targetClient.Send <- data:
 		default:
-			close(targetClient.Send)
-			delete(s.clients, msg.To)
+			s.removeClient(from.Room, msg.To)
 		}
 	}
 }
@@ -271,6 +472,7 @@ func (s *Server) Start(addr string) error {
 	// Usar um ServeMux próprio ao invés do global para evitar conflitos em testes
 	mux := http.NewServeMux()
 	mux.HandleFunc("/ws", s.HandleWebSocket)
+	mux.HandleFunc("/stats", s.handleStats)
 
 	s.httpServer = &http.Server{
 		Addr:    addr,
@@ -0,0 +1,224 @@
+package wallet
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	keystoreVersion = 1
+	keystoreKDF     = "scrypt"
+	keystoreCipher  = "aes-256-gcm"
+
+	// Parâmetros padrão do scrypt (N=2^15, r=8, p=1 ~ 32 MB de memória)
+	keystoreScryptN      = 1 << 15
+	keystoreScryptR      = 8
+	keystoreScryptP      = 1
+	keystoreScryptKeyLen = 32
+	keystoreSaltSize     = 32
+
+	// Limites dos parâmetros lidos de um arquivo: o scrypt usa 128*N*r bytes de memória e N*r*p
+	// de trabalho, então um arquivo adulterado poderia travar ou derrubar o processo
+	keystoreMaxScryptN      = 1 << 20
+	keystoreMaxScryptR      = 32
+	keystoreMaxScryptP      = 16
+	keystoreMaxScryptMemory = 256 << 20 // 128*N*r
+)
+
+// keystoreFile representa o arquivo JSON de uma carteira criptografada
+type keystoreFile struct {
+	Version   int            `json:"version"`
+	Address   string         `json:"address"`
+	PublicKey string         `json:"public_key"`
+	Crypto    keystoreCrypto `json:"crypto"`
+}
+
+// keystoreCrypto contém os parâmetros e dados da criptografia da chave privada
+type keystoreCrypto struct {
+	Cipher     string         `json:"cipher"`
+	KDF        string         `json:"kdf"`
+	KDFParams  keystoreScrypt `json:"kdfparams"`
+	Salt       string         `json:"salt"`       // Salt do scrypt (hex)
+	Nonce      string         `json:"nonce"`      // Nonce do AES-GCM (hex)
+	Ciphertext string         `json:"ciphertext"` // Chave privada criptografada + tag (hex)
+}
+
+// keystoreScrypt contém os parâmetros do scrypt
+type keystoreScrypt struct {
+	N      int `json:"n"`
+	R      int `json:"r"`
+	P      int `json:"p"`
+	KeyLen int `json:"dklen"`
+}
+
+// validate recusa parâmetros fora dos limites antes de derivar a chave
+func (p keystoreScrypt) validate() error {
+	if p.N <= 1 || p.N&(p.N-1) != 0 || p.N > keystoreMaxScryptN {
+		return fmt.Errorf("invalid keystore scrypt N: %d (must be a power of 2 up to %d)", p.N, keystoreMaxScryptN)
+	}
+	if p.R <= 0 || p.R > keystoreMaxScryptR {
+		return fmt.Errorf("invalid keystore scrypt r: %d (max %d)", p.R, keystoreMaxScryptR)
+	}
+	if p.P <= 0 || p.P > keystoreMaxScryptP {
+		return fmt.Errorf("invalid keystore scrypt p: %d (max %d)", p.P, keystoreMaxScryptP)
+	}
+	if 128*p.N*p.R > keystoreMaxScryptMemory {
+		return fmt.Errorf("keystore scrypt parameters need too much memory: N=%d r=%d (max %d MB)", p.N, p.R, keystoreMaxScryptMemory>>20)
+	}
+	if p.KeyLen != keystoreScryptKeyLen {
+		return fmt.Errorf("invalid keystore scrypt dklen: %d (expected %d)", p.KeyLen, keystoreScryptKeyLen)
+	}
+	return nil
+}
+
+// SaveKeystore salva a carteira em um arquivo JSON criptografado com a senha
+// (chave derivada com scrypt, chave privada cifrada com AES-256-GCM).
+// O endereço fica em claro e é autenticado junto com o ciphertext.
+func (w *Wallet) SaveKeystore(path, password string) error {
+	if password == "" {
+		return fmt.Errorf("keystore password cannot be empty")
+	}
+
+	salt := make([]byte, keystoreSaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return fmt.Errorf("failed to generate salt: %w", err)
+	}
+
+	params := keystoreScrypt{
+		N:      keystoreScryptN,
+		R:      keystoreScryptR,
+		P:      keystoreScryptP,
+		KeyLen: keystoreScryptKeyLen,
+	}
+
+	gcm, err := newKeystoreCipher(password, salt, params)
+	if err != nil {
+		return err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	// Chave privada com exatamente 32 bytes (padding com zeros à esquerda)
+	privateKey := make([]byte, 32)
+	dBytes := w.PrivateKey.D.Bytes()
+	copy(privateKey[32-len(dBytes):], dBytes)
+
+	address := w.GetAddress()
+	ciphertext := gcm.Seal(nil, nonce, privateKey, []byte(address))
+
+	keystore := keystoreFile{
+		Version:   keystoreVersion,
+		Address:   address,
+		PublicKey: w.GetPublicKeyHex(),
+		Crypto: keystoreCrypto{
+			Cipher:     keystoreCipher,
+			KDF:        keystoreKDF,
+			KDFParams:  params,
+			Salt:       hex.EncodeToString(salt),
+			Nonce:      hex.EncodeToString(nonce),
+			Ciphertext: hex.EncodeToString(ciphertext),
+		},
+	}
+
+	data, err := json.MarshalIndent(keystore, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal keystore: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write keystore file: %w", err)
+	}
+
+	return nil
+}
+
+// LoadKeystore carrega uma carteira de um arquivo JSON criptografado com SaveKeystore
+func LoadKeystore(path, password string) (*Wallet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read keystore file: %w", err)
+	}
+
+	var keystore keystoreFile
+	if err := json.Unmarshal(data, &keystore); err != nil {
+		return nil, fmt.Errorf("failed to parse keystore file: %w", err)
+	}
+
+	if keystore.Version != keystoreVersion {
+		return nil, fmt.Errorf("unsupported keystore version: %d", keystore.Version)
+	}
+	if keystore.Crypto.KDF != keystoreKDF || keystore.Crypto.Cipher != keystoreCipher {
+		return nil, fmt.Errorf("unsupported keystore kdf/cipher: %s/%s", keystore.Crypto.KDF, keystore.Crypto.Cipher)
+	}
+
+	if err := keystore.Crypto.KDFParams.validate(); err != nil {
+		return nil, err
+	}
+
+	salt, err := hex.DecodeString(keystore.Crypto.Salt)
+	if err != nil {
+		return nil, fmt.Errorf("invalid keystore salt: %w", err)
+	}
+	nonce, err := hex.DecodeString(keystore.Crypto.Nonce)
+	if err != nil {
+		return nil, fmt.Errorf("invalid keystore nonce: %w", err)
+	}
+	ciphertext, err := hex.DecodeString(keystore.Crypto.Ciphertext)
+	if err != nil {
+		return nil, fmt.Errorf("invalid keystore ciphertext: %w", err)
+	}
+
+	gcm, err := newKeystoreCipher(password, salt, keystore.Crypto.KDFParams)
+	if err != nil {
+		return nil, err
+	}
+	if len(nonce) != gcm.NonceSize() {
+		return nil, fmt.Errorf("invalid keystore nonce length: expected %d bytes, got %d", gcm.NonceSize(), len(nonce))
+	}
+
+	privateKey, err := gcm.Open(nil, nonce, ciphertext, []byte(keystore.Address))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt keystore: wrong password or corrupted file")
+	}
+
+	w, err := NewWalletFromPrivateKey(hex.EncodeToString(privateKey))
+	if err != nil {
+		return nil, err
+	}
+
+	if w.GetAddress() != keystore.Address {
+		return nil, fmt.Errorf("keystore address mismatch: file has %s, key derives %s", keystore.Address, w.GetAddress())
+	}
+
+	return w, nil
+}
+
+// newKeystoreCipher deriva a chave com scrypt e cria o AES-GCM
+func newKeystoreCipher(password string, salt []byte, params keystoreScrypt) (cipher.AEAD, error) {
+	key, err := scrypt.Key([]byte(password), salt, params.N, params.R, params.P, params.KeyLen)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive keystore key: %w", err)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GCM: %w", err)
+	}
+
+	return gcm, nil
+}
@@ -0,0 +1,139 @@
+package wallet
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestKeystoreRoundTrip(t *testing.T) {
+	original, err := NewWallet()
+	if err != nil {
+		t.Fatalf("Failed to create wallet: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "wallet.json")
+	if err := original.SaveKeystore(path, "correct horse"); err != nil {
+		t.Fatalf("Failed to save keystore: %v", err)
+	}
+
+	loaded, err := LoadKeystore(path, "correct horse")
+	if err != nil {
+		t.Fatalf("Failed to load keystore: %v", err)
+	}
+
+	if loaded.GetAddress() != original.GetAddress() {
+		t.Errorf("Address mismatch: %s != %s", loaded.GetAddress(), original.GetAddress())
+	}
+	if loaded.GetPrivateKeyHex() != original.GetPrivateKeyHex() {
+		t.Error("Private key mismatch after decrypt")
+	}
+}
+
+func TestKeystoreDoesNotStorePlaintextKey(t *testing.T) {
+	w, _ := NewWallet()
+	path := filepath.Join(t.TempDir(), "wallet.json")
+	if err := w.SaveKeystore(path, "secret"); err != nil {
+		t.Fatalf("Failed to save keystore: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read keystore: %v", err)
+	}
+	if strings.Contains(string(data), w.GetPrivateKeyHex()) {
+		t.Error("Keystore must not contain the plaintext private key")
+	}
+
+	var file map[string]interface{}
+	if err := json.Unmarshal(data, &file); err != nil {
+		t.Fatalf("Keystore should be valid JSON: %v", err)
+	}
+	crypto, _ := file["crypto"].(map[string]interface{})
+	for _, field := range []string{"salt", "nonce", "ciphertext"} {
+		if crypto[field] == "" || crypto[field] == nil {
+			t.Errorf("Keystore missing crypto.%s", field)
+		}
+	}
+
+	info, _ := os.Stat(path)
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("Keystore should be written with 0600, got %v", info.Mode().Perm())
+	}
+}
+
+func TestKeystoreWrongPassword(t *testing.T) {
+	w, _ := NewWallet()
+	path := filepath.Join(t.TempDir(), "wallet.json")
+	if err := w.SaveKeystore(path, "right"); err != nil {
+		t.Fatalf("Failed to save keystore: %v", err)
+	}
+
+	_, err := LoadKeystore(path, "wrong")
+	if err == nil {
+		t.Fatal("Loading with wrong password should fail")
+	}
+	if !strings.Contains(err.Error(), "wrong password") {
+		t.Errorf("Expected wrong password error, got: %v", err)
+	}
+}
+
+func TestKeystoreTamperedAddress(t *testing.T) {
+	w, _ := NewWallet()
+	other, _ := NewWallet()
+	path := filepath.Join(t.TempDir(), "wallet.json")
+	if err := w.SaveKeystore(path, "pass"); err != nil {
+		t.Fatalf("Failed to save keystore: %v", err)
+	}
+
+	// Trocar o endereço em claro invalida a autenticação do ciphertext
+	data, _ := os.ReadFile(path)
+	tampered := strings.Replace(string(data), w.GetAddress(), other.GetAddress(), 1)
+	if err := os.WriteFile(path, []byte(tampered), 0600); err != nil {
+		t.Fatalf("Failed to write tampered keystore: %v", err)
+	}
+
+	if _, err := LoadKeystore(path, "pass"); err == nil {
+		t.Error("Tampered keystore should fail to load")
+	}
+}
+
+func TestKeystoreEmptyPassword(t *testing.T) {
+	w, _ := NewWallet()
+	if err := w.SaveKeystore(filepath.Join(t.TempDir(), "wallet.json"), ""); err == nil {
+		t.Error("Saving with empty password should fail")
+	}
+}
+
+func TestKeystoreRejectsOversizedScryptParams(t *testing.T) {
+	w, _ := NewWallet()
+	path := filepath.Join(t.TempDir(), "wallet.json")
+	if err := w.SaveKeystore(path, "pass"); err != nil {
+		t.Fatalf("Failed to save keystore: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+
+	// Parâmetros adulterados precisam ser recusados antes de derivar a chave
+	for _, tc := range []struct{ from, to string }{
+		{`"n": 32768`, `"n": 1073741824`},
+		{`"n": 32768`, `"n": 1000`},
+		{`"r": 8`, `"r": 1024`},
+		{`"p": 1`, `"p": 100000`},
+		{`"dklen": 32`, `"dklen": 1000000`},
+	} {
+		tampered := strings.Replace(string(data), tc.from, tc.to, 1)
+		if tampered == string(data) {
+			t.Fatalf("Keystore does not contain %s", tc.from)
+		}
+		if err := os.WriteFile(path, []byte(tampered), 0600); err != nil {
+			t.Fatalf("Failed to write tampered keystore: %v", err)
+		}
+
+		_, err := LoadKeystore(path, "pass")
+		if err == nil || !strings.Contains(err.Error(), "scrypt") {
+			t.Errorf("Expected scrypt parameter error for %s, got: %v", tc.to, err)
+		}
+	}
+}
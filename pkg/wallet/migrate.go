@@ -0,0 +1,88 @@
+package wallet
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// plaintextWalletFile representa o JSON em texto puro gerado pelo wallet-gen sem -encrypt
+type plaintextWalletFile struct {
+	PrivateKey string `json:"private_key"`
+	PublicKey  string `json:"public_key"`
+	Address    string `json:"address"`
+	Mnemonic   string `json:"mnemonic,omitempty"`
+}
+
+// LoadPlaintextWallet carrega uma carteira do formato antigo em texto puro do wallet-gen,
+// verificando que a chave privada deriva a chave pública, o endereço e (se houver) a frase
+// mnemônica gravados no arquivo
+func LoadPlaintextWallet(path string) (*Wallet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read plaintext wallet: %w", err)
+	}
+
+	// Arquivos com várias carteiras (wallet-gen -count N) são listas
+	if trimmed := strings.TrimSpace(string(data)); strings.HasPrefix(trimmed, "[") {
+		return nil, fmt.Errorf("plaintext wallet file contains a list of wallets; split it into one file per wallet")
+	}
+
+	var plaintext plaintextWalletFile
+	if err := json.Unmarshal(data, &plaintext); err != nil {
+		return nil, fmt.Errorf("failed to parse plaintext wallet: %w", err)
+	}
+	if plaintext.PrivateKey == "" || plaintext.Address == "" {
+		return nil, fmt.Errorf("plaintext wallet is missing private_key or address")
+	}
+
+	w, err := NewWalletFromPrivateKey(plaintext.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if w.GetAddress() != plaintext.Address {
+		return nil, fmt.Errorf("plaintext wallet address mismatch: file has %s, key derives %s", plaintext.Address, w.GetAddress())
+	}
+	if plaintext.PublicKey != "" && w.GetPublicKeyHex() != plaintext.PublicKey {
+		return nil, fmt.Errorf("plaintext wallet public key does not match the private key")
+	}
+
+	if plaintext.Mnemonic != "" {
+		fromMnemonic, err := NewWalletFromMnemonic(plaintext.Mnemonic)
+		if err != nil {
+			return nil, fmt.Errorf("invalid mnemonic in plaintext wallet: %w", err)
+		}
+		if fromMnemonic.GetAddress() != plaintext.Address {
+			return nil, fmt.Errorf("plaintext wallet mnemonic does not derive address %s", plaintext.Address)
+		}
+		w.mnemonic = fromMnemonic.mnemonic
+	}
+
+	return w, nil
+}
+
+// MigrateToEncrypted converte uma carteira em texto puro do wallet-gen em um keystore
+// criptografado (ver SaveKeystore). Não sobrescreve um keystore existente nem apaga o arquivo
+// antigo: depois de conferir o keystore, remova o texto puro manualmente. A frase mnemônica
+// não é guardada no keystore.
+func MigrateToEncrypted(plaintextPath, encryptedPath, passphrase string) (*Wallet, error) {
+	if _, err := os.Stat(encryptedPath); err == nil {
+		return nil, fmt.Errorf("keystore file %s already exists", encryptedPath)
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to check keystore file: %w", err)
+	}
+
+	w, err := LoadPlaintextWallet(plaintextPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := w.SaveKeystore(encryptedPath, passphrase); err != nil {
+		return nil, err
+	}
+
+	return w, nil
+}
@@ -0,0 +1,134 @@
+package wallet
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Helper: grava a carteira no formato em texto puro do wallet-gen
+func writePlaintextWallet(t *testing.T, dir string, w *Wallet) string {
+	t.Helper()
+
+	plaintext := plaintextWalletFile{
+		PrivateKey: w.GetPrivateKeyHex(),
+		PublicKey:  w.GetPublicKeyHex(),
+		Address:    w.GetAddress(),
+	}
+	plaintext.Mnemonic, _ = w.Mnemonic()
+
+	data, err := json.MarshalIndent(plaintext, "", "  ")
+	if err != nil {
+		t.Fatalf("Failed to marshal plaintext wallet: %v", err)
+	}
+	path := filepath.Join(dir, "wallet.json")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatalf("Failed to write plaintext wallet: %v", err)
+	}
+	return path
+}
+
+func TestMigrateToEncryptedRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	original, err := NewWalletWithMnemonic(12)
+	if err != nil {
+		t.Fatalf("Failed to create wallet: %v", err)
+	}
+	plaintextPath := writePlaintextWallet(t, dir, original)
+	keystorePath := filepath.Join(dir, "keystore.json")
+
+	migrated, err := MigrateToEncrypted(plaintextPath, keystorePath, "migration pass")
+	if err != nil {
+		t.Fatalf("Failed to migrate wallet: %v", err)
+	}
+	if migrated.GetAddress() != original.GetAddress() {
+		t.Errorf("Migrated address mismatch: %s != %s", migrated.GetAddress(), original.GetAddress())
+	}
+
+	loaded, err := LoadKeystore(keystorePath, "migration pass")
+	if err != nil {
+		t.Fatalf("Failed to load migrated keystore: %v", err)
+	}
+	if loaded.GetPrivateKeyHex() != original.GetPrivateKeyHex() {
+		t.Error("Private key mismatch after migration")
+	}
+
+	// O keystore não guarda a chave em texto puro
+	data, _ := os.ReadFile(keystorePath)
+	if strings.Contains(string(data), original.GetPrivateKeyHex()) {
+		t.Error("Keystore should not contain the plaintext private key")
+	}
+
+	// Não sobrescreve um keystore existente
+	if _, err := MigrateToEncrypted(plaintextPath, keystorePath, "other pass"); err == nil {
+		t.Error("Migrating over an existing keystore should fail")
+	}
+	if _, err := LoadKeystore(keystorePath, "migration pass"); err != nil {
+		t.Errorf("Existing keystore should be left untouched: %v", err)
+	}
+}
+
+func TestMigrateToEncryptedTamperedPlaintext(t *testing.T) {
+	w, _ := NewWalletWithMnemonic(12)
+	other, _ := NewWalletWithMnemonic(12)
+	otherMnemonic, _ := other.Mnemonic()
+
+	tests := []struct {
+		name   string
+		tamper func(plaintext string) string
+	}{
+		{"address", func(p string) string { return strings.Replace(p, w.GetAddress(), other.GetAddress(), 1) }},
+		{"private key", func(p string) string { return strings.Replace(p, w.GetPrivateKeyHex(), other.GetPrivateKeyHex(), 1) }},
+		{"public key", func(p string) string { return strings.Replace(p, w.GetPublicKeyHex(), other.GetPublicKeyHex(), 1) }},
+		{"mnemonic", func(p string) string {
+			mnemonic, _ := w.Mnemonic()
+			return strings.Replace(p, mnemonic, otherMnemonic, 1)
+		}},
+	}
+
+	for _, tt := range tests {
+		dir := t.TempDir()
+		plaintextPath := writePlaintextWallet(t, dir, w)
+		data, _ := os.ReadFile(plaintextPath)
+		if err := os.WriteFile(plaintextPath, []byte(tt.tamper(string(data))), 0644); err != nil {
+			t.Fatalf("Failed to tamper plaintext wallet: %v", err)
+		}
+
+		keystorePath := filepath.Join(dir, "keystore.json")
+		if _, err := MigrateToEncrypted(plaintextPath, keystorePath, "pass"); err == nil {
+			t.Errorf("Tampered %s should fail to migrate", tt.name)
+		}
+		if _, err := os.Stat(keystorePath); !os.IsNotExist(err) {
+			t.Errorf("Tampered %s should not write a keystore", tt.name)
+		}
+	}
+}
+
+func TestMigrateToEncryptedInvalidFiles(t *testing.T) {
+	dir := t.TempDir()
+	w, _ := NewWallet()
+
+	// Lista de carteiras (wallet-gen -count N)
+	list, _ := json.Marshal([]plaintextWalletFile{{PrivateKey: w.GetPrivateKeyHex(), Address: w.GetAddress()}})
+	listPath := filepath.Join(dir, "wallets.json")
+	os.WriteFile(listPath, list, 0644)
+	if _, err := MigrateToEncrypted(listPath, filepath.Join(dir, "k1.json"), "pass"); err == nil {
+		t.Error("Wallet list should fail to migrate")
+	}
+
+	// Arquivo sem chave privada (já migrado com -encrypt)
+	noKey, _ := json.Marshal(plaintextWalletFile{Address: w.GetAddress()})
+	noKeyPath := filepath.Join(dir, "nokey.json")
+	os.WriteFile(noKeyPath, noKey, 0644)
+	if _, err := MigrateToEncrypted(noKeyPath, filepath.Join(dir, "k2.json"), "pass"); err == nil {
+		t.Error("Wallet without private key should fail to migrate")
+	}
+
+	// Senha vazia
+	plaintextPath := writePlaintextWallet(t, dir, w)
+	if _, err := MigrateToEncrypted(plaintextPath, filepath.Join(dir, "k3.json"), ""); err == nil {
+		t.Error("Migrating with empty passphrase should fail")
+	}
+}
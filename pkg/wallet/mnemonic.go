@@ -0,0 +1,197 @@
+package wallet
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+const (
+	// Parâmetros do BIP39 para derivação da seed
+	mnemonicSeedIterations = 2048
+	mnemonicSeedLength     = 64
+	mnemonicSaltPrefix     = "mnemonic"
+
+	// Chave HMAC usada para derivar a chave privada P-256 a partir da seed
+	// (mesma ideia do "Bitcoin seed" do BIP32, com domínio próprio)
+	mnemonicKeyDomain = "Krakovia seed"
+)
+
+// wordIndex mapeia cada palavra do wordlist para seu índice
+var wordIndex = func() map[string]int {
+	index := make(map[string]int, len(englishWordlist))
+	for i, word := range englishWordlist {
+		index[word] = i
+	}
+	return index
+}()
+
+// NewMnemonic gera uma nova frase mnemônica BIP39 com 12 ou 24 palavras
+func NewMnemonic(wordCount int) (string, error) {
+	entropySize, err := entropySizeForWords(wordCount)
+	if err != nil {
+		return "", err
+	}
+
+	entropy := make([]byte, entropySize)
+	if _, err := rand.Read(entropy); err != nil {
+		return "", fmt.Errorf("failed to generate entropy: %w", err)
+	}
+
+	return mnemonicFromEntropy(entropy)
+}
+
+// NewWalletWithMnemonic cria uma nova carteira a partir de uma frase mnemônica recém-gerada
+func NewWalletWithMnemonic(wordCount int) (*Wallet, error) {
+	phrase, err := NewMnemonic(wordCount)
+	if err != nil {
+		return nil, err
+	}
+	return NewWalletFromMnemonic(phrase)
+}
+
+// NewWalletFromMnemonic cria uma carteira derivando a chave privada de forma
+// determinística a partir de uma frase mnemônica BIP39 (12 ou 24 palavras)
+func NewWalletFromMnemonic(phrase string) (*Wallet, error) {
+	phrase = normalizeMnemonic(phrase)
+
+	// Valida quantidade de palavras, palavras desconhecidas e checksum
+	if _, err := entropyFromMnemonic(phrase); err != nil {
+		return nil, err
+	}
+
+	privateKey, err := privateKeyFromSeed(mnemonicToSeed(phrase, ""))
+	if err != nil {
+		return nil, err
+	}
+
+	return &Wallet{
+		PrivateKey: privateKey,
+		PublicKey:  &privateKey.PublicKey,
+		mnemonic:   phrase,
+	}, nil
+}
+
+// Mnemonic retorna a frase mnemônica que originou a carteira.
+// Carteiras criadas a partir de chave privada não possuem frase (a derivação é unidirecional).
+func (w *Wallet) Mnemonic() (string, error) {
+	if w.mnemonic == "" {
+		return "", fmt.Errorf("wallet was not created from a mnemonic")
+	}
+	return w.mnemonic, nil
+}
+
+// normalizeMnemonic remove espaços extras e converte para minúsculas
+func normalizeMnemonic(phrase string) string {
+	return strings.Join(strings.Fields(strings.ToLower(phrase)), " ")
+}
+
+// entropySizeForWords retorna o tamanho da entropia (em bytes) para a quantidade de palavras
+func entropySizeForWords(wordCount int) (int, error) {
+	switch wordCount {
+	case 12:
+		return 16, nil
+	case 24:
+		return 32, nil
+	default:
+		return 0, fmt.Errorf("invalid mnemonic word count: expected 12 or 24, got %d", wordCount)
+	}
+}
+
+// mnemonicFromEntropy codifica a entropia como frase mnemônica (entropia + checksum em grupos de 11 bits)
+func mnemonicFromEntropy(entropy []byte) (string, error) {
+	if len(entropy) != 16 && len(entropy) != 32 {
+		return "", fmt.Errorf("invalid entropy length: expected 16 or 32 bytes, got %d", len(entropy))
+	}
+
+	checksumBits := uint(len(entropy) * 8 / 32)
+	hash := sha256.Sum256(entropy)
+
+	// bits = entropia || primeiros checksumBits do SHA-256
+	bits := new(big.Int).SetBytes(entropy)
+	bits.Lsh(bits, checksumBits)
+	bits.Or(bits, big.NewInt(int64(hash[0]>>(8-checksumBits))))
+
+	wordCount := (len(entropy)*8 + int(checksumBits)) / 11
+	words := make([]string, wordCount)
+	mask := big.NewInt(2047)
+	for i := wordCount - 1; i >= 0; i-- {
+		words[i] = englishWordlist[new(big.Int).And(bits, mask).Int64()]
+		bits.Rsh(bits, 11)
+	}
+
+	return strings.Join(words, " "), nil
+}
+
+// entropyFromMnemonic decodifica a frase e valida quantidade de palavras e checksum
+func entropyFromMnemonic(phrase string) ([]byte, error) {
+	words := strings.Fields(phrase)
+
+	entropySize, err := entropySizeForWords(len(words))
+	if err != nil {
+		return nil, err
+	}
+
+	bits := new(big.Int)
+	for i, word := range words {
+		index, ok := wordIndex[word]
+		if !ok {
+			return nil, fmt.Errorf("invalid mnemonic word %d: %q is not in the BIP39 wordlist", i+1, word)
+		}
+		bits.Lsh(bits, 11)
+		bits.Or(bits, big.NewInt(int64(index)))
+	}
+
+	checksumBits := uint(entropySize * 8 / 32)
+	checksum := byte(new(big.Int).And(bits, big.NewInt(int64(1)<<checksumBits-1)).Int64())
+	bits.Rsh(bits, checksumBits)
+
+	entropy := make([]byte, entropySize)
+	bits.FillBytes(entropy)
+
+	hash := sha256.Sum256(entropy)
+	if expected := hash[0] >> (8 - checksumBits); checksum != expected {
+		return nil, fmt.Errorf("invalid mnemonic checksum")
+	}
+
+	return entropy, nil
+}
+
+// mnemonicToSeed deriva a seed BIP39 (PBKDF2-HMAC-SHA512, 2048 iterações)
+func mnemonicToSeed(phrase, passphrase string) []byte {
+	return pbkdf2.Key([]byte(phrase), []byte(mnemonicSaltPrefix+passphrase), mnemonicSeedIterations, mnemonicSeedLength, sha512.New)
+}
+
+// privateKeyFromSeed deriva uma chave privada P-256 válida a partir da seed.
+// Usa os primeiros 32 bytes de HMAC-SHA512(domínio, seed); se o escalar cair fora
+// de [1, N-1], aplica o HMAC novamente sobre o resultado anterior.
+func privateKeyFromSeed(seed []byte) (*ecdsa.PrivateKey, error) {
+	curve := elliptic.P256()
+	n := curve.Params().N
+
+	data := seed
+	for attempt := 0; attempt < 16; attempt++ {
+		mac := hmac.New(sha512.New, []byte(mnemonicKeyDomain))
+		mac.Write(data)
+		sum := mac.Sum(nil)
+
+		d := new(big.Int).SetBytes(sum[:32])
+		if d.Sign() > 0 && d.Cmp(n) < 0 {
+			privateKey := &ecdsa.PrivateKey{D: d}
+			privateKey.Curve = curve
+			privateKey.X, privateKey.Y = curve.ScalarBaseMult(sum[:32])
+			return privateKey, nil
+		}
+		data = sum
+	}
+
+	return nil, fmt.Errorf("failed to derive private key from seed")
+}
@@ -0,0 +1,181 @@
+package wallet
+
+import (
+	"encoding/hex"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestWordlist(t *testing.T) {
+	if len(englishWordlist) != 2048 {
+		t.Fatalf("Wordlist should have 2048 words, got %d", len(englishWordlist))
+	}
+	if !sort.StringsAreSorted(englishWordlist) {
+		t.Error("Wordlist should be sorted")
+	}
+
+	// No BIP39 as 4 primeiras letras identificam cada palavra
+	prefixes := make(map[string]bool)
+	for _, word := range englishWordlist {
+		prefix := word
+		if len(prefix) > 4 {
+			prefix = prefix[:4]
+		}
+		if prefixes[prefix] {
+			t.Errorf("Duplicate prefix %q", prefix)
+		}
+		prefixes[prefix] = true
+	}
+}
+
+func TestMnemonicFromEntropyVectors(t *testing.T) {
+	// Vetores oficiais do BIP39 (trezor/python-mnemonic)
+	vectors := []struct {
+		entropy  string
+		mnemonic string
+	}{
+		{"00000000000000000000000000000000", "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"},
+		{"7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f", "legal winner thank year wave sausage worth useful legal winner thank yellow"},
+		{"80808080808080808080808080808080", "letter advice cage absurd amount doctor acoustic avoid letter advice cage above"},
+		{"ffffffffffffffffffffffffffffffff", "zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo wrong"},
+		{"9e885d952ad362caeb4efe34a8e91bd2", "ozone drill grab fiber curtain grace pudding thank cruise elder eight picnic"},
+		{"c0ba5a8e914111210f2bd131f3d5e08d", "scheme spot photo card baby mountain device kick cradle pact join borrow"},
+		{"0000000000000000000000000000000000000000000000000000000000000000", "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon art"},
+		{"ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff", "zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo vote"},
+		{"68a79eaca2324873eacc50cb9c6eca8cc68ea5d936f98787c60c7ebc74e6ce7c", "hamster diagram private dutch cause delay private meat slide toddler razor book happy fancy gospel tennis maple dilemma loan word shrug inflict delay length"},
+	}
+
+	for _, v := range vectors {
+		entropy, _ := hex.DecodeString(v.entropy)
+
+		mnemonic, err := mnemonicFromEntropy(entropy)
+		if err != nil {
+			t.Fatalf("Failed to encode entropy %s: %v", v.entropy, err)
+		}
+		if mnemonic != v.mnemonic {
+			t.Errorf("Entropy %s: expected %q, got %q", v.entropy, v.mnemonic, mnemonic)
+		}
+
+		decoded, err := entropyFromMnemonic(v.mnemonic)
+		if err != nil {
+			t.Fatalf("Failed to decode %q: %v", v.mnemonic, err)
+		}
+		if hex.EncodeToString(decoded) != v.entropy {
+			t.Errorf("Mnemonic %q: expected entropy %s, got %x", v.mnemonic, v.entropy, decoded)
+		}
+	}
+}
+
+func TestMnemonicToSeedVector(t *testing.T) {
+	seed := mnemonicToSeed("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about", "TREZOR")
+
+	expected := "c55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04"
+	if hex.EncodeToString(seed) != expected {
+		t.Errorf("Unexpected seed: %x", seed)
+	}
+}
+
+func TestMnemonicRoundTrip(t *testing.T) {
+	for _, wordCount := range []int{12, 24} {
+		original, err := NewWalletWithMnemonic(wordCount)
+		if err != nil {
+			t.Fatalf("Failed to create wallet with %d words: %v", wordCount, err)
+		}
+
+		phrase, err := original.Mnemonic()
+		if err != nil {
+			t.Fatalf("Failed to get mnemonic: %v", err)
+		}
+		if got := len(strings.Fields(phrase)); got != wordCount {
+			t.Errorf("Expected %d words, got %d", wordCount, got)
+		}
+
+		restored, err := NewWalletFromMnemonic(phrase)
+		if err != nil {
+			t.Fatalf("Failed to restore wallet from mnemonic: %v", err)
+		}
+		if restored.GetAddress() != original.GetAddress() {
+			t.Errorf("Address mismatch after round-trip: %s != %s", restored.GetAddress(), original.GetAddress())
+		}
+
+		// Espaços extras e maiúsculas não alteram a derivação
+		messy, err := NewWalletFromMnemonic("  " + strings.ToUpper(strings.ReplaceAll(phrase, " ", "   ")) + "\n")
+		if err != nil {
+			t.Fatalf("Failed to restore wallet from unnormalized mnemonic: %v", err)
+		}
+		if messy.GetAddress() != original.GetAddress() {
+			t.Error("Normalized mnemonic should derive the same address")
+		}
+
+		// A chave derivada continua válida via chave privada hex
+		fromKey, err := NewWalletFromPrivateKey(original.GetPrivateKeyHex())
+		if err != nil {
+			t.Fatalf("Failed to restore from private key: %v", err)
+		}
+		if fromKey.GetAddress() != original.GetAddress() {
+			t.Error("Private key restore should match mnemonic wallet address")
+		}
+	}
+}
+
+func TestNewWalletFromMnemonicDeterministic(t *testing.T) {
+	phrase := "legal winner thank year wave sausage worth useful legal winner thank yellow"
+
+	w1, err := NewWalletFromMnemonic(phrase)
+	if err != nil {
+		t.Fatalf("Failed to create wallet: %v", err)
+	}
+	w2, _ := NewWalletFromMnemonic(phrase)
+
+	if w1.GetPrivateKeyHex() != w2.GetPrivateKeyHex() {
+		t.Error("Same mnemonic should derive the same private key")
+	}
+
+	// A carteira deve assinar e verificar normalmente
+	data := []byte("mnemonic wallet")
+	signature, err := w1.Sign(data)
+	if err != nil {
+		t.Fatalf("Failed to sign: %v", err)
+	}
+	if valid, _ := Verify(w1.GetPublicKeyHex(), data, signature); !valid {
+		t.Error("Signature from mnemonic wallet should be valid")
+	}
+}
+
+func TestNewWalletFromMnemonicInvalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		phrase string
+		errMsg string
+	}{
+		{"wrong word count", "abandon abandon abandon", "word count"},
+		{"15 words", strings.Repeat("abandon ", 14) + "about", "word count"},
+		{"unknown word", "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon krakovia", "not in the BIP39 wordlist"},
+		{"bad checksum", "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon", "checksum"},
+		{"empty", "", "word count"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewWalletFromMnemonic(tt.phrase)
+			if err == nil {
+				t.Fatal("Expected error")
+			}
+			if !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("Expected error containing %q, got: %v", tt.errMsg, err)
+			}
+		})
+	}
+}
+
+func TestMnemonicNotAvailable(t *testing.T) {
+	w, _ := NewWallet()
+	if _, err := w.Mnemonic(); err == nil {
+		t.Error("Wallet without mnemonic should return an error")
+	}
+
+	if _, err := NewMnemonic(18); err == nil {
+		t.Error("Unsupported word count should return an error")
+	}
+}
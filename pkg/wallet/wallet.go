@@ -14,6 +14,9 @@ import (
 type Wallet struct {
 	PrivateKey *ecdsa.PrivateKey
 	PublicKey  *ecdsa.PublicKey
+
+	// Frase mnemônica BIP39 de origem (vazia quando a carteira não veio de uma frase)
+	mnemonic string
 }
 
 // NewWallet cria uma nova carteira com par de chaves ECDSA
@@ -0,0 +1,403 @@
+package tests
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/krakovia/blockchain/internal/config"
+	"github.com/krakovia/blockchain/pkg/api"
+	"github.com/krakovia/blockchain/pkg/blockchain"
+	"github.com/krakovia/blockchain/pkg/node"
+	"github.com/krakovia/blockchain/pkg/signaling"
+)
+
+// TestAPIEventStreamNewBlock testa que um cliente de /api/ws recebe um evento new_block quando
+// um bloco entra na chain
+func TestAPIEventStreamNewBlock(t *testing.T) {
+	signalingPort := getRandomPort()
+	signalingURL := fmt.Sprintf("ws://localhost:%d/ws", signalingPort)
+	tempDir := getTempDataDir(t, "apievents")
+
+	server := signaling.NewServer()
+	go func() {
+		if err := server.Start(fmt.Sprintf(":%d", signalingPort)); err != nil {
+			t.Logf("Signaling server error: %v", err)
+		}
+	}()
+	defer func() {
+		if err := server.Stop(); err != nil {
+			t.Logf("Warning: error stopping signaling server: %v", err)
+		}
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+
+	apiAddr := fmt.Sprintf("127.0.0.1:%d", getRandomPort())
+	nodeConfig := createTestNodeConfig(t, "apievents-node", signalingURL, tempDir)
+	nodeConfig.APIConfig = &config.APIConfig{
+		Enabled:   true,
+		Address:   apiAddr,
+		Username:  "admin",
+		Password:  "secret",
+		BasicAuth: true,
+	}
+
+	n, err := node.NewNode(nodeConfig)
+	if err != nil {
+		t.Fatalf("Failed to create node: %v", err)
+	}
+	defer stopNode(n, t)
+
+	if err := n.Start(); err != nil {
+		t.Fatalf("Failed to start node: %v", err)
+	}
+
+	wsURL := "ws://" + apiAddr + "/api/ws"
+	dial := func(url string, header http.Header) (*websocket.Conn, *http.Response, error) {
+		var conn *websocket.Conn
+		var resp *http.Response
+		var err error
+		deadline := time.Now().Add(2 * time.Second)
+		for time.Now().Before(deadline) {
+			conn, resp, err = websocket.DefaultDialer.Dial(url, header)
+			if resp != nil {
+				break // Servidor respondeu (conexão aceita ou recusada)
+			}
+			time.Sleep(50 * time.Millisecond)
+		}
+		return conn, resp, err
+	}
+
+	// Sem credenciais a conexão é recusada
+	if _, resp, err := dial(wsURL, nil); err == nil || resp == nil || resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("Connection without credentials should be rejected with 401 (err %v)", err)
+	}
+
+	// Credenciais pelo parâmetro auth, como faz o navegador
+	token := base64.StdEncoding.EncodeToString([]byte("admin:secret"))
+	conn, _, err := dial(wsURL+"?auth="+token, nil)
+	if err != nil {
+		t.Fatalf("Failed to connect to event stream: %v", err)
+	}
+	defer conn.Close()
+
+	// Dá tempo do servidor registrar o cliente antes de publicar
+	time.Sleep(100 * time.Millisecond)
+
+	blocks := createSignedBlocks(t, nodeConfig.GenesisBlock, createTestWallet(t), 1)
+	data, err := blocks[0].Serialize()
+	if err != nil {
+		t.Fatalf("Failed to serialize block: %v", err)
+	}
+	n.HandlePeerMessage("gossip-peer", "block", data)
+	if height := n.GetChainHeight(); height != 1 {
+		t.Fatalf("Block should be added, height %d", height)
+	}
+
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	for {
+		var event struct {
+			Type string `json:"type"`
+			Data struct {
+				Height uint64 `json:"height"`
+				Hash   string `json:"hash"`
+			} `json:"data"`
+		}
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			t.Fatalf("Did not receive new_block event: %v", err)
+		}
+		if err := json.Unmarshal(message, &event); err != nil {
+			t.Fatalf("Invalid event %q: %v", message, err)
+		}
+		if event.Type != api.EventNewBlock {
+			continue
+		}
+
+		if event.Data.Height != 1 || event.Data.Hash != blocks[0].Hash {
+			t.Errorf("Expected new_block for height 1 (%s), got height %d (%s)", blocks[0].Hash, event.Data.Height, event.Data.Hash)
+		}
+		break
+	}
+
+	t.Logf("✓ new_block event pushed to WebSocket client")
+}
+
+// TestAPIRawStreamBackfill testa que um cliente de /api/stream/raw com fromHeight recebe os
+// blocos históricos completos e, em seguida, os blocos minerados ao vivo
+func TestAPIRawStreamBackfill(t *testing.T) {
+	signalingPort := getRandomPort()
+	signalingURL := fmt.Sprintf("ws://localhost:%d/ws", signalingPort)
+	tempDir := getTempDataDir(t, "apiraw")
+
+	server := signaling.NewServer()
+	go func() {
+		if err := server.Start(fmt.Sprintf(":%d", signalingPort)); err != nil {
+			t.Logf("Signaling server error: %v", err)
+		}
+	}()
+	defer func() {
+		if err := server.Stop(); err != nil {
+			t.Logf("Warning: error stopping signaling server: %v", err)
+		}
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+
+	apiAddr := fmt.Sprintf("127.0.0.1:%d", getRandomPort())
+	nodeConfig := createTestNodeConfig(t, "apiraw-node", signalingURL, tempDir)
+	nodeConfig.ChainConfig.BlockTime = 100 * time.Millisecond
+	nodeConfig.InitialStakeAddr = nodeConfig.Wallet.GetAddress()
+	nodeConfig.InitialStake = 100000
+	nodeConfig.APIConfig = &config.APIConfig{
+		Enabled:  true,
+		Address:  apiAddr,
+		Username: "admin",
+		Password: "secret",
+	}
+
+	n, err := node.NewNode(nodeConfig)
+	if err != nil {
+		t.Fatalf("Failed to create node: %v", err)
+	}
+	defer stopNode(n, t)
+
+	if err := n.Start(); err != nil {
+		t.Fatalf("Failed to start node: %v", err)
+	}
+
+	// Histórico: alguns blocos minerados antes de o cliente conectar
+	if err := n.StartMining(); err != nil {
+		t.Fatalf("Failed to start mining: %v", err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for n.GetChainHeight() < 3 && time.Now().Before(deadline) {
+		time.Sleep(50 * time.Millisecond)
+	}
+	n.StopMining()
+	historical := n.GetChainHeight()
+	if historical < 3 {
+		t.Fatalf("Expected at least 3 mined blocks, got height %d", historical)
+	}
+
+	// Token de /api/login
+	resp, err := http.Post("http://"+apiAddr+"/api/login", "application/json",
+		strings.NewReader(`{"username":"admin","password":"secret"}`))
+	if err != nil {
+		t.Fatalf("Failed to login: %v", err)
+	}
+	var login api.LoginResponse
+	err = json.NewDecoder(resp.Body).Decode(&login)
+	resp.Body.Close()
+	if err != nil || login.Token == "" {
+		t.Fatalf("Login should return a token (status %d): %v", resp.StatusCode, err)
+	}
+
+	header := http.Header{"Authorization": []string{"Bearer " + login.Token}}
+	conn, _, err := websocket.DefaultDialer.Dial("ws://"+apiAddr+"/api/stream/raw?fromHeight=1", header)
+	if err != nil {
+		t.Fatalf("Failed to connect to raw stream: %v", err)
+	}
+	defer conn.Close()
+
+	readBlock := func() api.RawEvent {
+		for {
+			var event api.RawEvent
+			_, message, err := conn.ReadMessage()
+			if err != nil {
+				t.Fatalf("Failed to read raw stream: %v", err)
+			}
+			if err := json.Unmarshal(message, &event); err != nil {
+				t.Fatalf("Invalid raw event %q: %v", message, err)
+			}
+			if event.Type == api.RawEventBlock {
+				return event
+			}
+		}
+	}
+
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	// Blocos históricos completos, em ordem, a partir da altura pedida
+	for height := uint64(1); height <= historical; height++ {
+		event := readBlock()
+		if !event.Backfill || event.Height != height {
+			t.Fatalf("Expected backfilled block %d, got height %d (backfill %v)", height, event.Height, event.Backfill)
+		}
+		block, err := blockchain.DeserializeBlock(event.Data)
+		if err != nil {
+			t.Fatalf("Raw block %d should deserialize: %v", height, err)
+		}
+		stored, _ := n.GetBlockByHeight(height)
+		if block.Hash != stored.Hash || block.Header.Signature == "" || len(block.Transactions) == 0 {
+			t.Errorf("Raw block %d should be the full stored block", height)
+		}
+	}
+
+	// Bloco minerado depois do backfill chega ao vivo, sem repetir os históricos
+	if err := n.StartMining(); err != nil {
+		t.Fatalf("Failed to restart mining: %v", err)
+	}
+	defer n.StopMining()
+
+	// Um bloco que terminava de ser minerado ao parar a mineração ainda pode vir no backfill
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	last := historical
+	live := readBlock()
+	for live.Backfill {
+		if live.Height != last+1 {
+			t.Fatalf("Expected backfilled block %d, got %d", last+1, live.Height)
+		}
+		last = live.Height
+		live = readBlock()
+	}
+	if live.Height != last+1 {
+		t.Fatalf("Expected live block %d right after the backfill, got height %d", last+1, live.Height)
+	}
+	if _, err := blockchain.DeserializeBlock(live.Data); err != nil {
+		t.Errorf("Live raw block should deserialize: %v", err)
+	}
+
+	t.Logf("✓ Raw stream sent %d backfilled blocks and live block %d", last, live.Height)
+}
+
+// TestAPIEventStreamReorg testa que o nó troca a chain principal por um fork concorrente mais
+// pesado (mais stake acumulado, mesmo sendo mais curto), restaura os saldos do fork e publica
+// o evento reorg com a profundidade
+func TestAPIEventStreamReorg(t *testing.T) {
+	signalingPort := getRandomPort()
+	signalingURL := fmt.Sprintf("ws://localhost:%d/ws", signalingPort)
+	tempDir := getTempDataDir(t, "apireorg")
+
+	server := signaling.NewServer()
+	go func() {
+		if err := server.Start(fmt.Sprintf(":%d", signalingPort)); err != nil {
+			t.Logf("Signaling server error: %v", err)
+		}
+	}()
+	defer func() {
+		if err := server.Stop(); err != nil {
+			t.Logf("Warning: error stopping signaling server: %v", err)
+		}
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+
+	// O validador pesado é a carteira do nó (dona da alocação do gênesis); o nó não minera
+	apiAddr := fmt.Sprintf("127.0.0.1:%d", getRandomPort())
+	nodeConfig := createTestNodeConfig(t, "apireorg-node", signalingURL, tempDir)
+	heavy := nodeConfig.Wallet
+	light := createTestWallet(t)
+	nodeConfig.InitialStakeAddr = heavy.GetAddress()
+	nodeConfig.InitialStake = 1000
+	nodeConfig.APIConfig = &config.APIConfig{
+		Enabled:   true,
+		Address:   apiAddr,
+		Username:  "admin",
+		Password:  "secret",
+		BasicAuth: true,
+	}
+
+	n, err := node.NewNode(nodeConfig)
+	if err != nil {
+		t.Fatalf("Failed to create node: %v", err)
+	}
+	defer stopNode(n, t)
+
+	if err := n.Start(); err != nil {
+		t.Fatalf("Failed to start node: %v", err)
+	}
+
+	token := base64.StdEncoding.EncodeToString([]byte("admin:secret"))
+	var conn *websocket.Conn
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if conn, _, err = websocket.DefaultDialer.Dial("ws://"+apiAddr+"/api/ws?auth="+token, nil); err == nil {
+			break
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	if err != nil {
+		t.Fatalf("Failed to connect to event stream: %v", err)
+	}
+	defer conn.Close()
+	time.Sleep(100 * time.Millisecond)
+
+	deliver := func(block *blockchain.Block) {
+		data, err := block.Serialize()
+		if err != nil {
+			t.Fatalf("Failed to serialize block: %v", err)
+		}
+		n.HandlePeerMessage("fork-peer", "block", data)
+	}
+
+	// Chain principal: 2 blocos de um validador sem stake (peso 0)
+	lightBlocks := createSignedBlocks(t, nodeConfig.GenesisBlock, light, 2)
+	for _, block := range lightBlocks {
+		deliver(block)
+	}
+	if height := n.GetChainHeight(); height != 2 {
+		t.Fatalf("Light blocks should be added, height %d", height)
+	}
+	if balance := n.GetChain().GetBalance(light.GetAddress()); balance != 100 {
+		t.Fatalf("Light validator should have 2 rewards, balance %d", balance)
+	}
+	heavyBalance := n.GetChain().GetBalance(heavy.GetAddress())
+
+	// Fork concorrente a partir do gênesis: 1 bloco do validador com stake (peso 1000)
+	heavyBlocks := createSignedBlocks(t, nodeConfig.GenesisBlock, heavy, 1)
+	deliver(heavyBlocks[0])
+
+	if last := n.GetChain().GetLastBlock(); last.Hash != heavyBlocks[0].Hash {
+		t.Fatalf("Node should switch to the heavier fork, tip at height %d", last.Header.Height)
+	}
+	if height := n.GetChainHeight(); height != 1 {
+		t.Errorf("Expected height 1 after reorganization, got %d", height)
+	}
+	if balance := n.GetChain().GetBalance(light.GetAddress()); balance != 0 {
+		t.Errorf("Replaced rewards should be reverted, balance %d", balance)
+	}
+	if balance := n.GetChain().GetBalance(heavy.GetAddress()); balance != heavyBalance+50 {
+		t.Errorf("Heavy validator should gain 1 reward, balance %d (was %d)", balance, heavyBalance)
+	}
+	if count := n.GetReorgCount(); count != 1 {
+		t.Errorf("Expected 1 reorganization, got %d", count)
+	}
+
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	for {
+		var event struct {
+			Type string `json:"type"`
+			Data struct {
+				ForkHeight uint64 `json:"fork_height"`
+				Depth      int    `json:"depth"`
+				OldTip     string `json:"old_tip"`
+				NewTip     string `json:"new_tip"`
+			} `json:"data"`
+		}
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			t.Fatalf("Did not receive reorg event: %v", err)
+		}
+		if err := json.Unmarshal(message, &event); err != nil {
+			t.Fatalf("Invalid event %q: %v", message, err)
+		}
+		if event.Type != api.EventReorg {
+			continue
+		}
+
+		if event.Data.Depth != 2 || event.Data.ForkHeight != 0 ||
+			event.Data.OldTip != lightBlocks[1].Hash || event.Data.NewTip != heavyBlocks[0].Hash {
+			t.Errorf("Unexpected reorg event: %s", message)
+		}
+		break
+	}
+
+	t.Logf("✓ Node reorganized to the heavier fork and published the reorg event")
+}
@@ -0,0 +1,123 @@
+package tests
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/krakovia/blockchain/pkg/blockchain"
+	"github.com/krakovia/blockchain/pkg/node"
+)
+
+// TestBlockFetchBackfillsParentByHash testa que um bloco de um fork cujo pai o nó nunca viu
+// dispara get_block pelo hash dos pais até o ramo conectar e ser aplicado
+func TestBlockFetchBackfillsParentByHash(t *testing.T) {
+	tempDir := getTempDataDir(t, "block-fetch")
+	nodeConfig := createTestNodeConfig(t, "fetch-node", "ws://localhost:0/ws", tempDir)
+
+	var requests []node.GetBlockRequest
+	nodeConfig.SendGetBlock = func(peerID string, req node.GetBlockRequest) error {
+		if peerID != "fork-peer" {
+			t.Errorf("get_block sent to %s, want fork-peer", peerID)
+		}
+		requests = append(requests, req)
+		return nil
+	}
+
+	n, err := node.NewNode(nodeConfig)
+	if err != nil {
+		t.Fatalf("Failed to create node: %v", err)
+	}
+	defer stopNode(n, t)
+
+	mainChain := createSignedBlocks(t, nodeConfig.GenesisBlock, createTestWallet(t), 4)
+	loadSyncedBlocks(t, n, mainChain)
+
+	// O fork sai do bloco 2 e é mais longo que a chain principal: 3', 4', 5'
+	fork := createSignedBlocks(t, mainChain[1], createTestWallet(t), 3)
+	forkByHash := make(map[string]*blockchain.Block)
+	for _, block := range fork {
+		forkByHash[block.Hash] = block
+	}
+
+	// Só a ponta do fork chega por gossip; o pai 4' é desconhecido
+	data, err := fork[2].Serialize()
+	if err != nil {
+		t.Fatalf("Failed to serialize block: %v", err)
+	}
+	n.HandlePeerMessage("fork-peer", "block", data)
+
+	// O peer responde cada get_block até o ramo encontrar o bloco 2
+	for answered := 0; answered < len(requests); answered++ {
+		response, err := json.Marshal(node.BlockResponse{
+			Hash:  requests[answered].Hash,
+			Block: forkByHash[requests[answered].Hash],
+		})
+		if err != nil {
+			t.Fatalf("Failed to marshal block response: %v", err)
+		}
+		n.HandlePeerMessage("fork-peer", "block_response", response)
+	}
+
+	if len(requests) != 2 || requests[0].Hash != fork[1].Hash || requests[1].Hash != fork[0].Hash {
+		t.Fatalf("Expected get_block for 4' and 3', got %+v", requests)
+	}
+	if height := n.GetChainHeight(); height != 5 {
+		t.Fatalf("Expected the fork to become the main chain at height 5, got %d", height)
+	}
+	for _, block := range fork {
+		if stored, found := n.GetBlockByHeight(block.Header.Height); !found || stored.Hash != block.Hash {
+			t.Errorf("Block %d should be the fork block after the reorg (found: %v)", block.Header.Height, found)
+		}
+	}
+
+	// O nó também serve os blocos por hash, inclusive os do fork que acabou de aplicar
+	if resp := n.BuildBlockResponse(fork[0].Hash); resp.Block == nil || resp.Block.Hash != fork[0].Hash {
+		t.Errorf("Expected block 3' in the response, got %+v", resp.Block)
+	}
+	if resp := n.BuildBlockResponse(mainChain[3].Hash); resp.Block != nil {
+		t.Errorf("Replaced block 4 should not be served, got %+v", resp.Block)
+	}
+}
+
+// TestBlockResponseIgnoresUnrequestedBlocks testa que respostas sem get_block correspondente
+// (outro peer ou hash não pedido) não alteram a chain
+func TestBlockResponseIgnoresUnrequestedBlocks(t *testing.T) {
+	tempDir := getTempDataDir(t, "block-fetch-unrequested")
+	nodeConfig := createTestNodeConfig(t, "fetch-node", "ws://localhost:0/ws", tempDir)
+	nodeConfig.SendGetBlock = func(peerID string, req node.GetBlockRequest) error { return nil }
+
+	n, err := node.NewNode(nodeConfig)
+	if err != nil {
+		t.Fatalf("Failed to create node: %v", err)
+	}
+	defer stopNode(n, t)
+
+	mainChain := createSignedBlocks(t, nodeConfig.GenesisBlock, createTestWallet(t), 2)
+	loadSyncedBlocks(t, n, mainChain)
+
+	fork := createSignedBlocks(t, mainChain[0], createTestWallet(t), 2)
+	data, err := fork[1].Serialize()
+	if err != nil {
+		t.Fatalf("Failed to serialize block: %v", err)
+	}
+	n.HandlePeerMessage("fork-peer", "block", data)
+
+	for _, resp := range []struct {
+		peerID string
+		msg    node.BlockResponse
+	}{
+		{"other-peer", node.BlockResponse{Hash: fork[0].Hash, Block: fork[0]}},
+		{"fork-peer", node.BlockResponse{Hash: mainChain[1].Hash, Block: fork[0]}},
+		{"fork-peer", node.BlockResponse{Hash: fork[0].Hash, Block: mainChain[1]}},
+	} {
+		payload, err := json.Marshal(resp.msg)
+		if err != nil {
+			t.Fatalf("Failed to marshal block response: %v", err)
+		}
+		n.HandlePeerMessage(resp.peerID, "block_response", payload)
+	}
+
+	if last := n.GetLastBlock(); last.Hash != mainChain[1].Hash {
+		t.Errorf("Unrequested block responses should not change the chain, tip is %d (%s)", last.Header.Height, last.Hash)
+	}
+}
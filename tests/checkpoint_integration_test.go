@@ -1,6 +1,7 @@
 package tests
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -113,7 +114,6 @@ func TestCheckpointIntegration(t *testing.T) {
 	chainConfig.BlockTime = 100 * time.Millisecond // Blocos rápidos para teste
 
 	nodeConfig := node.Config{
-		ID:                nodeID,
 		Address:           fmt.Sprintf(":%d", getRandomPort()),
 		DBPath:            dbPath,
 		SignalingServer:   signalingURL,
@@ -297,7 +297,6 @@ func TestCheckpointPruning(t *testing.T) {
 	chainConfig.BlockTime = 100 * time.Millisecond
 
 	nodeConfig := node.Config{
-		ID:                nodeID,
 		Address:           fmt.Sprintf(":%d", getRandomPort()),
 		DBPath:            dbPath,
 		SignalingServer:   signalingURL,
@@ -423,7 +422,6 @@ func TestCheckpointSync(t *testing.T) {
 
 	// Node 1
 	config1 := node.Config{
-		ID:                "sync_node1",
 		Address:           fmt.Sprintf(":%d", getRandomPort()),
 		DBPath:            filepath.Join(os.TempDir(), "krakovia_test_sync_node1"),
 		SignalingServer:   signalingURL,
@@ -456,8 +454,8 @@ func TestCheckpointSync(t *testing.T) {
 		t.Fatalf("Failed to start mining: %v", err)
 	}
 
-	// Aguardar 10 blocos
-	time.Sleep(3 * time.Second)
+	// Aguardar 10 blocos (o minerador espera BlockTime entre um bloco e o próximo)
+	time.Sleep(4 * time.Second)
 	node1.StopMining()
 
 	height1 := node1.GetChainHeight()
@@ -478,7 +476,6 @@ func TestCheckpointSync(t *testing.T) {
 	// Criar Node 2 (vai sincronizar via checkpoint)
 	fmt.Printf("\n[Node2] Starting and syncing...\n")
 	config2 := node.Config{
-		ID:                "sync_node2",
 		Address:           fmt.Sprintf(":%d", getRandomPort()),
 		DBPath:            filepath.Join(os.TempDir(), "krakovia_test_sync_node2"),
 		SignalingServer:   signalingURL,
@@ -596,7 +593,6 @@ func TestCheckpointHashValidation(t *testing.T) {
 
 	// Criar node
 	nodeConfig := node.Config{
-		ID:                "validation_test_node",
 		Address:           fmt.Sprintf(":%d", getRandomPort()),
 		DBPath:            dbPath,
 		SignalingServer:   signalingURL,
@@ -695,3 +691,117 @@ func TestCheckpointHashValidation(t *testing.T) {
 	fmt.Printf("✓ Checkpoint hash present in blocks: YES\n")
 	fmt.Printf("✓ Invalid block creation: VERIFIED\n")
 }
+
+// checkpointAccounts monta o estado de contas de uma chain como em um checkpoint
+func checkpointAccounts(ctx *blockchain.Context) map[string]*blockchain.AccountState {
+	accounts := make(map[string]*blockchain.AccountState)
+	account := func(addr string) *blockchain.AccountState {
+		if accounts[addr] == nil {
+			accounts[addr] = &blockchain.AccountState{Address: addr}
+		}
+		return accounts[addr]
+	}
+	for addr, balance := range ctx.GetAllBalances() {
+		account(addr).Balance = balance
+	}
+	for addr, stake := range ctx.GetAllStakes() {
+		account(addr).Stake = stake
+	}
+	for addr, nonce := range ctx.GetAllNonces() {
+		account(addr).Nonce = nonce
+	}
+	return accounts
+}
+
+// TestCheckpointEndorsementRequiredForFastSync verifica que, por padrão, um nó só restaura um
+// checkpoint endossado pela supermaioria do stake que ele conhece
+func TestCheckpointEndorsementRequiredForFastSync(t *testing.T) {
+	validator := createTestWallet(t)
+	attacker := createTestWallet(t)
+
+	genesisTx := blockchain.NewCoinbaseTransaction(validator.GetAddress(), 1000000000, 0)
+	stakeData, _ := blockchain.NewStakeData(100000).Serialize()
+	stakeTx := blockchain.NewTransaction(validator.GetAddress(), validator.GetAddress(), 100000, 0, 0, stakeData)
+	if err := stakeTx.Sign(validator); err != nil {
+		t.Fatalf("Failed to sign stake transaction: %v", err)
+	}
+	genesis := createGenesisWithStake(genesisTx, stakeTx)
+
+	chainConfig := blockchain.DefaultChainConfig()
+	chainConfig.BlockTime = 300 * time.Millisecond
+
+	// Chain de origem com 4 blocos; checkpoint na altura 3
+	source, err := blockchain.NewChain(genesis, chainConfig)
+	if err != nil {
+		t.Fatalf("Failed to create source chain: %v", err)
+	}
+	miner := blockchain.NewMiner(validator, source, blockchain.NewMempool())
+	var checkpoint *blockchain.Checkpoint
+	for i := 0; i < 4; i++ {
+		block, err := miner.CreateBlock()
+		if err != nil {
+			t.Fatalf("Failed to create block: %v", err)
+		}
+		if err := source.AddBlock(block); err != nil {
+			t.Fatalf("Failed to add block: %v", err)
+		}
+		if block.Header.Height == 3 {
+			checkpoint, err = blockchain.CreateCheckpoint(3, time.Now().Unix(), checkpointAccounts(source.GetContext()), source.TotalSupply(), ",")
+			if err != nil {
+				t.Fatalf("Failed to create checkpoint: %v", err)
+			}
+			checkpoint.BlockHash = block.Hash
+		}
+	}
+	blocksSince := source.GetBlockRange(3, 4)
+
+	tempDir := getTempDataDir(t, "checkpoint-endorsement")
+	nodeConfig := createTestNodeConfigWithSharedGenesis(t, "endorsement-node", "ws://localhost:9000/ws", tempDir, genesis)
+	nodeConfig.ChainConfig = chainConfig
+	nodeConfig.CheckpointConfig = &config.CheckpointConfig{
+		Enabled:      true,
+		Interval:     3,
+		KeepInMemory: 10,
+		KeepOnDisk:   2,
+		CSVDelimiter: ",",
+	}
+
+	testNode, err := node.NewNode(nodeConfig)
+	if err != nil {
+		t.Fatalf("Failed to create node: %v", err)
+	}
+	defer stopNode(testNode, t)
+
+	sendCheckpoint := func() {
+		data, err := json.Marshal(node.CheckpointResponse{
+			Checkpoint:    checkpoint,
+			BlocksSince:   blocksSince,
+			HasCheckpoint: true,
+		})
+		if err != nil {
+			t.Fatalf("Failed to marshal checkpoint response: %v", err)
+		}
+		testNode.HandlePeerMessage("peer", "checkpoint_response", data)
+	}
+
+	// Assinado apenas por quem não tem stake: rejeitado
+	if err := checkpoint.Sign(attacker, genesis.Hash); err != nil {
+		t.Fatalf("Failed to sign checkpoint: %v", err)
+	}
+	sendCheckpoint()
+	if height := testNode.GetChainHeight(); height != 0 {
+		t.Fatalf("Insufficiently endorsed checkpoint should be rejected, node height is %d", height)
+	}
+
+	// Endossado pelo validador que detém todo o stake: restaurado
+	if err := checkpoint.Sign(validator, genesis.Hash); err != nil {
+		t.Fatalf("Failed to sign checkpoint: %v", err)
+	}
+	sendCheckpoint()
+	if height := testNode.GetChainHeight(); height != 4 {
+		t.Fatalf("Endorsed checkpoint should be restored and followed by block 4, node height is %d", height)
+	}
+	if testNode.GetLastBlock().Hash != source.GetLastBlock().Hash {
+		t.Error("Node should follow the source chain after fast sync")
+	}
+}
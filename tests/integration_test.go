@@ -28,7 +28,6 @@ func createIntegrationNodeConfig(t *testing.T, nodeID string, signalingURL strin
 	dbPath := filepath.Join(os.TempDir(), "krakovia_test_"+nodeID)
 
 	return node.Config{
-		ID:                nodeID,
 		Address:           fmt.Sprintf(":%d", getRandomPort()),
 		DBPath:            dbPath,
 		SignalingServer:   signalingURL,
@@ -0,0 +1,122 @@
+package tests
+
+import (
+	"bufio"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/krakovia/blockchain/internal/config"
+	"github.com/krakovia/blockchain/pkg/node"
+	"github.com/krakovia/blockchain/pkg/signaling"
+)
+
+// TestNodeMetricsEndpoint testa o endpoint /metrics de um nó minerando
+func TestNodeMetricsEndpoint(t *testing.T) {
+	signalingPort := getRandomPort()
+	signalingURL := fmt.Sprintf("ws://localhost:%d/ws", signalingPort)
+	tempDir := getTempDataDir(t, "metrics")
+
+	server := signaling.NewServer()
+	go func() {
+		if err := server.Start(fmt.Sprintf(":%d", signalingPort)); err != nil {
+			t.Logf("Signaling server error: %v", err)
+		}
+	}()
+	defer func() {
+		if err := server.Stop(); err != nil {
+			t.Logf("Warning: error stopping signaling server: %v", err)
+		}
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+
+	apiAddr := fmt.Sprintf("127.0.0.1:%d", getRandomPort())
+	nodeConfig := createTestNodeConfig(t, "metrics-node", signalingURL, tempDir)
+	nodeConfig.ChainConfig.BlockTime = 100 * time.Millisecond
+	nodeConfig.InitialStakeAddr = nodeConfig.Wallet.GetAddress()
+	nodeConfig.InitialStake = 100000
+	nodeConfig.APIConfig = &config.APIConfig{
+		Enabled:        true,
+		Address:        apiAddr,
+		Username:       "admin",
+		Password:       "secret",
+		BasicAuth:      true,
+		MetricsEnabled: true,
+	}
+
+	n, err := node.NewNode(nodeConfig)
+	if err != nil {
+		t.Fatalf("Failed to create node: %v", err)
+	}
+	defer stopNode(n, t)
+
+	if err := n.Start(); err != nil {
+		t.Fatalf("Failed to start node: %v", err)
+	}
+	if err := n.StartMining(); err != nil {
+		t.Fatalf("Failed to start mining: %v", err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for n.GetChainHeight() < 2 && time.Now().Before(deadline) {
+		time.Sleep(50 * time.Millisecond)
+	}
+	n.StopMining()
+
+	req, _ := http.NewRequest(http.MethodGet, "http://"+apiAddr+"/metrics", nil)
+	req.SetBasicAuth("admin", "secret")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Failed to fetch metrics: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", resp.StatusCode)
+	}
+
+	values := make(map[string]float64)
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		value, err := strconv.ParseFloat(fields[1], 64)
+		if err != nil {
+			t.Fatalf("Invalid metric value in %q: %v", line, err)
+		}
+		values[fields[0]] = value
+	}
+
+	for _, name := range []string{
+		"krakovia_chain_height",
+		"krakovia_mempool_size",
+		"krakovia_peers",
+		"krakovia_blocks_mined_total",
+		"krakovia_syncing",
+		"krakovia_last_block_timestamp_seconds",
+	} {
+		if _, ok := values[name]; !ok {
+			t.Errorf("Expected metric %s to be exposed", name)
+		}
+	}
+
+	if values["krakovia_blocks_mined_total"] < 1 {
+		t.Errorf("Expected mined blocks to be counted, got %v", values["krakovia_blocks_mined_total"])
+	}
+	if values["krakovia_chain_height"] < 1 {
+		t.Errorf("Expected chain height to be reported, got %v", values["krakovia_chain_height"])
+	}
+
+	t.Logf("✓ Metrics endpoint exposes node metrics (height %v, mined %v)",
+		values["krakovia_chain_height"], values["krakovia_blocks_mined_total"])
+}
@@ -0,0 +1,119 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/krakovia/blockchain/internal/config"
+	"github.com/krakovia/blockchain/pkg/blockchain"
+	"github.com/krakovia/blockchain/pkg/node"
+	"github.com/krakovia/blockchain/pkg/signaling"
+)
+
+// TestExternalMiningTemplateAndSubmit monta um bloco fora do nó a partir de
+// GET /api/mining/template e o entrega em POST /api/mining/submit
+func TestExternalMiningTemplateAndSubmit(t *testing.T) {
+	signalingPort := getRandomPort()
+	signalingURL := fmt.Sprintf("ws://localhost:%d/ws", signalingPort)
+	tempDir := getTempDataDir(t, "external-mining")
+
+	server := signaling.NewServer()
+	go func() {
+		if err := server.Start(fmt.Sprintf(":%d", signalingPort)); err != nil {
+			t.Logf("Signaling server error: %v", err)
+		}
+	}()
+	defer func() {
+		if err := server.Stop(); err != nil {
+			t.Logf("Warning: error stopping signaling server: %v", err)
+		}
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+
+	apiAddr := fmt.Sprintf("127.0.0.1:%d", getRandomPort())
+	nodeConfig := createTestNodeConfig(t, "external-miner", signalingURL, tempDir)
+	nodeConfig.ChainConfig.BlockTime = 100 * time.Millisecond
+	nodeConfig.InitialStakeAddr = nodeConfig.Wallet.GetAddress()
+	nodeConfig.InitialStake = 100000
+	nodeConfig.APIConfig = &config.APIConfig{
+		Enabled: true,
+		Address: apiAddr,
+	}
+
+	n, err := node.NewNode(nodeConfig)
+	if err != nil {
+		t.Fatalf("Failed to create node: %v", err)
+	}
+	defer stopNode(n, t)
+
+	if err := n.Start(); err != nil {
+		t.Fatalf("Failed to start node: %v", err)
+	}
+	time.Sleep(200 * time.Millisecond)
+
+	// Transação pendente que o template deve incluir
+	tx, err := n.CreateTransaction(createTestWallet(t).GetAddress(), 10, 1, "")
+	if err != nil {
+		t.Fatalf("Failed to create transaction: %v", err)
+	}
+
+	resp, err := http.Get("http://" + apiAddr + "/api/mining/template")
+	if err != nil {
+		t.Fatalf("Failed to fetch template: %v", err)
+	}
+	var template blockchain.BlockTemplate
+	err = json.NewDecoder(resp.Body).Decode(&template)
+	resp.Body.Close()
+	if err != nil {
+		t.Fatalf("Failed to decode template: %v", err)
+	}
+
+	if template.Height != 1 || template.PreviousHash != n.GetLastBlock().Hash {
+		t.Fatalf("Template does not extend the tip: height %d, previous %s", template.Height, template.PreviousHash)
+	}
+	if !template.CanMine || !template.IsMyTurn || template.Rank != 0 {
+		t.Errorf("Sole validator should be eligible: %+v", template)
+	}
+	if len(template.Transactions) != 1 || template.Transactions[0].ID != tx.ID {
+		t.Fatalf("Expected the pending transaction in the template, got %d transactions", len(template.Transactions))
+	}
+
+	// O processo externo monta o bloco sem assinatura; o nó assina com a carteira do validador
+	block := template.NewBlock()
+	body, err := json.Marshal(block)
+	if err != nil {
+		t.Fatalf("Failed to marshal block: %v", err)
+	}
+
+	submit := func() int {
+		resp, err := http.Post("http://"+apiAddr+"/api/mining/submit", "application/json", bytes.NewReader(body))
+		if err != nil {
+			t.Fatalf("Failed to submit block: %v", err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	if status := submit(); status != http.StatusOK {
+		t.Fatalf("Expected block to be accepted, got status %d", status)
+	}
+	if n.GetChainHeight() != 1 {
+		t.Fatalf("Expected height 1 after submit, got %d", n.GetChainHeight())
+	}
+	if n.GetMempoolSize() != 0 {
+		t.Errorf("Submitted block should clear its transactions from the mempool, %d left", n.GetMempoolSize())
+	}
+	if _, _, found := n.GetChain().FindTransaction(tx.ID); !found {
+		t.Error("Transaction from the template should be in the chain")
+	}
+
+	// O mesmo bloco não é aceito duas vezes
+	if status := submit(); status != http.StatusBadRequest {
+		t.Errorf("Expected resubmission to be rejected, got status %d", status)
+	}
+}
@@ -0,0 +1,78 @@
+package tests
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/krakovia/blockchain/pkg/node"
+)
+
+// TestConcurrentStartStopMining dispara StartMining/StopMining/IsMining de várias goroutines
+// (rode com -race): nenhum close duplo do canal de parada e o nó continua minerando depois
+func TestConcurrentStartStopMining(t *testing.T) {
+	tempDir := getTempDataDir(t, "mining-lifecycle")
+	nodeConfig := createTestNodeConfig(t, "mining-lifecycle", "ws://localhost:1/ws", tempDir)
+	nodeConfig.ChainConfig.BlockTime = 100 * time.Millisecond
+	nodeConfig.InitialStakeAddr = nodeConfig.Wallet.GetAddress()
+	nodeConfig.InitialStake = 100000
+
+	n, err := node.NewNode(nodeConfig)
+	if err != nil {
+		t.Fatalf("Failed to create node: %v", err)
+	}
+	defer stopNode(n, t)
+
+	var wg sync.WaitGroup
+	var startsMutex sync.Mutex
+	starts := 0
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				switch (g + i) % 3 {
+				case 0:
+					if n.StartMining() == nil {
+						startsMutex.Lock()
+						starts++
+						startsMutex.Unlock()
+					}
+				case 1:
+					n.StopMining()
+				default:
+					_ = n.IsMining()
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	if starts == 0 {
+		t.Fatal("Expected at least one successful StartMining")
+	}
+
+	// Parada e partida duplas são inofensivas
+	n.StopMining()
+	n.StopMining()
+	if n.IsMining() {
+		t.Fatal("Node should not be mining after StopMining")
+	}
+	if err := n.StartMining(); err != nil {
+		t.Fatalf("Failed to start mining after the storm: %v", err)
+	}
+	if err := n.StartMining(); err == nil {
+		t.Error("Second StartMining should report that the node is already mining")
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for n.GetChainHeight() < 1 && time.Now().Before(deadline) {
+		time.Sleep(50 * time.Millisecond)
+	}
+	n.StopMining()
+
+	if n.GetChainHeight() < 1 {
+		t.Errorf("Node should still mine after concurrent start/stop calls")
+	}
+	t.Logf("✓ %d concurrent starts, chain at height %d", starts, n.GetChainHeight())
+}
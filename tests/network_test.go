@@ -1,11 +1,14 @@
 package tests
 
 import (
+	"encoding/json"
 	"fmt"
 	"sync"
 	"testing"
 	"time"
 
+	"github.com/krakovia/blockchain/pkg/blockchain"
+	"github.com/krakovia/blockchain/pkg/network"
 	"github.com/krakovia/blockchain/pkg/node"
 	"github.com/krakovia/blockchain/pkg/signaling"
 )
@@ -303,3 +306,724 @@ func TestNodeReconnection(t *testing.T) {
 
 	t.Logf("✓ Reconnection successful")
 }
+
+// peerRecorder registra os peers de um cliente WebRTC usado diretamente no teste
+type peerRecorder struct {
+	mu    sync.Mutex
+	peers map[string]bool
+}
+
+func (r *peerRecorder) AddPeer(peer *network.Peer) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.peers[peer.ID] = true
+}
+
+func (r *peerRecorder) RemovePeer(peerID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.peers, peerID)
+}
+
+// TestNodeRejectsImpersonatingPeer testa que um peer com ID diferente da sua chave é desconectado
+func TestNodeRejectsImpersonatingPeer(t *testing.T) {
+	signalingPort := getRandomPort()
+	signalingURL := fmt.Sprintf("ws://localhost:%d/ws", signalingPort)
+	tempDir := getTempDataDir(t, "impersonation")
+
+	server := signaling.NewServer()
+	go func() {
+		if err := server.Start(fmt.Sprintf(":%d", signalingPort)); err != nil {
+			t.Logf("Signaling server error: %v", err)
+		}
+	}()
+	defer func() {
+		if err := server.Stop(); err != nil {
+			t.Logf("Warning: error stopping signaling server: %v", err)
+		}
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+
+	// ID diferente do endereço da carteira é recusado na criação do nó
+	badConfig := createTestNodeConfig(t, "bad-id-node", signalingURL, tempDir)
+	badConfig.ID = "node1"
+	if _, err := node.NewNode(badConfig); err == nil {
+		t.Fatal("Node with an ID that does not match its wallet should be rejected")
+	}
+
+	nodeConfig := createTestNodeConfig(t, "honest-node", signalingURL, tempDir)
+	n, err := node.NewNode(nodeConfig)
+	if err != nil {
+		t.Fatalf("Failed to create node: %v", err)
+	}
+	defer stopNode(n, t)
+
+	if n.ID != nodeConfig.Wallet.GetAddress() {
+		t.Errorf("Node ID should be derived from its wallet, got %s", n.ID)
+	}
+	if err := n.Start(); err != nil {
+		t.Fatalf("Failed to start node: %v", err)
+	}
+
+	// Atacante se registra com o ID de outra carteira, mas só tem a própria chave
+	victim := createTestWallet(t)
+	attacker := createTestWallet(t)
+	recorder := &peerRecorder{peers: make(map[string]bool)}
+	impostor, err := network.NewWebRTCClient(victim.GetAddress(), signalingURL, recorder)
+	if err != nil {
+		t.Fatalf("Failed to create impostor client: %v", err)
+	}
+	impostor.SetIdentity(attacker)
+	defer impostor.Close()
+
+	if err := impostor.Connect(); err != nil {
+		t.Fatalf("Failed to connect impostor: %v", err)
+	}
+
+	time.Sleep(2 * time.Second)
+
+	if peers := n.GetPeers(); len(peers) != 0 {
+		t.Errorf("Impersonating peer should be disconnected, node has %d peers", len(peers))
+	}
+
+	t.Logf("✓ Impersonating peer rejected by handshake")
+}
+
+// TestNodeRateLimitsTransactionFlood testa que um peer não consegue inundar o nó com transações
+func TestNodeRateLimitsTransactionFlood(t *testing.T) {
+	tempDir := getTempDataDir(t, "ratelimit")
+
+	nodeConfig := createTestNodeConfig(t, "ratelimit-node", "ws://localhost:1/ws", tempDir)
+	nodeConfig.MessageRateLimits = map[string]network.TokenBucketLimit{
+		"transaction": {Rate: 1, Burst: 5},
+	}
+
+	n, err := node.NewNode(nodeConfig)
+	if err != nil {
+		t.Fatalf("Failed to create node: %v", err)
+	}
+	defer stopNode(n, t)
+
+	sender := createTestWallet(t)
+	dest := createTestWallet(t)
+	newTxData := func(nonce uint64) []byte {
+		tx := blockchain.NewTransaction(sender.GetAddress(), dest.GetAddress(), 1, 1, nonce, "")
+		if err := tx.Sign(sender); err != nil {
+			t.Fatalf("Failed to sign transaction: %v", err)
+		}
+		data, err := tx.Serialize()
+		if err != nil {
+			t.Fatalf("Failed to serialize transaction: %v", err)
+		}
+		return data
+	}
+
+	// Rajada de 20 transações do mesmo peer: só as 5 da rajada permitida entram
+	for nonce := uint64(1); nonce <= 20; nonce++ {
+		n.HandlePeerMessage("flooder", "transaction", newTxData(nonce))
+	}
+	if size := n.GetMempoolSize(); size != 5 {
+		t.Errorf("Expected 5 transactions admitted from the flooding peer, got %d", size)
+	}
+
+	// O limite é por peer: outro peer continua sendo atendido
+	n.HandlePeerMessage("honest", "transaction", newTxData(21))
+	if size := n.GetMempoolSize(); size != 6 {
+		t.Errorf("Transaction from another peer should be admitted, mempool size %d", size)
+	}
+
+	t.Logf("✓ Excess transactions from flooding peer dropped")
+}
+
+// TestGossipBlockAheadOfTipIsBufferedAsOrphan testa que um bloco de gossip que pula alturas
+// fica guardado como órfão, enquanto blocos contíguos da sincronização são aplicados
+func TestGossipBlockAheadOfTipIsBufferedAsOrphan(t *testing.T) {
+	tempDir := getTempDataDir(t, "orphans")
+
+	nodeConfig := createTestNodeConfig(t, "orphan-node", "ws://localhost:1/ws", tempDir)
+	nodeConfig.MaxFutureBlocks = 5
+	nodeConfig.InitialStakeAddr = nodeConfig.Wallet.GetAddress()
+	nodeConfig.InitialStake = 1000
+
+	n, err := node.NewNode(nodeConfig)
+	if err != nil {
+		t.Fatalf("Failed to create node: %v", err)
+	}
+	defer stopNode(n, t)
+
+	// Blocos 1-7 do validador com stake, encadeados sobre o gênesis do nó
+	blocks := createSignedBlocks(t, nodeConfig.GenesisBlock, nodeConfig.Wallet, 7)
+
+	gossip := func(block *blockchain.Block) {
+		data, err := block.Serialize()
+		if err != nil {
+			t.Fatalf("Failed to serialize block: %v", err)
+		}
+		n.HandlePeerMessage("gossiper", "block", data)
+	}
+
+	// Além de MaxFutureBlocks o bloco é descartado
+	gossip(blocks[6])
+	if n.GetOrphanCount() != 0 {
+		t.Errorf("Block beyond max future distance should be ignored, got %d orphans", n.GetOrphanCount())
+	}
+
+	// Bloco em tip+5: guardado como órfão, não aplicado
+	gossip(blocks[4])
+	if height := n.GetChainHeight(); height != 0 {
+		t.Fatalf("Gossip block at tip+5 should not be applied, height is %d", height)
+	}
+	if n.GetOrphanCount() != 1 {
+		t.Fatalf("Gossip block at tip+5 should be buffered as orphan, got %d orphans", n.GetOrphanCount())
+	}
+
+	// Blocos 1-4 entregues pela sincronização são aplicados e conectam o órfão
+	data, err := json.Marshal(node.SyncResponse{Blocks: blocks[:4]})
+	if err != nil {
+		t.Fatalf("Failed to marshal sync response: %v", err)
+	}
+	n.HandlePeerMessage("syncer", "sync_response", data)
+
+	if height := n.GetChainHeight(); height != 5 {
+		t.Errorf("Expected height 5 after sync connected the orphan, got %d", height)
+	}
+	if n.GetOrphanCount() != 0 {
+		t.Errorf("Connected orphan should leave the buffer, got %d orphans", n.GetOrphanCount())
+	}
+
+	// Bloco em tip+1 por gossip é aplicado diretamente
+	gossip(blocks[5])
+	if height := n.GetChainHeight(); height != 6 {
+		t.Errorf("Gossip block at tip+1 should be applied, height is %d", height)
+	}
+
+	t.Logf("✓ Gossip orphans buffered until sync fills the gap")
+}
+
+// TestGossipOrphansConnectWhenParentArrives testa que blocos de gossip que chegam antes do pai
+// ficam no pool de órfãos e entram na chain, em cadeia, assim que o pai chega
+func TestGossipOrphansConnectWhenParentArrives(t *testing.T) {
+	tempDir := getTempDataDir(t, "orphans-parent")
+
+	nodeConfig := createTestNodeConfig(t, "orphan-node", "ws://localhost:1/ws", tempDir)
+	nodeConfig.SendSyncRequest = func(peerID string, req node.SyncRequest) error { return nil }
+	nodeConfig.InitialStakeAddr = nodeConfig.Wallet.GetAddress()
+	nodeConfig.InitialStake = 1000
+
+	n, err := node.NewNode(nodeConfig)
+	if err != nil {
+		t.Fatalf("Failed to create node: %v", err)
+	}
+	defer stopNode(n, t)
+
+	blocks := createSignedBlocks(t, nodeConfig.GenesisBlock, nodeConfig.Wallet, 4)
+	gossip := func(block *blockchain.Block) {
+		data, err := block.Serialize()
+		if err != nil {
+			t.Fatalf("Failed to serialize block: %v", err)
+		}
+		n.HandlePeerMessage("gossiper", "block", data)
+	}
+
+	gossip(blocks[0])
+	if height := n.GetChainHeight(); height != 1 {
+		t.Fatalf("Expected height 1, got %d", height)
+	}
+
+	// N+2 e N+1 chegam antes de N (N = 2): ficam guardados, fora da chain
+	gossip(blocks[3])
+	gossip(blocks[2])
+	gossip(blocks[2]) // Repetido não ocupa o pool duas vezes
+	if height := n.GetChainHeight(); height != 1 {
+		t.Fatalf("Blocks ahead of their parent should not be applied, height is %d", height)
+	}
+	if count := n.GetOrphanCount(); count != 2 {
+		t.Fatalf("Expected 2 orphans waiting for their parents, got %d", count)
+	}
+
+	// N chega: N+1 e N+2 conectam em seguida
+	gossip(blocks[1])
+	if height := n.GetChainHeight(); height != 4 {
+		t.Fatalf("Expected the orphans to connect once the parent arrived, height is %d", height)
+	}
+	for _, block := range blocks {
+		if stored, found := n.GetBlockByHeight(block.Header.Height); !found || stored.Hash != block.Hash {
+			t.Errorf("Block %d should be in the chain (found: %v)", block.Header.Height, found)
+		}
+	}
+	if count := n.GetOrphanCount(); count != 0 {
+		t.Errorf("Connected orphans should leave the pool, got %d", count)
+	}
+}
+
+// TestOrphanPoolLimitsPerPeer testa que só blocos de validadores com stake ocupam o pool de
+// órfãos e que um peer que inunda o pool descarta os próprios órfãos, não os dos outros
+func TestOrphanPoolLimitsPerPeer(t *testing.T) {
+	tempDir := getTempDataDir(t, "orphans-per-peer")
+
+	nodeConfig := createTestNodeConfig(t, "orphan-node", "ws://localhost:1/ws", tempDir)
+	nodeConfig.SendSyncRequest = func(peerID string, req node.SyncRequest) error { return nil }
+	nodeConfig.MaxFutureBlocks = 100
+	nodeConfig.InitialStakeAddr = nodeConfig.Wallet.GetAddress()
+	nodeConfig.InitialStake = 1000
+
+	n, err := node.NewNode(nodeConfig)
+	if err != nil {
+		t.Fatalf("Failed to create node: %v", err)
+	}
+	defer stopNode(n, t)
+
+	gossip := func(peerID string, block *blockchain.Block) {
+		data, err := block.Serialize()
+		if err != nil {
+			t.Fatalf("Failed to serialize block: %v", err)
+		}
+		n.HandlePeerMessage(peerID, "block", data)
+	}
+
+	// Órfão assinado por um endereço sem stake não ocupa o pool
+	junk := createSignedBlocks(t, nodeConfig.GenesisBlock, createTestWallet(t), 2)
+	gossip("junk", junk[1])
+	if count := n.GetOrphanCount(); count != 0 {
+		t.Fatalf("Orphan from a validator without stake should be ignored, got %d orphans", count)
+	}
+
+	// O peer que inunda fica limitado à sua cota: os órfãos mais antigos dele saem
+	blocks := createSignedBlocks(t, nodeConfig.GenesisBlock, nodeConfig.Wallet, 60)
+	for _, block := range blocks[1:50] {
+		gossip("flooder", block)
+	}
+	flooded := n.GetOrphanCount()
+	if flooded == 0 || flooded >= 49 {
+		t.Fatalf("Flooding peer should be capped below 49 orphans, got %d", flooded)
+	}
+
+	// Outro peer continua tendo vaga
+	gossip("honest", blocks[55])
+	if count := n.GetOrphanCount(); count != flooded+1 {
+		t.Errorf("Orphan from another peer should be buffered, got %d orphans (flooder had %d)", count, flooded)
+	}
+}
+
+// TestHeadersFirstSync testa que um nó valida os headers de um peer antes de baixar os corpos
+func TestHeadersFirstSync(t *testing.T) {
+	signalingPort := getRandomPort()
+	signalingURL := fmt.Sprintf("ws://localhost:%d/ws", signalingPort)
+	tempDir := getTempDataDir(t, "headers-first")
+
+	server := signaling.NewServer()
+	go func() {
+		if err := server.Start(fmt.Sprintf(":%d", signalingPort)); err != nil {
+			t.Logf("Signaling server error: %v", err)
+		}
+	}()
+	defer func() {
+		if err := server.Stop(); err != nil {
+			t.Logf("Warning: error stopping signaling server: %v", err)
+		}
+	}()
+	time.Sleep(100 * time.Millisecond)
+
+	sourceConfig := createTestNodeConfig(t, "headers-source", signalingURL, tempDir)
+	syncerConfig := createTestNodeConfigWithSharedGenesis(t, "headers-syncer", signalingURL, tempDir, sourceConfig.GenesisBlock)
+	syncerConfig.HeadersFirstSync = true
+
+	// Os headers só são aceitos de validadores com stake
+	validator := sourceConfig.Wallet
+	for _, config := range []*node.Config{&sourceConfig, &syncerConfig} {
+		config.InitialStakeAddr = validator.GetAddress()
+		config.InitialStake = 1000
+	}
+
+	source, err := node.NewNode(sourceConfig)
+	if err != nil {
+		t.Fatalf("Failed to create source node: %v", err)
+	}
+	defer stopNode(source, t)
+
+	syncer, err := node.NewNode(syncerConfig)
+	if err != nil {
+		t.Fatalf("Failed to create syncer node: %v", err)
+	}
+	defer stopNode(syncer, t)
+
+	// O nó de origem tem 50 blocos
+	blocks := createSignedBlocks(t, sourceConfig.GenesisBlock, validator, 50)
+	data, err := json.Marshal(node.SyncResponse{Blocks: blocks})
+	if err != nil {
+		t.Fatalf("Failed to marshal sync response: %v", err)
+	}
+	source.HandlePeerMessage("loader", "sync_response", data)
+	if height := source.GetChainHeight(); height != 50 {
+		t.Fatalf("Source node should have 50 blocks, got %d", height)
+	}
+
+	// Headers adulterados são rejeitados antes de qualquer corpo ser baixado
+	bogus := source.BuildHeadersResponse(1)
+	if len(bogus.Headers) != 50 || bogus.HasMore {
+		t.Fatalf("Expected 50 headers without more, got %d (has more %v)", len(bogus.Headers), bogus.HasMore)
+	}
+	bogus.Headers[20].MerkleRoot = "forged"
+	data, err = json.Marshal(bogus)
+	if err != nil {
+		t.Fatalf("Failed to marshal headers response: %v", err)
+	}
+	syncer.HandlePeerMessage("liar", "headers_response", data)
+	if validated := syncer.GetHeadersValidated(); validated != 0 {
+		t.Fatalf("Bogus headers should be rejected, %d validated", validated)
+	}
+
+	if err := source.Start(); err != nil {
+		t.Fatalf("Failed to start source node: %v", err)
+	}
+	if err := syncer.Start(); err != nil {
+		t.Fatalf("Failed to start syncer node: %v", err)
+	}
+
+	// Ao conectar, o nó valida os 50 headers e então baixa os corpos
+	deadline := time.Now().Add(15 * time.Second)
+	for syncer.GetChainHeight() < 50 && time.Now().Before(deadline) {
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	if height := syncer.GetChainHeight(); height != 50 {
+		t.Fatalf("Syncer should reach height 50, got %d", height)
+	}
+	if validated := syncer.GetHeadersValidated(); validated != 50 {
+		t.Errorf("Expected 50 validated headers, got %d", validated)
+	}
+	if last := syncer.GetChain().GetLastBlock(); last.Hash != blocks[49].Hash {
+		t.Errorf("Syncer tip should be %s, got %s", blocks[49].Hash, last.Hash)
+	}
+
+	t.Logf("✓ 50 headers validated before downloading bodies")
+}
+
+// TestForceSyncFromPeer testa que o sync forçado com um peer à frente alcança a altura dele
+// sem esperar um novo anúncio de bloco
+func TestForceSyncFromPeer(t *testing.T) {
+	signalingPort := getRandomPort()
+	signalingURL := fmt.Sprintf("ws://localhost:%d/ws", signalingPort)
+	tempDir := getTempDataDir(t, "force-sync")
+
+	server := signaling.NewServer()
+	go func() {
+		if err := server.Start(fmt.Sprintf(":%d", signalingPort)); err != nil {
+			t.Logf("Signaling server error: %v", err)
+		}
+	}()
+	defer func() {
+		if err := server.Stop(); err != nil {
+			t.Logf("Warning: error stopping signaling server: %v", err)
+		}
+	}()
+	time.Sleep(100 * time.Millisecond)
+
+	sourceConfig := createTestNodeConfig(t, "force-source", signalingURL, tempDir)
+	laggingConfig := createTestNodeConfigWithSharedGenesis(t, "force-lagging", signalingURL, tempDir, sourceConfig.GenesisBlock)
+
+	source, err := node.NewNode(sourceConfig)
+	if err != nil {
+		t.Fatalf("Failed to create source node: %v", err)
+	}
+	defer stopNode(source, t)
+
+	lagging, err := node.NewNode(laggingConfig)
+	if err != nil {
+		t.Fatalf("Failed to create lagging node: %v", err)
+	}
+	defer stopNode(lagging, t)
+
+	if err := lagging.ForceSyncFrom("unknown-peer"); err == nil {
+		t.Error("Force sync from an unknown peer should fail")
+	}
+
+	if err := source.Start(); err != nil {
+		t.Fatalf("Failed to start source node: %v", err)
+	}
+	if err := lagging.Start(); err != nil {
+		t.Fatalf("Failed to start lagging node: %v", err)
+	}
+
+	// Aguarda a conexão dos dois lados (e o sync inicial, sem blocos novos)
+	connected := func(n *node.Node) bool {
+		peers := n.GetPeers()
+		return len(peers) == 1 && peers[0].IsReady()
+	}
+	deadline := time.Now().Add(15 * time.Second)
+	for !(connected(source) && connected(lagging)) && time.Now().Before(deadline) {
+		time.Sleep(100 * time.Millisecond)
+	}
+	if !connected(source) || !connected(lagging) {
+		t.Fatal("Nodes did not connect")
+	}
+	time.Sleep(time.Second)
+
+	// Blocos carregados por uma resposta de sync não são anunciados: o nó atrasado fica para trás
+	blocks := createSignedBlocks(t, sourceConfig.GenesisBlock, createTestWallet(t), 30)
+	data, err := json.Marshal(node.SyncResponse{Blocks: blocks})
+	if err != nil {
+		t.Fatalf("Failed to marshal sync response: %v", err)
+	}
+	source.HandlePeerMessage("loader", "sync_response", data)
+	if height := source.GetChainHeight(); height != 30 {
+		t.Fatalf("Source node should have 30 blocks, got %d", height)
+	}
+	time.Sleep(500 * time.Millisecond)
+	if height := lagging.GetChainHeight(); height != 0 {
+		t.Fatalf("Lagging node should still be at genesis, got height %d", height)
+	}
+
+	if err := lagging.ForceSyncFrom(source.GetID()); err != nil {
+		t.Fatalf("Force sync failed: %v", err)
+	}
+
+	deadline = time.Now().Add(10 * time.Second)
+	for lagging.GetChainHeight() < 30 && time.Now().Before(deadline) {
+		time.Sleep(100 * time.Millisecond)
+	}
+	if height := lagging.GetChainHeight(); height != 30 {
+		t.Fatalf("Lagging node should catch up to height 30, got %d", height)
+	}
+	if last := lagging.GetChain().GetLastBlock(); last.Hash != blocks[29].Hash {
+		t.Errorf("Lagging node tip should be %s, got %s", blocks[29].Hash, last.Hash)
+	}
+
+	t.Logf("✓ Forced sync caught up with the peer")
+}
+
+// TestParallelBlockDownload testa que blocos que faltam são baixados em trechos de vários
+// peers ao mesmo tempo, reordenados antes de entrar na chain, e que trechos entregues pela
+// metade ou não entregues no prazo são pedidos a outro peer
+func TestParallelBlockDownload(t *testing.T) {
+	tempDir := getTempDataDir(t, "paralleldownload")
+
+	type syncRequest struct {
+		peerID string
+		req    node.SyncRequest
+	}
+	requests := make(chan syncRequest, 1000)
+
+	nodeConfig := createTestNodeConfig(t, "download-node", "ws://localhost:1/ws", tempDir)
+	nodeConfig.DisableRateLimit = true
+	nodeConfig.DownloadTimeout = 200 * time.Millisecond
+	nodeConfig.SendSyncRequest = func(peerID string, req node.SyncRequest) error {
+		requests <- syncRequest{peerID: peerID, req: req}
+		return nil
+	}
+
+	n, err := node.NewNode(nodeConfig)
+	if err != nil {
+		t.Fatalf("Failed to create node: %v", err)
+	}
+	defer stopNode(n, t)
+
+	const tip = 300
+	blocks := createSignedBlocks(t, nodeConfig.GenesisBlock, createTestWallet(t), tip)
+
+	// Cada peer mock tem só uma faixa de alturas; peer-0-silent (primeiro na ordem dos peers,
+	// recebe o primeiro trecho) nunca responde
+	owned := map[string][2]uint64{
+		"peer-a": {1, 100},
+		"peer-b": {101, 200},
+		"peer-c": {201, 300},
+	}
+	delays := map[string]time.Duration{
+		"peer-a": 40 * time.Millisecond, // Responde por último: seus blocos seguram a reordenação
+		"peer-b": 20 * time.Millisecond,
+		"peer-c": 0,
+	}
+	for _, id := range []string{"peer-a", "peer-b", "peer-c", "peer-0-silent"} {
+		n.AddPeer(network.NewPeer(id, nil))
+	}
+
+	var servedMutex sync.Mutex
+	served := make(map[string]int)
+	serve := func(r syncRequest) {
+		rng, ok := owned[r.peerID]
+		if !ok {
+			return
+		}
+		time.Sleep(delays[r.peerID])
+
+		resp := node.SyncResponse{Blocks: []*blockchain.Block{}, TipHeight: tip}
+		for h := r.req.FromHeight; h <= r.req.ToHeight && h >= rng[0] && h <= rng[1]; h++ {
+			resp.Blocks = append(resp.Blocks, blocks[h-1])
+		}
+		servedMutex.Lock()
+		served[r.peerID] += len(resp.Blocks)
+		servedMutex.Unlock()
+
+		data, err := json.Marshal(resp)
+		if err != nil {
+			t.Errorf("Failed to marshal sync response: %v", err)
+			return
+		}
+		n.HandlePeerMessage(r.peerID, "sync_response", data)
+	}
+
+	// Primeira resposta de peer-a revela que a chain vai até tip
+	data, err := json.Marshal(node.SyncResponse{Blocks: blocks[:10], HasMore: true, TipHeight: tip})
+	if err != nil {
+		t.Fatalf("Failed to marshal sync response: %v", err)
+	}
+	n.HandlePeerMessage("peer-a", "sync_response", data)
+
+	requestedFrom := make(map[string]bool)
+	deadline := time.After(10 * time.Second)
+	for n.GetChainHeight() < tip {
+		select {
+		case r := <-requests:
+			if r.req.ToHeight == 0 {
+				t.Errorf("Parallel download should request bounded ranges, got open request from %d", r.req.FromHeight)
+			}
+			requestedFrom[r.peerID] = true
+			go serve(r)
+		case <-deadline:
+			t.Fatalf("Download did not complete, height %d", n.GetChainHeight())
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+
+	for i, block := range blocks {
+		got, exists := n.GetBlockByHeight(uint64(i + 1))
+		if !exists || got.Hash != block.Hash {
+			t.Fatalf("Block %d differs from the served chain", i+1)
+		}
+	}
+
+	servedMutex.Lock()
+	defer servedMutex.Unlock()
+	for peerID := range owned {
+		if served[peerID] == 0 {
+			t.Errorf("Expected %s to serve blocks, served %v", peerID, served)
+		}
+	}
+	if !requestedFrom["peer-0-silent"] {
+		t.Error("Silent peer should have been assigned a range (and timed out)")
+	}
+
+	t.Logf("✓ Downloaded %d blocks from %v", tip, served)
+}
+
+// TestCompressedSyncWithMixedPeers testa que um nó com compressão sincroniza comprimido com
+// outro que também comprime e continua servindo JSON puro a um peer sem compressão
+func TestCompressedSyncWithMixedPeers(t *testing.T) {
+	signalingPort := getRandomPort()
+	signalingURL := fmt.Sprintf("ws://localhost:%d/ws", signalingPort)
+	tempDir := getTempDataDir(t, "compression")
+
+	server := signaling.NewServer()
+	go func() {
+		if err := server.Start(fmt.Sprintf(":%d", signalingPort)); err != nil {
+			t.Logf("Signaling server error: %v", err)
+		}
+	}()
+	defer func() {
+		if err := server.Stop(); err != nil {
+			t.Logf("Warning: error stopping signaling server: %v", err)
+		}
+	}()
+	time.Sleep(100 * time.Millisecond)
+
+	sourceConfig := createTestNodeConfig(t, "compress-source", signalingURL, tempDir)
+	sourceConfig.CompressMessages = true
+	modernConfig := createTestNodeConfigWithSharedGenesis(t, "compress-modern", signalingURL, tempDir, sourceConfig.GenesisBlock)
+	modernConfig.CompressMessages = true
+	legacyConfig := createTestNodeConfigWithSharedGenesis(t, "compress-legacy", signalingURL, tempDir, sourceConfig.GenesisBlock)
+
+	source, err := node.NewNode(sourceConfig)
+	if err != nil {
+		t.Fatalf("Failed to create source node: %v", err)
+	}
+	defer stopNode(source, t)
+
+	modern, err := node.NewNode(modernConfig)
+	if err != nil {
+		t.Fatalf("Failed to create modern node: %v", err)
+	}
+	defer stopNode(modern, t)
+
+	legacy, err := node.NewNode(legacyConfig)
+	if err != nil {
+		t.Fatalf("Failed to create legacy node: %v", err)
+	}
+	defer stopNode(legacy, t)
+
+	// Nó de origem com 50 blocos, carregados por uma resposta de sync comprimida
+	blocks := createSignedBlocks(t, sourceConfig.GenesisBlock, createTestWallet(t), 50)
+	data, err := json.Marshal(node.SyncResponse{Blocks: blocks})
+	if err != nil {
+		t.Fatalf("Failed to marshal sync response: %v", err)
+	}
+	compressed, err := blockchain.CompressPayload(data)
+	if err != nil {
+		t.Fatalf("Failed to compress sync response: %v", err)
+	}
+	if len(compressed) >= len(data) {
+		t.Errorf("Compressed sync response (%d bytes) should be smaller than raw (%d bytes)", len(compressed), len(data))
+	}
+	source.HandlePeerMessage("loader", "sync_response", compressed)
+	if height := source.GetChainHeight(); height != 50 {
+		t.Fatalf("Source node should accept compressed blocks, got height %d", height)
+	}
+
+	// Os nós entram um de cada vez (conexões simultâneas entre os três competem no handshake)
+	waitHeight := func(n *node.Node, height uint64) {
+		deadline := time.Now().Add(15 * time.Second)
+		for n.GetChainHeight() < height && time.Now().Before(deadline) {
+			time.Sleep(100 * time.Millisecond)
+		}
+	}
+	if err := source.Start(); err != nil {
+		t.Fatalf("Failed to start source node: %v", err)
+	}
+	if err := modern.Start(); err != nil {
+		t.Fatalf("Failed to start modern node: %v", err)
+	}
+	waitHeight(modern, 50)
+	if height := modern.GetChainHeight(); height != 50 {
+		t.Fatalf("Compressing peer should reach height 50, got %d", height)
+	}
+
+	if err := legacy.Start(); err != nil {
+		t.Fatalf("Failed to start legacy node: %v", err)
+	}
+	waitHeight(legacy, 50)
+	if height := legacy.GetChainHeight(); height != 50 {
+		t.Fatalf("Peer without compression should reach height 50, got %d", height)
+	}
+
+	// Só os nós com compressão a negociaram entre si
+	if !source.PeerSupportsCompression(modern.ID) || !modern.PeerSupportsCompression(source.ID) {
+		t.Error("Source and modern nodes should negotiate compression")
+	}
+	if source.PeerSupportsCompression(legacy.ID) || modern.PeerSupportsCompression(legacy.ID) {
+		t.Error("Legacy node should not be sent compressed payloads")
+	}
+
+	t.Logf("✓ Compressed and uncompressed peers synced from the same node")
+}
+
+// TestNodeICEServersFromConfig testa que os servidores STUN/TURN de node.Config chegam à
+// configuração das conexões WebRTC (sem estabelecer conexões)
+func TestNodeICEServersFromConfig(t *testing.T) {
+	tempDir := getTempDataDir(t, "iceservers")
+	nodeConfig := createTestNodeConfig(t, "ice-node", "ws://localhost:0/ws", tempDir)
+	nodeConfig.ICEServers = []network.ICEServer{
+		{URLs: []string{"turn:turn.example.com:3478"}, Username: "krakovia", Credential: "secret"},
+	}
+
+	n, err := node.NewNode(nodeConfig)
+	if err != nil {
+		t.Fatalf("Failed to create node: %v", err)
+	}
+	defer stopNode(n, t)
+
+	servers := n.GetWebRTC().ICEConfiguration().ICEServers
+	if len(servers) != 1 || servers[0].URLs[0] != "turn:turn.example.com:3478" ||
+		servers[0].Username != "krakovia" || servers[0].Credential != "secret" {
+		t.Errorf("Node should use the configured TURN server, got %+v", servers)
+	}
+}
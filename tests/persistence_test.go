@@ -1,7 +1,10 @@
 package tests
 
 import (
+	"fmt"
 	"os"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -10,6 +13,7 @@ import (
 	"github.com/krakovia/blockchain/pkg/node"
 	"github.com/krakovia/blockchain/pkg/wallet"
 	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/util"
 )
 
 // TestBlockchainPersistence testa se a blockchain é persistida e carregada corretamente
@@ -33,7 +37,7 @@ func TestBlockchainPersistence(t *testing.T) {
 	// Configurações da chain
 	chainConfig := blockchain.ChainConfig{
 		BlockTime:         200 * time.Millisecond,
-		MaxBlockSize:      1000,
+		Consensus:         blockchain.ConsensusParams{MaxBlockTxs: 1000},
 		BlockReward:       50,
 		MinValidatorStake: 100,
 	}
@@ -50,7 +54,6 @@ func TestBlockchainPersistence(t *testing.T) {
 
 	// Criar primeiro node
 	nodeConfig1 := node.Config{
-		ID:               "test-node-1",
 		Address:          ":9999",
 		DBPath:           dbPath,
 		SignalingServer:  "ws://localhost:9000/ws",
@@ -154,7 +157,6 @@ func TestBlockchainPersistence(t *testing.T) {
 	// Criar segundo node com mesma configuração (simular reinicialização)
 	t.Log("Creating second node (simulating restart)...")
 	nodeConfig2 := node.Config{
-		ID:               "test-node-2",
 		Address:          ":9998",
 		DBPath:           dbPath,
 		SignalingServer:  "ws://localhost:9000/ws",
@@ -252,7 +254,7 @@ func TestBlockchainPersistenceWithMultipleRestarts(t *testing.T) {
 
 	chainConfig := blockchain.ChainConfig{
 		BlockTime:         200 * time.Millisecond,
-		MaxBlockSize:      1000,
+		Consensus:         blockchain.ConsensusParams{MaxBlockTxs: 1000},
 		BlockReward:       50,
 		MinValidatorStake: 100,
 	}
@@ -275,7 +277,6 @@ func TestBlockchainPersistenceWithMultipleRestarts(t *testing.T) {
 		t.Logf("=== Restart %d/%d ===", restart+1, restarts)
 
 		nodeConfig := node.Config{
-			ID:               "test-node",
 			Address:          ":9997",
 			DBPath:           dbPath,
 			SignalingServer:  "ws://localhost:9000/ws",
@@ -358,7 +359,6 @@ func TestEmptyDatabaseLoad(t *testing.T) {
 	chainConfig := blockchain.DefaultChainConfig()
 
 	nodeConfig := node.Config{
-		ID:              "test-node",
 		Address:         ":9996",
 		DBPath:          dbPath,
 		SignalingServer: "ws://localhost:9000/ws",
@@ -383,3 +383,476 @@ func TestEmptyDatabaseLoad(t *testing.T) {
 
 	t.Log("✓ Empty database load test passed!")
 }
+
+// failingBlockStore é um BlockStore que sempre falha e conta as tentativas
+type failingBlockStore struct {
+	mu       sync.Mutex
+	attempts int
+}
+
+func (s *failingBlockStore) SaveBlock(block *blockchain.Block) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.attempts++
+	return fmt.Errorf("simulated disk failure")
+}
+
+func (s *failingBlockStore) getAttempts() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.attempts
+}
+
+// TestBlockSaveRetryHaltsMining verifica que falhas ao salvar blocos são
+// repetidas com backoff e que a mineração é interrompida após esgotar as tentativas
+func TestBlockSaveRetryHaltsMining(t *testing.T) {
+	tempDir := getTempDataDir(t, "save-retry")
+
+	nodeConfig := createTestNodeConfig(t, "retry-node", "ws://localhost:9000/ws", tempDir)
+	nodeConfig.InitialStakeAddr = nodeConfig.Wallet.GetAddress()
+	nodeConfig.InitialStake = 1000
+	nodeConfig.BlockSaveRetries = 2
+	nodeConfig.BlockSaveBackoff = 5 * time.Millisecond
+
+	testNode, err := node.NewNode(nodeConfig)
+	if err != nil {
+		t.Fatalf("Failed to create node: %v", err)
+	}
+	defer stopNode(testNode, t)
+
+	store := &failingBlockStore{}
+	testNode.SetBlockStore(store)
+
+	if err := testNode.StartMining(); err != nil {
+		t.Fatalf("Failed to start mining: %v", err)
+	}
+
+	// Aguardar a mineração ser interrompida
+	deadline := time.Now().Add(5 * time.Second)
+	for testNode.IsMining() && time.Now().Before(deadline) {
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	if testNode.IsMining() {
+		t.Fatal("Expected mining to halt after block save retries were exhausted")
+	}
+
+	// 1 tentativa inicial + 2 retries
+	if attempts := store.getAttempts(); attempts != 3 {
+		t.Errorf("Expected 3 save attempts, got %d", attempts)
+	}
+
+	if testNode.GetLastBlockSaveError() == nil {
+		t.Error("Expected last block save error to be recorded")
+	}
+
+	t.Log("✓ Block save retry test passed!")
+}
+
+// TestBlockSaveRetriesDisabled verifica que BlockSaveRetries negativo desativa as novas tentativas
+func TestBlockSaveRetriesDisabled(t *testing.T) {
+	tempDir := getTempDataDir(t, "save-no-retry")
+
+	nodeConfig := createTestNodeConfig(t, "no-retry-node", "ws://localhost:9000/ws", tempDir)
+	nodeConfig.InitialStakeAddr = nodeConfig.Wallet.GetAddress()
+	nodeConfig.InitialStake = 1000
+	nodeConfig.BlockSaveRetries = -1
+	nodeConfig.BlockSaveBackoff = 5 * time.Millisecond
+
+	testNode, err := node.NewNode(nodeConfig)
+	if err != nil {
+		t.Fatalf("Failed to create node: %v", err)
+	}
+	defer stopNode(testNode, t)
+
+	store := &failingBlockStore{}
+	testNode.SetBlockStore(store)
+
+	if err := testNode.StartMining(); err != nil {
+		t.Fatalf("Failed to start mining: %v", err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for testNode.IsMining() && time.Now().Before(deadline) {
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	if testNode.IsMining() {
+		t.Fatal("Expected mining to halt after the first failed save")
+	}
+	if attempts := store.getAttempts(); attempts != 1 {
+		t.Errorf("Expected a single save attempt, got %d", attempts)
+	}
+}
+
+// slowBlockLoader simula um disco lento: os primeiros blocos demoram e os
+// seguintes nunca retornam (até o teste liberar)
+type slowBlockLoader struct {
+	db        *leveldb.DB
+	fastUntil uint64
+	delay     time.Duration
+	release   chan struct{}
+}
+
+func (l *slowBlockLoader) LoadBlock(height uint64) (*blockchain.Block, error) {
+	if height > l.fastUntil {
+		<-l.release
+	}
+	time.Sleep(l.delay)
+	return blockchain.LoadBlockFromDB(l.db, height)
+}
+
+// TestSyncResponseAssemblyDeadline verifica que a montagem da resposta de sync
+// para no SyncAssemblyTimeout e devolve os blocos já coletados
+func TestSyncResponseAssemblyDeadline(t *testing.T) {
+	tempDir := getTempDataDir(t, "sync-deadline")
+
+	nodeConfig := createTestNodeConfig(t, "sync-deadline-node", "ws://localhost:9000/ws", tempDir)
+	nodeConfig.InitialStakeAddr = nodeConfig.Wallet.GetAddress()
+	nodeConfig.InitialStake = 1000
+	nodeConfig.SyncAssemblyTimeout = 300 * time.Millisecond
+
+	testNode, err := node.NewNode(nodeConfig)
+	if err != nil {
+		t.Fatalf("Failed to create node: %v", err)
+	}
+	defer stopNode(testNode, t)
+
+	if err := testNode.StartMining(); err != nil {
+		t.Fatalf("Failed to start mining: %v", err)
+	}
+	deadline := time.Now().Add(10 * time.Second)
+	for testNode.GetChainHeight() < 5 && time.Now().Before(deadline) {
+		time.Sleep(50 * time.Millisecond)
+	}
+	testNode.StopMining()
+	time.Sleep(300 * time.Millisecond)
+
+	height := testNode.GetChainHeight()
+	if height < 5 {
+		t.Fatalf("Expected at least 5 blocks, got %d", height)
+	}
+
+	// Remove os blocos antigos da memória para forçar a leitura do disco
+	if err := blockchain.PruneOldBlocks(testNode.GetDB(), testNode.GetChain().GetAllBlocksPointer(), 1); err != nil {
+		t.Fatalf("Failed to prune blocks: %v", err)
+	}
+
+	loader := &slowBlockLoader{
+		db:        testNode.GetDB(),
+		fastUntil: 2,
+		delay:     20 * time.Millisecond,
+		release:   make(chan struct{}),
+	}
+	defer close(loader.release)
+	testNode.SetBlockLoader(loader)
+
+	start := time.Now()
+	response := testNode.BuildSyncResponse(1)
+	elapsed := time.Since(start)
+
+	if elapsed > time.Second {
+		t.Fatalf("Sync response assembly took %v, expected to stop near the 300ms deadline", elapsed)
+	}
+	if len(response.Blocks) != 2 {
+		t.Fatalf("Expected partial response with 2 blocks, got %d", len(response.Blocks))
+	}
+	for i, block := range response.Blocks {
+		if block.Header.Height != uint64(i+1) {
+			t.Errorf("Expected block %d at position %d, got height %d", i+1, i, block.Header.Height)
+		}
+	}
+	if !response.HasMore {
+		t.Error("Partial response should signal that more blocks are available")
+	}
+
+	t.Logf("✓ Partial sync response with %d blocks assembled in %v", len(response.Blocks), elapsed)
+}
+
+// TestFindTransactionByID verifica que uma transação minerada pode ser localizada pelo ID,
+// inclusive depois que seu bloco sai da memória e após um restart com índice reconstruído
+func TestFindTransactionByID(t *testing.T) {
+	tempDir := getTempDataDir(t, "tx-lookup")
+
+	nodeConfig := createTestNodeConfig(t, "tx-lookup-node", "ws://localhost:9000/ws", tempDir)
+	nodeConfig.InitialStakeAddr = nodeConfig.Wallet.GetAddress()
+	nodeConfig.InitialStake = 1000
+
+	testNode, err := node.NewNode(nodeConfig)
+	if err != nil {
+		t.Fatalf("Failed to create node: %v", err)
+	}
+
+	recipient, err := wallet.NewWallet()
+	if err != nil {
+		t.Fatalf("Failed to create recipient wallet: %v", err)
+	}
+	tx, err := testNode.CreateTransaction(recipient.GetAddress(), 500, 5, "lookup me")
+	if err != nil {
+		t.Fatalf("Failed to create transaction: %v", err)
+	}
+
+	if err := testNode.StartMining(); err != nil {
+		t.Fatalf("Failed to start mining: %v", err)
+	}
+
+	var minedHeight uint64
+	deadline := time.Now().Add(10 * time.Second)
+	for time.Now().Before(deadline) {
+		if _, height, found := testNode.GetChain().FindTransaction(tx.ID); found {
+			minedHeight = height
+			if testNode.GetChainHeight() >= height+3 {
+				break
+			}
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	testNode.StopMining()
+	time.Sleep(300 * time.Millisecond)
+
+	if minedHeight == 0 {
+		t.Fatal("Transaction was not mined")
+	}
+
+	found, height, ok := testNode.GetChain().FindTransaction(tx.ID)
+	if !ok || height != minedHeight || found.To != recipient.GetAddress() || found.Amount != 500 {
+		t.Fatalf("Unexpected lookup result: ok=%v height=%d tx=%+v", ok, height, found)
+	}
+	if confirmations := testNode.GetChainHeight() - height; confirmations < 3 {
+		t.Errorf("Expected at least 3 confirmations, got %d", confirmations)
+	}
+
+	// Sem o bloco em memória, a busca usa o índice do disco
+	if err := blockchain.PruneOldBlocks(testNode.GetDB(), testNode.GetChain().GetAllBlocksPointer(), 1); err != nil {
+		t.Fatalf("Failed to prune blocks: %v", err)
+	}
+	if _, height, ok := testNode.GetChain().FindTransaction(tx.ID); !ok || height != minedHeight {
+		t.Fatalf("Expected indexed lookup at height %d, got ok=%v height=%d", minedHeight, ok, height)
+	}
+	if _, _, ok := testNode.GetChain().FindTransaction("does-not-exist"); ok {
+		t.Error("Lookup of unknown transaction should fail")
+	}
+
+	stopNode(testNode, t)
+
+	// Simular um banco anterior ao índice: remover as entradas tx- e o marcador
+	db, err := leveldb.OpenFile(nodeConfig.DBPath, nil)
+	if err != nil {
+		t.Fatalf("Failed to open DB: %v", err)
+	}
+	iter := db.NewIterator(util.BytesPrefix([]byte("tx-")), nil)
+	for iter.Next() {
+		if err := db.Delete(iter.Key(), nil); err != nil {
+			t.Fatalf("Failed to delete index entry: %v", err)
+		}
+	}
+	iter.Release()
+	if err := db.Delete([]byte("metadata-tx-index"), nil); err != nil {
+		t.Fatalf("Failed to delete index marker: %v", err)
+	}
+	if err := db.Close(); err != nil {
+		t.Fatalf("Failed to close DB: %v", err)
+	}
+
+	restarted, err := node.NewNode(nodeConfig)
+	if err != nil {
+		t.Fatalf("Failed to restart node: %v", err)
+	}
+	defer stopNode(restarted, t)
+
+	if err := blockchain.PruneOldBlocks(restarted.GetDB(), restarted.GetChain().GetAllBlocksPointer(), 1); err != nil {
+		t.Fatalf("Failed to prune blocks: %v", err)
+	}
+	if _, height, ok := restarted.GetChain().FindTransaction(tx.ID); !ok || height != minedHeight {
+		t.Fatalf("Expected backfilled lookup at height %d, got ok=%v height=%d", minedHeight, ok, height)
+	}
+
+	t.Logf("✓ Transaction %s found at height %d", tx.ID[:16], minedHeight)
+}
+
+// countingCompactor envolve o compactador real contando as chamadas
+type countingCompactor struct {
+	inner node.DBCompactor
+	mu    sync.Mutex
+	calls int
+}
+
+func (c *countingCompactor) CompactRange(r util.Range) error {
+	c.mu.Lock()
+	c.calls++
+	c.mu.Unlock()
+	return c.inner.CompactRange(r)
+}
+
+func (c *countingCompactor) Calls() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.calls
+}
+
+// TestCompactOnStartup verifica que a compactação roda na inicialização quando habilitada,
+// respeita o intervalo mínimo entre execuções e não impede a leitura dos blocos
+func TestCompactOnStartup(t *testing.T) {
+	tempDir := getTempDataDir(t, "compaction")
+
+	nodeConfig := createTestNodeConfig(t, "compaction-node", "ws://localhost:9000/ws", tempDir)
+	nodeConfig.InitialStakeAddr = nodeConfig.Wallet.GetAddress()
+	nodeConfig.InitialStake = 1000
+
+	// Gerar alguns blocos no disco
+	testNode, err := node.NewNode(nodeConfig)
+	if err != nil {
+		t.Fatalf("Failed to create node: %v", err)
+	}
+	if err := testNode.StartMining(); err != nil {
+		t.Fatalf("Failed to start mining: %v", err)
+	}
+	deadline := time.Now().Add(10 * time.Second)
+	for testNode.GetChainHeight() < 3 && time.Now().Before(deadline) {
+		time.Sleep(50 * time.Millisecond)
+	}
+	testNode.StopMining()
+	time.Sleep(300 * time.Millisecond)
+	savedHeight := testNode.GetChainHeight()
+	if savedHeight < 3 {
+		t.Fatalf("Expected at least 3 blocks, got %d", savedHeight)
+	}
+	stopNode(testNode, t)
+
+	compactor := &countingCompactor{}
+	nodeConfig.CompactOnStartup = true
+	nodeConfig.CompactInterval = time.Hour
+	nodeConfig.WrapCompactor = func(inner node.DBCompactor) node.DBCompactor {
+		compactor.inner = inner
+		return compactor
+	}
+
+	// Primeira inicialização com a opção habilitada compacta
+	compacted, err := node.NewNode(nodeConfig)
+	if err != nil {
+		t.Fatalf("Failed to restart node: %v", err)
+	}
+	if compactor.Calls() != 1 {
+		t.Fatalf("Expected compaction to run once, got %d calls", compactor.Calls())
+	}
+	if compacted.GetChainHeight() != savedHeight {
+		t.Fatalf("Expected height %d after compaction, got %d", savedHeight, compacted.GetChainHeight())
+	}
+	for h := uint64(1); h <= savedHeight; h++ {
+		if _, err := blockchain.LoadBlockFromDB(compacted.GetDB(), h); err != nil {
+			t.Errorf("Failed to read block %d after compaction: %v", h, err)
+		}
+	}
+	stopNode(compacted, t)
+
+	// Dentro do intervalo, a compactação não roda de novo
+	again, err := node.NewNode(nodeConfig)
+	if err != nil {
+		t.Fatalf("Failed to restart node: %v", err)
+	}
+	defer stopNode(again, t)
+	if compactor.Calls() != 1 {
+		t.Errorf("Expected compaction to be skipped within interval, got %d calls", compactor.Calls())
+	}
+	if block, exists := again.GetBlockByHeight(savedHeight); !exists || block.Header.Height != savedHeight {
+		t.Errorf("Expected to read block %d after restart", savedHeight)
+	}
+
+	t.Logf("✓ Compaction ran once and %d blocks remained readable", savedHeight)
+}
+
+// TestGenesisHashVerification verifica que o nó recusa iniciar quando o gênesis recriado não
+// bate com o hash configurado, antes de abrir o banco
+func TestGenesisHashVerification(t *testing.T) {
+	tempDir := getTempDataDir(t, "genesis-hash")
+
+	nodeConfig := createTestNodeConfig(t, "genesis-hash-node", "ws://localhost:9000/ws", tempDir)
+
+	// Mesmas alocações com outro timestamp: o hash configurado deixa de corresponder
+	other, err := blockchain.GenesisBlockWithAllocations(
+		[]blockchain.GenesisAllocation{{Address: nodeConfig.Wallet.GetAddress(), Amount: 1000000000}},
+		nodeConfig.GenesisBlock.Header.Timestamp+1,
+	)
+	if err != nil {
+		t.Fatalf("Failed to create genesis: %v", err)
+	}
+	nodeConfig.GenesisHash = other.Hash
+
+	if testNode, err := node.NewNode(nodeConfig); err == nil {
+		stopNode(testNode, t)
+		t.Fatal("Expected startup to fail with mismatched genesis hash")
+	} else if !strings.Contains(err.Error(), "genesis hash mismatch") {
+		t.Fatalf("Expected genesis hash mismatch error, got: %v", err)
+	}
+	if _, err := os.Stat(nodeConfig.DBPath); !os.IsNotExist(err) {
+		t.Errorf("Database should not be created when genesis hash mismatches")
+	}
+
+	// Com o hash correto o nó inicia normalmente
+	nodeConfig.GenesisHash = nodeConfig.GenesisBlock.Hash
+	testNode, err := node.NewNode(nodeConfig)
+	if err != nil {
+		t.Fatalf("Failed to create node with matching genesis hash: %v", err)
+	}
+	defer stopNode(testNode, t)
+
+	t.Log("✓ Node refused mismatched genesis hash and started with the correct one")
+}
+
+// TestRestartReloadsExactHeight verifica que, com pruning de memória ativo, o nó reinicia na
+// mesma altura e ponta em que parou: gravar blocos podados não pode rebaixar a altura salva
+func TestRestartReloadsExactHeight(t *testing.T) {
+	tempDir := getTempDataDir(t, "exact-height")
+
+	nodeConfig := createTestNodeConfig(t, "exact-height-node", "ws://localhost:9000/ws", tempDir)
+	nodeConfig.ChainConfig.BlockTime = 100 * time.Millisecond
+	nodeConfig.InitialStakeAddr = nodeConfig.Wallet.GetAddress()
+	nodeConfig.InitialStake = 1000
+	nodeConfig.PruneConfig = &config.PruneConfig{KeepInMemory: 2}
+
+	testNode, err := node.NewNode(nodeConfig)
+	if err != nil {
+		t.Fatalf("Failed to create node: %v", err)
+	}
+	if err := testNode.StartMining(); err != nil {
+		t.Fatalf("Failed to start mining: %v", err)
+	}
+	deadline := time.Now().Add(10 * time.Second)
+	for testNode.GetChainHeight() < 6 && time.Now().Before(deadline) {
+		time.Sleep(50 * time.Millisecond)
+	}
+	testNode.StopMining()
+	time.Sleep(300 * time.Millisecond)
+
+	stoppedHeight := testNode.GetChainHeight()
+	stoppedTip := testNode.GetChain().GetLastBlock().Hash
+	if stoppedHeight < 6 {
+		t.Fatalf("Expected at least 6 blocks, got %d", stoppedHeight)
+	}
+	if inMemory := len(testNode.GetChain().GetAllBlocks()); inMemory > 3 {
+		t.Fatalf("Expected pruned chain in memory, got %d blocks", inMemory)
+	}
+	stopNode(testNode, t)
+
+	restarted, err := node.NewNode(nodeConfig)
+	if err != nil {
+		t.Fatalf("Failed to restart node: %v", err)
+	}
+	defer stopNode(restarted, t)
+
+	if height := restarted.GetChainHeight(); height != stoppedHeight {
+		t.Fatalf("Expected reloaded height %d, got %d", stoppedHeight, height)
+	}
+	if tip := restarted.GetChain().GetLastBlock().Hash; tip != stoppedTip {
+		t.Errorf("Expected reloaded tip %s, got %s", stoppedTip, tip)
+	}
+	savedHeight, savedTip, err := blockchain.LoadChainTip(restarted.GetDB())
+	if err != nil {
+		t.Fatalf("Failed to load saved chain tip: %v", err)
+	}
+	if savedHeight != stoppedHeight || savedTip != stoppedTip {
+		t.Errorf("Expected saved tip %d/%s, got %d/%s", stoppedHeight, stoppedTip, savedHeight, savedTip)
+	}
+
+	t.Logf("✓ Node restarted at height %d with the same tip", stoppedHeight)
+}
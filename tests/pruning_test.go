@@ -0,0 +1,107 @@
+package tests
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/krakovia/blockchain/internal/config"
+	"github.com/krakovia/blockchain/pkg/blockchain"
+	"github.com/krakovia/blockchain/pkg/node"
+)
+
+// loadSyncedBlocks entrega os blocos ao nó como uma resposta de sync
+func loadSyncedBlocks(t *testing.T, n *node.Node, blocks []*blockchain.Block) {
+	t.Helper()
+	data, err := json.Marshal(node.SyncResponse{Blocks: blocks})
+	if err != nil {
+		t.Fatalf("Failed to marshal sync response: %v", err)
+	}
+	n.HandlePeerMessage("loader", "sync_response", data)
+	if height := n.GetChainHeight(); height != blocks[len(blocks)-1].Header.Height {
+		t.Fatalf("Expected height %d after sync, got %d", blocks[len(blocks)-1].Header.Height, height)
+	}
+}
+
+// TestPruneToDepthKeepsBlocksOnDisk testa que PruneToDepth reduz os blocos em memória sem
+// checkpoints e que as alturas removidas continuam sendo servidas pelo LevelDB
+func TestPruneToDepthKeepsBlocksOnDisk(t *testing.T) {
+	tempDir := getTempDataDir(t, "prune-depth")
+	nodeConfig := createTestNodeConfig(t, "prune-node", "ws://localhost:0/ws", tempDir)
+
+	n, err := node.NewNode(nodeConfig)
+	if err != nil {
+		t.Fatalf("Failed to create node: %v", err)
+	}
+	defer stopNode(n, t)
+
+	blocks := createSignedBlocks(t, nodeConfig.GenesisBlock, createTestWallet(t), 30)
+	loadSyncedBlocks(t, n, blocks)
+
+	// Sem PruneConfig nem checkpoints nada sai da memória sozinho
+	if inMemory := n.GetBlocksInMemory(); inMemory != 31 {
+		t.Fatalf("Expected all 31 blocks in memory before pruning, got %d", inMemory)
+	}
+
+	if err := n.PruneToDepth(5); err != nil {
+		t.Fatalf("PruneToDepth failed: %v", err)
+	}
+	if inMemory := n.GetBlocksInMemory(); inMemory != 5 {
+		t.Errorf("Expected 5 blocks in memory after pruning, got %d", inMemory)
+	}
+	if err := n.PruneToDepth(0); err == nil {
+		t.Error("Pruning to depth 0 should fail")
+	}
+
+	// Alturas podadas são lidas do disco
+	for _, height := range []uint64{1, 12, 25, 30} {
+		block, found := n.GetBlockByHeight(height)
+		if !found || block.Hash != blocks[height-1].Hash {
+			t.Errorf("Block %d should still be served after pruning (found: %v)", height, found)
+		}
+	}
+
+	// O sync continua podendo servir a chain inteira
+	served := n.GetBlockRange(1, 30)
+	if len(served) != 30 {
+		t.Fatalf("Expected 30 blocks for sync, got %d", len(served))
+	}
+	for i, block := range served {
+		if block.Hash != blocks[i].Hash {
+			t.Errorf("Sync block %d has hash %s, want %s", i+1, block.Hash, blocks[i].Hash)
+		}
+	}
+}
+
+// TestPruneConfigWithoutCheckpoints testa o pruning automático do PruneConfig: memória e disco
+// com profundidades próprias, sem checkpoints habilitados
+func TestPruneConfigWithoutCheckpoints(t *testing.T) {
+	tempDir := getTempDataDir(t, "prune-config")
+	nodeConfig := createTestNodeConfig(t, "light-node", "ws://localhost:0/ws", tempDir)
+	nodeConfig.PruneConfig = &config.PruneConfig{KeepInMemory: 10, KeepOnDisk: 20}
+
+	n, err := node.NewNode(nodeConfig)
+	if err != nil {
+		t.Fatalf("Failed to create node: %v", err)
+	}
+	defer stopNode(n, t)
+
+	blocks := createSignedBlocks(t, nodeConfig.GenesisBlock, createTestWallet(t), 40)
+	loadSyncedBlocks(t, n, blocks)
+
+	if inMemory := n.GetBlocksInMemory(); inMemory != 10 {
+		t.Errorf("Expected 10 blocks in memory, got %d", inMemory)
+	}
+
+	// Os últimos 20 blocos continuam disponíveis (10 só no disco)
+	for height := uint64(21); height <= 40; height++ {
+		if block, found := n.GetBlockByHeight(height); !found || block.Hash != blocks[height-1].Hash {
+			t.Errorf("Block %d should be kept (found: %v)", height, found)
+		}
+	}
+	// Os mais antigos saíram do disco
+	for _, height := range []uint64{1, 10, 20} {
+		if _, found := n.GetBlockByHeight(height); found {
+			t.Errorf("Block %d should have been pruned from disk", height)
+		}
+	}
+}
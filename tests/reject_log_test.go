@@ -0,0 +1,156 @@
+package tests
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/krakovia/blockchain/pkg/blockchain"
+	"github.com/krakovia/blockchain/pkg/node"
+)
+
+// readRejectLog lê as entradas de um arquivo do log de rejeições
+func readRejectLog(t *testing.T, path string) []node.RejectLogEntry {
+	t.Helper()
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Failed to open reject log: %v", err)
+	}
+	defer file.Close()
+
+	var entries []node.RejectLogEntry
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var entry node.RejectLogEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatalf("Invalid reject log line %q: %v", scanner.Text(), err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatalf("Failed to read reject log: %v", err)
+	}
+	return entries
+}
+
+// TestRejectedTransactionIsLogged testa que uma transação rejeitada pelo mempool gera uma
+// entrada no log de rejeições com o motivo da rejeição
+func TestRejectedTransactionIsLogged(t *testing.T) {
+	tempDir := getTempDataDir(t, "rejectlog")
+	logPath := filepath.Join(tempDir, "rejects.jsonl")
+
+	sender := createTestWallet(t)
+	nodeConfig := createTestNodeConfig(t, "rejectlog-node", "ws://localhost:1/ws", tempDir)
+	nodeConfig.RejectLogPath = logPath
+	nodeConfig.SenderDenylist = []string{sender.GetAddress()}
+	nodeConfig.FilterMempool = true
+
+	n, err := node.NewNode(nodeConfig)
+	if err != nil {
+		t.Fatalf("Failed to create node: %v", err)
+	}
+	defer stopNode(n, t)
+
+	// Transação de um remetente bloqueado
+	tx := blockchain.NewTransaction(sender.GetAddress(), createTestWallet(t).GetAddress(), 1, 1, 1, "")
+	if err := tx.Sign(sender); err != nil {
+		t.Fatalf("Failed to sign transaction: %v", err)
+	}
+	data, err := tx.Serialize()
+	if err != nil {
+		t.Fatalf("Failed to serialize transaction: %v", err)
+	}
+	n.HandlePeerMessage("attacker", "transaction", data)
+
+	if size := n.GetMempoolSize(); size != 0 {
+		t.Fatalf("Transaction from denied sender should be rejected, mempool size %d", size)
+	}
+
+	entries := readRejectLog(t, logPath)
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 reject log entry, got %d", len(entries))
+	}
+	entry := entries[0]
+	if entry.Kind != node.RejectKindTransaction || entry.ID != tx.ID || entry.Peer != "attacker" {
+		t.Errorf("Unexpected entry: kind=%s id=%s peer=%s", entry.Kind, entry.ID, entry.Peer)
+	}
+	if expected := fmt.Sprintf("sender %s is not allowed", sender.GetAddress()); entry.Reason != expected {
+		t.Errorf("Expected reason %q, got %q", expected, entry.Reason)
+	}
+	logged, err := blockchain.DeserializeTransaction(entry.Data)
+	if err != nil || logged.ID != tx.ID {
+		t.Errorf("Entry should carry the rejected transaction (%v)", err)
+	}
+
+	// Transação adulterada após a assinatura: motivo é a falha de validação
+	honest := createTestWallet(t)
+	forged := blockchain.NewTransaction(honest.GetAddress(), sender.GetAddress(), 1, 1, 1, "")
+	if err := forged.Sign(honest); err != nil {
+		t.Fatalf("Failed to sign transaction: %v", err)
+	}
+	forged.Amount = 1000
+	data, err = forged.Serialize()
+	if err != nil {
+		t.Fatalf("Failed to serialize transaction: %v", err)
+	}
+	n.HandlePeerMessage("attacker", "transaction", data)
+
+	entries = readRejectLog(t, logPath)
+	if len(entries) != 2 || !strings.HasPrefix(entries[1].Reason, "transaction validation failed") {
+		t.Errorf("Tampered transaction should be logged with its validation error, got %+v", entries)
+	}
+
+	t.Logf("✓ Rejected transactions logged with their reason")
+}
+
+// TestRejectLogRotation testa que o log de rejeições rotaciona ao atingir o tamanho máximo,
+// mantendo no máximo o número configurado de arquivos antigos
+func TestRejectLogRotation(t *testing.T) {
+	tempDir := getTempDataDir(t, "rejectrotation")
+	logPath := filepath.Join(tempDir, "rejects.jsonl")
+
+	rejectLog, err := node.OpenRejectLog(logPath, 512, 2)
+	if err != nil {
+		t.Fatalf("Failed to open reject log: %v", err)
+	}
+	defer rejectLog.Close()
+
+	for i := 0; i < 50; i++ {
+		entry := node.RejectLogEntry{
+			Kind:   node.RejectKindTransaction,
+			ID:     fmt.Sprintf("tx-%d", i),
+			Source: "gossip",
+			Reason: "transaction validation failed: invalid signature",
+		}
+		if err := rejectLog.Write(entry); err != nil {
+			t.Fatalf("Failed to write entry %d: %v", i, err)
+		}
+	}
+
+	for _, path := range []string{logPath, logPath + ".1", logPath + ".2"} {
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatalf("Expected log file %s: %v", path, err)
+		}
+		if info.Size() > 512 {
+			t.Errorf("Log file %s exceeds max size: %d bytes", path, info.Size())
+		}
+	}
+	if _, err := os.Stat(logPath + ".3"); !os.IsNotExist(err) {
+		t.Errorf("Only 2 rotated files should be kept")
+	}
+
+	// A entrada mais recente está no arquivo atual
+	entries := readRejectLog(t, logPath)
+	if len(entries) == 0 || entries[len(entries)-1].ID != "tx-49" {
+		t.Errorf("Current log should end with the latest entry, got %+v", entries)
+	}
+
+	t.Logf("✓ Reject log rotated at max size")
+}
@@ -0,0 +1,86 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/krakovia/blockchain/pkg/signaling"
+)
+
+// TestSignalingRejectsConnectionsOverCapacity testa que, com MaxClients atingido, uma nova
+// conexão é recusada com 503 e um motivo em JSON, e que desconectar um cliente libera a vaga
+func TestSignalingRejectsConnectionsOverCapacity(t *testing.T) {
+	signalingPort := getRandomPort()
+
+	server := signaling.NewServer()
+	server.MaxClients = 2
+	go func() {
+		if err := server.Start(fmt.Sprintf(":%d", signalingPort)); err != nil {
+			t.Logf("Signaling server error: %v", err)
+		}
+	}()
+	defer func() {
+		if err := server.Stop(); err != nil {
+			t.Logf("Warning: error stopping signaling server: %v", err)
+		}
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+	url := fmt.Sprintf("ws://localhost:%d/ws", signalingPort)
+
+	// Preenche todas as vagas
+	clients := make([]*websocket.Conn, 0, server.MaxClients)
+	for i := 0; i < server.MaxClients; i++ {
+		conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+		if err != nil {
+			t.Fatalf("Failed to connect client %d: %v", i, err)
+		}
+		defer conn.Close()
+		clients = append(clients, conn)
+	}
+
+	// A próxima conexão é recusada antes do upgrade
+	conn, resp, err := websocket.DefaultDialer.Dial(url, nil)
+	if err == nil {
+		conn.Close()
+		t.Fatal("Expected connection over capacity to be refused")
+	}
+	if resp == nil {
+		t.Fatalf("Expected an HTTP response for the refused connection, got error: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("Expected status %d, got %d", http.StatusServiceUnavailable, resp.StatusCode)
+	}
+	var body map[string]string
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("Failed to decode rejection body: %v", err)
+	}
+	if body["error"] == "" {
+		t.Errorf("Expected a rejection reason in the JSON body, got %v", body)
+	}
+	if got := server.ConnectionCount(); got != server.MaxClients {
+		t.Errorf("Expected %d connections after the rejection, got %d", server.MaxClients, got)
+	}
+
+	// Desconectar um cliente libera a vaga
+	if err := clients[0].Close(); err != nil {
+		t.Fatalf("Failed to close client: %v", err)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+		if err == nil {
+			conn.Close()
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected a slot to be freed after a client disconnected: %v", err)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
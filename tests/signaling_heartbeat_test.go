@@ -0,0 +1,113 @@
+package tests
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/krakovia/blockchain/pkg/signaling"
+)
+
+// TestSignalingEvictsUnresponsiveClient testa que um cliente que para de responder aos pings
+// é removido, os peers recebem "peer-left" e ele some das listas de peers
+func TestSignalingEvictsUnresponsiveClient(t *testing.T) {
+	signalingPort := getRandomPort()
+
+	server := signaling.NewServer()
+	server.PingInterval = 50 * time.Millisecond
+	server.MaxMissedPongs = 2
+	go func() {
+		if err := server.Start(fmt.Sprintf(":%d", signalingPort)); err != nil {
+			t.Logf("Signaling server error: %v", err)
+		}
+	}()
+	defer func() {
+		if err := server.Stop(); err != nil {
+			t.Logf("Warning: error stopping signaling server: %v", err)
+		}
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+	url := fmt.Sprintf("ws://localhost:%d/ws", signalingPort)
+
+	// Cliente vivo: lê continuamente, o que responde os pings automaticamente
+	alive, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("Failed to connect alive client: %v", err)
+	}
+	defer alive.Close()
+	messages := make(chan signaling.Message, 64)
+	go func() {
+		for {
+			var msg signaling.Message
+			if err := alive.ReadJSON(&msg); err != nil {
+				close(messages)
+				return
+			}
+			messages <- msg
+		}
+	}()
+	if err := alive.WriteJSON(signaling.Message{Type: "register", From: "alive"}); err != nil {
+		t.Fatalf("Failed to register alive client: %v", err)
+	}
+
+	// Cliente travado: registra e nunca mais lê, então nunca responde um ping
+	stale, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("Failed to connect stale client: %v", err)
+	}
+	defer stale.Close()
+	if err := stale.WriteJSON(signaling.Message{Type: "register", From: "stale"}); err != nil {
+		t.Fatalf("Failed to register stale client: %v", err)
+	}
+
+	// O vivo primeiro fica sabendo do travado, depois recebe o peer-left dele
+	joined, left := false, false
+	timeout := time.After(3 * time.Second)
+	for !left {
+		select {
+		case msg, ok := <-messages:
+			if !ok {
+				t.Fatal("Alive client was disconnected")
+			}
+			for _, peer := range msg.PeerList {
+				if peer != "stale" {
+					continue
+				}
+				switch msg.Type {
+				case "peer-list":
+					joined = true
+				case "peer-left":
+					left = true
+				}
+			}
+		case <-timeout:
+			t.Fatalf("Stale client was not evicted (joined: %v, clients: %d)", joined, server.ClientCount())
+		}
+	}
+	if !joined {
+		t.Error("Alive client should have been told about the stale client before it left")
+	}
+
+	// O travado não aparece mais na lista de peers, e o vivo continua registrado
+	time.Sleep(3 * server.PingInterval)
+	if err := alive.WriteJSON(signaling.Message{Type: "get-peers", From: "alive"}); err != nil {
+		t.Fatalf("Failed to request peers: %v", err)
+	}
+	select {
+	case msg, ok := <-messages:
+		if !ok {
+			t.Fatal("Alive client was evicted despite answering pings")
+		}
+		if msg.Type != "peer-list" || len(msg.PeerList) != 0 {
+			t.Errorf("Expected an empty peer list, got %+v", msg)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Peer list was not received")
+	}
+
+	if count := server.ClientCount(); count != 1 {
+		t.Errorf("Expected 1 live client, got %d", count)
+	}
+}
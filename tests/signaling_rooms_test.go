@@ -0,0 +1,120 @@
+package tests
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/krakovia/blockchain/pkg/signaling"
+)
+
+// TestSignalingRoomsIsolatePeers testa que clientes em salas diferentes do mesmo servidor de
+// signaling nunca recebem os peers nem as mensagens uns dos outros
+func TestSignalingRoomsIsolatePeers(t *testing.T) {
+	signalingPort := getRandomPort()
+
+	server := signaling.NewServer()
+	go func() {
+		if err := server.Start(fmt.Sprintf(":%d", signalingPort)); err != nil {
+			t.Logf("Signaling server error: %v", err)
+		}
+	}()
+	defer func() {
+		if err := server.Stop(); err != nil {
+			t.Logf("Warning: error stopping signaling server: %v", err)
+		}
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+
+	// Dois clientes por sala; "shared" existe nas duas salas com o mesmo ID
+	clients := []struct {
+		id, room string
+	}{
+		{"testnet-a", "testnet"},
+		{"shared", "testnet"},
+		{"mainnet-a", "mainnet"},
+		{"shared", "mainnet"},
+	}
+	expectedPeers := map[string][]string{
+		"testnet/testnet-a": {"shared"},
+		"testnet/shared":    {"testnet-a"},
+		"mainnet/mainnet-a": {"shared"},
+		"mainnet/shared":    {"mainnet-a"},
+	}
+
+	conns := make([]*websocket.Conn, len(clients))
+	for i, c := range clients {
+		url := fmt.Sprintf("ws://localhost:%d/ws?room=%s", signalingPort, c.room)
+		conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+		if err != nil {
+			t.Fatalf("Failed to connect %s/%s: %v", c.room, c.id, err)
+		}
+		defer conn.Close()
+		conns[i] = conn
+
+		if err := conn.WriteJSON(signaling.Message{Type: "register", From: c.id}); err != nil {
+			t.Fatalf("Failed to register %s/%s: %v", c.room, c.id, err)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	// Pedir a lista de peers (RequestPeerList) e tentar mandar uma oferta para a outra sala
+	for i, c := range clients {
+		if err := conns[i].WriteJSON(signaling.Message{Type: "get-peers", From: c.id}); err != nil {
+			t.Fatalf("Failed to request peers for %s/%s: %v", c.room, c.id, err)
+		}
+	}
+	if err := conns[0].WriteJSON(signaling.Message{Type: "offer", From: "testnet-a", To: "mainnet-a"}); err != nil {
+		t.Fatalf("Failed to send offer: %v", err)
+	}
+	if err := conns[2].WriteJSON(signaling.Message{Type: "offer", From: "mainnet-a", To: "shared"}); err != nil {
+		t.Fatalf("Failed to send offer: %v", err)
+	}
+
+	for i, c := range clients {
+		key := c.room + "/" + c.id
+		seen := make(map[string]bool)
+		var offers []signaling.Message
+
+		conns[i].SetReadDeadline(time.Now().Add(500 * time.Millisecond))
+		for {
+			var msg signaling.Message
+			if err := conns[i].ReadJSON(&msg); err != nil {
+				break
+			}
+			switch msg.Type {
+			case "peer-list":
+				for _, peer := range msg.PeerList {
+					seen[peer] = true
+				}
+			case "offer":
+				offers = append(offers, msg)
+			}
+		}
+
+		if len(seen) != len(expectedPeers[key]) {
+			t.Errorf("%s: expected peers %v, got %v", key, expectedPeers[key], seen)
+		}
+		for _, peer := range expectedPeers[key] {
+			if !seen[peer] {
+				t.Errorf("%s: expected peers %v, got %v", key, expectedPeers[key], seen)
+			}
+		}
+
+		// Só o "shared" da mainnet recebe a oferta da mainnet-a; a da testnet não atravessa
+		wantOffers := 0
+		if key == "mainnet/shared" {
+			wantOffers = 1
+		}
+		if len(offers) != wantOffers {
+			t.Errorf("%s: expected %d offers, got %d (%v)", key, wantOffers, len(offers), offers)
+		}
+		for _, offer := range offers {
+			if offer.From != "mainnet-a" {
+				t.Errorf("%s: received offer from another room: %+v", key, offer)
+			}
+		}
+	}
+}
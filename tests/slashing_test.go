@@ -0,0 +1,215 @@
+package tests
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/krakovia/blockchain/pkg/blockchain"
+	"github.com/krakovia/blockchain/pkg/node"
+	"github.com/krakovia/blockchain/pkg/signaling"
+)
+
+// TestSlashEvidenceReachesAllPeers testa que a assinatura dupla vista pelo nó A é repassada como
+// slash_evidence para B e C, que B (o único com saldo para a fee) a denuncia em uma transação e
+// que os três punem o validador uma única vez quando ela entra em um bloco
+func TestSlashEvidenceReachesAllPeers(t *testing.T) {
+	signalingPort := getRandomPort()
+	signalingURL := fmt.Sprintf("ws://localhost:%d/ws", signalingPort)
+	tempDir := getTempDataDir(t, "slashing")
+
+	server := signaling.NewServer()
+	go func() {
+		if err := server.Start(fmt.Sprintf(":%d", signalingPort)); err != nil {
+			t.Logf("Signaling server error: %v", err)
+		}
+	}()
+	defer func() {
+		if err := server.Stop(); err != nil {
+			t.Logf("Warning: error stopping signaling server: %v", err)
+		}
+	}()
+	time.Sleep(100 * time.Millisecond)
+
+	configs := make([]node.Config, 3)
+	for i := range configs {
+		configs[i] = createTestNodeConfigWithSharedGenesis(t, fmt.Sprintf("slash-node%d", i+1), signalingURL, tempDir, nil)
+	}
+
+	// Só o nó B tem saldo para pagar a fee da denúncia
+	validator := createTestWallet(t)
+	genesis, err := blockchain.GenesisBlockWithAllocations([]blockchain.GenesisAllocation{
+		{Address: validator.GetAddress(), Amount: 1000000000},
+		{Address: configs[1].Wallet.GetAddress(), Amount: 1000000},
+	}, time.Now().Unix())
+	if err != nil {
+		t.Fatalf("Failed to create genesis: %v", err)
+	}
+
+	// O validador tem o mesmo stake inicial nos três nós
+	nodes := make([]*node.Node, 3)
+	for i, config := range configs {
+		config.GenesisBlock = genesis
+		config.InitialStakeAddr = validator.GetAddress()
+		config.InitialStake = 1000
+
+		n, err := node.NewNode(config)
+		if err != nil {
+			t.Fatalf("Failed to create node%d: %v", i+1, err)
+		}
+		defer stopNode(n, t)
+		nodes[i] = n
+	}
+
+	// Os nós entram um de cada vez (conexões simultâneas entre os três competem no handshake).
+	// Com um peer cada, os três estão conectados, mesmo que C só alcance A repassando por B.
+	for i, n := range nodes {
+		if err := n.Start(); err != nil {
+			t.Fatalf("Failed to start node%d: %v", i+1, err)
+		}
+		if i == 0 {
+			continue
+		}
+		deadline := time.Now().Add(10 * time.Second)
+		for len(n.GetPeers()) == 0 && time.Now().Before(deadline) {
+			time.Sleep(100 * time.Millisecond)
+		}
+	}
+
+	// A denúncia só é enviada a peers já autenticados
+	authenticated := func(n *node.Node) bool {
+		peers := n.GetPeers()
+		for _, peer := range peers {
+			if !peer.IsAuthenticated() {
+				return false
+			}
+		}
+		return len(peers) > 0
+	}
+	deadline := time.Now().Add(10 * time.Second)
+	for i, n := range nodes {
+		for !authenticated(n) && time.Now().Before(deadline) {
+			time.Sleep(100 * time.Millisecond)
+		}
+		if !authenticated(n) {
+			t.Fatalf("Node%d has no authenticated peers", i+1)
+		}
+	}
+
+	// Dois blocos diferentes assinados pelo validador na altura 1
+	first := createSignedBlocks(t, genesis, validator, 1)[0]
+	conflicting := blockchain.NewBlock(1, genesis.Hash, first.Transactions, validator.GetAddress())
+	conflicting.Header.Timestamp = first.Header.Timestamp
+	conflicting.Header.Nonce = 1
+	if err := conflicting.Sign(validator); err != nil {
+		t.Fatalf("Failed to sign conflicting block: %v", err)
+	}
+
+	// Todos recebem o primeiro bloco; só o nó A vê o segundo, sem saldo para denunciar o validador
+	deliverBlock := func(n *node.Node, block *blockchain.Block) {
+		data, err := block.Serialize()
+		if err != nil {
+			t.Fatalf("Failed to serialize block: %v", err)
+		}
+		n.HandlePeerMessage("observer", "block", data)
+	}
+	for _, n := range nodes {
+		deliverBlock(n, first)
+	}
+	deliverBlock(nodes[0], conflicting)
+
+	received := func() bool {
+		for _, n := range nodes {
+			if len(n.GetSlashEvidence()) != 1 || n.GetMempoolSize() != 1 {
+				return false
+			}
+		}
+		return true
+	}
+	deadline = time.Now().Add(10 * time.Second)
+	for !received() && time.Now().Before(deadline) {
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	// A evidência e a transação de B chegam a todos; a detecção sozinha não pune ninguém
+	for i, n := range nodes {
+		if count := len(n.GetSlashEvidence()); count != 1 {
+			t.Fatalf("Node%d: expected the relayed evidence, got %d", i+1, count)
+		}
+		if size := n.GetMempoolSize(); size != 1 {
+			t.Fatalf("Node%d: expected one evidence transaction in the mempool, got %d transactions", i+1, size)
+		}
+		if stake := n.GetChain().GetStake(validator.GetAddress()); stake != 1000 {
+			t.Errorf("Node%d: detection should not slash, got stake %d", i+1, stake)
+		}
+	}
+
+	// Evidência repetida é ignorada e evidência forjada (bloco assinado por outra carteira) é recusada
+	evidence := nodes[2].GetSlashEvidence()[0]
+	data, err := evidence.Serialize()
+	if err != nil {
+		t.Fatalf("Failed to serialize evidence: %v", err)
+	}
+	nodes[2].HandlePeerMessage("observer", "slash_evidence", data)
+
+	attacker := createTestWallet(t)
+	forged := blockchain.NewBlock(1, genesis.Hash, first.Transactions, attacker.GetAddress())
+	forged.Header.Timestamp = first.Header.Timestamp
+	forged.Header.Nonce = 2
+	if err := forged.Sign(attacker); err != nil {
+		t.Fatalf("Failed to sign forged block: %v", err)
+	}
+	forged.Header.ValidatorAddr = validator.GetAddress()
+	forged.Hash, _ = forged.CalculateHash()
+	forgedData, err := blockchain.NewSlashingEvidence(first, forged).Serialize()
+	if err != nil {
+		t.Fatalf("Failed to serialize forged evidence: %v", err)
+	}
+	nodes[2].HandlePeerMessage("observer", "slash_evidence", forgedData)
+
+	if count := len(nodes[2].GetSlashEvidence()); count != 1 {
+		t.Errorf("Duplicate or forged evidence should not be stored, got %d", count)
+	}
+	if size := nodes[2].GetMempoolSize(); size != 1 {
+		t.Errorf("Duplicate or forged evidence should not be reported, got %d transactions", size)
+	}
+
+	// O validador monta o bloco 2 com a denúncia a partir do template do nó B
+	template, err := nodes[1].GetBlockTemplate()
+	if err != nil {
+		t.Fatalf("Failed to get block template: %v", err)
+	}
+	if len(template.Transactions) != 1 {
+		t.Fatalf("Expected the evidence transaction in the template, got %d", len(template.Transactions))
+	}
+	template.ValidatorAddr = validator.GetAddress()
+	template.Coinbase = blockchain.NewCoinbaseTransaction(validator.GetAddress(), template.Coinbase.Amount, template.Height)
+	block := template.NewBlock()
+	if err := block.Sign(validator); err != nil {
+		t.Fatalf("Failed to sign block: %v", err)
+	}
+	for _, n := range nodes {
+		deliverBlock(n, block)
+	}
+
+	for i, n := range nodes {
+		if height := n.GetChainHeight(); height != 2 {
+			t.Errorf("Node%d: expected height 2, got %d", i+1, height)
+		}
+		if stake := n.GetChain().GetStake(validator.GetAddress()); stake != 900 {
+			t.Errorf("Node%d: expected stake 900 after one 10%% slash, got %d", i+1, stake)
+		}
+		if height := n.GetChain().GetSlashHeight(validator.GetAddress()); height != 1 {
+			t.Errorf("Node%d: expected slash height 1, got %d", i+1, height)
+		}
+		if count := len(n.GetSlashEvidence()); count != 0 {
+			t.Errorf("Node%d: evidence already punished should be dropped, got %d", i+1, count)
+		}
+	}
+
+	// Reenviar a evidência depois da punição não pune de novo
+	nodes[0].HandlePeerMessage("observer", "slash_evidence", data)
+	if size := nodes[0].GetMempoolSize(); size != 0 {
+		t.Errorf("Evidence already punished should not be reported again, got %d transactions", size)
+	}
+}
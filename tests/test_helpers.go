@@ -54,13 +54,32 @@ func createTestGenesis(recipientAddr string, amount uint64) *blockchain.Block {
 	return blockchain.GenesisBlock(genesisTx)
 }
 
+// createSignedBlocks cria count blocos consecutivos sobre previous, assinados por validator,
+// com um segundo entre eles
+func createSignedBlocks(t *testing.T, previous *blockchain.Block, validator *wallet.Wallet, count int) []*blockchain.Block {
+	t.Helper()
+
+	blocks := make([]*blockchain.Block, 0, count)
+	for i := 0; i < count; i++ {
+		height := previous.Header.Height + 1
+		coinbase := blockchain.NewCoinbaseTransaction(validator.GetAddress(), 50, height)
+		block := blockchain.NewBlock(height, previous.Hash, blockchain.TransactionSlice{coinbase}, validator.GetAddress())
+		block.Header.Timestamp = previous.Header.Timestamp + 1
+		if err := block.Sign(validator); err != nil {
+			t.Fatalf("Failed to sign block %d: %v", height, err)
+		}
+		blocks = append(blocks, block)
+		previous = block
+	}
+	return blocks
+}
+
 // createTestNodeConfig cria uma configuração de node com wallet e genesis
 func createTestNodeConfig(t *testing.T, nodeID, signalingURL, tempDir string) node.Config {
 	w := createTestWallet(t)
 	genesis := createTestGenesis(w.GetAddress(), 1000000000)
 
 	return node.Config{
-		ID:                nodeID,
 		Address:           fmt.Sprintf(":%d", getRandomPort()),
 		DBPath:            filepath.Join(tempDir, nodeID),
 		SignalingServer:   signalingURL,
@@ -78,7 +97,6 @@ func createTestNodeConfigWithSharedGenesis(t *testing.T, nodeID, signalingURL, t
 	w := createTestWallet(t)
 
 	return node.Config{
-		ID:                nodeID,
 		Address:           fmt.Sprintf(":%d", getRandomPort()),
 		DBPath:            filepath.Join(tempDir, nodeID),
 		SignalingServer:   signalingURL,